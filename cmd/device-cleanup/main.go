@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"radar/config"
 	"radar/internal/domain/policy"
@@ -20,9 +21,11 @@ type cleanupParams struct {
 	Lifecycle fx.Lifecycle
 	Shutdown  fx.Shutdowner
 
-	DeviceRepo repository.DeviceRepository
-	Config     *config.Config
-	Logger     *slog.Logger
+	DeviceRepo       repository.DeviceRepository
+	NotificationRepo repository.NotificationRepository
+	MessageRepo      repository.PubSubMessageRepository
+	Config           *config.Config
+	Logger           *slog.Logger
 }
 
 func main() {
@@ -43,7 +46,11 @@ func injectInfra() fx.Option {
 }
 
 func injectRepo() fx.Option {
-	return fx.Provide(postgres.NewDeviceRepository)
+	return fx.Provide(
+		postgres.NewDeviceRepository,
+		postgres.NewNotificationRepository,
+		postgres.NewPubSubMessageRepository,
+	)
 }
 
 func runDeviceCleanup(params cleanupParams) {
@@ -63,6 +70,28 @@ func runDeviceCleanup(params cleanupParams) {
 				slog.Int64("rows_affected", rowsAffected),
 			)
 
+			logPolicy := policy.NotificationLogPolicy{RetentionDays: params.Config.DeviceCleanup.NotificationLogRetentionDays}
+			purged, err := params.NotificationRepo.PurgeOldNotificationLogs(cleanupCtx, logPolicy.PurgeCutoff(time.Now()))
+			if err != nil {
+				return fmt.Errorf("purge old notification logs: %w", err)
+			}
+
+			params.Logger.Info(
+				"Notification log purge completed",
+				slog.Int("retention_days", logPolicy.RetentionDays),
+				slog.Int64("rows_affected", purged),
+			)
+
+			expired, err := params.MessageRepo.PurgeExpiredMessages(cleanupCtx, time.Now())
+			if err != nil {
+				return fmt.Errorf("purge expired pubsub messages: %w", err)
+			}
+
+			params.Logger.Info(
+				"Pub/Sub message purge completed",
+				slog.Int64("rows_affected", expired),
+			)
+
 			return params.Shutdown.Shutdown()
 		},
 	})
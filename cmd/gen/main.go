@@ -23,6 +23,10 @@ func main() {
 		model.UserDeviceModel{},
 		model.MerchantLocationNotificationModel{},
 		model.NotificationLogModel{},
+		model.MerchantSettingsModel{},
+		model.NotificationPreferenceModel{},
+		model.APITokenModel{},
+		model.PubSubMessageClaimModel{},
 	}
 
 	gen := gen.NewGenerator(gen.Config{
@@ -7,12 +7,16 @@ import (
 
 	"radar/config"
 	"radar/internal/delivery"
+	"radar/internal/delivery/health"
 	"radar/internal/delivery/worker"
 	"radar/internal/delivery/worker/handler"
+	"radar/internal/domain/policy"
 	logs "radar/internal/infra/log"
+	"radar/internal/infra/metrics"
 	"radar/internal/infra/notification"
 	"radar/internal/infra/persistence/postgres"
-	"radar/internal/infra/routing/pmtiles"
+	"radar/internal/infra/routing"
+	"radar/internal/infra/tracing"
 
 	"go.uber.org/fx"
 )
@@ -25,7 +29,36 @@ type startServerParams struct {
 	Deliveries []delivery.Delivery `group:"deliveries"`
 }
 
+// Run without arguments to serve Pub/Sub pushes, with dispatch-scheduled to send the scheduled
+// notifications that are due and exit, or with cleanup-stale-devices to delete the devices the worker
+// has not delivered to within deviceCleanup.staleDeliveryDays and exit, for example from a cron job.
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == dispatchScheduledCommand {
+		fx.New(
+			injectInfra(),
+			injectRepo(),
+			injectService(),
+			injectHandler(),
+			fx.Invoke(runScheduledDispatch),
+			fx.StartTimeout(scheduledDispatchTimeout),
+		).Run()
+
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == cleanupStaleDevicesCommand {
+		fx.New(
+			injectInfra(),
+			injectRepo(),
+			injectService(),
+			injectHandler(),
+			fx.Invoke(runStaleDeviceCleanup),
+			fx.StartTimeout(staleDeviceCleanupTimeout),
+		).Run()
+
+		return
+	}
+
 	fx.New(
 		injectInfra(),
 		injectRepo(),
@@ -34,6 +67,7 @@ func main() {
 		injectDelivery(),
 		fx.Invoke(
 			startServer,
+			watchSourceReload,
 		),
 	).Run()
 }
@@ -49,6 +83,16 @@ func injectInfra() fx.Option {
 
 			return cfg.PMTiles
 		},
+		// Expose routing config for backend selection.
+		func(cfg *config.Config) *config.RoutingConfig {
+			if cfg == nil || cfg.Routing == nil {
+				return &config.RoutingConfig{}
+			}
+
+			return cfg.Routing
+		},
+		// Notification policies shared by the publish path and the push worker
+		policy.NewNotificationPolicies,
 		logs.New,
 		context.Background,
 		postgres.New,
@@ -59,8 +103,11 @@ func injectRepo() fx.Option {
 	return fx.Options(
 		fx.Provide(
 			postgres.NewSubscriptionRepository,
+			postgres.NewNotificationPreferenceRepository,
 			postgres.NewDeviceRepository,
+			postgres.NewHealthRepository,
 			postgres.NewNotificationRepository,
+			postgres.NewPubSubMessageRepository,
 		),
 	)
 }
@@ -69,7 +116,10 @@ func injectService() fx.Option {
 	return fx.Options(
 		fx.Provide(
 			notification.NewFirebaseService,
-			pmtiles.NewPMTilesRoutingService,
+			routing.NewRoutingService,
+			metrics.NewRegistry,
+			metrics.NewNotificationMetrics,
+			tracing.NewTracerProvider,
 		),
 	)
 }
@@ -78,6 +128,7 @@ func injectHandler() fx.Option {
 	return fx.Options(
 		fx.Provide(
 			handler.NewPushHandler,
+			health.NewHandler,
 		),
 	)
 }
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"radar/config"
+	"radar/internal/infra/routing/pmtiles"
+	"radar/internal/usecase"
+
+	"go.uber.org/fx"
+)
+
+// sourceReloadTimeout bounds one PMTiles reload, which reads the new archive's header before swapping
+const sourceReloadTimeout = 30 * time.Second
+
+type sourceReloadParams struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+
+	RoutingSvc usecase.RoutingUsecase
+	PMTiles    *config.PMTilesConfig
+	Logger     *slog.Logger
+}
+
+// watchSourceReload reloads the PMTiles source on SIGHUP, so an updated archive, at the same path or at a
+// new pmtiles.source in the config, is picked up without restarting the worker and dropping in-flight pushes
+func watchSourceReload(params sourceReloadParams) {
+	reloader, ok := params.RoutingSvc.(pmtiles.SourceReloader)
+	if !ok {
+		params.Logger.Info("Routing backend has no PMTiles source to reload, SIGHUP reload disabled")
+
+		return
+	}
+
+	hangups := make(chan os.Signal, 1)
+	done := make(chan struct{})
+
+	params.Lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			signal.Notify(hangups, syscall.SIGHUP)
+			go func() {
+				for {
+					select {
+					case <-hangups:
+						reloadSource(reloader, params.PMTiles, params.Logger)
+					case <-done:
+						return
+					}
+				}
+			}()
+
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			signal.Stop(hangups)
+			close(done)
+
+			return nil
+		},
+	})
+}
+
+// reloadSource rereads the config and reloads the archive at its pmtiles.source. Profile sources are
+// reopened from the startup config, since adding or changing a profile needs a restart.
+func reloadSource(reloader pmtiles.SourceReloader, startup *config.PMTilesConfig, logger *slog.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), sourceReloadTimeout)
+	defer cancel()
+
+	cfg, err := config.New()
+	if err != nil {
+		logger.Error("Failed to reread config for PMTiles reload, keeping the current archive", slog.String("error", err.Error()))
+
+		return
+	}
+
+	current := cfg.PMTiles.WithDefaults()
+	if !slices.Equal(current.Profiles, startup.WithDefaults().Profiles) {
+		logger.Warn("pmtiles.profiles changed since startup; restart the worker to apply it, reloading the startup profile sources")
+	}
+
+	if err := reloader.Reload(ctx, current.Source); err != nil {
+		logger.Error("PMTiles source reload failed, keeping the current archive", slog.String("error", err.Error()))
+	}
+}
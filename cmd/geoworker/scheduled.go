@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"radar/internal/delivery/worker/handler"
+
+	"go.uber.org/fx"
+)
+
+const (
+	// dispatchScheduledCommand runs one scheduled notification dispatch instead of the push server
+	dispatchScheduledCommand = "dispatch-scheduled"
+
+	// scheduledDispatchTimeout bounds one dispatch run, which executes as an Fx start hook
+	scheduledDispatchTimeout = 5 * time.Minute
+)
+
+type scheduledDispatchParams struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Shutdown  fx.Shutdowner
+
+	PushHandler *handler.PushHandler
+	Logger      *slog.Logger
+}
+
+func runScheduledDispatch(params scheduledDispatchParams) {
+	params.Lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			result, err := params.PushHandler.DispatchScheduledNotifications(ctx)
+			if err != nil {
+				return fmt.Errorf("dispatch scheduled notifications: %w", err)
+			}
+
+			params.Logger.Info(
+				"Scheduled notification dispatch completed",
+				slog.Int("due", result.Due),
+				slog.Int("dispatched", result.Dispatched),
+				slog.Int("skipped", result.Skipped),
+				slog.Int("suppressed", result.Suppressed),
+				slog.Int("failed", result.Failed),
+			)
+
+			return params.Shutdown.Shutdown()
+		},
+	})
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"radar/internal/delivery/worker/handler"
+
+	"go.uber.org/fx"
+)
+
+const (
+	// cleanupStaleDevicesCommand runs one stale device cleanup instead of the push server
+	cleanupStaleDevicesCommand = "cleanup-stale-devices"
+
+	// staleDeviceCleanupTimeout bounds one cleanup run, which executes as an Fx start hook
+	staleDeviceCleanupTimeout = 5 * time.Minute
+)
+
+type staleDeviceCleanupParams struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Shutdown  fx.Shutdowner
+
+	PushHandler *handler.PushHandler
+	Logger      *slog.Logger
+}
+
+func runStaleDeviceCleanup(params staleDeviceCleanupParams) {
+	params.Lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			result, err := params.PushHandler.CleanupStaleDevices(ctx)
+			if err != nil {
+				return fmt.Errorf("cleanup stale devices: %w", err)
+			}
+
+			params.Logger.Info(
+				"Stale device cleanup completed",
+				slog.Int("stale", result.Stale),
+				slog.Int64("deleted", result.Deleted),
+				slog.Int("failed_batches", result.FailedBatches),
+			)
+
+			return params.Shutdown.Shutdown()
+		},
+	})
+}
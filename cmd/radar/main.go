@@ -10,14 +10,18 @@ import (
 	"radar/internal/delivery/api"
 	apimiddleware "radar/internal/delivery/api/middleware"
 	"radar/internal/delivery/api/router/handler"
+	"radar/internal/delivery/health"
+	"radar/internal/domain/policy"
 	"radar/internal/infra/auth"
 	"radar/internal/infra/auth/google"
+	"radar/internal/infra/events"
 	logs "radar/internal/infra/log"
+	"radar/internal/infra/metrics"
 	"radar/internal/infra/notification"
 	"radar/internal/infra/persistence/postgres"
 	"radar/internal/infra/pubsub"
 	"radar/internal/infra/qrcode"
-	"radar/internal/infra/routing/pmtiles"
+	"radar/internal/infra/routing"
 	"radar/internal/usecase/impl"
 
 	"go.uber.org/fx"
@@ -57,6 +61,16 @@ func injectInfra() fx.Option {
 
 			return cfg.PMTiles
 		},
+		// Expose routing config for backend selection.
+		func(cfg *config.Config) *config.RoutingConfig {
+			if cfg == nil || cfg.Routing == nil {
+				return &config.RoutingConfig{}
+			}
+
+			return cfg.Routing
+		},
+		// Notification policies shared by the publish path and the push worker
+		policy.NewNotificationPolicies,
 		logs.New,
 		context.Background,
 		postgres.New,
@@ -75,8 +89,12 @@ func injectRepo() fx.Option {
 			postgres.NewDiscoveryRepository,
 			postgres.NewTransactionManager,
 			postgres.NewDeviceRepository,
+			postgres.NewHealthRepository,
 			postgres.NewSubscriptionRepository,
+			postgres.NewNotificationPreferenceRepository,
 			postgres.NewNotificationRepository,
+			postgres.NewMerchantSettingsRepository,
+			postgres.NewAPITokenRepository,
 		),
 	)
 }
@@ -90,7 +108,10 @@ func injectService() fx.Option {
 			notification.NewFirebaseService,
 			qrcode.NewQRCodeService,
 			pubsub.NewEventPublisher,
-			pmtiles.NewPMTilesRoutingService,
+			routing.NewRoutingService,
+			metrics.NewRegistry,
+			metrics.NewNotificationMetrics,
+			events.NewUserEventBus,
 		),
 	)
 }
@@ -106,7 +127,11 @@ func injectUsecase() fx.Option {
 			impl.NewDiscoveryService,
 			impl.NewDeviceService,
 			impl.NewSubscriptionService,
+			impl.NewRouteCacheService,
+			impl.NewSubscriberMatrixService,
 			impl.NewNotificationService,
+			impl.NewMerchantSettingsService,
+			impl.NewAPITokenService,
 		),
 	)
 }
@@ -131,6 +156,9 @@ func injectHandler() fx.Option {
 			handler.NewDeviceHandler,
 			handler.NewSubscriptionHandler,
 			handler.NewNotificationHandler,
+			handler.NewAdminHandler,
+			handler.NewAPITokenHandler,
+			health.NewHandler,
 		),
 	)
 }
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"time"
+
+	"radar/internal/infra/routing/ch"
+	"radar/internal/infra/routing/loader"
+	"radar/internal/usecase"
+)
+
+// benchOptions holds the inputs of the bench subcommand
+type benchOptions struct {
+	DataDir          string
+	Queries          int
+	TargetsPerQuery  int
+	Seed             uint64
+	ForceDijkstra    bool
+	TurnRestrictions bool
+}
+
+// vertexSampler draws benchmark coordinates from the graph's vertices, so every query point is on the road network
+type vertexSampler []loader.Vertex
+
+// newVertexSampler returns a sampler over vertices
+func newVertexSampler(vertices []loader.Vertex) (vertexSampler, error) {
+	if len(vertices) == 0 {
+		return nil, errors.New("routing data has no vertices")
+	}
+
+	return vertexSampler(vertices), nil
+}
+
+// randomCoordinate returns the coordinate of a vertex drawn uniformly
+func (s vertexSampler) randomCoordinate(rng *rand.Rand) ch.Coordinate {
+	vertex := s[rng.IntN(len(s))]
+
+	return ch.Coordinate{Lat: vertex.Lat, Lng: vertex.Lng}
+}
+
+// benchReport collects the outcome of a bench run
+type benchReport struct {
+	Latencies   []time.Duration // One per query, in the order they ran
+	Routes      int             // Source/target pairs routed
+	Unreachable int             // Pairs with no road route
+	Reasons     map[usecase.UnreachableReason]int
+}
+
+// Percentile returns the nearest-rank latency at p, between 0 and 100
+func (r benchReport) Percentile(p float64) time.Duration {
+	if len(r.Latencies) == 0 {
+		return 0
+	}
+
+	sorted := slices.Clone(r.Latencies)
+	slices.Sort(sorted)
+
+	rank := int(float64(len(sorted))*p/100+0.5) - 1
+
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
+
+// UnreachableRate returns the share of routed pairs with no road route
+func (r benchReport) UnreachableRate() float64 {
+	if r.Routes == 0 {
+		return 0
+	}
+
+	return float64(r.Unreachable) / float64(r.Routes)
+}
+
+// runBench loads the routing engine, routes random pairs of graph vertices, and prints the
+// latency percentiles and the unreachable rate. The same seed draws the same pairs, so runs against
+// the same data are comparable.
+func runBench(ctx context.Context, opts benchOptions) error {
+	if opts.Queries <= 0 || opts.TargetsPerQuery <= 0 {
+		return errors.New("--queries and --targets-per-query must be positive")
+	}
+
+	fmt.Printf("Loading routing data from: %s\n", opts.DataDir)
+	config := ch.DefaultEngineConfig()
+	config.ForceDijkstra = opts.ForceDijkstra
+	config.TurnRestrictions = opts.TurnRestrictions
+	engine := ch.NewEngine(config, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+	if err := engine.LoadData(opts.DataDir); err != nil {
+		return fmt.Errorf("failed to load routing data: %w", err)
+	}
+	sampler, err := newVertexSampler(engine.Vertices())
+	if err != nil {
+		return err
+	}
+
+	report, err := benchQueries(ctx, engine, sampler, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Ran %d queries with %d targets each (seed %d)\n", len(report.Latencies), opts.TargetsPerQuery, opts.Seed)
+	fmt.Printf("  p50: %s\n", report.Percentile(50))
+	fmt.Printf("  p95: %s\n", report.Percentile(95))
+	fmt.Printf("  p99: %s\n", report.Percentile(99))
+	fmt.Printf("  unreachable: %d/%d (%.1f%%)\n", report.Unreachable, report.Routes, report.UnreachableRate()*100)
+
+	reasons := make([]usecase.UnreachableReason, 0, len(report.Reasons))
+	for reason := range report.Reasons {
+		reasons = append(reasons, reason)
+	}
+	slices.SortFunc(reasons, func(a, b usecase.UnreachableReason) int { return cmp.Compare(a, b) })
+	for _, reason := range reasons {
+		fmt.Printf("    %s: %d\n", reason, report.Reasons[reason])
+	}
+
+	return nil
+}
+
+// benchQueries times opts.Queries queries from random source vertices to random target vertices.
+// A single target is routed with ShortestPath and several with OneToMany.
+func benchQueries(ctx context.Context, engine *ch.Engine, sampler vertexSampler, opts benchOptions) (benchReport, error) {
+	rng := rand.New(rand.NewPCG(opts.Seed, opts.Seed))
+	report := benchReport{
+		Latencies: make([]time.Duration, 0, opts.Queries),
+		Reasons:   make(map[usecase.UnreachableReason]int),
+	}
+
+	targets := make([]ch.Coordinate, opts.TargetsPerQuery)
+	for range opts.Queries {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		source := sampler.randomCoordinate(rng)
+		for idx := range targets {
+			targets[idx] = sampler.randomCoordinate(rng)
+		}
+
+		var (
+			results []ch.RouteResult
+			err     error
+		)
+		startedAt := time.Now()
+		if len(targets) == 1 {
+			var result *ch.RouteResult
+			result, err = engine.ShortestPath(ctx, ch.ProfileDefault, source, targets[0])
+			if result != nil {
+				results = []ch.RouteResult{*result}
+			}
+		} else {
+			results, err = engine.OneToMany(ctx, ch.ProfileDefault, source, targets)
+		}
+		report.Latencies = append(report.Latencies, time.Since(startedAt))
+
+		if err != nil {
+			return report, fmt.Errorf("query %d failed: %w", len(report.Latencies), err)
+		}
+
+		for _, result := range results {
+			report.Routes++
+			if !result.IsReachable {
+				report.Unreachable++
+				report.Reasons[result.UnreachableReason]++
+			}
+		}
+	}
+
+	return report, nil
+}
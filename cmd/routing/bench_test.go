@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"radar/internal/infra/routing/ch"
+	"radar/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBenchQueries_SameSeedSameRun(t *testing.T) {
+	dir := writeMatrixFixture(t)
+
+	engine := ch.NewEngine(ch.DefaultEngineConfig(), slog.New(slog.DiscardHandler))
+	require.NoError(t, engine.LoadData(dir))
+	sampler, err := newVertexSampler(engine.Vertices())
+	require.NoError(t, err)
+	require.Len(t, sampler, 4)
+
+	for _, targets := range []int{1, 3} {
+		opts := benchOptions{Queries: 20, TargetsPerQuery: targets, Seed: 7}
+
+		first, err := benchQueries(context.Background(), engine, sampler, opts)
+		require.NoError(t, err)
+		second, err := benchQueries(context.Background(), engine, sampler, opts)
+		require.NoError(t, err)
+
+		assert.Len(t, first.Latencies, 20)
+		assert.Equal(t, 20*targets, first.Routes)
+		// Every point is a vertex, so pairs fail only on the Penghu vertex that no road connects to Taipei
+		assert.Positive(t, first.Unreachable)
+		assert.Zero(t, first.Reasons[usecase.UnreachableReasonOffNetwork])
+		assert.Equal(t, first.Unreachable, second.Unreachable)
+		assert.Equal(t, first.Reasons, second.Reasons)
+	}
+}
+
+func TestBenchReport_Percentile(t *testing.T) {
+	report := benchReport{Routes: 4, Unreachable: 1}
+	for ms := 100; ms >= 1; ms-- {
+		report.Latencies = append(report.Latencies, time.Duration(ms)*time.Millisecond)
+	}
+
+	assert.Equal(t, 50*time.Millisecond, report.Percentile(50))
+	assert.Equal(t, 95*time.Millisecond, report.Percentile(95))
+	assert.Equal(t, 99*time.Millisecond, report.Percentile(99))
+	assert.Equal(t, 0.25, report.UnreachableRate())
+	assert.Zero(t, benchReport{}.Percentile(50))
+}
@@ -10,7 +10,7 @@ import (
 	"time"
 )
 
-func runConvert(ctx context.Context, input, output, region string, contract bool) error {
+func runConvert(ctx context.Context, input, output, region string, contract, restrictions bool) error {
 	absInput, err := filepath.Abs(input)
 	if err != nil {
 		return fmt.Errorf("failed to resolve absolute input path: %w", err)
@@ -23,6 +23,7 @@ func runConvert(ctx context.Context, input, output, region string, contract bool
 	fmt.Printf("Converting OSM data from %s to %s\n", absInput, absOutput)
 	fmt.Printf("Region: %s\n", region)
 	fmt.Printf("Contraction enabled: %v\n", contract)
+	fmt.Printf("Turn restrictions enabled: %v\n", restrictions)
 	fmt.Println()
 
 	// Validate input file exists
@@ -40,6 +41,15 @@ func runConvert(ctx context.Context, input, output, region string, contract bool
 		return fmt.Errorf("conversion failed: %w", err)
 	}
 
+	// Extract turn restrictions, or drop any left by an earlier conversion
+	if restrictions {
+		if err := runRestrictionExtraction(ctx, absInput, absOutput); err != nil {
+			return fmt.Errorf("turn restriction extraction failed: %w", err)
+		}
+	} else if err := removeRestrictions(absOutput); err != nil {
+		return err
+	}
+
 	// Generate metadata
 	if err := generateMetadata(absInput, absOutput, region, contract); err != nil {
 		return fmt.Errorf("failed to generate metadata: %w", err)
@@ -45,13 +45,18 @@ type DownloadConfig struct {
 	Verify    bool
 }
 
-func runDownload(ctx context.Context, region, outputDir string) error {
+func runDownload(ctx context.Context, region, outputDir string, policy DownloadPolicy) error {
 	// Get region configuration
 	regionConfig, exists := GetRegionConfig(region)
 	if !exists {
 		return fmt.Errorf("unsupported region '%s'. Supported regions: %s", region, strings.Join(ListRegions(), ", "))
 	}
 
+	// Reject before touching the network to avoid fetching the wrong multi-GB extract
+	if err := policy.Check(region, regionConfig.URL); err != nil {
+		return err
+	}
+
 	// Create download config
 	config := DownloadConfig{
 		Region:    region,
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Environment variables that seed the download policy flags, for CI environments
+// where flags are awkward to thread through
+const (
+	envAllowedRegions = "ROUTING_ALLOWED_REGIONS"
+	envDeniedRegions  = "ROUTING_DENIED_REGIONS"
+	envAllowedHosts   = "ROUTING_ALLOWED_HOSTS"
+)
+
+// DownloadPolicy restricts which regions and URL hosts may be downloaded.
+// Empty allowlists permit everything; the denylist always wins.
+type DownloadPolicy struct {
+	AllowedRegions []string
+	DeniedRegions  []string
+	AllowedHosts   []string
+}
+
+// newDownloadPolicy builds a policy from comma-separated flag values
+func newDownloadPolicy(allowedRegions, deniedRegions, allowedHosts string) DownloadPolicy {
+	return DownloadPolicy{
+		AllowedRegions: splitList(allowedRegions),
+		DeniedRegions:  splitList(deniedRegions),
+		AllowedHosts:   splitList(allowedHosts),
+	}
+}
+
+// Check returns an error if the region or the host of rawURL is not permitted
+func (p DownloadPolicy) Check(region, rawURL string) error {
+	region = strings.ToLower(region)
+
+	if slices.Contains(p.DeniedRegions, region) {
+		return fmt.Errorf("region '%s' is denied by the download policy", region)
+	}
+
+	if len(p.AllowedRegions) > 0 && !slices.Contains(p.AllowedRegions, region) {
+		return fmt.Errorf("region '%s' is not in the download allowlist. Allowed regions: %s", region, strings.Join(p.AllowedRegions, ", "))
+	}
+
+	if len(p.AllowedHosts) == 0 {
+		return nil
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse download URL: %w", err)
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if !slices.Contains(p.AllowedHosts, host) {
+		return fmt.Errorf("host '%s' is not in the download allowlist. Allowed hosts: %s", host, strings.Join(p.AllowedHosts, ", "))
+	}
+
+	return nil
+}
+
+// splitList parses a comma-separated list into trimmed, lowercased, non-empty entries
+func splitList(value string) []string {
+	var items []string
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
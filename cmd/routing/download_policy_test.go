@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadPolicy_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  DownloadPolicy
+		region  string
+		wantErr string
+	}{
+		{name: "empty policy allows everything", policy: DownloadPolicy{}, region: "taiwan"},
+		{name: "allowed region", policy: newDownloadPolicy("Taiwan, japan", "", ""), region: "taiwan"},
+		{name: "region outside allowlist", policy: newDownloadPolicy("taiwan", "", ""), region: "japan", wantErr: "not in the download allowlist"},
+		{name: "denied region", policy: newDownloadPolicy("", "japan", ""), region: "japan", wantErr: "denied by the download policy"},
+		{name: "denylist wins over allowlist", policy: newDownloadPolicy("japan", "japan", ""), region: "japan", wantErr: "denied"},
+		{name: "allowed host", policy: newDownloadPolicy("", "", "download.geofabrik.de"), region: "taiwan"},
+		{name: "host outside allowlist", policy: newDownloadPolicy("", "", "mirror.example.com"), region: "taiwan", wantErr: "host 'download.geofabrik.de'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			region, _ := GetRegionConfig(tt.region)
+			err := tt.policy.Check(tt.region, region.URL)
+
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// roundTripFunc serves the download client's requests in tests
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func TestRunDownload_DeniedRegionRejectedBeforeFetch(t *testing.T) {
+	outputDir := t.TempDir()
+	client := getHTTPClient()
+	previous := client.Transport
+	var fetched []string
+	client.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		fetched = append(fetched, req.URL.String())
+
+		return nil, errors.New("network disabled in tests")
+	})
+	t.Cleanup(func() { client.Transport = previous })
+	policy := newDownloadPolicy("taiwan", "", "")
+
+	err := runDownload(context.Background(), "japan", outputDir, policy)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "region 'japan' is not in the download allowlist")
+	assert.Empty(t, fetched, "a denied region must not reach the network")
+	assert.NoFileExists(t, outputDir+"/japan-latest.osm.pbf")
+
+	// An allowed region is fetched through the same client
+	err = runDownload(context.Background(), "taiwan", outputDir, policy)
+
+	require.ErrorContains(t, err, "network disabled in tests")
+	require.Len(t, fetched, 1)
+	assert.Contains(t, fetched[0], "taiwan")
+}
@@ -6,6 +6,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
+
+	"radar/internal/infra/routing/ch"
 )
 
 // Supported subcommands:
@@ -13,29 +15,62 @@ import (
 // - convert:  Convert to CH format
 // - prepare:  Download + convert in one step
 // - validate: Validate data integrity
+// - matrix:   Export a source-to-target road distance matrix
+// - pack:     Write the binary graph the engine can memory-map
+// - bench:    Benchmark routing query latency on random vertex pairs
 func main() {
 	// Subcommand definitions
 	downloadCmd := flag.NewFlagSet("download", flag.ExitOnError)
 	convertCmd := flag.NewFlagSet("convert", flag.ExitOnError)
 	prepareCmd := flag.NewFlagSet("prepare", flag.ExitOnError)
 	validateCmd := flag.NewFlagSet("validate", flag.ExitOnError)
+	matrixCmd := flag.NewFlagSet("matrix", flag.ExitOnError)
+	packCmd := flag.NewFlagSet("pack", flag.ExitOnError)
+	benchCmd := flag.NewFlagSet("bench", flag.ExitOnError)
 
 	// download parameters
 	downloadRegion := downloadCmd.String("region", "taiwan", "Region to download (taiwan, japan, etc.)")
 	downloadOutput := downloadCmd.String("output", "/tmp", "Output directory for PBF file")
+	downloadPolicy := registerDownloadPolicyFlags(downloadCmd)
 
 	// convert parameters
 	convertInput := convertCmd.String("input", "", "Input PBF file path")
 	convertOutput := convertCmd.String("output", "./data/routing", "Output directory for CSV files")
 	convertContract := convertCmd.Bool("contract", true, "Enable CH contraction preprocessing")
 	convertRegion := convertCmd.String("region", "unknown", "Region of the input data (taiwan, japan, etc.)")
+	convertNoRestrictions := convertCmd.Bool("no-restrictions", false, "Skip turn restriction extraction; routes may then use forbidden turns")
 
 	// prepare parameters (combines download + convert)
 	prepareRegion := prepareCmd.String("region", "taiwan", "Region to download")
 	prepareOutput := prepareCmd.String("output", "./data/routing", "Output directory for CSV files")
+	preparePolicy := registerDownloadPolicyFlags(prepareCmd)
 
 	// validate parameters
 	validateDir := validateCmd.String("dir", "./data/routing", "Directory to validate")
+	validateMaxDisconnected := validateCmd.Float64("max-disconnected-percent", defaultMaxDisconnectedPercent,
+		"Maximum percentage of vertices allowed outside the largest connected component")
+
+	// matrix parameters
+	matrixData := matrixCmd.String("data", "./data/routing", "Directory with the routing data to load")
+	matrixSources := matrixCmd.String("sources", "", "CSV of source points with lat,lng and an optional id column")
+	matrixTargets := matrixCmd.String("targets", "", "CSV of target points with lat,lng and an optional id column")
+	matrixOut := matrixCmd.String("out", "matrix.csv", "Output CSV path, one row per source/target pair")
+	matrixMaxRadius := matrixCmd.Float64("max-radius-km", ch.DefaultEngineConfig().MaxQueryRadiusMeters/1000,
+		"Targets farther than this straight-line distance from a source are reported out_of_range")
+	matrixWorkers := matrixCmd.Int("workers", ch.DefaultEngineConfig().OneToManyWorkers, "Concurrent routing workers per source")
+	matrixForceDijkstra := matrixCmd.Bool("force-dijkstra", false, forceDijkstraUsage)
+	matrixTurnRestrictions := matrixCmd.Bool("turn-restrictions", false, turnRestrictionsUsage)
+
+	// pack parameters
+	packDir := packCmd.String("dir", "./data/routing", "Directory with the CSV files; graph.bin is written next to them")
+
+	// bench parameters
+	benchData := benchCmd.String("data", "./data/routing", "Directory with the routing data to load")
+	benchQueries := benchCmd.Int("queries", 1000, "Number of queries to run")
+	benchTargets := benchCmd.Int("targets-per-query", 1, "Targets per query; 1 uses ShortestPath, more use OneToMany")
+	benchSeed := benchCmd.Uint64("seed", 1, "Seed for the random source/target pairs")
+	benchForceDijkstra := benchCmd.Bool("force-dijkstra", false, forceDijkstraUsage)
+	benchTurnRestrictions := benchCmd.Bool("turn-restrictions", false, turnRestrictionsUsage)
 
 	if len(os.Args) < 2 {
 		printUsage()
@@ -50,22 +85,51 @@ func main() {
 			cmd:    downloadCmd,
 			region: downloadRegion,
 			output: downloadOutput,
+			policy: downloadPolicy,
 		},
 		Convert: convertFlags{
-			cmd:      convertCmd,
-			input:    convertInput,
-			output:   convertOutput,
-			contract: convertContract,
-			region:   convertRegion,
+			cmd:            convertCmd,
+			input:          convertInput,
+			output:         convertOutput,
+			contract:       convertContract,
+			region:         convertRegion,
+			noRestrictions: convertNoRestrictions,
 		},
 		Prepare: prepareFlags{
 			cmd:    prepareCmd,
 			region: prepareRegion,
 			output: prepareOutput,
+			policy: preparePolicy,
 		},
 		Validate: validateFlags{
-			cmd: validateCmd,
-			dir: validateDir,
+			cmd:                    validateCmd,
+			dir:                    validateDir,
+			maxDisconnectedPercent: validateMaxDisconnected,
+		},
+		Matrix: matrixFlags{
+			cmd:       matrixCmd,
+			data:      matrixData,
+			sources:   matrixSources,
+			targets:   matrixTargets,
+			out:       matrixOut,
+			maxRadius: matrixMaxRadius,
+			workers:   matrixWorkers,
+
+			forceDijkstra:    matrixForceDijkstra,
+			turnRestrictions: matrixTurnRestrictions,
+		},
+		Pack: packFlags{
+			cmd: packCmd,
+			dir: packDir,
+		},
+		Bench: benchFlags{
+			cmd:              benchCmd,
+			data:             benchData,
+			queries:          benchQueries,
+			targetsPerQuery:  benchTargets,
+			seed:             benchSeed,
+			forceDijkstra:    benchForceDijkstra,
+			turnRestrictions: benchTurnRestrictions,
 		},
 	}
 
@@ -80,12 +144,35 @@ type routingFlags struct {
 	Convert  convertFlags
 	Prepare  prepareFlags
 	Validate validateFlags
+	Matrix   matrixFlags
+	Pack     packFlags
+	Bench    benchFlags
 }
 
 type downloadFlags struct {
 	cmd    *flag.FlagSet
 	region *string
 	output *string
+	policy downloadPolicyFlags
+}
+
+type downloadPolicyFlags struct {
+	allowedRegions *string
+	deniedRegions  *string
+	allowedHosts   *string
+}
+
+// registerDownloadPolicyFlags adds the download allow/deny flags, defaulting to the environment
+func registerDownloadPolicyFlags(cmd *flag.FlagSet) downloadPolicyFlags {
+	return downloadPolicyFlags{
+		allowedRegions: cmd.String("allow-regions", os.Getenv(envAllowedRegions), "Comma-separated regions permitted for download (env "+envAllowedRegions+")"),
+		deniedRegions:  cmd.String("deny-regions", os.Getenv(envDeniedRegions), "Comma-separated regions rejected for download (env "+envDeniedRegions+")"),
+		allowedHosts:   cmd.String("allow-hosts", os.Getenv(envAllowedHosts), "Comma-separated URL hosts permitted for download (env "+envAllowedHosts+")"),
+	}
+}
+
+func (f downloadPolicyFlags) policy() DownloadPolicy {
+	return newDownloadPolicy(*f.allowedRegions, *f.deniedRegions, *f.allowedHosts)
 }
 
 type convertFlags struct {
@@ -94,19 +181,51 @@ type convertFlags struct {
 	output   *string
 	contract *bool
 	region   *string
+
+	noRestrictions *bool
 }
 
 type prepareFlags struct {
 	cmd    *flag.FlagSet
 	region *string
 	output *string
+	policy downloadPolicyFlags
 }
 
 type validateFlags struct {
+	cmd                    *flag.FlagSet
+	dir                    *string
+	maxDisconnectedPercent *float64
+}
+
+type matrixFlags struct {
+	cmd       *flag.FlagSet
+	data      *string
+	sources   *string
+	targets   *string
+	out       *string
+	maxRadius *float64
+	workers   *int
+
+	forceDijkstra    *bool
+	turnRestrictions *bool
+}
+
+type packFlags struct {
 	cmd *flag.FlagSet
 	dir *string
 }
 
+type benchFlags struct {
+	cmd              *flag.FlagSet
+	data             *string
+	queries          *int
+	targetsPerQuery  *int
+	seed             *uint64
+	forceDijkstra    *bool
+	turnRestrictions *bool
+}
+
 func runSubcommand(ctx context.Context, flags *routingFlags) error {
 	switch os.Args[1] {
 	case "download":
@@ -117,6 +236,12 @@ func runSubcommand(ctx context.Context, flags *routingFlags) error {
 		return handlePrepare(ctx, flags)
 	case "validate":
 		return handleValidate(flags)
+	case "matrix":
+		return handleMatrix(ctx, flags)
+	case "pack":
+		return handlePack(flags)
+	case "bench":
+		return handleBench(ctx, flags)
 	default:
 		printUsage()
 
@@ -129,7 +254,7 @@ func handleDownload(ctx context.Context, flags *routingFlags) error {
 		return fmt.Errorf("failed to parse download flags: %w", err)
 	}
 
-	return runDownload(ctx, *flags.Download.region, *flags.Download.output)
+	return runDownload(ctx, *flags.Download.region, *flags.Download.output, flags.Download.policy.policy())
 }
 
 func handleConvert(ctx context.Context, flags *routingFlags) error {
@@ -141,7 +266,8 @@ func handleConvert(ctx context.Context, flags *routingFlags) error {
 		return errors.New("--input flag is required for convert command")
 	}
 
-	return runConvert(ctx, *flags.Convert.input, *flags.Convert.output, *flags.Convert.region, *flags.Convert.contract)
+	return runConvert(ctx, *flags.Convert.input, *flags.Convert.output, *flags.Convert.region,
+		*flags.Convert.contract, !*flags.Convert.noRestrictions)
 }
 
 func handlePrepare(ctx context.Context, flags *routingFlags) error {
@@ -149,7 +275,7 @@ func handlePrepare(ctx context.Context, flags *routingFlags) error {
 		return fmt.Errorf("failed to parse prepare flags: %w", err)
 	}
 
-	return runPrepare(ctx, *flags.Prepare.region, *flags.Prepare.output)
+	return runPrepare(ctx, *flags.Prepare.region, *flags.Prepare.output, flags.Prepare.policy.policy())
 }
 
 func handleValidate(flags *routingFlags) error {
@@ -157,9 +283,60 @@ func handleValidate(flags *routingFlags) error {
 		return fmt.Errorf("failed to parse validate flags: %w", err)
 	}
 
-	return runValidate(*flags.Validate.dir)
+	return runValidate(*flags.Validate.dir, *flags.Validate.maxDisconnectedPercent)
+}
+
+func handleMatrix(ctx context.Context, flags *routingFlags) error {
+	if err := flags.Matrix.cmd.Parse(os.Args[2:]); err != nil {
+		return fmt.Errorf("failed to parse matrix flags: %w", err)
+	}
+
+	if *flags.Matrix.sources == "" || *flags.Matrix.targets == "" {
+		return errors.New("--sources and --targets flags are required for matrix command")
+	}
+
+	return runMatrix(ctx, matrixOptions{
+		DataDir:     *flags.Matrix.data,
+		SourcesPath: *flags.Matrix.sources,
+		TargetsPath: *flags.Matrix.targets,
+		OutPath:     *flags.Matrix.out,
+		MaxRadiusKm: *flags.Matrix.maxRadius,
+		Workers:     *flags.Matrix.workers,
+
+		ForceDijkstra:    *flags.Matrix.forceDijkstra,
+		TurnRestrictions: *flags.Matrix.turnRestrictions,
+	})
+}
+
+func handlePack(flags *routingFlags) error {
+	if err := flags.Pack.cmd.Parse(os.Args[2:]); err != nil {
+		return fmt.Errorf("failed to parse pack flags: %w", err)
+	}
+
+	return runPack(*flags.Pack.dir)
+}
+
+func handleBench(ctx context.Context, flags *routingFlags) error {
+	if err := flags.Bench.cmd.Parse(os.Args[2:]); err != nil {
+		return fmt.Errorf("failed to parse bench flags: %w", err)
+	}
+
+	return runBench(ctx, benchOptions{
+		DataDir:          *flags.Bench.data,
+		Queries:          *flags.Bench.queries,
+		TargetsPerQuery:  *flags.Bench.targetsPerQuery,
+		Seed:             *flags.Bench.seed,
+		ForceDijkstra:    *flags.Bench.forceDijkstra,
+		TurnRestrictions: *flags.Bench.turnRestrictions,
+	})
 }
 
+// forceDijkstraUsage describes the debug flag that bypasses the CH query
+const forceDijkstraUsage = "Route with plain Dijkstra instead of the CH query, to check contracted data against a reference"
+
+// turnRestrictionsUsage describes the flag that honors restrictions.csv, as routing.ch.turnRestrictions does
+const turnRestrictionsUsage = "Honor restrictions.csv; every route then uses turn-aware Dijkstra instead of the CH query"
+
 func printUsage() {
 	fmt.Println("Usage: routing-cli <command> [options]")
 	fmt.Println("")
@@ -168,6 +345,9 @@ func printUsage() {
 	fmt.Println("  convert     Convert OSM PBF to CH format")
 	fmt.Println("  prepare     Download and convert in one step")
 	fmt.Println("  validate    Validate data integrity")
+	fmt.Println("  matrix      Export a source-to-target road distance matrix")
+	fmt.Println("  pack        Write graph.bin so the engine can memory-map the graph")
+	fmt.Println("  bench       Benchmark routing query latency on random source/target pairs")
 	fmt.Println("")
 	fmt.Println("Use 'routing-cli <command> -h' for more information about a command.")
 }
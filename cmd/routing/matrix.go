@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"radar/internal/infra/routing/ch"
+)
+
+// matrixHeader is the header of the matrix CSV; each row is one source/target pair
+var matrixHeader = []string{"source_id", "target_id", "distance_m", "duration_s", "reachable", "unreachable_reason"}
+
+// matrixPoint is one row of a sources or targets CSV
+type matrixPoint struct {
+	ID    string
+	Coord ch.Coordinate
+}
+
+// matrixOptions holds the inputs of the matrix subcommand
+type matrixOptions struct {
+	DataDir     string
+	SourcesPath string
+	TargetsPath string
+	OutPath     string
+	MaxRadiusKm float64
+	Workers     int
+
+	ForceDijkstra    bool
+	TurnRestrictions bool
+}
+
+// runMatrix loads the routing engine and writes road distances from every source to every target.
+// Targets are held in memory while sources are read and routed one at a time, and each source's
+// rows are flushed before the next is read, so the output never has to fit in memory.
+func runMatrix(ctx context.Context, opts matrixOptions) error {
+	config := ch.DefaultEngineConfig()
+	if opts.MaxRadiusKm > 0 {
+		config.MaxQueryRadiusMeters = opts.MaxRadiusKm * 1000
+	}
+	if opts.Workers > 0 {
+		config.OneToManyWorkers = opts.Workers
+	}
+	config.ForceDijkstra = opts.ForceDijkstra
+	config.TurnRestrictions = opts.TurnRestrictions
+
+	fmt.Printf("Loading routing data from: %s\n", opts.DataDir)
+	engine := ch.NewEngine(config, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+	if err := engine.LoadData(opts.DataDir); err != nil {
+		return fmt.Errorf("failed to load routing data: %w", err)
+	}
+
+	targets, err := readMatrixTargets(opts.TargetsPath)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Loaded %d targets from: %s\n", len(targets), opts.TargetsPath)
+
+	sourcesFile, err := os.Open(opts.SourcesPath)
+	if err != nil {
+		return fmt.Errorf("failed to open sources file: %w", err)
+	}
+	defer sourcesFile.Close()
+
+	outFile, err := os.Create(opts.OutPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer outFile.Close()
+
+	sources, err := writeMatrix(ctx, engine, sourcesFile, targets, outFile)
+	if err != nil {
+		return fmt.Errorf("matrix export failed after %d sources: %w", sources, err)
+	}
+	if err := outFile.Close(); err != nil {
+		return fmt.Errorf("failed to close output file: %w", err)
+	}
+
+	fmt.Printf("✅ Wrote %dx%d matrix to: %s\n", sources, len(targets), opts.OutPath)
+
+	return nil
+}
+
+// writeMatrix routes each source read from sources to all targets and writes the pairs to out.
+// It returns the number of sources written.
+func writeMatrix(ctx context.Context, engine *ch.Engine, sources io.Reader, targets []matrixPoint, out io.Writer) (int, error) {
+	reader, err := newMatrixPointReader(sources)
+	if err != nil {
+		return 0, fmt.Errorf("sources: %w", err)
+	}
+
+	writer := csv.NewWriter(out)
+	if err := writer.Write(matrixHeader); err != nil {
+		return 0, fmt.Errorf("failed to write matrix header: %w", err)
+	}
+
+	coords := make([]ch.Coordinate, len(targets))
+	for idx, target := range targets {
+		coords[idx] = target.Coord
+	}
+
+	written := 0
+	for {
+		source, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return written, fmt.Errorf("sources: %w", err)
+		}
+
+		// An off-network source still returns every target marked unreachable
+		results, err := engine.OneToMany(ctx, ch.ProfileDefault, source.Coord, coords)
+		if err != nil && !errors.Is(err, ch.ErrSnapDistanceExceeded) {
+			return written, fmt.Errorf("failed to route source %s: %w", source.ID, err)
+		}
+
+		for idx, result := range results {
+			if err := writer.Write(matrixRow(source, targets[idx], result)); err != nil {
+				return written, fmt.Errorf("failed to write matrix row: %w", err)
+			}
+		}
+
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return written, fmt.Errorf("failed to write matrix rows: %w", err)
+		}
+		written++
+	}
+
+	return written, nil
+}
+
+func matrixRow(source, target matrixPoint, result ch.RouteResult) []string {
+	if !result.IsReachable {
+		return []string{source.ID, target.ID, "", "", "false", string(result.UnreachableReason)}
+	}
+
+	return []string{
+		source.ID,
+		target.ID,
+		strconv.FormatFloat(result.Distance, 'f', 1, 64),
+		strconv.FormatFloat(result.Duration.Seconds(), 'f', 1, 64),
+		"true",
+		"",
+	}
+}
+
+// readMatrixTargets reads every point of a targets CSV
+func readMatrixTargets(path string) ([]matrixPoint, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open targets file: %w", err)
+	}
+	defer file.Close()
+
+	reader, err := newMatrixPointReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("targets: %w", err)
+	}
+
+	var points []matrixPoint
+	for {
+		point, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return points, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("targets: %w", err)
+		}
+		points = append(points, point)
+	}
+}
+
+// matrixPointReader reads points from a CSV with lat and lng columns and an optional id column.
+// Rows without an id are named after their 1-based row number.
+type matrixPointReader struct {
+	reader  *csv.Reader
+	idCol   int // -1 when the file has no id column
+	latCol  int
+	lngCol  int
+	lastRow int
+}
+
+func newMatrixPointReader(r io.Reader) (*matrixPointReader, error) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("file is empty or has no header")
+		}
+
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for idx, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = idx
+	}
+
+	pointReader := &matrixPointReader{reader: reader, idCol: -1}
+	var ok bool
+	if pointReader.latCol, ok = columns["lat"]; !ok {
+		return nil, fmt.Errorf("missing required column 'lat' in header. Got: %v", header)
+	}
+	if pointReader.lngCol, ok = columns["lng"]; !ok {
+		return nil, fmt.Errorf("missing required column 'lng' in header. Got: %v", header)
+	}
+	if idCol, ok := columns["id"]; ok {
+		pointReader.idCol = idCol
+	}
+
+	return pointReader, nil
+}
+
+// Next returns the next point, or io.EOF after the last row
+func (r *matrixPointReader) Next() (matrixPoint, error) {
+	record, err := r.reader.Read()
+	if err != nil {
+		return matrixPoint{}, err
+	}
+	r.lastRow++
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(record[r.latCol]), 64)
+	if err != nil {
+		return matrixPoint{}, fmt.Errorf("row %d: invalid lat %q: %w", r.lastRow, record[r.latCol], err)
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(record[r.lngCol]), 64)
+	if err != nil {
+		return matrixPoint{}, fmt.Errorf("row %d: invalid lng %q: %w", r.lastRow, record[r.lngCol], err)
+	}
+
+	id := strconv.Itoa(r.lastRow)
+	if r.idCol >= 0 && strings.TrimSpace(record[r.idCol]) != "" {
+		id = strings.TrimSpace(record[r.idCol])
+	}
+
+	return matrixPoint{ID: id, Coord: ch.Coordinate{Lat: lat, Lng: lng}}, nil
+}
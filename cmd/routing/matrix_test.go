@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeMatrixFixture writes a three-vertex Taipei graph plus an unconnected Penghu vertex
+func writeMatrixFixture(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		routingVerticesCSV: `id,lat,lng,order_pos,importance
+0,25.0330,121.5654,0,1
+1,25.0478,121.5170,1,2
+2,25.0400,121.5400,2,3
+3,23.5711,119.5793,3,4
+`,
+		routingEdgesCSV: `from,to,weight
+0,1,2000
+1,2,1500
+0,2,2500
+2,0,2500
+`,
+		routingShortcutsCSV: "from,to,weight,via_node\n",
+		"sources.csv": `id,lat,lng
+taipei-main,25.0330,121.5654
+taipei-west,25.0478,121.5170
+off-road,25.0500,121.5800
+`,
+		"targets.csv": `lat,lng
+25.0478,121.5170
+25.0400,121.5400
+23.5711,119.5793
+`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	return dir
+}
+
+func TestRunMatrix(t *testing.T) {
+	dir := writeMatrixFixture(t)
+	out := filepath.Join(dir, "matrix.csv")
+
+	err := runMatrix(context.Background(), matrixOptions{
+		DataDir:     dir,
+		SourcesPath: filepath.Join(dir, "sources.csv"),
+		TargetsPath: filepath.Join(dir, "targets.csv"),
+		OutPath:     out,
+		Workers:     2,
+	})
+	require.NoError(t, err)
+
+	file, err := os.Open(out)
+	require.NoError(t, err)
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+
+	// One header plus a row for each of the 3x3 source/target pairs
+	require.Len(t, rows, 1+3*3)
+	assert.Equal(t, matrixHeader, rows[0])
+
+	// Direct edge 0->1 at the default 30 km/h
+	assert.Equal(t, []string{"taipei-main", "1", "2000.0", "240.0", "true", ""}, rows[1])
+	// Direct edge 1->2
+	assert.Equal(t, []string{"taipei-west", "2", "1500.0", "180.0", "true", ""}, rows[5])
+	// Penghu is beyond the query radius
+	assert.Equal(t, []string{"taipei-main", "3", "", "", "false", "out_of_range"}, rows[3])
+
+	// A source too far from any road is reported unreachable instead of failing the export
+	for _, row := range rows[7:] {
+		assert.Equal(t, "off-road", row[0])
+		assert.Equal(t, []string{"", "", "false", "off_network"}, row[2:])
+	}
+}
+
+func TestRunMatrix_InvalidPoints(t *testing.T) {
+	dir := writeMatrixFixture(t)
+	badTargets := filepath.Join(dir, "bad_targets.csv")
+	require.NoError(t, os.WriteFile(badTargets, []byte("latitude,lng\n25.0,121.5\n"), 0644))
+
+	err := runMatrix(context.Background(), matrixOptions{
+		DataDir:     dir,
+		SourcesPath: filepath.Join(dir, "sources.csv"),
+		TargetsPath: badTargets,
+		OutPath:     filepath.Join(dir, "matrix.csv"),
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required column 'lat'")
+}
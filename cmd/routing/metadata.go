@@ -19,6 +19,9 @@ const (
 	routingShortcutsCSV = "shortcuts.csv"
 )
 
+// optionalOutputFiles are only written by some conversions, so their absence is not reported
+var optionalOutputFiles = map[string]bool{routingRestrictionsCSV: true}
+
 // RoutingMetadata represents the metadata for routing data
 type RoutingMetadata struct {
 	Version    string             `json:"version"`
@@ -50,10 +53,11 @@ type ProcessingMetadata struct {
 
 // OutputMetadata contains information about the output files
 type OutputMetadata struct {
-	VerticesCount  int                     `json:"vertices_count"`
-	EdgesCount     int                     `json:"edges_count"`
-	ShortcutsCount int                     `json:"shortcuts_count"`
-	Files          map[string]FileMetadata `json:"files"`
+	VerticesCount     int                     `json:"vertices_count"`
+	EdgesCount        int                     `json:"edges_count"`
+	ShortcutsCount    int                     `json:"shortcuts_count"`
+	RestrictionsCount int                     `json:"restrictions_count,omitempty"`
+	Files             map[string]FileMetadata `json:"files"`
 }
 
 // FileMetadata contains metadata for individual output files
@@ -132,7 +136,7 @@ func getOutputMetadata(outputDir string) (*OutputMetadata, error) {
 		Files: make(map[string]FileMetadata),
 	}
 
-	expectedFiles := [...]string{routingVerticesCSV, routingEdgesCSV, routingShortcutsCSV}
+	expectedFiles := [...]string{routingVerticesCSV, routingEdgesCSV, routingShortcutsCSV, routingRestrictionsCSV}
 
 	for _, filename := range expectedFiles {
 		filePath := filepath.Join(outputDir, filename)
@@ -140,7 +144,9 @@ func getOutputMetadata(outputDir string) (*OutputMetadata, error) {
 		info, err := os.Stat(filePath)
 		if err != nil {
 			if os.IsNotExist(err) {
-				fmt.Printf("Warning: Expected output file not found: %s\n", filePath)
+				if !optionalOutputFiles[filename] {
+					fmt.Printf("Warning: Expected output file not found: %s\n", filePath)
+				}
 
 				continue
 			}
@@ -173,6 +179,8 @@ func getOutputMetadata(outputDir string) (*OutputMetadata, error) {
 				output.EdgesCount = count
 			case routingShortcutsCSV:
 				output.ShortcutsCount = count
+			case routingRestrictionsCSV:
+				output.RestrictionsCount = count
 			}
 		}
 	}
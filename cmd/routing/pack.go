@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"radar/internal/infra/routing/loader"
+)
+
+// runPack converts the CSV files in dataDir into the binary graph the CH engine memory-maps
+func runPack(dataDir string) error {
+	fmt.Printf("Loading CSV routing data from: %s\n", dataDir)
+	graphData, err := loader.NewCSVLoader(dataDir).Load()
+	if err != nil {
+		return fmt.Errorf("failed to load routing data: %w", err)
+	}
+
+	graph := loader.BuildCSRGraph(graphData)
+	if graph.SkippedEdges > 0 || graph.SkippedShortcuts > 0 {
+		fmt.Printf("⚠️  Skipped %d edges and %d shortcuts with out-of-range vertices\n", graph.SkippedEdges, graph.SkippedShortcuts)
+	}
+
+	// The engine refuses the graph once metadata.json no longer matches, so a re-prepared directory needs a new pack
+	source, err := loader.SourceFingerprint(dataDir)
+	if err != nil {
+		return fmt.Errorf("failed to fingerprint routing data: %w", err)
+	}
+
+	out := filepath.Join(dataDir, loader.BinaryGraphFile)
+	if err := loader.WriteBinaryGraph(out, graph, source); err != nil {
+		return fmt.Errorf("failed to write binary graph: %w", err)
+	}
+
+	fmt.Printf("✅ Wrote %d vertices and %d arcs (%d bytes) to: %s\n", len(graph.Vertices), len(graph.Arcs), graph.SizeBytes(), out)
+
+	return nil
+}
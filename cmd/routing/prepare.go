@@ -10,7 +10,7 @@ import (
 	"radar/internal/util"
 )
 
-func runPrepare(ctx context.Context, region, output string) error {
+func runPrepare(ctx context.Context, region, output string, policy DownloadPolicy) error {
 	fmt.Printf("Preparing routing data for region: %s\n", region)
 	fmt.Printf("Output directory: %s\n", output)
 	fmt.Println()
@@ -20,7 +20,7 @@ func runPrepare(ctx context.Context, region, output string) error {
 	// Step 1: Download OSM data
 	fmt.Println("=== Step 1: Downloading OSM data ===")
 	tempDir := os.TempDir()
-	if err := runDownload(ctx, region, tempDir); err != nil {
+	if err := runDownload(ctx, region, tempDir, policy); err != nil {
 		return fmt.Errorf("download failed: %w", err)
 	}
 
@@ -43,7 +43,7 @@ func runPrepare(ctx context.Context, region, output string) error {
 
 	// Step 2: Convert OSM data and generate metadata
 	fmt.Println("\n=== Step 2: Converting OSM data and generating metadata ===")
-	if err := runConvert(ctx, inputFile, output, region, true); err != nil {
+	if err := runConvert(ctx, inputFile, output, region, true, true); err != nil {
 		return fmt.Errorf("conversion failed: %w", err)
 	}
 
@@ -51,7 +51,7 @@ func runPrepare(ctx context.Context, region, output string) error {
 
 	// Step 3: Validate results
 	fmt.Println("\n=== Step 3: Validating results ===")
-	if err := validateRoutingData(output); err != nil {
+	if err := validateRoutingData(output, defaultMaxDisconnectedPercent); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
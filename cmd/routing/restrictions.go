@@ -0,0 +1,361 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"radar/internal/infra/routing/loader"
+
+	"github.com/paulmach/osm"
+	"github.com/paulmach/osm/osmpbf"
+)
+
+const routingRestrictionsCSV = "restrictions.csv"
+
+// osmTurnRestriction is a type=restriction relation with a single from way, via node, and to way
+type osmTurnRestriction struct {
+	only    bool // only_* restrictions allow just this turn; no_* restrictions forbid it
+	fromWay int64
+	viaNode int64
+	toWay   int64
+}
+
+// restrictionStats summarizes an extraction
+type restrictionStats struct {
+	Relations int // Restriction relations with a supported shape
+	Skipped   int // Supported relations whose ways or via node could not be matched to the graph
+	Written   int // Forbidden turns written to restrictions.csv
+}
+
+// runRestrictionExtraction reads turn restrictions from the PBF input and writes the turns they forbid,
+// as vertex triples of the converted graph, to restrictions.csv in output
+func runRestrictionExtraction(ctx context.Context, input, output string) error {
+	fmt.Println("Extracting turn restrictions...")
+
+	stats, err := extractRestrictions(ctx, input, output)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Turn restrictions: %d relations, %d skipped, %d forbidden turns written\n",
+		stats.Relations, stats.Skipped, stats.Written)
+
+	return nil
+}
+
+func extractRestrictions(ctx context.Context, input, output string) (restrictionStats, error) {
+	var stats restrictionStats
+
+	// Pass 1: restriction relations, which follow the nodes and ways in the file
+	var osmRestrictions []osmTurnRestriction
+	err := scanOSMPBF(ctx, input, func(scanner *osmpbf.Scanner) {
+		scanner.SkipNodes = true
+		scanner.SkipWays = true
+	}, func(object osm.Object) {
+		if restriction, ok := parseTurnRestriction(object.(*osm.Relation)); ok {
+			osmRestrictions = append(osmRestrictions, restriction)
+		}
+	})
+	if err != nil {
+		return stats, err
+	}
+	stats.Relations = len(osmRestrictions)
+
+	// Pass 2: the node lists of the from and to ways. The filters run on the decoder goroutines,
+	// so they only read the wanted sets while results are collected in separate maps.
+	wantedWays := make(map[osm.WayID]struct{})
+	for _, restriction := range osmRestrictions {
+		wantedWays[osm.WayID(restriction.fromWay)] = struct{}{}
+		wantedWays[osm.WayID(restriction.toWay)] = struct{}{}
+	}
+	wayRefs := make(map[int64][]int64, len(wantedWays))
+	err = scanOSMPBF(ctx, input, func(scanner *osmpbf.Scanner) {
+		scanner.SkipNodes = true
+		scanner.SkipRelations = true
+		scanner.FilterWay = func(way *osm.Way) bool {
+			_, wanted := wantedWays[way.ID]
+
+			return wanted
+		}
+	}, func(object osm.Object) {
+		way := object.(*osm.Way)
+		refs := make([]int64, len(way.Nodes))
+		for i, node := range way.Nodes {
+			refs[i] = int64(node.ID)
+		}
+		wayRefs[int64(way.ID)] = refs
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	// Pass 3: coordinates of every node on those ways, which include the via nodes
+	wantedNodes := make(map[osm.NodeID]struct{})
+	for _, refs := range wayRefs {
+		for _, ref := range refs {
+			wantedNodes[osm.NodeID(ref)] = struct{}{}
+		}
+	}
+	nodeCoords := make(map[int64]vertexKey, len(wantedNodes))
+	err = scanOSMPBF(ctx, input, func(scanner *osmpbf.Scanner) {
+		scanner.SkipWays = true
+		scanner.SkipRelations = true
+		scanner.FilterNode = func(node *osm.Node) bool {
+			_, wanted := wantedNodes[node.ID]
+
+			return wanted
+		}
+	}, func(object osm.Object) {
+		node := object.(*osm.Node)
+		nodeCoords[int64(node.ID)] = newVertexKey(node.Lat, node.Lon)
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	graph, err := loadRestrictionGraph(output)
+	if err != nil {
+		return stats, err
+	}
+
+	forbidden := make(map[loader.Restriction]struct{})
+	for _, restriction := range osmRestrictions {
+		turns := graph.resolve(restriction, wayRefs, nodeCoords)
+		if len(turns) == 0 {
+			stats.Skipped++
+
+			continue
+		}
+		for _, turn := range turns {
+			forbidden[turn] = struct{}{}
+		}
+	}
+	stats.Written = len(forbidden)
+
+	if err := writeRestrictions(filepath.Join(output, routingRestrictionsCSV), forbidden); err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+// scanOSMPBF streams the elements of a PBF file to visit. configure selects the element types to
+// decode and may set filters, which keep unwanted elements from being allocated.
+func scanOSMPBF(ctx context.Context, path string, configure func(*osmpbf.Scanner), visit func(osm.Object)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := osmpbf.New(ctx, file, runtime.GOMAXPROCS(0))
+	defer scanner.Close()
+	configure(scanner)
+
+	for scanner.Scan() {
+		visit(scanner.Object())
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// parseTurnRestriction accepts restriction relations with exactly one from way, via node, and to way.
+// Restrictions through via ways, and conditional or vehicle-specific ones, are not supported.
+func parseTurnRestriction(relation *osm.Relation) (osmTurnRestriction, bool) {
+	if relation.Tags.Find("type") != "restriction" {
+		return osmTurnRestriction{}, false
+	}
+
+	kind := relation.Tags.Find("restriction")
+	restriction := osmTurnRestriction{only: strings.HasPrefix(kind, "only_")}
+	if !restriction.only && !strings.HasPrefix(kind, "no_") {
+		return osmTurnRestriction{}, false
+	}
+
+	var from, via, to int
+	for _, member := range relation.Members {
+		switch {
+		case member.Role == "from" && member.Type == osm.TypeWay:
+			restriction.fromWay = member.Ref
+			from++
+		case member.Role == "via" && member.Type == osm.TypeNode:
+			restriction.viaNode = member.Ref
+			via++
+		case member.Role == "via":
+			return osmTurnRestriction{}, false
+		case member.Role == "to" && member.Type == osm.TypeWay:
+			restriction.toWay = member.Ref
+			to++
+		}
+	}
+
+	return restriction, from == 1 && via == 1 && to == 1
+}
+
+// vertexKey identifies a location at the converter's 6-digit geometry precision
+type vertexKey struct {
+	lat, lng int64
+	valid    bool
+}
+
+func newVertexKey(lat, lng float64) vertexKey {
+	return vertexKey{lat: int64(math.Round(lat * 1e6)), lng: int64(math.Round(lng * 1e6)), valid: true}
+}
+
+// restrictionGraph maps OSM node locations onto the vertices and edges of the converted graph
+type restrictionGraph struct {
+	vertices map[vertexKey]int64
+	out      map[int64][]int64 // Vertices reachable over one edge
+}
+
+func loadRestrictionGraph(dataDir string) (*restrictionGraph, error) {
+	csvLoader := loader.NewCSVLoader(dataDir)
+	vertices, err := csvLoader.LoadVertices()
+	if err != nil {
+		return nil, fmt.Errorf("load converted vertices: %w", err)
+	}
+	edges, err := csvLoader.LoadEdges()
+	if err != nil {
+		return nil, fmt.Errorf("load converted edges: %w", err)
+	}
+
+	graph := &restrictionGraph{
+		vertices: make(map[vertexKey]int64, len(vertices)),
+		out:      make(map[int64][]int64),
+	}
+	for _, vertex := range vertices {
+		graph.vertices[newVertexKey(vertex.Lat, vertex.Lng)] = vertex.ID
+	}
+	for _, edge := range edges {
+		graph.out[edge.From] = append(graph.out[edge.From], edge.To)
+	}
+
+	return graph, nil
+}
+
+// resolve returns the vertex turns a restriction forbids. A no_* restriction forbids the turn from the
+// from way onto the to way; an only_* restriction forbids every other turn leaving the from way at the via.
+func (g *restrictionGraph) resolve(restriction osmTurnRestriction, wayRefs map[int64][]int64, nodeCoords map[int64]vertexKey) []loader.Restriction {
+	via, ok := g.vertices[nodeCoords[restriction.viaNode]]
+	if !ok {
+		return nil
+	}
+
+	fromVertices := g.wayNeighbors(wayRefs[restriction.fromWay], restriction.viaNode, nodeCoords)
+	toVertices := g.wayNeighbors(wayRefs[restriction.toWay], restriction.viaNode, nodeCoords)
+	// An only_* restriction with an unmatched to way would forbid every turn at the via
+	if len(toVertices) == 0 {
+		return nil
+	}
+
+	var turns []loader.Restriction
+	for _, from := range fromVertices {
+		if !slices.Contains(g.out[from], via) {
+			continue
+		}
+
+		if !restriction.only {
+			for _, to := range toVertices {
+				if slices.Contains(g.out[via], to) {
+					turns = append(turns, loader.Restriction{From: from, Via: via, To: to})
+				}
+			}
+
+			continue
+		}
+
+		for _, to := range g.out[via] {
+			if !slices.Contains(toVertices, to) {
+				turns = append(turns, loader.Restriction{From: from, Via: via, To: to})
+			}
+		}
+	}
+
+	return turns
+}
+
+// wayNeighbors walks a way from the via node in both directions and returns the first graph vertex
+// found on each side
+func (g *restrictionGraph) wayNeighbors(refs []int64, viaNode int64, nodeCoords map[int64]vertexKey) []int64 {
+	at := slices.Index(refs, viaNode)
+	if at < 0 {
+		return nil
+	}
+
+	var neighbors []int64
+	for _, step := range []int{-1, 1} {
+		for idx := at + step; idx >= 0 && idx < len(refs); idx += step {
+			if vertex, ok := g.vertices[nodeCoords[refs[idx]]]; ok {
+				neighbors = append(neighbors, vertex)
+
+				break
+			}
+		}
+	}
+
+	return neighbors
+}
+
+// writeRestrictions writes the forbidden turns sorted by vertex, in the format loader.LoadRestrictions reads
+func writeRestrictions(path string, forbidden map[loader.Restriction]struct{}) (err error) {
+	turns := make([]loader.Restriction, 0, len(forbidden))
+	for turn := range forbidden {
+		turns = append(turns, turn)
+	}
+	slices.SortFunc(turns, func(a, b loader.Restriction) int {
+		return cmp.Or(cmp.Compare(a.Via, b.Via), cmp.Compare(a.From, b.From), cmp.Compare(a.To, b.To))
+	})
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("close %s: %w", path, closeErr)
+		}
+	}()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"from", "via", "to"}); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	for _, turn := range turns {
+		record := []string{
+			strconv.FormatInt(turn.From, 10),
+			strconv.FormatInt(turn.Via, 10),
+			strconv.FormatInt(turn.To, 10),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("write %s: %w", path, err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// removeRestrictions deletes a restrictions.csv left by an earlier conversion
+func removeRestrictions(output string) error {
+	path := filepath.Join(output, routingRestrictionsCSV)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"radar/internal/infra/routing/loader"
+
+	"github.com/paulmach/osm"
+	"github.com/paulmach/osm/osmpbf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Protobuf wire types and OSM relation member types used by the fixture encoder
+const (
+	wireVarint = 0
+	wireBytes  = 2
+
+	pbfMemberNode = 0
+	pbfMemberWay  = 1
+)
+
+// pbfMessage builds a protobuf message field by field
+type pbfMessage []byte
+
+func (m pbfMessage) varint(num int, value uint64) pbfMessage {
+	m = binary.AppendUvarint(m, uint64(num)<<3|wireVarint)
+
+	return binary.AppendUvarint(m, value)
+}
+
+func (m pbfMessage) bytes(num int, data []byte) pbfMessage {
+	m = binary.AppendUvarint(m, uint64(num)<<3|wireBytes)
+	m = binary.AppendUvarint(m, uint64(len(data)))
+
+	return append(m, data...)
+}
+
+func packed(values ...uint64) []byte {
+	var data []byte
+	for _, value := range values {
+		data = binary.AppendUvarint(data, value)
+	}
+
+	return data
+}
+
+func zigzagEncode(value int64) uint64 {
+	return uint64(value<<1) ^ uint64(value>>63)
+}
+
+func packedDeltas(values ...int64) []byte {
+	var data []byte
+	var last int64
+	for _, value := range values {
+		data = binary.AppendUvarint(data, zigzagEncode(value-last))
+		last = value
+	}
+
+	return data
+}
+
+// appendBlob appends a zlib-compressed blob of the given type to file
+func appendBlob(t *testing.T, file []byte, blobType string, data []byte) []byte {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	_, err := zw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	blob := pbfMessage(nil).varint(2, uint64(len(data))).bytes(3, compressed.Bytes())
+	header := pbfMessage(nil).bytes(1, []byte(blobType)).varint(3, uint64(len(blob)))
+
+	file = binary.BigEndian.AppendUint32(file, uint32(len(header)))
+	file = append(file, header...)
+
+	return append(file, blob...)
+}
+
+// writeIntersectionPBF writes a four-way intersection at node 10 with turn restriction relations
+func writeIntersectionPBF(t *testing.T, dir string) string {
+	strings := []string{"", "type", "restriction", "no_left_turn", "only_straight_on", "from", "via", "to"}
+	var stringTable pbfMessage
+	for _, entry := range strings {
+		stringTable = stringTable.bytes(1, []byte(entry))
+	}
+
+	// Coordinates are in units of the default 100 nanodegree granularity
+	dense := pbfMessage(nil).
+		bytes(1, packedDeltas(1, 2, 3, 10)).
+		bytes(8, packedDeltas(249990000, 250000000, 250010000, 250000000)).
+		bytes(9, packedDeltas(1210000000, 1210010000, 1210000000, 1210000000))
+	nodes := pbfMessage(nil).bytes(2, dense)
+
+	way := func(id int64, refs ...int64) []byte {
+		return pbfMessage(nil).varint(1, uint64(id)).bytes(8, packedDeltas(refs...))
+	}
+	ways := pbfMessage(nil).bytes(3, way(100, 1, 10)).bytes(3, way(200, 10, 2)).bytes(3, way(300, 3, 10))
+
+	relation := func(id int64, kind uint64, members []int64, types []uint64) []byte {
+		return pbfMessage(nil).
+			varint(1, uint64(id)).
+			bytes(2, packed(1, 2)).
+			bytes(3, packed(2, kind)).
+			bytes(8, packed(5, 6, 7)).
+			bytes(9, packedDeltas(members...)).
+			bytes(10, packed(types...))
+	}
+	relations := pbfMessage(nil).
+		bytes(4, relation(1, 3, []int64{100, 10, 200}, []uint64{pbfMemberWay, pbfMemberNode, pbfMemberWay})).
+		bytes(4, relation(2, 4, []int64{300, 10, 100}, []uint64{pbfMemberWay, pbfMemberNode, pbfMemberWay})).
+		// A via way is not supported
+		bytes(4, relation(3, 3, []int64{100, 300, 200}, []uint64{pbfMemberWay, pbfMemberWay, pbfMemberWay}))
+
+	block := pbfMessage(nil).bytes(1, stringTable).bytes(2, nodes).bytes(2, ways).bytes(2, relations)
+
+	var file []byte
+	file = appendBlob(t, file, "OSMHeader", pbfMessage(nil).bytes(4, []byte("OsmSchema-V0.6")))
+	file = appendBlob(t, file, "OSMData", block)
+
+	path := filepath.Join(dir, "intersection.osm.pbf")
+	require.NoError(t, os.WriteFile(path, file, 0644))
+
+	return path
+}
+
+func TestExtractRestrictions(t *testing.T) {
+	dir := t.TempDir()
+	input := writeIntersectionPBF(t, dir)
+
+	// The converted graph: vertex 1 is the intersection, connected both ways to 0 (south), 2 (east), 3 (north)
+	files := map[string]string{
+		routingVerticesCSV: `id,lat,lng,order_pos,importance
+0,24.999000,121.000000,0,1
+1,25.000000,121.000000,1,1
+2,25.000000,121.001000,2,1
+3,25.001000,121.000000,3,1
+`,
+		routingEdgesCSV: `from,to,weight
+0,1,111
+1,0,111
+1,2,100
+2,1,100
+1,3,111
+3,1,111
+`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	stats, err := extractRestrictions(context.Background(), input, dir)
+	require.NoError(t, err)
+	assert.Equal(t, restrictionStats{Relations: 2, Skipped: 0, Written: 3}, stats)
+
+	restrictions, err := loader.NewCSVLoader(dir).LoadRestrictions()
+	require.NoError(t, err)
+	assert.Equal(t, []loader.Restriction{
+		{From: 0, Via: 1, To: 2}, // no_left_turn from the south onto the east road
+		{From: 3, Via: 1, To: 2}, // only_straight_on from the north allows only the south road
+		{From: 3, Via: 1, To: 3},
+	}, restrictions)
+}
+
+func TestScanOSMPBF_RejectsTruncatedFile(t *testing.T) {
+	dir := t.TempDir()
+	input := writeIntersectionPBF(t, dir)
+	data, err := os.ReadFile(input)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(input, data[:len(data)-10], 0644))
+
+	err = scanOSMPBF(context.Background(), input, func(*osmpbf.Scanner) {}, func(osm.Object) {})
+	require.Error(t, err)
+}
@@ -8,13 +8,17 @@ import (
 	"os"
 	"path/filepath"
 
+	"radar/internal/infra/routing/loader"
 	"radar/internal/util"
 )
 
-func runValidate(dir string) error {
+// defaultMaxDisconnectedPercent is the share of vertices allowed outside the largest connected component
+const defaultMaxDisconnectedPercent = 5.0
+
+func runValidate(dir string, maxDisconnectedPercent float64) error {
 	fmt.Printf("Validating routing data in directory: %s\n", dir)
 
-	if err := validateRoutingData(dir); err != nil {
+	if err := validateRoutingData(dir, maxDisconnectedPercent); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
@@ -23,7 +27,7 @@ func runValidate(dir string) error {
 	return nil
 }
 
-func validateRoutingData(dir string) error {
+func validateRoutingData(dir string, maxDisconnectedPercent float64) error {
 	// Check if directory exists
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
 		return fmt.Errorf("directory does not exist: %s: %w", dir, err)
@@ -48,7 +52,8 @@ func validateRoutingData(dir string) error {
 	// Check data consistency
 	logConsistencyStats(metadata)
 
-	return nil
+	// Check that the road network is not split into large islands
+	return validateConnectivity(dir, maxDisconnectedPercent)
 }
 
 func loadAndValidateMetadata(dir string) (*RoutingMetadata, error) {
@@ -156,6 +161,98 @@ func logConsistencyStats(metadata *RoutingMetadata) {
 	}
 }
 
+// componentStats summarizes the connected components of a routing graph
+type componentStats struct {
+	Vertices   int
+	Components int
+	Largest    int
+}
+
+// DisconnectedPercent returns the share of vertices outside the largest component
+func (s componentStats) DisconnectedPercent() float64 {
+	if s.Vertices == 0 {
+		return 0
+	}
+
+	return float64(s.Vertices-s.Largest) / float64(s.Vertices) * 100
+}
+
+func validateConnectivity(dir string, maxDisconnectedPercent float64) error {
+	fmt.Println("\nChecking graph connectivity...")
+
+	data, err := loader.NewCSVLoader(dir).Load()
+	if err != nil {
+		return fmt.Errorf("failed to load graph data: %w", err)
+	}
+
+	stats := analyzeComponents(data)
+	fmt.Printf("  Components: %d\n", stats.Components)
+	fmt.Printf("  Largest component: %d of %d vertices\n", stats.Largest, stats.Vertices)
+	fmt.Printf("  Outside largest component: %.2f%%\n", stats.DisconnectedPercent())
+
+	if err := checkConnectivity(stats, maxDisconnectedPercent); err != nil {
+		fmt.Printf("  ❌ %v\n", err)
+
+		return err
+	}
+
+	fmt.Printf("  ✅ Connectivity within %.2f%% threshold\n", maxDisconnectedPercent)
+
+	return nil
+}
+
+// analyzeComponents groups vertices into weakly connected components, treating every edge as
+// two-way so one-way streets do not split a connected neighborhood. Shortcuts are derived from
+// edges and do not change connectivity, so they are ignored.
+func analyzeComponents(data *loader.GraphData) componentStats {
+	vertexCount := len(data.Vertices)
+	parent := make([]int, vertexCount)
+	for idx := range parent {
+		parent[idx] = idx
+	}
+
+	var find func(int) int
+	find = func(v int) int {
+		for parent[v] != v {
+			parent[v] = parent[parent[v]]
+			v = parent[v]
+		}
+
+		return v
+	}
+
+	for _, edge := range data.Edges {
+		from, to := int(edge.From), int(edge.To)
+		if from < 0 || from >= vertexCount || to < 0 || to >= vertexCount {
+			continue
+		}
+		if rootFrom, rootTo := find(from), find(to); rootFrom != rootTo {
+			parent[rootFrom] = rootTo
+		}
+	}
+
+	sizes := make(map[int]int)
+	stats := componentStats{Vertices: vertexCount}
+	for v := range vertexCount {
+		root := find(v)
+		sizes[root]++
+		stats.Largest = max(stats.Largest, sizes[root])
+	}
+	stats.Components = len(sizes)
+
+	return stats
+}
+
+// checkConnectivity fails when more than maxDisconnectedPercent of the vertices are outside the largest component
+func checkConnectivity(stats componentStats, maxDisconnectedPercent float64) error {
+	if disconnected := stats.DisconnectedPercent(); disconnected > maxDisconnectedPercent {
+		return fmt.Errorf("%.2f%% of vertices are disconnected from the largest component (max %.2f%%)",
+			disconnected, maxDisconnectedPercent)
+	}
+
+	return nil
+}
+
 // validateVerticesCSV performs basic validation of vertices.csv
 func validateVerticesCSV(filePath string) error {
 	return validateCSVHasColumns(filePath, []string{"id", "lat", "lng"})
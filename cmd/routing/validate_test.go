@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"radar/internal/infra/routing/loader"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeComponents(t *testing.T) {
+	data := &loader.GraphData{
+		Vertices: make([]loader.Vertex, 6),
+		Edges: []loader.Edge{
+			{From: 0, To: 1},
+			{From: 2, To: 1}, // one-way edges still join a component
+			{From: 3, To: 4},
+			{From: 4, To: 9}, // out-of-range vertex is ignored
+		},
+	}
+
+	stats := analyzeComponents(data)
+
+	assert.Equal(t, componentStats{Vertices: 6, Components: 3, Largest: 3}, stats)
+	assert.InDelta(t, 50.0, stats.DisconnectedPercent(), 1e-9)
+}
+
+func TestAnalyzeComponents_Empty(t *testing.T) {
+	stats := analyzeComponents(&loader.GraphData{})
+
+	assert.Equal(t, componentStats{}, stats)
+	assert.Zero(t, stats.DisconnectedPercent())
+}
+
+func TestCheckConnectivity(t *testing.T) {
+	stats := componentStats{Vertices: 100, Components: 2, Largest: 96}
+
+	require.NoError(t, checkConnectivity(stats, 5))
+	require.NoError(t, checkConnectivity(stats, 4))
+
+	err := checkConnectivity(stats, 3)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "4.00% of vertices are disconnected")
+}
@@ -1,7 +1,10 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -19,15 +22,46 @@ import (
 )
 
 const (
-	defaultPath                 = "."
-	defaultMaxRequestBodySize   = "100KB"
-	postgresMasterDSNEnvKey     = "POSTGRES_MASTER_DSN"
-	defaultAccessTokenTTL       = 15 * time.Minute
-	defaultRefreshTokenTTL      = 7 * 24 * time.Hour
-	defaultOnboardingTokenTTL   = 10 * time.Minute
-	defaultLinkingTokenTTL      = 10 * time.Minute
-	defaultNotificationTimeout  = 10 * time.Second
-	defaultDeviceCleanupTimeout = 5 * time.Minute
+	defaultPath                                 = "."
+	defaultMaxRequestBodySize                   = "100KB"
+	postgresMasterDSNEnvKey                     = "POSTGRES_MASTER_DSN"
+	defaultAccessTokenTTL                       = 15 * time.Minute
+	defaultRefreshTokenTTL                      = 7 * 24 * time.Hour
+	defaultOnboardingTokenTTL                   = 10 * time.Minute
+	defaultLinkingTokenTTL                      = 10 * time.Minute
+	defaultNotificationTimeout                  = 10 * time.Second
+	defaultNotificationRetryBackoff             = 200 * time.Millisecond
+	defaultNotificationBreakerFailureRate       = 0.5
+	defaultNotificationBreakerWindowSize        = 20
+	defaultNotificationBreakerMinRequests       = 10
+	defaultNotificationBreakerOpenDuration      = 30 * time.Second
+	defaultNotificationBroadcastTTL             = 30 * time.Minute
+	defaultNotificationMaxConcurrentSends       = 4
+	defaultNotificationInvalidTokenStrikes      = 1
+	defaultNotificationInvalidTokenStrikeWindow = 72 * time.Hour
+	defaultCoordinatePrecision                  = 5
+	defaultDeviceCleanupTimeout                 = 5 * time.Minute
+	defaultNotificationLogRetentionDays         = 90
+	defaultStaleDeviceDeliveryDays              = 60
+	defaultPMTilesRoadLayer                     = "transportation"
+	defaultPMTilesZoomLevel                     = 14
+	defaultPMTilesCacheSize                     = 64
+	defaultPMTilesMinEdgeDistanceMeters         = 1.0
+	defaultPMTilesMaxTileSpan                   = 32
+	defaultPMTilesCorridorBufferTiles           = 1
+	defaultPMTilesMaxSnapDistanceMeters         = 500
+	maxPMTilesZoomLevel                         = 22
+	defaultCHMaxSnapDistanceMeters              = 500
+	defaultStraightLineRadiusFactor             = 1.0
+	defaultRoutingSpeedKmh                      = 30.0
+	defaultRoutingFallbackTimeout               = 2 * time.Second
+)
+
+// Routing backends selectable through routing.backend
+const (
+	RoutingBackendPMTiles   = "pmtiles"
+	RoutingBackendCH        = "ch"
+	RoutingBackendHaversine = "haversine"
 )
 
 type Config struct {
@@ -48,6 +82,12 @@ type Config struct {
 			ReadHeaderTimeout time.Duration `json:"readHeaderTimeout" yaml:"readHeaderTimeout"`
 			WriteTimeout      time.Duration `json:"writeTimeout" yaml:"writeTimeout"`
 			IdleTimeout       time.Duration `json:"idleTimeout" yaml:"idleTimeout"`
+
+			// Context deadline for API handlers without a route override; 0 leaves them bounded only by WriteTimeout
+			Handler time.Duration `json:"handler" yaml:"handler"`
+
+			// Handler deadlines for individual routes, overriding Handler
+			Routes []RouteTimeout `json:"routes" yaml:"routes"`
 		} `json:"timeouts" yaml:"timeouts"`
 	} `json:"http" yaml:"http"`
 
@@ -58,6 +98,12 @@ type Config struct {
 		Refresh    string `json:"refresh" yaml:"refresh"`
 		Onboarding string `json:"onboarding" yaml:"onboarding"`
 		Linking    string `json:"linking" yaml:"linking"`
+
+		// Signing key set for rotation. New tokens are signed with CurrentKeyID (the first key when empty)
+		// and carry it as their kid; tokens validate against whichever listed key their kid names. When keys
+		// are set, the secrets above are optional and, if present, still validate tokens issued without a kid.
+		CurrentKeyID string             `json:"currentKeyId" yaml:"currentKeyId"`
+		Keys         []SigningKeyConfig `json:"keys" yaml:"keys"`
 	} `json:"secretKey" yaml:"secretKey"`
 
 	GoogleOAuth *GoogleOAuthConfig `json:"googleOAuth" yaml:"googleOAuth"`
@@ -66,6 +112,9 @@ type Config struct {
 
 	LoginThrottle *LoginThrottleConfig `json:"loginThrottle" yaml:"loginThrottle"`
 
+	// RateLimit configuration for per-user limits on authenticated route groups
+	RateLimit *RateLimitConfig `json:"rateLimit" yaml:"rateLimit"`
+
 	PasswordStrength *PasswordStrengthConfig `json:"passwordStrength" yaml:"passwordStrength"`
 
 	// TestRoutes configuration for testing endpoints
@@ -89,8 +138,24 @@ type Config struct {
 	// PMTiles configuration for serverless routing
 	PMTiles *PMTilesConfig `json:"pmtiles" yaml:"pmtiles"`
 
+	// Routing configuration selecting the routing backend
+	Routing *RoutingConfig `json:"routing" yaml:"routing"`
+
 	// DeviceCleanup configuration for stale device cleanup job
 	DeviceCleanup *DeviceCleanupConfig `json:"deviceCleanup" yaml:"deviceCleanup"`
+
+	// Tracing configuration for exporting worker spans
+	Tracing *TracingConfig `json:"tracing" yaml:"tracing"`
+}
+
+// SigningKeyConfig is one key of the token signing key set.
+// Empty onboarding and linking secrets are derived from the key's access secret.
+type SigningKeyConfig struct {
+	ID         string `json:"id" yaml:"id"`
+	Access     string `json:"access" yaml:"access"`
+	Refresh    string `json:"refresh" yaml:"refresh"`
+	Onboarding string `json:"onboarding" yaml:"onboarding"`
+	Linking    string `json:"linking" yaml:"linking"`
 }
 
 type GoogleOAuthConfig struct {
@@ -110,6 +175,8 @@ type AuthConfig struct {
 	RefreshTokenTTL     time.Duration `json:"refreshTokenTTL" yaml:"refreshTokenTTL"`
 	OnboardingTokenTTL  time.Duration `json:"onboardingTokenTTL" yaml:"onboardingTokenTTL"`
 	LinkingTokenTTL     time.Duration `json:"linkingTokenTTL" yaml:"linkingTokenTTL"`
+	// User IDs granted the admin role when tokens are issued
+	AdminUserIDs []string `json:"adminUserIds" yaml:"adminUserIds"`
 }
 
 // LoginThrottleConfig defines progressive login throttling configuration.
@@ -126,6 +193,34 @@ func DefaultLoginThrottleConfig() LoginThrottleConfig {
 	}
 }
 
+// RouteTimeout sets the handler context deadline for one registered route.
+type RouteTimeout struct {
+	// HTTP method to match; empty matches every method
+	Method string `json:"method" yaml:"method"`
+
+	// Route pattern as registered, e.g. /api/v1/subscriptions/:merchantId
+	Path string `json:"path" yaml:"path"`
+
+	// Deadline for the handler context; 0 disables the deadline for this route
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+}
+
+// RateLimitConfig defines per-user request limits for authenticated route groups.
+type RateLimitConfig struct {
+	Notifications RateLimitRule `json:"notifications" yaml:"notifications"`
+	Subscriptions RateLimitRule `json:"subscriptions" yaml:"subscriptions"`
+	Locations     RateLimitRule `json:"locations" yaml:"locations"`
+}
+
+// RateLimitRule defines a token bucket applied to each authenticated user.
+type RateLimitRule struct {
+	// Sustained requests per minute allowed for one user (0 disables the limit)
+	RequestsPerMinute int `json:"requestsPerMinute" yaml:"requestsPerMinute"`
+
+	// Requests a user may make back to back before the sustained rate applies; defaults to RequestsPerMinute
+	Burst int `json:"burst" yaml:"burst"`
+}
+
 // PasswordStrengthConfig defines password strength requirements
 type PasswordStrengthConfig struct {
 	MinLength        int  `json:"minLength" yaml:"minLength"`
@@ -152,11 +247,95 @@ type LocationNotificationConfig struct {
 	MerchantMaxLocations int     `json:"merchantMaxLocations" yaml:"merchantMaxLocations"`
 	DefaultRadius        float64 `json:"defaultRadius" yaml:"defaultRadius"`
 	MaxRadius            float64 `json:"maxRadius" yaml:"maxRadius"`
+
+	// Decimal places kept when persisting address coordinates (5 is roughly 1m); unset or negative uses 5, and 0 keeps whole degrees
+	CoordinatePrecision *int `json:"coordinatePrecision" yaml:"coordinatePrecision"`
 }
 
 // NotificationConfig defines notification behavior configuration.
 type NotificationConfig struct {
 	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+
+	// Retries for transient push provider errors, on top of the first attempt
+	MaxRetries int `json:"maxRetries" yaml:"maxRetries"`
+
+	// Base delay between retries; doubled on each subsequent attempt
+	RetryBackoff time.Duration `json:"retryBackoff" yaml:"retryBackoff"`
+
+	// Failure ratio (0-1] over the recent call window that opens the circuit breaker
+	BreakerFailureRate float64 `json:"breakerFailureRate" yaml:"breakerFailureRate"`
+
+	// Number of recent calls sampled for the failure ratio
+	BreakerWindowSize int `json:"breakerWindowSize" yaml:"breakerWindowSize"`
+
+	// Minimum calls in the window before the breaker may open
+	BreakerMinRequests int `json:"breakerMinRequests" yaml:"breakerMinRequests"`
+
+	// How long the breaker stays open before allowing a recovery probe
+	BreakerOpenDuration time.Duration `json:"breakerOpenDuration" yaml:"breakerOpenDuration"`
+
+	// Store deep link template, e.g. "nomnom://merchants/{merchant_id}" (empty disables deep links)
+	DeepLinkTemplate string `json:"deepLinkTemplate" yaml:"deepLinkTemplate"`
+
+	// Click action / notification category delivered alongside the deep link
+	ClickAction string `json:"clickAction" yaml:"clickAction"`
+
+	// URL schemes a deep link may use
+	AllowedDeepLinkSchemes []string `json:"allowedDeepLinkSchemes" yaml:"allowedDeepLinkSchemes"`
+
+	// How long a queued broadcast stays deliverable before the worker drops it
+	BroadcastTTL time.Duration `json:"broadcastTTL" yaml:"broadcastTTL"`
+
+	// Minimum time between a merchant's immediate broadcasts from the same location (0 disables the cooldown)
+	BroadcastCooldown time.Duration `json:"broadcastCooldown" yaml:"broadcastCooldown"`
+
+	// Consecutive invalid-token responses before a device is deleted (1 deletes on the first response)
+	InvalidTokenStrikes int `json:"invalidTokenStrikes" yaml:"invalidTokenStrikes"`
+
+	// Window from a device's first invalid-token strike within which further strikes count toward deletion
+	InvalidTokenStrikeWindow time.Duration `json:"invalidTokenStrikeWindow" yaml:"invalidTokenStrikeWindow"`
+
+	// Maximum provider batches sent in parallel by the inline (synchronous) publish path
+	MaxConcurrentBatches int `json:"maxConcurrentBatches" yaml:"maxConcurrentBatches"`
+
+	// Apply the road reachability filter before publishing so the worker can skip its recheck
+	PrefilterReachability bool `json:"prefilterReachability" yaml:"prefilterReachability"`
+
+	// How often routes from merchants' default locations to their subscribers are re-warmed (0 disables the warmer)
+	RouteCacheWarmInterval time.Duration `json:"routeCacheWarmInterval" yaml:"routeCacheWarmInterval"`
+
+	// Zoom of the map tiles that batched subscriber lookups are grouped by; sources with subscribers in the same
+	// tile are routed on one shared graph. Defaults to the PMTiles zoom so a bucket matches one routing tile.
+	SubscriberTileZoom int `json:"subscriberTileZoom" yaml:"subscriberTileZoom"`
+
+	// Device platforms notifications are delivered to: ios, android, web (empty delivers to every platform)
+	TargetPlatforms []string `json:"targetPlatforms" yaml:"targetPlatforms"`
+
+	// Minimum client app version that receives notifications; devices below it or without a reported version are skipped
+	MinAppVersion string `json:"minAppVersion" yaml:"minAppVersion"`
+
+	// Skip subscribers that also have a merchant account; by default they receive broadcasts like any subscriber
+	ExcludeMerchantSubscribers bool `json:"excludeMerchantSubscribers" yaml:"excludeMerchantSubscribers"`
+
+	// Canary mode delivers broadcasts only to a stable cohort of subscribers; the rest are recorded as canary-suppressed
+	Canary CanaryConfig `json:"canary" yaml:"canary"`
+
+	// Most subscribers one broadcast is delivered to after reachability filtering (0 sets no cap)
+	MaxRecipientsPerBroadcast int `json:"maxRecipientsPerBroadcast" yaml:"maxRecipientsPerBroadcast"`
+
+	// Reject broadcasts over the cap instead of delivering to the nearest MaxRecipientsPerBroadcast subscribers
+	StrictRecipientCap bool `json:"strictRecipientCap" yaml:"strictRecipientCap"`
+}
+
+// CanaryConfig selects the canary cohort of notification subscribers
+type CanaryConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Share of subscribers in the cohort, in [0, 1]; users are hashed by ID so the same ones are chosen every time
+	Fraction float64 `json:"fraction" yaml:"fraction"`
+
+	// User IDs that are always in the cohort
+	UserIDs []string `json:"userIds" yaml:"userIds"`
 }
 
 // FirebaseConfig defines Firebase configuration for push notifications
@@ -183,6 +362,28 @@ type PubSubConfig struct {
 
 	// Local HTTP endpoint for development (for local provider)
 	LocalEndpoint string `json:"localEndpoint" yaml:"localEndpoint"`
+
+	// Maximum push messages the worker processes at once; extra deliveries are
+	// rejected with 429 so Pub/Sub backs off (0 disables the limit)
+	MaxConcurrentPushes int `json:"maxConcurrentPushes" yaml:"maxConcurrentPushes"`
+
+	// Time budget for processing one push message; keep it below the subscription's ack
+	// deadline so slow routing returns a retryable 503 instead of a redelivery (0 disables)
+	ProcessingBudget time.Duration `json:"processingBudget" yaml:"processingBudget"`
+
+	// Resends of transiently failed FCM tokens one push message may spend across its batches before
+	// it is returned for redelivery (0 disables in-worker retries)
+	SendRetryBudget int `json:"sendRetryBudget" yaml:"sendRetryBudget"`
+
+	// Delay before the first resend, doubled for each later one and jittered (0 uses the default of 200ms)
+	SendRetryBackoff time.Duration `json:"sendRetryBackoff" yaml:"sendRetryBackoff"`
+
+	// Longest delay before one resend; the doubling stops here before jitter is applied (0 uses the default of 5s)
+	SendRetryMaxBackoff time.Duration `json:"sendRetryMaxBackoff" yaml:"sendRetryMaxBackoff"`
+
+	// How long a processed message ID is remembered so a redelivery of it is acknowledged without
+	// processing (0 uses the default of 1h)
+	MessageDedupTTL time.Duration `json:"messageDedupTTL" yaml:"messageDedupTTL"`
 }
 
 // PMTilesConfig defines PMTiles routing configuration for notification runtime routing.
@@ -190,19 +391,430 @@ type PMTilesConfig struct {
 	// Enable PMTiles-based routing
 	Enabled bool `json:"enabled" yaml:"enabled"`
 
-	// PMTiles source URL (local file path, HTTP URL, or GCS URL)
+	// PMTiles source URL (local file path, HTTP URL, or gs://, s3://, or azblob:// bucket URL)
 	Source string `json:"source" yaml:"source"`
 
+	// Tilesets per routing profile, such as driving, walking, and cycling; when set, source is ignored
+	// and the first profile is the default one
+	Profiles []PMTilesProfileConfig `json:"profiles" yaml:"profiles"`
+
 	// Road layer name in the MVT tiles
 	RoadLayer string `json:"roadLayer" yaml:"roadLayer"`
 
 	// Zoom level for tile queries
 	ZoomLevel int `json:"zoomLevel" yaml:"zoomLevel"`
+
+	// Approximate memory budget in bytes for a merged routing graph (0 disables the check)
+	MaxGraphMemoryBytes int64 `json:"maxGraphMemoryBytes" yaml:"maxGraphMemoryBytes"`
+
+	// Mark targets without a road route as unreachable instead of estimating a Haversine distance
+	DisableHaversineFallback bool `json:"disableHaversineFallback" yaml:"disableHaversineFallback"`
+
+	// Include the nearest edge and projected point in nearest-node lookups
+	IncludeEdgeSnap bool `json:"includeEdgeSnap" yaml:"includeEdgeSnap"`
+
+	// Minimum edge length in meters; shorter edges are clamped up. Unset uses the default of 1m, and 0 disables clamping
+	MinEdgeDistanceMeters *float64 `json:"minEdgeDistanceMeters" yaml:"minEdgeDistanceMeters"`
+
+	// Maximum distance in meters a coordinate may be from a road to be snapped onto it; a farther source falls back
+	// to Haversine and a farther target is estimated (0 uses the default of 500m)
+	MaxSnapDistanceMeters float64 `json:"maxSnapDistanceMeters" yaml:"maxSnapDistanceMeters"`
+
+	// Maximum tiles per axis a routing area may span; larger areas use the Haversine fallback (0 uses the default of 32)
+	MaxTileSpan int `json:"maxTileSpan" yaml:"maxTileSpan"`
+
+	// Load only the tiles along each source-target line instead of their whole bounding box; the corridor may hold up to maxTileSpan² tiles
+	CorridorTiles bool `json:"corridorTiles" yaml:"corridorTiles"`
+
+	// Tiles on each side of a source-target line a corridor covers; unset uses the default of 1, and 0 covers only the tiles the line crosses
+	CorridorBufferTiles *int `json:"corridorBufferTiles" yaml:"corridorBufferTiles"`
+
+	// Leave roads tagged access=private or access=no out of the routing graph
+	ExcludeRestrictedAccess bool `json:"excludeRestrictedAccess" yaml:"excludeRestrictedAccess"`
+
+	// Number of raw tiles the PMTiles server keeps in memory (0 uses the default of 64)
+	CacheSize int `json:"cacheSize" yaml:"cacheSize"`
+
+	// Store graph node coordinates as float32 to reduce memory; positions lose sub-meter precision
+	CompactNodeCoordinates bool `json:"compactNodeCoordinates" yaml:"compactNodeCoordinates"`
+
+	// When a tile is missing at zoomLevel and the archive's max zoom is lower, load its ancestor at the max zoom instead
+	ZoomFallback bool `json:"zoomFallback" yaml:"zoomFallback"`
+
+	// How long cached tile graphs are used before the source version is rechecked; a changed version clears them (0 never rechecks)
+	TileCacheMaxAge time.Duration `json:"tileCacheMaxAge" yaml:"tileCacheMaxAge"`
+
+	// Composite edge cost weights; all zero keeps shortest-distance routing
+	RoutingCost PMTilesRoutingCostConfig `json:"routingCost" yaml:"routingCost"`
+}
+
+// PMTilesProfileConfig names the tileset a routing profile is served from
+type PMTilesProfileConfig struct {
+	// Profile name callers pass to route on this tileset
+	Name string `json:"name" yaml:"name"`
+
+	// PMTiles source URL, in any form pmtiles.source accepts
+	Source string `json:"source" yaml:"source"`
+
+	// CH profile that large queries on this tileset are delegated to with routing.largeGraphEdgeThreshold.
+	// Empty uses the CH profile of the same name when the engine has one, and the engine's default otherwise.
+	LargeGraphProfile string `json:"largeGraphProfile" yaml:"largeGraphProfile"`
+}
+
+// PMTilesRoutingCostConfig weighs travel time, turns, and road class into one edge cost.
+// The cost of an edge is duration × (durationWeight + roadClassWeight × class penalty), plus
+// turnPenaltySeconds for every sharp turn, where the class penalty runs from 0 on primary roads to 1 on residential ones.
+type PMTilesRoutingCostConfig struct {
+	// Cost per second of travel time
+	DurationWeight float64 `json:"durationWeight" yaml:"durationWeight"`
+
+	// Cost added for each turn sharper than 45 degrees
+	TurnPenaltySeconds float64 `json:"turnPenaltySeconds" yaml:"turnPenaltySeconds"`
+
+	// Extra cost per second of travel on lower-class roads, scaled by the class penalty
+	RoadClassWeight float64 `json:"roadClassWeight" yaml:"roadClassWeight"`
+}
+
+// Enabled reports whether any weight is set, switching the pathfinder to the composite cost.
+func (c PMTilesRoutingCostConfig) Enabled() bool {
+	return c.DurationWeight > 0 || c.TurnPenaltySeconds > 0 || c.RoadClassWeight > 0
+}
+
+// WithDefaults returns a copy of the PMTiles config with unset values replaced by their defaults.
+// A nil receiver yields a disabled config with defaults applied.
+func (c *PMTilesConfig) WithDefaults() PMTilesConfig {
+	var out PMTilesConfig
+	if c != nil {
+		out = *c
+	}
+
+	if strings.TrimSpace(out.RoadLayer) == "" {
+		out.RoadLayer = defaultPMTilesRoadLayer
+	}
+	if out.ZoomLevel == 0 {
+		out.ZoomLevel = defaultPMTilesZoomLevel
+	}
+	if out.CacheSize <= 0 {
+		out.CacheSize = defaultPMTilesCacheSize
+	}
+	if out.MinEdgeDistanceMeters == nil {
+		out.MinEdgeDistanceMeters = new(defaultPMTilesMinEdgeDistanceMeters)
+	}
+	if out.MaxSnapDistanceMeters <= 0 {
+		out.MaxSnapDistanceMeters = defaultPMTilesMaxSnapDistanceMeters
+	}
+	if out.MaxTileSpan <= 0 {
+		out.MaxTileSpan = defaultPMTilesMaxTileSpan
+	}
+	if out.CorridorBufferTiles == nil {
+		out.CorridorBufferTiles = new(defaultPMTilesCorridorBufferTiles)
+	}
+	// A negative budget means the same as zero: the check is disabled
+	out.MaxGraphMemoryBytes = max(out.MaxGraphMemoryBytes, 0)
+	out.TileCacheMaxAge = max(out.TileCacheMaxAge, 0)
+
+	return out
+}
+
+// Validate reports a misconfiguration of an enabled PMTiles config. Call it on the result of WithDefaults.
+func (c PMTilesConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	errs := c.validateSources()
+	if c.ZoomLevel < 1 || c.ZoomLevel > maxPMTilesZoomLevel {
+		errs = append(errs, fmt.Errorf("pmtiles.zoomLevel must be between 1 and %d, got %d", maxPMTilesZoomLevel, c.ZoomLevel))
+	}
+	if c.MinEdgeDistanceMeters != nil && *c.MinEdgeDistanceMeters < 0 {
+		errs = append(errs, fmt.Errorf("pmtiles.minEdgeDistanceMeters must not be negative, got %g", *c.MinEdgeDistanceMeters))
+	}
+	if c.CorridorBufferTiles != nil && *c.CorridorBufferTiles < 0 {
+		errs = append(errs, fmt.Errorf("pmtiles.corridorBufferTiles must not be negative, got %d", *c.CorridorBufferTiles))
+	}
+	if c.RoutingCost.DurationWeight < 0 || c.RoutingCost.TurnPenaltySeconds < 0 || c.RoutingCost.RoadClassWeight < 0 {
+		errs = append(errs, errors.New("pmtiles.routingCost weights must not be negative"))
+	}
+	if c.RoutingCost.Enabled() && c.RoutingCost.DurationWeight <= 0 {
+		errs = append(errs, errors.New("pmtiles.routingCost.durationWeight is required when other routing cost weights are set"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateSources checks the single source, or each profile's name and source when profiles are set
+func (c PMTilesConfig) validateSources() []error {
+	if len(c.Profiles) == 0 {
+		if strings.TrimSpace(c.Source) == "" {
+			return []error{errors.New("pmtiles.source is required when enabled")}
+		}
+		if err := validatePMTilesSourceCredentials(c.Source); err != nil {
+			return []error{err}
+		}
+
+		return nil
+	}
+
+	var errs []error
+	seen := make(map[string]bool, len(c.Profiles))
+	for idx, profile := range c.Profiles {
+		name := strings.TrimSpace(profile.Name)
+		switch {
+		case name == "":
+			errs = append(errs, fmt.Errorf("pmtiles.profiles[%d].name is required", idx))
+		case seen[name]:
+			errs = append(errs, fmt.Errorf("pmtiles.profiles has duplicate profile %q", name))
+		}
+		seen[name] = true
+
+		if strings.TrimSpace(profile.Source) == "" {
+			errs = append(errs, fmt.Errorf("pmtiles.profiles[%d].source is required", idx))
+
+			continue
+		}
+		if err := validatePMTilesSourceCredentials(profile.Source); err != nil {
+			errs = append(errs, fmt.Errorf("pmtiles.profiles[%d]: %w", idx, err))
+		}
+	}
+
+	return errs
+}
+
+// validatePMTilesSourceCredentials checks that an s3:// or azblob:// source has the settings its blob driver
+// reads at open time, so a misconfigured bucket fails config validation instead of the first tile read.
+// Credentials themselves are resolved by the driver; only their presence is checked here.
+func validatePMTilesSourceCredentials(source string) error {
+	scheme, rest, found := strings.Cut(source, "://")
+	if !found {
+		return nil
+	}
+	_, rawQuery, _ := strings.Cut(rest, "?")
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return fmt.Errorf("pmtiles.source has an invalid query string: %w", err)
+	}
+
+	switch scheme {
+	case "s3":
+		// The AWS SDK reads static keys from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY, falling back to the
+		// shared config, web identity, or instance role. The region comes from ?region=, AWS_REGION,
+		// AWS_DEFAULT_REGION, or the profile selected by ?profile= or AWS_PROFILE in AWS_CONFIG_FILE.
+		if (os.Getenv("AWS_ACCESS_KEY_ID") == "") != (os.Getenv("AWS_SECRET_ACCESS_KEY") == "") {
+			return errors.New("pmtiles.source s3:// requires AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY to be set together")
+		}
+		if params.Get("region") == "" && params.Get("profile") == "" && !anyEnvSet(
+			"AWS_REGION", "AWS_DEFAULT_REGION", "AWS_PROFILE", "AWS_CONFIG_FILE",
+		) {
+			return errors.New("pmtiles.source s3:// requires a region: add ?region= or set AWS_REGION or AWS_PROFILE")
+		}
+	case "azblob":
+		// The Azure driver reads the account from ?storage_account=, AZURE_STORAGE_ACCOUNT, or a connection string
+		// in AZURE_STORAGE_CONNECTION_STRING, and authenticates with AZURE_STORAGE_KEY, AZURE_STORAGE_SAS_TOKEN,
+		// the connection string, or the default Azure credential chain (AZURE_CLIENT_ID and friends, managed identity).
+		if params.Get("storage_account") == "" && !anyEnvSet(
+			"AZURE_STORAGE_ACCOUNT", "AZURE_STORAGE_CONNECTION_STRING", "AZURE_STORAGEBLOB_CONNECTIONSTRING",
+		) {
+			return errors.New("pmtiles.source azblob:// requires a storage account: add ?storage_account= or set AZURE_STORAGE_ACCOUNT")
+		}
+	}
+
+	return nil
+}
+
+// anyEnvSet reports whether any of the environment variables has a non-empty value
+func anyEnvSet(keys ...string) bool {
+	for _, key := range keys {
+		if os.Getenv(key) != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RoutingConfig selects the backend behind the routing usecase.
+type RoutingConfig struct {
+	// Routing backend: pmtiles, ch, or haversine (empty uses pmtiles)
+	Backend string `json:"backend" yaml:"backend"`
+
+	// Multiplier applied to a subscriber's notification radius when the distance is a straight-line estimate,
+	// as with the haversine backend, disabled PMTiles routing, or a target without a road route.
+	// 1 (the default) treats the radius as straight-line; below 1 narrows it to allow for road detours.
+	StraightLineRadiusFactor float64 `json:"straightLineRadiusFactor" yaml:"straightLineRadiusFactor"`
+
+	// Routing profile subscriber reachability is measured on, such as walking; empty uses the backend's
+	// default profile
+	NotificationProfile string `json:"notificationProfile" yaml:"notificationProfile"`
+
+	// With the pmtiles backend, queries whose merged tile graph passes this many edges are routed by the CH engine
+	// loaded from routing.ch instead, as are areas too large for PMTiles to build (0 disables)
+	LargeGraphEdgeThreshold int `json:"largeGraphEdgeThreshold" yaml:"largeGraphEdgeThreshold"`
+
+	// Ordered backends to try for each query, replacing backend when set. Targets a backend leaves without
+	// a road route (an error, a timeout, an unreachable target, or only a straight-line estimate) are retried
+	// on the next; the last backend's answer is kept when none routes them
+	FallbackChain []string `json:"fallbackChain" yaml:"fallbackChain"`
+
+	// Time limit for one fallback chain attempt, keyed by backend name (an unset backend uses 2s)
+	FallbackTimeouts map[string]time.Duration `json:"fallbackTimeouts" yaml:"fallbackTimeouts"`
+
+	// Speed in km/h that turns straight-line estimates into ETAs (0 uses 30); set it to a walking speed
+	// for walking-profile deployments
+	DefaultSpeedKmh float64 `json:"defaultSpeedKmh" yaml:"defaultSpeedKmh"`
+
+	// CH configuration, used when the backend is ch or for large pmtiles queries
+	CH CHRoutingConfig `json:"ch" yaml:"ch"`
+}
+
+// CHRoutingConfig defines the contraction hierarchies engine configuration.
+type CHRoutingConfig struct {
+	// Directory holding the prepared vertices, edges, shortcuts, and metadata files
+	DataDir string `json:"dataDir" yaml:"dataDir"`
+
+	// Maximum distance in meters to snap a coordinate to the road network (0 uses the default of 500m)
+	MaxSnapDistanceMeters float64 `json:"maxSnapDistanceMeters" yaml:"maxSnapDistanceMeters"`
+
+	// Memory-map graph.bin (written by `routing-cli pack`) instead of parsing the CSV files; for large regions
+	MemoryMap bool `json:"memoryMap" yaml:"memoryMap"`
+
+	// Honor restrictions.csv from `routing-cli convert`. Restricted data cannot use the CH query, so
+	// every route is then searched with the slower turn-aware Dijkstra; off ignores the file.
+	TurnRestrictions bool `json:"turnRestrictions" yaml:"turnRestrictions"`
+
+	// Run a few representative queries after loading so hot graph data is paged in before serving
+	Warmup bool `json:"warmup" yaml:"warmup"`
+
+	// Most concurrent searches per one-to-many query (0 uses the engine default of 20)
+	OneToManyWorkers int `json:"oneToManyWorkers" yaml:"oneToManyWorkers"`
+
+	// Most one-to-many searches per available CPU, which keeps large batches from oversubscribing
+	// small machines (0 uses the engine default of 2)
+	WorkersPerCPU int `json:"workersPerCPU" yaml:"workersPerCPU"`
+}
+
+// WithDefaults returns a copy of the routing config with unset values replaced by their defaults.
+// A nil receiver yields the pmtiles backend, matching deployments without a routing section.
+func (c *RoutingConfig) WithDefaults() RoutingConfig {
+	var out RoutingConfig
+	if c != nil {
+		out = *c
+	}
+
+	out.Backend = strings.ToLower(strings.TrimSpace(out.Backend))
+	out.NotificationProfile = strings.TrimSpace(out.NotificationProfile)
+	if len(out.FallbackChain) > 0 {
+		chain := make([]string, len(out.FallbackChain))
+		timeouts := make(map[string]time.Duration, len(out.FallbackChain))
+		for idx, backend := range out.FallbackChain {
+			chain[idx] = strings.ToLower(strings.TrimSpace(backend))
+			timeouts[chain[idx]] = defaultRoutingFallbackTimeout
+		}
+		for backend, timeout := range out.FallbackTimeouts {
+			if key := strings.ToLower(strings.TrimSpace(backend)); timeout > 0 {
+				timeouts[key] = timeout
+			}
+		}
+		out.FallbackChain, out.FallbackTimeouts = chain, timeouts
+
+		// The chain's first backend is the primary one, so the per-backend checks below apply to it
+		out.Backend = chain[0]
+	}
+	if out.Backend == "" {
+		out.Backend = RoutingBackendPMTiles
+	}
+	if out.CH.MaxSnapDistanceMeters <= 0 {
+		out.CH.MaxSnapDistanceMeters = defaultCHMaxSnapDistanceMeters
+	}
+	if out.StraightLineRadiusFactor <= 0 {
+		out.StraightLineRadiusFactor = defaultStraightLineRadiusFactor
+	}
+	if out.DefaultSpeedKmh == 0 {
+		out.DefaultSpeedKmh = defaultRoutingSpeedKmh
+	}
+
+	return out
+}
+
+// Validate reports an unknown backend or an incomplete CH config. Call it on the result of WithDefaults.
+func (c RoutingConfig) Validate() error {
+	if !(c.DefaultSpeedKmh > 0) || math.IsInf(c.DefaultSpeedKmh, 0) {
+		return fmt.Errorf("routing.defaultSpeedKmh must be positive, got %v", c.DefaultSpeedKmh)
+	}
+	if err := c.validateFallbackChain(); err != nil {
+		return err
+	}
+
+	switch c.Backend {
+	case RoutingBackendPMTiles:
+		if c.LargeGraphEdgeThreshold < 0 {
+			return errors.New("routing.largeGraphEdgeThreshold must not be negative")
+		}
+		if c.LargeGraphEdgeThreshold > 0 && strings.TrimSpace(c.CH.DataDir) == "" {
+			return errors.New("routing.ch.dataDir is required when routing.largeGraphEdgeThreshold is set")
+		}
+
+		return nil
+	case RoutingBackendHaversine:
+		return nil
+	case RoutingBackendCH:
+		if strings.TrimSpace(c.CH.DataDir) == "" {
+			return errors.New("routing.ch.dataDir is required for the ch backend")
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("routing.backend must be one of %s, %s, %s, got %q",
+			RoutingBackendPMTiles, RoutingBackendCH, RoutingBackendHaversine, c.Backend)
+	}
+}
+
+func (c RoutingConfig) validateFallbackChain() error {
+	seen := make(map[string]bool, len(c.FallbackChain))
+	for _, backend := range c.FallbackChain {
+		switch backend {
+		case RoutingBackendPMTiles, RoutingBackendHaversine:
+		case RoutingBackendCH:
+			if strings.TrimSpace(c.CH.DataDir) == "" {
+				return errors.New("routing.ch.dataDir is required when routing.fallbackChain includes ch")
+			}
+		default:
+			return fmt.Errorf("routing.fallbackChain entries must be one of %s, %s, %s, got %q",
+				RoutingBackendPMTiles, RoutingBackendCH, RoutingBackendHaversine, backend)
+		}
+		if seen[backend] {
+			return fmt.Errorf("routing.fallbackChain lists %q more than once", backend)
+		}
+		seen[backend] = true
+	}
+
+	return nil
 }
 
 // DeviceCleanupConfig defines cleanup-job runtime configuration.
 type DeviceCleanupConfig struct {
 	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+
+	// Notification logs older than this many days are purged by the cleanup job
+	NotificationLogRetentionDays int `json:"notificationLogRetentionDays" yaml:"notificationLogRetentionDays"`
+
+	// Devices whose last successful delivery is older than this many days are deleted by the geoworker cleanup task
+	StaleDeliveryDays int `json:"staleDeliveryDays" yaml:"staleDeliveryDays"`
+}
+
+// TracingConfig defines OpenTelemetry span export for the worker.
+type TracingConfig struct {
+	// Export spans to an OTLP/HTTP collector; when false the worker records no spans
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Collector host and port, such as "otel-collector:4318". Empty uses OTEL_EXPORTER_OTLP_TRACES_ENDPOINT or
+	// OTEL_EXPORTER_OTLP_ENDPOINT when set, and localhost:4318 otherwise
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+
+	// Send spans over plain HTTP instead of HTTPS, for a collector sidecar
+	Insecure bool `json:"insecure" yaml:"insecure"`
+
+	// Share of traces that are sampled, between 0 and 1; unset samples every trace
+	SampleRatio *float64 `json:"sampleRatio" yaml:"sampleRatio"`
 }
 
 // LoadWithEnv loads .yaml files through koanf.
@@ -306,6 +918,7 @@ func ApplyDefaults(cfg *Config) {
 	applyHTTPDefaults(cfg)
 	applyAuthDefaults(cfg)
 	applyLoginThrottleDefaults(cfg)
+	applyRateLimitDefaults(cfg)
 	applyLocationNotificationDefaults(cfg)
 	applyNotificationDefaults(cfg)
 	applyDeviceCleanupDefaults(cfg)
@@ -378,6 +991,9 @@ func applyLocationNotificationDefaults(cfg *Config) {
 	if cfg.LocationNotification.MaxRadius <= 0 {
 		cfg.LocationNotification.MaxRadius = 5000
 	}
+	if precision := cfg.LocationNotification.CoordinatePrecision; precision == nil || *precision < 0 {
+		cfg.LocationNotification.CoordinatePrecision = new(defaultCoordinatePrecision)
+	}
 }
 
 func applyNotificationDefaults(cfg *Config) {
@@ -387,6 +1003,58 @@ func applyNotificationDefaults(cfg *Config) {
 	if cfg.Notification.Timeout <= 0 {
 		cfg.Notification.Timeout = defaultNotificationTimeout
 	}
+	if cfg.Notification.MaxRetries < 0 {
+		cfg.Notification.MaxRetries = 0
+	}
+	if cfg.Notification.RetryBackoff <= 0 {
+		cfg.Notification.RetryBackoff = defaultNotificationRetryBackoff
+	}
+	if cfg.Notification.BreakerFailureRate <= 0 || cfg.Notification.BreakerFailureRate > 1 {
+		cfg.Notification.BreakerFailureRate = defaultNotificationBreakerFailureRate
+	}
+	if cfg.Notification.BreakerWindowSize <= 0 {
+		cfg.Notification.BreakerWindowSize = defaultNotificationBreakerWindowSize
+	}
+	if cfg.Notification.BreakerMinRequests <= 0 {
+		cfg.Notification.BreakerMinRequests = defaultNotificationBreakerMinRequests
+	}
+	if cfg.Notification.BreakerOpenDuration <= 0 {
+		cfg.Notification.BreakerOpenDuration = defaultNotificationBreakerOpenDuration
+	}
+	if cfg.Notification.BroadcastTTL <= 0 {
+		cfg.Notification.BroadcastTTL = defaultNotificationBroadcastTTL
+	}
+	if cfg.Notification.BroadcastCooldown < 0 {
+		cfg.Notification.BroadcastCooldown = 0
+	}
+	if cfg.Notification.InvalidTokenStrikes <= 0 {
+		cfg.Notification.InvalidTokenStrikes = defaultNotificationInvalidTokenStrikes
+	}
+	if cfg.Notification.InvalidTokenStrikeWindow <= 0 {
+		cfg.Notification.InvalidTokenStrikeWindow = defaultNotificationInvalidTokenStrikeWindow
+	}
+	if cfg.Notification.MaxConcurrentBatches <= 0 {
+		cfg.Notification.MaxConcurrentBatches = defaultNotificationMaxConcurrentSends
+	}
+	cfg.Notification.Canary.Fraction = min(max(cfg.Notification.Canary.Fraction, 0), 1)
+	if cfg.Notification.SubscriberTileZoom <= 0 || cfg.Notification.SubscriberTileZoom > maxPMTilesZoomLevel {
+		cfg.Notification.SubscriberTileZoom = defaultPMTilesZoomLevel
+	}
+}
+
+func applyRateLimitDefaults(cfg *Config) {
+	if cfg.RateLimit == nil {
+		cfg.RateLimit = &RateLimitConfig{}
+	}
+	for _, rule := range []*RateLimitRule{
+		&cfg.RateLimit.Notifications,
+		&cfg.RateLimit.Subscriptions,
+		&cfg.RateLimit.Locations,
+	} {
+		if rule.RequestsPerMinute > 0 && rule.Burst <= 0 {
+			rule.Burst = rule.RequestsPerMinute
+		}
+	}
 }
 
 func applyDeviceCleanupDefaults(cfg *Config) {
@@ -396,6 +1064,12 @@ func applyDeviceCleanupDefaults(cfg *Config) {
 	if cfg.DeviceCleanup.Timeout <= 0 {
 		cfg.DeviceCleanup.Timeout = defaultDeviceCleanupTimeout
 	}
+	if cfg.DeviceCleanup.NotificationLogRetentionDays <= 0 {
+		cfg.DeviceCleanup.NotificationLogRetentionDays = defaultNotificationLogRetentionDays
+	}
+	if cfg.DeviceCleanup.StaleDeliveryDays <= 0 {
+		cfg.DeviceCleanup.StaleDeliveryDays = defaultStaleDeviceDeliveryDays
+	}
 }
 
 func canonicalizeEnvKey(rawKey string, existing map[string]any) string {
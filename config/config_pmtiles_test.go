@@ -0,0 +1,150 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPMTilesConfig_WithDefaults_AppliesDefaults(t *testing.T) {
+	var cfg *PMTilesConfig
+
+	got := cfg.WithDefaults()
+
+	want := PMTilesConfig{
+		RoadLayer:             defaultPMTilesRoadLayer,
+		ZoomLevel:             defaultPMTilesZoomLevel,
+		CacheSize:             defaultPMTilesCacheSize,
+		MinEdgeDistanceMeters: new(defaultPMTilesMinEdgeDistanceMeters),
+		MaxSnapDistanceMeters: defaultPMTilesMaxSnapDistanceMeters,
+		MaxTileSpan:           defaultPMTilesMaxTileSpan,
+		CorridorBufferTiles:   new(defaultPMTilesCorridorBufferTiles),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected defaults: got %+v, want %+v", got, want)
+	}
+}
+
+func TestPMTilesConfig_WithDefaults_KeepsExplicitValues(t *testing.T) {
+	cfg := &PMTilesConfig{
+		Enabled:               true,
+		Source:                "file:///data/roads.pmtiles",
+		RoadLayer:             "roads",
+		ZoomLevel:             15,
+		CacheSize:             8,
+		MaxTileSpan:           4,
+		CorridorBufferTiles:   new(0),
+		MinEdgeDistanceMeters: new(0.0),
+		MaxSnapDistanceMeters: 150,
+		MaxGraphMemoryBytes:   -1,
+		TileCacheMaxAge:       -time.Minute,
+	}
+
+	got := cfg.WithDefaults()
+
+	if got.RoadLayer != "roads" || got.ZoomLevel != 15 || got.CacheSize != 8 || got.MaxTileSpan != 4 ||
+		got.MaxSnapDistanceMeters != 150 || *got.CorridorBufferTiles != 0 ||
+		*got.MinEdgeDistanceMeters != 0 {
+		t.Fatalf("explicit values overwritten: %+v", got)
+	}
+	if got.MaxGraphMemoryBytes != 0 {
+		t.Fatalf("negative memory budget should disable the check, got %d", got.MaxGraphMemoryBytes)
+	}
+	if got.TileCacheMaxAge != 0 {
+		t.Fatalf("negative tile cache max age should disable rechecks, got %s", got.TileCacheMaxAge)
+	}
+	if cfg.CacheSize != 8 || cfg.MaxTileSpan != 4 {
+		t.Fatalf("receiver mutated: %+v", cfg)
+	}
+}
+
+func TestPMTilesConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     PMTilesConfig
+		wantErr string
+	}{
+		{name: "disabled without source", cfg: PMTilesConfig{}},
+		{name: "enabled with source", cfg: PMTilesConfig{Enabled: true, Source: "roads.pmtiles"}},
+		{name: "enabled with empty source", cfg: PMTilesConfig{Enabled: true, Source: "  "}, wantErr: "pmtiles.source is required"},
+		{name: "zoom level out of range", cfg: PMTilesConfig{Enabled: true, Source: "roads.pmtiles", ZoomLevel: 30}, wantErr: "pmtiles.zoomLevel must be between"},
+		{name: "composite routing cost", cfg: PMTilesConfig{Enabled: true, Source: "roads.pmtiles", RoutingCost: PMTilesRoutingCostConfig{DurationWeight: 1, TurnPenaltySeconds: 10, RoadClassWeight: 0.5}}},
+		{name: "negative routing cost weight", cfg: PMTilesConfig{Enabled: true, Source: "roads.pmtiles", RoutingCost: PMTilesRoutingCostConfig{DurationWeight: 1, TurnPenaltySeconds: -1}}, wantErr: "must not be negative"},
+		{name: "routing cost without duration weight", cfg: PMTilesConfig{Enabled: true, Source: "roads.pmtiles", RoutingCost: PMTilesRoutingCostConfig{RoadClassWeight: 0.5}}, wantErr: "durationWeight is required"},
+		{name: "negative min edge distance", cfg: PMTilesConfig{Enabled: true, Source: "roads.pmtiles", MinEdgeDistanceMeters: new(-1.0)}, wantErr: "pmtiles.minEdgeDistanceMeters must not be negative"},
+		{name: "negative corridor buffer", cfg: PMTilesConfig{Enabled: true, Source: "roads.pmtiles", CorridorBufferTiles: new(-1)}, wantErr: "pmtiles.corridorBufferTiles must not be negative"},
+		{name: "profiles without source", cfg: PMTilesConfig{Enabled: true, Profiles: []PMTilesProfileConfig{{Name: "driving", Source: "driving.pmtiles"}, {Name: "walking", Source: "walking.pmtiles"}}}},
+		{name: "profile without name", cfg: PMTilesConfig{Enabled: true, Profiles: []PMTilesProfileConfig{{Source: "driving.pmtiles"}}}, wantErr: "pmtiles.profiles[0].name is required"},
+		{name: "profile without source", cfg: PMTilesConfig{Enabled: true, Profiles: []PMTilesProfileConfig{{Name: "walking"}}}, wantErr: "pmtiles.profiles[0].source is required"},
+		{name: "duplicate profile", cfg: PMTilesConfig{Enabled: true, Profiles: []PMTilesProfileConfig{{Name: "walking", Source: "a.pmtiles"}, {Name: "walking", Source: "b.pmtiles"}}}, wantErr: `duplicate profile "walking"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			err := (&cfg).WithDefaults().Validate()
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPMTilesConfig_Validate_CloudSourceCredentials(t *testing.T) {
+	credentialEnv := []string{
+		"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_REGION", "AWS_DEFAULT_REGION", "AWS_PROFILE", "AWS_CONFIG_FILE",
+		"AZURE_STORAGE_ACCOUNT", "AZURE_STORAGE_CONNECTION_STRING", "AZURE_STORAGEBLOB_CONNECTIONSTRING",
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		env     map[string]string
+		wantErr string
+	}{
+		{name: "gcs source needs no extra settings", source: "gs://tiles/roads.pmtiles"},
+		{name: "s3 region in query", source: "s3://tiles/roads.pmtiles?region=ap-northeast-1"},
+		{name: "s3 region from env", source: "s3://tiles/roads.pmtiles", env: map[string]string{"AWS_REGION": "ap-northeast-1"}},
+		{name: "s3 region from profile", source: "s3://tiles/roads.pmtiles", env: map[string]string{"AWS_PROFILE": "tiles"}},
+		{name: "s3 without region", source: "s3://tiles/roads.pmtiles", wantErr: "requires a region"},
+		{
+			name:    "s3 with half of a static key pair",
+			source:  "s3://tiles/roads.pmtiles?region=ap-northeast-1",
+			env:     map[string]string{"AWS_ACCESS_KEY_ID": "AKIDEXAMPLE"},
+			wantErr: "set together",
+		},
+		{name: "azure account in query", source: "azblob://tiles/roads.pmtiles?storage_account=radar"},
+		{name: "azure account from env", source: "azblob://tiles/roads.pmtiles", env: map[string]string{"AZURE_STORAGE_ACCOUNT": "radar"}},
+		{name: "azure without account", source: "azblob://tiles/roads.pmtiles", wantErr: "requires a storage account"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range credentialEnv {
+				t.Setenv(key, tt.env[key])
+			}
+
+			err := (&PMTilesConfig{Enabled: true, Source: tt.source}).WithDefaults().Validate()
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRoutingConfig_WithDefaults(t *testing.T) {
+	var cfg *RoutingConfig
+
+	got := cfg.WithDefaults()
+
+	if got.Backend != RoutingBackendPMTiles || got.CH.MaxSnapDistanceMeters != defaultCHMaxSnapDistanceMeters ||
+		got.StraightLineRadiusFactor != defaultStraightLineRadiusFactor || got.DefaultSpeedKmh != defaultRoutingSpeedKmh {
+		t.Fatalf("unexpected defaults: %+v", got)
+	}
+
+	explicit := (&RoutingConfig{
+		Backend:                  " CH ",
+		StraightLineRadiusFactor: 0.7,
+		CH:                       CHRoutingConfig{MaxSnapDistanceMeters: 200},
+	}).WithDefaults()
+	if explicit.Backend != RoutingBackendCH || explicit.CH.MaxSnapDistanceMeters != 200 || explicit.StraightLineRadiusFactor != 0.7 {
+		t.Fatalf("explicit values overwritten: %+v", explicit)
+	}
+
+	chain := (&RoutingConfig{
+		Backend:          RoutingBackendPMTiles,
+		FallbackChain:    []string{" CH ", "haversine"},
+		FallbackTimeouts: map[string]time.Duration{"ch": 500 * time.Millisecond},
+	}).WithDefaults()
+	if chain.Backend != RoutingBackendCH || chain.FallbackChain[0] != RoutingBackendCH {
+		t.Fatalf("fallback chain not normalized: %+v", chain)
+	}
+	if chain.FallbackTimeouts["ch"] != 500*time.Millisecond || chain.FallbackTimeouts["haversine"] != defaultRoutingFallbackTimeout {
+		t.Fatalf("unexpected fallback timeouts: %v", chain.FallbackTimeouts)
+	}
+}
+
+func TestRoutingConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     RoutingConfig
+		wantErr string
+	}{
+		{name: "default backend", cfg: RoutingConfig{}},
+		{name: "haversine backend", cfg: RoutingConfig{Backend: RoutingBackendHaversine}},
+		{name: "ch backend with data dir", cfg: RoutingConfig{Backend: RoutingBackendCH, CH: CHRoutingConfig{DataDir: "./data/routing"}}},
+		{name: "ch backend without data dir", cfg: RoutingConfig{Backend: RoutingBackendCH}, wantErr: "routing.ch.dataDir is required"},
+		{name: "unknown backend", cfg: RoutingConfig{Backend: "osrm"}, wantErr: "routing.backend must be one of"},
+		{name: "pmtiles large graph delegation", cfg: RoutingConfig{LargeGraphEdgeThreshold: 200000, CH: CHRoutingConfig{DataDir: "./data/routing"}}},
+		{name: "large graph delegation without data dir", cfg: RoutingConfig{LargeGraphEdgeThreshold: 200000}, wantErr: "routing.ch.dataDir is required when"},
+		{name: "negative large graph threshold", cfg: RoutingConfig{LargeGraphEdgeThreshold: -1}, wantErr: "must not be negative"},
+		{name: "fallback chain", cfg: RoutingConfig{FallbackChain: []string{"pmtiles", "ch", "haversine"}, CH: CHRoutingConfig{DataDir: "./data/routing"}}},
+		{name: "fallback chain with unknown backend", cfg: RoutingConfig{FallbackChain: []string{"pmtiles", "osrm"}}, wantErr: "routing.fallbackChain entries must be one of"},
+		{name: "fallback chain with duplicate backend", cfg: RoutingConfig{FallbackChain: []string{"pmtiles", "PMTiles"}}, wantErr: "more than once"},
+		{name: "fallback chain ch without data dir", cfg: RoutingConfig{FallbackChain: []string{"pmtiles", "ch"}}, wantErr: "routing.fallbackChain includes ch"},
+		{name: "walking speed", cfg: RoutingConfig{DefaultSpeedKmh: 5}},
+		{name: "negative speed", cfg: RoutingConfig{DefaultSpeedKmh: -5}, wantErr: "routing.defaultSpeedKmh must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			err := (&cfg).WithDefaults().Validate()
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+ALTER TABLE user_merchant_subscriptions
+    ADD COLUMN snoozed_until TIMESTAMPTZ;
+
+COMMENT ON COLUMN user_merchant_subscriptions.snoozed_until IS
+'Broadcasts from this merchant are suppressed until this time. Past values have no effect, so snoozes expire without cleanup.';
+
+ALTER TABLE user_profiles
+    ADD COLUMN broadcasts_snoozed_until TIMESTAMPTZ;
+
+COMMENT ON COLUMN user_profiles.broadcasts_snoozed_until IS
+'Broadcasts from every subscribed merchant are suppressed until this time. Past values have no effect.';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+
+ALTER TABLE user_profiles
+    DROP COLUMN IF EXISTS broadcasts_snoozed_until;
+
+ALTER TABLE user_merchant_subscriptions
+    DROP COLUMN IF EXISTS snoozed_until;
@@ -0,0 +1,25 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+CREATE TABLE merchant_settings (
+    merchant_id UUID PRIMARY KEY REFERENCES merchant_profiles(user_id) ON DELETE CASCADE,
+    max_notification_radius DECIMAL(10,2),
+    broadcasts_per_hour INTEGER,
+    distance_unit TEXT,
+    rotate_notification_templates BOOLEAN,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE merchant_settings IS
+'Per-merchant behavior overrides. NULL columns fall back to the service-wide defaults.';
+
+CREATE TRIGGER update_merchant_settings_updated_at
+    BEFORE UPDATE ON merchant_settings
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+
+DROP TABLE IF EXISTS merchant_settings;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+ALTER TABLE refresh_tokens
+    ADD COLUMN last_used_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN refresh_tokens.last_used_at IS
+'Last time this token was presented to refresh an access token. NULL means it has not been used since it was issued.';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+
+ALTER TABLE refresh_tokens
+    DROP COLUMN IF EXISTS last_used_at;
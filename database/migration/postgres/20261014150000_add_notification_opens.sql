@@ -0,0 +1,23 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+ALTER TABLE notification_logs
+    ADD COLUMN opened_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN notification_logs.opened_at IS
+'First time the recipient reported opening the notification. NULL means it has not been opened.';
+
+ALTER TABLE merchant_location_notifications
+    ADD COLUMN total_opened INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN merchant_location_notifications.total_opened IS
+'Number of recipients who opened the notification; each user counts once regardless of device count.';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+
+ALTER TABLE merchant_location_notifications
+    DROP COLUMN IF EXISTS total_opened;
+
+ALTER TABLE notification_logs
+    DROP COLUMN IF EXISTS opened_at;
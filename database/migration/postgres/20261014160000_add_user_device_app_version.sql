@@ -0,0 +1,14 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+ALTER TABLE user_devices
+    ADD COLUMN app_version TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN user_devices.app_version IS
+'Client app version reported at registration, e.g. 2.4.0. Empty means the client did not report one.';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+
+ALTER TABLE user_devices
+    DROP COLUMN IF EXISTS app_version;
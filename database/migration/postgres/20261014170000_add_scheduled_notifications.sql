@@ -0,0 +1,27 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+ALTER TABLE merchant_location_notifications
+    ADD COLUMN scheduled_at TIMESTAMPTZ,
+    ADD COLUMN schedule_status TEXT
+        CHECK (schedule_status IN ('pending', 'dispatched', 'canceled'));
+
+COMMENT ON COLUMN merchant_location_notifications.scheduled_at IS
+'Time a scheduled notification is due to be sent. NULL means it was sent when published.';
+
+COMMENT ON COLUMN merchant_location_notifications.schedule_status IS
+'Lifecycle of a scheduled notification: pending until due, then dispatched, or canceled by the merchant.';
+
+-- Supports the scheduled dispatch job's poll for due notifications
+CREATE INDEX idx_merchant_location_notifications_pending_schedule
+    ON merchant_location_notifications (scheduled_at)
+    WHERE schedule_status = 'pending';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+
+DROP INDEX IF EXISTS idx_merchant_location_notifications_pending_schedule;
+
+ALTER TABLE merchant_location_notifications
+    DROP COLUMN IF EXISTS schedule_status,
+    DROP COLUMN IF EXISTS scheduled_at;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+ALTER TABLE user_devices
+    ADD COLUMN invalid_token_strikes INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN invalid_token_first_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN user_devices.invalid_token_strikes IS
+'Consecutive sends FCM answered with an invalid-token error. Reset by a successful send or a token refresh.';
+
+COMMENT ON COLUMN user_devices.invalid_token_first_at IS
+'When the current run of invalid-token strikes started. NULL when the device has no strikes.';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+
+ALTER TABLE user_devices
+    DROP COLUMN IF EXISTS invalid_token_first_at,
+    DROP COLUMN IF EXISTS invalid_token_strikes;
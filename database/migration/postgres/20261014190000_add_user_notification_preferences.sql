@@ -0,0 +1,39 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+CREATE TABLE user_notification_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    quiet_hours_start SMALLINT CHECK (quiet_hours_start BETWEEN 0 AND 1439),
+    quiet_hours_end SMALLINT CHECK (quiet_hours_end BETWEEN 0 AND 1439),
+    opted_out_category_ids JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_user_notification_preferences_quiet_hours
+        CHECK ((quiet_hours_start IS NULL) = (quiet_hours_end IS NULL))
+);
+
+COMMENT ON TABLE user_notification_preferences IS
+'Per-subscriber controls over which broadcasts are delivered. Users without a row receive every in-radius broadcast.';
+
+COMMENT ON COLUMN user_notification_preferences.timezone IS
+'IANA time zone the quiet hours are evaluated in.';
+
+COMMENT ON COLUMN user_notification_preferences.quiet_hours_start IS
+'Start of the daily quiet window in minutes after local midnight. A window whose end is before its start spans midnight.';
+
+COMMENT ON COLUMN user_notification_preferences.quiet_hours_end IS
+'End of the daily quiet window in minutes after local midnight, exclusive.';
+
+COMMENT ON COLUMN user_notification_preferences.opted_out_category_ids IS
+'Discovery category IDs whose merchants'' broadcasts the subscriber does not receive.';
+
+CREATE TRIGGER update_user_notification_preferences_updated_at
+    BEFORE UPDATE ON user_notification_preferences
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+
+DROP TABLE IF EXISTS user_notification_preferences;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+CREATE TABLE merchant_api_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    merchant_id UUID NOT NULL REFERENCES merchant_profiles(user_id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    scopes JSONB NOT NULL DEFAULT '[]',
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_merchant_api_tokens_merchant_id ON merchant_api_tokens (merchant_id);
+
+COMMENT ON TABLE merchant_api_tokens IS
+'Merchant-scoped credentials for server-to-server calls. Only the SHA-256 hash of each raw token is stored.';
+
+COMMENT ON COLUMN merchant_api_tokens.scopes IS
+'Operations the token may perform, e.g. notifications:publish.';
+
+COMMENT ON COLUMN merchant_api_tokens.revoked_at IS
+'When the merchant revoked the token. Revoked tokens are kept so their use can still be identified.';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+
+DROP TABLE IF EXISTS merchant_api_tokens;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+ALTER TABLE addresses
+    ADD COLUMN geofence_polygon GEOMETRY(POLYGON, 4326);
+
+ALTER TABLE addresses
+    ADD CONSTRAINT chk_addresses_geofence_polygon_valid
+        CHECK (geofence_polygon IS NULL OR ST_IsValid(geofence_polygon));
+
+CREATE INDEX idx_addresses_geofence_polygon
+    ON addresses USING GIST (geofence_polygon)
+    WHERE geofence_polygon IS NOT NULL;
+
+COMMENT ON COLUMN addresses.geofence_polygon IS
+'Optional neighborhood a subscriber wants broadcasts from. When set, a merchant broadcast reaches the address only from inside the polygon, and the subscription notification radius is ignored.';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+
+DROP INDEX IF EXISTS idx_addresses_geofence_polygon;
+ALTER TABLE addresses DROP CONSTRAINT IF EXISTS chk_addresses_geofence_polygon_valid;
+ALTER TABLE addresses DROP COLUMN IF EXISTS geofence_polygon;
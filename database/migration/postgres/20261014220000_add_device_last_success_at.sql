@@ -0,0 +1,20 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+ALTER TABLE user_devices
+    ADD COLUMN last_success_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN user_devices.last_success_at IS
+'When a notification was last delivered to the device. NULL until its first delivery, when stale-device cleanup measures from created_at.';
+
+CREATE INDEX IF NOT EXISTS idx_user_devices_last_success_at
+    ON user_devices ((COALESCE(last_success_at, created_at)))
+    WHERE deleted_at IS NULL;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+
+DROP INDEX IF EXISTS idx_user_devices_last_success_at;
+
+ALTER TABLE user_devices
+    DROP COLUMN IF EXISTS last_success_at;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+-- The baseline's idx_user_devices_fcm_token_unique already keeps a token on at most one live row, so this
+-- index adds no uniqueness. It exists only as the arbiter that ON CONFLICT (user_id, fcm_token) requires for
+-- device registration to refresh the user's live row for a token instead of failing on the insert.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_devices_user_fcm_token_unique
+    ON user_devices(user_id, fcm_token)
+    WHERE deleted_at IS NULL;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+
+DROP INDEX IF EXISTS idx_user_devices_user_fcm_token_unique;
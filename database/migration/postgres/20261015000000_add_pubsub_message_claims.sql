@@ -0,0 +1,25 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+CREATE TABLE pubsub_message_claims (
+    message_id TEXT PRIMARY KEY,
+    status TEXT NOT NULL CHECK (status IN ('processing', 'processed')),
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_pubsub_message_claims_expires_at ON pubsub_message_claims (expires_at);
+
+COMMENT ON TABLE pubsub_message_claims IS
+'Pub/Sub message IDs seen by the push handler, so a redelivered message is acknowledged without being processed again.';
+
+COMMENT ON COLUMN pubsub_message_claims.status IS
+'processing while a delivery holds the claim, processed once its side effects are done.';
+
+COMMENT ON COLUMN pubsub_message_claims.expires_at IS
+'When a processing claim lapses and another delivery may take it over, or when a processed record may be purged.';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+
+DROP TABLE IF EXISTS pubsub_message_claims;
@@ -0,0 +1,27 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+ALTER TABLE user_devices
+    ADD COLUMN notifications_enabled BOOLEAN,
+    ADD COLUMN quiet_hours_start SMALLINT CHECK (quiet_hours_start BETWEEN 0 AND 1439),
+    ADD COLUMN quiet_hours_end SMALLINT CHECK (quiet_hours_end BETWEEN 0 AND 1439),
+    ADD CONSTRAINT chk_user_devices_quiet_hours
+        CHECK ((quiet_hours_start IS NULL) = (quiet_hours_end IS NULL));
+
+COMMENT ON COLUMN user_devices.notifications_enabled IS
+'Per-device override of whether broadcasts are delivered to the device. NULL inherits the owner''s preferences.';
+
+COMMENT ON COLUMN user_devices.quiet_hours_start IS
+'Start of the device''s daily quiet window in minutes after the owner''s local midnight, applied on top of the owner''s quiet hours. NULL sets no device window.';
+
+COMMENT ON COLUMN user_devices.quiet_hours_end IS
+'End of the device''s daily quiet window in minutes after the owner''s local midnight, exclusive.';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+
+ALTER TABLE user_devices
+    DROP CONSTRAINT IF EXISTS chk_user_devices_quiet_hours,
+    DROP COLUMN IF EXISTS quiet_hours_end,
+    DROP COLUMN IF EXISTS quiet_hours_start,
+    DROP COLUMN IF EXISTS notifications_enabled;
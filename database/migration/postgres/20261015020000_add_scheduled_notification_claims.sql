@@ -0,0 +1,46 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+ALTER TABLE merchant_location_notifications
+    ADD COLUMN claimed_at TIMESTAMPTZ;
+
+ALTER TABLE merchant_location_notifications
+    DROP CONSTRAINT IF EXISTS merchant_location_notifications_schedule_status_check;
+
+ALTER TABLE merchant_location_notifications
+    ADD CONSTRAINT merchant_location_notifications_schedule_status_check
+        CHECK (schedule_status IN ('pending', 'claimed', 'dispatched', 'canceled'));
+
+COMMENT ON COLUMN merchant_location_notifications.claimed_at IS
+'When a dispatch run last claimed the scheduled notification. A claimed row whose claim is older than the dispatch lease was abandoned and is claimed again.';
+
+COMMENT ON COLUMN merchant_location_notifications.schedule_status IS
+'Lifecycle of a scheduled notification: pending until due, claimed while a dispatch run delivers it, then dispatched, or canceled by the merchant.';
+
+-- Supports the scheduled dispatch job's poll for abandoned claims
+CREATE INDEX idx_merchant_location_notifications_claimed_schedule
+    ON merchant_location_notifications (claimed_at)
+    WHERE schedule_status = 'claimed';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+
+DROP INDEX IF EXISTS idx_merchant_location_notifications_claimed_schedule;
+
+-- Abandoned claims go back to pending so the original constraint holds
+UPDATE merchant_location_notifications
+SET schedule_status = 'pending'
+WHERE schedule_status = 'claimed';
+
+ALTER TABLE merchant_location_notifications
+    DROP CONSTRAINT IF EXISTS merchant_location_notifications_schedule_status_check;
+
+ALTER TABLE merchant_location_notifications
+    ADD CONSTRAINT merchant_location_notifications_schedule_status_check
+        CHECK (schedule_status IN ('pending', 'dispatched', 'canceled'));
+
+COMMENT ON COLUMN merchant_location_notifications.schedule_status IS
+'Lifecycle of a scheduled notification: pending until due, then dispatched, or canceled by the merchant.';
+
+ALTER TABLE merchant_location_notifications
+    DROP COLUMN IF EXISTS claimed_at;
@@ -1,12 +1,15 @@
 package middleware
 
 import (
+	"errors"
 	"strings"
 
 	"radar/config"
 	"radar/internal/delivery/api/response"
 	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
 	"radar/internal/domain/service"
+	"radar/internal/usecase"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -20,6 +23,8 @@ const (
 	contextKeyUserID ContextKey = "userID"
 	// contextKeyRoles is the key for storing user roles in context.
 	contextKeyRoles ContextKey = "roles"
+	// contextKeyAPITokenID is the key for storing the ID of the API token that authenticated the request.
+	contextKeyAPITokenID ContextKey = "apiTokenID"
 )
 
 // GetUserID extracts the authenticated user ID from context.
@@ -40,50 +45,116 @@ func GetRoles(c echo.Context) (entity.Roles, bool) {
 	return roles, ok
 }
 
+// GetAPITokenID extracts the ID of the merchant API token that authenticated the request.
+// It returns false when the request was authenticated with a JWT.
+func GetAPITokenID(c echo.Context) (uuid.UUID, bool) {
+	val := c.Get(string(contextKeyAPITokenID))
+	id, ok := val.(uuid.UUID)
+
+	return id, ok
+}
+
 // AuthMiddleware provides middleware for JWT authentication and authorization.
 type AuthMiddleware struct {
-	tokenSvc service.TokenService
-	cfg      *config.Config
+	tokenSvc   service.TokenService
+	apiTokenUC usecase.APITokenUsecase
+	cfg        *config.Config
 }
 
 // NewAuthMiddleware is the constructor for AuthMiddleware.
-func NewAuthMiddleware(tokenSvc service.TokenService, cfg *config.Config) *AuthMiddleware {
-	return &AuthMiddleware{tokenSvc: tokenSvc, cfg: cfg}
+func NewAuthMiddleware(tokenSvc service.TokenService, apiTokenUC usecase.APITokenUsecase, cfg *config.Config) *AuthMiddleware {
+	return &AuthMiddleware{tokenSvc: tokenSvc, apiTokenUC: apiTokenUC, cfg: cfg}
 }
 
 // Authenticate is the core middleware function that validates the JWT access token.
+// Merchant API tokens are rejected; routes that accept them use AuthenticateWithAPIToken.
 func (m *AuthMiddleware) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		authHeader := c.Request().Header.Get("Authorization")
-		if authHeader == "" {
-			return response.AuthRequired(c)
+		tokenString, ok := bearerToken(c)
+		if !ok {
+			return rejectCredentials(c)
 		}
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenString == authHeader {
-			return response.InvalidToken(c)
-		}
+		return m.authenticateJWT(c, tokenString, next)
+	}
+}
 
-		claims, err := m.tokenSvc.ValidateToken(tokenString)
-		if err != nil {
-			return response.InvalidToken(c)
-		}
-		if claims.Type != service.TokenTypeAccess {
-			return response.InvalidToken(c)
+// AuthenticateWithAPIToken accepts either a JWT access token or a merchant API token carrying the scope.
+// An API token authenticates the request as its merchant, with the merchant role only.
+func (m *AuthMiddleware) AuthenticateWithAPIToken(scope entity.APITokenScope) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			tokenString, ok := bearerToken(c)
+			if !ok {
+				return rejectCredentials(c)
+			}
+			if !entity.IsAPIToken(tokenString) {
+				return m.authenticateJWT(c, tokenString, next)
+			}
+
+			token, err := m.apiTokenUC.AuthenticateAPIToken(c.Request().Context(), tokenString)
+			if err != nil {
+				if errors.Is(err, domainerrors.ErrInvalidToken) {
+					return response.InvalidToken(c)
+				}
+
+				return err
+			}
+			if !token.HasScope(scope) {
+				return response.ForbiddenAccess(c)
+			}
+
+			c.Set(string(contextKeyUserID), token.MerchantID)
+			c.Set(string(contextKeyRoles), entity.Roles{entity.RoleMerchant})
+			c.Set(string(contextKeyAPITokenID), token.ID)
+
+			return next(c)
 		}
+	}
+}
 
-		// Extract user ID
-		userID := claims.UserID
+// bearerToken extracts the credential from the Authorization header.
+// ok is false when the header is missing or does not use the Bearer scheme.
+func bearerToken(c echo.Context) (token string, ok bool) {
+	authHeader := c.Request().Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	if authHeader == "" || tokenString == authHeader {
+		return "", false
+	}
 
-		// Convert []string roles from JWT to entity.Roles (boundary conversion)
-		roles := entity.RolesFromStrings(claims.Roles)
+	return tokenString, true
+}
 
-		// Set user info on the context for handlers to use
-		c.Set(string(contextKeyUserID), userID)
-		c.Set(string(contextKeyRoles), roles)
+// rejectCredentials answers a request whose Authorization header is missing or malformed.
+func rejectCredentials(c echo.Context) error {
+	if c.Request().Header.Get("Authorization") == "" {
+		return response.AuthRequired(c)
+	}
+
+	return response.InvalidToken(c)
+}
 
-		return next(c)
+// authenticateJWT validates a JWT access token and sets the user info on the context.
+func (m *AuthMiddleware) authenticateJWT(c echo.Context, tokenString string, next echo.HandlerFunc) error {
+	claims, err := m.tokenSvc.ValidateToken(tokenString)
+	if err != nil {
+		return response.InvalidToken(c)
+	}
+	if claims.Type != service.TokenTypeAccess {
+		return response.InvalidToken(c)
 	}
+
+	// Extract user ID
+	userID := claims.UserID
+
+	// Convert []string roles from JWT to entity.Roles (boundary conversion)
+	roles := entity.RolesFromStrings(claims.Roles)
+
+	// Set user info on the context for handlers to use
+	c.Set(string(contextKeyUserID), userID)
+	c.Set(string(contextKeyRoles), roles)
+
+	return next(c)
 }
 
 // RequireRole is a middleware factory that checks if the user has a specific role.
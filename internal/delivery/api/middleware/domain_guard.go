@@ -29,8 +29,8 @@ func NewDomainGuardMiddleware(cfg *config.Config) *DomainGuardMiddleware {
 // ValidateHost blocks requests that do not pass origin validation.
 func (m *DomainGuardMiddleware) ValidateHost(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		// Keep probe endpoint available for Cloud Run startup checks.
-		if c.Request().URL.Path == "/health" {
+		// Keep probe endpoints available for Cloud Run startup and liveness checks.
+		if isProbePath(c.Request().URL.Path) {
 			return next(c)
 		}
 
@@ -79,3 +79,13 @@ func normalizeHost(rawHost string) string {
 
 	return strings.ToLower(host)
 }
+
+// isProbePath reports whether path is a health probe endpoint
+func isProbePath(path string) bool {
+	switch path {
+	case "/health", "/healthz", "/readyz":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"radar/config"
+	"radar/internal/delivery/api/response"
+	domainerrors "radar/internal/domain/errors"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Idle buckets are swept at most this often so the limiter does not grow with every user ever seen.
+const rateLimitSweepInterval = 10 * time.Minute
+
+// tokenBucket tracks one user's remaining requests
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// UserRateLimiter applies a token bucket per authenticated user.
+type UserRateLimiter struct {
+	ratePerSecond float64
+	burst         float64
+	now           func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[uuid.UUID]*tokenBucket
+	lastSweep time.Time
+}
+
+// NewUserRateLimiter creates a limiter for one route group; a rule without a rate disables limiting.
+func NewUserRateLimiter(rule config.RateLimitRule) *UserRateLimiter {
+	burst := rule.Burst
+	if burst <= 0 {
+		burst = rule.RequestsPerMinute
+	}
+
+	return &UserRateLimiter{
+		ratePerSecond: float64(max(rule.RequestsPerMinute, 0)) / 60.0,
+		burst:         float64(burst),
+		now:           time.Now,
+		buckets:       make(map[uuid.UUID]*tokenBucket),
+	}
+}
+
+// Enabled reports whether the limiter restricts any requests.
+func (l *UserRateLimiter) Enabled() bool {
+	return l.ratePerSecond > 0 && l.burst > 0
+}
+
+// Limit rejects requests over the user's budget with 429 and a Retry-After header.
+// It must be used AFTER the Authenticate middleware.
+func (l *UserRateLimiter) Limit(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !l.Enabled() {
+			return next(c)
+		}
+
+		userID, ok := GetUserID(c)
+		if !ok {
+			return next(c)
+		}
+
+		allowed, retryAfter := l.take(userID)
+		if !allowed {
+			c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfterHeaderSeconds(retryAfter)))
+
+			return response.AppError(c, domainerrors.ErrRateLimitExceeded)
+		}
+
+		return next(c)
+	}
+}
+
+// take consumes one token for the user, returning how long to wait when none is left
+func (l *UserRateLimiter) take(userID uuid.UUID) (bool, time.Duration) {
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	bucket, ok := l.buckets[userID]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, updated: now}
+		l.buckets[userID] = bucket
+	}
+	bucket.tokens = l.refill(bucket, now)
+	bucket.updated = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+
+		return true, 0
+	}
+
+	missing := 1 - bucket.tokens
+
+	return false, time.Duration(missing / l.ratePerSecond * float64(time.Second))
+}
+
+func (l *UserRateLimiter) refill(bucket *tokenBucket, now time.Time) float64 {
+	elapsed := now.Sub(bucket.updated).Seconds()
+	if elapsed <= 0 {
+		return bucket.tokens
+	}
+
+	return min(l.burst, bucket.tokens+elapsed*l.ratePerSecond)
+}
+
+// sweep drops buckets that have refilled completely, since they behave like a fresh bucket
+func (l *UserRateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for userID, bucket := range l.buckets {
+		if l.refill(bucket, now) >= l.burst {
+			delete(l.buckets, userID)
+		}
+	}
+}
+
+// retryAfterHeaderSeconds rounds the wait up so clients never retry before a token is available
+func retryAfterHeaderSeconds(wait time.Duration) int {
+	return max(int(math.Ceil(wait.Seconds())), 1)
+}
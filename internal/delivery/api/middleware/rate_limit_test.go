@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"radar/config"
+	domainerrors "radar/internal/domain/errors"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitTestLimiter(rule config.RateLimitRule, now *time.Time) *UserRateLimiter {
+	limiter := NewUserRateLimiter(rule)
+	limiter.now = func() time.Time { return *now }
+
+	return limiter
+}
+
+func serveRateLimited(t *testing.T, limiter *UserRateLimiter, userID uuid.UUID) *httptest.ResponseRecorder {
+	t.Helper()
+
+	e := echo.New()
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/v1/notifications", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set(string(contextKeyUserID), userID)
+
+	err := limiter.Limit(func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})(c)
+	require.NoError(t, err)
+
+	return rec
+}
+
+func TestUserRateLimiter_RejectsUserOverLimit(t *testing.T) {
+	now := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	limiter := newRateLimitTestLimiter(config.RateLimitRule{RequestsPerMinute: 6, Burst: 2}, &now)
+	userID := uuid.New()
+
+	assert.Equal(t, http.StatusNoContent, serveRateLimited(t, limiter, userID).Code)
+	assert.Equal(t, http.StatusNoContent, serveRateLimited(t, limiter, userID).Code)
+
+	rec := serveRateLimited(t, limiter, userID)
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "10", rec.Header().Get("Retry-After"))
+	assert.Contains(t, rec.Body.String(), domainerrors.ErrRateLimitExceeded.ErrorCode())
+
+	// One token refills every ten seconds at six requests per minute
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, http.StatusNoContent, serveRateLimited(t, limiter, userID).Code)
+	assert.Equal(t, http.StatusTooManyRequests, serveRateLimited(t, limiter, userID).Code)
+}
+
+func TestUserRateLimiter_OtherUserUnaffected(t *testing.T) {
+	now := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	limiter := newRateLimitTestLimiter(config.RateLimitRule{RequestsPerMinute: 60, Burst: 1}, &now)
+	noisyUser := uuid.New()
+	quietUser := uuid.New()
+
+	assert.Equal(t, http.StatusNoContent, serveRateLimited(t, limiter, noisyUser).Code)
+	assert.Equal(t, http.StatusTooManyRequests, serveRateLimited(t, limiter, noisyUser).Code)
+
+	assert.Equal(t, http.StatusNoContent, serveRateLimited(t, limiter, quietUser).Code)
+}
+
+func TestUserRateLimiter_DisabledRuleAllowsAllRequests(t *testing.T) {
+	limiter := NewUserRateLimiter(config.RateLimitRule{})
+	userID := uuid.New()
+
+	assert.False(t, limiter.Enabled())
+	for range 5 {
+		assert.Equal(t, http.StatusNoContent, serveRateLimited(t, limiter, userID).Code)
+	}
+}
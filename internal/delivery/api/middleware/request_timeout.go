@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"radar/config"
+	domainerrors "radar/internal/domain/errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// anyMethod keys route timeouts that apply to every HTTP method
+const anyMethod = "*"
+
+// RequestTimeoutMiddleware bounds each handler's context with a per-route deadline.
+type RequestTimeoutMiddleware struct {
+	defaultTimeout time.Duration
+	routes         map[string]time.Duration // Keyed by routeTimeoutKey
+}
+
+// NewRequestTimeoutMiddleware creates the middleware from the configured handler timeouts.
+func NewRequestTimeoutMiddleware(cfg *config.Config) *RequestTimeoutMiddleware {
+	m := &RequestTimeoutMiddleware{routes: make(map[string]time.Duration)}
+	if cfg == nil {
+		return m
+	}
+
+	m.defaultTimeout = cfg.HTTP.Timeouts.Handler
+	for _, route := range cfg.HTTP.Timeouts.Routes {
+		method := strings.ToUpper(strings.TrimSpace(route.Method))
+		if method == "" {
+			method = anyMethod
+		}
+		m.routes[routeTimeoutKey(method, strings.TrimSpace(route.Path))] = route.Timeout
+	}
+
+	return m
+}
+
+// Apply runs the handler under the route's deadline. It must be registered with Echo#Use so the
+// matched route pattern is known. An error returned after the deadline passed is reported as a timeout.
+func (m *RequestTimeoutMiddleware) Apply(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		timeout := m.timeoutFor(c.Request().Method, c.Path())
+		if timeout <= 0 {
+			return next(c)
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+		defer cancel()
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		err := next(c)
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w: %w", domainerrors.ErrRequestTimeout, err)
+		}
+
+		return err
+	}
+}
+
+// timeoutFor returns the deadline for a route, preferring a method-specific override
+func (m *RequestTimeoutMiddleware) timeoutFor(method, path string) time.Duration {
+	if timeout, ok := m.routes[routeTimeoutKey(method, path)]; ok {
+		return timeout
+	}
+	if timeout, ok := m.routes[routeTimeoutKey(anyMethod, path)]; ok {
+		return timeout
+	}
+
+	return m.defaultTimeout
+}
+
+func routeTimeoutKey(method, path string) string {
+	return method + " " + path
+}
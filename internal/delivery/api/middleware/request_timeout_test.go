@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"radar/config"
+	domainerrors "radar/internal/domain/errors"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequestTimeoutTestEcho(handler time.Duration, routes ...config.RouteTimeout) *echo.Echo {
+	cfg := &config.Config{}
+	cfg.HTTP.Timeouts.Handler = handler
+	cfg.HTTP.Timeouts.Routes = routes
+
+	e := echo.New()
+	e.HTTPErrorHandler = NewErrorMiddleware(slog.New(slog.NewTextHandler(io.Discard, nil))).HandleHTTPError
+	e.Use(NewRequestTimeoutMiddleware(cfg).Apply)
+
+	return e
+}
+
+func TestRequestTimeoutMiddleware_CancelsContextAtRouteDeadline(t *testing.T) {
+	e := newRequestTimeoutTestEcho(time.Hour, config.RouteTimeout{
+		Method:  "get",
+		Path:    "/api/v1/notifications",
+		Timeout: 20 * time.Millisecond,
+	})
+
+	var started time.Time
+	var cancelledAfter time.Duration
+	var ctxErr error
+	e.GET("/api/v1/notifications", func(c echo.Context) error {
+		started = time.Now()
+		<-c.Request().Context().Done()
+		cancelledAfter = time.Since(started)
+		ctxErr = c.Request().Context().Err()
+
+		return ctxErr
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/v1/notifications", nil))
+
+	require.ErrorIs(t, ctxErr, context.DeadlineExceeded)
+	assert.GreaterOrEqual(t, cancelledAfter, 20*time.Millisecond)
+	assert.Less(t, cancelledAfter, time.Second)
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+	assert.Contains(t, rec.Body.String(), domainerrors.ErrRequestTimeout.ErrorCode())
+}
+
+func TestRequestTimeoutMiddleware_AppliesPerRouteBudgets(t *testing.T) {
+	e := newRequestTimeoutTestEcho(2*time.Second,
+		config.RouteTimeout{Path: "/api/v1/notifications", Timeout: 30 * time.Second},
+		config.RouteTimeout{Method: http.MethodPost, Path: "/api/v1/subscriptions", Timeout: time.Second},
+		config.RouteTimeout{Method: http.MethodGet, Path: "/api/v1/devices", Timeout: 0},
+	)
+
+	budgets := make(map[string]time.Duration)
+	record := func(c echo.Context) error {
+		deadline, ok := c.Request().Context().Deadline()
+		if ok {
+			budgets[c.Request().Method+" "+c.Path()] = time.Until(deadline)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+	e.GET("/api/v1/notifications", record)
+	e.POST("/api/v1/subscriptions", record)
+	e.GET("/api/v1/subscriptions", record)
+	e.GET("/api/v1/subscriptions/:merchantId/reachability", record)
+	e.GET("/api/v1/devices", record)
+
+	for _, target := range []struct{ method, path string }{
+		{http.MethodGet, "/api/v1/notifications"},
+		{http.MethodPost, "/api/v1/subscriptions"},
+		{http.MethodGet, "/api/v1/subscriptions"},
+		{http.MethodGet, "/api/v1/subscriptions/0b7f1c9e/reachability"},
+		{http.MethodGet, "/api/v1/devices"},
+	} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequestWithContext(context.Background(), target.method, target.path, nil))
+		require.Equal(t, http.StatusNoContent, rec.Code, target.path)
+	}
+
+	assert.InDelta(t, 30*time.Second, budgets["GET /api/v1/notifications"], float64(time.Second))
+	assert.InDelta(t, time.Second, budgets["POST /api/v1/subscriptions"], float64(500*time.Millisecond))
+	assert.InDelta(t, 2*time.Second, budgets["GET /api/v1/subscriptions"], float64(500*time.Millisecond))
+	assert.InDelta(t, 2*time.Second, budgets["GET /api/v1/subscriptions/:merchantId/reachability"], float64(500*time.Millisecond))
+	assert.NotContains(t, budgets, "GET /api/v1/devices", "a zero route timeout disables the deadline")
+}
+
+func TestRequestTimeoutMiddleware_KeepsErrorsBeforeDeadline(t *testing.T) {
+	e := newRequestTimeoutTestEcho(time.Hour)
+	e.GET("/api/v1/devices", func(echo.Context) error {
+		return domainerrors.ErrNotFound
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/v1/devices", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
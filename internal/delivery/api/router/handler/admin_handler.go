@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"radar/internal/delivery/api/response"
+	"radar/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+// AdminHandlerParams holds dependencies for AdminHandler, injected by Fx.
+type AdminHandlerParams struct {
+	fx.In
+
+	SessionUC usecase.SessionUsecase
+	RoutingUC usecase.RoutingUsecase
+	Logger    *slog.Logger
+}
+
+// AdminHandler holds dependencies for operator-only handlers
+type AdminHandler struct {
+	sessionUC usecase.SessionUsecase
+	routingUC usecase.RoutingUsecase
+	logger    *slog.Logger
+}
+
+// NewAdminHandler is the constructor for AdminHandler
+func NewAdminHandler(params AdminHandlerParams) *AdminHandler {
+	return &AdminHandler{
+		sessionUC: params.SessionUC,
+		routingUC: params.RoutingUC,
+		logger:    params.Logger,
+	}
+}
+
+// SessionCleanupResponse represents the result of a manual session cleanup
+type SessionCleanupResponse struct {
+	DeletedCount int `json:"deleted_count"`
+}
+
+// CleanupExpiredSessions handles on-demand removal of expired sessions
+func (h *AdminHandler) CleanupExpiredSessions(c echo.Context) error {
+	deletedCount, err := h.sessionUC.CleanupExpiredSessions(c.Request().Context())
+	if err != nil {
+		return withSourceStack(err)
+	}
+
+	return response.Success(c, http.StatusOK, SessionCleanupResponse{DeletedCount: deletedCount})
+}
+
+// GetRoutingMetadata reports which routing backend and dataset the server has loaded
+func (h *AdminHandler) GetRoutingMetadata(c echo.Context) error {
+	return response.Success(c, http.StatusOK, h.routingUC.Metadata())
+}
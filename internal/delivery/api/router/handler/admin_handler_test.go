@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"radar/internal/domain/entity"
+	"radar/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedSessionUsecase struct {
+	deletedCount int
+	err          error
+	calls        int
+}
+
+func (uc *fixedSessionUsecase) GetActiveSessions(context.Context, uuid.UUID) ([]*entity.SessionInfo, error) {
+	return nil, nil
+}
+
+func (uc *fixedSessionUsecase) RevokeSession(context.Context, uuid.UUID, uuid.UUID) error {
+	return nil
+}
+
+func (uc *fixedSessionUsecase) RevokeAllSessions(context.Context, uuid.UUID) error {
+	return nil
+}
+
+func (uc *fixedSessionUsecase) RevokeAllOtherSessions(context.Context, uuid.UUID, uuid.UUID) error {
+	return nil
+}
+
+func (uc *fixedSessionUsecase) GetSessionInfo(context.Context, uuid.UUID, uuid.UUID) (*entity.SessionInfo, error) {
+	return nil, nil
+}
+
+func (uc *fixedSessionUsecase) CleanupExpiredSessions(context.Context) (int, error) {
+	uc.calls++
+
+	return uc.deletedCount, uc.err
+}
+
+func TestAdminHandler_CleanupExpiredSessions_ReturnsDeletedCount(t *testing.T) {
+	sessionUC := &fixedSessionUsecase{deletedCount: 42}
+	handler := &AdminHandler{sessionUC: sessionUC}
+	c, rec := newJSONContext(http.MethodPost, "/admin/sessions/cleanup", "")
+
+	err := handler.CleanupExpiredSessions(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, sessionUC.calls)
+	assert.Contains(t, rec.Body.String(), `"deleted_count":42`)
+}
+
+func TestAdminHandler_CleanupExpiredSessions_PropagatesError(t *testing.T) {
+	handler := &AdminHandler{sessionUC: &fixedSessionUsecase{err: errors.New("cleanup failed")}}
+	c, rec := newJSONContext(http.MethodPost, "/admin/sessions/cleanup", "")
+
+	err := handler.CleanupExpiredSessions(c)
+	writeTestErrorResponse(c, err)
+
+	require.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "deleted_count")
+}
+
+// fixedRoutingMetadata serves fixed metadata; other routing methods are not used by the admin handler
+type fixedRoutingMetadata struct {
+	usecase.RoutingUsecase
+	metadata usecase.RoutingMetadata
+}
+
+func (r fixedRoutingMetadata) Metadata() usecase.RoutingMetadata {
+	return r.metadata
+}
+
+func TestAdminHandler_GetRoutingMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata usecase.RoutingMetadata
+		want     []string
+	}{
+		{
+			name: "ch dataset",
+			metadata: usecase.RoutingMetadata{
+				Backend:        "ch",
+				Ready:          true,
+				Region:         "taiwan",
+				OSMTimestamp:   time.Date(2026, 9, 30, 21, 0, 0, 0, time.UTC),
+				Profile:        "scooter",
+				VerticesCount:  1200,
+				EdgesCount:     3400,
+				ShortcutsCount: 560,
+			},
+			want: []string{
+				`"backend":"ch"`,
+				`"ready":true`,
+				`"region":"taiwan"`,
+				`"osm_timestamp":"2026-09-30T21:00:00Z"`,
+				`"profile":"scooter"`,
+				`"vertices_count":1200`,
+				`"edges_count":3400`,
+				`"shortcuts_count":560`,
+			},
+		},
+		{
+			name: "pmtiles archive",
+			metadata: usecase.RoutingMetadata{
+				Backend:   "pmtiles",
+				Ready:     true,
+				Source:    "s3://tiles/taiwan.pmtiles",
+				Tileset:   "taiwan",
+				RoadLayer: "transportation",
+				ZoomLevel: 14,
+			},
+			want: []string{
+				`"backend":"pmtiles"`,
+				`"source":"s3://tiles/taiwan.pmtiles"`,
+				`"tileset":"taiwan"`,
+				`"road_layer":"transportation"`,
+				`"zoom_level":14`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &AdminHandler{routingUC: fixedRoutingMetadata{metadata: tt.metadata}}
+			c, rec := newJSONContext(http.MethodGet, "/admin/routing/metadata", "")
+
+			err := handler.GetRoutingMetadata(c)
+
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			for _, field := range tt.want {
+				assert.Contains(t, rec.Body.String(), field)
+			}
+		})
+	}
+}
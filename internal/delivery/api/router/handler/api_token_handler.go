@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"radar/internal/delivery/api/middleware"
+	"radar/internal/delivery/api/response"
+	"radar/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+// APITokenHandlerParams holds dependencies for APITokenHandler, injected by Fx.
+type APITokenHandlerParams struct {
+	fx.In
+
+	APITokenUC usecase.APITokenUsecase
+	Logger     *slog.Logger
+}
+
+// APITokenHandler holds dependencies for merchant API token handlers
+type APITokenHandler struct {
+	apiTokenUC usecase.APITokenUsecase
+	logger     *slog.Logger
+}
+
+// NewAPITokenHandler is the constructor for APITokenHandler
+func NewAPITokenHandler(params APITokenHandlerParams) *APITokenHandler {
+	return &APITokenHandler{
+		apiTokenUC: params.APITokenUC,
+		logger:     params.Logger,
+	}
+}
+
+// CreateAPITokenRequest represents the request body for creating a merchant API token
+type CreateAPITokenRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+// CreateAPIToken handles issuing an API token for the merchant's own servers.
+// The raw token is only returned in this response.
+func (h *APITokenHandler) CreateAPIToken(c echo.Context) error {
+	merchantID, ok := middleware.GetUserID(c)
+	if !ok {
+		return response.InvalidToken(c)
+	}
+
+	var req CreateAPITokenRequest
+	if err := bindAndValidateRequest(c, &req, "Invalid API token input"); err != nil {
+		return err
+	}
+
+	token, err := h.apiTokenUC.CreateAPIToken(c.Request().Context(), merchantID, req.Name)
+	if err != nil {
+		return withSourceStack(err)
+	}
+
+	return response.Success(c, http.StatusCreated, token)
+}
+
+// ListAPITokens handles retrieving the merchant's API tokens
+func (h *APITokenHandler) ListAPITokens(c echo.Context) error {
+	merchantID, ok := middleware.GetUserID(c)
+	if !ok {
+		return response.InvalidToken(c)
+	}
+
+	tokens, err := h.apiTokenUC.ListAPITokens(c.Request().Context(), merchantID)
+	if err != nil {
+		return withSourceStack(err)
+	}
+
+	return response.Success(c, http.StatusOK, tokens)
+}
+
+// RevokeAPIToken handles revoking one of the merchant's API tokens
+func (h *APITokenHandler) RevokeAPIToken(c echo.Context) error {
+	merchantID, ok := middleware.GetUserID(c)
+	if !ok {
+		return response.InvalidToken(c)
+	}
+
+	tokenID, err := bindAPITokenIDPathParam(c, "Invalid API token ID")
+	if err != nil {
+		return err
+	}
+
+	if err := h.apiTokenUC.RevokeAPIToken(c.Request().Context(), merchantID, tokenID); err != nil {
+		return withSourceStack(err)
+	}
+
+	return response.Success(c, http.StatusOK, map[string]string{responseKeyMessage: "API token revoked successfully"})
+}
@@ -6,6 +6,7 @@ import (
 
 	"radar/internal/delivery/api/middleware"
 	"radar/internal/delivery/api/response"
+	"radar/internal/domain/entity"
 	"radar/internal/usecase"
 
 	"github.com/google/uuid"
@@ -37,9 +38,10 @@ func NewDeviceHandler(params DeviceHandlerParams) *DeviceHandler {
 
 // RegisterDeviceRequest represents the request body for registering a device
 type RegisterDeviceRequest struct {
-	FCMToken string `json:"fcm_token" validate:"required"`
-	DeviceID string `json:"device_id" validate:"required"`
-	Platform string `json:"platform" validate:"required,oneof=ios android"`
+	FCMToken   string `json:"fcm_token" validate:"required"`
+	DeviceID   string `json:"device_id" validate:"required"`
+	Platform   string `json:"platform" validate:"required,oneof=ios android web"`
+	AppVersion string `json:"app_version" validate:"omitempty,max=32"`
 }
 
 // UpdateFCMTokenRequest represents the request body for updating FCM token
@@ -47,6 +49,14 @@ type UpdateFCMTokenRequest struct {
 	FCMToken string `json:"fcm_token" validate:"required"`
 }
 
+// UpdateNotificationSettingsRequest represents the request body for a device's notification settings.
+// Omitted fields inherit the user's notification preferences.
+type UpdateNotificationSettingsRequest struct {
+	NotificationsEnabled *bool `json:"notifications_enabled"`
+	QuietHoursStart      *int  `json:"quiet_hours_start" validate:"omitempty,min=0,max=1439"`
+	QuietHoursEnd        *int  `json:"quiet_hours_end" validate:"omitempty,min=0,max=1439"`
+}
+
 // RegisterDevice handles device registration
 func (h *DeviceHandler) RegisterDevice(c echo.Context) error {
 	userID, ok := middleware.GetUserID(c)
@@ -60,9 +70,10 @@ func (h *DeviceHandler) RegisterDevice(c echo.Context) error {
 	}
 
 	deviceInfo := &usecase.DeviceInfo{
-		FCMToken: req.FCMToken,
-		DeviceID: req.DeviceID,
-		Platform: req.Platform,
+		FCMToken:   req.FCMToken,
+		DeviceID:   req.DeviceID,
+		Platform:   req.Platform,
+		AppVersion: req.AppVersion,
 	}
 
 	device, err := h.deviceUC.RegisterDevice(c.Request().Context(), userID, deviceInfo)
@@ -127,6 +138,38 @@ func (h *DeviceHandler) UpdateFCMToken(c echo.Context) error {
 	return response.Success(c, http.StatusOK, map[string]string{responseKeyMessage: "FCM token updated successfully"})
 }
 
+// UpdateNotificationSettings handles replacing a device's notification settings
+func (h *DeviceHandler) UpdateNotificationSettings(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return response.InvalidToken(c)
+	}
+
+	deviceID, err := h.parseDeviceID(c)
+	if err != nil {
+		return err
+	}
+
+	var req UpdateNotificationSettingsRequest
+	if err := bindAndValidateRequest(c, &req, "Invalid notification settings input"); err != nil {
+		return err
+	}
+	if (req.QuietHoursStart == nil) != (req.QuietHoursEnd == nil) {
+		return validationFailedError("quiet_hours_start and quiet_hours_end must be provided together")
+	}
+
+	settings := entity.DeviceNotificationSettings{
+		NotificationsEnabled: req.NotificationsEnabled,
+		QuietHoursStart:      req.QuietHoursStart,
+		QuietHoursEnd:        req.QuietHoursEnd,
+	}
+	if err := h.deviceUC.UpdateNotificationSettings(c.Request().Context(), userID, deviceID, settings); err != nil {
+		return withSourceStack(err)
+	}
+
+	return response.Success(c, http.StatusOK, map[string]string{responseKeyMessage: "Notification settings updated successfully"})
+}
+
 // DeactivateDevice handles deactivating a device
 func (h *DeviceHandler) DeactivateDevice(c echo.Context) error {
 	userID, ok := middleware.GetUserID(c)
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"fmt"
 	"log/slog"
 	"net/http"
 
@@ -10,6 +11,9 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+	"github.com/paulmach/orb/planar"
 	"go.uber.org/fx"
 )
 
@@ -43,6 +47,9 @@ type CreateLocationRequest struct {
 	Longitude   float64 `json:"longitude" validate:"required,min=-180,max=180"`
 	IsPrimary   bool    `json:"is_primary"`
 	IsActive    bool    `json:"is_active"`
+
+	// Optional GeoJSON Polygon that replaces the notification radius; user locations only
+	GeofencePolygon *geojson.Polygon `json:"geofence_polygon,omitempty"`
 }
 
 // UpdateLocationRequest represents the request body for updating a location
@@ -53,6 +60,71 @@ type UpdateLocationRequest struct {
 	Longitude   *float64 `json:"longitude,omitempty" validate:"omitempty,min=-180,max=180"`
 	IsPrimary   *bool    `json:"is_primary,omitempty"`
 	IsActive    *bool    `json:"is_active,omitempty"`
+
+	// GeofencePolygon replaces the location's geofence and ClearGeofence removes it
+	GeofencePolygon *geojson.Polygon `json:"geofence_polygon,omitempty"`
+	ClearGeofence   bool             `json:"clear_geofence,omitempty"`
+}
+
+// maxGeofenceVertices bounds the polygon a subscriber can store, which keeps every broadcast's
+// point-in-polygon checks cheap
+const maxGeofenceVertices = 500
+
+func (r *CreateLocationRequest) validateGeofence() error {
+	return validateGeofencePolygon(r.GeofencePolygon)
+}
+
+func (r *UpdateLocationRequest) validateGeofence() error {
+	if r.ClearGeofence && r.GeofencePolygon != nil {
+		return validationFailedError("geofence_polygon cannot be set together with clear_geofence")
+	}
+
+	return validateGeofencePolygon(r.GeofencePolygon)
+}
+
+// validateGeofencePolygon checks the rings of a geofence polygon. Self-intersections are left to the
+// database, whose validity check rejects them as an invalid geofence.
+func validateGeofencePolygon(polygon *geojson.Polygon) error {
+	if polygon == nil {
+		return nil
+	}
+	if len(*polygon) == 0 {
+		return validationFailedError("geofence_polygon needs an outer ring")
+	}
+
+	vertices := 0
+	for _, ring := range *polygon {
+		if len(ring) < 4 {
+			return validationFailedError("geofence_polygon rings need at least 4 positions")
+		}
+		if !ring.Closed() {
+			return validationFailedError("geofence_polygon rings must end at their first position")
+		}
+		for _, point := range ring {
+			if point.Lat() < -90 || point.Lat() > 90 || point.Lon() < -180 || point.Lon() > 180 {
+				return validationFailedError("geofence_polygon positions must be [longitude, latitude] within range")
+			}
+		}
+		vertices += len(ring)
+	}
+	if vertices > maxGeofenceVertices {
+		return validationFailedError(fmt.Sprintf("geofence_polygon must have at most %d positions", maxGeofenceVertices))
+	}
+	if planar.Area((*polygon)[0]) == 0 {
+		return validationFailedError("geofence_polygon must enclose an area")
+	}
+
+	return nil
+}
+
+// RoutePreviewQueryParams represents the query for previewing a route between two points
+type RoutePreviewQueryParams struct {
+	Src  string `query:"src" validate:"required"` // Route start as "lat,lng"
+	Dst  string `query:"dst" validate:"required"` // Route end as "lat,lng"
+	Unit string `query:"unit"`                    // Display unit, "km" or "mi"; defaults from Accept-Language
+
+	// Routing profile to route on, such as walking; omitted uses the backend's default profile
+	Profile string `query:"profile" validate:"omitempty,max=64"`
 }
 
 // CreateUserLocation handles creating a new user location
@@ -66,6 +138,9 @@ func (h *LocationHandler) CreateUserLocation(c echo.Context) error {
 	if err := bindAndValidateRequest(c, &req, "Invalid location input"); err != nil {
 		return err
 	}
+	if err := req.validateGeofence(); err != nil {
+		return err
+	}
 
 	location, err := h.locationUC.AddUserLocation(c.Request().Context(), userID, newAddLocationInput(&req))
 	if err != nil {
@@ -106,6 +181,9 @@ func (h *LocationHandler) UpdateUserLocation(c echo.Context) error {
 	if err := bindAndValidateRequest(c, &req, "Invalid location input"); err != nil {
 		return err
 	}
+	if err := req.validateGeofence(); err != nil {
+		return err
+	}
 
 	location, err := h.locationUC.UpdateUserLocation(c.Request().Context(), userID, locationID, newUpdateLocationInput(&req))
 	if err != nil {
@@ -145,6 +223,9 @@ func (h *LocationHandler) CreateMerchantLocation(c echo.Context) error {
 	if err := bindAndValidateRequest(c, &req, "Invalid location input"); err != nil {
 		return err
 	}
+	if err := req.validateGeofence(); err != nil {
+		return err
+	}
 
 	location, err := h.locationUC.AddMerchantLocation(c.Request().Context(), merchantID, newAddLocationInput(&req))
 	if err != nil {
@@ -185,6 +266,9 @@ func (h *LocationHandler) UpdateMerchantLocation(c echo.Context) error {
 	if err := bindAndValidateRequest(c, &req, "Invalid location input"); err != nil {
 		return err
 	}
+	if err := req.validateGeofence(); err != nil {
+		return err
+	}
 
 	location, err := h.locationUC.UpdateMerchantLocation(c.Request().Context(), merchantID, locationID, newUpdateLocationInput(&req))
 	if err != nil {
@@ -213,12 +297,80 @@ func (h *LocationHandler) DeleteMerchantLocation(c echo.Context) error {
 	return response.Success(c, http.StatusOK, map[string]string{responseKeyMessage: "Location deleted successfully"})
 }
 
+// RestoreMerchantLocation handles restoring a deleted merchant location
+func (h *LocationHandler) RestoreMerchantLocation(c echo.Context) error {
+	merchantID, ok := middleware.GetUserID(c)
+	if !ok {
+		return response.InvalidToken(c)
+	}
+
+	locationID, err := h.parseLocationID(c)
+	if err != nil {
+		return err
+	}
+
+	location, err := h.locationUC.RestoreMerchantLocation(c.Request().Context(), merchantID, locationID)
+	if err != nil {
+		return withSourceStack(err)
+	}
+
+	return response.Success(c, http.StatusOK, location)
+}
+
+// PreviewRoute handles previewing the road route between two points as a GeoJSON LineString feature.
+// The geometry is null when the target is unreachable. Reachable routes also carry their distance and ETA
+// formatted in the unit query parameter, or in the unit of the Accept-Language region when it is omitted.
+// The profile query parameter picks the road network, such as walking; an unknown profile is a validation error.
+func (h *LocationHandler) PreviewRoute(c echo.Context) error {
+	var query RoutePreviewQueryParams
+	if err := bindQueryParams(c, &query, "Invalid route preview query input"); err != nil {
+		return err
+	}
+	if err := c.Validate(&query); err != nil {
+		return validationFailedError(validationMessage(err, &query))
+	}
+
+	source, err := parseCoordinateQuery("src", query.Src)
+	if err != nil {
+		return err
+	}
+	target, err := parseCoordinateQuery("dst", query.Dst)
+	if err != nil {
+		return err
+	}
+	unit, err := resolveDistanceUnit(query.Unit, c.Request().Header.Get("Accept-Language"))
+	if err != nil {
+		return err
+	}
+
+	route, err := h.locationUC.PreviewRoute(c.Request().Context(), query.Profile, source, target)
+	if err != nil {
+		return withSourceStack(err)
+	}
+
+	return response.Success(c, http.StatusOK, newRouteFeature(route, unit))
+}
+
+// resolveDistanceUnit returns the unit named by the unit query parameter, or the Accept-Language unit without one
+func resolveDistanceUnit(value, acceptLanguage string) (usecase.DistanceUnit, error) {
+	if value == "" {
+		return usecase.DistanceUnitFromAcceptLanguage(acceptLanguage), nil
+	}
+
+	unit, ok := usecase.ParseDistanceUnit(value)
+	if !ok {
+		return "", validationFailedError("unit must be km or mi")
+	}
+
+	return unit, nil
+}
+
 func (h *LocationHandler) parseLocationID(c echo.Context) (uuid.UUID, error) {
 	return bindLocationIDPathParam(c, "Invalid location ID")
 }
 
 func newAddLocationInput(req *CreateLocationRequest) *usecase.AddLocationInput {
-	return &usecase.AddLocationInput{
+	input := &usecase.AddLocationInput{
 		Label:       req.Label,
 		FullAddress: req.FullAddress,
 		Latitude:    req.Latitude,
@@ -226,15 +378,56 @@ func newAddLocationInput(req *CreateLocationRequest) *usecase.AddLocationInput {
 		IsPrimary:   req.IsPrimary,
 		IsActive:    req.IsActive,
 	}
+	if req.GeofencePolygon != nil {
+		input.GeofencePolygon = *req.GeofencePolygon
+	}
+
+	return input
 }
 
 func newUpdateLocationInput(req *UpdateLocationRequest) *usecase.UpdateLocationInput {
-	return &usecase.UpdateLocationInput{
-		Label:       req.Label,
-		FullAddress: req.FullAddress,
-		Latitude:    req.Latitude,
-		Longitude:   req.Longitude,
-		IsPrimary:   req.IsPrimary,
-		IsActive:    req.IsActive,
+	input := &usecase.UpdateLocationInput{
+		Label:         req.Label,
+		FullAddress:   req.FullAddress,
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+		IsPrimary:     req.IsPrimary,
+		IsActive:      req.IsActive,
+		ClearGeofence: req.ClearGeofence,
+	}
+	if req.GeofencePolygon != nil {
+		input.GeofencePolygon = *req.GeofencePolygon
 	}
+
+	return input
+}
+
+// newRouteFeature converts a route into a GeoJSON feature whose properties carry the route metrics,
+// with the distance and ETA of a reachable route formatted in unit
+func newRouteFeature(route *usecase.RouteResult, unit usecase.DistanceUnit) *geojson.Feature {
+	var geometry orb.Geometry
+	if len(route.Geometry) >= 2 {
+		line := make(orb.LineString, len(route.Geometry))
+		for idx, coord := range route.Geometry {
+			line[idx] = orb.Point{coord.Lng, coord.Lat}
+		}
+		geometry = line
+	}
+
+	feature := geojson.NewFeature(geometry)
+	feature.Properties["distance_km"] = route.DistanceKm
+	feature.Properties["duration_min"] = route.DurationMin
+	feature.Properties["is_reachable"] = route.IsReachable
+	feature.Properties["is_estimate"] = route.IsEstimate
+	if route.IsReachable {
+		display := usecase.NewRouteDisplay(route, unit)
+		feature.Properties["unit"] = display.Unit
+		feature.Properties["distance_text"] = display.DistanceText
+		feature.Properties["duration_text"] = display.DurationText
+	}
+	if route.UnreachableReason != "" {
+		feature.Properties["unreachable_reason"] = route.UnreachableReason
+	}
+
+	return feature
 }
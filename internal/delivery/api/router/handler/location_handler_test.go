@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	domainerrors "radar/internal/domain/errors"
+	"radar/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedLocationUsecase struct {
+	usecase.LocationUsecase
+	route   *usecase.RouteResult
+	err     error
+	profile string
+	source  usecase.Coordinate
+	target  usecase.Coordinate
+	calls   int
+}
+
+func (uc *fixedLocationUsecase) PreviewRoute(_ context.Context, profile string, source, target usecase.Coordinate) (*usecase.RouteResult, error) {
+	uc.calls++
+	uc.profile = profile
+	uc.source = source
+	uc.target = target
+
+	return uc.route, uc.err
+}
+
+func TestLocationHandler_PreviewRoute_ReturnsLineString(t *testing.T) {
+	source := usecase.Coordinate{Lat: 25.03, Lng: 121.56}
+	target := usecase.Coordinate{Lat: 25.04, Lng: 121.57}
+	locationUC := &fixedLocationUsecase{route: &usecase.RouteResult{
+		Source:      source,
+		Target:      target,
+		DistanceKm:  1.6,
+		DurationMin: 3.2,
+		IsReachable: true,
+		Geometry:    []usecase.Coordinate{source, {Lat: 25.035, Lng: 121.56}, target},
+	}}
+	handler := &LocationHandler{locationUC: locationUC}
+	c, rec := newJSONContext(http.MethodGet, "/routes/preview?src=25.03,121.56&dst=25.04,121.57", "")
+
+	err := handler.PreviewRoute(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, locationUC.calls)
+	assert.Equal(t, usecase.DefaultRoutingProfile, locationUC.profile)
+	assert.Equal(t, source, locationUC.source)
+	assert.Equal(t, target, locationUC.target)
+
+	var body struct {
+		Data struct {
+			Type     string `json:"type"`
+			Geometry struct {
+				Type        string       `json:"type"`
+				Coordinates [][2]float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]any `json:"properties"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "Feature", body.Data.Type)
+	assert.Equal(t, "LineString", body.Data.Geometry.Type)
+	assert.Equal(t, [][2]float64{{121.56, 25.03}, {121.56, 25.035}, {121.57, 25.04}}, body.Data.Geometry.Coordinates)
+	assert.Equal(t, 1.6, body.Data.Properties["distance_km"])
+	assert.Equal(t, true, body.Data.Properties["is_reachable"])
+}
+
+func TestLocationHandler_PreviewRoute_FormatsDistanceInUnit(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		acceptLanguage string
+		wantUnit       string
+		wantDistance   string
+	}{
+		{name: "metric by default", query: "", wantUnit: "km", wantDistance: "1.6 km"},
+		{name: "miles from the query", query: "&unit=mi", wantUnit: "mi", wantDistance: "1.0 mi"},
+		{name: "miles from the language region", acceptLanguage: "en-US,en;q=0.9", wantUnit: "mi", wantDistance: "1.0 mi"},
+		{name: "query overrides the language region", query: "&unit=km", acceptLanguage: "en-GB", wantUnit: "km", wantDistance: "1.6 km"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locationUC := &fixedLocationUsecase{route: &usecase.RouteResult{DistanceKm: 1.6, DurationMin: 3.2, IsReachable: true}}
+			handler := &LocationHandler{locationUC: locationUC}
+			c, rec := newJSONContext(http.MethodGet, "/routes/preview?src=25.03,121.56&dst=25.04,121.57"+tt.query, "")
+			if tt.acceptLanguage != "" {
+				c.Request().Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			err := handler.PreviewRoute(c)
+
+			require.NoError(t, err)
+			var body struct {
+				Data struct {
+					Properties map[string]any `json:"properties"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			// The raw distance stays metric whatever the display unit
+			assert.Equal(t, 1.6, body.Data.Properties["distance_km"])
+			assert.Equal(t, tt.wantUnit, body.Data.Properties["unit"])
+			assert.Equal(t, tt.wantDistance, body.Data.Properties["distance_text"])
+			assert.Equal(t, "3 min", body.Data.Properties["duration_text"])
+		})
+	}
+}
+
+func TestLocationHandler_PreviewRoute_PassesProfile(t *testing.T) {
+	locationUC := &fixedLocationUsecase{route: &usecase.RouteResult{IsReachable: true}}
+	handler := &LocationHandler{locationUC: locationUC}
+	c, rec := newJSONContext(http.MethodGet, "/routes/preview?src=25.03,121.56&dst=25.04,121.57&profile=walking", "")
+
+	err := handler.PreviewRoute(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "walking", locationUC.profile)
+}
+
+func TestLocationHandler_PreviewRoute_UnreachableHasNullGeometry(t *testing.T) {
+	locationUC := &fixedLocationUsecase{route: &usecase.RouteResult{UnreachableReason: usecase.UnreachableReasonOffNetwork}}
+	handler := &LocationHandler{locationUC: locationUC}
+	c, rec := newJSONContext(http.MethodGet, "/routes/preview?src=25.03,121.56&dst=25.04,121.57", "")
+
+	err := handler.PreviewRoute(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"geometry":null`)
+	assert.Contains(t, rec.Body.String(), `"unreachable_reason":"off_network"`)
+}
+
+func TestLocationHandler_PreviewRoute_InvalidQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "missing src", query: "dst=25.04,121.57"},
+		{name: "missing dst", query: "src=25.03,121.56"},
+		{name: "malformed src", query: "src=25.03&dst=25.04,121.57"},
+		{name: "dst out of range", query: "src=25.03,121.56&dst=25.04,190"},
+		{name: "unsupported unit", query: "src=25.03,121.56&dst=25.04,121.57&unit=furlong"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locationUC := &fixedLocationUsecase{}
+			handler := &LocationHandler{locationUC: locationUC}
+			c, rec := newJSONContext(http.MethodGet, "/routes/preview?"+tt.query, "")
+
+			err := handler.PreviewRoute(c)
+			writeTestErrorResponse(c, err)
+
+			require.Error(t, err)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Zero(t, locationUC.calls)
+		})
+	}
+}
+
+func TestLocationHandler_PreviewRoute_RoutingFailure(t *testing.T) {
+	handler := &LocationHandler{locationUC: &fixedLocationUsecase{err: errors.New("routing service failed")}}
+	c, rec := newJSONContext(http.MethodGet, "/routes/preview?src=25.03,121.56&dst=25.04,121.57", "")
+
+	err := handler.PreviewRoute(c)
+	writeTestErrorResponse(c, err)
+
+	require.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestUpdateLocationRequest_ValidateGeofence(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{name: "no geofence", body: `{}`},
+		{name: "closed ring", body: `{"geofence_polygon":{"type":"Polygon","coordinates":[[[121.55,25.02],[121.58,25.02],[121.58,25.05],[121.55,25.02]]]}}`},
+		{name: "clear only", body: `{"clear_geofence":true}`},
+		{name: "clear and replace", body: `{"clear_geofence":true,"geofence_polygon":{"type":"Polygon","coordinates":[[[121.55,25.02],[121.58,25.02],[121.58,25.05],[121.55,25.02]]]}}`, wantErr: true},
+		{name: "no rings", body: `{"geofence_polygon":{"type":"Polygon","coordinates":[]}}`, wantErr: true},
+		{name: "open ring", body: `{"geofence_polygon":{"type":"Polygon","coordinates":[[[121.55,25.02],[121.58,25.02],[121.58,25.05],[121.56,25.03]]]}}`, wantErr: true},
+		{name: "too few positions", body: `{"geofence_polygon":{"type":"Polygon","coordinates":[[[121.55,25.02],[121.58,25.02],[121.55,25.02]]]}}`, wantErr: true},
+		{name: "latitude out of range", body: `{"geofence_polygon":{"type":"Polygon","coordinates":[[[121.55,95],[121.58,25.02],[121.58,25.05],[121.55,95]]]}}`, wantErr: true},
+		{name: "no area", body: `{"geofence_polygon":{"type":"Polygon","coordinates":[[[121.55,25.02],[121.56,25.02],[121.57,25.02],[121.55,25.02]]]}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req UpdateLocationRequest
+			require.NoError(t, json.Unmarshal([]byte(tt.body), &req))
+
+			err := req.validateGeofence()
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, domainerrors.ErrValidationFailed)
+
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
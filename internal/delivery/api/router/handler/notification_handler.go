@@ -1,12 +1,18 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"radar/internal/delivery/api/middleware"
 	"radar/internal/delivery/api/response"
+	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
 	"radar/internal/usecase"
 
 	"github.com/google/uuid"
@@ -18,21 +24,24 @@ import (
 type NotificationHandlerParams struct {
 	fx.In
 
-	NotificationUC usecase.NotificationUsecase
-	Logger         *slog.Logger
+	NotificationUC     usecase.NotificationUsecase
+	SubscriberMatrixUC usecase.SubscriberMatrixUsecase
+	Logger             *slog.Logger
 }
 
 // NotificationHandler holds dependencies for notification-related handlers
 type NotificationHandler struct {
-	notificationUC usecase.NotificationUsecase
-	logger         *slog.Logger
+	notificationUC     usecase.NotificationUsecase
+	subscriberMatrixUC usecase.SubscriberMatrixUsecase
+	logger             *slog.Logger
 }
 
 // NewNotificationHandler is the constructor for NotificationHandler
 func NewNotificationHandler(params NotificationHandlerParams) *NotificationHandler {
 	return &NotificationHandler{
-		notificationUC: params.NotificationUC,
-		logger:         params.Logger,
+		notificationUC:     params.NotificationUC,
+		subscriberMatrixUC: params.SubscriberMatrixUC,
+		logger:             params.Logger,
 	}
 }
 
@@ -41,6 +50,18 @@ type PublishNotificationRequest struct {
 	AddressID    *uuid.UUID            `json:"address_id,omitempty"`
 	LocationData *usecase.LocationData `json:"location_data,omitempty"`
 	HintMessage  string                `json:"hint_message,omitempty"`
+	ScheduledAt  *time.Time            `json:"scheduled_at,omitempty"` // Send later instead of now (RFC 3339)
+}
+
+// PublishMultiLocationRequest represents the request body for publishing several locations at once
+type PublishMultiLocationRequest struct {
+	Locations []PublishNotificationRequest `json:"locations"`
+}
+
+// PublishLocationResultResponse reports the outcome of one location of a multi-location publish
+type PublishLocationResultResponse struct {
+	Notification *entity.MerchantLocationNotification `json:"notification,omitempty"`
+	Error        *response.ErrorInfo                  `json:"error,omitempty"`
 }
 
 const (
@@ -53,6 +74,21 @@ type NotificationHistoryQueryParams struct {
 	LimitOffsetQueryParams
 }
 
+type SubscriberSnapshotQueryParams struct {
+	AddressID string `query:"address_id" validate:"required,uuid"`
+}
+
+// BroadcastStatsQueryParams represents the inclusive range of UTC days for a merchant's broadcast stats
+type BroadcastStatsQueryParams struct {
+	From string `query:"from" validate:"required"` // First day as YYYY-MM-DD
+	To   string `query:"to" validate:"required"`   // Last day as YYYY-MM-DD
+}
+
+// SubscriptionReachabilityQueryParams represents the query for a subscriber's reachability self-check
+type SubscriptionReachabilityQueryParams struct {
+	From string `query:"from" validate:"required"` // Merchant location as "lat,lng"
+}
+
 // PublishLocationNotification handles publishing a location notification
 func (h *NotificationHandler) PublishLocationNotification(c echo.Context) error {
 	merchantID, ok := middleware.GetUserID(c)
@@ -70,13 +106,24 @@ func (h *NotificationHandler) PublishLocationNotification(c echo.Context) error
 		return err
 	}
 
-	notification, err := h.notificationUC.PublishLocationNotification(
-		c.Request().Context(),
-		merchantID,
-		req.AddressID,
-		req.LocationData,
-		req.HintMessage,
-	)
+	var notification *entity.MerchantLocationNotification
+	var err error
+	if req.ScheduledAt != nil {
+		notification, err = h.notificationUC.ScheduleLocationNotification(c.Request().Context(), merchantID, usecase.PublishLocationInput{
+			AddressID:    req.AddressID,
+			LocationData: req.LocationData,
+			HintMessage:  req.HintMessage,
+			ScheduledAt:  req.ScheduledAt,
+		})
+	} else {
+		notification, err = h.notificationUC.PublishLocationNotification(
+			c.Request().Context(),
+			merchantID,
+			req.AddressID,
+			req.LocationData,
+			req.HintMessage,
+		)
+	}
 	if err != nil {
 		return withSourceStack(err)
 	}
@@ -84,6 +131,77 @@ func (h *NotificationHandler) PublishLocationNotification(c echo.Context) error
 	return response.Success(c, http.StatusCreated, notification)
 }
 
+// PublishMultiLocation handles publishing notifications for several locations in one request
+func (h *NotificationHandler) PublishMultiLocation(c echo.Context) error {
+	merchantID, ok := middleware.GetUserID(c)
+	if !ok {
+		return response.InvalidToken(c)
+	}
+
+	var req PublishMultiLocationRequest
+	if err := bindRequest(c, &req, "Invalid notification input"); err != nil {
+		return err
+	}
+	if len(req.Locations) == 0 {
+		return validationFailedError("at least one location must be provided")
+	}
+
+	inputs := make([]usecase.PublishLocationInput, 0, len(req.Locations))
+	for idx := range req.Locations {
+		location := &req.Locations[idx]
+		if err := h.validatePublishNotificationRequest(location); err != nil {
+			if appErr, ok := errors.AsType[domainerrors.AppError](err); ok {
+				return validationFailedError(fmt.Sprintf("locations[%d]: %s", idx, appErr.Details()))
+			}
+
+			return err
+		}
+		inputs = append(inputs, usecase.PublishLocationInput{
+			AddressID:    location.AddressID,
+			LocationData: location.LocationData,
+			HintMessage:  location.HintMessage,
+			ScheduledAt:  location.ScheduledAt,
+		})
+	}
+
+	results, err := h.notificationUC.PublishMultiLocation(c.Request().Context(), merchantID, inputs)
+	if err != nil {
+		return withSourceStack(err)
+	}
+
+	resp := make([]PublishLocationResultResponse, 0, len(results))
+	for _, result := range results {
+		resp = append(resp, PublishLocationResultResponse{
+			Notification: result.Notification,
+			Error:        publishLocationErrorInfo(result.Err),
+		})
+	}
+
+	return response.Success(c, http.StatusOK, resp)
+}
+
+// publishLocationErrorInfo renders a per-location failure, hiding details of unexpected errors
+func publishLocationErrorInfo(err error) *response.ErrorInfo {
+	if err == nil {
+		return nil
+	}
+
+	appErr, ok := errors.AsType[domainerrors.AppError](err)
+	if !ok || appErr.HTTPCode() >= http.StatusInternalServerError {
+		return &response.ErrorInfo{
+			Code:    domainerrors.ErrInternalError.ErrorCode(),
+			Message: domainerrors.ErrInternalError.Message(),
+		}
+	}
+
+	info := &response.ErrorInfo{Code: appErr.ErrorCode(), Message: appErr.Message()}
+	if details := appErr.Details(); details != "" && appErr.HTTPCode() != http.StatusUnauthorized && appErr.HTTPCode() != http.StatusForbidden {
+		info.Details = details
+	}
+
+	return info
+}
+
 // validatePublishNotificationRequest validates the publish notification request
 func (h *NotificationHandler) validatePublishNotificationRequest(req *PublishNotificationRequest) error {
 	// Validate that either addressID or locationData is provided
@@ -109,9 +227,6 @@ func (h *NotificationHandler) validateLocationData(data *usecase.LocationData) e
 	if data.LocationName == "" {
 		return validationFailedError("location_name is required in location_data")
 	}
-	if data.FullAddress == "" {
-		return validationFailedError("full_address is required in location_data")
-	}
 	if data.Latitude < -90 || data.Latitude > 90 {
 		return validationFailedError("latitude must be between -90 and 90")
 	}
@@ -142,6 +257,188 @@ func (h *NotificationHandler) GetMerchantNotificationHistory(c echo.Context) err
 	return response.Success(c, http.StatusOK, notifications)
 }
 
+// GetMerchantBroadcastStats handles retrieving a merchant's daily broadcast reach over a range of days
+func (h *NotificationHandler) GetMerchantBroadcastStats(c echo.Context) error {
+	merchantID, ok := middleware.GetUserID(c)
+	if !ok {
+		return response.InvalidToken(c)
+	}
+
+	var query BroadcastStatsQueryParams
+	if err := bindQueryParams(c, &query, "Invalid broadcast stats query input"); err != nil {
+		return err
+	}
+	if err := c.Validate(&query); err != nil {
+		return validationFailedError(validationMessage(err, &query))
+	}
+
+	from, fromErr := time.Parse(time.DateOnly, query.From)
+	to, toErr := time.Parse(time.DateOnly, query.To)
+	if fromErr != nil || toErr != nil {
+		return validationFailedError("from and to must be formatted as YYYY-MM-DD")
+	}
+
+	stats, err := h.notificationUC.GetMerchantBroadcastStats(c.Request().Context(), merchantID, from, to)
+	if err != nil {
+		return withSourceStack(err)
+	}
+
+	return response.Success(c, http.StatusOK, stats)
+}
+
+// GetMerchantLocationReach handles counting the subscribers each of the merchant's locations would reach
+func (h *NotificationHandler) GetMerchantLocationReach(c echo.Context) error {
+	merchantID, ok := middleware.GetUserID(c)
+	if !ok {
+		return response.InvalidToken(c)
+	}
+
+	reach, err := h.subscriberMatrixUC.GetMerchantLocationReach(c.Request().Context(), merchantID)
+	if err != nil {
+		return withSourceStack(err)
+	}
+
+	return response.Success(c, http.StatusOK, reach)
+}
+
+// GetSubscriberSnapshots handles retrieving routing diagnostics for subscribers around a merchant address
+func (h *NotificationHandler) GetSubscriberSnapshots(c echo.Context) error {
+	merchantID, ok := middleware.GetUserID(c)
+	if !ok {
+		return response.InvalidToken(c)
+	}
+
+	var query SubscriberSnapshotQueryParams
+	if err := bindQueryParams(c, &query, "Invalid subscriber snapshot query input"); err != nil {
+		return err
+	}
+	if err := c.Validate(&query); err != nil {
+		return validationFailedError(validationMessage(err, &query))
+	}
+
+	addressID, err := uuid.Parse(query.AddressID)
+	if err != nil {
+		return validationFailedError("address_id must be a valid UUID")
+	}
+
+	snapshots, err := h.notificationUC.GetSubscriberSnapshots(c.Request().Context(), merchantID, addressID)
+	if err != nil {
+		return withSourceStack(err)
+	}
+
+	return response.Success(c, http.StatusOK, snapshots)
+}
+
+// GetSubscriptionReachability handles a subscriber checking whether they would be notified from a merchant location
+func (h *NotificationHandler) GetSubscriptionReachability(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return response.InvalidToken(c)
+	}
+
+	merchantID, err := bindMerchantIDPathParam(c, "Invalid merchant ID")
+	if err != nil {
+		return err
+	}
+
+	var query SubscriptionReachabilityQueryParams
+	if err := bindQueryParams(c, &query, "Invalid reachability query input"); err != nil {
+		return err
+	}
+	if err := c.Validate(&query); err != nil {
+		return validationFailedError(validationMessage(err, &query))
+	}
+
+	from, err := parseCoordinateQuery("from", query.From)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := h.notificationUC.GetSubscriptionReachability(c.Request().Context(), userID, merchantID, from)
+	if err != nil {
+		return withSourceStack(err)
+	}
+
+	return response.Success(c, http.StatusOK, snapshots)
+}
+
+// CancelScheduledNotification handles a merchant canceling a scheduled notification before it is sent
+func (h *NotificationHandler) CancelScheduledNotification(c echo.Context) error {
+	merchantID, ok := middleware.GetUserID(c)
+	if !ok {
+		return response.InvalidToken(c)
+	}
+
+	notificationID, err := bindNotificationIDPathParam(c, "Invalid notification ID")
+	if err != nil {
+		return err
+	}
+
+	if err := h.notificationUC.CancelScheduledNotification(c.Request().Context(), merchantID, notificationID); err != nil {
+		return withSourceStack(err)
+	}
+
+	return response.Success(c, http.StatusOK, map[string]string{responseKeyMessage: "Scheduled notification canceled"})
+}
+
+// GetReceivedNotifications handles a subscriber listing the notifications delivered to their devices
+func (h *NotificationHandler) GetReceivedNotifications(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return response.InvalidToken(c)
+	}
+
+	query, err := h.parseNotificationHistoryQueryParams(c)
+	if err != nil {
+		return err
+	}
+
+	received, err := h.notificationUC.GetReceivedNotifications(c.Request().Context(), userID, query.Limit, query.Offset)
+	if err != nil {
+		return withSourceStack(err)
+	}
+
+	return response.Success(c, http.StatusOK, received)
+}
+
+// RecordNotificationOpened handles a subscriber reporting that they opened a received notification
+func (h *NotificationHandler) RecordNotificationOpened(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return response.InvalidToken(c)
+	}
+
+	notificationID, err := bindNotificationIDPathParam(c, "Invalid notification ID")
+	if err != nil {
+		return err
+	}
+
+	if err := h.notificationUC.RecordNotificationOpened(c.Request().Context(), userID, notificationID); err != nil {
+		return withSourceStack(err)
+	}
+
+	return response.Success(c, http.StatusOK, map[string]string{responseKeyMessage: "Notification open recorded"})
+}
+
+// parseCoordinateQuery parses the "lat,lng" value of the named query parameter into a coordinate
+func parseCoordinateQuery(name, value string) (usecase.Coordinate, error) {
+	latText, lngText, found := strings.Cut(value, ",")
+	if !found {
+		return usecase.Coordinate{}, validationFailedError(name + " must be formatted as lat,lng")
+	}
+
+	lat, latErr := strconv.ParseFloat(strings.TrimSpace(latText), 64)
+	lng, lngErr := strconv.ParseFloat(strings.TrimSpace(lngText), 64)
+	if latErr != nil || lngErr != nil {
+		return usecase.Coordinate{}, validationFailedError(name + " must be formatted as lat,lng")
+	}
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return usecase.Coordinate{}, validationFailedError(name + " is outside the valid coordinate range")
+	}
+
+	return usecase.Coordinate{Lat: lat, Lng: lng}, nil
+}
+
 func (h *NotificationHandler) parseNotificationHistoryQueryParams(c echo.Context) (NotificationHistoryQueryParams, error) {
 	query := newNotificationHistoryQueryParams()
 
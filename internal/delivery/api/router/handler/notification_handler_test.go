@@ -0,0 +1,463 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
+	"radar/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedNotificationUsecase struct {
+	results    []*usecase.PublishLocationResult
+	inputs     []usecase.PublishLocationInput
+	snapshots  []*usecase.SubscriberSnapshot
+	received   []*entity.ReceivedNotification
+	stats      *usecase.MerchantBroadcastStats
+	err        error
+	userID     uuid.UUID
+	merchantID uuid.UUID
+	from       usecase.Coordinate
+	calls      int
+	limit      int
+	offset     int
+
+	notificationID uuid.UUID
+	statsFrom      time.Time
+	statsTo        time.Time
+}
+
+func (uc *fixedNotificationUsecase) PublishLocationNotification(context.Context, uuid.UUID, *uuid.UUID, *usecase.LocationData, string) (*entity.MerchantLocationNotification, error) {
+	return nil, nil
+}
+
+func (uc *fixedNotificationUsecase) ScheduleLocationNotification(
+	_ context.Context,
+	merchantID uuid.UUID,
+	input usecase.PublishLocationInput,
+) (*entity.MerchantLocationNotification, error) {
+	uc.calls++
+	uc.merchantID = merchantID
+	uc.inputs = []usecase.PublishLocationInput{input}
+
+	return &entity.MerchantLocationNotification{ScheduledAt: input.ScheduledAt, ScheduleStatus: entity.ScheduleStatusPending}, uc.err
+}
+
+func (uc *fixedNotificationUsecase) CancelScheduledNotification(_ context.Context, merchantID, notificationID uuid.UUID) error {
+	uc.calls++
+	uc.merchantID = merchantID
+	uc.notificationID = notificationID
+
+	return uc.err
+}
+
+func (uc *fixedNotificationUsecase) PublishMultiLocation(
+	_ context.Context,
+	merchantID uuid.UUID,
+	inputs []usecase.PublishLocationInput,
+) ([]*usecase.PublishLocationResult, error) {
+	uc.calls++
+	uc.merchantID = merchantID
+	uc.inputs = inputs
+
+	return uc.results, uc.err
+}
+
+func (uc *fixedNotificationUsecase) GetMerchantNotificationHistory(context.Context, uuid.UUID, int, int) ([]*entity.MerchantLocationNotification, error) {
+	return nil, nil
+}
+
+func (uc *fixedNotificationUsecase) GetSubscriberSnapshots(context.Context, uuid.UUID, uuid.UUID) ([]*usecase.SubscriberSnapshot, error) {
+	return nil, nil
+}
+
+func (uc *fixedNotificationUsecase) GetSubscriptionReachability(
+	_ context.Context,
+	userID, merchantID uuid.UUID,
+	from usecase.Coordinate,
+) ([]*usecase.SubscriberSnapshot, error) {
+	uc.calls++
+	uc.userID = userID
+	uc.merchantID = merchantID
+	uc.from = from
+
+	return uc.snapshots, uc.err
+}
+
+func (uc *fixedNotificationUsecase) GetMerchantBroadcastStats(_ context.Context, merchantID uuid.UUID, from, to time.Time) (*usecase.MerchantBroadcastStats, error) {
+	uc.calls++
+	uc.merchantID = merchantID
+	uc.statsFrom, uc.statsTo = from, to
+
+	return uc.stats, uc.err
+}
+
+func (uc *fixedNotificationUsecase) GetReceivedNotifications(_ context.Context, userID uuid.UUID, limit, offset int) ([]*entity.ReceivedNotification, error) {
+	uc.calls++
+	uc.userID = userID
+	uc.limit, uc.offset = limit, offset
+
+	return uc.received, uc.err
+}
+
+func (uc *fixedNotificationUsecase) RecordNotificationOpened(_ context.Context, userID, notificationID uuid.UUID) error {
+	uc.calls++
+	uc.userID = userID
+	uc.notificationID = notificationID
+
+	return uc.err
+}
+
+func TestNotificationHandler_GetSubscriptionReachability_Reachable(t *testing.T) {
+	userID := uuid.New()
+	merchantID := uuid.New()
+	notificationUC := &fixedNotificationUsecase{snapshots: []*usecase.SubscriberSnapshot{{
+		AddressID:          uuid.New(),
+		SnapNode:           &usecase.NodeInfo{ID: 42, Location: usecase.Coordinate{Lat: 25.03, Lng: 121.56}},
+		DistanceKm:         0.8,
+		DurationMin:        2.5,
+		IsReachable:        true,
+		NotificationRadius: 1000,
+		WithinRadius:       true,
+	}}}
+	handler := &NotificationHandler{notificationUC: notificationUC}
+	c, rec := newJSONContext(http.MethodGet, "/subscriptions/"+merchantID.String()+"/reachability?from=25.03,121.56", "")
+	c.SetParamNames("merchantId")
+	c.SetParamValues(merchantID.String())
+	c.Set("userID", userID)
+
+	err := handler.GetSubscriptionReachability(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, notificationUC.calls)
+	assert.Equal(t, userID, notificationUC.userID)
+	assert.Equal(t, merchantID, notificationUC.merchantID)
+	assert.Equal(t, usecase.Coordinate{Lat: 25.03, Lng: 121.56}, notificationUC.from)
+	assert.Contains(t, rec.Body.String(), `"is_reachable":true`)
+	assert.Contains(t, rec.Body.String(), `"within_radius":true`)
+}
+
+func TestNotificationHandler_GetSubscriptionReachability_Unreachable(t *testing.T) {
+	merchantID := uuid.New()
+	notificationUC := &fixedNotificationUsecase{snapshots: []*usecase.SubscriberSnapshot{{
+		AddressID:          uuid.New(),
+		IsReachable:        false,
+		NotificationRadius: 1000,
+		WithinRadius:       false,
+	}}}
+	handler := &NotificationHandler{notificationUC: notificationUC}
+	c, rec := newJSONContext(http.MethodGet, "/subscriptions/"+merchantID.String()+"/reachability?from=25.03,121.56", "")
+	c.SetParamNames("merchantId")
+	c.SetParamValues(merchantID.String())
+	c.Set("userID", uuid.New())
+
+	err := handler.GetSubscriptionReachability(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "snap_node")
+	assert.Contains(t, rec.Body.String(), `"is_reachable":false`)
+	assert.Contains(t, rec.Body.String(), `"within_radius":false`)
+}
+
+func TestNotificationHandler_GetSubscriptionReachability_NotSubscribed(t *testing.T) {
+	merchantID := uuid.New()
+	handler := &NotificationHandler{notificationUC: &fixedNotificationUsecase{err: domainerrors.ErrSubscriptionNotFound}}
+	c, rec := newJSONContext(http.MethodGet, "/subscriptions/"+merchantID.String()+"/reachability?from=25.03,121.56", "")
+	c.SetParamNames("merchantId")
+	c.SetParamValues(merchantID.String())
+	c.Set("userID", uuid.New())
+
+	err := handler.GetSubscriptionReachability(c)
+	writeTestErrorResponse(c, err)
+
+	require.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestNotificationHandler_GetSubscriptionReachability_InvalidFrom(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+	}{
+		{name: "missing", from: ""},
+		{name: "single value", from: "25.03"},
+		{name: "not a number", from: "north,121.56"},
+		{name: "out of range", from: "95,121.56"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merchantID := uuid.New()
+			notificationUC := &fixedNotificationUsecase{}
+			handler := &NotificationHandler{notificationUC: notificationUC}
+			c, rec := newJSONContext(http.MethodGet, "/subscriptions/"+merchantID.String()+"/reachability?from="+tt.from, "")
+			c.SetParamNames("merchantId")
+			c.SetParamValues(merchantID.String())
+			c.Set("userID", uuid.New())
+
+			err := handler.GetSubscriptionReachability(c)
+			writeTestErrorResponse(c, err)
+
+			require.Error(t, err)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Zero(t, notificationUC.calls)
+		})
+	}
+}
+
+func TestNotificationHandler_PublishMultiLocation_ReturnsPerLocationResults(t *testing.T) {
+	merchantID := uuid.New()
+	notificationID := uuid.New()
+	notificationUC := &fixedNotificationUsecase{results: []*usecase.PublishLocationResult{
+		{Notification: &entity.MerchantLocationNotification{ID: notificationID, MerchantID: merchantID}},
+		{Err: domainerrors.ErrAddressNotFound},
+		{Err: errors.New("connection reset by peer")},
+	}}
+	handler := &NotificationHandler{notificationUC: notificationUC}
+	addressID := uuid.New()
+	body := `{"locations":[
+		{"location_data":{"location_name":"Stall A","full_address":"1 Market St","latitude":25.0,"longitude":121.0},"hint_message":"open now"},
+		{"address_id":"` + addressID.String() + `"},
+		{"location_data":{"location_name":"Stall C","full_address":"5 Market St","latitude":25.005,"longitude":121.005}}
+	]}`
+	c, rec := newJSONContext(http.MethodPost, "/notifications/batch", body)
+	c.Set("userID", merchantID)
+
+	err := handler.PublishMultiLocation(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, merchantID, notificationUC.merchantID)
+	require.Len(t, notificationUC.inputs, 3)
+	assert.Equal(t, "open now", notificationUC.inputs[0].HintMessage)
+	require.NotNil(t, notificationUC.inputs[1].AddressID)
+	assert.Equal(t, addressID, *notificationUC.inputs[1].AddressID)
+
+	var payload struct {
+		Data []struct {
+			Notification *struct {
+				ID uuid.UUID `json:"id"`
+			} `json:"notification"`
+			Error *struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &payload))
+	require.Len(t, payload.Data, 3)
+	require.NotNil(t, payload.Data[0].Notification)
+	assert.Equal(t, notificationID, payload.Data[0].Notification.ID)
+	assert.Nil(t, payload.Data[0].Error)
+	require.NotNil(t, payload.Data[1].Error)
+	assert.Equal(t, domainerrors.ErrAddressNotFound.ErrorCode(), payload.Data[1].Error.Code)
+	require.NotNil(t, payload.Data[2].Error)
+	assert.Equal(t, domainerrors.ErrInternalError.ErrorCode(), payload.Data[2].Error.Code)
+	assert.NotContains(t, rec.Body.String(), "connection reset")
+}
+
+func TestNotificationHandler_PublishMultiLocation_RejectsInvalidLocation(t *testing.T) {
+	notificationUC := &fixedNotificationUsecase{}
+	handler := &NotificationHandler{notificationUC: notificationUC}
+	body := `{"locations":[
+		{"location_data":{"location_name":"Stall A","full_address":"1 Market St","latitude":25.0,"longitude":121.0}},
+		{"hint_message":"missing location"}
+	]}`
+	c, rec := newJSONContext(http.MethodPost, "/notifications/batch", body)
+	c.Set("userID", uuid.New())
+
+	err := handler.PublishMultiLocation(c)
+	writeTestErrorResponse(c, err)
+
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "locations[1]")
+	assert.Zero(t, notificationUC.calls)
+}
+
+func TestNotificationHandler_RecordNotificationOpened(t *testing.T) {
+	userID := uuid.New()
+	notificationID := uuid.New()
+	notificationUC := &fixedNotificationUsecase{}
+	handler := &NotificationHandler{notificationUC: notificationUC}
+	c, rec := newJSONContext(http.MethodPost, "/subscriptions/notifications/"+notificationID.String()+"/opened", "")
+	c.SetParamNames("notificationId")
+	c.SetParamValues(notificationID.String())
+	c.Set("userID", userID)
+
+	err := handler.RecordNotificationOpened(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, notificationUC.calls)
+	assert.Equal(t, userID, notificationUC.userID)
+	assert.Equal(t, notificationID, notificationUC.notificationID)
+}
+
+func TestNotificationHandler_GetReceivedNotifications(t *testing.T) {
+	userID := uuid.New()
+	notificationUC := &fixedNotificationUsecase{received: []*entity.ReceivedNotification{
+		{NotificationID: uuid.New(), StoreName: "Night Market Bao", LocationName: "Gate 2", Status: "sent"},
+		{NotificationID: uuid.New(), StoreName: "Night Market Bao", LocationName: "Gate 3", Status: "failed"},
+	}}
+	handler := &NotificationHandler{notificationUC: notificationUC}
+	c, rec := newJSONContext(http.MethodGet, "/subscriptions/notifications?limit=5&offset=10", "")
+	c.Set("userID", userID)
+
+	err := handler.GetReceivedNotifications(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, userID, notificationUC.userID)
+	assert.Equal(t, 5, notificationUC.limit)
+	assert.Equal(t, 10, notificationUC.offset)
+	assert.Contains(t, rec.Body.String(), `"store_name":"Night Market Bao"`)
+	assert.Contains(t, rec.Body.String(), `"status":"failed"`)
+}
+
+func TestNotificationHandler_GetMerchantBroadcastStats(t *testing.T) {
+	merchantID := uuid.New()
+	notificationUC := &fixedNotificationUsecase{stats: &usecase.MerchantBroadcastStats{TotalBroadcasts: 3, TotalRecipients: 45, AverageRecipients: 15}}
+	handler := &NotificationHandler{notificationUC: notificationUC}
+	c, rec := newJSONContext(http.MethodGet, "/notifications/stats?from=2026-10-01&to=2026-10-07", "")
+	c.Set("userID", merchantID)
+
+	err := handler.GetMerchantBroadcastStats(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, merchantID, notificationUC.merchantID)
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), notificationUC.statsFrom)
+	assert.Equal(t, time.Date(2026, 10, 7, 0, 0, 0, 0, time.UTC), notificationUC.statsTo)
+	assert.Contains(t, rec.Body.String(), `"average_recipients":15`)
+}
+
+func TestNotificationHandler_GetMerchantBroadcastStats_InvalidDate(t *testing.T) {
+	for _, target := range []string{
+		"/notifications/stats?from=2026-10-01",
+		"/notifications/stats?from=2026-10-01&to=10/07/2026",
+	} {
+		t.Run(target, func(t *testing.T) {
+			notificationUC := &fixedNotificationUsecase{}
+			handler := &NotificationHandler{notificationUC: notificationUC}
+			c, rec := newJSONContext(http.MethodGet, target, "")
+			c.Set("userID", uuid.New())
+
+			err := handler.GetMerchantBroadcastStats(c)
+			writeTestErrorResponse(c, err)
+
+			require.Error(t, err)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Zero(t, notificationUC.calls)
+		})
+	}
+}
+
+func TestNotificationHandler_RecordNotificationOpened_NotReceived(t *testing.T) {
+	notificationID := uuid.New()
+	handler := &NotificationHandler{notificationUC: &fixedNotificationUsecase{err: domainerrors.ErrNotificationNotFound}}
+	c, rec := newJSONContext(http.MethodPost, "/subscriptions/notifications/"+notificationID.String()+"/opened", "")
+	c.SetParamNames("notificationId")
+	c.SetParamValues(notificationID.String())
+	c.Set("userID", uuid.New())
+
+	err := handler.RecordNotificationOpened(c)
+	writeTestErrorResponse(c, err)
+
+	require.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestNotificationHandler_PublishLocationNotification_Scheduled(t *testing.T) {
+	merchantID := uuid.New()
+	addressID := uuid.New()
+	notificationUC := &fixedNotificationUsecase{}
+	handler := &NotificationHandler{notificationUC: notificationUC}
+	body := `{"address_id":"` + addressID.String() + `","scheduled_at":"2026-10-15T09:00:00Z"}`
+	c, rec := newJSONContext(http.MethodPost, "/notifications", body)
+	c.Set("userID", merchantID)
+
+	err := handler.PublishLocationNotification(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	require.Equal(t, 1, notificationUC.calls)
+	assert.Equal(t, merchantID, notificationUC.merchantID)
+	require.Len(t, notificationUC.inputs, 1)
+	assert.Equal(t, &addressID, notificationUC.inputs[0].AddressID)
+	require.NotNil(t, notificationUC.inputs[0].ScheduledAt)
+	assert.Equal(t, "2026-10-15T09:00:00Z", notificationUC.inputs[0].ScheduledAt.Format(time.RFC3339))
+	assert.Contains(t, rec.Body.String(), `"schedule_status":"pending"`)
+}
+
+func TestNotificationHandler_CancelScheduledNotification(t *testing.T) {
+	merchantID := uuid.New()
+	notificationID := uuid.New()
+	notificationUC := &fixedNotificationUsecase{}
+	handler := &NotificationHandler{notificationUC: notificationUC}
+	c, rec := newJSONContext(http.MethodPost, "/notifications/"+notificationID.String()+"/cancel", "")
+	c.SetParamNames("notificationId")
+	c.SetParamValues(notificationID.String())
+	c.Set("userID", merchantID)
+
+	err := handler.CancelScheduledNotification(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, merchantID, notificationUC.merchantID)
+	assert.Equal(t, notificationID, notificationUC.notificationID)
+}
+
+func TestNotificationHandler_CancelScheduledNotification_OtherMerchant(t *testing.T) {
+	notificationID := uuid.New()
+	handler := &NotificationHandler{notificationUC: &fixedNotificationUsecase{err: domainerrors.ErrNotificationOwnershipViolation}}
+	c, rec := newJSONContext(http.MethodPost, "/notifications/"+notificationID.String()+"/cancel", "")
+	c.SetParamNames("notificationId")
+	c.SetParamValues(notificationID.String())
+	c.Set("userID", uuid.New())
+
+	err := handler.CancelScheduledNotification(c)
+	writeTestErrorResponse(c, err)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+type fixedSubscriberMatrixUsecase struct {
+	usecase.SubscriberMatrixUsecase
+	reach      []*usecase.LocationReach
+	merchantID uuid.UUID
+}
+
+func (uc *fixedSubscriberMatrixUsecase) GetMerchantLocationReach(_ context.Context, merchantID uuid.UUID) ([]*usecase.LocationReach, error) {
+	uc.merchantID = merchantID
+
+	return uc.reach, nil
+}
+
+func TestNotificationHandler_GetMerchantLocationReach(t *testing.T) {
+	merchantID := uuid.New()
+	matrixUC := &fixedSubscriberMatrixUsecase{reach: []*usecase.LocationReach{
+		{AddressID: uuid.New(), Label: "Market", Candidates: 2, Reachable: 1},
+	}}
+	handler := &NotificationHandler{subscriberMatrixUC: matrixUC}
+	c, rec := newJSONContext(http.MethodGet, "/notifications/location-reach", "")
+	c.Set("userID", merchantID)
+
+	err := handler.GetMerchantLocationReach(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, merchantID, matrixUC.merchantID)
+	assert.Contains(t, rec.Body.String(), `"candidates":2`)
+	assert.Contains(t, rec.Body.String(), `"reachable":1`)
+}
@@ -113,6 +113,14 @@ func bindDeviceIDPathParam(c echo.Context, invalidMessage string) (uuid.UUID, er
 	return bindUUIDPathParam(c, "deviceId", invalidMessage)
 }
 
+func bindNotificationIDPathParam(c echo.Context, invalidMessage string) (uuid.UUID, error) {
+	return bindUUIDPathParam(c, "notificationId", invalidMessage)
+}
+
+func bindAPITokenIDPathParam(c echo.Context, invalidMessage string) (uuid.UUID, error) {
+	return bindUUIDPathParam(c, "tokenId", invalidMessage)
+}
+
 func bindUUIDPathParam(c echo.Context, paramName, invalidMessage string) (uuid.UUID, error) {
 	value := strings.TrimSpace(c.Param(paramName))
 	if value == "" {
@@ -3,6 +3,7 @@ package handler
 import (
 	"log/slog"
 	"net/http"
+	"time"
 
 	"radar/internal/delivery/api/middleware"
 	"radar/internal/delivery/api/response"
@@ -47,6 +48,16 @@ type ProcessQRRequest struct {
 	DeviceInfo *usecase.DeviceInfo `json:"device_info,omitempty"`
 }
 
+// SnoozeRequest represents the request body for snoozing broadcasts; zero minutes clears the snooze
+type SnoozeRequest struct {
+	DurationMinutes int `json:"duration_minutes" validate:"min=0,max=10080"` // Up to 7 days
+}
+
+// UpdateRadiusRequest represents the request body for updating the notification radius of every subscription
+type UpdateRadiusRequest struct {
+	RadiusKm float64 `json:"radius_km" validate:"gt=0"`
+}
+
 // SubscribeToMerchant handles subscribing to a merchant
 func (h *SubscriptionHandler) SubscribeToMerchant(c echo.Context) error {
 	userID, ok := middleware.GetUserID(c)
@@ -86,6 +97,70 @@ func (h *SubscriptionHandler) UnsubscribeFromMerchant(c echo.Context) error {
 	return response.Success(c, http.StatusOK, map[string]string{responseKeyMessage: "Unsubscribed successfully"})
 }
 
+// SnoozeSubscription handles snoozing broadcasts from one merchant
+func (h *SubscriptionHandler) SnoozeSubscription(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return response.InvalidToken(c)
+	}
+
+	merchantID, err := h.parseMerchantID(c)
+	if err != nil {
+		return err
+	}
+
+	var req SnoozeRequest
+	if err := bindAndValidateRequest(c, &req, "Invalid snooze input"); err != nil {
+		return err
+	}
+
+	subscription, err := h.subscriptionUC.SnoozeSubscription(c.Request().Context(), userID, merchantID, snoozeUntil(req))
+	if err != nil {
+		return withSourceStack(err)
+	}
+
+	return response.Success(c, http.StatusOK, subscription)
+}
+
+// SnoozeAllSubscriptions handles snoozing broadcasts from every subscribed merchant
+func (h *SubscriptionHandler) SnoozeAllSubscriptions(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return response.InvalidToken(c)
+	}
+
+	var req SnoozeRequest
+	if err := bindAndValidateRequest(c, &req, "Invalid snooze input"); err != nil {
+		return err
+	}
+
+	if err := h.subscriptionUC.SnoozeAllSubscriptions(c.Request().Context(), userID, snoozeUntil(req)); err != nil {
+		return withSourceStack(err)
+	}
+
+	return response.Success(c, http.StatusOK, map[string]string{responseKeyMessage: "Broadcasts snoozed successfully"})
+}
+
+// UpdateAllSubscriptionRadii handles updating the notification radius of every active subscription
+func (h *SubscriptionHandler) UpdateAllSubscriptionRadii(c echo.Context) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return response.InvalidToken(c)
+	}
+
+	var req UpdateRadiusRequest
+	if err := bindAndValidateRequest(c, &req, "Invalid radius input"); err != nil {
+		return err
+	}
+
+	updated, err := h.subscriptionUC.UpdateAllSubscriptionRadii(c.Request().Context(), userID, req.RadiusKm)
+	if err != nil {
+		return withSourceStack(err)
+	}
+
+	return response.Success(c, http.StatusOK, map[string]int64{"updated": updated})
+}
+
 // GetUserSubscriptions handles retrieving all user subscriptions
 func (h *SubscriptionHandler) GetUserSubscriptions(c echo.Context) error {
 	userID, ok := middleware.GetUserID(c)
@@ -140,6 +215,15 @@ func (h *SubscriptionHandler) ProcessQRSubscription(c echo.Context) error {
 	return response.Success(c, http.StatusCreated, subscription)
 }
 
+// snoozeUntil converts a snooze duration into an end time, using the zero time to clear the snooze
+func snoozeUntil(req SnoozeRequest) time.Time {
+	if req.DurationMinutes == 0 {
+		return time.Time{}
+	}
+
+	return time.Now().Add(time.Duration(req.DurationMinutes) * time.Minute)
+}
+
 func (h *SubscriptionHandler) parseMerchantID(c echo.Context) (uuid.UUID, error) {
 	return bindMerchantIDPathParam(c, "Invalid merchant ID")
 }
@@ -21,6 +21,8 @@ type RouterParams struct {
 	SubscriptionHandler *handler.SubscriptionHandler
 	NotificationHandler *handler.NotificationHandler
 	DiscoveryHandler    *handler.DiscoveryHandler
+	AdminHandler        *handler.AdminHandler
+	APITokenHandler     *handler.APITokenHandler
 	AuthMiddleware      *middleware.AuthMiddleware
 	Config              *config.Config
 }
@@ -35,13 +37,25 @@ type router struct {
 	subscriptionHandler *handler.SubscriptionHandler
 	notificationHandler *handler.NotificationHandler
 	discoveryHandler    *handler.DiscoveryHandler
+	adminHandler        *handler.AdminHandler
+	apiTokenHandler     *handler.APITokenHandler
 	authMiddleware      *middleware.AuthMiddleware
 	config              *config.Config
+
+	// Per-user limits for the authenticated route groups
+	notificationLimiter *middleware.UserRateLimiter
+	subscriptionLimiter *middleware.UserRateLimiter
+	locationLimiter     *middleware.UserRateLimiter
 }
 
 // NewRouter is the constructor for the Router.
 // Fx will inject the required handlers here.
 func NewRouter(params RouterParams) *router {
+	var rateLimits config.RateLimitConfig
+	if params.Config != nil && params.Config.RateLimit != nil {
+		rateLimits = *params.Config.RateLimit
+	}
+
 	return &router{
 		userHandler:         params.UserHandler,
 		testHandler:         params.TestHandler,
@@ -51,8 +65,13 @@ func NewRouter(params RouterParams) *router {
 		subscriptionHandler: params.SubscriptionHandler,
 		notificationHandler: params.NotificationHandler,
 		discoveryHandler:    params.DiscoveryHandler,
+		adminHandler:        params.AdminHandler,
+		apiTokenHandler:     params.APITokenHandler,
 		authMiddleware:      params.AuthMiddleware,
 		config:              params.Config,
+		notificationLimiter: middleware.NewUserRateLimiter(rateLimits.Notifications),
+		subscriptionLimiter: middleware.NewUserRateLimiter(rateLimits.Subscriptions),
+		locationLimiter:     middleware.NewUserRateLimiter(rateLimits.Locations),
 	}
 }
 
@@ -64,6 +83,7 @@ func (r *router) RegisterRoutes(e *echo.Echo) {
 	r.registerPublicRoutes(e)
 	r.registerAuthenticatedRootRoutes(e)
 	r.registerAPIV1Routes(e)
+	r.registerAPITokenRoutes(e)
 }
 
 func (r *router) registerPublicRoutes(e *echo.Echo) {
@@ -97,6 +117,14 @@ func (r *router) registerAuthenticatedRootRoutes(e *echo.Echo) {
 	{
 		// Reserved for non-versioned merchant-only routes.
 	}
+
+	adminGroup := e.Group("/admin")
+	adminGroup.Use(r.authMiddleware.Authenticate)
+	adminGroup.Use(r.authMiddleware.RequireRole(entity.RoleAdmin))
+	{
+		adminGroup.POST("/sessions/cleanup", r.adminHandler.CleanupExpiredSessions)
+		adminGroup.GET("/routing/metadata", r.adminHandler.GetRoutingMetadata)
+	}
 }
 
 func (r *router) registerAPIV1Routes(e *echo.Echo) {
@@ -116,6 +144,7 @@ func (r *router) registerAPIV1UserRoutes(apiV1 *echo.Group) {
 	}
 
 	locationsGroup := apiV1.Group("/locations")
+	locationsGroup.Use(r.locationLimiter.Limit)
 	{
 		locationsGroup.POST("/user", r.locationHandler.CreateUserLocation)
 		locationsGroup.GET("/user", r.locationHandler.GetUserLocations)
@@ -129,15 +158,23 @@ func (r *router) registerAPIV1UserRoutes(apiV1 *echo.Group) {
 		devicesGroup.GET("", r.deviceHandler.GetUserDevices)
 		devicesGroup.GET("/health", r.deviceHandler.GetDeviceHealth)
 		devicesGroup.PUT("/:deviceId/token", r.deviceHandler.UpdateFCMToken)
+		devicesGroup.PUT("/:deviceId/notification-settings", r.deviceHandler.UpdateNotificationSettings)
 		devicesGroup.DELETE("/:deviceId", r.deviceHandler.DeactivateDevice)
 	}
 
 	subscriptionsGroup := apiV1.Group("/subscriptions")
+	subscriptionsGroup.Use(r.subscriptionLimiter.Limit)
 	{
 		subscriptionsGroup.POST("", r.subscriptionHandler.SubscribeToMerchant)
 		subscriptionsGroup.DELETE("/:merchantId", r.subscriptionHandler.UnsubscribeFromMerchant)
 		subscriptionsGroup.GET("", r.subscriptionHandler.GetUserSubscriptions)
 		subscriptionsGroup.POST("/qr", r.subscriptionHandler.ProcessQRSubscription)
+		subscriptionsGroup.PUT("/snooze", r.subscriptionHandler.SnoozeAllSubscriptions)
+		subscriptionsGroup.PUT("/radius", r.subscriptionHandler.UpdateAllSubscriptionRadii)
+		subscriptionsGroup.PUT("/:merchantId/snooze", r.subscriptionHandler.SnoozeSubscription)
+		subscriptionsGroup.GET("/:merchantId/reachability", r.notificationHandler.GetSubscriptionReachability)
+		subscriptionsGroup.GET("/notifications", r.notificationHandler.GetReceivedNotifications)
+		subscriptionsGroup.POST("/notifications/:notificationId/opened", r.notificationHandler.RecordNotificationOpened)
 	}
 }
 
@@ -149,13 +186,21 @@ func (r *router) registerAPIV1SharedRoutes(apiV1 *echo.Group) {
 		discoveryGroup.GET("/hubs", r.discoveryHandler.ListActiveHubs)
 	}
 
+	routesGroup := apiV1.Group("/routes")
+	routesGroup.Use(r.locationLimiter.Limit)
+	{
+		routesGroup.GET("/preview", r.locationHandler.PreviewRoute)
+	}
+
 	locationsGroup := apiV1.Group("/locations/merchant")
 	locationsGroup.Use(r.authMiddleware.RequireRole(entity.RoleMerchant))
+	locationsGroup.Use(r.locationLimiter.Limit)
 	{
 		locationsGroup.POST("", r.locationHandler.CreateMerchantLocation)
 		locationsGroup.GET("", r.locationHandler.GetMerchantLocations)
 		locationsGroup.PUT("/:locationId", r.locationHandler.UpdateMerchantLocation)
 		locationsGroup.DELETE("/:locationId", r.locationHandler.DeleteMerchantLocation)
+		locationsGroup.POST("/:locationId/restore", r.locationHandler.RestoreMerchantLocation)
 	}
 }
 
@@ -178,6 +223,9 @@ func (r *router) registerAPIV1MerchantRoutes(apiV1 *echo.Group) {
 		merchantGroup.POST("/verification", r.userHandler.SubmitMerchantVerification)
 		merchantGroup.GET("/discovery-profile", r.userHandler.GetMerchantDiscoveryProfile)
 		merchantGroup.PATCH("/discovery-profile", r.userHandler.UpdateMerchantDiscoveryProfile)
+		merchantGroup.POST("/api-tokens", r.apiTokenHandler.CreateAPIToken)
+		merchantGroup.GET("/api-tokens", r.apiTokenHandler.ListAPITokens)
+		merchantGroup.DELETE("/api-tokens/:tokenId", r.apiTokenHandler.RevokeAPIToken)
 	}
 
 	merchantMenusGroup := apiV1.Group("/menus/merchant")
@@ -193,12 +241,27 @@ func (r *router) registerAPIV1MerchantRoutes(apiV1 *echo.Group) {
 
 	notificationsGroup := apiV1.Group("/notifications")
 	notificationsGroup.Use(r.authMiddleware.RequireRole(entity.RoleMerchant))
+	notificationsGroup.Use(r.notificationLimiter.Limit)
 	{
-		notificationsGroup.POST("", r.notificationHandler.PublishLocationNotification)
+		notificationsGroup.POST("/batch", r.notificationHandler.PublishMultiLocation)
 		notificationsGroup.GET("", r.notificationHandler.GetMerchantNotificationHistory)
+		notificationsGroup.GET("/stats", r.notificationHandler.GetMerchantBroadcastStats)
+		notificationsGroup.GET("/location-reach", r.notificationHandler.GetMerchantLocationReach)
+		notificationsGroup.GET("/subscriber-snapshots", r.notificationHandler.GetSubscriberSnapshots)
+		notificationsGroup.POST("/:notificationId/cancel", r.notificationHandler.CancelScheduledNotification)
 	}
 }
 
+// registerAPITokenRoutes registers the routes merchant API tokens may call, outside the JWT-only /api/v1 group.
+// Each route also accepts a merchant JWT.
+func (r *router) registerAPITokenRoutes(e *echo.Echo) {
+	e.POST("/api/v1/notifications", r.notificationHandler.PublishLocationNotification,
+		r.authMiddleware.AuthenticateWithAPIToken(entity.APITokenScopePublishNotifications),
+		r.authMiddleware.RequireRole(entity.RoleMerchant),
+		r.notificationLimiter.Limit,
+	)
+}
+
 func (r *router) RegisterTestRoutes(e *echo.Echo) {
 	// Test routes - only enabled when configured
 	if r.config.TestRoutes != nil && r.config.TestRoutes.Enabled {
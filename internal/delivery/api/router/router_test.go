@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	apimiddleware "radar/internal/delivery/api/middleware"
 	"radar/internal/delivery/api/router/handler"
 	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
 	"radar/internal/domain/service"
 	"radar/internal/usecase"
 
@@ -25,8 +27,15 @@ import (
 const (
 	testUserToken     = "user-token"
 	testMerchantToken = "merchant-token"
+	testAdminToken    = "admin-token"
+
+	testAPIToken           = entity.APITokenPrefix + "publish"
+	testRevokedAPIToken    = entity.APITokenPrefix + "revoked"
+	testWrongScopeAPIToken = entity.APITokenPrefix + "no-scope"
 )
 
+var testAPITokenMerchantID = uuid.New()
+
 type routerTestTokenService struct {
 	claims map[string]*service.Claims
 }
@@ -64,6 +73,42 @@ func (s *routerTestTokenService) RotateTokens(uuid.UUID, []string) (string, stri
 	return "", "", "", nil
 }
 
+// routerTestAPITokenUsecase only authenticates the fixed test API tokens
+type routerTestAPITokenUsecase struct {
+	usecase.APITokenUsecase
+}
+
+func (routerTestAPITokenUsecase) AuthenticateAPIToken(_ context.Context, rawToken string) (*entity.APIToken, error) {
+	switch rawToken {
+	case testAPIToken:
+		return &entity.APIToken{
+			ID:         uuid.New(),
+			MerchantID: testAPITokenMerchantID,
+			Scopes:     []entity.APITokenScope{entity.APITokenScopePublishNotifications},
+		}, nil
+	case testWrongScopeAPIToken:
+		return &entity.APIToken{ID: uuid.New(), MerchantID: testAPITokenMerchantID, Scopes: []entity.APITokenScope{"menus:write"}}, nil
+	default:
+		// Revoked and unknown tokens are rejected alike
+		return nil, domainerrors.ErrInvalidToken
+	}
+}
+
+// routerTestNotificationUsecase echoes the publishing merchant back
+type routerTestNotificationUsecase struct {
+	usecase.NotificationUsecase
+}
+
+func (routerTestNotificationUsecase) PublishLocationNotification(
+	_ context.Context,
+	merchantID uuid.UUID,
+	addressID *uuid.UUID,
+	_ *usecase.LocationData,
+	_ string,
+) (*entity.MerchantLocationNotification, error) {
+	return &entity.MerchantLocationNotification{MerchantID: merchantID, AddressID: addressID}, nil
+}
+
 type routerTestDiscoveryUsecase struct{}
 
 func (uc *routerTestDiscoveryUsecase) ListActiveCategories(context.Context) (*usecase.ListDiscoveryCategoriesResult, error) {
@@ -98,6 +143,41 @@ func (uc *routerTestDiscoveryUsecase) SearchPublicMerchants(
 	}, nil
 }
 
+type routerTestSessionUsecase struct{}
+
+func (uc *routerTestSessionUsecase) GetActiveSessions(context.Context, uuid.UUID) ([]*entity.SessionInfo, error) {
+	return nil, nil
+}
+
+func (uc *routerTestSessionUsecase) RevokeSession(context.Context, uuid.UUID, uuid.UUID) error {
+	return nil
+}
+
+func (uc *routerTestSessionUsecase) RevokeAllSessions(context.Context, uuid.UUID) error {
+	return nil
+}
+
+func (uc *routerTestSessionUsecase) RevokeAllOtherSessions(context.Context, uuid.UUID, uuid.UUID) error {
+	return nil
+}
+
+func (uc *routerTestSessionUsecase) GetSessionInfo(context.Context, uuid.UUID, uuid.UUID) (*entity.SessionInfo, error) {
+	return nil, nil
+}
+
+func (uc *routerTestSessionUsecase) CleanupExpiredSessions(context.Context) (int, error) {
+	return 7, nil
+}
+
+// routerTestRoutingUsecase only answers metadata requests
+type routerTestRoutingUsecase struct {
+	usecase.RoutingUsecase
+}
+
+func (routerTestRoutingUsecase) Metadata() usecase.RoutingMetadata {
+	return usecase.RoutingMetadata{Backend: "haversine", Ready: true}
+}
+
 type routerTestProfileUsecase struct{}
 
 func (uc *routerTestProfileUsecase) GetProfile(_ context.Context, userID uuid.UUID) (*entity.User, error) {
@@ -206,6 +286,117 @@ func TestRouter_ProfileSupportsAPIV1AndLegacyRoutes(t *testing.T) {
 	}
 }
 
+func TestRouter_AdminSessionCleanupRequiresAdminRole(t *testing.T) {
+	e := newRouterTestEcho()
+
+	for _, tt := range []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{name: "anonymous", token: "", status: http.StatusUnauthorized},
+		{name: "user", token: testUserToken, status: http.StatusForbidden},
+		{name: "merchant", token: testMerchantToken, status: http.StatusForbidden},
+		{name: "admin", token: testAdminToken, status: http.StatusOK},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newRouterTestRequest(http.MethodPost, "/admin/sessions/cleanup", tt.token)
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.status, rec.Code)
+			if tt.status == http.StatusOK {
+				assert.Contains(t, rec.Body.String(), `"deleted_count":7`)
+			}
+		})
+	}
+}
+
+func TestRouter_AdminRoutingMetadataRequiresAdminRole(t *testing.T) {
+	e := newRouterTestEcho()
+
+	for _, tt := range []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{name: "anonymous", token: "", status: http.StatusUnauthorized},
+		{name: "user", token: testUserToken, status: http.StatusForbidden},
+		{name: "merchant", token: testMerchantToken, status: http.StatusForbidden},
+		{name: "admin", token: testAdminToken, status: http.StatusOK},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newRouterTestRequest(http.MethodGet, "/admin/routing/metadata", tt.token)
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.status, rec.Code)
+			if tt.status == http.StatusOK {
+				assert.Contains(t, rec.Body.String(), `"backend":"haversine"`)
+			}
+		})
+	}
+}
+
+func TestRouter_PublishNotificationAcceptsMerchantAPIToken(t *testing.T) {
+	e := newRouterTestEcho()
+
+	for _, tt := range []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{name: "valid api token", token: testAPIToken, status: http.StatusCreated},
+		{name: "revoked api token", token: testRevokedAPIToken, status: http.StatusUnauthorized},
+		{name: "api token without the publish scope", token: testWrongScopeAPIToken, status: http.StatusForbidden},
+		{name: "merchant jwt", token: testMerchantToken, status: http.StatusCreated},
+		{name: "user jwt", token: testUserToken, status: http.StatusForbidden},
+		{name: "anonymous", token: "", status: http.StatusUnauthorized},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequestWithContext(context.Background(), http.MethodPost, "/api/v1/notifications",
+				strings.NewReader(`{"address_id":"`+uuid.NewString()+`"}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if tt.token != "" {
+				req.Header.Set(echo.HeaderAuthorization, "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.status, rec.Code, rec.Body.String())
+			if tt.token == testAPIToken {
+				assert.Contains(t, rec.Body.String(), `"merchant_id":"`+testAPITokenMerchantID.String()+`"`)
+			}
+		})
+	}
+}
+
+func TestRouter_APITokenRejectedOutsidePublish(t *testing.T) {
+	e := newRouterTestEcho()
+
+	for _, tt := range []struct {
+		method string
+		path   string
+	}{
+		{method: http.MethodGet, path: "/api/v1/notifications"},
+		{method: http.MethodGet, path: "/api/v1/merchant/api-tokens"},
+		{method: http.MethodGet, path: "/api/v1/discovery/categories"},
+	} {
+		t.Run(tt.path, func(t *testing.T) {
+			req := newRouterTestRequest(tt.method, tt.path, testAPIToken)
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, http.StatusUnauthorized, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"code":"INVALID_TOKEN"`)
+		})
+	}
+}
+
 func newRouterTestEcho() *echo.Echo {
 	userID := uuid.New()
 	tokenSvc := &routerTestTokenService{
@@ -220,11 +411,16 @@ func newRouterTestEcho() *echo.Echo {
 				Roles:  []string{string(entity.RoleMerchant)},
 				Type:   service.TokenTypeAccess,
 			},
+			testAdminToken: {
+				UserID: uuid.New(),
+				Roles:  []string{string(entity.RoleAdmin)},
+				Type:   service.TokenTypeAccess,
+			},
 		},
 	}
 
 	e := echo.New()
-	authMiddleware := apimiddleware.NewAuthMiddleware(tokenSvc, &config.Config{})
+	authMiddleware := apimiddleware.NewAuthMiddleware(tokenSvc, routerTestAPITokenUsecase{}, &config.Config{})
 	r := NewRouter(RouterParams{
 		UserHandler: handler.NewUserHandler(handler.UserHandlerParams{
 			ProfileUC: &routerTestProfileUsecase{},
@@ -234,6 +430,15 @@ func newRouterTestEcho() *echo.Echo {
 			DiscoveryUC: &routerTestDiscoveryUsecase{},
 			Logger:      slog.Default(),
 		}),
+		NotificationHandler: handler.NewNotificationHandler(handler.NotificationHandlerParams{
+			NotificationUC: routerTestNotificationUsecase{},
+			Logger:         slog.Default(),
+		}),
+		AdminHandler: handler.NewAdminHandler(handler.AdminHandlerParams{
+			SessionUC: &routerTestSessionUsecase{},
+			RoutingUC: routerTestRoutingUsecase{},
+			Logger:    slog.Default(),
+		}),
 		AuthMiddleware: authMiddleware,
 		Config:         &config.Config{},
 	})
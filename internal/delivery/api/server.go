@@ -13,11 +13,14 @@ import (
 	apimiddleware "radar/internal/delivery/api/middleware"
 	"radar/internal/delivery/api/router"
 	"radar/internal/delivery/api/validator"
+	"radar/internal/delivery/health"
 	"radar/internal/delivery/middleware"
 	"radar/internal/domain/lifecycle"
 
 	"github.com/labstack/echo/v4"
 	echomiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/fx"
 	"golang.org/x/net/http2"
 )
@@ -32,10 +35,14 @@ type apiServer struct {
 type ServerParams struct {
 	fx.In
 
-	Lc           fx.Lifecycle
-	Cfg          *config.Config
-	Logger       *slog.Logger
-	RouterParams router.RouterParams
+	Lc            fx.Lifecycle
+	Cfg           *config.Config
+	Logger        *slog.Logger
+	RouterParams  router.RouterParams
+	HealthHandler *health.Handler
+
+	// Registry served on /metrics; the endpoint is not registered without one
+	MetricsRegistry *prometheus.Registry `optional:"true"`
 }
 
 func NewServer(params ServerParams) (delivery.Delivery, error) {
@@ -78,9 +85,21 @@ func NewServer(params ServerParams) (delivery.Delivery, error) {
 	// 8. Keep a bounded JSON body copy for sanitized error-only request logging.
 	echoServer.Use(apimiddleware.CaptureRequestBodyForErrorLog)
 
+	// 9. Per-route handler context deadlines
+	requestTimeout := apimiddleware.NewRequestTimeoutMiddleware(params.Cfg)
+	echoServer.Use(requestTimeout.Apply)
+
 	// Set up validator
 	echoServer.Validator = validator.New()
 
+	if params.MetricsRegistry != nil {
+		echoServer.GET("/metrics", echo.WrapHandler(promhttp.HandlerFor(params.MetricsRegistry, promhttp.HandlerOpts{})))
+	}
+
+	// Liveness and readiness probes
+	echoServer.GET("/healthz", params.HealthHandler.Live)
+	echoServer.GET("/readyz", params.HealthHandler.Ready)
+
 	r := router.NewRouter(params.RouterParams)
 	r.RegisterRoutes(echoServer)
 	r.RegisterTestRoutes(echoServer)
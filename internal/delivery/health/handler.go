@@ -0,0 +1,154 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"radar/config"
+	"radar/internal/domain/constants"
+	"radar/internal/domain/repository"
+	"radar/internal/domain/service"
+	"radar/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+// dbPingTimeout bounds the database check so a stuck pool cannot stall the probe
+const dbPingTimeout = 2 * time.Second
+
+const (
+	statusOK       = "ok"
+	statusNotReady = "not_ready"
+)
+
+// Subsystem names reported in the readiness breakdown
+const (
+	subsystemDatabase = "database"
+	subsystemRouting  = "routing"
+	subsystemFirebase = "firebase"
+	subsystemPubSub   = "pubsub"
+)
+
+// readinessCheck reports whether one subsystem can serve traffic
+type readinessCheck struct {
+	name  string
+	ready func(ctx context.Context) bool
+}
+
+// Handler serves the liveness and readiness probes shared by the API and worker servers
+type Handler struct {
+	checks []readinessCheck
+	logger *slog.Logger
+}
+
+// HandlerParams holds dependencies for Handler, injected by Fx.
+type HandlerParams struct {
+	fx.In
+
+	Cfg        *config.Config
+	RoutingSvc usecase.RoutingUsecase
+	HealthRepo repository.HealthRepository
+	Logger     *slog.Logger
+
+	// Provider checks are only required by the binaries that wire the provider
+	NotificationSvc service.NotificationService `optional:"true"`
+	EventPublisher  service.EventPublisher      `optional:"true"`
+}
+
+// NewHandler creates a new health handler
+func NewHandler(params HandlerParams) *Handler {
+	h := &Handler{logger: params.Logger}
+
+	h.checks = append(h.checks,
+		readinessCheck{name: subsystemDatabase, ready: func(ctx context.Context) bool {
+			return h.pingDatabase(ctx, params.HealthRepo)
+		}},
+		readinessCheck{name: subsystemRouting, ready: func(context.Context) bool {
+			return params.RoutingSvc.IsReady()
+		}},
+	)
+	if params.NotificationSvc != nil {
+		h.checks = append(h.checks, readinessCheck{name: subsystemFirebase, ready: func(context.Context) bool {
+			return firebaseConfigured(params.Cfg)
+		}})
+	}
+	if params.EventPublisher != nil {
+		h.checks = append(h.checks, readinessCheck{name: subsystemPubSub, ready: func(context.Context) bool {
+			return pubSubConfigured(params.Cfg)
+		}})
+	}
+
+	return h
+}
+
+// ReadinessResponse reports the overall readiness and the state of each subsystem
+type ReadinessResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Live reports that the process is up; it never checks dependencies
+func (h *Handler) Live(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]string{"status": statusOK})
+}
+
+// Ready reports 200 only when every subsystem wired into this server is ready, and 503 otherwise
+func (h *Handler) Ready(c echo.Context) error {
+	ctx := c.Request().Context()
+	checks := make(map[string]string, len(h.checks))
+	ready := true
+
+	for _, check := range h.checks {
+		if check.ready(ctx) {
+			checks[check.name] = statusOK
+
+			continue
+		}
+		checks[check.name] = statusNotReady
+		ready = false
+	}
+
+	if !ready {
+		return c.JSON(http.StatusServiceUnavailable, ReadinessResponse{Status: statusNotReady, Checks: checks})
+	}
+
+	return c.JSON(http.StatusOK, ReadinessResponse{Status: statusOK, Checks: checks})
+}
+
+func (h *Handler) pingDatabase(ctx context.Context, repo repository.HealthRepository) bool {
+	ctx, cancel := context.WithTimeout(ctx, dbPingTimeout)
+	defer cancel()
+
+	if err := repo.Ping(ctx); err != nil {
+		h.logger.Warn("Readiness check: database ping failed", slog.String("error", err.Error()))
+
+		return false
+	}
+
+	return true
+}
+
+// firebaseConfigured reports whether Firebase credentials are set
+func firebaseConfigured(cfg *config.Config) bool {
+	return cfg != nil && cfg.Firebase != nil &&
+		cfg.Firebase.ProjectID != "" && cfg.Firebase.CredentialsPath != ""
+}
+
+// pubSubConfigured reports whether the Pub/Sub provider has the settings it needs to publish
+func pubSubConfigured(cfg *config.Config) bool {
+	if cfg == nil || cfg.PubSub == nil {
+		return false
+	}
+
+	switch cfg.PubSub.Provider {
+	case constants.PubSubProviderLocal:
+		return cfg.PubSub.LocalEndpoint != ""
+	case constants.PubSubProviderGoogle:
+		return cfg.PubSub.ProjectID != "" && cfg.PubSub.TopicID != ""
+	default:
+		return false
+	}
+}
@@ -0,0 +1,180 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"radar/config"
+	"radar/internal/domain/constants"
+	"radar/internal/domain/service"
+	"radar/internal/usecase"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedRoutingUsecase struct {
+	usecase.RoutingUsecase
+	ready bool
+}
+
+func (uc *fixedRoutingUsecase) IsReady() bool {
+	return uc.ready
+}
+
+type fixedHealthRepository struct {
+	err error
+}
+
+func (repo *fixedHealthRepository) Ping(context.Context) error {
+	return repo.err
+}
+
+func newProbeContext(target string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequestWithContext(context.Background(), http.MethodGet, target, nil)
+	rec := httptest.NewRecorder()
+
+	return echo.New().NewContext(req, rec), rec
+}
+
+func TestHandler_Live(t *testing.T) {
+	handler := NewHandler(HandlerParams{
+		RoutingSvc: &fixedRoutingUsecase{},
+		HealthRepo: &fixedHealthRepository{err: errors.New("connection refused")},
+		Logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	c, rec := newProbeContext("/healthz")
+
+	require.NoError(t, handler.Live(c))
+
+	// Liveness ignores dependency state
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+}
+
+// readyParams returns handler params where every subsystem, including both providers, is ready
+func readyParams() HandlerParams {
+	return HandlerParams{
+		Cfg: &config.Config{
+			Firebase: &config.FirebaseConfig{ProjectID: "radar", CredentialsPath: "/secrets/firebase.json"},
+			PubSub:   &config.PubSubConfig{Provider: constants.PubSubProviderGoogle, ProjectID: "radar", TopicID: "notifications"},
+		},
+		RoutingSvc:      &fixedRoutingUsecase{ready: true},
+		HealthRepo:      &fixedHealthRepository{},
+		Logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+		NotificationSvc: &stubNotificationService{},
+		EventPublisher:  &stubEventPublisher{},
+	}
+}
+
+// The provider stubs only mark the provider as wired; readiness comes from its configuration
+type stubNotificationService struct {
+	service.NotificationService
+}
+
+type stubEventPublisher struct {
+	service.EventPublisher
+}
+
+func TestHandler_Ready(t *testing.T) {
+	allOK := map[string]string{
+		subsystemDatabase: statusOK,
+		subsystemRouting:  statusOK,
+		subsystemFirebase: statusOK,
+		subsystemPubSub:   statusOK,
+	}
+	withCheck := func(name, status string) map[string]string {
+		checks := maps.Clone(allOK)
+		checks[name] = status
+
+		return checks
+	}
+
+	tests := []struct {
+		name       string
+		mutate     func(params *HandlerParams)
+		wantCode   int
+		wantChecks map[string]string
+	}{
+		{
+			name:       "all subsystems ready",
+			mutate:     func(*HandlerParams) {},
+			wantCode:   http.StatusOK,
+			wantChecks: allOK,
+		},
+		{
+			name: "database unreachable",
+			mutate: func(params *HandlerParams) {
+				params.HealthRepo = &fixedHealthRepository{err: errors.New("connection refused")}
+			},
+			wantCode:   http.StatusServiceUnavailable,
+			wantChecks: withCheck(subsystemDatabase, statusNotReady),
+		},
+		{
+			name: "routing not ready",
+			mutate: func(params *HandlerParams) {
+				params.RoutingSvc = &fixedRoutingUsecase{ready: false}
+			},
+			wantCode:   http.StatusServiceUnavailable,
+			wantChecks: withCheck(subsystemRouting, statusNotReady),
+		},
+		{
+			name: "firebase credentials missing",
+			mutate: func(params *HandlerParams) {
+				params.Cfg.Firebase.CredentialsPath = ""
+			},
+			wantCode:   http.StatusServiceUnavailable,
+			wantChecks: withCheck(subsystemFirebase, statusNotReady),
+		},
+		{
+			name: "pubsub local endpoint missing",
+			mutate: func(params *HandlerParams) {
+				params.Cfg.PubSub = &config.PubSubConfig{Provider: constants.PubSubProviderLocal}
+			},
+			wantCode:   http.StatusServiceUnavailable,
+			wantChecks: withCheck(subsystemPubSub, statusNotReady),
+		},
+		{
+			name: "pubsub not wired into this server",
+			mutate: func(params *HandlerParams) {
+				params.EventPublisher = nil
+				params.Cfg.PubSub = nil
+			},
+			wantCode: http.StatusOK,
+			wantChecks: map[string]string{
+				subsystemDatabase: statusOK,
+				subsystemRouting:  statusOK,
+				subsystemFirebase: statusOK,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := readyParams()
+			tt.mutate(&params)
+			handler := NewHandler(params)
+			c, rec := newProbeContext("/readyz")
+
+			require.NoError(t, handler.Ready(c))
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			var body ReadinessResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantChecks, body.Checks)
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, statusOK, body.Status)
+			} else {
+				assert.Equal(t, statusNotReady, body.Status)
+			}
+		})
+	}
+}
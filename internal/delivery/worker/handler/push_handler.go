@@ -7,10 +7,13 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
 	"net/http"
 	"slices"
+	"sync/atomic"
 	"time"
 
+	"radar/config"
 	"radar/internal/domain/entity"
 	"radar/internal/domain/policy"
 	"radar/internal/domain/repository"
@@ -20,6 +23,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 )
 
@@ -59,6 +64,19 @@ func isRetryableError(err error) bool {
 	return ok
 }
 
+// defaultSendRetryBackoff is the delay before the first resend of transiently failed tokens
+const defaultSendRetryBackoff = 200 * time.Millisecond
+
+// defaultSendRetryMaxBackoff bounds the doubled resend delay when not configured
+const defaultSendRetryMaxBackoff = 5 * time.Second
+
+const (
+	// defaultMessageDedupTTL is how long a processed message ID is remembered when not configured
+	defaultMessageDedupTTL = time.Hour
+	// defaultMessageClaimLease bounds how long a claim blocks redeliveries when no processing budget is set
+	defaultMessageClaimLease = 10 * time.Minute
+)
+
 // PushHandler handles Pub/Sub push messages for geo processing
 type PushHandler struct {
 	logger           *slog.Logger
@@ -67,6 +85,51 @@ type PushHandler struct {
 	subscriptionRepo repository.SubscriptionRepository
 	deviceRepo       repository.DeviceRepository
 	notificationRepo repository.NotificationRepository
+	preferenceRepo   repository.NotificationPreferenceRepository
+	messageRepo      repository.PubSubMessageRepository
+	deepLinkPolicy   policy.DeepLinkPolicy
+	radiusPolicy     usecase.RadiusPolicy
+	deviceTarget     repository.DeviceTargetFilter
+
+	// When set, subscribers that also have a merchant account are not notified
+	excludeMerchantSubscribers bool
+
+	// Limits broadcasts to a canary cohort of subscribers when enabled
+	canaryPolicy policy.CanaryPolicy
+
+	// When devices with invalid tokens are deleted
+	invalidTokenPolicy policy.InvalidTokenPolicy
+
+	// Bounds how many reachable subscribers one broadcast is delivered to
+	recipientCap policy.RecipientCapPolicy
+
+	// Minimum time between broadcasts from the same location, rechecked when scheduled notifications are due
+	broadcastCooldown time.Duration
+
+	// Backpressure: bounded in-flight pushes (nil means unlimited) and drain state during shutdown
+	inflight chan struct{}
+	draining atomic.Bool
+
+	// Per-message deadline so processing stops before the Pub/Sub ack deadline (0 disables)
+	processingBudget time.Duration
+
+	// How long a processed message ID suppresses redeliveries; deduplication is off without messageRepo
+	messageDedupTTL time.Duration
+
+	// Resends of transient FCM failures per message (0 disables), the delay before the first one, and
+	// the bound the doubled delay is clamped to
+	sendRetryBudget     int
+	sendRetryBackoff    time.Duration
+	sendRetryMaxBackoff time.Duration
+
+	// Devices last delivered to more than this many days ago are removed by CleanupStaleDevices
+	staleDeliveryDays int
+
+	// Spans for each push and its routing and FCM calls; a no-op tracer when no provider is configured
+	tracer trace.Tracer
+
+	// Delivery outcome counters and routing latency; a no-op recorder when none is injected
+	metrics service.NotificationMetrics
 }
 
 // PushHandlerParams holds dependencies for the PushHandler
@@ -79,23 +142,126 @@ type PushHandlerParams struct {
 	SubscriptionRepo repository.SubscriptionRepository
 	DeviceRepo       repository.DeviceRepository
 	NotificationRepo repository.NotificationRepository
+	PreferenceRepo   repository.NotificationPreferenceRepository `optional:"true"`
+	MessageRepo      repository.PubSubMessageRepository          `optional:"true"`
+	TracerProvider   trace.TracerProvider                        `optional:"true"`
+	Metrics          service.NotificationMetrics                 `optional:"true"`
+	Policies         policy.NotificationPolicies
+	Config           *config.Config
 }
 
 // NewPushHandler creates a new Pub/Sub push handler
 func NewPushHandler(params PushHandlerParams) *PushHandler {
+	var inflight chan struct{}
+	var processingBudget time.Duration
+	var sendRetryBudget int
+	sendRetryBackoff := defaultSendRetryBackoff
+	sendRetryMaxBackoff := defaultSendRetryMaxBackoff
+	messageDedupTTL := defaultMessageDedupTTL
+	if params.Config != nil && params.Config.PubSub != nil {
+		if params.Config.PubSub.MaxConcurrentPushes > 0 {
+			inflight = make(chan struct{}, params.Config.PubSub.MaxConcurrentPushes)
+		}
+		processingBudget = max(params.Config.PubSub.ProcessingBudget, 0)
+		sendRetryBudget = max(params.Config.PubSub.SendRetryBudget, 0)
+		if params.Config.PubSub.SendRetryBackoff > 0 {
+			sendRetryBackoff = params.Config.PubSub.SendRetryBackoff
+		}
+		if params.Config.PubSub.SendRetryMaxBackoff > 0 {
+			sendRetryMaxBackoff = params.Config.PubSub.SendRetryMaxBackoff
+		}
+		if params.Config.PubSub.MessageDedupTTL > 0 {
+			messageDedupTTL = params.Config.PubSub.MessageDedupTTL
+		}
+	}
+
+	var deviceTarget repository.DeviceTargetFilter
+	var excludeMerchantSubscribers bool
+	var broadcastCooldown time.Duration
+	if params.Config != nil && params.Config.Notification != nil {
+		deviceTarget = repository.DeviceTargetFilter{
+			Platforms:     params.Config.Notification.TargetPlatforms,
+			MinAppVersion: params.Config.Notification.MinAppVersion,
+		}
+		excludeMerchantSubscribers = params.Config.Notification.ExcludeMerchantSubscribers
+		broadcastCooldown = params.Config.Notification.BroadcastCooldown
+	}
+
+	staleDeliveryDays := defaultStaleDeliveryDays
+	if params.Config != nil && params.Config.DeviceCleanup != nil && params.Config.DeviceCleanup.StaleDeliveryDays > 0 {
+		staleDeliveryDays = params.Config.DeviceCleanup.StaleDeliveryDays
+	}
+
 	return &PushHandler{
-		logger:           params.Logger,
-		routingSvc:       params.RoutingSvc,
-		notificationSvc:  params.NotificationSvc,
-		subscriptionRepo: params.SubscriptionRepo,
-		deviceRepo:       params.DeviceRepo,
-		notificationRepo: params.NotificationRepo,
+		logger:              params.Logger,
+		routingSvc:          params.RoutingSvc,
+		notificationSvc:     params.NotificationSvc,
+		subscriptionRepo:    params.SubscriptionRepo,
+		deviceRepo:          params.DeviceRepo,
+		notificationRepo:    params.NotificationRepo,
+		preferenceRepo:      params.PreferenceRepo,
+		messageRepo:         params.MessageRepo,
+		deepLinkPolicy:      params.Policies.DeepLink,
+		deviceTarget:        deviceTarget,
+		radiusPolicy:        usecase.NewRadiusPolicy(params.Config),
+		inflight:            inflight,
+		processingBudget:    processingBudget,
+		messageDedupTTL:     messageDedupTTL,
+		sendRetryBudget:     sendRetryBudget,
+		sendRetryBackoff:    sendRetryBackoff,
+		sendRetryMaxBackoff: sendRetryMaxBackoff,
+		tracer:              newTracer(params.TracerProvider),
+		metrics:             service.NotificationMetricsOrNoop(params.Metrics),
+
+		excludeMerchantSubscribers: excludeMerchantSubscribers,
+		canaryPolicy:               params.Policies.Canary,
+		invalidTokenPolicy:         params.Policies.InvalidToken,
+		recipientCap:               params.Policies.RecipientCap,
+		broadcastCooldown:          broadcastCooldown,
+		staleDeliveryDays:          staleDeliveryDays,
+	}
+}
+
+// Drain makes the handler reject new pushes with 503 so Pub/Sub redelivers them elsewhere
+// while in-flight messages finish during graceful shutdown.
+func (h *PushHandler) Drain() {
+	h.draining.Store(true)
+}
+
+// tryAcquire reserves an in-flight slot without blocking.
+// It returns false when the concurrency limit is reached.
+func (h *PushHandler) tryAcquire() (release func(), ok bool) {
+	if h.inflight == nil {
+		return func() {}, true
+	}
+
+	select {
+	case h.inflight <- struct{}{}:
+		return func() { <-h.inflight }, true
+	default:
+		return nil, false
 	}
 }
 
 // HandlePush handles incoming Pub/Sub push messages
 func (h *PushHandler) HandlePush(c echo.Context) error {
-	ctx := c.Request().Context()
+	ctx, span := startSpan(c.Request().Context(), h.tracer, spanHandlePush)
+	defer span.End()
+
+	// Apply backpressure before doing any work; non-2xx responses make Pub/Sub back off and redeliver
+	if h.draining.Load() {
+		h.logger.Warn("[Worker] Rejecting push while draining")
+
+		return c.NoContent(http.StatusServiceUnavailable)
+	}
+
+	release, ok := h.tryAcquire()
+	if !ok {
+		h.logger.Warn("[Worker] Rejecting push, concurrency limit reached", slog.Int("limit", cap(h.inflight)))
+
+		return c.NoContent(http.StatusTooManyRequests)
+	}
+	defer release()
 
 	// Parse Pub/Sub message
 	var pushMsg PubSubMessage
@@ -131,6 +297,10 @@ func (h *PushHandler) HandlePush(c echo.Context) error {
 	// Update context with requestID and logger
 	ctx = observability.WithCorrelationID(ctx, requestID)
 	ctx = observability.WithLogger(ctx, reqLogger)
+	span.SetAttributes(
+		attribute.String(attrRequestID, requestID),
+		attribute.String("notification_id", event.NotificationID),
+	)
 
 	reqLogger.Info("[Worker] Processing notification event",
 		slog.String("notification_id", event.NotificationID),
@@ -138,8 +308,27 @@ func (h *PushHandler) HandlePush(c echo.Context) error {
 		slog.Int("subscriber_count", len(event.SubscriberIDs)),
 	)
 
+	// Claim the message ID before any side effect so a redelivery is not processed twice
+	messageID := pushMsg.Message.MessageID
+	claimed, status := h.claimMessage(ctx, messageID)
+	if status != 0 {
+		return c.NoContent(status)
+	}
+
+	// Bound processing by the budget so a slow job is retried instead of outliving the ack deadline
+	if h.processingBudget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.processingBudget)
+		defer cancel()
+	}
+
 	// Process the notification
-	if err := h.processNotification(ctx, &event); err != nil {
+	err = h.processNotification(ctx, &event)
+	if claimed {
+		h.settleMessage(ctx, messageID, err)
+	}
+	if err != nil {
+		recordSpanError(span, err)
 		reqLogger.Error("[Worker] Failed to process notification",
 			slog.String("notification_id", event.NotificationID),
 			slog.String("error", err.Error()),
@@ -161,6 +350,72 @@ func (h *PushHandler) HandlePush(c echo.Context) error {
 	return c.NoContent(http.StatusOK)
 }
 
+// claimMessage records the message as being processed. It returns a non-zero status when the
+// delivery must be answered without processing: 200 for a message already processed, 409 for one
+// another delivery is still working on so Pub/Sub retries it later. A failed claim is logged and
+// the message is processed without deduplication.
+func (h *PushHandler) claimMessage(ctx context.Context, messageID string) (claimed bool, status int) {
+	if h.messageRepo == nil || messageID == "" {
+		return false, 0
+	}
+
+	now := time.Now()
+	lease := h.processingBudget
+	if lease <= 0 {
+		lease = defaultMessageClaimLease
+	}
+
+	logger := observability.LoggerFromContextOrDefault(ctx, h.logger)
+	claim, err := h.messageRepo.ClaimMessage(ctx, messageID, now, now.Add(lease))
+	if err != nil {
+		logger.Warn("[Worker] Failed to claim message, processing without deduplication",
+			slog.String("message_id", messageID),
+			slog.String("error", err.Error()),
+		)
+
+		return false, 0
+	}
+
+	switch claim {
+	case repository.MessageProcessed:
+		logger.Info("[Worker] Skipping duplicate delivery of a processed message", slog.String("message_id", messageID))
+
+		return false, http.StatusOK
+	case repository.MessageInFlight:
+		logger.Info("[Worker] Deferring delivery of a message still being processed", slog.String("message_id", messageID))
+
+		return false, http.StatusConflict
+	default:
+		return true, 0
+	}
+}
+
+// settleMessage releases the claim after a retryable failure so the redelivery is processed, and
+// otherwise remembers the message as processed for messageDedupTTL.
+func (h *PushHandler) settleMessage(ctx context.Context, messageID string, processErr error) {
+	// The processing budget may have expired; settling the claim must still reach the database
+	ctx = context.WithoutCancel(ctx)
+	logger := observability.LoggerFromContextOrDefault(ctx, h.logger)
+
+	if isRetryableError(processErr) {
+		if err := h.messageRepo.ReleaseMessage(ctx, messageID); err != nil {
+			logger.Warn("[Worker] Failed to release message claim",
+				slog.String("message_id", messageID),
+				slog.String("error", err.Error()),
+			)
+		}
+
+		return
+	}
+
+	if err := h.messageRepo.CompleteMessage(ctx, messageID, time.Now().Add(h.messageDedupTTL)); err != nil {
+		logger.Warn("[Worker] Failed to mark message processed",
+			slog.String("message_id", messageID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
 // extractRequestID extracts request ID from message attributes, event, or generates a new one
 func (h *PushHandler) extractRequestID(ctx context.Context, pushMsg *PubSubMessage, event *service.NotificationEvent) string {
 	// 1. Try message attribute request_id (from Pub/Sub)
@@ -184,6 +439,16 @@ func (h *PushHandler) extractRequestID(ctx context.Context, pushMsg *PubSubMessa
 
 // processNotification processes a notification event
 func (h *PushHandler) processNotification(ctx context.Context, event *service.NotificationEvent) error {
+	// Stale broadcasts (e.g. "store is open now" delayed by a relay backlog) are acknowledged without sending
+	if event.IsExpired(time.Now()) {
+		h.logger.Warn("[Worker] Dropping expired notification event",
+			slog.String("notification_id", event.NotificationID),
+			slog.Time("expires_at", event.ExpiresAt),
+		)
+
+		return nil
+	}
+
 	// Parse IDs
 	notificationID, merchantID, subscriberIDs, err := h.parseEventIDs(event)
 	if err != nil {
@@ -218,19 +483,54 @@ func (h *PushHandler) processNotification(ctx context.Context, event *service.No
 		return nil
 	}
 
+	// A redelivery after a partial send only reaches the devices the earlier attempts did not log
+	devices, deviceMap, err = h.skipLoggedDevices(ctx, notificationID, devices, deviceMap, event.NotificationID)
+	if err != nil {
+		return err
+	}
+
+	if len(devices) == 0 {
+		return nil
+	}
+
+	// Nothing has been sent yet, so a retry cannot deliver duplicates
+	if err := ctx.Err(); err != nil {
+		return newRetryableError(fmt.Errorf("processing budget exhausted before sending: %w", err))
+	}
+
 	// Prepare and send notifications
 	title, body, notificationData := h.prepareNotificationContent(event)
 	tokens := h.collectTokens(devices)
 
-	totalSent, totalFailed, invalidTokens, notificationLogs := h.sendBatchedNotifications(
+	totalSent, totalFailed, totalDeferred, invalidTokens, notificationLogs := h.sendBatchedNotifications(
 		ctx, tokens, deviceMap, title, body, notificationData, notificationID,
 	)
+	h.metrics.TokensSent(totalSent)
+	h.metrics.DeliveryFailed(totalFailed)
+
+	// Nothing was delivered and every token failed transiently, for example while the provider is
+	// short-circuited, so Pub/Sub can redeliver without duplicating any send.
+	if totalDeferred == len(tokens) {
+		return newRetryableError(fmt.Errorf("send notifications: %w", service.ErrNotificationUnavailable))
+	}
+
+	// Record what was sent even if the budget ran out mid-send; retrying would duplicate those sends.
+	persistCtx := ctx
+	if ctx.Err() != nil {
+		persistCtx = context.WithoutCancel(ctx)
+	}
 
 	// Cleanup tokens confirmed unregistered by FCM.
-	h.cleanupInvalidTokens(ctx, invalidTokens, deviceMap)
+	h.cleanupInvalidTokens(persistCtx, invalidTokens, deviceMap)
+	h.resetInvalidTokenStrikes(persistCtx, notificationLogs)
 
 	// Save results
-	h.saveNotificationResults(ctx, notificationID, notificationLogs, totalSent, totalFailed, len(invalidTokens), event.NotificationID)
+	h.saveNotificationResults(persistCtx, notificationID, notificationLogs, totalSent, totalFailed, len(invalidTokens), event.NotificationID)
+
+	// The transiently failed tokens have no log, so the redelivery sends only to them
+	if totalDeferred > 0 {
+		return newRetryableError(fmt.Errorf("send notifications: %d tokens deferred: %w", totalDeferred, service.ErrNotificationUnavailable))
+	}
 
 	return nil
 }
@@ -259,12 +559,41 @@ func (h *PushHandler) parseEventIDs(event *service.NotificationEvent) (notificat
 }
 
 // filterSubscribersByDistance filters subscribers based on road network distance
-func (h *PushHandler) filterSubscribersByDistance(ctx context.Context, merchantID uuid.UUID, subscriberIDs []uuid.UUID, event *service.NotificationEvent) ([]uuid.UUID, error) {
+func (h *PushHandler) filterSubscribersByDistance(ctx context.Context, merchantID uuid.UUID, subscriberIDs []uuid.UUID, event *service.NotificationEvent) (validUserIDs []uuid.UUID, err error) {
+	ctx, span := startSpan(ctx, h.tracer, spanFilterByDistance, attribute.Int("subscriber_count", len(subscriberIDs)))
+	defer func() { endSpan(span, err) }()
+
 	addresses, err := h.subscriptionRepo.FindSubscriberAddressesByUserIDs(ctx, merchantID, subscriberIDs)
 	if err != nil {
 		return nil, newRetryableError(fmt.Errorf("find subscriber addresses by user ids: %w", err))
 	}
 
+	// Snoozes can start after the event was published, so re-check them at delivery time
+	addresses = entity.WithoutSnoozedSubscribers(addresses, time.Now())
+	if h.excludeMerchantSubscribers {
+		addresses = entity.WithoutMerchantSubscribers(addresses)
+	}
+	addresses, suppressed := usecase.CanaryCohort(addresses, h.canaryPolicy)
+	h.metrics.CanarySuppressed(suppressed)
+	if suppressed > 0 {
+		h.logger.Info("[Worker] Canary mode suppressed subscribers outside the cohort",
+			slog.String("notification_id", event.NotificationID),
+			slog.Int("suppressed_count", suppressed),
+		)
+	}
+	// Quiet hours depend on the delivery time, so preferences are applied here even if the publisher did
+	if h.preferenceRepo != nil {
+		addresses, suppressed, err = usecase.ApplyNotificationPreferences(ctx, h.preferenceRepo, merchantID, addresses, time.Now())
+		if err != nil {
+			return nil, newRetryableError(fmt.Errorf("apply notification preferences: %w", err))
+		}
+		if suppressed > 0 {
+			h.logger.Info("[Worker] Notification preferences suppressed subscribers",
+				slog.String("notification_id", event.NotificationID),
+				slog.Int("suppressed_count", suppressed),
+			)
+		}
+	}
 	if len(addresses) == 0 {
 		h.logger.Info("[Worker] No addresses found for subscribers",
 			slog.String("notification_id", event.NotificationID),
@@ -273,25 +602,36 @@ func (h *PushHandler) filterSubscribersByDistance(ctx context.Context, merchantI
 		return nil, nil
 	}
 
+	// The publisher already applied the shared reachability filter, so skip the recheck
 	source := usecase.Coordinate{Lat: event.Latitude, Lng: event.Longitude}
-	targets := make([]usecase.Coordinate, len(addresses))
-	for idx, addr := range addresses {
-		targets[idx] = usecase.Coordinate{Lat: addr.Latitude, Lng: addr.Longitude}
+	validAddresses := addresses
+	if !event.ReachabilityFiltered {
+		routingSvc := tracedRouting{RoutingUsecase: h.routingSvc, tracer: h.tracer}
+		startedAt := time.Now()
+		validAddresses, err = usecase.FilterReachableAddresses(ctx, routingSvc, h.radiusPolicy, source, addresses)
+		h.metrics.ObserveRoutingLatency(time.Since(startedAt))
+		if err != nil {
+			return nil, newRetryableError(fmt.Errorf("filter subscribers by distance: %w", err))
+		}
 	}
 
-	routeResults, err := h.routingSvc.OneToMany(ctx, source, targets)
+	// The cap is applied to every event, so a publisher with a laxer or no cap cannot exceed the worker's.
+	// Rejected broadcasts are not retried, since redelivery would reach the same subscribers.
+	validAddresses, capped, err := usecase.CapRecipients(source, validAddresses, h.recipientCap)
 	if err != nil {
-		return nil, newRetryableError(fmt.Errorf("filter subscribers by distance: %w", err))
+		return nil, fmt.Errorf("cap recipients: %w", err)
 	}
-
-	validUserIDs := make([]uuid.UUID, 0)
-	for idx, result := range routeResults.Results {
-		distanceMeters := result.DistanceKm * 1000.0
-		if result.IsReachable && distanceMeters <= addresses[idx].NotificationRadius {
-			validUserIDs = append(validUserIDs, addresses[idx].OwnerID)
-		}
+	h.metrics.RecipientsCapped(capped)
+	if capped > 0 {
+		h.logger.Warn("[Worker] Broadcast capped to the nearest subscribers",
+			slog.String("notification_id", event.NotificationID),
+			slog.Int("max_recipients", h.recipientCap.MaxRecipients),
+			slog.Int("dropped_count", capped),
+		)
 	}
 
+	validUserIDs = usecase.SubscriberIDs(validAddresses)
+
 	h.logger.Info("[Worker] Filtered subscribers by road distance",
 		slog.String("notification_id", event.NotificationID),
 		slog.Int("original_count", len(addresses)),
@@ -301,13 +641,24 @@ func (h *PushHandler) filterSubscribersByDistance(ctx context.Context, merchantI
 	return validUserIDs, nil
 }
 
-// getDevicesForUsers retrieves devices for the given user IDs
+// getDevicesForUsers retrieves devices for the given user IDs, dropping devices whose own settings suppress the broadcast
 func (h *PushHandler) getDevicesForUsers(ctx context.Context, userIDs []uuid.UUID, notificationID string) ([]*entity.UserDevice, map[string]*entity.UserDevice, error) {
-	devices, err := h.subscriptionRepo.FindDevicesForUsers(ctx, userIDs, policy.DefaultDevicePolicy().HealthyWindowDays)
+	devices, err := h.subscriptionRepo.FindDevicesForUsers(ctx, userIDs, policy.DefaultDevicePolicy().HealthyWindowDays, h.deviceTarget)
 	if err != nil {
 		return nil, nil, newRetryableError(fmt.Errorf("find devices for users: %w", err))
 	}
 
+	devices, suppressed, err := usecase.ApplyDevicePreferences(ctx, h.preferenceRepo, devices, time.Now())
+	if err != nil {
+		return nil, nil, newRetryableError(fmt.Errorf("apply device preferences: %w", err))
+	}
+	if suppressed > 0 {
+		h.logger.Info("[Worker] Device notification settings suppressed devices",
+			slog.String("notification_id", notificationID),
+			slog.Int("suppressed_count", suppressed),
+		)
+	}
+
 	if len(devices) == 0 {
 		h.logger.Info("[Worker] No devices found for valid subscribers",
 			slog.String("notification_id", notificationID),
@@ -324,6 +675,40 @@ func (h *PushHandler) getDevicesForUsers(ctx context.Context, userIDs []uuid.UUI
 	return devices, deviceMap, nil
 }
 
+// skipLoggedDevices drops the devices that already have a log for the notification and rebuilds the token map.
+// Nothing has been sent in this attempt yet, so a failed lookup is retryable.
+func (h *PushHandler) skipLoggedDevices(
+	ctx context.Context,
+	notificationID uuid.UUID,
+	devices []*entity.UserDevice,
+	deviceMap map[string]*entity.UserDevice,
+	eventID string,
+) ([]*entity.UserDevice, map[string]*entity.UserDevice, error) {
+	logged, err := h.notificationRepo.FindLoggedDeviceIDs(ctx, notificationID)
+	if err != nil {
+		return nil, nil, newRetryableError(fmt.Errorf("find logged devices: %w", err))
+	}
+	if len(logged) == 0 {
+		return devices, deviceMap, nil
+	}
+
+	devices = slices.DeleteFunc(devices, func(device *entity.UserDevice) bool {
+		return slices.Contains(logged, device.ID)
+	})
+	h.logger.Info("[Worker] Skipping devices already handled by an earlier delivery",
+		slog.String("notification_id", eventID),
+		slog.Int("logged_count", len(logged)),
+		slog.Int("remaining_count", len(devices)),
+	)
+
+	remaining := make(map[string]*entity.UserDevice, len(devices))
+	for _, device := range devices {
+		remaining[device.FCMToken] = device
+	}
+
+	return devices, remaining, nil
+}
+
 // collectTokens extracts FCM tokens from devices
 func (h *PushHandler) collectTokens(devices []*entity.UserDevice) []string {
 	tokens := make([]string, 0, len(devices))
@@ -337,9 +722,9 @@ func (h *PushHandler) collectTokens(devices []*entity.UserDevice) []string {
 // prepareNotificationContent creates the notification title, body, and data
 func (h *PushHandler) prepareNotificationContent(event *service.NotificationEvent) (title, body string, data map[string]string) {
 	title = "商戶位置通知"
-	body = fmt.Sprintf("%s 已在 %s 開始營業", event.LocationName, event.FullAddress)
-	if event.HintMessage != "" {
-		body = fmt.Sprintf("%s - %s", body, event.HintMessage)
+	body = policy.LocationNotificationBody(event.LocationName, event.FullAddress)
+	if hint := policy.DefaultHintMessagePolicy().Sanitize(event.HintMessage); hint != "" {
+		body = fmt.Sprintf("%s - %s", body, hint)
 	}
 
 	data = map[string]string{
@@ -351,119 +736,237 @@ func (h *PushHandler) prepareNotificationContent(event *service.NotificationEven
 		"full_address":    event.FullAddress,
 	}
 
+	if err := h.deepLinkPolicy.ApplyMerchantAction(data, event.MerchantID); err != nil {
+		h.logger.Warn("[Worker] Skipping rejected notification deep link",
+			slog.String("notification_id", event.NotificationID),
+			slog.String("error", err.Error()),
+		)
+	}
+
 	return title, body, data
 }
 
-// sendBatchedNotifications sends notifications in batches and collects results
-func (h *PushHandler) sendBatchedNotifications(ctx context.Context, tokens []string, deviceMap map[string]*entity.UserDevice, title, body string, data map[string]string, notificationID uuid.UUID) (sent, failed int, invalidTokens []string, logs []*entity.NotificationLog) {
+// sendBatchedNotifications sends notifications in batches and collects results.
+// Tokens left undelivered by a transient provider or transport error are resent while the message's
+// retry budget lasts, and those still undelivered are counted as deferred and left without a log. Tokens in the
+// batches not started before the processing budget ran out are logged and counted as failed with
+// policy.NotificationLogErrorBudgetExceeded.
+func (h *PushHandler) sendBatchedNotifications(ctx context.Context, tokens []string, deviceMap map[string]*entity.UserDevice, title, body string, data map[string]string, notificationID uuid.UUID) (sent, failed, deferred int, invalidTokens []string, logs []*entity.NotificationLog) {
 	const batchSize = 500
 
+	retriesLeft := h.sendRetryBudget
 	totalSent := 0
 	totalFailed := 0
+	totalDeferred := 0
 	var allInvalidTokens []string
 	var notificationLogs []*entity.NotificationLog
 
 	for idx := 0; idx < len(tokens); idx += batchSize {
+		// Stop starting new batches once the processing budget is spent
+		if ctx.Err() != nil {
+			skipped := tokens[idx:]
+			h.logger.Warn("[Worker] Processing budget exhausted, skipping remaining batches",
+				slog.String("notification_id", notificationID.String()),
+				slog.Int("skipped_tokens", len(skipped)),
+			)
+			totalFailed += len(skipped)
+			for _, token := range skipped {
+				if log := h.newNotificationLog(deviceMap, token, notificationID, policy.NotificationLogStatusFailed, policy.NotificationLogErrorBudgetExceeded); log != nil {
+					notificationLogs = append(notificationLogs, log)
+				}
+			}
+
+			break
+		}
+
 		end := min(idx+batchSize, len(tokens))
 		batch := tokens[idx:end]
 
-		successCount, failureCount, batchInvalidTokens, sendErr := h.notificationSvc.SendBatchNotification(
-			ctx, batch, title, body, data,
-		)
+		results, sendErr := h.sendBatchWithRetry(ctx, idx, batch, title, body, data, &retriesLeft)
+
+		successCount, failureCount, batchInvalidTokens := service.SummarizeTokenResults(results)
+		batchDeferred := 0
+		for _, result := range results {
+			if result.Status == service.TokenStatusTransient {
+				batchDeferred++
+			}
+		}
 
 		if sendErr != nil {
-			h.logger.Error("[Worker] Failed to send batch",
+			h.logger.Error("[Worker] Batch send left tokens undelivered",
 				slog.Int("batch_start", idx),
 				slog.Int("batch_size", len(batch)),
+				slog.Int("sent_count", successCount),
+				slog.Int("transient_count", batchDeferred),
 				slog.String("error", sendErr.Error()),
 			)
-			totalFailed += len(batch)
-
-			// Create failure logs for all tokens in this batch
-			for _, token := range batch {
-				device, ok := deviceMap[token]
-				if !ok || device == nil {
-					h.logger.Warn("[Worker] Device not found for token during batch failure",
-						slog.String("token_prefix", token[:min(10, len(token))]),
-					)
+		}
 
-					continue
-				}
+		totalSent += successCount
+		totalFailed += failureCount - batchDeferred
+		totalDeferred += batchDeferred
+		allInvalidTokens = append(allInvalidTokens, batchInvalidTokens...)
 
-				notificationLogs = append(notificationLogs, &entity.NotificationLog{
-					ID:             uuid.New(),
-					NotificationID: notificationID,
-					UserID:         device.UserID,
-					DeviceID:       device.ID,
-					Status:         "failed",
-					ErrorMessage:   fmt.Sprintf("batch send error: %v", sendErr),
-					SentAt:         time.Now(),
-				})
+		// Create a log for each device with its own outcome. Deferred tokens are left unlogged for the redelivery.
+		for _, result := range results {
+			if result.Status == service.TokenStatusTransient {
+				continue
+			}
+			status, errorMsg := policy.NotificationLogOutcome(result)
+			if log := h.newNotificationLog(deviceMap, result.Token, notificationID, status, errorMsg); log != nil {
+				notificationLogs = append(notificationLogs, log)
 			}
-
-			continue
 		}
+	}
 
-		totalSent += successCount
-		totalFailed += failureCount
-		allInvalidTokens = append(allInvalidTokens, batchInvalidTokens...)
+	return totalSent, totalFailed, totalDeferred, allInvalidTokens, notificationLogs
+}
 
-		// Create logs for this batch
-		for _, token := range batch {
-			device, ok := deviceMap[token]
-			if !ok || device == nil {
-				h.logger.Warn("[Worker] Device not found for token",
-					slog.String("token_prefix", token[:min(10, len(token))]),
-				)
+// newNotificationLog builds the log of one send outcome for the device holding token, or returns nil when no
+// device holds it
+func (h *PushHandler) newNotificationLog(
+	deviceMap map[string]*entity.UserDevice,
+	token string,
+	notificationID uuid.UUID,
+	status, errorMsg string,
+) *entity.NotificationLog {
+	device, ok := deviceMap[token]
+	if !ok || device == nil {
+		h.logger.Warn("[Worker] Device not found for token",
+			slog.String("token_prefix", token[:min(10, len(token))]),
+		)
 
-				continue
+		return nil
+	}
+
+	return &entity.NotificationLog{
+		ID:             uuid.New(),
+		NotificationID: notificationID,
+		UserID:         device.UserID,
+		DeviceID:       device.ID,
+		Status:         status,
+		ErrorMessage:   errorMsg,
+		SentAt:         time.Now(),
+	}
+}
+
+// sendBatchWithRetry sends one batch, then resends the tokens still marked transient with exponential
+// backoff and jitter, spending one of retriesLeft per resend. Sent, failed, and invalid tokens are never resent.
+// With a retry budget the notification service's own retries are disabled, so every resend is counted here.
+// The returned error is that of the last attempt.
+func (h *PushHandler) sendBatchWithRetry(ctx context.Context, batchStart int, batch []string, title, body string, data map[string]string, retriesLeft *int) ([]service.TokenResult, error) {
+	if h.sendRetryBudget > 0 {
+		ctx = service.WithoutSendRetries(ctx)
+	}
+	results, sendErr := h.sendBatch(ctx, batchStart, batch, title, body, data, 0)
+
+	for attempt := 1; ; attempt++ {
+		var pending []int
+		for idx, result := range results {
+			if result.Status == service.TokenStatusTransient {
+				pending = append(pending, idx)
 			}
+		}
+		if len(pending) == 0 || *retriesLeft <= 0 {
+			return results, sendErr
+		}
 
-			status := "sent"
-			errorMsg := ""
-			if slices.Contains(batchInvalidTokens, token) {
-				status = "failed"
-				errorMsg = "unregistered token"
+		delay := h.sendRetryDelay(attempt)
+		h.logger.Warn("[Worker] Retrying transiently failed tokens",
+			slog.Int("batch_start", batchStart),
+			slog.Int("attempt", attempt),
+			slog.Int("transient_count", len(pending)),
+			slog.Duration("delay", delay),
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return results, sendErr
+		case <-timer.C:
+		}
+		*retriesLeft--
+
+		tokens := make([]string, len(pending))
+		for idx, resultIdx := range pending {
+			tokens[idx] = results[resultIdx].Token
+		}
+
+		var retryResults []service.TokenResult
+		retryResults, sendErr = h.sendBatch(ctx, batchStart, tokens, title, body, data, attempt)
+		for idx, resultIdx := range pending {
+			if idx < len(retryResults) {
+				results[resultIdx] = retryResults[idx]
 			}
+		}
+	}
+}
 
-			notificationLogs = append(notificationLogs, &entity.NotificationLog{
-				ID:             uuid.New(),
-				NotificationID: notificationID,
-				UserID:         device.UserID,
-				DeviceID:       device.ID,
-				Status:         status,
-				ErrorMessage:   errorMsg,
-				SentAt:         time.Now(),
-			})
+// sendBatch makes one SendBatchNotification call inside its own span
+func (h *PushHandler) sendBatch(ctx context.Context, batchStart int, tokens []string, title, body string, data map[string]string, attempt int) ([]service.TokenResult, error) {
+	ctx, span := startSpan(ctx, h.tracer, spanSendBatchNotification,
+		attribute.Int("batch_start", batchStart),
+		attribute.Int("batch_size", len(tokens)),
+		attribute.Int("attempt", attempt),
+	)
+	results, err := h.notificationSvc.SendBatchNotification(ctx, tokens, title, body, data)
+	endSpan(span, err)
+
+	return results, err
+}
+
+// sendRetryDelay returns the backoff before resend attempt, doubling per attempt up to the max backoff,
+// with jitter over its upper half. The doubling is clamped before it can overflow on a large attempt.
+func (h *PushHandler) sendRetryDelay(attempt int) time.Duration {
+	delay := min(h.sendRetryBackoff, h.sendRetryMaxBackoff)
+	for range attempt - 1 {
+		if delay >= h.sendRetryMaxBackoff/2 {
+			delay = h.sendRetryMaxBackoff
+
+			break
 		}
+		delay *= 2
 	}
 
-	return totalSent, totalFailed, allInvalidTokens, notificationLogs
+	return delay/2 + rand.N(delay/2+1)
 }
 
-// cleanupInvalidTokens removes devices with tokens confirmed unregistered by FCM.
+// cleanupInvalidTokens strikes or removes devices with tokens confirmed unregistered by FCM, by the same
+// rules as the inline send path
 func (h *PushHandler) cleanupInvalidTokens(ctx context.Context, invalidTokens []string, deviceMap map[string]*entity.UserDevice) {
-	for _, token := range invalidTokens {
-		if device, ok := deviceMap[token]; ok {
-			if err := h.deviceRepo.DeleteDevice(ctx, device.ID); err != nil {
-				h.logger.Warn("[Worker] Failed to delete invalid device",
-					slog.String("device_id", device.ID.String()),
-					slog.String("error", err.Error()),
-				)
-			}
-		}
+	cleaned, err := h.invalidTokenPolicy.CleanupInvalidTokens(ctx, h.deviceRepo, invalidTokens, deviceMap, time.Now())
+	if err != nil {
+		h.logger.Warn("[Worker] Failed to clean up invalid tokens", slog.String("error", err.Error()))
+	}
+	h.metrics.InvalidTokensCleaned(cleaned)
+}
+
+// resetInvalidTokenStrikes clears the invalid-token strikes of devices the notification was sent to
+func (h *PushHandler) resetInvalidTokenStrikes(ctx context.Context, logs []*entity.NotificationLog) {
+	if err := h.invalidTokenPolicy.ResetStrikes(ctx, h.deviceRepo, logs); err != nil {
+		h.logger.Warn("[Worker] Failed to reset invalid token strikes", slog.String("error", err.Error()))
+	}
+}
+
+// recordDeliverySuccess stamps the devices the notification was sent to so stale-device cleanup keeps them
+func (h *PushHandler) recordDeliverySuccess(ctx context.Context, logs []*entity.NotificationLog) {
+	if err := policy.RecordDeliverySuccess(ctx, h.deviceRepo, logs, time.Now()); err != nil {
+		h.logger.Warn("[Worker] Failed to record device delivery success", slog.String("error", err.Error()))
 	}
 }
 
-// saveNotificationResults saves notification logs and updates status
+// saveNotificationResults saves notification logs, stamps delivered devices, and updates status
 func (h *PushHandler) saveNotificationResults(ctx context.Context, notificationID uuid.UUID, logs []*entity.NotificationLog, sent, failed, invalidTokensCount int, eventID string) {
 	if len(logs) > 0 {
 		if err := h.notificationRepo.BatchCreateNotificationLogs(ctx, logs); err != nil {
 			h.logger.Error("[Worker] Failed to create notification logs", slog.String("error", err.Error()))
 		}
 	}
+	h.recordDeliverySuccess(ctx, logs)
 
-	if err := h.notificationRepo.UpdateNotificationStatus(ctx, notificationID, sent, failed); err != nil {
+	if err := h.notificationRepo.AddNotificationResults(ctx, notificationID, sent, failed); err != nil {
 		h.logger.Error("[Worker] Failed to update notification status", slog.String("error", err.Error()))
 	}
 
@@ -0,0 +1,1197 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"radar/config"
+	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
+	"radar/internal/domain/policy"
+	"radar/internal/domain/repository"
+	"radar/internal/domain/service"
+	mockRepo "radar/internal/mocks/repository"
+	mockSvc "radar/internal/mocks/service"
+	"radar/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// nearbyRoutingService reports every target as reachable within a short walk
+type nearbyRoutingService struct{}
+
+func (r nearbyRoutingService) ManyToMany(ctx context.Context, profile string, sources, targets []usecase.Coordinate) ([]*usecase.OneToManyResult, error) {
+	return usecase.RouteEachSource(ctx, r, profile, sources, targets)
+}
+
+func (nearbyRoutingService) OneToMany(_ context.Context, _ string, source usecase.Coordinate, targets []usecase.Coordinate) (*usecase.OneToManyResult, error) {
+	results := make([]usecase.RouteResult, len(targets))
+	for i, target := range targets {
+		results[i] = usecase.RouteResult{Source: source, Target: target, DistanceKm: 0.1, DurationMin: 1, IsReachable: true}
+	}
+
+	return &usecase.OneToManyResult{Source: source, Targets: targets, Results: results}, nil
+}
+
+func (nearbyRoutingService) FindNearestNode(_ context.Context, _ string, coord usecase.Coordinate) (*usecase.NodeInfo, bool, error) {
+	return &usecase.NodeInfo{Location: coord}, true, nil
+}
+
+func (nearbyRoutingService) SnapBatch(_ context.Context, _ string, coords []usecase.Coordinate) ([]usecase.NodeInfo, []bool, error) {
+	nodes := make([]usecase.NodeInfo, len(coords))
+	found := make([]bool, len(coords))
+	for i, coord := range coords {
+		nodes[i] = usecase.NodeInfo{Location: coord}
+		found[i] = true
+	}
+
+	return nodes, found, nil
+}
+
+func (nearbyRoutingService) CalculateDistance(_ context.Context, _ string, source, target usecase.Coordinate) (*usecase.RouteResult, error) {
+	return &usecase.RouteResult{Source: source, Target: target, DistanceKm: 0.1, DurationMin: 1, IsReachable: true}, nil
+}
+
+func (s nearbyRoutingService) CalculateRoute(ctx context.Context, profile string, source, target usecase.Coordinate) (*usecase.RouteResult, error) {
+	return s.CalculateDistance(ctx, profile, source, target)
+}
+
+func (nearbyRoutingService) NearestRoadName(context.Context, usecase.Coordinate) (string, bool, error) {
+	return "", false, nil
+}
+
+func (nearbyRoutingService) IsReady() bool {
+	return true
+}
+
+func (nearbyRoutingService) Metadata() usecase.RoutingMetadata {
+	return usecase.RoutingMetadata{}
+}
+
+type pushHandlerFixtures struct {
+	handler          *PushHandler
+	subscriptionRepo *mockRepo.MockSubscriptionRepository
+	notificationRepo *mockRepo.MockNotificationRepository
+	deviceRepo       *mockRepo.MockDeviceRepository
+	notificationSvc  *mockSvc.MockNotificationService
+}
+
+func createTestPushHandler(t *testing.T) pushHandlerFixtures {
+	subscriptionRepo := mockRepo.NewMockSubscriptionRepository(t)
+	notificationRepo := mockRepo.NewMockNotificationRepository(t)
+	deviceRepo := mockRepo.NewMockDeviceRepository(t)
+	notificationSvc := mockSvc.NewMockNotificationService(t)
+
+	handler := NewPushHandler(PushHandlerParams{
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		RoutingSvc:       nearbyRoutingService{},
+		NotificationSvc:  notificationSvc,
+		SubscriptionRepo: subscriptionRepo,
+		DeviceRepo:       deviceRepo,
+		NotificationRepo: notificationRepo,
+		Config:           &config.Config{},
+	})
+
+	return pushHandlerFixtures{
+		handler:          handler,
+		subscriptionRepo: subscriptionRepo,
+		notificationRepo: notificationRepo,
+		deviceRepo:       deviceRepo,
+		notificationSvc:  notificationSvc,
+	}
+}
+
+func newTestNotificationEvent(subscriberID uuid.UUID, expiresAt time.Time) *service.NotificationEvent {
+	return &service.NotificationEvent{
+		NotificationID: uuid.New().String(),
+		MerchantID:     uuid.New().String(),
+		Latitude:       25.0330,
+		Longitude:      121.5654,
+		LocationName:   "Test Store",
+		FullAddress:    "123 Test St",
+		SubscriberIDs:  []string{subscriberID.String()},
+		ExpiresAt:      expiresAt,
+	}
+}
+
+func TestPushHandler_ProcessNotification_WithinTTLSends(t *testing.T) {
+	fx := createTestPushHandler(t)
+	ctx := context.Background()
+	subscriberID := uuid.New()
+	deviceID := uuid.New()
+	event := newTestNotificationEvent(subscriberID, time.Now().Add(time.Minute))
+
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByUserIDs(ctx, mock.Anything, []uuid.UUID{subscriberID}).
+		Return([]*entity.SubscriberAddress{{
+			Address:            entity.Address{OwnerID: subscriberID, Latitude: 25.0335, Longitude: 121.5660},
+			NotificationRadius: 1000,
+		}}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindDevicesForUsers(ctx, []uuid.UUID{subscriberID}, mock.Anything, repository.DeviceTargetFilter{}).
+		Return([]*entity.UserDevice{{ID: deviceID, UserID: subscriberID, FCMToken: "token-1"}}, nil)
+	fx.notificationRepo.EXPECT().FindLoggedDeviceIDs(ctx, mock.Anything).Return(nil, nil)
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(ctx, []string{"token-1"}, mock.Anything, mock.Anything, mock.Anything).
+		Return([]service.TokenResult{{Token: "token-1", Status: service.TokenStatusSent}}, nil)
+	fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
+	// The delivered device is stamped so stale-device cleanup keeps it
+	fx.deviceRepo.EXPECT().RecordDeliverySuccess(ctx, []uuid.UUID{deviceID}, mock.Anything).Return(nil).Once()
+	fx.notificationRepo.EXPECT().AddNotificationResults(ctx, mock.Anything, 1, 0).Return(nil)
+
+	require.NoError(t, fx.handler.processNotification(ctx, event))
+}
+
+func TestPushHandler_ProcessNotification_ExpiredEventDropped(t *testing.T) {
+	fx := createTestPushHandler(t)
+	event := newTestNotificationEvent(uuid.New(), time.Now().Add(-time.Minute))
+
+	// No repository or provider expectations: an expired event must not be sent
+	require.NoError(t, fx.handler.processNotification(context.Background(), event))
+}
+
+func TestPushHandler_PrepareNotificationContent_SanitizesHint(t *testing.T) {
+	fx := createTestPushHandler(t)
+	event := newTestNotificationEvent(uuid.New(), time.Time{})
+	event.HintMessage = "first spot\nby the\x07 corner" + strings.Repeat("!", policy.DefaultHintMessagePolicy().MaxLength)
+
+	_, body, _ := fx.handler.prepareNotificationContent(event)
+
+	hint := strings.TrimPrefix(body, "Test Store 已在 123 Test St 開始營業 - ")
+	assert.NotContains(t, body, "\n")
+	assert.NotContains(t, body, "\x07")
+	assert.True(t, strings.HasPrefix(hint, "first spot by the corner"))
+	assert.Equal(t, policy.DefaultHintMessagePolicy().MaxLength, utf8.RuneCountInString(hint))
+}
+
+func newPushRequestContext(t *testing.T, event *service.NotificationEvent) (echo.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+
+	return newPushMessageRequestContext(t, "", event)
+}
+
+func newPushMessageRequestContext(t *testing.T, messageID string, event *service.NotificationEvent) (echo.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	var msg PubSubMessage
+	msg.Message.Data = base64.StdEncoding.EncodeToString(data)
+	msg.Message.MessageID = messageID
+	body, err := json.Marshal(msg)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/push", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	return echo.New().NewContext(req, rec), rec
+}
+
+func TestPushHandler_HandlePush_RejectsBeyondConcurrencyLimit(t *testing.T) {
+	handler := NewPushHandler(PushHandlerParams{
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Config: &config.Config{PubSub: &config.PubSubConfig{MaxConcurrentPushes: 1}},
+	})
+
+	// Occupy the only slot as if another push were still being processed
+	release, ok := handler.tryAcquire()
+	require.True(t, ok)
+
+	c, rec := newPushRequestContext(t, newTestNotificationEvent(uuid.New(), time.Time{}))
+	require.NoError(t, handler.HandlePush(c))
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	// Once the slot frees up the push is accepted; an event without subscribers is acknowledged
+	release()
+	event := newTestNotificationEvent(uuid.New(), time.Time{})
+	event.SubscriberIDs = nil
+	c, rec = newPushRequestContext(t, event)
+	require.NoError(t, handler.HandlePush(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestPushHandler_HandlePush_RejectsWhileDraining(t *testing.T) {
+	fx := createTestPushHandler(t)
+	fx.handler.Drain()
+
+	c, rec := newPushRequestContext(t, newTestNotificationEvent(uuid.New(), time.Time{}))
+	require.NoError(t, fx.handler.HandlePush(c))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestPushHandler_ProcessNotification_SnoozedSubscriberSkipped(t *testing.T) {
+	fx := createTestPushHandler(t)
+	ctx := context.Background()
+	subscriberID := uuid.New()
+	event := newTestNotificationEvent(subscriberID, time.Time{})
+	snoozedUntil := time.Now().Add(2 * time.Hour)
+
+	// A snooze started after publish is honored at delivery time
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByUserIDs(ctx, mock.Anything, []uuid.UUID{subscriberID}).
+		Return([]*entity.SubscriberAddress{{
+			Address:            entity.Address{OwnerID: subscriberID, Latitude: 25.0335, Longitude: 121.5660},
+			NotificationRadius: 1000,
+			SnoozedUntil:       &snoozedUntil,
+		}}, nil)
+
+	require.NoError(t, fx.handler.processNotification(ctx, event))
+}
+
+// slowRoutingService blocks OneToMany until the caller's context is done
+type slowRoutingService struct {
+	nearbyRoutingService
+}
+
+func (slowRoutingService) OneToMany(ctx context.Context, _ string, _ usecase.Coordinate, _ []usecase.Coordinate) (*usecase.OneToManyResult, error) {
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+func TestPushHandler_HandlePush_AbortsAtProcessingBudget(t *testing.T) {
+	subscriptionRepo := mockRepo.NewMockSubscriptionRepository(t)
+	handler := NewPushHandler(PushHandlerParams{
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		RoutingSvc:       slowRoutingService{},
+		NotificationSvc:  mockSvc.NewMockNotificationService(t),
+		SubscriptionRepo: subscriptionRepo,
+		DeviceRepo:       mockRepo.NewMockDeviceRepository(t),
+		NotificationRepo: mockRepo.NewMockNotificationRepository(t),
+		Config:           &config.Config{PubSub: &config.PubSubConfig{ProcessingBudget: 50 * time.Millisecond}},
+	})
+
+	subscriberID := uuid.New()
+	subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByUserIDs(mock.Anything, mock.Anything, []uuid.UUID{subscriberID}).
+		Return([]*entity.SubscriberAddress{{
+			Address:            entity.Address{OwnerID: subscriberID, Latitude: 25.0335, Longitude: 121.5660},
+			NotificationRadius: 1000,
+		}}, nil)
+
+	c, rec := newPushRequestContext(t, newTestNotificationEvent(subscriberID, time.Time{}))
+
+	start := time.Now()
+	require.NoError(t, handler.HandlePush(c))
+
+	// The slow routing job is cut off at the budget and handed back to Pub/Sub for a retry
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestPushHandler_HandlePush_RecordsSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	subscriptionRepo := mockRepo.NewMockSubscriptionRepository(t)
+	notificationRepo := mockRepo.NewMockNotificationRepository(t)
+	deviceRepo := mockRepo.NewMockDeviceRepository(t)
+	notificationSvc := mockSvc.NewMockNotificationService(t)
+	handler := NewPushHandler(PushHandlerParams{
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		RoutingSvc:       nearbyRoutingService{},
+		NotificationSvc:  notificationSvc,
+		SubscriptionRepo: subscriptionRepo,
+		DeviceRepo:       deviceRepo,
+		NotificationRepo: notificationRepo,
+		TracerProvider:   provider,
+		Config:           &config.Config{},
+	})
+
+	subscriberID := uuid.New()
+	subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByUserIDs(mock.Anything, mock.Anything, []uuid.UUID{subscriberID}).
+		Return([]*entity.SubscriberAddress{{
+			Address:            entity.Address{OwnerID: subscriberID, Latitude: 25.0335, Longitude: 121.5660},
+			NotificationRadius: 1000,
+		}}, nil)
+	subscriptionRepo.EXPECT().
+		FindDevicesForUsers(mock.Anything, []uuid.UUID{subscriberID}, mock.Anything, repository.DeviceTargetFilter{}).
+		Return([]*entity.UserDevice{{ID: uuid.New(), UserID: subscriberID, FCMToken: "token-1"}}, nil)
+	notificationRepo.EXPECT().FindLoggedDeviceIDs(mock.Anything, mock.Anything).Return(nil, nil)
+	notificationSvc.EXPECT().
+		SendBatchNotification(mock.Anything, []string{"token-1"}, mock.Anything, mock.Anything, mock.Anything).
+		Return([]service.TokenResult{{Token: "token-1", Status: service.TokenStatusSent}}, nil)
+	notificationRepo.EXPECT().BatchCreateNotificationLogs(mock.Anything, mock.Anything).Return(nil)
+	deviceRepo.EXPECT().RecordDeliverySuccess(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	notificationRepo.EXPECT().AddNotificationResults(mock.Anything, mock.Anything, 1, 0).Return(nil)
+
+	event := newTestNotificationEvent(subscriberID, time.Time{})
+	event.RequestID = "req-trace-1"
+	c, rec := newPushRequestContext(t, event)
+
+	require.NoError(t, handler.HandlePush(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	spans := make(map[string]tracetest.SpanStub)
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+	require.Len(t, spans, 4)
+	root, filter := spans[spanHandlePush], spans[spanFilterByDistance]
+	routing, send := spans[spanOneToMany], spans[spanSendBatchNotification]
+
+	assert.False(t, root.Parent.IsValid())
+	assert.Equal(t, root.SpanContext.SpanID(), filter.Parent.SpanID())
+	assert.Equal(t, filter.SpanContext.SpanID(), routing.Parent.SpanID())
+	assert.Equal(t, root.SpanContext.SpanID(), send.Parent.SpanID())
+	for name, span := range spans {
+		assert.Equal(t, root.SpanContext.TraceID(), span.SpanContext.TraceID(), name)
+		assert.Contains(t, span.Attributes, attribute.String(attrRequestID, "req-trace-1"), name)
+	}
+	assert.Contains(t, send.Attributes, attribute.Int("batch_size", 1))
+}
+
+func TestPushHandler_NoTracerProviderKeepsContext(t *testing.T) {
+	handler := NewPushHandler(PushHandlerParams{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	ctx := context.Background()
+
+	spanCtx, span := startSpan(ctx, handler.tracer, spanHandlePush)
+	defer span.End()
+
+	assert.False(t, span.IsRecording())
+	assert.Equal(t, ctx, spanCtx)
+}
+
+func TestPushHandler_PrepareNotificationContent_EmptyLocationFallback(t *testing.T) {
+	fx := createTestPushHandler(t)
+	event := newTestNotificationEvent(uuid.New(), time.Time{})
+	event.LocationName = ""
+	event.FullAddress = ""
+
+	_, body, _ := fx.handler.prepareNotificationContent(event)
+
+	assert.Equal(t, "您追蹤的商家 已在附近開始營業", body)
+}
+
+// scriptedRoutingService returns preset route results index-aligned with the targets
+type scriptedRoutingService struct {
+	nearbyRoutingService
+	results []usecase.RouteResult
+}
+
+func (s scriptedRoutingService) OneToMany(_ context.Context, _ string, source usecase.Coordinate, targets []usecase.Coordinate) (*usecase.OneToManyResult, error) {
+	return &usecase.OneToManyResult{Source: source, Targets: targets, Results: s.results}, nil
+}
+
+func TestPushHandler_FilterSubscribersByDistance_MatchesSharedReachability(t *testing.T) {
+	routingSvc := scriptedRoutingService{results: []usecase.RouteResult{
+		{DistanceKm: 0.4, IsReachable: true},
+		{DistanceKm: 2.5, IsReachable: true},
+		{DistanceKm: 0.9, IsReachable: true},
+		{IsReachable: false},
+	}}
+	fx := createTestPushHandler(t)
+	fx.handler.routingSvc = routingSvc
+
+	ctx := context.Background()
+	addresses := []*entity.SubscriberAddress{
+		{Address: entity.Address{OwnerID: uuid.New(), Latitude: 25.034, Longitude: 121.566}, NotificationRadius: 1000},
+		{Address: entity.Address{OwnerID: uuid.New(), Latitude: 25.040, Longitude: 121.570}, NotificationRadius: 2000},
+		{Address: entity.Address{OwnerID: uuid.New(), Latitude: 25.036, Longitude: 121.568}, NotificationRadius: 1000},
+		{Address: entity.Address{OwnerID: uuid.New(), Latitude: 25.050, Longitude: 121.580}, NotificationRadius: 3000},
+	}
+	event := newTestNotificationEvent(uuid.New(), time.Time{})
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByUserIDs(ctx, mock.Anything, mock.Anything).
+		Return(addresses, nil)
+
+	userIDs, err := fx.handler.filterSubscribersByDistance(ctx, uuid.New(), usecase.SubscriberIDs(addresses), event)
+	require.NoError(t, err)
+
+	// The worker must include exactly the subscribers the inline path would
+	source := usecase.Coordinate{Lat: event.Latitude, Lng: event.Longitude}
+	expected, err := usecase.FilterReachableAddresses(ctx, routingSvc, fx.handler.radiusPolicy, source, addresses)
+	require.NoError(t, err)
+	assert.Equal(t, usecase.SubscriberIDs(expected), userIDs)
+	assert.Len(t, userIDs, 2)
+}
+
+func TestPushHandler_FilterSubscribersByDistance_SkipsRecheckWhenPrefiltered(t *testing.T) {
+	fx := createTestPushHandler(t)
+	fx.handler.routingSvc = slowRoutingService{}
+
+	// Routing would block until the deadline, so a prompt result proves the recheck was skipped
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	addresses := []*entity.SubscriberAddress{
+		{Address: entity.Address{OwnerID: uuid.New(), Latitude: 25.034, Longitude: 121.566}, NotificationRadius: 1000},
+	}
+	event := newTestNotificationEvent(uuid.New(), time.Time{})
+	event.ReachabilityFiltered = true
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByUserIDs(ctx, mock.Anything, mock.Anything).
+		Return(addresses, nil)
+
+	userIDs, err := fx.handler.filterSubscribersByDistance(ctx, uuid.New(), usecase.SubscriberIDs(addresses), event)
+
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{addresses[0].OwnerID}, userIDs)
+	assert.NoError(t, ctx.Err())
+}
+
+func TestPushHandler_GetDevicesForUsers_AppliesConfiguredTarget(t *testing.T) {
+	subscriptionRepo := mockRepo.NewMockSubscriptionRepository(t)
+	handler := NewPushHandler(PushHandlerParams{
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		RoutingSvc:       nearbyRoutingService{},
+		NotificationSvc:  mockSvc.NewMockNotificationService(t),
+		SubscriptionRepo: subscriptionRepo,
+		DeviceRepo:       mockRepo.NewMockDeviceRepository(t),
+		NotificationRepo: mockRepo.NewMockNotificationRepository(t),
+		Config: &config.Config{Notification: &config.NotificationConfig{
+			TargetPlatforms: []string{"ios", "android"},
+			MinAppVersion:   "2.4.0",
+		}},
+	})
+
+	ctx := context.Background()
+	userIDs := []uuid.UUID{uuid.New()}
+	eligible := &entity.UserDevice{ID: uuid.New(), UserID: userIDs[0], FCMToken: "token-1", Platform: "ios", AppVersion: "2.5.0"}
+	subscriptionRepo.EXPECT().
+		FindDevicesForUsers(ctx, userIDs, policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{
+			Platforms:     []string{"ios", "android"},
+			MinAppVersion: "2.4.0",
+		}).
+		Return([]*entity.UserDevice{eligible}, nil)
+
+	devices, deviceMap, err := handler.getDevicesForUsers(ctx, userIDs, uuid.New().String())
+
+	require.NoError(t, err)
+	assert.Equal(t, []*entity.UserDevice{eligible}, devices)
+	assert.Equal(t, eligible, deviceMap["token-1"])
+}
+
+func TestPushHandler_ProcessNotification_SkipsDisabledDevice(t *testing.T) {
+	fx := createTestPushHandler(t)
+	ctx := context.Background()
+	subscriberID := uuid.New()
+	event := newTestNotificationEvent(subscriberID, time.Now().Add(time.Minute))
+	enabled, disabled := true, false
+	phone := &entity.UserDevice{ID: uuid.New(), UserID: subscriberID, FCMToken: "token-phone"}
+	phone.NotificationsEnabled = &enabled
+	workPhone := &entity.UserDevice{ID: uuid.New(), UserID: subscriberID, FCMToken: "token-work"}
+	workPhone.NotificationsEnabled = &disabled
+
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByUserIDs(ctx, mock.Anything, []uuid.UUID{subscriberID}).
+		Return([]*entity.SubscriberAddress{{
+			Address:            entity.Address{OwnerID: subscriberID, Latitude: 25.0335, Longitude: 121.5660},
+			NotificationRadius: 1000,
+		}}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindDevicesForUsers(ctx, []uuid.UUID{subscriberID}, mock.Anything, repository.DeviceTargetFilter{}).
+		Return([]*entity.UserDevice{phone, workPhone}, nil)
+	fx.notificationRepo.EXPECT().FindLoggedDeviceIDs(ctx, mock.Anything).Return(nil, nil)
+	// Only the enabled device is in the token list
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(ctx, []string{"token-phone"}, mock.Anything, mock.Anything, mock.Anything).
+		Return([]service.TokenResult{{Token: "token-phone", Status: service.TokenStatusSent}}, nil)
+	fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
+	fx.deviceRepo.EXPECT().RecordDeliverySuccess(ctx, []uuid.UUID{phone.ID}, mock.Anything).Return(nil).Once()
+	fx.notificationRepo.EXPECT().AddNotificationResults(ctx, mock.Anything, 1, 0).Return(nil)
+
+	require.NoError(t, fx.handler.processNotification(ctx, event))
+}
+
+func TestPushHandler_GetDevicesForUsers_DeviceQuietHoursUseOwnerTimezone(t *testing.T) {
+	taipei, err := time.LoadLocation("Asia/Taipei")
+	require.NoError(t, err)
+	local := time.Now().In(taipei)
+	minute := local.Hour()*60 + local.Minute()
+	quietStart, quietEnd := (minute+1440-30)%1440, (minute+30)%1440
+
+	fx := createTestPushHandler(t)
+	preferenceRepo := mockRepo.NewMockNotificationPreferenceRepository(t)
+	fx.handler.preferenceRepo = preferenceRepo
+	ctx := context.Background()
+	userID := uuid.New()
+	quiet := &entity.UserDevice{ID: uuid.New(), UserID: userID, FCMToken: "token-quiet"}
+	quiet.QuietHoursStart, quiet.QuietHoursEnd = &quietStart, &quietEnd
+	inheriting := &entity.UserDevice{ID: uuid.New(), UserID: userID, FCMToken: "token-inheriting"}
+
+	fx.subscriptionRepo.EXPECT().
+		FindDevicesForUsers(ctx, []uuid.UUID{userID}, mock.Anything, repository.DeviceTargetFilter{}).
+		Return([]*entity.UserDevice{quiet, inheriting}, nil)
+	preferenceRepo.EXPECT().
+		FindNotificationPreferences(ctx, []uuid.UUID{userID}).
+		Return([]*entity.NotificationPreference{{UserID: userID, Timezone: "Asia/Taipei"}}, nil)
+
+	devices, deviceMap, err := fx.handler.getDevicesForUsers(ctx, []uuid.UUID{userID}, uuid.New().String())
+
+	require.NoError(t, err)
+	assert.Equal(t, []*entity.UserDevice{inheriting}, devices)
+	assert.NotContains(t, deviceMap, "token-quiet")
+}
+
+func TestPushHandler_FilterSubscribersByDistance_MerchantSubscriberPolicy(t *testing.T) {
+	consumerID := uuid.New()
+	merchantSubscriberID := uuid.New()
+
+	tests := []struct {
+		name             string
+		excludeMerchants bool
+		want             []uuid.UUID
+	}{
+		{name: "merchant subscribers receive broadcasts by default", want: []uuid.UUID{consumerID, merchantSubscriberID}},
+		{name: "merchant subscribers excluded", excludeMerchants: true, want: []uuid.UUID{consumerID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := createTestPushHandler(t)
+			fx.handler.excludeMerchantSubscribers = tt.excludeMerchants
+			ctx := context.Background()
+			event := newTestNotificationEvent(consumerID, time.Time{})
+			merchantID := uuid.MustParse(event.MerchantID)
+			subscriberIDs := []uuid.UUID{consumerID, merchantSubscriberID}
+
+			fx.subscriptionRepo.EXPECT().
+				FindSubscriberAddressesByUserIDs(ctx, merchantID, subscriberIDs).
+				Return([]*entity.SubscriberAddress{
+					{Address: entity.Address{OwnerID: consumerID, Latitude: 25.0335, Longitude: 121.5660}, NotificationRadius: 1000},
+					{Address: entity.Address{OwnerID: merchantSubscriberID, Latitude: 25.0336, Longitude: 121.5661}, NotificationRadius: 1000, IsMerchant: true},
+				}, nil)
+
+			validUserIDs, err := fx.handler.filterSubscribersByDistance(ctx, merchantID, subscriberIDs, event)
+
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, validUserIDs)
+		})
+	}
+}
+
+func TestPushHandler_FilterSubscribersByDistance_CanaryCohort(t *testing.T) {
+	canaryID := uuid.New()
+	suppressedID := uuid.New()
+
+	fx := createTestPushHandler(t)
+	fx.handler.canaryPolicy = policy.CanaryPolicy{Enabled: true, AllowedUserIDs: []string{canaryID.String()}}
+	ctx := context.Background()
+	event := newTestNotificationEvent(canaryID, time.Time{})
+	merchantID := uuid.MustParse(event.MerchantID)
+	subscriberIDs := []uuid.UUID{canaryID, suppressedID}
+
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByUserIDs(ctx, merchantID, subscriberIDs).
+		Return([]*entity.SubscriberAddress{
+			{Address: entity.Address{OwnerID: canaryID, Latitude: 25.0335, Longitude: 121.5660}, NotificationRadius: 1000},
+			{Address: entity.Address{OwnerID: suppressedID, Latitude: 25.0336, Longitude: 121.5661}, NotificationRadius: 1000},
+		}, nil)
+
+	validUserIDs, err := fx.handler.filterSubscribersByDistance(ctx, merchantID, subscriberIDs, event)
+
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{canaryID}, validUserIDs)
+}
+
+func TestPushHandler_FilterSubscribersByDistance_RecipientCap(t *testing.T) {
+	nearID, farID, unreachableID := uuid.New(), uuid.New(), uuid.New()
+	routingSvc := scriptedRoutingService{results: []usecase.RouteResult{
+		{DistanceKm: 1.5, IsReachable: true},
+		{DistanceKm: 0.3, IsReachable: true},
+		{IsReachable: false},
+	}}
+
+	tests := []struct {
+		name         string
+		recipientCap policy.RecipientCapPolicy
+		want         []uuid.UUID
+		wantError    error
+	}{
+		{name: "under the cap", recipientCap: policy.RecipientCapPolicy{MaxRecipients: 2, Strict: true}, want: []uuid.UUID{farID, nearID}},
+		{name: "strict cap rejects", recipientCap: policy.RecipientCapPolicy{MaxRecipients: 1, Strict: true}, wantError: domainerrors.ErrRecipientCapExceeded},
+		{name: "capped to the nearest", recipientCap: policy.RecipientCapPolicy{MaxRecipients: 1}, want: []uuid.UUID{nearID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := createTestPushHandler(t)
+			fx.handler.routingSvc = routingSvc
+			fx.handler.recipientCap = tt.recipientCap
+			ctx := context.Background()
+			event := newTestNotificationEvent(nearID, time.Time{})
+			merchantID := uuid.MustParse(event.MerchantID)
+			subscriberIDs := []uuid.UUID{farID, nearID, unreachableID}
+
+			// The unreachable subscriber is nearest in a straight line but is filtered out before the cap
+			fx.subscriptionRepo.EXPECT().
+				FindSubscriberAddressesByUserIDs(ctx, merchantID, subscriberIDs).
+				Return([]*entity.SubscriberAddress{
+					{Address: entity.Address{OwnerID: farID, Latitude: 25.0430, Longitude: 121.5654}, NotificationRadius: 2000},
+					{Address: entity.Address{OwnerID: nearID, Latitude: 25.0350, Longitude: 121.5654}, NotificationRadius: 1000},
+					{Address: entity.Address{OwnerID: unreachableID, Latitude: 25.0331, Longitude: 121.5654}, NotificationRadius: 1000},
+				}, nil)
+
+			validUserIDs, err := fx.handler.filterSubscribersByDistance(ctx, merchantID, subscriberIDs, event)
+
+			if tt.wantError != nil {
+				require.ErrorIs(t, err, tt.wantError)
+				assert.False(t, isRetryableError(err), "redelivery would reach the same subscribers")
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, validUserIDs)
+		})
+	}
+}
+
+func TestPushHandler_FilterSubscribersByDistance_CapsReachabilityFilteredEvents(t *testing.T) {
+	fx := createTestPushHandler(t)
+	fx.handler.recipientCap = policy.RecipientCapPolicy{MaxRecipients: 1}
+	ctx := context.Background()
+	nearID, farID := uuid.New(), uuid.New()
+	event := newTestNotificationEvent(nearID, time.Time{})
+	event.ReachabilityFiltered = true
+	merchantID := uuid.MustParse(event.MerchantID)
+	subscriberIDs := []uuid.UUID{farID, nearID}
+
+	// A publisher without a cap sent both subscribers; routing is skipped but the worker's cap still applies
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByUserIDs(ctx, merchantID, subscriberIDs).
+		Return([]*entity.SubscriberAddress{
+			{Address: entity.Address{OwnerID: farID, Latitude: 25.0430, Longitude: 121.5654}, NotificationRadius: 2000},
+			{Address: entity.Address{OwnerID: nearID, Latitude: 25.0350, Longitude: 121.5654}, NotificationRadius: 1000},
+		}, nil)
+
+	validUserIDs, err := fx.handler.filterSubscribersByDistance(ctx, merchantID, subscriberIDs, event)
+
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{nearID}, validUserIDs)
+}
+
+func TestPushHandler_FilterSubscribersByDistance_NotificationPreferences(t *testing.T) {
+	taipei, err := time.LoadLocation("Asia/Taipei")
+	require.NoError(t, err)
+	local := time.Now().In(taipei)
+	minute := local.Hour()*60 + local.Minute()
+	window := func(fromOffset, toOffset int) (*int, *int) {
+		start, end := (minute+fromOffset+1440)%1440, (minute+toOffset+1440)%1440
+
+		return &start, &end
+	}
+
+	quietID, awakeID, optedOutID, defaultID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	categoryID := uuid.New()
+	quietStart, quietEnd := window(-30, 30)
+	awakeStart, awakeEnd := window(60, 120)
+
+	fx := createTestPushHandler(t)
+	preferenceRepo := mockRepo.NewMockNotificationPreferenceRepository(t)
+	fx.handler.preferenceRepo = preferenceRepo
+	ctx := context.Background()
+	event := newTestNotificationEvent(quietID, time.Time{})
+	merchantID := uuid.MustParse(event.MerchantID)
+	subscriberIDs := []uuid.UUID{quietID, awakeID, optedOutID, defaultID}
+
+	addresses := make([]*entity.SubscriberAddress, len(subscriberIDs))
+	for idx, userID := range subscriberIDs {
+		addresses[idx] = &entity.SubscriberAddress{
+			Address:            entity.Address{OwnerID: userID, Latitude: 25.0335, Longitude: 121.5660},
+			NotificationRadius: 1000,
+		}
+	}
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByUserIDs(ctx, merchantID, subscriberIDs).
+		Return(addresses, nil)
+	preferenceRepo.EXPECT().
+		FindNotificationPreferences(ctx, subscriberIDs).
+		Return([]*entity.NotificationPreference{
+			{UserID: quietID, Timezone: "Asia/Taipei", QuietHoursStart: quietStart, QuietHoursEnd: quietEnd},
+			{UserID: awakeID, Timezone: "Asia/Taipei", QuietHoursStart: awakeStart, QuietHoursEnd: awakeEnd},
+			{UserID: optedOutID, OptedOutCategoryIDs: []uuid.UUID{categoryID}},
+		}, nil)
+	preferenceRepo.EXPECT().FindMerchantCategoryID(ctx, merchantID).Return(&categoryID, nil)
+
+	validUserIDs, err := fx.handler.filterSubscribersByDistance(ctx, merchantID, subscriberIDs, event)
+
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{awakeID, defaultID}, validUserIDs)
+}
+
+func TestPushHandler_ProcessNotification_InvalidTokenStrikes(t *testing.T) {
+	tests := []struct {
+		name       string
+		strikes    int
+		wantDelete bool
+	}{
+		{name: "first invalid response keeps the device", strikes: 1},
+		{name: "reaching the strike count deletes the device", strikes: 3, wantDelete: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := createTestPushHandler(t)
+			fx.handler.invalidTokenPolicy = policy.InvalidTokenPolicy{Strikes: 3, Window: 72 * time.Hour}
+			ctx := context.Background()
+			subscriberID := uuid.New()
+			event := newTestNotificationEvent(subscriberID, time.Now().Add(time.Minute))
+			invalidDevice := &entity.UserDevice{ID: uuid.New(), UserID: subscriberID, FCMToken: "token-invalid"}
+			sentDevice := &entity.UserDevice{ID: uuid.New(), UserID: subscriberID, FCMToken: "token-sent"}
+
+			fx.subscriptionRepo.EXPECT().
+				FindSubscriberAddressesByUserIDs(ctx, mock.Anything, []uuid.UUID{subscriberID}).
+				Return([]*entity.SubscriberAddress{{
+					Address:            entity.Address{OwnerID: subscriberID, Latitude: 25.0335, Longitude: 121.5660},
+					NotificationRadius: 1000,
+				}}, nil)
+			fx.subscriptionRepo.EXPECT().
+				FindDevicesForUsers(ctx, []uuid.UUID{subscriberID}, mock.Anything, repository.DeviceTargetFilter{}).
+				Return([]*entity.UserDevice{invalidDevice, sentDevice}, nil)
+			fx.notificationRepo.EXPECT().FindLoggedDeviceIDs(ctx, mock.Anything).Return(nil, nil)
+			fx.notificationSvc.EXPECT().
+				SendBatchNotification(ctx, []string{"token-invalid", "token-sent"}, mock.Anything, mock.Anything, mock.Anything).
+				Return([]service.TokenResult{
+					{Token: "token-invalid", Status: service.TokenStatusInvalid, ErrorCode: "UNREGISTERED"},
+					{Token: "token-sent", Status: service.TokenStatusSent},
+				}, nil)
+			fx.deviceRepo.EXPECT().
+				RecordInvalidTokenStrike(ctx, invalidDevice.ID, mock.Anything, 72*time.Hour).
+				Return(tt.strikes, nil).Once()
+			if tt.wantDelete {
+				fx.deviceRepo.EXPECT().DeleteDevice(ctx, invalidDevice.ID).Return(nil).Once()
+			}
+			// The delivered device starts over, so earlier strikes against it no longer count
+			fx.deviceRepo.EXPECT().ResetInvalidTokenStrikes(ctx, []uuid.UUID{sentDevice.ID}).Return(nil).Once()
+			fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
+			fx.deviceRepo.EXPECT().RecordDeliverySuccess(ctx, []uuid.UUID{sentDevice.ID}, mock.Anything).Return(nil).Once()
+			fx.notificationRepo.EXPECT().AddNotificationResults(ctx, mock.Anything, 1, 1).Return(nil)
+
+			require.NoError(t, fx.handler.processNotification(ctx, event))
+		})
+	}
+}
+
+func TestPushHandler_ProcessNotification_RecordsMetrics(t *testing.T) {
+	fx := createTestPushHandler(t)
+	metrics := mockSvc.NewMockNotificationMetrics(t)
+	fx.handler.metrics = metrics
+	fx.handler.recipientCap = policy.RecipientCapPolicy{MaxRecipients: 1}
+	ctx := context.Background()
+	nearID, farID := uuid.New(), uuid.New()
+	event := newTestNotificationEvent(nearID, time.Now().Add(time.Minute))
+	event.SubscriberIDs = []string{nearID.String(), farID.String()}
+	invalidDevice := &entity.UserDevice{ID: uuid.New(), UserID: nearID, FCMToken: "token-invalid"}
+	sentDevice := &entity.UserDevice{ID: uuid.New(), UserID: nearID, FCMToken: "token-sent"}
+
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByUserIDs(ctx, mock.Anything, []uuid.UUID{nearID, farID}).
+		Return([]*entity.SubscriberAddress{
+			{Address: entity.Address{OwnerID: nearID, Latitude: 25.0335, Longitude: 121.5660}, NotificationRadius: 1000},
+			{Address: entity.Address{OwnerID: farID, Latitude: 25.0380, Longitude: 121.5660}, NotificationRadius: 1000},
+		}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindDevicesForUsers(ctx, []uuid.UUID{nearID}, mock.Anything, repository.DeviceTargetFilter{}).
+		Return([]*entity.UserDevice{invalidDevice, sentDevice}, nil)
+	fx.notificationRepo.EXPECT().FindLoggedDeviceIDs(ctx, mock.Anything).Return(nil, nil)
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(ctx, []string{"token-invalid", "token-sent"}, mock.Anything, mock.Anything, mock.Anything).
+		Return([]service.TokenResult{
+			{Token: "token-invalid", Status: service.TokenStatusInvalid, ErrorCode: "UNREGISTERED"},
+			{Token: "token-sent", Status: service.TokenStatusSent},
+		}, nil)
+	fx.deviceRepo.EXPECT().DeleteDevice(ctx, invalidDevice.ID).Return(nil).Once()
+	fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
+	fx.deviceRepo.EXPECT().RecordDeliverySuccess(ctx, []uuid.UUID{sentDevice.ID}, mock.Anything).Return(nil)
+	fx.notificationRepo.EXPECT().AddNotificationResults(ctx, mock.Anything, 1, 1).Return(nil)
+
+	// The worker records the same delivery outcomes the API does when it sends directly
+	metrics.EXPECT().CanarySuppressed(0).Once()
+	metrics.EXPECT().ObserveRoutingLatency(mock.Anything).Once()
+	metrics.EXPECT().RecipientsCapped(1).Once()
+	metrics.EXPECT().TokensSent(1).Once()
+	metrics.EXPECT().DeliveryFailed(1).Once()
+	metrics.EXPECT().InvalidTokensCleaned(1).Once()
+
+	require.NoError(t, fx.handler.processNotification(ctx, event))
+}
+
+func TestPushHandler_ProcessNotification_LogsPerTokenResults(t *testing.T) {
+	fx := createTestPushHandler(t)
+	ctx := context.Background()
+	subscriberID := uuid.New()
+	event := newTestNotificationEvent(subscriberID, time.Now().Add(time.Minute))
+
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByUserIDs(ctx, mock.Anything, []uuid.UUID{subscriberID}).
+		Return([]*entity.SubscriberAddress{{
+			Address:            entity.Address{OwnerID: subscriberID, Latitude: 25.0335, Longitude: 121.5660},
+			NotificationRadius: 1000,
+		}}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindDevicesForUsers(ctx, []uuid.UUID{subscriberID}, mock.Anything, repository.DeviceTargetFilter{}).
+		Return([]*entity.UserDevice{
+			{ID: uuid.New(), UserID: subscriberID, FCMToken: "token-sent"},
+			{ID: uuid.New(), UserID: subscriberID, FCMToken: "token-failed"},
+			{ID: uuid.New(), UserID: subscriberID, FCMToken: "token-transient"},
+		}, nil)
+	fx.notificationRepo.EXPECT().FindLoggedDeviceIDs(ctx, mock.Anything).Return(nil, nil)
+	// A partial transport error still reports every token, so the delivered one is not counted as failed
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(ctx, []string{"token-sent", "token-failed", "token-transient"}, mock.Anything, mock.Anything, mock.Anything).
+		Return([]service.TokenResult{
+			{Token: "token-sent", Status: service.TokenStatusSent},
+			{Token: "token-failed", Status: service.TokenStatusFailed, ErrorCode: "INVALID_ARGUMENT"},
+			{Token: "token-transient", Status: service.TokenStatusTransient, ErrorCode: "UNAVAILABLE"},
+		}, errors.New("firebase multicast left 1 of 3 tokens undelivered"))
+	// The transient token is left without a log so the redelivery sends to it
+	fx.notificationRepo.EXPECT().
+		BatchCreateNotificationLogs(ctx, mock.MatchedBy(func(logs []*entity.NotificationLog) bool {
+			if len(logs) != 2 {
+				return false
+			}
+
+			return logs[0].Status == policy.NotificationLogStatusSent &&
+				logs[1].Status == policy.NotificationLogStatusFailed && logs[1].ErrorMessage == "failed send error: INVALID_ARGUMENT"
+		})).
+		Return(nil)
+	fx.deviceRepo.EXPECT().RecordDeliverySuccess(ctx, mock.Anything, mock.Anything).Return(nil)
+	fx.notificationRepo.EXPECT().AddNotificationResults(ctx, mock.Anything, 1, 1).Return(nil)
+
+	err := fx.handler.processNotification(ctx, event)
+	require.Error(t, err)
+	assert.True(t, isRetryableError(err))
+	assert.ErrorIs(t, err, service.ErrNotificationUnavailable)
+}
+
+func TestPushHandler_ProcessNotification_RedeliverySkipsLoggedDevices(t *testing.T) {
+	fx := createTestPushHandler(t)
+	ctx := context.Background()
+	subscriberID := uuid.New()
+	event := newTestNotificationEvent(subscriberID, time.Now().Add(time.Minute))
+	sent := &entity.UserDevice{ID: uuid.New(), UserID: subscriberID, FCMToken: "token-sent"}
+	deferred := &entity.UserDevice{ID: uuid.New(), UserID: subscriberID, FCMToken: "token-deferred"}
+
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByUserIDs(ctx, mock.Anything, []uuid.UUID{subscriberID}).
+		Return([]*entity.SubscriberAddress{{
+			Address:            entity.Address{OwnerID: subscriberID, Latitude: 25.0335, Longitude: 121.5660},
+			NotificationRadius: 1000,
+		}}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindDevicesForUsers(ctx, []uuid.UUID{subscriberID}, mock.Anything, repository.DeviceTargetFilter{}).
+		Return([]*entity.UserDevice{sent, deferred}, nil)
+	// The first delivery logged the sent device, so only the deferred one is sent again
+	fx.notificationRepo.EXPECT().FindLoggedDeviceIDs(ctx, mock.Anything).Return([]uuid.UUID{sent.ID}, nil)
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(ctx, []string{"token-deferred"}, mock.Anything, mock.Anything, mock.Anything).
+		Return([]service.TokenResult{{Token: "token-deferred", Status: service.TokenStatusSent}}, nil).
+		Once()
+	fx.notificationRepo.EXPECT().
+		BatchCreateNotificationLogs(ctx, mock.MatchedBy(func(logs []*entity.NotificationLog) bool {
+			return len(logs) == 1 && logs[0].DeviceID == deferred.ID
+		})).
+		Return(nil)
+	fx.deviceRepo.EXPECT().RecordDeliverySuccess(ctx, []uuid.UUID{deferred.ID}, mock.Anything).Return(nil)
+	fx.notificationRepo.EXPECT().AddNotificationResults(ctx, mock.Anything, 1, 0).Return(nil)
+
+	require.NoError(t, fx.handler.processNotification(ctx, event))
+}
+
+// expectSubscriberDevices sets up one nearby subscriber owning a device for each token
+func TestPushHandler_HandlePush_FirstDeliveryClaimsAndCompletes(t *testing.T) {
+	messageRepo := mockRepo.NewMockPubSubMessageRepository(t)
+	handler := NewPushHandler(PushHandlerParams{
+		Logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+		MessageRepo: messageRepo,
+		Config:      &config.Config{PubSub: &config.PubSubConfig{MessageDedupTTL: 30 * time.Minute}},
+	})
+
+	// The claim is written before processing, and the processed record outlives it by the TTL
+	claim := messageRepo.EXPECT().
+		ClaimMessage(mock.Anything, "msg-1", mock.Anything, mock.Anything).
+		Return(repository.MessageClaimed, nil).
+		Once()
+	messageRepo.EXPECT().
+		CompleteMessage(mock.Anything, "msg-1", mock.MatchedBy(func(expiresAt time.Time) bool {
+			return time.Until(expiresAt) > 29*time.Minute
+		})).
+		Return(nil).
+		Once().
+		NotBefore(claim)
+
+	event := newTestNotificationEvent(uuid.New(), time.Time{})
+	event.SubscriberIDs = nil
+	c, rec := newPushMessageRequestContext(t, "msg-1", event)
+	require.NoError(t, handler.HandlePush(c))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestPushHandler_HandlePush_SkipsDuplicateDelivery(t *testing.T) {
+	fx := createTestPushHandler(t)
+	messageRepo := mockRepo.NewMockPubSubMessageRepository(t)
+	fx.handler.messageRepo = messageRepo
+	subscriberID := uuid.New()
+
+	// A processed message is acknowledged without looking up subscribers or sending
+	messageRepo.EXPECT().
+		ClaimMessage(mock.Anything, "msg-1", mock.Anything, mock.Anything).
+		Return(repository.MessageProcessed, nil).
+		Once()
+	c, rec := newPushMessageRequestContext(t, "msg-1", newTestNotificationEvent(subscriberID, time.Time{}))
+	require.NoError(t, fx.handler.HandlePush(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// A message another delivery still holds is handed back so Pub/Sub retries it later
+	messageRepo.EXPECT().
+		ClaimMessage(mock.Anything, "msg-2", mock.Anything, mock.Anything).
+		Return(repository.MessageInFlight, nil).
+		Once()
+	c, rec = newPushMessageRequestContext(t, "msg-2", newTestNotificationEvent(subscriberID, time.Time{}))
+	require.NoError(t, fx.handler.HandlePush(c))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestPushHandler_HandlePush_RetryableFailureReleasesClaim(t *testing.T) {
+	subscriptionRepo := mockRepo.NewMockSubscriptionRepository(t)
+	messageRepo := mockRepo.NewMockPubSubMessageRepository(t)
+	handler := NewPushHandler(PushHandlerParams{
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		RoutingSvc:       slowRoutingService{},
+		NotificationSvc:  mockSvc.NewMockNotificationService(t),
+		SubscriptionRepo: subscriptionRepo,
+		DeviceRepo:       mockRepo.NewMockDeviceRepository(t),
+		NotificationRepo: mockRepo.NewMockNotificationRepository(t),
+		MessageRepo:      messageRepo,
+		Config:           &config.Config{PubSub: &config.PubSubConfig{ProcessingBudget: 50 * time.Millisecond}},
+	})
+
+	subscriberID := uuid.New()
+	subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByUserIDs(mock.Anything, mock.Anything, []uuid.UUID{subscriberID}).
+		Return([]*entity.SubscriberAddress{{
+			Address:            entity.Address{OwnerID: subscriberID, Latitude: 25.0335, Longitude: 121.5660},
+			NotificationRadius: 1000,
+		}}, nil)
+	messageRepo.EXPECT().
+		ClaimMessage(mock.Anything, "msg-1", mock.Anything, mock.Anything).
+		Return(repository.MessageClaimed, nil).
+		Once()
+	// The budget has expired, but the release must still reach the database for the redelivery to be processed
+	messageRepo.EXPECT().
+		ReleaseMessage(mock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() == nil }), "msg-1").
+		Return(nil).
+		Once()
+
+	c, rec := newPushMessageRequestContext(t, "msg-1", newTestNotificationEvent(subscriberID, time.Time{}))
+	require.NoError(t, handler.HandlePush(c))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func expectSubscriberDevices(fx pushHandlerFixtures, ctx context.Context, subscriberID uuid.UUID, tokens ...string) {
+	devices := make([]*entity.UserDevice, len(tokens))
+	for idx, token := range tokens {
+		devices[idx] = &entity.UserDevice{ID: uuid.New(), UserID: subscriberID, FCMToken: token}
+	}
+
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByUserIDs(ctx, mock.Anything, []uuid.UUID{subscriberID}).
+		Return([]*entity.SubscriberAddress{{
+			Address:            entity.Address{OwnerID: subscriberID, Latitude: 25.0335, Longitude: 121.5660},
+			NotificationRadius: 1000,
+		}}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindDevicesForUsers(ctx, []uuid.UUID{subscriberID}, mock.Anything, repository.DeviceTargetFilter{}).
+		Return(devices, nil)
+	fx.notificationRepo.EXPECT().FindLoggedDeviceIDs(ctx, mock.Anything).Return(nil, nil)
+}
+
+func TestPushHandler_ProcessNotification_RetriesTransientSends(t *testing.T) {
+	fx := createTestPushHandler(t)
+	fx.handler.sendRetryBudget = 3
+	fx.handler.sendRetryBackoff = time.Millisecond
+	ctx := context.Background()
+	subscriberID := uuid.New()
+	event := newTestNotificationEvent(subscriberID, time.Now().Add(time.Minute))
+	expectSubscriberDevices(fx, ctx, subscriberID, "token-1")
+
+	// FCM returns 503 twice, then delivers; the service's own retries are off so the budget counts every resend
+	retriesDisabled := mock.MatchedBy(service.SendRetriesDisabled)
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(retriesDisabled, []string{"token-1"}, mock.Anything, mock.Anything, mock.Anything).
+		Return([]service.TokenResult{{Token: "token-1", Status: service.TokenStatusTransient, ErrorCode: "UNAVAILABLE"}},
+			errors.New("firebase multicast left 1 of 1 tokens undelivered")).
+		Twice()
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(retriesDisabled, []string{"token-1"}, mock.Anything, mock.Anything, mock.Anything).
+		Return([]service.TokenResult{{Token: "token-1", Status: service.TokenStatusSent}}, nil).
+		Once()
+	fx.notificationRepo.EXPECT().
+		BatchCreateNotificationLogs(ctx, mock.MatchedBy(func(logs []*entity.NotificationLog) bool {
+			return len(logs) == 1 && logs[0].Status == "sent"
+		})).
+		Return(nil)
+	fx.deviceRepo.EXPECT().RecordDeliverySuccess(ctx, mock.Anything, mock.Anything).Return(nil)
+	fx.notificationRepo.EXPECT().AddNotificationResults(ctx, mock.Anything, 1, 0).Return(nil)
+
+	require.NoError(t, fx.handler.processNotification(ctx, event))
+	fx.notificationSvc.AssertNumberOfCalls(t, "SendBatchNotification", 3)
+}
+
+func TestPushHandler_ProcessNotification_RetryBudgetBoundsAttempts(t *testing.T) {
+	fx := createTestPushHandler(t)
+	fx.handler.sendRetryBudget = 2
+	fx.handler.sendRetryBackoff = time.Millisecond
+	ctx := context.Background()
+	subscriberID := uuid.New()
+	event := newTestNotificationEvent(subscriberID, time.Now().Add(time.Minute))
+	expectSubscriberDevices(fx, ctx, subscriberID, "token-1")
+
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(mock.Anything, []string{"token-1"}, mock.Anything, mock.Anything, mock.Anything).
+		Return([]service.TokenResult{{Token: "token-1", Status: service.TokenStatusTransient, ErrorCode: "UNAVAILABLE"}},
+			errors.New("firebase multicast left 1 of 1 tokens undelivered"))
+
+	// Once the budget is spent nothing was delivered, so the whole message goes back for redelivery
+	err := fx.handler.processNotification(ctx, event)
+	require.Error(t, err)
+	assert.True(t, isRetryableError(err))
+	fx.notificationSvc.AssertNumberOfCalls(t, "SendBatchNotification", 3)
+}
+
+func TestPushHandler_ProcessNotification_DoesNotRetryInvalidTokens(t *testing.T) {
+	fx := createTestPushHandler(t)
+	fx.handler.sendRetryBudget = 3
+	fx.handler.sendRetryBackoff = time.Millisecond
+	ctx := context.Background()
+	subscriberID := uuid.New()
+	event := newTestNotificationEvent(subscriberID, time.Now().Add(time.Minute))
+	expectSubscriberDevices(fx, ctx, subscriberID, "token-invalid", "token-transient")
+
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(mock.Anything, []string{"token-invalid", "token-transient"}, mock.Anything, mock.Anything, mock.Anything).
+		Return([]service.TokenResult{
+			{Token: "token-invalid", Status: service.TokenStatusInvalid, ErrorCode: "UNREGISTERED"},
+			{Token: "token-transient", Status: service.TokenStatusTransient, ErrorCode: "UNAVAILABLE"},
+		}, errors.New("firebase multicast left 1 of 2 tokens undelivered")).
+		Once()
+	// Only the transient token is resent
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(mock.Anything, []string{"token-transient"}, mock.Anything, mock.Anything, mock.Anything).
+		Return([]service.TokenResult{{Token: "token-transient", Status: service.TokenStatusSent}}, nil).
+		Once()
+	fx.deviceRepo.EXPECT().DeleteDevice(ctx, mock.Anything).Return(nil).Once()
+	fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
+	fx.deviceRepo.EXPECT().RecordDeliverySuccess(ctx, mock.Anything, mock.Anything).Return(nil)
+	fx.notificationRepo.EXPECT().AddNotificationResults(ctx, mock.Anything, 1, 1).Return(nil)
+
+	require.NoError(t, fx.handler.processNotification(ctx, event))
+}
+
+func TestPushHandler_SendBatchedNotifications_LogsTokensSkippedAtBudget(t *testing.T) {
+	fx := createTestPushHandler(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	notificationID := uuid.New()
+
+	// One full batch and one token in a second batch
+	tokens := make([]string, 501)
+	deviceMap := make(map[string]*entity.UserDevice, len(tokens))
+	firstBatch := make([]service.TokenResult, 500)
+	for idx := range tokens {
+		tokens[idx] = fmt.Sprintf("token-%03d", idx)
+		deviceMap[tokens[idx]] = &entity.UserDevice{ID: uuid.New(), UserID: uuid.New(), FCMToken: tokens[idx]}
+		if idx < len(firstBatch) {
+			firstBatch[idx] = service.TokenResult{Token: tokens[idx], Status: service.TokenStatusSent}
+		}
+	}
+
+	// The budget runs out while the first batch is in flight
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(mock.Anything, tokens[:500], mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(context.Context, []string, string, string, map[string]string) ([]service.TokenResult, error) {
+			cancel()
+
+			return firstBatch, nil
+		}).
+		Once()
+
+	sent, failed, deferred, invalidTokens, logs := fx.handler.sendBatchedNotifications(
+		ctx, tokens, deviceMap, "title", "body", nil, notificationID,
+	)
+
+	assert.Equal(t, 500, sent)
+	assert.Equal(t, 1, failed)
+	assert.Zero(t, deferred)
+	assert.Empty(t, invalidTokens)
+	require.Len(t, logs, 501)
+	skipped := logs[500]
+	assert.Equal(t, deviceMap["token-500"].ID, skipped.DeviceID)
+	assert.Equal(t, "failed", skipped.Status)
+	assert.Equal(t, policy.NotificationLogErrorBudgetExceeded, skipped.ErrorMessage)
+}
+
+func TestPushHandler_SendRetryDelay(t *testing.T) {
+	handler := &PushHandler{sendRetryBackoff: 100 * time.Millisecond, sendRetryMaxBackoff: time.Second}
+
+	for attempt, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond,
+		5: time.Second, 64: time.Second, 1000: time.Second,
+	} {
+		for range 20 {
+			delay := handler.sendRetryDelay(attempt)
+			assert.GreaterOrEqual(t, delay, want/2, "attempt %d", attempt)
+			assert.LessOrEqual(t, delay, want, "attempt %d", attempt)
+		}
+	}
+}
+
+func TestNewPushHandler_SendRetryBackoffDefaults(t *testing.T) {
+	handler := NewPushHandler(PushHandlerParams{
+		Config: &config.Config{PubSub: &config.PubSubConfig{SendRetryBudget: 3}},
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	assert.Equal(t, defaultSendRetryBackoff, handler.sendRetryBackoff)
+	assert.Equal(t, defaultSendRetryMaxBackoff, handler.sendRetryMaxBackoff)
+	delay := handler.sendRetryDelay(math.MaxInt)
+	assert.GreaterOrEqual(t, delay, defaultSendRetryMaxBackoff/2)
+	assert.LessOrEqual(t, delay, defaultSendRetryMaxBackoff)
+}
+
+func TestPushHandler_CleanupInvalidTokens_AlreadyDeletedDeviceIsSilent(t *testing.T) {
+	fx := createTestPushHandler(t)
+	var logs bytes.Buffer
+	fx.handler.logger = slog.New(slog.NewTextHandler(&logs, nil))
+	ctx := context.Background()
+	device := &entity.UserDevice{ID: uuid.New(), FCMToken: "token-invalid"}
+	deviceMap := map[string]*entity.UserDevice{"token-invalid": device}
+
+	// Two notifications see the same invalid token; the second delete finds the row already removed,
+	// which the repository reports as success
+	fx.deviceRepo.EXPECT().DeleteDevice(ctx, device.ID).Return(nil).Times(2)
+
+	for range 2 {
+		fx.handler.cleanupInvalidTokens(ctx, []string{"token-invalid", "token-invalid"}, deviceMap)
+	}
+
+	assert.NotContains(t, logs.String(), "Failed to clean up invalid tokens")
+}
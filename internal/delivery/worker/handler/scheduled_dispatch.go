@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"radar/internal/domain/entity"
+	"radar/internal/domain/repository"
+	"radar/internal/domain/service"
+	"radar/internal/usecase"
+)
+
+// scheduledDispatchBatchSize bounds how many due notifications one dispatch run picks up
+const scheduledDispatchBatchSize = 100
+
+// scheduledClaimLease is how long a claim keeps other runs off a notification. It is longer than one dispatch
+// run, so only the claims of a run that died before finishing their notifications are taken over.
+const scheduledClaimLease = 15 * time.Minute
+
+// ScheduledDispatchResult summarizes one run of the scheduled notification dispatcher
+type ScheduledDispatchResult struct {
+	Due        int // Pending notifications that were due
+	Dispatched int // Notifications processed and sent
+	Skipped    int // Notifications canceled or claimed by another run in the meantime
+	Suppressed int // Notifications canceled because the merchant broadcast from the same location within the cooldown
+	Failed     int // Notifications that failed; retryable failures stay pending for the next run
+}
+
+// DispatchScheduledNotifications sends the scheduled notifications that are due through the same path as a
+// Pub/Sub push. Each notification is claimed before processing so a concurrent run or cancellation cannot
+// send it twice, marked dispatched only once it has been processed, and released again when processing fails
+// before anything was sent. A claim left behind by a run that died mid-way expires after scheduledClaimLease,
+// and the notification is picked up again. The broadcast cooldown is checked again at claim time, since
+// another broadcast may have gone out after it was scheduled.
+func (h *PushHandler) DispatchScheduledNotifications(ctx context.Context) (*ScheduledDispatchResult, error) {
+	now := time.Now()
+	claimedBefore := now.Add(-scheduledClaimLease)
+	due, err := h.notificationRepo.FindDueScheduledNotifications(ctx, now, claimedBefore, scheduledDispatchBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("find due scheduled notifications: %w", err)
+	}
+
+	result := &ScheduledDispatchResult{Due: len(due)}
+	for _, notification := range due {
+		claim, err := h.notificationRepo.ClaimScheduledNotification(ctx, notification, h.broadcastCooldown, claimedBefore)
+		if err != nil {
+			h.logger.Warn("[Worker] Failed to claim scheduled notification",
+				slog.String("notification_id", notification.ID.String()),
+				slog.String("error", err.Error()),
+			)
+			result.Failed++
+
+			continue
+		}
+		switch claim {
+		case repository.ScheduleClaimed:
+		case repository.ScheduleClaimInCooldown:
+			h.logger.Info("[Worker] Canceled scheduled notification within broadcast cooldown",
+				slog.String("notification_id", notification.ID.String()),
+			)
+			result.Suppressed++
+
+			continue
+		default:
+			result.Skipped++
+
+			continue
+		}
+
+		if err := h.dispatchScheduledNotification(ctx, notification); err != nil {
+			h.logger.Error("[Worker] Failed to dispatch scheduled notification",
+				slog.String("notification_id", notification.ID.String()),
+				slog.String("error", err.Error()),
+				slog.Bool("retryable", isRetryableError(err)),
+			)
+			result.Failed++
+			// A permanent failure is finished with, like a delivered notification, so it is not retried
+			if isRetryableError(err) {
+				h.releaseScheduledNotification(ctx, notification)
+			} else {
+				h.markScheduledNotificationDispatched(ctx, notification)
+			}
+
+			continue
+		}
+
+		h.markScheduledNotificationDispatched(ctx, notification)
+		result.Dispatched++
+	}
+
+	return result, nil
+}
+
+// dispatchScheduledNotification picks the subscribers around the notification's location and processes it
+func (h *PushHandler) dispatchScheduledNotification(ctx context.Context, notification *entity.MerchantLocationNotification) error {
+	// Subscribers are selected when the notification is due, not when it was scheduled
+	addresses, err := h.subscriptionRepo.FindSubscriberAddressesWithinRadius(
+		ctx, notification.MerchantID, notification.Latitude, notification.Longitude,
+	)
+	if err != nil {
+		return newRetryableError(fmt.Errorf("find subscriber addresses within radius: %w", err))
+	}
+
+	userIDs := usecase.SubscriberIDs(addresses)
+	subscriberIDs := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		subscriberIDs = append(subscriberIDs, userID.String())
+	}
+
+	event := &service.NotificationEvent{
+		NotificationID: notification.ID.String(),
+		MerchantID:     notification.MerchantID.String(),
+		Latitude:       notification.Latitude,
+		Longitude:      notification.Longitude,
+		LocationName:   notification.LocationName,
+		FullAddress:    notification.FullAddress,
+		HintMessage:    notification.HintMessage,
+		SubscriberIDs:  subscriberIDs,
+	}
+
+	return h.processNotification(ctx, event)
+}
+
+// markScheduledNotificationDispatched finishes a claimed notification. If the update fails the claim expires
+// and a later run sends the notification again, which is preferred over losing it.
+func (h *PushHandler) markScheduledNotificationDispatched(ctx context.Context, notification *entity.MerchantLocationNotification) {
+	if _, err := h.notificationRepo.UpdateScheduleStatus(
+		context.WithoutCancel(ctx), notification.ID, entity.ScheduleStatusClaimed, entity.ScheduleStatusDispatched,
+	); err != nil {
+		h.logger.Error("[Worker] Failed to mark scheduled notification dispatched",
+			slog.String("notification_id", notification.ID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// releaseScheduledNotification returns a claimed notification to pending so the next run retries it
+func (h *PushHandler) releaseScheduledNotification(ctx context.Context, notification *entity.MerchantLocationNotification) {
+	if _, err := h.notificationRepo.UpdateScheduleStatus(
+		context.WithoutCancel(ctx), notification.ID, entity.ScheduleStatusClaimed, entity.ScheduleStatusPending,
+	); err != nil {
+		h.logger.Error("[Worker] Failed to release scheduled notification",
+			slog.String("notification_id", notification.ID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
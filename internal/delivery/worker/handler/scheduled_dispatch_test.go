@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
+	"radar/internal/domain/policy"
+	"radar/internal/domain/repository"
+	"radar/internal/domain/service"
+	"radar/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestScheduledNotification() *entity.MerchantLocationNotification {
+	return &entity.MerchantLocationNotification{
+		ID:             uuid.New(),
+		MerchantID:     uuid.New(),
+		LocationName:   "Test Store",
+		FullAddress:    "123 Test St",
+		Latitude:       25.0330,
+		Longitude:      121.5654,
+		ScheduleStatus: entity.ScheduleStatusPending,
+	}
+}
+
+func TestPushHandler_DispatchScheduledNotifications_SendsDueNotification(t *testing.T) {
+	fx := createTestPushHandler(t)
+	ctx := context.Background()
+	notification := newTestScheduledNotification()
+	subscriberID := uuid.New()
+	address := &entity.SubscriberAddress{
+		Address:            entity.Address{OwnerID: subscriberID, Latitude: 25.0335, Longitude: 121.5660},
+		NotificationRadius: 1000,
+	}
+
+	var dueBy, claimedBefore time.Time
+	fx.notificationRepo.EXPECT().
+		FindDueScheduledNotifications(ctx, mock.Anything, mock.Anything, scheduledDispatchBatchSize).
+		RunAndReturn(func(_ context.Context, due, claimed time.Time, _ int) ([]*entity.MerchantLocationNotification, error) {
+			dueBy, claimedBefore = due, claimed
+
+			return []*entity.MerchantLocationNotification{notification}, nil
+		})
+	fx.notificationRepo.EXPECT().
+		ClaimScheduledNotification(ctx, notification, time.Duration(0), mock.Anything).
+		Return(repository.ScheduleClaimed, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, notification.MerchantID, notification.Latitude, notification.Longitude).
+		Return([]*entity.SubscriberAddress{address}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByUserIDs(ctx, notification.MerchantID, []uuid.UUID{subscriberID}).
+		Return([]*entity.SubscriberAddress{address}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindDevicesForUsers(ctx, []uuid.UUID{subscriberID}, mock.Anything, repository.DeviceTargetFilter{}).
+		Return([]*entity.UserDevice{{ID: uuid.New(), UserID: subscriberID, FCMToken: "token-1"}}, nil)
+	fx.notificationRepo.EXPECT().FindLoggedDeviceIDs(ctx, mock.Anything).Return(nil, nil)
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(ctx, []string{"token-1"}, mock.Anything, mock.Anything, mock.Anything).
+		Return([]service.TokenResult{{Token: "token-1", Status: service.TokenStatusSent}}, nil)
+	fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
+	fx.deviceRepo.EXPECT().RecordDeliverySuccess(ctx, mock.Anything, mock.Anything).Return(nil)
+	processed := fx.notificationRepo.EXPECT().AddNotificationResults(ctx, notification.ID, 1, 0).Return(nil)
+	// The claim is finished only after the notification has been processed
+	fx.notificationRepo.EXPECT().
+		UpdateScheduleStatus(mock.Anything, notification.ID, entity.ScheduleStatusClaimed, entity.ScheduleStatusDispatched).
+		Return(true, nil).
+		NotBefore(processed.Call)
+
+	result, err := fx.handler.DispatchScheduledNotifications(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, &ScheduledDispatchResult{Due: 1, Dispatched: 1}, result)
+	assert.Equal(t, scheduledClaimLease, dueBy.Sub(claimedBefore), "claims older than the lease are taken over")
+}
+
+func TestPushHandler_DispatchScheduledNotifications_SkipsCanceledNotification(t *testing.T) {
+	fx := createTestPushHandler(t)
+	ctx := context.Background()
+	notification := newTestScheduledNotification()
+
+	fx.notificationRepo.EXPECT().
+		FindDueScheduledNotifications(ctx, mock.Anything, mock.Anything, scheduledDispatchBatchSize).
+		Return([]*entity.MerchantLocationNotification{notification}, nil)
+	// Canceled after the poll: the claim fails and nothing is sent
+	fx.notificationRepo.EXPECT().
+		ClaimScheduledNotification(ctx, notification, time.Duration(0), mock.Anything).
+		Return(repository.ScheduleClaimLost, nil)
+
+	result, err := fx.handler.DispatchScheduledNotifications(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, &ScheduledDispatchResult{Due: 1, Skipped: 1}, result)
+}
+
+func TestPushHandler_DispatchScheduledNotifications_SuppressesWithinCooldown(t *testing.T) {
+	fx := createTestPushHandler(t)
+	fx.handler.broadcastCooldown = 15 * time.Minute
+	ctx := context.Background()
+	notification := newTestScheduledNotification()
+
+	fx.notificationRepo.EXPECT().
+		FindDueScheduledNotifications(ctx, mock.Anything, mock.Anything, scheduledDispatchBatchSize).
+		Return([]*entity.MerchantLocationNotification{notification}, nil)
+	// An immediate broadcast from the same location went out after this one was scheduled
+	fx.notificationRepo.EXPECT().
+		ClaimScheduledNotification(ctx, notification, 15*time.Minute, mock.Anything).
+		Return(repository.ScheduleClaimInCooldown, nil)
+
+	result, err := fx.handler.DispatchScheduledNotifications(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, &ScheduledDispatchResult{Due: 1, Suppressed: 1}, result)
+}
+
+func TestPushHandler_DispatchScheduledNotifications_RetryableFailureReleasesClaim(t *testing.T) {
+	fx := createTestPushHandler(t)
+	ctx := context.Background()
+	notification := newTestScheduledNotification()
+
+	fx.notificationRepo.EXPECT().
+		FindDueScheduledNotifications(ctx, mock.Anything, mock.Anything, scheduledDispatchBatchSize).
+		Return([]*entity.MerchantLocationNotification{notification}, nil)
+	fx.notificationRepo.EXPECT().
+		ClaimScheduledNotification(ctx, notification, time.Duration(0), mock.Anything).
+		Return(repository.ScheduleClaimed, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, notification.MerchantID, notification.Latitude, notification.Longitude).
+		Return(nil, domainerrors.ErrPersistenceFailed)
+	fx.notificationRepo.EXPECT().
+		UpdateScheduleStatus(mock.Anything, notification.ID, entity.ScheduleStatusClaimed, entity.ScheduleStatusPending).
+		Return(true, nil)
+
+	result, err := fx.handler.DispatchScheduledNotifications(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, &ScheduledDispatchResult{Due: 1, Failed: 1}, result)
+}
+
+func TestPushHandler_DispatchScheduledNotifications_PermanentFailureFinishesClaim(t *testing.T) {
+	fx := createTestPushHandler(t)
+	fx.handler.recipientCap = policy.RecipientCapPolicy{MaxRecipients: 1, Strict: true}
+	ctx := context.Background()
+	notification := newTestScheduledNotification()
+	addresses := []*entity.SubscriberAddress{
+		{Address: entity.Address{OwnerID: uuid.New(), Latitude: 25.0335, Longitude: 121.5660}, NotificationRadius: 1000},
+		{Address: entity.Address{OwnerID: uuid.New(), Latitude: 25.0336, Longitude: 121.5661}, NotificationRadius: 1000},
+	}
+
+	fx.notificationRepo.EXPECT().
+		FindDueScheduledNotifications(ctx, mock.Anything, mock.Anything, scheduledDispatchBatchSize).
+		Return([]*entity.MerchantLocationNotification{notification}, nil)
+	fx.notificationRepo.EXPECT().
+		ClaimScheduledNotification(ctx, notification, time.Duration(0), mock.Anything).
+		Return(repository.ScheduleClaimed, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, notification.MerchantID, notification.Latitude, notification.Longitude).
+		Return(addresses, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByUserIDs(ctx, notification.MerchantID, usecase.SubscriberIDs(addresses)).
+		Return(addresses, nil)
+	// A strict cap rejection cannot succeed on a later run, so the notification is finished rather than
+	// retried once its claim expires
+	fx.notificationRepo.EXPECT().
+		UpdateScheduleStatus(mock.Anything, notification.ID, entity.ScheduleStatusClaimed, entity.ScheduleStatusDispatched).
+		Return(true, nil)
+
+	result, err := fx.handler.DispatchScheduledNotifications(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, &ScheduledDispatchResult{Due: 1, Failed: 1}, result)
+}
+
+func TestPushHandler_DispatchScheduledNotifications_PollError(t *testing.T) {
+	fx := createTestPushHandler(t)
+	ctx := context.Background()
+
+	fx.notificationRepo.EXPECT().
+		FindDueScheduledNotifications(ctx, mock.Anything, mock.Anything, scheduledDispatchBatchSize).
+		Return(nil, errors.New("database unavailable"))
+
+	_, err := fx.handler.DispatchScheduledNotifications(ctx)
+
+	require.Error(t, err)
+}
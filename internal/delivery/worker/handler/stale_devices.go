@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultStaleDeliveryDays is how long a device may go without a successful delivery before cleanup removes it
+	defaultStaleDeliveryDays = 60
+
+	// staleDeviceDeleteBatchSize bounds how many devices one lookup returns and one delete statement removes
+	staleDeviceDeleteBatchSize = 100
+)
+
+// StaleDeviceCleanupResult summarizes one run of the stale device cleanup
+type StaleDeviceCleanupResult struct {
+	Stale         int   // Devices whose last successful delivery predated the cutoff
+	Deleted       int64 // Devices deleted; devices deleted concurrently are not counted
+	FailedBatches int   // Delete batches that failed; their devices are picked up again by the next run
+}
+
+// CleanupStaleDevices deletes the devices the worker has not delivered to within the stale window.
+// Stale devices are looked up and deleted a page at a time, so one run never loads the whole set or
+// holds a long-running statement over it, and a failed batch does not stop the remaining ones.
+func (h *PushHandler) CleanupStaleDevices(ctx context.Context) (*StaleDeviceCleanupResult, error) {
+	cutoff := time.Now().AddDate(0, 0, -h.staleDeliveryDays)
+
+	result := &StaleDeviceCleanupResult{}
+	afterID := uuid.Nil
+	for {
+		devices, err := h.deviceRepo.FindStaleDevices(ctx, cutoff, afterID, staleDeviceDeleteBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("find stale devices: %w", err)
+		}
+		if len(devices) == 0 {
+			return result, nil
+		}
+
+		ids := make([]uuid.UUID, 0, len(devices))
+		for _, device := range devices {
+			ids = append(ids, device.ID)
+		}
+		// Pages continue after the last device, so a failed batch is not looked up again this run
+		afterID = ids[len(ids)-1]
+		result.Stale += len(ids)
+
+		deleted, err := h.deviceRepo.DeleteDevices(ctx, ids)
+		if err != nil {
+			h.logger.Warn("[Worker] Failed to delete stale devices",
+				slog.Int("batch_size", len(ids)),
+				slog.String("error", err.Error()),
+			)
+			result.FailedBatches++
+		} else {
+			result.Deleted += deleted
+		}
+
+		if len(devices) < staleDeviceDeleteBatchSize {
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"radar/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushHandler_CleanupStaleDevices_DeletesInBatches(t *testing.T) {
+	fx := createTestPushHandler(t)
+	ctx := context.Background()
+
+	devices := make([]*entity.UserDevice, 2*staleDeviceDeleteBatchSize+50)
+	ids := make([]uuid.UUID, len(devices))
+	for i := range devices {
+		ids[i] = uuid.New()
+		devices[i] = &entity.UserDevice{ID: ids[i]}
+	}
+
+	cutoff := mock.MatchedBy(func(cutoff time.Time) bool {
+		want := time.Now().AddDate(0, 0, -defaultStaleDeliveryDays)
+
+		return cutoff.Sub(want).Abs() < time.Minute
+	})
+	// Each page starts after the last device of the one before; a short page ends the run
+	fx.deviceRepo.EXPECT().FindStaleDevices(ctx, cutoff, uuid.Nil, staleDeviceDeleteBatchSize).
+		Return(devices[:staleDeviceDeleteBatchSize], nil).Once()
+	fx.deviceRepo.EXPECT().FindStaleDevices(ctx, cutoff, ids[staleDeviceDeleteBatchSize-1], staleDeviceDeleteBatchSize).
+		Return(devices[staleDeviceDeleteBatchSize:2*staleDeviceDeleteBatchSize], nil).Once()
+	fx.deviceRepo.EXPECT().FindStaleDevices(ctx, cutoff, ids[2*staleDeviceDeleteBatchSize-1], staleDeviceDeleteBatchSize).
+		Return(devices[2*staleDeviceDeleteBatchSize:], nil).Once()
+	fx.deviceRepo.EXPECT().DeleteDevices(ctx, ids[:staleDeviceDeleteBatchSize]).Return(int64(staleDeviceDeleteBatchSize), nil).Once()
+	// A failed batch does not stop the ones after it
+	fx.deviceRepo.EXPECT().DeleteDevices(ctx, ids[staleDeviceDeleteBatchSize:2*staleDeviceDeleteBatchSize]).Return(int64(0), errors.New("db down")).Once()
+	fx.deviceRepo.EXPECT().DeleteDevices(ctx, ids[2*staleDeviceDeleteBatchSize:]).Return(int64(48), nil).Once()
+
+	result, err := fx.handler.CleanupStaleDevices(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, &StaleDeviceCleanupResult{
+		Stale:         len(devices),
+		Deleted:       staleDeviceDeleteBatchSize + 48,
+		FailedBatches: 1,
+	}, result)
+}
+
+func TestPushHandler_CleanupStaleDevices_NothingStale(t *testing.T) {
+	fx := createTestPushHandler(t)
+	ctx := context.Background()
+
+	fx.deviceRepo.EXPECT().FindStaleDevices(ctx, mock.Anything, uuid.Nil, staleDeviceDeleteBatchSize).Return(nil, nil).Once()
+
+	result, err := fx.handler.CleanupStaleDevices(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, &StaleDeviceCleanupResult{}, result)
+}
+
+func TestPushHandler_CleanupStaleDevices_LookupError(t *testing.T) {
+	fx := createTestPushHandler(t)
+	ctx := context.Background()
+
+	fx.deviceRepo.EXPECT().FindStaleDevices(ctx, mock.Anything, uuid.Nil, staleDeviceDeleteBatchSize).Return(nil, errors.New("db down")).Once()
+
+	result, err := fx.handler.CleanupStaleDevices(ctx)
+
+	require.Error(t, err)
+	assert.Nil(t, result)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+
+	"radar/internal/platform/observability"
+	"radar/internal/usecase"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName identifies the worker's spans to the tracer provider
+const tracerName = "radar/internal/delivery/worker/handler"
+
+// Span names of the push pipeline
+const (
+	spanHandlePush            = "PushHandler.HandlePush"
+	spanFilterByDistance      = "PushHandler.filterSubscribersByDistance"
+	spanOneToMany             = "RoutingUsecase.OneToMany"
+	spanSendBatchNotification = "NotificationService.SendBatchNotification"
+)
+
+// attrRequestID carries the correlation ID that also tags the worker's log lines
+const attrRequestID = "request_id"
+
+// newTracer returns the worker's tracer from provider, or a no-op tracer when none is configured
+func newTracer(provider trace.TracerProvider) trace.Tracer {
+	if provider == nil {
+		provider = noop.NewTracerProvider()
+	}
+
+	return provider.Tracer(tracerName)
+}
+
+// startSpan starts a span tagged with the request ID in ctx. A span without a trace identity, as the no-op
+// tracer returns, has nothing to propagate, so ctx is returned unchanged for it.
+func startSpan(ctx context.Context, tracer trace.Tracer, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if requestID := observability.CorrelationIDFromContext(ctx); requestID != "" {
+		attrs = append(attrs, attribute.String(attrRequestID, requestID))
+	}
+
+	spanCtx, span := tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+	if !span.SpanContext().IsValid() {
+		return ctx, span
+	}
+
+	return spanCtx, span
+}
+
+// recordSpanError marks span as failed with err
+func recordSpanError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// endSpan records err on span, if any, and ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		recordSpanError(span, err)
+	}
+	span.End()
+}
+
+// tracedRouting wraps a routing service with a span around each OneToMany call
+type tracedRouting struct {
+	usecase.RoutingUsecase
+
+	tracer trace.Tracer
+}
+
+// OneToMany routes from source to targets inside a child span of ctx
+func (r tracedRouting) OneToMany(ctx context.Context, profile string, source usecase.Coordinate, targets []usecase.Coordinate) (*usecase.OneToManyResult, error) {
+	ctx, span := startSpan(ctx, r.tracer, spanOneToMany, attribute.Int("target_count", len(targets)))
+	result, err := r.RoutingUsecase.OneToMany(ctx, profile, source, targets)
+	endSpan(span, err)
+
+	return result, err
+}
@@ -11,30 +11,38 @@ import (
 	"radar/config"
 	"radar/internal/delivery"
 	apimiddleware "radar/internal/delivery/api/middleware"
+	"radar/internal/delivery/health"
 	"radar/internal/delivery/middleware"
 	"radar/internal/delivery/worker/handler"
 	"radar/internal/domain/lifecycle"
 
 	"github.com/labstack/echo/v4"
 	echomiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/fx"
 	"golang.org/x/net/http2"
 )
 
 type workerServer struct {
-	cfg    *config.Config
-	logger *slog.Logger
-	server *echo.Echo
+	cfg         *config.Config
+	logger      *slog.Logger
+	server      *echo.Echo
+	pushHandler *handler.PushHandler
 }
 
 // ServerParams holds dependencies for the worker server
 type ServerParams struct {
 	fx.In
 
-	Lc          fx.Lifecycle
-	Cfg         *config.Config
-	Logger      *slog.Logger
-	PushHandler *handler.PushHandler
+	Lc            fx.Lifecycle
+	Cfg           *config.Config
+	Logger        *slog.Logger
+	PushHandler   *handler.PushHandler
+	HealthHandler *health.Handler
+
+	// Registry served on /metrics; the endpoint is not registered without one
+	MetricsRegistry *prometheus.Registry `optional:"true"`
 }
 
 // NewServer creates a new worker HTTP server
@@ -73,13 +81,22 @@ func NewServer(params ServerParams) (delivery.Delivery, error) {
 		return c.JSON(200, map[string]string{"status": "ok"})
 	})
 
+	if params.MetricsRegistry != nil {
+		e.GET("/metrics", echo.WrapHandler(promhttp.HandlerFor(params.MetricsRegistry, promhttp.HandlerOpts{})))
+	}
+
+	// Liveness and readiness probes
+	e.GET("/healthz", params.HealthHandler.Live)
+	e.GET("/readyz", params.HealthHandler.Ready)
+
 	// Pub/Sub push endpoint
 	e.POST("/push", params.PushHandler.HandlePush)
 
 	srv := &workerServer{
-		cfg:    params.Cfg,
-		logger: params.Logger,
-		server: e,
+		cfg:         params.Cfg,
+		logger:      params.Logger,
+		server:      e,
+		pushHandler: params.PushHandler,
 	}
 
 	params.Lc.Append(fx.Hook{
@@ -110,5 +127,8 @@ func (s *workerServer) stop(ctx context.Context) error {
 
 	s.logger.Info("Shutting down Worker HTTP server")
 
+	// Turn away new pushes while in-flight messages drain
+	s.pushHandler.Drain()
+
 	return s.server.Shutdown(shutdownCtx)
 }
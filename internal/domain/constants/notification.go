@@ -0,0 +1,7 @@
+package constants
+
+// Push notification data payload keys shared by senders and providers
+const (
+	NotificationDataDeepLink    = "deep_link"
+	NotificationDataClickAction = "click_action"
+)
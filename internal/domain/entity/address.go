@@ -5,6 +5,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+	"github.com/paulmach/orb/planar"
 )
 
 // Address is the core entity for a physical location.
@@ -21,4 +24,19 @@ type Address struct {
 	IsActive    bool      `json:"is_active"`    // Indicates if this location is active for notifications.
 	CreatedAt   time.Time `json:"created_at"`   // Timestamp of when this address was created.
 	UpdatedAt   time.Time `json:"updated_at"`   // Timestamp of the last modification.
+
+	// GeofencePolygon is the neighborhood a subscriber wants broadcasts from, in [lng, lat] order.
+	// When set it replaces the notification radius; nil keeps the radius. Only user addresses have one.
+	GeofencePolygon geojson.Polygon `json:"geofence_polygon,omitempty"`
+}
+
+// HasGeofence reports whether the subscriber limited broadcasts to a polygon instead of a radius.
+func (a *Address) HasGeofence() bool {
+	return len(a.GeofencePolygon) > 0
+}
+
+// InGeofence reports whether the point lies inside the subscriber's geofence polygon, outside its holes.
+// It treats coordinates as planar, which is accurate enough for neighborhood-sized polygons.
+func (a *Address) InGeofence(lat, lng float64) bool {
+	return a.HasGeofence() && planar.PolygonContains(orb.Polygon(a.GeofencePolygon), orb.Point{lng, lat})
 }
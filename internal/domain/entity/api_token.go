@@ -0,0 +1,48 @@
+// Package entity contains the core business objects of the project.
+package entity
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APITokenPrefix starts every raw API token, telling it apart from a JWT in the Authorization header.
+const APITokenPrefix = "nnr_"
+
+// APITokenScope names an operation an API token may perform.
+type APITokenScope string
+
+const (
+	// APITokenScopePublishNotifications allows publishing location notifications on the merchant's behalf.
+	APITokenScopePublishNotifications APITokenScope = "notifications:publish"
+)
+
+// APIToken is a merchant-scoped credential for server-to-server calls, such as a POS announcing the store is open.
+// Only a hash of the raw token is stored; the raw token is shown once when the token is created.
+type APIToken struct {
+	ID         uuid.UUID       `json:"id"`                   // The unique ID for this API token.
+	MerchantID uuid.UUID       `json:"merchant_id"`          // The merchant the token acts for.
+	Name       string          `json:"name"`                 // Label the merchant gave the token, e.g. the integration using it.
+	TokenHash  string          `json:"-"`                    // SHA-256 hash of the raw token for lookup.
+	Scopes     []APITokenScope `json:"scopes"`               // Operations the token may perform.
+	RevokedAt  *time.Time      `json:"revoked_at,omitempty"` // When the merchant revoked the token; nil while it is active.
+	CreatedAt  time.Time       `json:"created_at"`           // Timestamp of when the token was created.
+}
+
+// IsRevoked reports whether the token has been revoked.
+func (t *APIToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// HasScope reports whether the token may perform the operation.
+func (t *APIToken) HasScope(scope APITokenScope) bool {
+	return slices.Contains(t.Scopes, scope)
+}
+
+// IsAPIToken reports whether a bearer credential is an API token rather than a JWT.
+func IsAPIToken(credential string) bool {
+	return strings.HasPrefix(credential, APITokenPrefix)
+}
@@ -62,6 +62,7 @@ type RefreshToken struct {
 	IsRevoked  bool       // Indicates whether the token has been rotated out or invalidated.
 	ReplacedBy *uuid.UUID // Points to the token that replaced this token during rotation.
 	ExpiresAt  time.Time  // The exact time when this refresh token will expire and become invalid.
+	LastUsedAt *time.Time // Last time the session refreshed its access token; nil if never used since login.
 	CreatedAt  time.Time  // Timestamp of when this session was created (i.e., when the user logged in).
 }
 
@@ -7,15 +7,42 @@ import (
 	"github.com/google/uuid"
 )
 
+// DeviceNotificationSettings overrides the owner's notification preferences for one device.
+// A nil field inherits the owner's setting.
+type DeviceNotificationSettings struct {
+	NotificationsEnabled *bool `json:"notifications_enabled,omitempty"` // Whether broadcasts are delivered to this device.
+	QuietHoursStart      *int  `json:"quiet_hours_start,omitempty"`     // Start of the device's daily quiet window in minutes after the owner's local midnight.
+	QuietHoursEnd        *int  `json:"quiet_hours_end,omitempty"`       // End of the device's daily quiet window, exclusive; before the start means it spans midnight.
+}
+
+// Suppresses reports whether the device's own settings keep a broadcast from it at now.
+// The owner's preferences are applied to the subscriber beforehand, so a device override can only silence
+// the device further: its quiet window applies on top of the owner's.
+func (s DeviceNotificationSettings) Suppresses(now time.Time, timezone string) bool {
+	if s.NotificationsEnabled != nil && !*s.NotificationsEnabled {
+		return true
+	}
+
+	return inQuietWindow(s.QuietHoursStart, s.QuietHoursEnd, timezone, now)
+}
+
+// HasQuietHours reports whether the device sets a quiet window of its own.
+func (s DeviceNotificationSettings) HasQuietHours() bool {
+	return s.QuietHoursStart != nil && s.QuietHoursEnd != nil
+}
+
 // UserDevice represents a user's device registered for push notifications.
 type UserDevice struct {
 	ID               uuid.UUID `json:"id"`                 // The Global Unique Identifier (GUID) for the device.
 	UserID           uuid.UUID `json:"user_id"`            // The ID of the user who owns this device.
 	FCMToken         string    `json:"fcm_token"`          // Firebase Cloud Messaging token for push notifications.
 	DeviceID         string    `json:"device_id"`          // Unique device identifier from the client.
-	Platform         string    `json:"platform"`           // Device platform (ios, android).
+	Platform         string    `json:"platform"`           // Device platform (ios, android, web).
+	AppVersion       string    `json:"app_version"`        // Client app version reported at registration (empty if unknown).
 	IsActive         bool      `json:"is_active"`          // Indicates if this device is active for notifications.
 	TokenRefreshedAt time.Time `json:"token_refreshed_at"` // Timestamp of the last token refresh reported by the client.
 	CreatedAt        time.Time `json:"created_at"`         // Timestamp of when this device was registered.
 	UpdatedAt        time.Time `json:"updated_at"`         // Timestamp of the last modification.
+
+	DeviceNotificationSettings // Per-device overrides of the owner's notification preferences.
 }
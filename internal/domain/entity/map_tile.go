@@ -0,0 +1,31 @@
+package entity
+
+import "math"
+
+// MapTile identifies a Web Mercator map tile
+type MapTile struct {
+	Zoom int `json:"zoom"`
+	X    int `json:"x"`
+	Y    int `json:"y"`
+}
+
+// mercatorMaxLat is the latitude limit of the Web Mercator projection
+const mercatorMaxLat = 85.05112878
+
+// TileAt returns the Web Mercator tile containing the coordinate at the given zoom.
+// Latitudes beyond the projection limit fall in the first or last tile row.
+func TileAt(lat, lng float64, zoom int) MapTile {
+	tiles := math.Exp2(float64(zoom))
+	lat = math.Max(-mercatorMaxLat, math.Min(mercatorMaxLat, lat))
+	latRad := lat * math.Pi / 180
+
+	x := math.Floor((lng + 180) / 360 * tiles)
+	y := math.Floor((1 - math.Log(math.Tan(latRad)+1/math.Cos(latRad))/math.Pi) / 2 * tiles)
+	last := tiles - 1
+
+	return MapTile{
+		Zoom: zoom,
+		X:    int(math.Max(0, math.Min(last, x))),
+		Y:    int(math.Max(0, math.Min(last, y))),
+	}
+}
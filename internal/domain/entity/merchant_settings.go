@@ -0,0 +1,20 @@
+// Package entity contains the core business objects of the project.
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MerchantSettings holds a merchant's overrides of service-wide behavior.
+// A nil field means the merchant has not overridden the default.
+type MerchantSettings struct {
+	MerchantID                  uuid.UUID `json:"merchant_id"`                             // The merchant these settings belong to.
+	MaxNotificationRadius       *float64  `json:"max_notification_radius,omitempty"`       // Upper bound (in meters) for subscriber notification radii.
+	BroadcastsPerHour           *int      `json:"broadcasts_per_hour,omitempty"`           // Maximum location broadcasts per hour; 0 means unlimited.
+	DistanceUnit                *string   `json:"distance_unit,omitempty"`                 // Unit used when presenting distances ("km" or "mi").
+	RotateNotificationTemplates *bool     `json:"rotate_notification_templates,omitempty"` // Whether broadcasts rotate through notification templates.
+	CreatedAt                   time.Time `json:"created_at"`                              // Timestamp of when the settings were first saved.
+	UpdatedAt                   time.Time `json:"updated_at"`                              // Timestamp of the last modification.
+}
@@ -19,19 +19,66 @@ type MerchantLocationNotification struct {
 	HintMessage  string     `json:"hint_message"`  // Optional hint message (e.g., "I'm at the first parking spot by the corner").
 	TotalSent    int        `json:"total_sent"`    // Total number of notifications successfully sent.
 	TotalFailed  int        `json:"total_failed"`  // Total number of notifications that failed to send.
-	PublishedAt  time.Time  `json:"published_at"`  // Timestamp of when the notification was published.
+	TotalOpened  int        `json:"total_opened"`  // Number of recipients who opened the notification.
+	PublishedAt  time.Time  `json:"published_at"`  // Timestamp of when the notification was published, or is scheduled to be.
 	CreatedAt    time.Time  `json:"created_at"`    // Timestamp of when this record was created.
 	UpdatedAt    time.Time  `json:"updated_at"`    // Timestamp of the last modification.
+
+	ScheduledAt    *time.Time     `json:"scheduled_at,omitempty"`    // When set, the notification is held until this time.
+	ScheduleStatus ScheduleStatus `json:"schedule_status,omitempty"` // Lifecycle of a scheduled notification; empty when sent immediately.
 }
 
+// ScheduleStatus tracks a scheduled notification from creation until it is dispatched or canceled.
+type ScheduleStatus string
+
+const (
+	// ScheduleStatusPending marks a scheduled notification that has not been sent yet.
+	ScheduleStatusPending ScheduleStatus = "pending"
+	// ScheduleStatusClaimed marks a scheduled notification a dispatch run is delivering. It becomes dispatched
+	// once delivered, pending again if the run releases it, and can be claimed by another run once the claim's
+	// lease has expired.
+	ScheduleStatusClaimed ScheduleStatus = "claimed"
+	// ScheduleStatusDispatched marks a scheduled notification that has been handed to delivery.
+	ScheduleStatusDispatched ScheduleStatus = "dispatched"
+	// ScheduleStatusCanceled marks a scheduled notification the merchant canceled before it was due.
+	ScheduleStatusCanceled ScheduleStatus = "canceled"
+)
+
 // NotificationLog represents a log entry for a single notification sent to a user device.
 type NotificationLog struct {
-	ID             uuid.UUID `json:"id"`              // The Global Unique Identifier (GUID) for the log entry.
-	NotificationID uuid.UUID `json:"notification_id"` // The ID of the notification this log belongs to.
-	UserID         uuid.UUID `json:"user_id"`         // The ID of the user who received the notification.
-	DeviceID       uuid.UUID `json:"device_id"`       // The ID of the device that received the notification.
-	Status         string    `json:"status"`          // The status of the notification (sent, failed).
-	FCMMessageID   string    `json:"fcm_message_id"`  // The Firebase Cloud Messaging message ID.
-	ErrorMessage   string    `json:"error_message"`   // Error message if the notification failed.
-	SentAt         time.Time `json:"sent_at"`         // Timestamp of when the notification was sent.
+	ID             uuid.UUID  `json:"id"`              // The Global Unique Identifier (GUID) for the log entry.
+	NotificationID uuid.UUID  `json:"notification_id"` // The ID of the notification this log belongs to.
+	UserID         uuid.UUID  `json:"user_id"`         // The ID of the user who received the notification.
+	DeviceID       uuid.UUID  `json:"device_id"`       // The ID of the device that received the notification.
+	Status         string     `json:"status"`          // The status of the notification (sent, failed).
+	FCMMessageID   string     `json:"fcm_message_id"`  // The Firebase Cloud Messaging message ID.
+	ErrorMessage   string     `json:"error_message"`   // Error message if the notification failed.
+	SentAt         time.Time  `json:"sent_at"`         // Timestamp of when the notification was sent.
+	OpenedAt       *time.Time `json:"opened_at"`       // Timestamp of when the recipient opened the notification, if they did.
+}
+
+// BroadcastDayStats summarizes the notifications a merchant sent on one UTC day.
+type BroadcastDayStats struct {
+	Day        time.Time `json:"day"`        // Midnight UTC at the start of the day.
+	Broadcasts int       `json:"broadcasts"` // Number of notifications sent that day.
+	Recipients int       `json:"recipients"` // Successful deliveries across those notifications.
+}
+
+// ReceivedNotification is one delivery of a merchant notification to a subscriber's device, together with the
+// notification it delivered. Failed deliveries are included so subscribers can see what did not reach them.
+type ReceivedNotification struct {
+	LogID          uuid.UUID  `json:"log_id"`          // The ID of the delivery log entry.
+	NotificationID uuid.UUID  `json:"notification_id"` // The ID of the delivered notification.
+	DeviceID       uuid.UUID  `json:"device_id"`       // The ID of the device the notification was sent to.
+	Status         string     `json:"status"`          // The delivery status (sent, failed).
+	SentAt         time.Time  `json:"sent_at"`         // Timestamp of when delivery was attempted.
+	OpenedAt       *time.Time `json:"opened_at"`       // Timestamp of when the subscriber opened the notification, if they did.
+	MerchantID     uuid.UUID  `json:"merchant_id"`     // The ID of the merchant who published the notification.
+	StoreName      string     `json:"store_name"`      // The merchant's store name.
+	LocationName   string     `json:"location_name"`   // The name/label of the notified location.
+	FullAddress    string     `json:"full_address"`    // The full address of the notified location.
+	Latitude       float64    `json:"latitude"`        // The geographic latitude of the location.
+	Longitude      float64    `json:"longitude"`       // The geographic longitude of the location.
+	HintMessage    string     `json:"hint_message"`    // The merchant's hint message, if any.
+	PublishedAt    time.Time  `json:"published_at"`    // Timestamp of when the notification was published.
 }
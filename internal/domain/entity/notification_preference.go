@@ -0,0 +1,112 @@
+// Package entity contains the core business objects of the project.
+package entity
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// minutesPerDay bounds the quiet-hours minute offsets.
+const minutesPerDay = 24 * 60
+
+// NotificationPreference holds a subscriber's controls over which broadcasts reach them.
+// Subscribers without a saved preference receive every in-radius broadcast.
+type NotificationPreference struct {
+	UserID              uuid.UUID   `json:"user_id"`                     // The subscriber these preferences belong to.
+	Timezone            string      `json:"timezone"`                    // IANA time zone quiet hours are evaluated in; empty or unknown means UTC.
+	QuietHoursStart     *int        `json:"quiet_hours_start,omitempty"` // Start of the daily quiet window in minutes after local midnight; nil disables quiet hours.
+	QuietHoursEnd       *int        `json:"quiet_hours_end,omitempty"`   // End of the daily quiet window, exclusive; before the start means it spans midnight.
+	OptedOutCategoryIDs []uuid.UUID `json:"opted_out_category_ids"`      // Discovery categories whose merchants' broadcasts are not delivered.
+	CreatedAt           time.Time   `json:"created_at"`                  // Timestamp of when the preferences were first saved.
+	UpdatedAt           time.Time   `json:"updated_at"`                  // Timestamp of the last modification.
+}
+
+// InQuietHours reports whether now falls inside the subscriber's quiet window in their time zone.
+func (p *NotificationPreference) InQuietHours(now time.Time) bool {
+	return inQuietWindow(p.QuietHoursStart, p.QuietHoursEnd, p.Timezone, now)
+}
+
+// inQuietWindow reports whether now falls inside the daily window from start to end in the time zone.
+// A nil bound or an empty window is never quiet.
+func inQuietWindow(quietStart, quietEnd *int, timezone string, now time.Time) bool {
+	if quietStart == nil || quietEnd == nil {
+		return false
+	}
+
+	start, end := *quietStart%minutesPerDay, *quietEnd%minutesPerDay
+	if start == end {
+		return false
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	local := now.In(location)
+	minute := local.Hour()*60 + local.Minute()
+
+	if start < end {
+		return minute >= start && minute < end
+	}
+
+	return minute >= start || minute < end
+}
+
+// OptedOutOf reports whether the subscriber opted out of broadcasts from merchants in the category.
+// A nil category means the merchant is uncategorized, which no subscriber can opt out of.
+func (p *NotificationPreference) OptedOutOf(categoryID *uuid.UUID) bool {
+	return categoryID != nil && slices.Contains(p.OptedOutCategoryIDs, *categoryID)
+}
+
+// Suppresses reports whether a broadcast in the category must not be delivered to the subscriber at now.
+func (p *NotificationPreference) Suppresses(now time.Time, categoryID *uuid.UUID) bool {
+	return p.InQuietHours(now) || p.OptedOutOf(categoryID)
+}
+
+// WithoutDeviceSuppressed returns the devices whose own notification settings allow a broadcast at now.
+// Device quiet hours are evaluated in the owner's time zone, or UTC for owners without a preference.
+func WithoutDeviceSuppressed(devices []*UserDevice, preferences []*NotificationPreference, now time.Time) []*UserDevice {
+	timezones := make(map[uuid.UUID]string, len(preferences))
+	for _, preference := range preferences {
+		timezones[preference.UserID] = preference.Timezone
+	}
+
+	allowed := make([]*UserDevice, 0, len(devices))
+	for _, device := range devices {
+		if device.Suppresses(now, timezones[device.UserID]) {
+			continue
+		}
+		allowed = append(allowed, device)
+	}
+
+	return allowed
+}
+
+// WithoutPreferenceSuppressed returns the addresses whose subscribers' preferences allow a broadcast in the
+// category at now. Subscribers without a preference are kept.
+func WithoutPreferenceSuppressed(
+	addresses []*SubscriberAddress,
+	preferences []*NotificationPreference,
+	now time.Time,
+	categoryID *uuid.UUID,
+) []*SubscriberAddress {
+	if len(preferences) == 0 {
+		return addresses
+	}
+
+	suppressed := make(map[uuid.UUID]bool, len(preferences))
+	for _, preference := range preferences {
+		suppressed[preference.UserID] = preference.Suppresses(now, categoryID)
+	}
+
+	allowed := make([]*SubscriberAddress, 0, len(addresses))
+	for _, addr := range addresses {
+		if !suppressed[addr.OwnerID] {
+			allowed = append(allowed, addr)
+		}
+	}
+
+	return allowed
+}
@@ -11,6 +11,8 @@ const (
 	RoleUser Role = "user"
 	// RoleMerchant indicates a merchant role.
 	RoleMerchant Role = "merchant"
+	// RoleAdmin indicates an operator account allowed to use admin endpoints.
+	RoleAdmin Role = "admin"
 )
 
 // String returns the string representation of the Role.
@@ -21,7 +23,7 @@ func (r Role) String() string {
 // IsValid checks if the Role is a valid value.
 func (r Role) IsValid() bool {
 	switch r {
-	case RoleUser, RoleMerchant:
+	case RoleUser, RoleMerchant, RoleAdmin:
 		return true
 	default:
 		return false
@@ -1,10 +1,59 @@
 package entity
 
+import (
+	"time"
+)
+
 // SubscriberAddress represents a user address bundled with the subscription's
 // notification radius. This is used for geospatial queries that need both the
 // address location and the user's chosen radius in one result to avoid N+1
 // lookups.
 type SubscriberAddress struct {
 	Address
-	NotificationRadius float64 `json:"notification_radius"`
+	NotificationRadius float64    `json:"notification_radius"`
+	SnoozedUntil       *time.Time `json:"snoozed_until,omitempty"` // Latest of the per-merchant and global broadcast snoozes.
+	IsMerchant         bool       `json:"is_merchant"`             // The subscriber also has a merchant account.
+}
+
+// IsSnoozed reports whether broadcasts to this subscriber are suppressed at now.
+// Snoozes expire on their own once now passes SnoozedUntil.
+func (a *SubscriberAddress) IsSnoozed(now time.Time) bool {
+	return a.SnoozedUntil != nil && now.Before(*a.SnoozedUntil)
+}
+
+// WithoutSnoozedSubscribers returns the addresses whose subscribers are not snoozed at now.
+func WithoutSnoozedSubscribers(addresses []*SubscriberAddress, now time.Time) []*SubscriberAddress {
+	active := make([]*SubscriberAddress, 0, len(addresses))
+	for _, addr := range addresses {
+		if !addr.IsSnoozed(now) {
+			active = append(active, addr)
+		}
+	}
+
+	return active
+}
+
+// WithoutMerchantSubscribers returns the addresses whose subscribers do not also have a merchant account.
+func WithoutMerchantSubscribers(addresses []*SubscriberAddress) []*SubscriberAddress {
+	consumers := make([]*SubscriberAddress, 0, len(addresses))
+	for _, addr := range addresses {
+		if !addr.IsMerchant {
+			consumers = append(consumers, addr)
+		}
+	}
+
+	return consumers
+}
+
+// SubscriberCandidate is a subscriber address within the notification radius of one source location.
+type SubscriberCandidate struct {
+	SourceIndex int // Index of the source location in the batched query
+	Address     *SubscriberAddress
+}
+
+// SubscriberTileBucket groups the candidates whose address falls in one map tile,
+// so the routing for every source with candidates in the tile can share one road graph.
+type SubscriberTileBucket struct {
+	Tile       MapTile
+	Candidates []SubscriberCandidate
 }
@@ -18,4 +18,6 @@ var (
 	ErrConflict               = NewBaseError(http.StatusConflict, "CONFLICT", "資源衝突", "")
 	ErrForbiddenHost          = NewBaseError(http.StatusForbidden, "FORBIDDEN_HOST", "不允許的網域", "")
 	ErrForbiddenOrigin        = NewBaseError(http.StatusForbidden, "FORBIDDEN_ORIGIN", "不允許的來源", "")
+	ErrRateLimitExceeded      = NewBaseError(http.StatusTooManyRequests, "RATE_LIMIT_EXCEEDED", "請求過於頻繁，請稍後再試", "")
+	ErrRequestTimeout         = NewBaseError(http.StatusGatewayTimeout, "REQUEST_TIMEOUT", "請求處理逾時，請稍後再試", "")
 )
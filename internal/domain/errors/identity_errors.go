@@ -4,6 +4,7 @@ import "net/http"
 
 var (
 	ErrUserNotFound              = NewBaseError(http.StatusNotFound, "USER_NOT_FOUND", "找不到該使用者", "")
+	ErrUserProfileNotFound       = NewBaseError(http.StatusNotFound, "USER_PROFILE_NOT_FOUND", "找不到使用者個人資料", "")
 	ErrUserAlreadyExists         = NewBaseError(http.StatusConflict, "USER_ALREADY_EXISTS", "此電子郵件已被註冊", "")
 	ErrUserCreateFailed          = NewBaseError(http.StatusInternalServerError, "USER_CREATE_FAILED", "建立使用者失敗", "")
 	ErrUserUpdateFailed          = NewBaseError(http.StatusInternalServerError, "USER_UPDATE_FAILED", "更新使用者失敗", "")
@@ -22,6 +23,7 @@ var (
 	ErrOAuthTokenInvalid         = NewBaseError(http.StatusBadRequest, "OAUTH_TOKEN_INVALID", "無效的 ID 權杖", "")
 	ErrRefreshTokenNotFound      = NewBaseError(http.StatusNotFound, "REFRESH_TOKEN_NOT_FOUND", "找不到重新整理權杖", "")
 	ErrRefreshTokenExpired       = NewBaseError(http.StatusUnauthorized, "REFRESH_TOKEN_EXPIRED", "重新整理權杖已過期", "")
+	ErrTokenReuseDetected        = NewBaseError(http.StatusUnauthorized, "REFRESH_TOKEN_REUSE_DETECTED", "偵測到重新整理權杖遭重複使用，已撤銷所有工作階段", "")
 	ErrRefreshTokenAlreadyExists = NewBaseError(
 		http.StatusConflict,
 		"REFRESH_TOKEN_ALREADY_EXISTS",
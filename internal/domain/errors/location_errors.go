@@ -9,6 +9,7 @@ var (
 	ErrAddressUpdateFailed       = NewBaseError(http.StatusInternalServerError, "ADDRESS_UPDATE_FAILED", "更新地址失敗", "")
 	ErrAddressOwnershipViolation = NewBaseError(http.StatusForbidden, "ADDRESS_OWNERSHIP_VIOLATION", "您沒有權限存取此地址", "")
 	ErrLocationLimitReached      = NewBaseError(http.StatusConflict, "LOCATION_LIMIT_REACHED", "已達位置數量上限", "")
+	ErrInvalidGeofence           = NewBaseError(http.StatusBadRequest, "INVALID_GEOFENCE", "地理圍欄多邊形無效", "")
 	ErrDeviceNotFound            = NewBaseError(http.StatusNotFound, "DEVICE_NOT_FOUND", "找不到該裝置", "")
 	ErrDeviceAlreadyExists       = NewBaseError(http.StatusConflict, "DEVICE_ALREADY_EXISTS", "裝置已存在", "")
 	ErrDeviceCreateFailed        = NewBaseError(http.StatusInternalServerError, "DEVICE_CREATE_FAILED", "建立裝置失敗", "")
@@ -40,5 +40,16 @@ var (
 		"建立通知紀錄失敗",
 		"",
 	)
+	ErrNotificationOwnershipViolation = NewBaseError(
+		http.StatusForbidden,
+		"NOTIFICATION_OWNERSHIP_VIOLATION",
+		"您沒有權限存取此通知",
+		"",
+	)
+	ErrNotificationNotCancelable  = NewBaseError(http.StatusConflict, "NOTIFICATION_NOT_CANCELABLE", "此通知已無法取消", "")
+	ErrRateLimited                = NewBaseError(http.StatusTooManyRequests, "BROADCAST_RATE_LIMITED", "此地點的通知發送過於頻繁，請稍後再試", "")
+	ErrRecipientCapExceeded       = NewBaseError(http.StatusUnprocessableEntity, "BROADCAST_RECIPIENT_CAP_EXCEEDED", "通知對象超過單次發送上限", "")
+	ErrMerchantSettingsNotFound   = NewBaseError(http.StatusNotFound, "MERCHANT_SETTINGS_NOT_FOUND", "找不到商家設定", "")
 	ErrSelfSubscriptionNotAllowed = NewBaseError(http.StatusBadRequest, "SELF_SUBSCRIPTION_NOT_ALLOWED", "不可訂閱自己", "")
+	ErrAPITokenNotFound           = NewBaseError(http.StatusNotFound, "API_TOKEN_NOT_FOUND", "找不到 API 權杖", "")
 )
@@ -0,0 +1,67 @@
+package policy
+
+import (
+	"strconv"
+	"strings"
+)
+
+// AppVersionAtLeast reports whether a dotted client version such as "2.4.1" is at or above minimum.
+// Missing components count as zero and pre-release or build suffixes are ignored, so "2.4" equals "2.4.0-beta".
+// An empty minimum accepts every version; a version that cannot be parsed never meets a minimum.
+func AppVersionAtLeast(version, minimum string) bool {
+	minParts, ok := parseAppVersion(minimum)
+	if !ok {
+		return true
+	}
+
+	parts, ok := parseAppVersion(version)
+	if !ok {
+		return false
+	}
+
+	for idx := range max(len(parts), len(minParts)) {
+		current, required := versionPart(parts, idx), versionPart(minParts, idx)
+		if current != required {
+			return current > required
+		}
+	}
+
+	return true
+}
+
+// ValidAppVersion reports whether version is a dotted numeric version
+func ValidAppVersion(version string) bool {
+	_, ok := parseAppVersion(version)
+
+	return ok
+}
+
+func parseAppVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if idx := strings.IndexAny(version, "-+"); idx >= 0 {
+		version = version[:idx]
+	}
+	if version == "" {
+		return nil, false
+	}
+
+	fields := strings.Split(version, ".")
+	parts := make([]int, len(fields))
+	for idx, field := range fields {
+		part, err := strconv.Atoi(field)
+		if err != nil || part < 0 {
+			return nil, false
+		}
+		parts[idx] = part
+	}
+
+	return parts, true
+}
+
+func versionPart(parts []int, idx int) int {
+	if idx < len(parts) {
+		return parts[idx]
+	}
+
+	return 0
+}
@@ -0,0 +1,37 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppVersionAtLeast(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		version  string
+		minimum  string
+		expected bool
+	}{
+		{name: "no minimum", version: "", minimum: "", expected: true},
+		{name: "equal", version: "2.4.0", minimum: "2.4.0", expected: true},
+		{name: "newer patch", version: "2.4.1", minimum: "2.4.0", expected: true},
+		{name: "older minor", version: "2.3.9", minimum: "2.4.0", expected: false},
+		{name: "numeric not lexical", version: "2.10.0", minimum: "2.9.0", expected: true},
+		{name: "missing components are zero", version: "2.4", minimum: "2.4.0", expected: true},
+		{name: "pre-release suffix ignored", version: "2.4.0-beta.1", minimum: "2.4.0", expected: true},
+		{name: "v prefix", version: "v3.0", minimum: "2.4.0", expected: true},
+		{name: "unknown version", version: "", minimum: "2.4.0", expected: false},
+		{name: "unparseable version", version: "latest", minimum: "2.4.0", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, AppVersionAtLeast(tt.version, tt.minimum))
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package policy
+
+import (
+	"hash/fnv"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// canaryBuckets is the resolution of the canary fraction; each user hashes to one bucket
+const canaryBuckets = 10000
+
+// CanaryPolicy defines domain rules for limiting broadcasts to a canary cohort of subscribers.
+// When enabled, a subscriber is in the cohort if their user ID is on AllowedUserIDs or hashes into
+// the first Fraction of the buckets. The hash only depends on the user ID, so the same users are chosen
+// on every broadcast. The zero value is disabled and delivers to everyone.
+type CanaryPolicy struct {
+	Enabled        bool
+	Fraction       float64  // Share of subscribers in the cohort, in [0, 1]
+	AllowedUserIDs []string // Users always in the cohort
+}
+
+// Includes reports whether a broadcast is delivered to the user under the policy.
+func (p CanaryPolicy) Includes(userID uuid.UUID) bool {
+	if !p.Enabled {
+		return true
+	}
+	if slices.ContainsFunc(p.AllowedUserIDs, func(allowed string) bool {
+		return strings.EqualFold(strings.TrimSpace(allowed), userID.String())
+	}) {
+		return true
+	}
+
+	return float64(canaryBucket(userID)) < p.Fraction*canaryBuckets
+}
+
+// canaryBucket hashes a user ID into [0, canaryBuckets)
+func canaryBucket(userID uuid.UUID) uint64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write(userID[:])
+
+	return hash.Sum64() % canaryBuckets
+}
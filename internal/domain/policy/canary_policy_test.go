@@ -0,0 +1,68 @@
+package policy
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanaryPolicy_Includes_StableCohort(t *testing.T) {
+	canary := CanaryPolicy{Enabled: true, Fraction: 0.1}
+
+	userIDs := make([]uuid.UUID, 2000)
+	for idx := range userIDs {
+		userIDs[idx] = uuid.NewSHA1(uuid.NameSpaceOID, fmt.Appendf(nil, "canary-user-%d", idx))
+	}
+
+	cohort := make(map[uuid.UUID]bool)
+	for _, userID := range userIDs {
+		if canary.Includes(userID) {
+			cohort[userID] = true
+		}
+	}
+	assert.InDelta(t, 200, len(cohort), 40, "about a tenth of the users are in the cohort")
+
+	// The same users are chosen on every broadcast and by every policy with the same fraction
+	again := CanaryPolicy{Enabled: true, Fraction: 0.1}
+	for _, userID := range userIDs {
+		assert.Equal(t, cohort[userID], again.Includes(userID), userID.String())
+	}
+
+	// Widening the fraction keeps the existing cohort
+	wider := CanaryPolicy{Enabled: true, Fraction: 0.5}
+	for userID := range cohort {
+		assert.True(t, wider.Includes(userID), userID.String())
+	}
+}
+
+func TestCanaryPolicy_Includes(t *testing.T) {
+	userID := uuid.MustParse("0b5f0d6c-3a58-4c55-9a36-1f6f1c1f2e01")
+
+	tests := []struct {
+		name   string
+		canary CanaryPolicy
+		want   bool
+	}{
+		{name: "disabled delivers to everyone", canary: CanaryPolicy{}, want: true},
+		{name: "empty cohort", canary: CanaryPolicy{Enabled: true}, want: false},
+		{name: "full cohort", canary: CanaryPolicy{Enabled: true, Fraction: 1}, want: true},
+		{
+			name:   "allowlisted user",
+			canary: CanaryPolicy{Enabled: true, AllowedUserIDs: []string{" 0B5F0D6C-3A58-4C55-9A36-1F6F1C1F2E01 "}},
+			want:   true,
+		},
+		{
+			name:   "other allowlisted user",
+			canary: CanaryPolicy{Enabled: true, AllowedUserIDs: []string{uuid.NewString()}},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.canary.Includes(userID))
+		})
+	}
+}
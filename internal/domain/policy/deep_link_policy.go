@@ -0,0 +1,76 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"radar/internal/domain/constants"
+)
+
+// DeepLinkMerchantIDPlaceholder is replaced with the merchant ID when building a store deep link.
+const DeepLinkMerchantIDPlaceholder = "{merchant_id}"
+
+var (
+	// ErrInvalidDeepLink indicates a deep link that cannot be parsed as an absolute URL.
+	ErrInvalidDeepLink = errors.New("invalid deep link")
+	// ErrDeepLinkSchemeNotAllowed indicates a deep link whose scheme is not on the allowlist.
+	ErrDeepLinkSchemeNotAllowed = errors.New("deep link scheme is not allowed")
+)
+
+// DeepLinkPolicy defines domain rules for action links attached to push notifications.
+// An empty Template disables deep links entirely.
+type DeepLinkPolicy struct {
+	Template       string
+	ClickAction    string
+	AllowedSchemes []string
+}
+
+// MerchantLink builds the store deep link for a merchant and validates it.
+// It returns an empty link when deep links are not configured.
+func (p DeepLinkPolicy) MerchantLink(merchantID string) (string, error) {
+	if strings.TrimSpace(p.Template) == "" {
+		return "", nil
+	}
+
+	link := strings.ReplaceAll(p.Template, DeepLinkMerchantIDPlaceholder, url.PathEscape(merchantID))
+	if err := p.Validate(link); err != nil {
+		return "", err
+	}
+
+	return link, nil
+}
+
+// Validate checks that a deep link is an absolute URL whose scheme is allowlisted.
+func (p DeepLinkPolicy) Validate(link string) error {
+	parsed, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || parsed.Scheme == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidDeepLink, link)
+	}
+
+	if !slices.ContainsFunc(p.AllowedSchemes, func(scheme string) bool {
+		return strings.EqualFold(strings.TrimSpace(scheme), parsed.Scheme)
+	}) {
+		return fmt.Errorf("%w: %q", ErrDeepLinkSchemeNotAllowed, parsed.Scheme)
+	}
+
+	return nil
+}
+
+// ApplyMerchantAction adds the merchant deep link and click action to a notification
+// data payload. The payload is left untouched when deep links are disabled or rejected.
+func (p DeepLinkPolicy) ApplyMerchantAction(data map[string]string, merchantID string) error {
+	link, err := p.MerchantLink(merchantID)
+	if err != nil || link == "" {
+		return err
+	}
+
+	data[constants.NotificationDataDeepLink] = link
+	if action := strings.TrimSpace(p.ClickAction); action != "" {
+		data[constants.NotificationDataClickAction] = action
+	}
+
+	return nil
+}
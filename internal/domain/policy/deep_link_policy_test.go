@@ -0,0 +1,92 @@
+package policy
+
+import (
+	"testing"
+
+	"radar/internal/domain/constants"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeepLinkPolicy_MerchantLink(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		policy   DeepLinkPolicy
+		expected string
+		err      error
+	}{
+		{
+			name:     "disabled without template",
+			policy:   DeepLinkPolicy{AllowedSchemes: []string{"nomnom"}},
+			expected: "",
+		},
+		{
+			name:     "custom app scheme",
+			policy:   DeepLinkPolicy{Template: "nomnom://merchants/{merchant_id}", AllowedSchemes: []string{"nomnom"}},
+			expected: "nomnom://merchants/merchant-1",
+		},
+		{
+			name:     "scheme match is case-insensitive",
+			policy:   DeepLinkPolicy{Template: "https://radar.example/m/{merchant_id}", AllowedSchemes: []string{"HTTPS"}},
+			expected: "https://radar.example/m/merchant-1",
+		},
+		{
+			name:   "disallowed scheme",
+			policy: DeepLinkPolicy{Template: "javascript:alert('{merchant_id}')", AllowedSchemes: []string{"nomnom", "https"}},
+			err:    ErrDeepLinkSchemeNotAllowed,
+		},
+		{
+			name:   "empty allowlist rejects everything",
+			policy: DeepLinkPolicy{Template: "nomnom://merchants/{merchant_id}"},
+			err:    ErrDeepLinkSchemeNotAllowed,
+		},
+		{
+			name:   "relative link",
+			policy: DeepLinkPolicy{Template: "/merchants/{merchant_id}", AllowedSchemes: []string{"nomnom"}},
+			err:    ErrInvalidDeepLink,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			link, err := tt.policy.MerchantLink("merchant-1")
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				assert.Empty(t, link)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, link)
+		})
+	}
+}
+
+func TestDeepLinkPolicy_ApplyMerchantAction(t *testing.T) {
+	t.Parallel()
+
+	allowed := DeepLinkPolicy{
+		Template:       "nomnom://merchants/{merchant_id}",
+		ClickAction:    "VIEW_STORE",
+		AllowedSchemes: []string{"nomnom"},
+	}
+	data := map[string]string{"merchant_id": "merchant-1"}
+
+	require.NoError(t, allowed.ApplyMerchantAction(data, "merchant-1"))
+	assert.Equal(t, "nomnom://merchants/merchant-1", data[constants.NotificationDataDeepLink])
+	assert.Equal(t, "VIEW_STORE", data[constants.NotificationDataClickAction])
+
+	rejected := allowed
+	rejected.Template = "ftp://merchants/{merchant_id}"
+	untouched := map[string]string{"merchant_id": "merchant-1"}
+
+	require.ErrorIs(t, rejected.ApplyMerchantAction(untouched, "merchant-1"), ErrDeepLinkSchemeNotAllowed)
+	assert.NotContains(t, untouched, constants.NotificationDataDeepLink)
+	assert.NotContains(t, untouched, constants.NotificationDataClickAction)
+}
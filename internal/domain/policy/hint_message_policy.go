@@ -0,0 +1,75 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrHintMessageTooLong indicates a hint message longer than the allowed number of characters.
+	ErrHintMessageTooLong = errors.New("hint message is too long")
+	// ErrHintMessageMultiline indicates a hint message containing line breaks.
+	ErrHintMessageMultiline = errors.New("hint message must be a single line")
+)
+
+// HintMessagePolicy defines domain rules for the merchant hint appended to a push notification body.
+type HintMessagePolicy struct {
+	MaxLength int // Maximum length in characters (runes)
+}
+
+func DefaultHintMessagePolicy() HintMessagePolicy {
+	return HintMessagePolicy{
+		MaxLength: 100,
+	}
+}
+
+// Normalize validates a hint supplied by a merchant and returns it with control characters stripped.
+// Line breaks and hints over MaxLength are rejected rather than silently altered.
+func (p HintMessagePolicy) Normalize(hint string) (string, error) {
+	if strings.ContainsFunc(hint, isLineBreak) {
+		return "", ErrHintMessageMultiline
+	}
+
+	normalized := strings.TrimSpace(stripControlCharacters(hint))
+	if length := utf8.RuneCountInString(normalized); length > p.MaxLength {
+		return "", fmt.Errorf("%w: %d characters exceeds %d", ErrHintMessageTooLong, length, p.MaxLength)
+	}
+
+	return normalized, nil
+}
+
+// Sanitize makes an already accepted hint safe to render, replacing line breaks with spaces,
+// stripping control characters and truncating it to MaxLength.
+func (p HintMessagePolicy) Sanitize(hint string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		if isLineBreak(r) {
+			return ' '
+		}
+
+		return r
+	}, hint)
+	sanitized = strings.TrimSpace(stripControlCharacters(sanitized))
+
+	if utf8.RuneCountInString(sanitized) > p.MaxLength {
+		sanitized = strings.TrimSpace(string([]rune(sanitized)[:p.MaxLength]))
+	}
+
+	return sanitized
+}
+
+func isLineBreak(r rune) bool {
+	return r == '\n' || r == '\r' || r == '\u2028' || r == '\u2029'
+}
+
+func stripControlCharacters(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || unicode.Is(unicode.Cf, r) {
+			return -1
+		}
+
+		return r
+	}, value)
+}
@@ -0,0 +1,86 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHintMessagePolicy_Normalize(t *testing.T) {
+	t.Parallel()
+
+	policy := HintMessagePolicy{MaxLength: 10}
+
+	tests := []struct {
+		name     string
+		hint     string
+		expected string
+		err      error
+	}{
+		{name: "empty hint", hint: "", expected: ""},
+		{name: "surrounding spaces trimmed", hint: "  By gate ", expected: "By gate"},
+		{name: "control characters stripped", hint: "Gate\x00\x07\t B\u200b", expected: "Gate B"},
+		{name: "limit counts characters not bytes", hint: "第一個停車格旁邊轉角", expected: "第一個停車格旁邊轉角"},
+		{name: "exactly max length", hint: "0123456789", expected: "0123456789"},
+		{name: "over-long hint rejected", hint: "0123456789X", err: ErrHintMessageTooLong},
+		{name: "newline rejected", hint: "Gate\nB", err: ErrHintMessageMultiline},
+		{name: "carriage return rejected", hint: "Gate\rB", err: ErrHintMessageMultiline},
+		{name: "unicode line separator rejected", hint: "Gate\u2028B", err: ErrHintMessageMultiline},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			normalized, err := policy.Normalize(tt.hint)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, normalized)
+		})
+	}
+}
+
+func TestHintMessagePolicy_Sanitize(t *testing.T) {
+	t.Parallel()
+
+	policy := HintMessagePolicy{MaxLength: 10}
+
+	tests := []struct {
+		name     string
+		hint     string
+		expected string
+	}{
+		{name: "plain hint", hint: "By gate", expected: "By gate"},
+		{name: "line breaks become spaces", hint: "Gate\r\nB", expected: "Gate  B"},
+		{name: "control characters stripped", hint: "\x1b[31mGate", expected: "[31mGate"},
+		{name: "over-long hint truncated by characters", hint: "第一個停車格旁邊轉角處", expected: "第一個停車格旁邊轉角"},
+		{name: "truncation trims trailing space", hint: "012345678 9", expected: "012345678"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, policy.Sanitize(tt.hint))
+		})
+	}
+}
+
+func TestDefaultHintMessagePolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := DefaultHintMessagePolicy()
+
+	_, err := policy.Normalize(strings.Repeat("a", policy.MaxLength))
+	require.NoError(t, err)
+
+	_, err = policy.Normalize(strings.Repeat("a", policy.MaxLength+1))
+	require.ErrorIs(t, err, ErrHintMessageTooLong)
+}
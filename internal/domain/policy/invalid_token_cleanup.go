@@ -0,0 +1,67 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"radar/internal/domain/entity"
+	"radar/internal/domain/repository"
+)
+
+// CleanupInvalidTokens strikes or deletes the devices whose token FCM reported invalid, each at most once, and
+// returns how many were deleted. Without strike tracking a device is deleted on the first response; otherwise
+// once it reaches the strike count. A failure on one device does not stop the others; the failures are joined.
+func (p InvalidTokenPolicy) CleanupInvalidTokens(
+	ctx context.Context,
+	deviceRepo repository.DeviceRepository,
+	invalidTokens []string,
+	deviceMap map[string]*entity.UserDevice,
+	now time.Time,
+) (int, error) {
+	deleted := 0
+	var errs []error
+	for _, device := range UniqueInvalidDevices(invalidTokens, deviceMap) {
+		if p.TracksStrikes() {
+			strikes, err := deviceRepo.RecordInvalidTokenStrike(ctx, device.ID, now, p.Window)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("record invalid token strike for device %s: %w", device.ID, err))
+
+				continue
+			}
+			if !p.ShouldDelete(strikes) {
+				continue
+			}
+		}
+
+		if err := deviceRepo.DeleteDevice(ctx, device.ID); err != nil {
+			errs = append(errs, fmt.Errorf("delete invalid device %s: %w", device.ID, err))
+
+			continue
+		}
+		deleted++
+	}
+
+	return deleted, errors.Join(errs...)
+}
+
+// ResetStrikes clears the invalid-token strikes of the devices the logs record as sent to.
+// It does nothing when the policy does not track strikes.
+func (p InvalidTokenPolicy) ResetStrikes(ctx context.Context, deviceRepo repository.DeviceRepository, logs []*entity.NotificationLog) error {
+	if !p.TracksStrikes() {
+		return nil
+	}
+
+	return deviceRepo.ResetInvalidTokenStrikes(ctx, SentDeviceIDs(logs))
+}
+
+// RecordDeliverySuccess stamps the devices the logs record as sent to, so stale-device cleanup keeps them.
+func RecordDeliverySuccess(ctx context.Context, deviceRepo repository.DeviceRepository, logs []*entity.NotificationLog, at time.Time) error {
+	deviceIDs := SentDeviceIDs(logs)
+	if len(deviceIDs) == 0 {
+		return nil
+	}
+
+	return deviceRepo.RecordDeliverySuccess(ctx, deviceIDs, at)
+}
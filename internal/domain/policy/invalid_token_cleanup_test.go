@@ -0,0 +1,78 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"radar/internal/domain/entity"
+	mockRepo "radar/internal/mocks/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidTokenPolicy_CleanupInvalidTokens_DeletesOnFirstResponse(t *testing.T) {
+	deviceRepo := mockRepo.NewMockDeviceRepository(t)
+	ctx := context.Background()
+	device := &entity.UserDevice{ID: uuid.New()}
+	failing := &entity.UserDevice{ID: uuid.New()}
+	deviceMap := map[string]*entity.UserDevice{"bad-token": device, "other-token": failing}
+
+	deviceRepo.EXPECT().DeleteDevice(ctx, device.ID).Return(nil).Once()
+	deviceRepo.EXPECT().DeleteDevice(ctx, failing.ID).Return(errors.New("db down")).Once()
+
+	deleted, err := InvalidTokenPolicy{}.CleanupInvalidTokens(ctx, deviceRepo, []string{"bad-token", "bad-token", "other-token", "unknown"}, deviceMap, time.Now())
+
+	assert.Equal(t, 1, deleted)
+	require.ErrorContains(t, err, "delete invalid device "+failing.ID.String())
+}
+
+func TestInvalidTokenPolicy_CleanupInvalidTokens_DeletesAtStrikeCount(t *testing.T) {
+	deviceRepo := mockRepo.NewMockDeviceRepository(t)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	struck, deleted := &entity.UserDevice{ID: uuid.New()}, &entity.UserDevice{ID: uuid.New()}
+	deviceMap := map[string]*entity.UserDevice{"struck-token": struck, "deleted-token": deleted}
+	invalidTokens := InvalidTokenPolicy{Strikes: 3, Window: 72 * time.Hour}
+
+	deviceRepo.EXPECT().RecordInvalidTokenStrike(ctx, struck.ID, now, 72*time.Hour).Return(2, nil).Once()
+	deviceRepo.EXPECT().RecordInvalidTokenStrike(ctx, deleted.ID, now, 72*time.Hour).Return(3, nil).Once()
+	deviceRepo.EXPECT().DeleteDevice(ctx, deleted.ID).Return(nil).Once()
+
+	count, err := invalidTokens.CleanupInvalidTokens(ctx, deviceRepo, []string{"struck-token", "deleted-token"}, deviceMap, now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestInvalidTokenPolicy_ResetStrikes(t *testing.T) {
+	deviceRepo := mockRepo.NewMockDeviceRepository(t)
+	ctx := context.Background()
+	sent, failed := uuid.New(), uuid.New()
+	logs := []*entity.NotificationLog{
+		{DeviceID: sent, Status: NotificationLogStatusSent},
+		{DeviceID: failed, Status: NotificationLogStatusFailed},
+	}
+
+	// Without strike tracking there is nothing to reset
+	require.NoError(t, InvalidTokenPolicy{}.ResetStrikes(ctx, deviceRepo, logs))
+
+	deviceRepo.EXPECT().ResetInvalidTokenStrikes(ctx, []uuid.UUID{sent}).Return(nil).Once()
+	require.NoError(t, InvalidTokenPolicy{Strikes: 3}.ResetStrikes(ctx, deviceRepo, logs))
+}
+
+func TestRecordDeliverySuccess_StampsSentDevices(t *testing.T) {
+	deviceRepo := mockRepo.NewMockDeviceRepository(t)
+	ctx := context.Background()
+	at := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	sent := uuid.New()
+
+	// No sent devices, no write
+	require.NoError(t, RecordDeliverySuccess(ctx, deviceRepo, []*entity.NotificationLog{{DeviceID: uuid.New(), Status: NotificationLogStatusFailed}}, at))
+
+	deviceRepo.EXPECT().RecordDeliverySuccess(ctx, []uuid.UUID{sent}, at).Return(nil).Once()
+	require.NoError(t, RecordDeliverySuccess(ctx, deviceRepo, []*entity.NotificationLog{{DeviceID: sent, Status: NotificationLogStatusSent}}, at))
+}
@@ -0,0 +1,30 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// fallbackNotificationSubject stands in for an empty location name
+	fallbackNotificationSubject = "您追蹤的商家"
+
+	// fallbackNotificationPlace stands in for an empty address
+	fallbackNotificationPlace = "附近"
+)
+
+// LocationNotificationBody formats the "opened at" notification body.
+// Empty names and addresses fall back to placeholders so the sentence always reads naturally.
+func LocationNotificationBody(locationName, fullAddress string) string {
+	subject := strings.TrimSpace(locationName)
+	if subject == "" {
+		subject = fallbackNotificationSubject
+	}
+
+	place := strings.TrimSpace(fullAddress)
+	if place == "" {
+		return fmt.Sprintf("%s 已在%s開始營業", subject, fallbackNotificationPlace)
+	}
+
+	return fmt.Sprintf("%s 已在 %s 開始營業", subject, place)
+}
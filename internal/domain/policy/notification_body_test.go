@@ -0,0 +1,32 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocationNotificationBody(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		locationName string
+		fullAddress  string
+		expected     string
+	}{
+		{name: "name and address", locationName: "Test Store", fullAddress: "123 Test St", expected: "Test Store 已在 123 Test St 開始營業"},
+		{name: "empty name", fullAddress: "123 Test St", expected: "您追蹤的商家 已在 123 Test St 開始營業"},
+		{name: "empty address", locationName: "Test Store", expected: "Test Store 已在附近開始營業"},
+		{name: "both empty", expected: "您追蹤的商家 已在附近開始營業"},
+		{name: "whitespace only treated as empty", locationName: "  ", fullAddress: "\t", expected: "您追蹤的商家 已在附近開始營業"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, LocationNotificationBody(tt.locationName, tt.fullAddress))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package policy
+
+import (
+	"fmt"
+
+	"radar/internal/domain/entity"
+	"radar/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// NotificationLogStatusSent marks a log whose push was accepted by FCM
+	NotificationLogStatusSent = "sent"
+
+	// NotificationLogStatusFailed marks a log whose push was rejected or never attempted
+	NotificationLogStatusFailed = "failed"
+)
+
+const (
+	// NotificationLogErrorUnregistered is the log error of a token FCM reported as no longer registered
+	NotificationLogErrorUnregistered = "unregistered token"
+
+	// NotificationLogErrorBudgetExceeded is the log error of a token skipped because the processing budget
+	// ran out before its batch
+	NotificationLogErrorBudgetExceeded = "budget_exceeded"
+)
+
+// SentDeviceIDs returns the devices whose log records a successful send, in log order.
+func SentDeviceIDs(logs []*entity.NotificationLog) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(logs))
+	for _, log := range logs {
+		if log.Status == NotificationLogStatusSent {
+			ids = append(ids, log.DeviceID)
+		}
+	}
+
+	return ids
+}
+
+// NotificationLogOutcome maps a token result onto a notification log status and error message.
+// A transient result is logged as failed; callers that redeliver transient tokens do not log them at all.
+func NotificationLogOutcome(result service.TokenResult) (status, errorMsg string) {
+	switch result.Status {
+	case service.TokenStatusSent:
+		return NotificationLogStatusSent, ""
+	case service.TokenStatusInvalid:
+		return NotificationLogStatusFailed, NotificationLogErrorUnregistered
+	default:
+		return NotificationLogStatusFailed, fmt.Sprintf("%s send error: %s", result.Status, result.ErrorCode)
+	}
+}
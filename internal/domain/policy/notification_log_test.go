@@ -0,0 +1,35 @@
+package policy
+
+import (
+	"testing"
+
+	"radar/internal/domain/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationLogOutcome(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		result     service.TokenResult
+		wantStatus string
+		wantError  string
+	}{
+		{name: "sent", result: service.TokenResult{Status: service.TokenStatusSent}, wantStatus: NotificationLogStatusSent},
+		{name: "invalid", result: service.TokenResult{Status: service.TokenStatusInvalid, ErrorCode: "UNREGISTERED"}, wantStatus: NotificationLogStatusFailed, wantError: NotificationLogErrorUnregistered},
+		{name: "failed", result: service.TokenResult{Status: service.TokenStatusFailed, ErrorCode: "INVALID_ARGUMENT"}, wantStatus: NotificationLogStatusFailed, wantError: "failed send error: INVALID_ARGUMENT"},
+		{name: "transient", result: service.TokenResult{Status: service.TokenStatusTransient, ErrorCode: "UNAVAILABLE"}, wantStatus: NotificationLogStatusFailed, wantError: "transient send error: UNAVAILABLE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			status, errorMsg := NotificationLogOutcome(tt.result)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantError, errorMsg)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package policy
+
+import (
+	"radar/config"
+)
+
+// NotificationPolicies groups the broadcast rules read from the notification config. The API and the worker are
+// injected the same value, so a broadcast is targeted, capped and delivered by the same rules on both sides.
+// The zero value applies no deep links, no canary, no cap and deletes invalid tokens on the first response.
+type NotificationPolicies struct {
+	DeepLink     DeepLinkPolicy
+	Canary       CanaryPolicy
+	InvalidToken InvalidTokenPolicy
+	RecipientCap RecipientCapPolicy
+}
+
+// NewNotificationPolicies builds the notification policies from the notification config.
+func NewNotificationPolicies(cfg *config.Config) NotificationPolicies {
+	if cfg == nil || cfg.Notification == nil {
+		return NotificationPolicies{}
+	}
+	notification := cfg.Notification
+
+	return NotificationPolicies{
+		DeepLink: DeepLinkPolicy{
+			Template:       notification.DeepLinkTemplate,
+			ClickAction:    notification.ClickAction,
+			AllowedSchemes: notification.AllowedDeepLinkSchemes,
+		},
+		Canary: CanaryPolicy{
+			Enabled:        notification.Canary.Enabled,
+			Fraction:       notification.Canary.Fraction,
+			AllowedUserIDs: notification.Canary.UserIDs,
+		},
+		InvalidToken: InvalidTokenPolicy{
+			Strikes: notification.InvalidTokenStrikes,
+			Window:  notification.InvalidTokenStrikeWindow,
+		},
+		RecipientCap: RecipientCapPolicy{
+			MaxRecipients: notification.MaxRecipientsPerBroadcast,
+			Strict:        notification.StrictRecipientCap,
+		},
+	}
+}
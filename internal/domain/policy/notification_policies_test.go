@@ -0,0 +1,35 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"radar/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNotificationPolicies_MapsNotificationConfig(t *testing.T) {
+	policies := NewNotificationPolicies(&config.Config{Notification: &config.NotificationConfig{
+		DeepLinkTemplate:          "radar://merchant/{merchantId}",
+		ClickAction:               "OPEN_MERCHANT",
+		AllowedDeepLinkSchemes:    []string{"radar"},
+		Canary:                    config.CanaryConfig{Enabled: true, Fraction: 0.05, UserIDs: []string{"user-1"}},
+		InvalidTokenStrikes:       3,
+		InvalidTokenStrikeWindow:  72 * time.Hour,
+		MaxRecipientsPerBroadcast: 500,
+		StrictRecipientCap:        true,
+	}})
+
+	assert.Equal(t, NotificationPolicies{
+		DeepLink:     DeepLinkPolicy{Template: "radar://merchant/{merchantId}", ClickAction: "OPEN_MERCHANT", AllowedSchemes: []string{"radar"}},
+		Canary:       CanaryPolicy{Enabled: true, Fraction: 0.05, AllowedUserIDs: []string{"user-1"}},
+		InvalidToken: InvalidTokenPolicy{Strikes: 3, Window: 72 * time.Hour},
+		RecipientCap: RecipientCapPolicy{MaxRecipients: 500, Strict: true},
+	}, policies)
+}
+
+func TestNewNotificationPolicies_MissingConfigIsZero(t *testing.T) {
+	assert.Equal(t, NotificationPolicies{}, NewNotificationPolicies(nil))
+	assert.Equal(t, NotificationPolicies{}, NewNotificationPolicies(&config.Config{}))
+}
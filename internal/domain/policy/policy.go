@@ -1,5 +1,13 @@
 package policy
 
+import (
+	"time"
+
+	"radar/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
 // DevicePolicy defines domain rules for device token health and stale cleanup.
 type DevicePolicy struct {
 	HealthyWindowDays int
@@ -18,6 +26,27 @@ type RefreshTokenPolicy struct {
 	RevokedRetentionDays int
 }
 
+// NotificationLogPolicy defines domain rules for per-device notification log retention.
+type NotificationLogPolicy struct {
+	RetentionDays int
+}
+
+// InvalidTokenPolicy defines domain rules for deleting devices whose token FCM reports as invalid.
+// A device is deleted once it collects Strikes consecutive invalid responses within Window;
+// a successful send resets its count. Strikes of one or less deletes on the first response.
+type InvalidTokenPolicy struct {
+	Strikes int
+	Window  time.Duration
+}
+
+// RecipientCapPolicy defines domain rules for bounding how many subscribers one broadcast reaches, so a merchant
+// with a very large audience cannot run up push costs. Over the cap a strict policy rejects the broadcast and
+// otherwise only the nearest MaxRecipients subscribers receive it. MaxRecipients of zero or less sets no cap.
+type RecipientCapPolicy struct {
+	MaxRecipients int
+	Strict        bool
+}
+
 func DefaultDevicePolicy() DevicePolicy {
 	return DevicePolicy{
 		HealthyWindowDays: 30,
@@ -39,6 +68,47 @@ func DefaultRefreshTokenPolicy() RefreshTokenPolicy {
 	}
 }
 
+// PurgeCutoff returns the send time before which notification logs are purged.
+// Logs sent exactly at the cutoff are kept.
+func (p NotificationLogPolicy) PurgeCutoff(now time.Time) time.Time {
+	return now.AddDate(0, 0, -p.RetentionDays)
+}
+
+// TracksStrikes reports whether devices survive invalid-token responses until they reach the strike count.
+func (p InvalidTokenPolicy) TracksStrikes() bool {
+	return p.Strikes > 1
+}
+
+// ShouldDelete reports whether a device with the given consecutive strikes is deleted.
+func (p InvalidTokenPolicy) ShouldDelete(strikes int) bool {
+	return strikes >= max(p.Strikes, 1)
+}
+
+// UniqueInvalidDevices resolves the tokens FCM reported invalid to their devices, dropping unknown tokens
+// and duplicates while keeping the reported order, so each device is struck or deleted at most once.
+func UniqueInvalidDevices(invalidTokens []string, deviceMap map[string]*entity.UserDevice) []*entity.UserDevice {
+	seen := make(map[uuid.UUID]struct{}, len(invalidTokens))
+	devices := make([]*entity.UserDevice, 0, len(invalidTokens))
+	for _, token := range invalidTokens {
+		device, ok := deviceMap[token]
+		if !ok || device == nil {
+			continue
+		}
+		if _, duplicate := seen[device.ID]; duplicate {
+			continue
+		}
+		seen[device.ID] = struct{}{}
+		devices = append(devices, device)
+	}
+
+	return devices
+}
+
+// Exceeded reports whether a broadcast to the given number of subscribers is over the cap.
+func (p RecipientCapPolicy) Exceeded(recipients int) bool {
+	return p.MaxRecipients > 0 && recipients > p.MaxRecipients
+}
+
 // LockoutMinutes computes lockout duration for the next lockout event.
 // lockoutCount is the historical lockout count before increment.
 func (p LoginThrottlePolicy) LockoutMinutes(lockoutCount int) int {
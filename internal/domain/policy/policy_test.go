@@ -0,0 +1,63 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"radar/internal/domain/entity"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationLogPolicy_PurgeCutoff(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 14, 3, 0, 0, 0, time.UTC)
+	cutoff := NotificationLogPolicy{RetentionDays: 90}.PurgeCutoff(now)
+
+	assert.Equal(t, time.Date(2026, 7, 16, 3, 0, 0, 0, time.UTC), cutoff)
+	assert.Equal(t, now, NotificationLogPolicy{}.PurgeCutoff(now))
+}
+
+func TestInvalidTokenPolicy_ShouldDelete(t *testing.T) {
+	t.Parallel()
+
+	immediate := InvalidTokenPolicy{}
+	assert.False(t, immediate.TracksStrikes())
+	assert.True(t, immediate.ShouldDelete(1))
+
+	graced := InvalidTokenPolicy{Strikes: 3, Window: 72 * time.Hour}
+	assert.True(t, graced.TracksStrikes())
+	assert.False(t, graced.ShouldDelete(0), "a missing device has nothing to delete")
+	assert.False(t, graced.ShouldDelete(2))
+	assert.True(t, graced.ShouldDelete(3))
+	assert.True(t, graced.ShouldDelete(4))
+}
+
+func TestUniqueInvalidDevices(t *testing.T) {
+	t.Parallel()
+
+	first := &entity.UserDevice{ID: uuid.New()}
+	second := &entity.UserDevice{ID: uuid.New()}
+	deviceMap := map[string]*entity.UserDevice{
+		"a": first,
+		"b": second,
+		"c": first, // rebound token pointing at the same device
+	}
+
+	devices := UniqueInvalidDevices([]string{"b", "a", "c", "b", "missing"}, deviceMap)
+
+	assert.Equal(t, []*entity.UserDevice{second, first}, devices)
+}
+
+func TestRecipientCapPolicy_Exceeded(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, RecipientCapPolicy{}.Exceeded(1_000_000), "the zero value sets no cap")
+
+	capped := RecipientCapPolicy{MaxRecipients: 100}
+	assert.False(t, capped.Exceeded(99))
+	assert.False(t, capped.Exceeded(100))
+	assert.True(t, capped.Exceeded(101))
+}
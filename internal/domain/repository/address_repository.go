@@ -27,13 +27,29 @@ type AddressRepository interface {
 	// UpdateAddress updates an existing address record.
 	UpdateAddress(ctx context.Context, address *entity.Address) error
 
-	// DeleteAddress removes an address by its ID.
+	// DeleteAddress soft-deletes an address by its ID, keeping the row for history and earlier notifications.
 	DeleteAddress(ctx context.Context, id uuid.UUID) error
 
+	// FindDeletedAddressByID retrieves a soft-deleted address by its ID.
+	// Returns ErrAddressNotFound if no soft-deleted address has that ID.
+	FindDeletedAddressByID(ctx context.Context, id uuid.UUID) (*entity.Address, error)
+
+	// RestoreAddress undoes the soft delete of an address by its ID. The address comes back as
+	// non-primary, since another address may have become primary while it was deleted.
+	// Returns ErrAddressNotFound if no soft-deleted address has that ID.
+	RestoreAddress(ctx context.Context, id uuid.UUID) error
+
+	// LockOwner locks the owner's profile row for the current transaction, serializing changes to the
+	// owner's location count. Returns ErrUserProfileNotFound or ErrMerchantNotFound if the owner has no profile.
+	LockOwner(ctx context.Context, ownerID uuid.UUID, ownerType entity.OwnerType) error
+
 	// CountAddressesByOwner returns the total count of addresses for a specific owner.
 	// Used for checking location limits.
 	CountAddressesByOwner(ctx context.Context, ownerID uuid.UUID, ownerType entity.OwnerType) (int64, error)
 
 	// FindActiveAddressesByOwner retrieves all active addresses (IsActive=true and not soft-deleted) for a specific owner.
 	FindActiveAddressesByOwner(ctx context.Context, ownerID uuid.UUID, ownerType entity.OwnerType) ([]*entity.Address, error)
+
+	// FindPrimaryAddressesByOwnerType retrieves the active primary address of every owner of the given type.
+	FindPrimaryAddressesByOwnerType(ctx context.Context, ownerType entity.OwnerType) ([]*entity.Address, error)
 }
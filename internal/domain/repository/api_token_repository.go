@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"radar/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// APITokenRepository defines the interface for merchant API token persistence.
+type APITokenRepository interface {
+	// CreateAPIToken persists a new API token.
+	CreateAPIToken(ctx context.Context, token *entity.APIToken) error
+
+	// FindAPITokenByHash retrieves an API token, including a revoked one, by the hash of its raw value.
+	// It returns ErrAPITokenNotFound when no token has the hash.
+	FindAPITokenByHash(ctx context.Context, tokenHash string) (*entity.APIToken, error)
+
+	// ListAPITokensByMerchant retrieves a merchant's API tokens, newest first, including revoked ones.
+	ListAPITokensByMerchant(ctx context.Context, merchantID uuid.UUID) ([]*entity.APIToken, error)
+
+	// RevokeAPIToken marks one of a merchant's active API tokens as revoked.
+	// It returns ErrAPITokenNotFound when the merchant has no active token with the ID.
+	RevokeAPIToken(ctx context.Context, merchantID, tokenID uuid.UUID, revokedAt time.Time) error
+}
@@ -28,6 +28,7 @@ type DeviceHealthRecord struct {
 // DeviceRepository defines the interface for device-related database operations.
 type DeviceRepository interface {
 	// CreateDevice persists a new device for a user.
+	// A live device of the same user with the same FCM token is updated with the device's metadata instead.
 	CreateDevice(ctx context.Context, device *entity.UserDevice) error
 
 	// FindDeviceByID retrieves a device by its unique ID.
@@ -48,6 +49,12 @@ type DeviceRepository interface {
 	// UpdateFCMToken updates the FCM token for a specific device.
 	UpdateFCMToken(ctx context.Context, deviceID uuid.UUID, fcmToken string) error
 
+	// UpdateDeviceClientInfo updates the platform and app version reported by the device's client.
+	UpdateDeviceClientInfo(ctx context.Context, deviceID uuid.UUID, platform, appVersion string) error
+
+	// UpdateDeviceNotificationSettings replaces the device's overrides of its owner's notification preferences.
+	UpdateDeviceNotificationSettings(ctx context.Context, deviceID uuid.UUID, settings entity.DeviceNotificationSettings) error
+
 	// SetDeviceActive updates the device active state without soft-deleting it.
 	SetDeviceActive(ctx context.Context, id uuid.UUID, isActive bool) error
 
@@ -57,6 +64,26 @@ type DeviceRepository interface {
 	// SoftDeleteStaleDevices soft-deletes devices whose token freshness exceeds the provided threshold.
 	SoftDeleteStaleDevices(ctx context.Context, staleDays int) (int64, error)
 
+	// RecordInvalidTokenStrike counts an invalid-token response for a device and returns its consecutive strikes.
+	// A strike more than window after the first strike of the current run starts a new run.
+	// A missing or already-deleted device is reported with zero strikes.
+	RecordInvalidTokenStrike(ctx context.Context, id uuid.UUID, at time.Time, window time.Duration) (int, error)
+
+	// ResetInvalidTokenStrikes clears the invalid-token strikes of devices whose token was delivered to.
+	ResetInvalidTokenStrikes(ctx context.Context, ids []uuid.UUID) error
+
+	// RecordDeliverySuccess stamps the given devices as last delivered to at.
+	RecordDeliverySuccess(ctx context.Context, ids []uuid.UUID, at time.Time) error
+
+	// FindStaleDevices retrieves up to limit devices whose last successful delivery predates olderThan,
+	// in ID order after afterID (uuid.Nil starts from the first). Devices never delivered to are
+	// measured from when they were registered.
+	FindStaleDevices(ctx context.Context, olderThan time.Time, afterID uuid.UUID, limit int) ([]*entity.UserDevice, error)
+
+	// DeleteDevices removes the devices with the given IDs (soft delete) and returns how many were deleted.
+	DeleteDevices(ctx context.Context, ids []uuid.UUID) (int64, error)
+
 	// DeleteDevice removes a device by its ID (soft delete).
+	// It is idempotent: deleting a missing or already-deleted device returns nil.
 	DeleteDevice(ctx context.Context, id uuid.UUID) error
 }
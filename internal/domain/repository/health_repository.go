@@ -0,0 +1,9 @@
+package repository
+
+import "context"
+
+// HealthRepository defines the interface for checking that the database is reachable.
+type HealthRepository interface {
+	// Ping checks that the database accepts connections.
+	Ping(ctx context.Context) error
+}
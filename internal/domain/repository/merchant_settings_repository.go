@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"radar/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// MerchantSettingsRepository defines the interface for per-merchant settings persistence.
+type MerchantSettingsRepository interface {
+	// FindMerchantSettings retrieves the settings row for a merchant.
+	// It returns ErrMerchantSettingsNotFound when the merchant has never saved settings.
+	FindMerchantSettings(ctx context.Context, merchantID uuid.UUID) (*entity.MerchantSettings, error)
+
+	// UpsertMerchantSettings creates or replaces the settings row for a merchant.
+	UpsertMerchantSettings(ctx context.Context, settings *entity.MerchantSettings) error
+}
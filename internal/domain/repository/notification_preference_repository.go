@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"radar/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// NotificationPreferenceRepository defines the interface for loading subscriber notification preferences.
+type NotificationPreferenceRepository interface {
+	// FindNotificationPreferences retrieves the saved preferences of the given users.
+	// Users who never saved preferences have no entry in the result.
+	FindNotificationPreferences(ctx context.Context, userIDs []uuid.UUID) ([]*entity.NotificationPreference, error)
+
+	// FindMerchantCategoryID retrieves the discovery category a merchant's broadcasts are filed under.
+	// It returns nil when the merchant has no category.
+	FindMerchantCategoryID(ctx context.Context, merchantID uuid.UUID) (*uuid.UUID, error)
+}
@@ -2,17 +2,43 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"radar/internal/domain/entity"
 
 	"github.com/google/uuid"
 )
 
+// ScheduleClaim is the outcome of claiming a due scheduled notification for dispatch.
+type ScheduleClaim int
+
+const (
+	// ScheduleClaimed means the notification is now dispatched and the caller should send it.
+	ScheduleClaimed ScheduleClaim = iota
+	// ScheduleClaimLost means the notification was no longer pending, canceled or claimed by another run.
+	ScheduleClaimLost
+	// ScheduleClaimInCooldown means the merchant broadcast from the same location within the cooldown,
+	// so the notification was canceled instead of sent.
+	ScheduleClaimInCooldown
+)
+
 // NotificationRepository defines the interface for notification-related database operations.
 type NotificationRepository interface {
-	// CreateNotification persists a new merchant location notification.
+	// CreateNotification persists a new merchant location notification, including a scheduled one
+	// that is held as pending until it is due.
 	CreateNotification(ctx context.Context, notification *entity.MerchantLocationNotification) error
 
+	// CreateNotificationOutsideCooldown persists an immediate notification unless the merchant already sent one
+	// from the same location within cooldown of its publish time; then nothing is created and the publish time
+	// of that earlier notification is returned. A saved address matches by AddressID; otherwise the exact
+	// coordinates of an ad-hoc location match. Scheduled notifications only count once they are dispatched.
+	// Concurrent calls for the same merchant are serialized, so at most one of them passes the check.
+	CreateNotificationOutsideCooldown(
+		ctx context.Context,
+		notification *entity.MerchantLocationNotification,
+		cooldown time.Duration,
+	) (*time.Time, error)
+
 	// FindNotificationByID retrieves a notification by its unique ID.
 	FindNotificationByID(ctx context.Context, id uuid.UUID) (*entity.MerchantLocationNotification, error)
 
@@ -22,9 +48,59 @@ type NotificationRepository interface {
 	// UpdateNotificationStatus updates the total sent and failed counts for a notification.
 	UpdateNotificationStatus(ctx context.Context, id uuid.UUID, totalSent, totalFailed int) error
 
+	// AddNotificationResults adds the sent and failed counts of one delivery attempt to a notification's totals,
+	// so a redelivery that sends the remaining devices does not overwrite the counts of the first attempt.
+	AddNotificationResults(ctx context.Context, id uuid.UUID, sent, failed int) error
+
 	// CreateNotificationLog persists a single notification log entry.
 	CreateNotificationLog(ctx context.Context, log *entity.NotificationLog) error
 
 	// BatchCreateNotificationLogs persists multiple notification log entries in a batch for better performance.
 	BatchCreateNotificationLogs(ctx context.Context, logs []*entity.NotificationLog) error
+
+	// FindLoggedDeviceIDs returns the devices that already have a log for the notification, sent or failed.
+	FindLoggedDeviceIDs(ctx context.Context, notificationID uuid.UUID) ([]uuid.UUID, error)
+
+	// FindNotificationLogsByUser retrieves the user's delivery logs, newest first, each with the notification it
+	// delivered and the merchant's store name. Failed deliveries are included.
+	FindNotificationLogsByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entity.ReceivedNotification, error)
+
+	// SumBroadcastsByDay counts the merchant's notifications published at or after from and before to, and sums
+	// their successful deliveries, per UTC day, oldest first. Days without notifications are omitted, and
+	// scheduled notifications only count once they are dispatched.
+	SumBroadcastsByDay(ctx context.Context, merchantID uuid.UUID, from, to time.Time) ([]*entity.BroadcastDayStats, error)
+
+	// MarkNotificationOpened records the time the user opened a notification that was sent to them and counts
+	// the user once on the notification. It returns false when the open was already recorded, and
+	// ErrNotificationNotFound when the notification was never sent to the user.
+	MarkNotificationOpened(ctx context.Context, notificationID, userID uuid.UUID, openedAt time.Time) (bool, error)
+
+	// FindDueScheduledNotifications retrieves pending scheduled notifications due at or before dueBy, and claimed
+	// ones whose claim was taken before claimedBefore by a run that never finished them, oldest first.
+	// A non-positive limit returns every due notification.
+	FindDueScheduledNotifications(
+		ctx context.Context,
+		dueBy, claimedBefore time.Time,
+		limit int,
+	) ([]*entity.MerchantLocationNotification, error)
+
+	// ClaimScheduledNotification moves a pending notification, or a claimed one whose claim was taken before
+	// claimedBefore, to claimed so only one run sends it. When the merchant sent another notification from the
+	// same location within cooldown of its publish time it is canceled instead, under the same per-merchant
+	// serialization as CreateNotificationOutsideCooldown. The run moves a claimed notification to dispatched
+	// with UpdateScheduleStatus once it is delivered.
+	ClaimScheduledNotification(
+		ctx context.Context,
+		notification *entity.MerchantLocationNotification,
+		cooldown time.Duration,
+		claimedBefore time.Time,
+	) (ScheduleClaim, error)
+
+	// UpdateScheduleStatus moves a scheduled notification to status to only while it is still in status from.
+	// It returns false when the notification was not in status from, leaving it unchanged.
+	UpdateScheduleStatus(ctx context.Context, id uuid.UUID, from, to entity.ScheduleStatus) (bool, error)
+
+	// PurgeOldNotificationLogs deletes log entries sent before olderThan and returns the number removed.
+	// Notification summaries (total sent/failed counts) are kept.
+	PurgeOldNotificationLogs(ctx context.Context, olderThan time.Time) (int64, error)
 }
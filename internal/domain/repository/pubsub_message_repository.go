@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// MessageClaim is the outcome of claiming a Pub/Sub message ID before processing it.
+type MessageClaim int
+
+const (
+	// MessageClaimed means the caller now holds the message and should process it.
+	MessageClaimed MessageClaim = iota
+	// MessageProcessed means an earlier delivery already finished the message.
+	MessageProcessed
+	// MessageInFlight means another delivery holds an unexpired claim on the message.
+	MessageInFlight
+)
+
+// PubSubMessageRepository records the Pub/Sub message IDs the push handler has seen so a
+// redelivered message is not processed twice.
+type PubSubMessageRepository interface {
+	// ClaimMessage records messageID as being processed until leaseUntil. A claim left by a
+	// delivery that crashed is taken over once its lease has lapsed at now.
+	ClaimMessage(ctx context.Context, messageID string, now, leaseUntil time.Time) (MessageClaim, error)
+
+	// CompleteMessage marks a claimed message as processed and remembers it until expiresAt.
+	CompleteMessage(ctx context.Context, messageID string, expiresAt time.Time) error
+
+	// ReleaseMessage drops a claim so the next delivery of the message is processed.
+	ReleaseMessage(ctx context.Context, messageID string) error
+
+	// PurgeExpiredMessages deletes records that expired before the given time and returns the number removed.
+	PurgeExpiredMessages(ctx context.Context, before time.Time) (int64, error)
+}
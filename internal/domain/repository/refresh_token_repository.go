@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"radar/internal/domain/entity"
 
@@ -31,6 +32,9 @@ type RefreshTokenRepository interface {
 	// This can be used to extend expiration or update device information.
 	UpdateRefreshToken(ctx context.Context, token *entity.RefreshToken) error
 
+	// TouchRefreshToken records that the token with the given hash was used to refresh an access token.
+	TouchRefreshToken(ctx context.Context, tokenHash string, usedAt time.Time) error
+
 	// DeleteRefreshToken removes a refresh token by its ID, effectively ending a session.
 	DeleteRefreshToken(ctx context.Context, id uuid.UUID) error
 
@@ -41,9 +45,9 @@ type RefreshTokenRepository interface {
 	// This is useful for "logout from all devices" functionality.
 	DeleteRefreshTokensByUserID(ctx context.Context, userID uuid.UUID) error
 
-	// DeleteExpiredRefreshTokens removes all expired refresh tokens from the database.
-	// This should be called periodically for cleanup.
-	DeleteExpiredRefreshTokens(ctx context.Context, revokedRetentionDays int) error
+	// DeleteExpiredRefreshTokens removes the refresh tokens expired at now, and those revoked more than
+	// revokedRetentionDays before it, and returns the number of tokens removed. This should be called periodically for cleanup.
+	DeleteExpiredRefreshTokens(ctx context.Context, now time.Time, revokedRetentionDays int) (int64, error)
 
 	// RevokeTokenFamily marks all tokens in the same family as revoked.
 	RevokeTokenFamily(ctx context.Context, familyID uuid.UUID) error
@@ -2,12 +2,28 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"radar/internal/domain/entity"
 
 	"github.com/google/uuid"
 )
 
+// DeviceTargetFilter narrows the devices a notification is delivered to. The zero value keeps every healthy device.
+type DeviceTargetFilter struct {
+	// Platforms limits delivery to these device platforms (empty allows all)
+	Platforms []string
+	// MinAppVersion excludes devices below this app version, including devices that never reported one
+	MinAppVersion string
+}
+
+// SubscriberSource is one merchant location whose subscriber addresses are looked up in a batch.
+type SubscriberSource struct {
+	MerchantID uuid.UUID
+	Latitude   float64
+	Longitude  float64
+}
+
 // SubscriptionRepository defines the interface for subscription-related database operations.
 type SubscriptionRepository interface {
 	// CreateSubscription persists a new subscription relationship.
@@ -31,6 +47,17 @@ type SubscriptionRepository interface {
 	// UpdateNotificationRadius updates the notification radius for a subscription.
 	UpdateNotificationRadius(ctx context.Context, id uuid.UUID, radius float64) error
 
+	// UpdateNotificationRadiusByUser sets the notification radius on every active subscription of a user in one statement.
+	// Returns the number of subscriptions updated.
+	UpdateNotificationRadiusByUser(ctx context.Context, userID uuid.UUID, radius float64) (int64, error)
+
+	// UpdateSnoozedUntil sets or clears (nil) the per-merchant broadcast snooze for a subscription.
+	UpdateSnoozedUntil(ctx context.Context, id uuid.UUID, until *time.Time) error
+
+	// UpdateGlobalSnoozedUntil sets or clears (nil) the user's broadcast snooze across all merchants.
+	// Returns ErrUserProfileNotFound when the user has no profile.
+	UpdateGlobalSnoozedUntil(ctx context.Context, userID uuid.UUID, until *time.Time) error
+
 	// DeleteSubscription removes a subscription by its ID (soft delete).
 	DeleteSubscription(ctx context.Context, id uuid.UUID) error
 
@@ -44,9 +71,15 @@ type SubscriptionRepository interface {
 	// Returns addresses bundled with their subscription notification radius to avoid N+1 lookups.
 	FindSubscriberAddressesWithinRadius(ctx context.Context, merchantID uuid.UUID, merchantLat, merchantLon float64) ([]*entity.SubscriberAddress, error)
 
+	// FindSubscriberAddressesByTile runs the FindSubscriberAddressesWithinRadius lookup for many source locations
+	// in batched queries and groups the candidates by the map tile their address falls in at the given zoom.
+	// An address within radius of several sources appears once per source. Buckets are ordered by tile row, then column.
+	FindSubscriberAddressesByTile(ctx context.Context, sources []SubscriberSource, zoom int) ([]*entity.SubscriberTileBucket, error)
+
 	// FindDevicesForUsers retrieves healthy devices for a list of user IDs.
 	// Healthy means active, non-deleted, and token refreshed within the health window.
-	FindDevicesForUsers(ctx context.Context, userIDs []uuid.UUID, healthyWindowDays int) ([]*entity.UserDevice, error)
+	// The target filter additionally drops devices on other platforms or below the minimum app version.
+	FindDevicesForUsers(ctx context.Context, userIDs []uuid.UUID, healthyWindowDays int, target DeviceTargetFilter) ([]*entity.UserDevice, error)
 
 	// FindSubscriberAddressesByUserIDs retrieves addresses for specific user IDs who subscribe to a merchant.
 	// Returns addresses bundled with their subscription notification radius.
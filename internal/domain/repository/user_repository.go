@@ -26,5 +26,8 @@ type UserRepository interface {
 	// Update modifies an existing user entity in the storage.
 	Update(ctx context.Context, user *entity.User) error
 
+	// IsBusinessLicenseTaken reports whether another active merchant already holds the business license.
+	IsBusinessLicenseTaken(ctx context.Context, businessLicense string, excludeUserID uuid.UUID) (bool, error)
+
 	// Note: Delete method can be added here as needed.
 }
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"time"
+)
+
+// Clock defines the interface for reading the current time.
+// This lets callers control time precisely in tests of expiry and scheduling logic.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// ClockFunc adapts a plain function, such as time.Now, to the Clock interface.
+type ClockFunc func() time.Time
+
+// Now calls f.
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
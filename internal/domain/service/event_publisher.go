@@ -2,19 +2,29 @@ package service
 
 import (
 	"context"
+	"time"
 )
 
 // NotificationEvent represents an event to be processed by the geo worker
 type NotificationEvent struct {
-	RequestID      string   `json:"request_id,omitempty"` // For distributed tracing
-	NotificationID string   `json:"notification_id"`
-	MerchantID     string   `json:"merchant_id"`
-	Latitude       float64  `json:"latitude"`
-	Longitude      float64  `json:"longitude"`
-	LocationName   string   `json:"location_name"`
-	FullAddress    string   `json:"full_address"`
-	HintMessage    string   `json:"hint_message,omitempty"`
-	SubscriberIDs  []string `json:"subscriber_ids"` // Pre-filtered subscriber user IDs
+	RequestID      string    `json:"request_id,omitempty"` // For distributed tracing
+	NotificationID string    `json:"notification_id"`
+	MerchantID     string    `json:"merchant_id"`
+	Latitude       float64   `json:"latitude"`
+	Longitude      float64   `json:"longitude"`
+	LocationName   string    `json:"location_name"`
+	FullAddress    string    `json:"full_address"`
+	HintMessage    string    `json:"hint_message,omitempty"`
+	SubscriberIDs  []string  `json:"subscriber_ids"`      // Pre-filtered subscriber user IDs
+	ExpiresAt      time.Time `json:"expires_at,omitzero"` // Events delivered after this time are dropped; zero never expires
+
+	// Set when SubscriberIDs already passed the road reachability filter, so the worker skips its recheck
+	ReachabilityFiltered bool `json:"reachability_filtered,omitempty"`
+}
+
+// IsExpired reports whether the event has outlived its delivery TTL
+func (e *NotificationEvent) IsExpired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && now.After(e.ExpiresAt)
 }
 
 // EventPublisher defines the interface for publishing events to a message queue
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"github.com/google/uuid"
+)
+
+// IDGenerator defines the interface for generating identifiers of newly created entities.
+// This lets callers substitute a deterministic sequence in tests.
+type IDGenerator interface {
+	// NewID returns a new unique identifier.
+	NewID() uuid.UUID
+}
+
+// IDGeneratorFunc adapts a plain function, such as uuid.New, to the IDGenerator interface.
+type IDGeneratorFunc func() uuid.UUID
+
+// NewID calls f.
+func (f IDGeneratorFunc) NewID() uuid.UUID {
+	return f()
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"time"
+)
+
+// NotificationMetrics defines the interface for recording notification delivery outcomes.
+// Implementations export them as time series so operators can follow success and failure rates.
+type NotificationMetrics interface {
+	// NotificationCreated counts a location notification record being created.
+	NotificationCreated()
+
+	// TokensSent counts FCM tokens the provider accepted.
+	TokensSent(count int)
+
+	// DeliveryFailed counts FCM tokens that could not be delivered.
+	DeliveryFailed(count int)
+
+	// InvalidTokensCleaned counts devices removed because FCM reported their token as unregistered.
+	InvalidTokensCleaned(count int)
+
+	// CanarySuppressed counts subscribers skipped because they are outside the canary cohort.
+	CanarySuppressed(count int)
+
+	// RecipientsCapped counts subscribers dropped from broadcasts over the recipient cap.
+	RecipientsCapped(count int)
+
+	// ObserveRoutingLatency records how long subscriber reachability routing took.
+	ObserveRoutingLatency(duration time.Duration)
+}
+
+// noopNotificationMetrics discards every measurement
+type noopNotificationMetrics struct{}
+
+func (noopNotificationMetrics) NotificationCreated()                {}
+func (noopNotificationMetrics) TokensSent(int)                      {}
+func (noopNotificationMetrics) DeliveryFailed(int)                  {}
+func (noopNotificationMetrics) InvalidTokensCleaned(int)            {}
+func (noopNotificationMetrics) CanarySuppressed(int)                {}
+func (noopNotificationMetrics) RecipientsCapped(int)                {}
+func (noopNotificationMetrics) ObserveRoutingLatency(time.Duration) {}
+
+// NotificationMetricsOrNoop returns metrics, falling back to a recorder that records nothing when it is nil
+func NotificationMetricsOrNoop(metrics NotificationMetrics) NotificationMetrics {
+	if metrics == nil {
+		return noopNotificationMetrics{}
+	}
+
+	return metrics
+}
@@ -2,14 +2,73 @@ package service
 
 import (
 	"context"
+	"errors"
 )
 
+// ErrNotificationUnavailable indicates the push provider is temporarily refusing sends,
+// for example while a circuit breaker is open. Callers should retry the delivery later.
+var ErrNotificationUnavailable = errors.New("notification provider temporarily unavailable")
+
+// TokenStatus is the outcome of a send to one device token
+type TokenStatus string
+
+const (
+	// TokenStatusSent means the provider accepted the message for the token
+	TokenStatusSent TokenStatus = "sent"
+	// TokenStatusInvalid means the provider confirmed the token is permanently unregistered, so its device is safe to soft-delete
+	TokenStatusInvalid TokenStatus = "invalid"
+	// TokenStatusFailed means the provider rejected the message for the token and resending it will not help
+	TokenStatusFailed TokenStatus = "failed"
+	// TokenStatusTransient means the message was not delivered because of a transient provider or transport error
+	TokenStatusTransient TokenStatus = "transient"
+)
+
+// TokenResult is the outcome of sending a batch notification to one device token
+type TokenResult struct {
+	Token     string
+	Status    TokenStatus
+	ErrorCode string // Provider error code such as UNREGISTERED or UNAVAILABLE; empty when sent
+}
+
+// SummarizeTokenResults counts the sent and not-sent tokens of a batch and lists the tokens confirmed invalid
+func SummarizeTokenResults(results []TokenResult) (sent, failed int, invalidTokens []string) {
+	invalidTokens = make([]string, 0)
+	for _, result := range results {
+		switch result.Status {
+		case TokenStatusSent:
+			sent++
+		case TokenStatusInvalid:
+			failed++
+			invalidTokens = append(invalidTokens, result.Token)
+		default:
+			failed++
+		}
+	}
+
+	return sent, failed, invalidTokens
+}
+
+type sendRetriesKey struct{}
+
+// WithoutSendRetries returns a context whose NotificationService sends make a single provider attempt.
+// Callers that resend transient tokens themselves use it so their retries do not stack on the service's own.
+func WithoutSendRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sendRetriesKey{}, true)
+}
+
+// SendRetriesDisabled reports whether ctx came from WithoutSendRetries
+func SendRetriesDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(sendRetriesKey{}).(bool)
+
+	return disabled
+}
+
 // NotificationService defines the interface for push notification services
 type NotificationService interface {
-	// SendBatchNotification sends push notifications to multiple device tokens
-	// Returns success count, failure count, tokens that are safe to soft-delete, and error.
-	// Only permanent token invalidation errors should be returned in the token list.
-	SendBatchNotification(ctx context.Context, tokens []string, title, body string, data map[string]string) (successCount, failureCount int, invalidTokens []string, err error)
+	// SendBatchNotification sends push notifications to multiple device tokens.
+	// Results are index-aligned with tokens and populated even when err is non-nil, so callers can record each
+	// device's outcome. err summarizes failures that affected the whole batch or left tokens transient.
+	SendBatchNotification(ctx context.Context, tokens []string, title, body string, data map[string]string) (results []TokenResult, err error)
 
 	// SendSingleNotification sends a push notification to a single device token
 	SendSingleNotification(ctx context.Context, token, title, body string, data map[string]string) error
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"radar/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// User lifecycle event names
+const (
+	UserEventRegistered     = "user.registered"
+	UserEventLoggedIn       = "user.logged_in"
+	UserEventLoggedOut      = "user.logged_out"
+	UserEventGoogleLinked   = "user.google_linked"
+	UserEventGoogleUnlinked = "user.google_unlinked"
+)
+
+// UserEvent is a user lifecycle event. Events are published only after the change they report has been committed.
+type UserEvent interface {
+	// EventName identifies the event type
+	EventName() string
+}
+
+// UserRegistered reports that a new account was created
+type UserRegistered struct {
+	UserID     uuid.UUID
+	Role       entity.Role
+	Provider   entity.ProviderType
+	OccurredAt time.Time
+}
+
+// EventName returns UserEventRegistered
+func (UserRegistered) EventName() string { return UserEventRegistered }
+
+// UserLoggedIn reports that an existing account signed in and was issued a session
+type UserLoggedIn struct {
+	UserID     uuid.UUID
+	Provider   entity.ProviderType
+	OccurredAt time.Time
+}
+
+// EventName returns UserEventLoggedIn
+func (UserLoggedIn) EventName() string { return UserEventLoggedIn }
+
+// UserLoggedOut reports that a session was revoked on logout
+type UserLoggedOut struct {
+	UserID     uuid.UUID
+	OccurredAt time.Time
+}
+
+// EventName returns UserEventLoggedOut
+func (UserLoggedOut) EventName() string { return UserEventLoggedOut }
+
+// GoogleLinked reports that a Google account was linked to an existing account
+type GoogleLinked struct {
+	UserID     uuid.UUID
+	OccurredAt time.Time
+}
+
+// EventName returns UserEventGoogleLinked
+func (GoogleLinked) EventName() string { return UserEventGoogleLinked }
+
+// GoogleUnlinked reports that a Google account was removed from an account
+type GoogleUnlinked struct {
+	UserID     uuid.UUID
+	OccurredAt time.Time
+}
+
+// EventName returns UserEventGoogleUnlinked
+func (GoogleUnlinked) EventName() string { return UserEventGoogleUnlinked }
+
+// UserEventPublisher defines the interface for publishing user lifecycle events in process.
+// Publishing never fails the operation that emitted the event; implementations handle subscriber errors themselves.
+type UserEventPublisher interface {
+	// PublishUserEvent delivers event to every subscriber
+	PublishUserEvent(ctx context.Context, event UserEvent)
+}
+
+// UserEventSubscriber defines the interface for reacting to user lifecycle events.
+// Subscribers run synchronously on the publishing request, so slow work belongs in a goroutine or queue.
+type UserEventSubscriber interface {
+	// HandleUserEvent reacts to event; a returned error is logged and does not reach other subscribers
+	HandleUserEvent(ctx context.Context, event UserEvent) error
+}
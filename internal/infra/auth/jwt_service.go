@@ -16,16 +16,25 @@ import (
 	"github.com/google/uuid"
 )
 
+// jwtKeyIDHeader is the token header naming the key a token was signed with.
+const jwtKeyIDHeader = "kid"
+
 // jwtService is a concrete implementation of the TokenService interface using the JWT standard.
 type jwtService struct {
-	accessSecret     string        // Secret key for signing access tokens.
-	refreshSecret    string        // Secret key for signing refresh tokens.
-	onboardingSecret string        // Secret key for signing onboarding tokens.
-	linkingSecret    string        // Secret key for signing account linking tokens.
-	onboardingTTL    time.Duration // Time-to-live for onboarding tokens.
-	linkingTTL       time.Duration // Time-to-live for account linking tokens.
-	accessTTL        time.Duration // Time-to-live for access tokens.
-	refreshTTL       time.Duration // Time-to-live for refresh tokens.
+	currentKeyID  string                    // Key ID new tokens are signed with and stamped as kid; empty for the unnamed key.
+	keys          map[string]*jwtSigningKey // Verification keys by key ID; the empty ID holds the unnamed key.
+	onboardingTTL time.Duration             // Time-to-live for onboarding tokens.
+	linkingTTL    time.Duration             // Time-to-live for account linking tokens.
+	accessTTL     time.Duration             // Time-to-live for access tokens.
+	refreshTTL    time.Duration             // Time-to-live for refresh tokens.
+}
+
+// jwtSigningKey holds the secrets of one signing key, one per token type.
+type jwtSigningKey struct {
+	accessSecret     string // Secret key for signing access tokens.
+	refreshSecret    string // Secret key for signing refresh tokens.
+	onboardingSecret string // Secret key for signing onboarding tokens.
+	linkingSecret    string // Secret key for signing account linking tokens.
 }
 
 type linkingTokenMetadata struct {
@@ -37,36 +46,88 @@ type linkingTokenMetadata struct {
 
 // NewJWTService is the constructor for jwtService.
 // It takes configuration values to create a new token service instance.
+// Without a key set it signs and validates with the unnamed secrets; with one it signs with the current key
+// and validates against every listed key, so a retired key keeps validating its tokens until it is removed.
 func NewJWTService(cfg *config.Config) (service.TokenService, error) {
 	if cfg == nil {
 		return nil, errors.New("config must be provided")
 	}
 	config.ApplyDefaults(cfg)
 
-	if cfg.SecretKey.Access == "" || cfg.SecretKey.Refresh == "" {
-		return nil, errors.New("jwt secrets must be provided")
+	keys, currentKeyID, err := newJWTKeySet(cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	onboardingSecret := cfg.SecretKey.Onboarding
-	if onboardingSecret == "" {
-		onboardingSecret = deriveTokenSecret(cfg.SecretKey.Access, service.TokenTypeOnboarding)
+	return &jwtService{
+		currentKeyID:  currentKeyID,
+		keys:          keys,
+		onboardingTTL: cfg.Auth.OnboardingTokenTTL,
+		linkingTTL:    cfg.Auth.LinkingTokenTTL,
+		accessTTL:     cfg.Auth.AccessTokenTTL,
+		refreshTTL:    cfg.Auth.RefreshTokenTTL,
+	}, nil
+}
+
+// newJWTKeySet builds the verification keys by key ID and picks the key ID new tokens are signed with
+func newJWTKeySet(cfg *config.Config) (map[string]*jwtSigningKey, string, error) {
+	secrets := cfg.SecretKey
+	hasUnnamedKey := secrets.Access != "" || secrets.Refresh != ""
+	if hasUnnamedKey && (secrets.Access == "" || secrets.Refresh == "") {
+		return nil, "", errors.New("jwt secrets must be provided")
+	}
+
+	keys := make(map[string]*jwtSigningKey, len(secrets.Keys)+1)
+	if hasUnnamedKey {
+		keys[""] = newJWTSigningKey(secrets.Access, secrets.Refresh, secrets.Onboarding, secrets.Linking)
+	}
+	if len(secrets.Keys) == 0 {
+		if !hasUnnamedKey {
+			return nil, "", errors.New("jwt secrets must be provided")
+		}
+
+		return keys, "", nil
+	}
+
+	for _, key := range secrets.Keys {
+		if key.ID == "" {
+			return nil, "", errors.New("jwt signing keys must have an id")
+		}
+		if _, ok := keys[key.ID]; ok {
+			return nil, "", fmt.Errorf("jwt signing key %q is listed more than once", key.ID)
+		}
+		if key.Access == "" || key.Refresh == "" {
+			return nil, "", fmt.Errorf("jwt signing key %q: jwt secrets must be provided", key.ID)
+		}
+		keys[key.ID] = newJWTSigningKey(key.Access, key.Refresh, key.Onboarding, key.Linking)
+	}
+
+	currentKeyID := secrets.CurrentKeyID
+	if currentKeyID == "" {
+		currentKeyID = secrets.Keys[0].ID
+	}
+	if _, ok := keys[currentKeyID]; !ok || currentKeyID == "" {
+		return nil, "", fmt.Errorf("current jwt signing key %q is not in the key set", currentKeyID)
 	}
 
-	linkingSecret := cfg.SecretKey.Linking
+	return keys, currentKeyID, nil
+}
+
+// newJWTSigningKey creates a signing key, deriving empty onboarding and linking secrets from the access secret
+func newJWTSigningKey(accessSecret, refreshSecret, onboardingSecret, linkingSecret string) *jwtSigningKey {
+	if onboardingSecret == "" {
+		onboardingSecret = deriveTokenSecret(accessSecret, service.TokenTypeOnboarding)
+	}
 	if linkingSecret == "" {
-		linkingSecret = deriveTokenSecret(cfg.SecretKey.Access, service.TokenTypeLinking)
+		linkingSecret = deriveTokenSecret(accessSecret, service.TokenTypeLinking)
 	}
 
-	return &jwtService{
-		accessSecret:     cfg.SecretKey.Access,
-		refreshSecret:    cfg.SecretKey.Refresh,
+	return &jwtSigningKey{
+		accessSecret:     accessSecret,
+		refreshSecret:    refreshSecret,
 		onboardingSecret: onboardingSecret,
 		linkingSecret:    linkingSecret,
-		onboardingTTL:    cfg.Auth.OnboardingTokenTTL,
-		linkingTTL:       cfg.Auth.LinkingTokenTTL,
-		accessTTL:        cfg.Auth.AccessTokenTTL,
-		refreshTTL:       cfg.Auth.RefreshTokenTTL,
-	}, nil
+	}
 }
 
 func deriveTokenSecret(accessSecret, tokenType string) string {
@@ -78,12 +139,12 @@ func deriveTokenSecret(accessSecret, tokenType string) string {
 
 // GenerateTokens creates a new access token and refresh token for a given user and roles.
 func (s *jwtService) GenerateTokens(userID uuid.UUID, roles []string) (accessToken string, refreshToken string, err error) {
-	accessToken, err = s.generateToken(userID, roles, s.accessTTL, s.accessSecret, service.TokenTypeAccess, nil)
+	accessToken, err = s.generateToken(userID, roles, s.accessTTL, service.TokenTypeAccess, nil)
 	if err != nil {
 		return "", "", err
 	}
 
-	refreshToken, err = s.generateToken(userID, nil, s.refreshTTL, s.refreshSecret, service.TokenTypeRefresh, nil)
+	refreshToken, err = s.generateToken(userID, nil, s.refreshTTL, service.TokenTypeRefresh, nil)
 	if err != nil {
 		return "", "", err
 	}
@@ -91,14 +152,19 @@ func (s *jwtService) GenerateTokens(userID uuid.UUID, roles []string) (accessTok
 	return accessToken, refreshToken, nil
 }
 
-// ValidateToken checks the validity of a token string against a secret.
+// ValidateToken checks the validity of a token string against the secret of the key its kid header names.
 func (s *jwtService) ValidateToken(tokenString string) (*service.Claims, error) {
-	unverifiedClaims, err := parseUnverifiedClaims(tokenString)
+	unverifiedToken, unverifiedClaims, err := parseUnverifiedClaims(tokenString)
 	if err != nil {
 		return nil, err
 	}
 
-	secret, err := s.secretForTokenType(unverifiedClaims.Type)
+	key, err := s.verificationKey(unverifiedToken)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := key.secretForTokenType(unverifiedClaims.Type)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +174,7 @@ func (s *jwtService) ValidateToken(tokenString string) (*service.Claims, error)
 
 // GenerateOnboardingToken creates a short-lived onboarding token for a given user.
 func (s *jwtService) GenerateOnboardingToken(userID uuid.UUID) (string, error) {
-	token, err := s.generateToken(userID, nil, s.onboardingTTL, s.onboardingSecret, service.TokenTypeOnboarding, nil)
+	token, err := s.generateToken(userID, nil, s.onboardingTTL, service.TokenTypeOnboarding, nil)
 	if err != nil {
 		return "", err
 	}
@@ -128,7 +194,6 @@ func (s *jwtService) GenerateLinkingToken(
 		userID,
 		nil,
 		s.linkingTTL,
-		s.linkingSecret,
 		service.TokenTypeLinking,
 		&linkingTokenMetadata{
 			Provider:       provider,
@@ -144,30 +209,48 @@ func (s *jwtService) GenerateLinkingToken(
 	return token, nil
 }
 
-func parseUnverifiedClaims(tokenString string) (*service.Claims, error) {
+func parseUnverifiedClaims(tokenString string) (*jwt.Token, *service.Claims, error) {
 	unverifiedToken, _, err := new(jwt.Parser).ParseUnverified(tokenString, &service.Claims{})
 	if err != nil {
-		return nil, fmt.Errorf("parse unverified token claims: %w", err)
+		return nil, nil, fmt.Errorf("parse unverified token claims: %w", err)
 	}
 
 	unverifiedClaims, ok := unverifiedToken.Claims.(*service.Claims)
 	if !ok {
-		return nil, errors.New("invalid token claims structure")
+		return nil, nil, errors.New("invalid token claims structure")
 	}
 
-	return unverifiedClaims, nil
+	return unverifiedToken, unverifiedClaims, nil
 }
 
-func (s *jwtService) secretForTokenType(tokenType string) ([]byte, error) {
+// verificationKey returns the key named by the token's kid header; a token without one uses the unnamed key
+func (s *jwtService) verificationKey(token *jwt.Token) (*jwtSigningKey, error) {
+	keyID := ""
+	if value, ok := token.Header[jwtKeyIDHeader]; ok {
+		keyID, ok = value.(string)
+		if !ok || keyID == "" {
+			return nil, errors.New("invalid token key id")
+		}
+	}
+
+	key, ok := s.keys[keyID]
+	if !ok {
+		return nil, errors.New("unknown token signing key")
+	}
+
+	return key, nil
+}
+
+func (k *jwtSigningKey) secretForTokenType(tokenType string) ([]byte, error) {
 	switch tokenType {
 	case service.TokenTypeAccess:
-		return []byte(s.accessSecret), nil
+		return []byte(k.accessSecret), nil
 	case service.TokenTypeRefresh:
-		return []byte(s.refreshSecret), nil
+		return []byte(k.refreshSecret), nil
 	case service.TokenTypeOnboarding:
-		return []byte(s.onboardingSecret), nil
+		return []byte(k.onboardingSecret), nil
 	case service.TokenTypeLinking:
-		return []byte(s.linkingSecret), nil
+		return []byte(k.linkingSecret), nil
 	default:
 		return nil, errors.New("unknown token type")
 	}
@@ -223,14 +306,19 @@ func (s *jwtService) RotateTokens(userID uuid.UUID, roles []string) (accessToken
 	return accessToken, refreshToken, refreshTokenHash, nil
 }
 
-// generateToken is a private helper to create a JWT with specific claims.
+// generateToken is a private helper to create a JWT with specific claims, signed with the current key.
 func (s *jwtService) generateToken(
 	userID uuid.UUID,
 	roles []string,
 	ttl time.Duration,
-	secret, tokenType string,
+	tokenType string,
 	linkingMetadata *linkingTokenMetadata,
 ) (string, error) {
+	secret, err := s.keys[s.currentKeyID].secretForTokenType(tokenType)
+	if err != nil {
+		return "", err
+	}
+
 	claims := service.Claims{
 		UserID: userID,
 		Roles:  roles,
@@ -251,7 +339,10 @@ func (s *jwtService) generateToken(
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(secret))
+	if s.currentKeyID != "" {
+		token.Header[jwtKeyIDHeader] = s.currentKeyID
+	}
+	signedToken, err := token.SignedString(secret)
 	if err != nil {
 		return "", fmt.Errorf("sign token: %w", err)
 	}
@@ -7,21 +7,14 @@ import (
 	"radar/config"
 	"radar/internal/domain/service"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func newJWTTestConfig(access, refresh string) *config.Config {
-	return &config.Config{
-		SecretKey: struct {
-			Access     string `json:"access" yaml:"access"`
-			Refresh    string `json:"refresh" yaml:"refresh"`
-			Onboarding string `json:"onboarding" yaml:"onboarding"`
-			Linking    string `json:"linking" yaml:"linking"`
-		}{
-			Access:  access,
-			Refresh: refresh,
-		},
+	cfg := &config.Config{
 		Auth: &config.AuthConfig{
 			AccessTokenTTL:     15 * time.Minute,
 			RefreshTokenTTL:    7 * 24 * time.Hour,
@@ -29,6 +22,10 @@ func newJWTTestConfig(access, refresh string) *config.Config {
 			LinkingTokenTTL:    10 * time.Minute,
 		},
 	}
+	cfg.SecretKey.Access = access
+	cfg.SecretKey.Refresh = refresh
+
+	return cfg
 }
 
 func TestJWTService_GenerateAndValidateTokens(t *testing.T) {
@@ -234,17 +231,9 @@ func TestJWTService_RotateTokens(t *testing.T) {
 
 func TestJWTService_TokenRotationFunctionality(t *testing.T) {
 	// Create test config
-	cfg := &config.Config{
-		SecretKey: struct {
-			Access     string `json:"access" yaml:"access"`
-			Refresh    string `json:"refresh" yaml:"refresh"`
-			Onboarding string `json:"onboarding" yaml:"onboarding"`
-			Linking    string `json:"linking" yaml:"linking"`
-		}{
-			Access:  "test_access_secret_key_very_long_for_testing",
-			Refresh: "test_refresh_secret_key_very_long_for_testing",
-		},
-	}
+	cfg := &config.Config{}
+	cfg.SecretKey.Access = "test_access_secret_key_very_long_for_testing"
+	cfg.SecretKey.Refresh = "test_refresh_secret_key_very_long_for_testing"
 
 	// Create JWT service
 	jwtService, err := NewJWTService(cfg)
@@ -283,3 +272,124 @@ func TestJWTService_TokenRotationFunctionality(t *testing.T) {
 	// Different users should have different hashes
 	assert.NotEqual(t, refreshHash, refreshHash2)
 }
+
+func newJWTKeySetTestConfig(currentKeyID string, keyIDs ...string) *config.Config {
+	cfg := newJWTTestConfig("", "")
+	cfg.SecretKey.CurrentKeyID = currentKeyID
+	for _, keyID := range keyIDs {
+		cfg.SecretKey.Keys = append(cfg.SecretKey.Keys, config.SigningKeyConfig{
+			ID:      keyID,
+			Access:  "test_access_secret_key_" + keyID,
+			Refresh: "test_refresh_secret_key_" + keyID,
+		})
+	}
+
+	return cfg
+}
+
+func TestJWTService_KeyRotation_OldTokensValidateUntilKeyRemoved(t *testing.T) {
+	userID := uuid.New()
+
+	// Before the rotation only the old key exists
+	before, err := NewJWTService(newJWTKeySetTestConfig("", "2026-07"))
+	require.NoError(t, err)
+	oldAccess, oldRefresh, err := before.GenerateTokens(userID, []string{"user"})
+	require.NoError(t, err)
+	oldOnboarding, err := before.GenerateOnboardingToken(userID)
+	require.NoError(t, err)
+
+	// The rotation signs with the new key and keeps the old one for validation
+	during, err := NewJWTService(newJWTKeySetTestConfig("2026-10", "2026-10", "2026-07"))
+	require.NoError(t, err)
+	newAccess, _, err := during.GenerateTokens(userID, []string{"user"})
+	require.NoError(t, err)
+
+	for _, token := range []string{oldAccess, oldRefresh, oldOnboarding, newAccess} {
+		claims, err := during.ValidateToken(token)
+		require.NoError(t, err)
+		assert.Equal(t, userID, claims.UserID)
+	}
+
+	parsed, _, err := new(jwt.Parser).ParseUnverified(newAccess, &service.Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10", parsed.Header["kid"])
+
+	// The old key has not seen the new one, so it cannot validate tokens signed with it
+	_, err = before.ValidateToken(newAccess)
+	require.Error(t, err)
+
+	// Removing the retired key ends its tokens
+	after, err := NewJWTService(newJWTKeySetTestConfig("", "2026-10"))
+	require.NoError(t, err)
+	_, err = after.ValidateToken(oldAccess)
+	require.ErrorContains(t, err, "unknown token signing key")
+	_, err = after.ValidateToken(newAccess)
+	require.NoError(t, err)
+}
+
+func TestJWTService_KeyRotation_FromUnnamedSecrets(t *testing.T) {
+	userID := uuid.New()
+	legacyCfg := newJWTTestConfig("test_access_secret_key_very_long_for_testing", "test_refresh_secret_key_very_long_for_testing")
+	legacy, err := NewJWTService(legacyCfg)
+	require.NoError(t, err)
+	legacyAccess, _, err := legacy.GenerateTokens(userID, nil)
+	require.NoError(t, err)
+
+	// Tokens issued before the key set have no kid and keep validating against the unnamed secrets
+	rotatedCfg := newJWTKeySetTestConfig("", "2026-10")
+	rotatedCfg.SecretKey.Access = legacyCfg.SecretKey.Access
+	rotatedCfg.SecretKey.Refresh = legacyCfg.SecretKey.Refresh
+	rotated, err := NewJWTService(rotatedCfg)
+	require.NoError(t, err)
+
+	_, err = rotated.ValidateToken(legacyAccess)
+	require.NoError(t, err)
+
+	// Without the unnamed secrets a token without a kid is rejected
+	keySetOnly, err := NewJWTService(newJWTKeySetTestConfig("", "2026-10"))
+	require.NoError(t, err)
+	_, err = keySetOnly.ValidateToken(legacyAccess)
+	require.ErrorContains(t, err, "unknown token signing key")
+}
+
+func TestJWTService_KeySetValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *config.Config
+		wantErr string
+	}{
+		{
+			name:    "unknown current key",
+			cfg:     newJWTKeySetTestConfig("2026-12", "2026-10"),
+			wantErr: `current jwt signing key "2026-12" is not in the key set`,
+		},
+		{
+			name:    "duplicate key id",
+			cfg:     newJWTKeySetTestConfig("", "2026-10", "2026-10"),
+			wantErr: `jwt signing key "2026-10" is listed more than once`,
+		},
+		{
+			name:    "missing key id",
+			cfg:     newJWTKeySetTestConfig("", ""),
+			wantErr: "jwt signing keys must have an id",
+		},
+		{
+			name: "missing key secret",
+			cfg: func() *config.Config {
+				cfg := newJWTKeySetTestConfig("", "2026-10")
+				cfg.SecretKey.Keys[0].Refresh = ""
+
+				return cfg
+			}(),
+			wantErr: `jwt signing key "2026-10": jwt secrets must be provided`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jwtService, err := NewJWTService(tt.cfg)
+			require.ErrorContains(t, err, tt.wantErr)
+			assert.Nil(t, jwtService)
+		})
+	}
+}
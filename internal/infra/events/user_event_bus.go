@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"radar/internal/domain/service"
+
+	"go.uber.org/fx"
+)
+
+// UserEventSubscriberGroup is the fx value group user event subscribers are provided into
+const UserEventSubscriberGroup = `group:"user_event_subscribers"`
+
+// UserEventBusParams defines the dependencies for the in-process user event bus
+type UserEventBusParams struct {
+	fx.In
+
+	Subscribers []service.UserEventSubscriber `group:"user_event_subscribers"`
+	Logger      *slog.Logger
+}
+
+// userEventBus fans each user event out to the subscribers in the order fx provided them
+type userEventBus struct {
+	subscribers []service.UserEventSubscriber
+	logger      *slog.Logger
+}
+
+// NewUserEventBus creates the in-process user event publisher.
+// Subscribers join it by being provided with fx.ResultTags(UserEventSubscriberGroup).
+func NewUserEventBus(params UserEventBusParams) service.UserEventPublisher {
+	return &userEventBus{
+		subscribers: params.Subscribers,
+		logger:      params.Logger,
+	}
+}
+
+// PublishUserEvent delivers event to every subscriber. A failing or panicking subscriber is logged
+// and skipped, so it neither fails the publishing operation nor hides the event from the others.
+func (b *userEventBus) PublishUserEvent(ctx context.Context, event service.UserEvent) {
+	for _, subscriber := range b.subscribers {
+		if err := deliverUserEvent(ctx, subscriber, event); err != nil {
+			b.logger.Warn("User event subscriber failed",
+				slog.String("event", event.EventName()),
+				slog.String("subscriber", fmt.Sprintf("%T", subscriber)),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+func deliverUserEvent(ctx context.Context, subscriber service.UserEventSubscriber, event service.UserEvent) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("subscriber panicked: %v", recovered)
+		}
+	}()
+
+	return subscriber.HandleUserEvent(ctx, event)
+}
@@ -0,0 +1,54 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"radar/internal/domain/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingSubscriber struct {
+	events []service.UserEvent
+	err    error
+	panics bool
+}
+
+func (s *recordingSubscriber) HandleUserEvent(_ context.Context, event service.UserEvent) error {
+	s.events = append(s.events, event)
+	if s.panics {
+		panic("boom")
+	}
+
+	return s.err
+}
+
+func TestUserEventBus_DeliversToEverySubscriberDespiteFailures(t *testing.T) {
+	failing := &recordingSubscriber{err: errors.New("subscriber down")}
+	panicking := &recordingSubscriber{panics: true}
+	healthy := &recordingSubscriber{}
+	bus := NewUserEventBus(UserEventBusParams{
+		Subscribers: []service.UserEventSubscriber{failing, panicking, healthy},
+		Logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	event := service.UserLoggedOut{UserID: uuid.New()}
+
+	bus.PublishUserEvent(context.Background(), event)
+
+	for _, subscriber := range []*recordingSubscriber{failing, panicking, healthy} {
+		assert.Equal(t, []service.UserEvent{event}, subscriber.events)
+	}
+}
+
+func TestUserEventBus_NoSubscribers(t *testing.T) {
+	bus := NewUserEventBus(UserEventBusParams{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+
+	assert.NotPanics(t, func() {
+		bus.PublishUserEvent(context.Background(), service.UserLoggedOut{UserID: uuid.New()})
+	})
+}
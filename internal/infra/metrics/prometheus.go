@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"radar/internal/domain/service"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const metricsNamespace = "radar"
+
+// NewRegistry creates the registry served on /metrics, including Go runtime and process collectors.
+func NewRegistry() (*prometheus.Registry, error) {
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(collectors.NewGoCollector()); err != nil {
+		return nil, fmt.Errorf("register go collector: %w", err)
+	}
+	if err := registry.Register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})); err != nil {
+		return nil, fmt.Errorf("register process collector: %w", err)
+	}
+
+	return registry, nil
+}
+
+type notificationMetrics struct {
+	notificationsCreated prometheus.Counter
+	tokensSent           prometheus.Counter
+	deliveryFailures     prometheus.Counter
+	invalidTokensCleaned prometheus.Counter
+	canarySuppressed     prometheus.Counter
+	recipientsCapped     prometheus.Counter
+	routingLatency       prometheus.Histogram
+}
+
+// NewNotificationMetrics registers the notification delivery metrics on the registry.
+func NewNotificationMetrics(registry *prometheus.Registry) (service.NotificationMetrics, error) {
+	metrics := &notificationMetrics{
+		notificationsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "notification",
+			Name:      "created_total",
+			Help:      "Location notifications created.",
+		}),
+		tokensSent: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "notification",
+			Name:      "tokens_sent_total",
+			Help:      "FCM tokens accepted by the push provider.",
+		}),
+		deliveryFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "notification",
+			Name:      "delivery_failures_total",
+			Help:      "FCM tokens that could not be delivered.",
+		}),
+		invalidTokensCleaned: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "notification",
+			Name:      "invalid_tokens_cleaned_total",
+			Help:      "Devices removed because FCM reported their token as unregistered.",
+		}),
+		canarySuppressed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "notification",
+			Name:      "canary_suppressed_total",
+			Help:      "Subscribers skipped because they are outside the canary cohort.",
+		}),
+		recipientsCapped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "notification",
+			Name:      "recipients_capped_total",
+			Help:      "Subscribers dropped from broadcasts over the recipient cap.",
+		}),
+		routingLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "notification",
+			Name:      "routing_duration_seconds",
+			Help:      "Time spent routing subscribers to decide road reachability.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+	}
+
+	for _, collector := range []prometheus.Collector{
+		metrics.notificationsCreated,
+		metrics.tokensSent,
+		metrics.deliveryFailures,
+		metrics.invalidTokensCleaned,
+		metrics.canarySuppressed,
+		metrics.recipientsCapped,
+		metrics.routingLatency,
+	} {
+		if err := registry.Register(collector); err != nil {
+			return nil, fmt.Errorf("register notification metrics: %w", err)
+		}
+	}
+
+	return metrics, nil
+}
+
+func (m *notificationMetrics) NotificationCreated() {
+	m.notificationsCreated.Inc()
+}
+
+func (m *notificationMetrics) TokensSent(count int) {
+	m.tokensSent.Add(float64(max(count, 0)))
+}
+
+func (m *notificationMetrics) DeliveryFailed(count int) {
+	m.deliveryFailures.Add(float64(max(count, 0)))
+}
+
+func (m *notificationMetrics) InvalidTokensCleaned(count int) {
+	m.invalidTokensCleaned.Add(float64(max(count, 0)))
+}
+
+func (m *notificationMetrics) CanarySuppressed(count int) {
+	m.canarySuppressed.Add(float64(max(count, 0)))
+}
+
+func (m *notificationMetrics) RecipientsCapped(count int) {
+	m.recipientsCapped.Add(float64(max(count, 0)))
+}
+
+func (m *notificationMetrics) ObserveRoutingLatency(duration time.Duration) {
+	m.routingLatency.Observe(duration.Seconds())
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gatherMetricFamilies(t *testing.T, families []*dto.MetricFamily) map[string]*dto.Metric {
+	t.Helper()
+
+	byName := make(map[string]*dto.Metric, len(families))
+	for _, family := range families {
+		require.NotEmpty(t, family.GetMetric())
+		byName[family.GetName()] = family.GetMetric()[0]
+	}
+
+	return byName
+}
+
+func TestNotificationMetrics_RecordsDeliveryOutcomes(t *testing.T) {
+	registry, err := NewRegistry()
+	require.NoError(t, err)
+	recorder, err := NewNotificationMetrics(registry)
+	require.NoError(t, err)
+
+	recorder.NotificationCreated()
+	recorder.NotificationCreated()
+	recorder.TokensSent(5)
+	recorder.DeliveryFailed(2)
+	recorder.InvalidTokensCleaned(1)
+	recorder.CanarySuppressed(3)
+	recorder.RecipientsCapped(4)
+	recorder.ObserveRoutingLatency(250 * time.Millisecond)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	metrics := gatherMetricFamilies(t, families)
+
+	assert.InDelta(t, 2, metrics["radar_notification_created_total"].GetCounter().GetValue(), 1e-9)
+	assert.InDelta(t, 5, metrics["radar_notification_tokens_sent_total"].GetCounter().GetValue(), 1e-9)
+	assert.InDelta(t, 2, metrics["radar_notification_delivery_failures_total"].GetCounter().GetValue(), 1e-9)
+	assert.InDelta(t, 1, metrics["radar_notification_invalid_tokens_cleaned_total"].GetCounter().GetValue(), 1e-9)
+	assert.InDelta(t, 3, metrics["radar_notification_canary_suppressed_total"].GetCounter().GetValue(), 1e-9)
+	assert.InDelta(t, 4, metrics["radar_notification_recipients_capped_total"].GetCounter().GetValue(), 1e-9)
+
+	latency := metrics["radar_notification_routing_duration_seconds"].GetHistogram()
+	assert.Equal(t, uint64(1), latency.GetSampleCount())
+	assert.InDelta(t, 0.25, latency.GetSampleSum(), 1e-9)
+}
+
+func TestNewNotificationMetrics_RejectsDuplicateRegistration(t *testing.T) {
+	registry, err := NewRegistry()
+	require.NoError(t, err)
+
+	_, err = NewNotificationMetrics(registry)
+	require.NoError(t, err)
+
+	_, err = NewNotificationMetrics(registry)
+	assert.Error(t, err)
+}
@@ -0,0 +1,161 @@
+package notification
+
+import (
+	"sync"
+	"time"
+)
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitClosed:
+		return "closed"
+	case circuitOpen:
+		return "open"
+	case circuitHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+// circuitBreaker tracks the failure ratio over a sliding window of recent calls.
+// It opens once the ratio reaches failureRate, rejects calls for openDuration,
+// then lets a single probe through to decide whether to close again.
+type circuitBreaker struct {
+	mu sync.Mutex
+
+	failureRate  float64
+	minRequests  int
+	openDuration time.Duration
+	now          func() time.Time
+
+	state         circuitState
+	outcomes      []bool // ring buffer of recent results, true marks a failure
+	next          int
+	count         int
+	failures      int
+	openedAt      time.Time
+	probeInFlight bool
+}
+
+func newCircuitBreaker(failureRate float64, windowSize, minRequests int, openDuration time.Duration) *circuitBreaker {
+	windowSize = max(windowSize, 1)
+
+	return &circuitBreaker{
+		failureRate:  failureRate,
+		minRequests:  min(max(minRequests, 1), windowSize),
+		openDuration: openDuration,
+		now:          time.Now,
+		outcomes:     make([]bool, windowSize),
+	}
+}
+
+// allow reports whether a call may proceed. When the open period has elapsed it
+// moves the breaker to half-open and admits exactly one probe call.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if b.now().Sub(b.openedAt) < b.openDuration {
+			return false
+		}
+		b.state = circuitHalfOpen
+		b.probeInFlight = true
+
+		return true
+	case circuitHalfOpen:
+		if b.probeInFlight {
+			return false
+		}
+		b.probeInFlight = true
+
+		return true
+	default:
+		return true
+	}
+}
+
+// record reports the outcome of an admitted call and returns the resulting state
+// along with whether the state changed.
+func (b *circuitBreaker) record(failed bool) (circuitState, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	previous := b.state
+
+	switch b.state {
+	case circuitHalfOpen:
+		b.probeInFlight = false
+		if failed {
+			b.trip()
+		} else {
+			b.reset()
+		}
+	case circuitClosed:
+		b.push(failed)
+		if b.count >= b.minRequests && float64(b.failures)/float64(b.count) >= b.failureRate {
+			b.trip()
+		}
+	case circuitOpen:
+		// Calls admitted before the breaker opened do not affect the open period.
+	}
+
+	return b.state, b.state != previous
+}
+
+// release returns an admitted call without an outcome. A half-open breaker stays half-open and
+// admits another probe; a closed breaker does not count the call.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == circuitHalfOpen {
+		b.probeInFlight = false
+	}
+}
+
+func (b *circuitBreaker) currentState() circuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+func (b *circuitBreaker) push(failed bool) {
+	if b.count == len(b.outcomes) {
+		if b.outcomes[b.next] {
+			b.failures--
+		}
+	} else {
+		b.count++
+	}
+
+	b.outcomes[b.next] = failed
+	if failed {
+		b.failures++
+	}
+	b.next = (b.next + 1) % len(b.outcomes)
+}
+
+func (b *circuitBreaker) trip() {
+	b.state = circuitOpen
+	b.openedAt = b.now()
+}
+
+func (b *circuitBreaker) reset() {
+	b.state = circuitClosed
+	clear(b.outcomes)
+	b.next = 0
+	b.count = 0
+	b.failures = 0
+}
@@ -21,7 +21,8 @@ type firebaseService struct {
 	client *messaging.Client
 }
 
-// NewFirebaseService creates a new Firebase notification service instance
+// NewFirebaseService creates a new Firebase notification service instance.
+// The real client is wrapped with retries and a circuit breaker configured under notification.
 func NewFirebaseService(params FirebaseDependencies) (service.NotificationService, error) {
 	if params.Config.Firebase == nil {
 		return nil, errors.New("firebase config must be configured")
@@ -41,6 +42,8 @@ func NewFirebaseService(params FirebaseDependencies) (service.NotificationServic
 		return nil, errors.New("firebase credentials path must be configured")
 	}
 
+	config.ApplyDefaults(params.Config)
+
 	credentialsJSON, readErr := os.ReadFile(params.Config.Firebase.CredentialsPath)
 	if readErr != nil {
 		return nil, fmt.Errorf("failed to read firebase credentials: %w", readErr)
@@ -62,9 +65,7 @@ func NewFirebaseService(params FirebaseDependencies) (service.NotificationServic
 
 	params.Logger.Info("Firebase notification service initialized successfully")
 
-	return &firebaseService{
-		client: client,
-	}, nil
+	return newResilientService(&firebaseService{client: client}, params.Config.Notification, params.Logger), nil
 }
 
 func shouldUseNoopNotificationService(cfg *config.Config) bool {
@@ -94,7 +95,9 @@ func (s *firebaseService) SendSingleNotification(ctx context.Context, token, tit
 			Title: title,
 			Body:  body,
 		},
-		Data: data,
+		Data:    data,
+		Android: androidActionConfig(data),
+		APNS:    apnsActionConfig(data),
 	}
 
 	_, err := s.client.Send(ctx, message)
@@ -105,45 +108,157 @@ func (s *firebaseService) SendSingleNotification(ctx context.Context, token, tit
 	return nil
 }
 
-// SendBatchNotification sends push notifications to multiple device tokens (max 500 tokens)
-func (s *firebaseService) SendBatchNotification(ctx context.Context, tokens []string, title, body string, data map[string]string) (successCount, failureCount int, invalidTokens []string, err error) {
+// SendBatchNotification sends push notifications to multiple device tokens (max 500 tokens).
+// Every token gets a result: when the whole request fails, each token carries the request's error.
+func (s *firebaseService) SendBatchNotification(ctx context.Context, tokens []string, title, body string, data map[string]string) ([]service.TokenResult, error) {
 	if len(tokens) == 0 {
-		return 0, 0, nil, nil
+		return nil, nil
 	}
 
 	// Firebase limits to 500 tokens per request
 	if len(tokens) > 500 {
-		return 0, 0, nil, fmt.Errorf("token count exceeds limit: %d (max 500)", len(tokens))
+		err := fmt.Errorf("token count exceeds limit: %d (max 500)", len(tokens))
+
+		return failedTokenResults(tokens, service.TokenStatusFailed, "BATCH_TOO_LARGE"), err
+	}
+
+	message := newMulticastMessage(tokens, title, body, data)
+
+	response, err := s.client.SendEachForMulticast(ctx, message)
+	if err != nil {
+		status := service.TokenStatusFailed
+		if isTransientSendError(err) {
+			status = service.TokenStatusTransient
+		}
+
+		return failedTokenResults(tokens, status, firebaseErrorCode(err)), fmt.Errorf("send firebase multicast notification: %w", err)
+	}
+
+	results := make([]service.TokenResult, len(tokens))
+	transient := 0
+	var firstTransientErr error
+	for idx, token := range tokens {
+		results[idx] = service.TokenResult{Token: token, Status: service.TokenStatusSent}
+		if idx >= len(response.Responses) {
+			// A response shorter than the request leaves the missing tokens unconfirmed
+			results[idx].Status, results[idx].ErrorCode = service.TokenStatusTransient, "MISSING_RESPONSE"
+			transient++
+
+			continue
+		}
+
+		sendErr := response.Responses[idx].Error
+		if sendErr == nil {
+			continue
+		}
+
+		results[idx].ErrorCode = firebaseErrorCode(sendErr)
+		switch {
+		// INVALID_ARGUMENT can also mean the payload is invalid, so only UNREGISTERED marks a device for deletion
+		case messaging.IsUnregistered(sendErr):
+			results[idx].Status = service.TokenStatusInvalid
+		case isTransientSendError(sendErr):
+			results[idx].Status = service.TokenStatusTransient
+			transient++
+			if firstTransientErr == nil {
+				firstTransientErr = sendErr
+			}
+		default:
+			results[idx].Status = service.TokenStatusFailed
+		}
+	}
+
+	if transient > 0 {
+		if firstTransientErr == nil {
+			firstTransientErr = errors.New("firebase returned fewer responses than tokens")
+		}
+
+		return results, fmt.Errorf("firebase multicast left %d of %d tokens undelivered: %w", transient, len(tokens), firstTransientErr)
 	}
 
-	message := &messaging.MulticastMessage{
+	return results, nil
+}
+
+// failedTokenResults gives every token the same failed outcome
+func failedTokenResults(tokens []string, status service.TokenStatus, errorCode string) []service.TokenResult {
+	results := make([]service.TokenResult, len(tokens))
+	for idx, token := range tokens {
+		results[idx] = service.TokenResult{Token: token, Status: status, ErrorCode: errorCode}
+	}
+
+	return results
+}
+
+// firebaseErrorCode names the FCM error behind err for notification logs
+func firebaseErrorCode(err error) string {
+	switch {
+	case messaging.IsUnregistered(err):
+		return "UNREGISTERED"
+	case messaging.IsInvalidArgument(err):
+		return "INVALID_ARGUMENT"
+	case messaging.IsSenderIDMismatch(err):
+		return "SENDER_ID_MISMATCH"
+	case messaging.IsQuotaExceeded(err):
+		return "QUOTA_EXCEEDED"
+	case messaging.IsUnavailable(err):
+		return "UNAVAILABLE"
+	case messaging.IsInternal(err):
+		return "INTERNAL"
+	case messaging.IsThirdPartyAuthError(err):
+		return "THIRD_PARTY_AUTH_ERROR"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "DEADLINE_EXCEEDED"
+	case errors.Is(err, context.Canceled):
+		return "CANCELED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// newMulticastMessage builds the FCM multicast payload, mapping deep link data onto
+// the platform click actions so tapping the notification opens the linked screen.
+func newMulticastMessage(tokens []string, title, body string, data map[string]string) *messaging.MulticastMessage {
+	return &messaging.MulticastMessage{
 		Tokens: tokens,
 		Notification: &messaging.Notification{
 			Title: title,
 			Body:  body,
 		},
-		Data: data,
+		Data:    data,
+		Android: androidActionConfig(data),
+		APNS:    apnsActionConfig(data),
 	}
+}
 
-	response, err := s.client.SendEachForMulticast(ctx, message)
-	if err != nil {
-		return 0, 0, nil, fmt.Errorf("send firebase multicast notification: %w", err)
+// notificationClickAction returns the click action for payloads that carry a deep link.
+func notificationClickAction(data map[string]string) string {
+	if data[constants.NotificationDataDeepLink] == "" {
+		return ""
 	}
 
-	successCount = response.SuccessCount
-	failureCount = response.FailureCount
+	return data[constants.NotificationDataClickAction]
+}
 
-	// Collect tokens that Firebase has confirmed are permanently unregistered.
-	// INVALID_ARGUMENT can also mean the payload is invalid, so it is not safe
-	// to use it as a device deletion signal.
-	invalidTokens = make([]string, 0)
-	for idx, sendResponse := range response.Responses {
-		if sendResponse.Error != nil {
-			if messaging.IsUnregistered(sendResponse.Error) {
-				invalidTokens = append(invalidTokens, tokens[idx])
-			}
-		}
+// androidActionConfig sets the Android click action when the payload carries a deep link.
+func androidActionConfig(data map[string]string) *messaging.AndroidConfig {
+	clickAction := notificationClickAction(data)
+	if clickAction == "" {
+		return nil
+	}
+
+	return &messaging.AndroidConfig{
+		Notification: &messaging.AndroidNotification{ClickAction: clickAction},
 	}
+}
 
-	return successCount, failureCount, invalidTokens, nil
+// apnsActionConfig sets the APNs category, which iOS maps to the registered notification action.
+func apnsActionConfig(data map[string]string) *messaging.APNSConfig {
+	category := notificationClickAction(data)
+	if category == "" {
+		return nil
+	}
+
+	return &messaging.APNSConfig{
+		Payload: &messaging.APNSPayload{Aps: &messaging.Aps{Category: category}},
+	}
 }
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"radar/config"
+	"radar/internal/domain/constants"
+	"radar/internal/domain/service"
 )
 
 func TestNewFirebaseService_WithLocalDemoConfig_UsesNoopService(t *testing.T) {
@@ -25,7 +27,7 @@ func TestNewFirebaseService_WithLocalDemoConfig_UsesNoopService(t *testing.T) {
 		t.Fatalf("NewFirebaseService returned error: %v", err)
 	}
 
-	successCount, failureCount, invalidTokens, err := notificationSvc.SendBatchNotification(
+	results, err := notificationSvc.SendBatchNotification(
 		context.Background(),
 		[]string{"token-a", "token-b"},
 		"title",
@@ -35,7 +37,8 @@ func TestNewFirebaseService_WithLocalDemoConfig_UsesNoopService(t *testing.T) {
 	if err != nil {
 		t.Fatalf("SendBatchNotification returned error: %v", err)
 	}
-	if successCount != 2 || failureCount != 0 || len(invalidTokens) != 0 {
+	successCount, failureCount, invalidTokens := service.SummarizeTokenResults(results)
+	if len(results) != 2 || successCount != 2 || failureCount != 0 || len(invalidTokens) != 0 {
 		t.Fatalf(
 			"unexpected noop result: success=%d failure=%d invalid=%d",
 			successCount,
@@ -62,3 +65,32 @@ func TestNewFirebaseService_WithLocalRealConfig_RequiresCredentialsFile(t *testi
 		t.Fatal("NewFirebaseService returned nil error for missing real credentials")
 	}
 }
+
+func TestNewMulticastMessage_DeepLinkReachesProviderPayload(t *testing.T) {
+	data := map[string]string{
+		constants.NotificationDataDeepLink:    "nomnom://merchants/merchant-1",
+		constants.NotificationDataClickAction: "VIEW_STORE",
+	}
+
+	message := newMulticastMessage([]string{"token-a"}, "title", "body", data)
+
+	if message.Data[constants.NotificationDataDeepLink] != "nomnom://merchants/merchant-1" {
+		t.Fatalf("deep_link data = %q", message.Data[constants.NotificationDataDeepLink])
+	}
+	if message.Android == nil || message.Android.Notification == nil || message.Android.Notification.ClickAction != "VIEW_STORE" {
+		t.Fatalf("android click action not set: %+v", message.Android)
+	}
+	if message.APNS == nil || message.APNS.Payload == nil || message.APNS.Payload.Aps == nil || message.APNS.Payload.Aps.Category != "VIEW_STORE" {
+		t.Fatalf("apns category not set: %+v", message.APNS)
+	}
+}
+
+func TestNewMulticastMessage_WithoutDeepLinkOmitsClickAction(t *testing.T) {
+	message := newMulticastMessage([]string{"token-a"}, "title", "body", map[string]string{
+		constants.NotificationDataClickAction: "VIEW_STORE",
+	})
+
+	if message.Android != nil || message.APNS != nil {
+		t.Fatalf("expected no platform click action without a deep link: android=%+v apns=%+v", message.Android, message.APNS)
+	}
+}
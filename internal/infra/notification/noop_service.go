@@ -17,8 +17,13 @@ func (s *noopService) SendBatchNotification(
 	tokens []string,
 	_, _ string,
 	_ map[string]string,
-) (successCount, failureCount int, invalidTokens []string, err error) {
-	return len(tokens), 0, nil, nil
+) ([]service.TokenResult, error) {
+	results := make([]service.TokenResult, len(tokens))
+	for idx, token := range tokens {
+		results[idx] = service.TokenResult{Token: token, Status: service.TokenStatusSent}
+	}
+
+	return results, nil
 }
 
 func (s *noopService) SendSingleNotification(
@@ -0,0 +1,174 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"radar/config"
+	"radar/internal/domain/service"
+
+	"firebase.google.com/go/v4/errorutils"
+	"firebase.google.com/go/v4/messaging"
+)
+
+// resilientService decorates a NotificationService with bounded retries for
+// transient provider errors and a circuit breaker that short-circuits sends
+// during a sustained outage. Sends on a service.WithoutSendRetries context are not retried.
+type resilientService struct {
+	next         service.NotificationService
+	breaker      *circuitBreaker
+	maxRetries   int
+	retryBackoff time.Duration
+	logger       *slog.Logger
+}
+
+func newResilientService(next service.NotificationService, cfg *config.NotificationConfig, logger *slog.Logger) *resilientService {
+	return &resilientService{
+		next: next,
+		breaker: newCircuitBreaker(
+			cfg.BreakerFailureRate,
+			cfg.BreakerWindowSize,
+			cfg.BreakerMinRequests,
+			cfg.BreakerOpenDuration,
+		),
+		maxRetries:   max(cfg.MaxRetries, 0),
+		retryBackoff: cfg.RetryBackoff,
+		logger:       logger,
+	}
+}
+
+// SendBatchNotification sends a multicast notification through the wrapped service.
+// Retries only resend the tokens still marked transient, so tokens already delivered are never sent twice.
+func (s *resilientService) SendBatchNotification(ctx context.Context, tokens []string, title, body string, data map[string]string) ([]service.TokenResult, error) {
+	results := make([]service.TokenResult, len(tokens))
+	pending := make([]int, len(tokens))
+	for idx, token := range tokens {
+		results[idx] = service.TokenResult{Token: token, Status: service.TokenStatusTransient, ErrorCode: "NOT_ATTEMPTED"}
+		pending[idx] = idx
+	}
+
+	err := s.execute(ctx, func(ctx context.Context) error {
+		batch := make([]string, len(pending))
+		for idx, tokenIdx := range pending {
+			batch[idx] = tokens[tokenIdx]
+		}
+
+		batchResults, sendErr := s.next.SendBatchNotification(ctx, batch, title, body, data)
+
+		next := pending[:0:0]
+		for idx, tokenIdx := range pending {
+			if idx < len(batchResults) {
+				results[tokenIdx] = batchResults[idx]
+			}
+			if results[tokenIdx].Status == service.TokenStatusTransient {
+				next = append(next, tokenIdx)
+			}
+		}
+		pending = next
+
+		return sendErr
+	})
+
+	return results, err
+}
+
+// SendSingleNotification sends a notification to one device through the wrapped service.
+func (s *resilientService) SendSingleNotification(ctx context.Context, token, title, body string, data map[string]string) error {
+	return s.execute(ctx, func(ctx context.Context) error {
+		return s.next.SendSingleNotification(ctx, token, title, body, data)
+	})
+}
+
+func (s *resilientService) execute(ctx context.Context, send func(ctx context.Context) error) error {
+	if !s.breaker.allow() {
+		return fmt.Errorf("%w: circuit breaker is open", service.ErrNotificationUnavailable)
+	}
+
+	err := s.sendWithRetry(ctx, send)
+	if errors.Is(err, context.Canceled) {
+		// The caller gave up, which says nothing about the provider, so the call neither counts nor settles a probe
+		s.breaker.release()
+
+		return err
+	}
+	s.recordOutcome(err != nil && isTransientSendError(err))
+
+	return err
+}
+
+func (s *resilientService) sendWithRetry(ctx context.Context, send func(ctx context.Context) error) error {
+	maxRetries := s.maxRetries
+	if service.SendRetriesDisabled(ctx) {
+		maxRetries = 0
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = send(ctx)
+		if err == nil || attempt >= maxRetries || !isTransientSendError(err) {
+			return err
+		}
+
+		delay := s.retryBackoff << attempt
+		s.logger.Warn("Retrying notification send after transient error",
+			slog.Int("attempt", attempt+1),
+			slog.Duration("delay", delay),
+			slog.String("error", err.Error()),
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// recordOutcome feeds the breaker. Only transient provider failures count against it;
+// a permanent error such as an invalid payload still proves the provider is reachable.
+func (s *resilientService) recordOutcome(failed bool) {
+	state, changed := s.breaker.record(failed)
+	if !changed {
+		return
+	}
+
+	if state == circuitOpen {
+		s.logger.Warn("Notification circuit breaker opened", slog.String("state", state.String()))
+
+		return
+	}
+
+	s.logger.Info("Notification circuit breaker state changed", slog.String("state", state.String()))
+}
+
+// isTransientSendError reports whether a send failure is worth retrying.
+// Firebase error helpers only inspect the concrete error, so the wrap chain is walked manually.
+func isTransientSendError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if netErr, ok := errors.AsType[net.Error](err); ok && netErr.Timeout() {
+		return true
+	}
+
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		if messaging.IsUnavailable(current) ||
+			messaging.IsInternal(current) ||
+			messaging.IsQuotaExceeded(current) ||
+			errorutils.IsUnavailable(current) ||
+			errorutils.IsInternal(current) ||
+			errorutils.IsDeadlineExceeded(current) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,282 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"radar/config"
+	"radar/internal/domain/service"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+type scriptedNotificationService struct {
+	errs      []error
+	calls     int
+	transient map[string]bool // Tokens reported transient, with a timeout, the first time they are sent
+	batches   [][]string
+}
+
+func (s *scriptedNotificationService) nextErr() error {
+	s.calls++
+	if len(s.errs) == 0 {
+		return nil
+	}
+	err := s.errs[0]
+	if len(s.errs) > 1 {
+		s.errs = s.errs[1:]
+	}
+
+	return err
+}
+
+func (s *scriptedNotificationService) SendBatchNotification(_ context.Context, tokens []string, _, _ string, _ map[string]string) ([]service.TokenResult, error) {
+	s.batches = append(s.batches, tokens)
+	if err := s.nextErr(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	results := make([]service.TokenResult, len(tokens))
+	for idx, token := range tokens {
+		results[idx] = service.TokenResult{Token: token, Status: service.TokenStatusSent}
+		if s.transient[token] {
+			delete(s.transient, token)
+			results[idx] = service.TokenResult{Token: token, Status: service.TokenStatusTransient, ErrorCode: "UNAVAILABLE"}
+			err = timeoutError{}
+		}
+	}
+
+	return results, err
+}
+
+func (s *scriptedNotificationService) SendSingleNotification(context.Context, string, string, string, map[string]string) error {
+	return s.nextErr()
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestResilientService(next service.NotificationService, maxRetries int) (*resilientService, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	svc := newResilientService(next, &config.NotificationConfig{
+		MaxRetries:          maxRetries,
+		RetryBackoff:        time.Millisecond,
+		BreakerFailureRate:  0.5,
+		BreakerWindowSize:   4,
+		BreakerMinRequests:  4,
+		BreakerOpenDuration: time.Minute,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	svc.breaker.now = clock.Now
+
+	return svc, clock
+}
+
+func sendTestBatch(svc *resilientService) error {
+	_, err := svc.SendBatchNotification(context.Background(), []string{"token-a"}, "title", "body", nil)
+
+	return err
+}
+
+func TestResilientService_SustainedFailuresOpenBreaker(t *testing.T) {
+	next := &scriptedNotificationService{errs: []error{timeoutError{}}}
+	svc, _ := newTestResilientService(next, 0)
+
+	for range 4 {
+		if err := sendTestBatch(svc); err == nil {
+			t.Fatal("expected transient send error")
+		}
+	}
+
+	if state := svc.breaker.currentState(); state != circuitOpen {
+		t.Fatalf("breaker state = %s, want open", state)
+	}
+
+	err := sendTestBatch(svc)
+	if !errors.Is(err, service.ErrNotificationUnavailable) {
+		t.Fatalf("expected ErrNotificationUnavailable while open, got %v", err)
+	}
+	if next.calls != 4 {
+		t.Fatalf("provider calls = %d, want 4 (open breaker must short-circuit)", next.calls)
+	}
+}
+
+func TestResilientService_RecoveryProbeClosesBreaker(t *testing.T) {
+	next := &scriptedNotificationService{errs: []error{timeoutError{}, timeoutError{}, timeoutError{}, timeoutError{}, nil}}
+	svc, clock := newTestResilientService(next, 0)
+
+	for range 4 {
+		_ = sendTestBatch(svc)
+	}
+	if state := svc.breaker.currentState(); state != circuitOpen {
+		t.Fatalf("breaker state = %s, want open", state)
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+
+	if err := sendTestBatch(svc); err != nil {
+		t.Fatalf("recovery probe returned error: %v", err)
+	}
+	if state := svc.breaker.currentState(); state != circuitClosed {
+		t.Fatalf("breaker state = %s, want closed after successful probe", state)
+	}
+	if err := sendTestBatch(svc); err != nil {
+		t.Fatalf("send after recovery returned error: %v", err)
+	}
+	if next.calls != 6 {
+		t.Fatalf("provider calls = %d, want 6", next.calls)
+	}
+}
+
+func TestResilientService_FailedProbeReopensBreaker(t *testing.T) {
+	next := &scriptedNotificationService{errs: []error{timeoutError{}}}
+	svc, clock := newTestResilientService(next, 0)
+
+	for range 4 {
+		_ = sendTestBatch(svc)
+	}
+	clock.now = clock.now.Add(time.Minute)
+
+	if err := sendTestBatch(svc); errors.Is(err, service.ErrNotificationUnavailable) || err == nil {
+		t.Fatalf("expected probe to reach the provider and fail, got %v", err)
+	}
+	if state := svc.breaker.currentState(); state != circuitOpen {
+		t.Fatalf("breaker state = %s, want open after failed probe", state)
+	}
+	if err := sendTestBatch(svc); !errors.Is(err, service.ErrNotificationUnavailable) {
+		t.Fatalf("expected ErrNotificationUnavailable after failed probe, got %v", err)
+	}
+}
+
+func TestResilientService_CanceledProbeKeepsBreakerHalfOpen(t *testing.T) {
+	next := &scriptedNotificationService{errs: []error{
+		timeoutError{}, timeoutError{}, timeoutError{}, timeoutError{}, fmt.Errorf("send: %w", context.Canceled), nil,
+	}}
+	svc, clock := newTestResilientService(next, 0)
+
+	for range 4 {
+		_ = sendTestBatch(svc)
+	}
+	clock.now = clock.now.Add(time.Minute)
+
+	if err := sendTestBatch(svc); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the probe to end canceled, got %v", err)
+	}
+	if state := svc.breaker.currentState(); state != circuitHalfOpen {
+		t.Fatalf("breaker state = %s, want half_open after canceled probe", state)
+	}
+
+	// The probe slot is released, so the next call probes the provider
+	if err := sendTestBatch(svc); err != nil {
+		t.Fatalf("second probe returned error: %v", err)
+	}
+	if state := svc.breaker.currentState(); state != circuitClosed {
+		t.Fatalf("breaker state = %s, want closed after successful probe", state)
+	}
+	if next.calls != 6 {
+		t.Fatalf("provider calls = %d, want 6", next.calls)
+	}
+}
+
+func TestResilientService_RetriesTransientErrorsWithinBound(t *testing.T) {
+	next := &scriptedNotificationService{errs: []error{timeoutError{}, timeoutError{}, nil}}
+	svc, _ := newTestResilientService(next, 2)
+
+	if err := sendTestBatch(svc); err != nil {
+		t.Fatalf("expected retries to succeed, got %v", err)
+	}
+	if next.calls != 3 {
+		t.Fatalf("provider calls = %d, want 3", next.calls)
+	}
+
+	exhausted := &scriptedNotificationService{errs: []error{timeoutError{}}}
+	svc, _ = newTestResilientService(exhausted, 2)
+
+	if err := svc.SendSingleNotification(context.Background(), "token-a", "title", "body", nil); err == nil {
+		t.Fatal("expected error after exhausting retries")
+	}
+	if exhausted.calls != 3 {
+		t.Fatalf("provider calls = %d, want 3", exhausted.calls)
+	}
+}
+
+func TestResilientService_WithoutSendRetriesMakesOneAttempt(t *testing.T) {
+	next := &scriptedNotificationService{errs: []error{timeoutError{}, nil}}
+	svc, _ := newTestResilientService(next, 2)
+
+	// The caller resends transient tokens itself, so the provider is called once
+	_, err := svc.SendBatchNotification(service.WithoutSendRetries(context.Background()), []string{"token-a"}, "title", "body", nil)
+	if err == nil {
+		t.Fatal("expected the transient error without a retry")
+	}
+	if next.calls != 1 {
+		t.Fatalf("provider calls = %d, want 1", next.calls)
+	}
+}
+
+func TestResilientService_PermanentErrorsAreNotRetriedOrCounted(t *testing.T) {
+	next := &scriptedNotificationService{errs: []error{errors.New("token count exceeds limit")}}
+	svc, _ := newTestResilientService(next, 2)
+
+	for range 6 {
+		if err := sendTestBatch(svc); err == nil {
+			t.Fatal("expected permanent send error")
+		}
+	}
+
+	if next.calls != 6 {
+		t.Fatalf("provider calls = %d, want 6 (no retries for permanent errors)", next.calls)
+	}
+	if state := svc.breaker.currentState(); state != circuitClosed {
+		t.Fatalf("breaker state = %s, want closed", state)
+	}
+}
+
+func TestResilientService_RetriesOnlyTransientTokens(t *testing.T) {
+	next := &scriptedNotificationService{transient: map[string]bool{"token-b": true}}
+	svc, _ := newTestResilientService(next, 2)
+
+	results, err := svc.SendBatchNotification(context.Background(), []string{"token-a", "token-b", "token-c"}, "title", "body", nil)
+	if err != nil {
+		t.Fatalf("expected the retry to deliver the transient token, got %v", err)
+	}
+	if len(next.batches) != 2 || len(next.batches[1]) != 1 || next.batches[1][0] != "token-b" {
+		t.Fatalf("provider batches = %v, want the retry to resend only token-b", next.batches)
+	}
+	for _, result := range results {
+		if result.Status != service.TokenStatusSent {
+			t.Fatalf("token %s status = %s, want sent", result.Token, result.Status)
+		}
+	}
+}
+
+func TestResilientService_OpenBreakerMarksTokensTransient(t *testing.T) {
+	next := &scriptedNotificationService{errs: []error{timeoutError{}}}
+	svc, _ := newTestResilientService(next, 0)
+
+	for range 4 {
+		_ = sendTestBatch(svc)
+	}
+
+	results, err := svc.SendBatchNotification(context.Background(), []string{"token-a"}, "title", "body", nil)
+	if !errors.Is(err, service.ErrNotificationUnavailable) {
+		t.Fatalf("expected ErrNotificationUnavailable while open, got %v", err)
+	}
+	if len(results) != 1 || results[0].Status != service.TokenStatusTransient {
+		t.Fatalf("results = %+v, want the token left transient", results)
+	}
+}
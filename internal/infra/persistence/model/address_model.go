@@ -86,9 +86,11 @@ type AddressModel struct {
 	// Use raw SQL queries with PostGIS functions (ST_Distance, ST_DWithin) for geospatial operations.
 	IsPrimary bool `gorm:"not null;default:false"`
 	IsActive  bool `gorm:"not null;default:true"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	// Optional subscriber neighborhood; NULL keeps the subscription radius
+	GeofencePolygon Polygon `gorm:"type:geometry(Polygon,4326)"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	DeletedAt       gorm.DeletedAt `gorm:"index"`
 }
 
 // IsUserAddress returns true if this address belongs to a user profile
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APITokenModel is the GORM-specific struct for the 'merchant_api_tokens' table.
+type APITokenModel struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v7()"`
+	MerchantID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Name       string    `gorm:"type:text;not null"`
+	TokenHash  string    `gorm:"type:text;unique;not null"`
+	Scopes     []string  `gorm:"type:jsonb;serializer:json;not null"`
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// TableName explicitly sets the table name for GORM.
+func (APITokenModel) TableName() string {
+	return "merchant_api_tokens"
+}
@@ -32,6 +32,7 @@ type RefreshTokenModel struct {
 	IsRevoked  bool       `gorm:"not null;default:false"`
 	ReplacedBy *uuid.UUID `gorm:"type:uuid"`
 	ExpiresAt  time.Time  `gorm:"not null"`
+	LastUsedAt *time.Time
 	CreatedAt  time.Time
 }
 
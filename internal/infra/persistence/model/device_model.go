@@ -15,11 +15,25 @@ type UserDeviceModel struct {
 	FCMToken         string    `gorm:"type:text;not null"`
 	DeviceID         string    `gorm:"type:text;not null"`
 	Platform         string    `gorm:"type:text;not null"`
+	AppVersion       string    `gorm:"type:text;not null;default:''"`
 	IsActive         bool      `gorm:"not null;default:true"`
 	TokenRefreshedAt time.Time `gorm:"not null;default:now()"`
-	CreatedAt        time.Time
-	UpdatedAt        time.Time
-	DeletedAt        gorm.DeletedAt `gorm:"index"`
+
+	// Consecutive invalid-token responses and when the current run of them started
+	InvalidTokenStrikes int        `gorm:"not null;default:0"`
+	InvalidTokenFirstAt *time.Time `gorm:"type:timestamptz"`
+
+	// When the worker last delivered a notification to the device
+	LastSuccessAt *time.Time `gorm:"type:timestamptz"`
+
+	// Per-device overrides of the owner's notification preferences; NULL inherits them
+	NotificationsEnabled *bool
+	QuietHoursStart      *int `gorm:"type:smallint"`
+	QuietHoursEnd        *int `gorm:"type:smallint"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
 // TableName explicitly sets the table name for GORM.
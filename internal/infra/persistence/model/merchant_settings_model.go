@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MerchantSettingsModel is the GORM-specific struct for the 'merchant_settings' table.
+// Nil columns mean the merchant has not overridden the service default.
+type MerchantSettingsModel struct {
+	MerchantID                  uuid.UUID `gorm:"type:uuid;primary_key"`
+	MaxNotificationRadius       *float64  `gorm:"type:decimal(10,2)"`
+	BroadcastsPerHour           *int
+	DistanceUnit                *string `gorm:"type:text"`
+	RotateNotificationTemplates *bool
+	CreatedAt                   time.Time
+	UpdatedAt                   time.Time
+}
+
+// TableName explicitly sets the table name for GORM.
+func (MerchantSettingsModel) TableName() string {
+	return "merchant_settings"
+}
@@ -22,9 +22,14 @@ type MerchantLocationNotificationModel struct {
 	HintMessage string `gorm:"type:text"`
 	TotalSent   int    `gorm:"not null;default:0"`
 	TotalFailed int    `gorm:"not null;default:0"`
+	TotalOpened int    `gorm:"not null;default:0"`
 	PublishedAt time.Time
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+
+	ScheduledAt    *time.Time
+	ScheduleStatus *string `gorm:"type:text"`
+	ClaimedAt      *time.Time
 }
 
 // TableName explicitly sets the table name for GORM.
@@ -43,6 +48,7 @@ type NotificationLogModel struct {
 	FCMMessageID   string    `gorm:"type:text"`
 	ErrorMessage   string    `gorm:"type:text"`
 	SentAt         time.Time
+	OpenedAt       *time.Time
 }
 
 // TableName explicitly sets the table name for GORM.
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationPreferenceModel is the GORM-specific struct for the 'user_notification_preferences' table.
+type NotificationPreferenceModel struct {
+	UserID              uuid.UUID   `gorm:"type:uuid;primary_key"`
+	Timezone            string      `gorm:"type:text;not null;default:UTC"`
+	QuietHoursStart     *int        `gorm:"type:smallint"`
+	QuietHoursEnd       *int        `gorm:"type:smallint"`
+	OptedOutCategoryIDs []uuid.UUID `gorm:"type:jsonb;serializer:json;not null"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// TableName explicitly sets the table name for GORM.
+func (NotificationPreferenceModel) TableName() string {
+	return "user_notification_preferences"
+}
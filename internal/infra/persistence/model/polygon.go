@@ -0,0 +1,49 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/ewkb"
+)
+
+// polygonSRID is the spatial reference of every polygon column, matching the WGS 84 point locations
+const polygonSRID = 4326
+
+// Polygon maps a nullable PostGIS GEOMETRY(POLYGON, 4326) column; an empty polygon is NULL.
+// Values are written as hex EWKB, which the geometry input accepts, and read back from the same encoding.
+type Polygon struct {
+	orb.Polygon
+}
+
+// Scan decodes a polygon column. A stored geometry that is not a polygon is an error rather than
+// being read as no polygon, so a bad row surfaces instead of silently dropping its geofence.
+func (p *Polygon) Scan(value any) error {
+	p.Polygon = nil
+	if text, ok := value.(string); ok {
+		value = []byte(text)
+	}
+
+	var polygon orb.Polygon
+	if err := ewkb.Scanner(&polygon).Scan(value); err != nil {
+		return fmt.Errorf("scan polygon: %w", err)
+	}
+	p.Polygon = polygon
+
+	return nil
+}
+
+// Value encodes the polygon as hex EWKB, or NULL when it has no rings.
+func (p Polygon) Value() (driver.Value, error) {
+	if len(p.Polygon) == 0 {
+		return nil, nil
+	}
+
+	encoded, err := ewkb.MarshalToHex(p.Polygon, polygonSRID)
+	if err != nil {
+		return nil, fmt.Errorf("encode polygon: %w", err)
+	}
+
+	return encoded, nil
+}
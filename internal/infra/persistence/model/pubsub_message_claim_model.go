@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// PubSubMessageClaimModel mirrors the 'pubsub_message_claims' table used to deduplicate push deliveries.
+type PubSubMessageClaimModel struct {
+	MessageID string    `gorm:"type:text;primary_key"`
+	Status    string    `gorm:"type:text;not null"`
+	ExpiresAt time.Time `gorm:"type:timestamptz;not null"`
+	CreatedAt time.Time
+}
+
+// TableName explicitly sets the table name for GORM.
+func (PubSubMessageClaimModel) TableName() string {
+	return "pubsub_message_claims"
+}
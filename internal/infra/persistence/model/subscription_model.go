@@ -15,6 +15,7 @@ type UserMerchantSubscriptionModel struct {
 	MerchantID         uuid.UUID `gorm:"type:uuid;not null;index"`
 	IsActive           bool      `gorm:"not null;default:true"`
 	NotificationRadius float64   `gorm:"type:decimal(10,2);not null;default:1000.0"`
+	SnoozedUntil       *time.Time
 	SubscribedAt       time.Time
 	UpdatedAt          time.Time
 	DeletedAt          gorm.DeletedAt `gorm:"index"`
@@ -30,11 +30,12 @@ func (UserModel) TableName() string {
 
 // UserProfileModel mirrors the 'user_profiles' table. UserID references users.id (UUID).
 type UserProfileModel struct {
-	UserID        uuid.UUID       `gorm:"primaryKey"`
-	Addresses     []*AddressModel `gorm:"foreignKey:UserProfileID"`
-	LoyaltyPoints int
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	UserID                 uuid.UUID       `gorm:"primaryKey"`
+	Addresses              []*AddressModel `gorm:"foreignKey:UserProfileID"`
+	LoyaltyPoints          int
+	BroadcastsSnoozedUntil *time.Time
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
 }
 
 // TableName explicitly sets the table name for GORM.
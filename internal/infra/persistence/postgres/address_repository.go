@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"radar/internal/domain/entity"
 	domainerrors "radar/internal/domain/errors"
@@ -12,8 +13,11 @@ import (
 	"radar/internal/infra/persistence/postgres/query"
 
 	"github.com/google/uuid"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
 	"github.com/slighter12/go-lib/errors/stack"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type addressRepository struct {
@@ -32,6 +36,9 @@ func (repo *addressRepository) CreateAddress(ctx context.Context, address *entit
 	addressM := fromAddressDomain(address)
 
 	if err := repo.q.AddressModel.WithContext(ctx).Create(addressM); err != nil {
+		if isInvalidGeofenceViolation(err) {
+			return replaceWithSourceStack(err, domainerrors.ErrInvalidGeofence)
+		}
 		if isUniqueConstraintViolation(err) {
 			return replaceWithSourceStack(err, domainerrors.ErrPrimaryAddressConflict)
 		}
@@ -136,6 +143,9 @@ func (repo *addressRepository) UpdateAddress(ctx context.Context, address *entit
 	addressM := fromAddressDomain(address)
 
 	if err := repo.q.AddressModel.WithContext(ctx).Save(addressM); err != nil {
+		if isInvalidGeofenceViolation(err) {
+			return replaceWithSourceStack(err, domainerrors.ErrInvalidGeofence)
+		}
 		if isUniqueConstraintViolation(err) {
 			return replaceWithSourceStack(err, domainerrors.ErrPrimaryAddressConflict)
 		}
@@ -173,6 +183,84 @@ func (repo *addressRepository) DeleteAddress(ctx context.Context, id uuid.UUID)
 	return nil
 }
 
+// FindDeletedAddressByID retrieves a soft-deleted address by its ID.
+func (repo *addressRepository) FindDeletedAddressByID(ctx context.Context, id uuid.UUID) (*entity.Address, error) {
+	addressM, err := repo.q.AddressModel.WithContext(ctx).
+		Unscoped().
+		Where(
+			repo.q.AddressModel.ID.Eq(id),
+			repo.q.AddressModel.DeletedAt.IsNotNull(),
+		).
+		First()
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, replaceWithSourceStack(err, domainerrors.ErrAddressNotFound)
+		}
+
+		return nil, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	return toAddressDomain(addressM), nil
+}
+
+// RestoreAddress undoes the soft delete of an address by its ID, restoring it as non-primary.
+func (repo *addressRepository) RestoreAddress(ctx context.Context, id uuid.UUID) error {
+	result, err := repo.q.AddressModel.WithContext(ctx).
+		Unscoped().
+		Where(
+			repo.q.AddressModel.ID.Eq(id),
+			repo.q.AddressModel.DeletedAt.IsNotNull(),
+		).
+		UpdateSimple(
+			repo.q.AddressModel.DeletedAt.Null(),
+			repo.q.AddressModel.IsPrimary.Value(false),
+			repo.q.AddressModel.UpdatedAt.Value(time.Now()),
+		)
+
+	if err != nil {
+		return replaceWithSourceStack(err, domainerrors.ErrAddressUpdateFailed)
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrAddressNotFound
+	}
+
+	return nil
+}
+
+// LockOwner locks the owner's user or merchant profile row for the current transaction.
+func (repo *addressRepository) LockOwner(ctx context.Context, ownerID uuid.UUID, ownerType entity.OwnerType) error {
+	var err error
+	switch ownerType {
+	case entity.OwnerTypeUserProfile:
+		_, err = repo.q.UserProfileModel.WithContext(ctx).
+			Select(repo.q.UserProfileModel.UserID).
+			Clauses(clause.Locking{Strength: rowLockStrengthUpdate}).
+			Where(repo.q.UserProfileModel.UserID.Eq(ownerID)).
+			Take()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return replaceWithSourceStack(err, domainerrors.ErrUserProfileNotFound)
+		}
+	case entity.OwnerTypeMerchantProfile:
+		_, err = repo.q.MerchantProfileModel.WithContext(ctx).
+			Select(repo.q.MerchantProfileModel.UserID).
+			Clauses(clause.Locking{Strength: rowLockStrengthUpdate}).
+			Where(repo.q.MerchantProfileModel.UserID.Eq(ownerID)).
+			Take()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return replaceWithSourceStack(err, domainerrors.ErrMerchantNotFound)
+		}
+	default:
+		return stack.With(fmt.Errorf("unsupported owner type: %s", ownerType))
+	}
+	if err != nil {
+		return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	return nil
+}
+
 // CountAddressesByOwner returns the total count of addresses for a specific owner (excluding soft-deleted).
 func (repo *addressRepository) CountAddressesByOwner(ctx context.Context, ownerID uuid.UUID, ownerType entity.OwnerType) (int64, error) {
 	query := repo.q.AddressModel.WithContext(ctx)
@@ -234,6 +322,38 @@ func (repo *addressRepository) FindActiveAddressesByOwner(ctx context.Context, o
 	return addresses, nil
 }
 
+// FindPrimaryAddressesByOwnerType retrieves the active primary address of every owner of the given type.
+func (repo *addressRepository) FindPrimaryAddressesByOwnerType(ctx context.Context, ownerType entity.OwnerType) ([]*entity.Address, error) {
+	query := repo.q.AddressModel.WithContext(ctx).
+		Where(
+			repo.q.AddressModel.IsPrimary.Is(true),
+			repo.q.AddressModel.IsActive.Is(true),
+			repo.q.AddressModel.DeletedAt.IsNull(),
+		)
+
+	// Apply owner filter based on owner type
+	switch ownerType {
+	case entity.OwnerTypeUserProfile:
+		query = query.Where(repo.q.AddressModel.UserProfileID.IsNotNull())
+	case entity.OwnerTypeMerchantProfile:
+		query = query.Where(repo.q.AddressModel.MerchantProfileID.IsNotNull())
+	default:
+		return nil, stack.With(fmt.Errorf("unsupported owner type: %s", ownerType))
+	}
+
+	addressModels, err := query.Find()
+	if err != nil {
+		return nil, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	addresses := make([]*entity.Address, 0, len(addressModels))
+	for _, addressM := range addressModels {
+		addresses = append(addresses, toAddressDomain(addressM))
+	}
+
+	return addresses, nil
+}
+
 // --- Mapper Functions ---
 
 // toAddressDomain converts a GORM AddressModel to a domain Address entity.
@@ -266,6 +386,8 @@ func toAddressDomain(data *model.AddressModel) *entity.Address {
 		IsActive:    data.IsActive,
 		CreatedAt:   data.CreatedAt,
 		UpdatedAt:   data.UpdatedAt,
+
+		GeofencePolygon: geojson.Polygon(data.GeofencePolygon.Polygon),
 	}
 }
 
@@ -285,6 +407,8 @@ func fromAddressDomain(data *entity.Address) *model.AddressModel {
 		IsActive:    data.IsActive,
 		CreatedAt:   data.CreatedAt,
 		UpdatedAt:   data.UpdatedAt,
+
+		GeofencePolygon: model.Polygon{Polygon: orb.Polygon(data.GeofencePolygon)},
 	}
 
 	// Set the appropriate FK field based on owner type
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
+
+	"github.com/google/uuid"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/ewkb"
+	"github.com/paulmach/orb/geojson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newDryRunAddressRepository(t *testing.T) (*addressRepository, *captureSQLLogger) {
+	t.Helper()
+
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	repo, ok := NewAddressRepository(db).(*addressRepository)
+	require.True(t, ok)
+
+	return repo, sqlLogger
+}
+
+func TestAddressRepository_DeleteAddress_SoftDeletes(t *testing.T) {
+	repo, sqlLogger := newDryRunAddressRepository(t)
+
+	// Dry-run statements affect no rows, which reads as an address that does not exist
+	err := repo.DeleteAddress(context.Background(), uuid.New())
+	require.ErrorIs(t, err, domainerrors.ErrAddressNotFound)
+	require.Len(t, sqlLogger.queries, 1)
+
+	sql := strings.ReplaceAll(sqlLogger.queries[0], `"`, "")
+	assert.Contains(t, sql, "UPDATE addresses SET deleted_at=")
+	assert.Contains(t, sql, "addresses.deleted_at IS NULL")
+	assert.NotContains(t, sql, "DELETE FROM")
+}
+
+func TestAddressRepository_FindDeletedAddressByID_OnlyMatchesDeletedRows(t *testing.T) {
+	repo, sqlLogger := newDryRunAddressRepository(t)
+
+	_, _ = repo.FindDeletedAddressByID(context.Background(), uuid.New())
+	require.Len(t, sqlLogger.queries, 1)
+
+	sql := strings.ReplaceAll(sqlLogger.queries[0], `"`, "")
+	assert.Contains(t, sql, "addresses.deleted_at IS NOT NULL")
+	assert.NotContains(t, sql, "addresses.deleted_at IS NULL")
+}
+
+func TestAddressRepository_RestoreAddress_ClearsDeletedAtAndPrimary(t *testing.T) {
+	repo, sqlLogger := newDryRunAddressRepository(t)
+
+	err := repo.RestoreAddress(context.Background(), uuid.New())
+	require.ErrorIs(t, err, domainerrors.ErrAddressNotFound)
+	require.Len(t, sqlLogger.queries, 1)
+
+	sql := strings.ReplaceAll(sqlLogger.queries[0], `"`, "")
+	assert.Contains(t, sql, "UPDATE addresses SET deleted_at=NULL,is_primary=false,updated_at=")
+	assert.Contains(t, sql, "addresses.deleted_at IS NOT NULL")
+}
+
+func TestAddressRepository_LockOwner_LocksTheOwnersProfileRow(t *testing.T) {
+	repo, sqlLogger := newDryRunAddressRepository(t)
+
+	_ = repo.LockOwner(context.Background(), uuid.New(), entity.OwnerTypeUserProfile)
+	_ = repo.LockOwner(context.Background(), uuid.New(), entity.OwnerTypeMerchantProfile)
+	require.Len(t, sqlLogger.queries, 2)
+
+	userSQL := strings.ReplaceAll(sqlLogger.queries[0], `"`, "")
+	assert.Contains(t, userSQL, "FROM user_profiles WHERE user_profiles.user_id =")
+	assert.Contains(t, userSQL, "FOR UPDATE")
+	merchantSQL := strings.ReplaceAll(sqlLogger.queries[1], `"`, "")
+	assert.Contains(t, merchantSQL, "FROM merchant_profiles WHERE merchant_profiles.user_id =")
+	assert.Contains(t, merchantSQL, "FOR UPDATE")
+}
+
+func TestAddressRepository_CreateAddress_WritesGeofencePolygon(t *testing.T) {
+	repo, sqlLogger := newDryRunAddressRepository(t)
+	polygon := geojson.Polygon{{{121.55, 25.02}, {121.58, 25.02}, {121.58, 25.05}, {121.55, 25.02}}}
+	stored, err := ewkb.MarshalToHex(orb.Polygon(polygon), 4326)
+	require.NoError(t, err)
+
+	fenced := &entity.Address{OwnerID: uuid.New(), OwnerType: entity.OwnerTypeUserProfile, GeofencePolygon: polygon}
+	require.NoError(t, repo.CreateAddress(context.Background(), fenced))
+	unfenced := &entity.Address{OwnerID: uuid.New(), OwnerType: entity.OwnerTypeUserProfile}
+	require.NoError(t, repo.CreateAddress(context.Background(), unfenced))
+	require.Len(t, sqlLogger.queries, 2)
+
+	fencedSQL := strings.ReplaceAll(sqlLogger.queries[0], `"`, "")
+	assert.Contains(t, fencedSQL, "geofence_polygon")
+	assert.Contains(t, fencedSQL, "'"+stored+"'")
+	unfencedSQL := strings.ReplaceAll(sqlLogger.queries[1], `"`, "")
+	assert.Contains(t, unfencedSQL, "geofence_polygon")
+	assert.NotContains(t, unfencedSQL, stored)
+}
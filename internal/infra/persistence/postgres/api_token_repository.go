@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
+	"radar/internal/domain/repository"
+	"radar/internal/infra/persistence/model"
+	"radar/internal/infra/persistence/postgres/query"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// apiTokenRepository implements the repository.APITokenRepository interface.
+type apiTokenRepository struct {
+	q *query.Query
+}
+
+// NewAPITokenRepository is the constructor for apiTokenRepository.
+func NewAPITokenRepository(db *gorm.DB) repository.APITokenRepository {
+	return &apiTokenRepository{
+		q: query.Use(db),
+	}
+}
+
+// CreateAPIToken persists a new API token.
+func (repo *apiTokenRepository) CreateAPIToken(ctx context.Context, token *entity.APIToken) error {
+	tokenM := fromAPITokenDomain(token)
+
+	if err := repo.q.APITokenModel.WithContext(ctx).Create(tokenM); err != nil {
+		if isForeignKeyConstraintViolation(err) {
+			return replaceWithSourceStack(err, domainerrors.ErrMerchantNotFound)
+		}
+
+		return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	// Update the entity with generated values
+	token.ID = tokenM.ID
+	token.CreatedAt = tokenM.CreatedAt
+
+	return nil
+}
+
+// FindAPITokenByHash retrieves an API token, including a revoked one, by the hash of its raw value.
+func (repo *apiTokenRepository) FindAPITokenByHash(ctx context.Context, tokenHash string) (*entity.APIToken, error) {
+	tokenM, err := repo.q.APITokenModel.WithContext(ctx).
+		Where(repo.q.APITokenModel.TokenHash.Eq(tokenHash)).
+		First()
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, replaceWithSourceStack(err, domainerrors.ErrAPITokenNotFound)
+		}
+
+		return nil, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	return toAPITokenDomain(tokenM), nil
+}
+
+// ListAPITokensByMerchant retrieves a merchant's API tokens, newest first, including revoked ones.
+func (repo *apiTokenRepository) ListAPITokensByMerchant(ctx context.Context, merchantID uuid.UUID) ([]*entity.APIToken, error) {
+	tokenModels, err := repo.q.APITokenModel.WithContext(ctx).
+		Where(repo.q.APITokenModel.MerchantID.Eq(merchantID)).
+		Order(repo.q.APITokenModel.CreatedAt.Desc()).
+		Find()
+
+	if err != nil {
+		return nil, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	tokens := make([]*entity.APIToken, 0, len(tokenModels))
+	for _, tokenM := range tokenModels {
+		tokens = append(tokens, toAPITokenDomain(tokenM))
+	}
+
+	return tokens, nil
+}
+
+// RevokeAPIToken marks one of a merchant's active API tokens as revoked.
+func (repo *apiTokenRepository) RevokeAPIToken(ctx context.Context, merchantID, tokenID uuid.UUID, revokedAt time.Time) error {
+	result, err := repo.q.APITokenModel.WithContext(ctx).
+		Where(
+			repo.q.APITokenModel.ID.Eq(tokenID),
+			repo.q.APITokenModel.MerchantID.Eq(merchantID),
+			repo.q.APITokenModel.RevokedAt.IsNull(),
+		).
+		Update(repo.q.APITokenModel.RevokedAt, revokedAt)
+
+	if err != nil {
+		return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrAPITokenNotFound
+	}
+
+	return nil
+}
+
+// --- Mapper Functions ---
+
+// toAPITokenDomain converts a GORM APITokenModel to a domain APIToken entity.
+func toAPITokenDomain(data *model.APITokenModel) *entity.APIToken {
+	if data == nil {
+		return nil
+	}
+
+	scopes := make([]entity.APITokenScope, len(data.Scopes))
+	for idx, scope := range data.Scopes {
+		scopes[idx] = entity.APITokenScope(scope)
+	}
+
+	return &entity.APIToken{
+		ID:         data.ID,
+		MerchantID: data.MerchantID,
+		Name:       data.Name,
+		TokenHash:  data.TokenHash,
+		Scopes:     scopes,
+		RevokedAt:  data.RevokedAt,
+		CreatedAt:  data.CreatedAt,
+	}
+}
+
+// fromAPITokenDomain converts a domain APIToken entity to a GORM APITokenModel.
+func fromAPITokenDomain(data *entity.APIToken) *model.APITokenModel {
+	if data == nil {
+		return nil
+	}
+
+	scopes := make([]string, len(data.Scopes))
+	for idx, scope := range data.Scopes {
+		scopes[idx] = string(scope)
+	}
+
+	return &model.APITokenModel{
+		ID:         data.ID,
+		MerchantID: data.MerchantID,
+		Name:       data.Name,
+		TokenHash:  data.TokenHash,
+		Scopes:     scopes,
+		RevokedAt:  data.RevokedAt,
+		CreatedAt:  data.CreatedAt,
+	}
+}
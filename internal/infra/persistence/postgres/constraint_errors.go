@@ -11,6 +11,7 @@ import (
 const (
 	constraintMerchantBusinessLicenseActive = "idx_merchant_profiles_business_license_active"
 	constraintUsersEmailActive              = "idx_users_email_active"
+	constraintAddressGeofenceValid          = "chk_addresses_geofence_polygon_valid"
 	rowLockStrengthUpdate                   = "UPDATE"
 )
 
@@ -53,6 +54,8 @@ func violatedConstraintName(err error) string {
 		return "idx_auth_provider_provider_user_id_active"
 	case strings.Contains(errMsg, "idx_auth_provider_provider_user_id"):
 		return "idx_auth_provider_provider_user_id"
+	case strings.Contains(errMsg, constraintAddressGeofenceValid):
+		return constraintAddressGeofenceValid
 	default:
 		return ""
 	}
@@ -67,6 +70,11 @@ func isBusinessLicenseUniqueConstraint(err error) bool {
 	}
 }
 
+// isInvalidGeofenceViolation reports whether an address write stored a self-intersecting or otherwise invalid polygon
+func isInvalidGeofenceViolation(err error) bool {
+	return violatedConstraintName(err) == constraintAddressGeofenceValid
+}
+
 func isForeignKeyConstraintViolation(err error) bool {
 	// Check for GORM's foreign key violation error
 	if errors.Is(err, gorm.ErrForeignKeyViolated) {
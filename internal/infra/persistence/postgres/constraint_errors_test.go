@@ -83,3 +83,35 @@ func TestIsBusinessLicenseUniqueConstraint(t *testing.T) {
 		})
 	}
 }
+
+func TestIsInvalidGeofenceViolation(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "geofence validity check",
+			err:  &pgconn.PgError{Code: "23514", ConstraintName: "chk_addresses_geofence_polygon_valid"},
+			want: true,
+		},
+		{
+			name: "geofence validity check message",
+			err: errors.New(
+				`ERROR: new row for relation "addresses" violates check constraint "chk_addresses_geofence_polygon_valid" (SQLSTATE 23514)`,
+			),
+			want: true,
+		},
+		{
+			name: "other check constraint",
+			err:  &pgconn.PgError{Code: "23514", ConstraintName: "chk_addresses_owner"},
+			want: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, isInvalidGeofenceViolation(tc.err))
+		})
+	}
+}
@@ -14,7 +14,9 @@ import (
 	"radar/internal/infra/persistence/postgres/query"
 
 	"github.com/google/uuid"
+	"gorm.io/gen/field"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // deviceRepository implements the repository.DeviceRepository interface.
@@ -30,10 +32,28 @@ func NewDeviceRepository(db *gorm.DB) repository.DeviceRepository {
 }
 
 // CreateDevice persists a new device for a user.
+// A live device of the same user with the same FCM token is refreshed in place instead, so an app that
+// registers again under a new client device ID keeps a single row for its token.
 func (repo *deviceRepository) CreateDevice(ctx context.Context, device *entity.UserDevice) error {
 	deviceM := fromDeviceDomain(device)
 
-	if err := repo.q.UserDeviceModel.WithContext(ctx).Create(deviceM); err != nil {
+	if err := repo.q.UserDeviceModel.WithContext(ctx).
+		Clauses(
+			clause.OnConflict{
+				Columns:     []clause.Column{{Name: "user_id"}, {Name: "fcm_token"}},
+				TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+				DoUpdates: clause.AssignmentColumns([]string{
+					"device_id",
+					"platform",
+					"app_version",
+					"is_active",
+					"token_refreshed_at",
+					"updated_at",
+				}),
+			},
+			clause.Returning{},
+		).
+		Create(deviceM); err != nil {
 		if isUniqueConstraintViolation(err) {
 			return replaceWithSourceStack(err, domainerrors.ErrDeviceAlreadyExists)
 		}
@@ -44,7 +64,7 @@ func (repo *deviceRepository) CreateDevice(ctx context.Context, device *entity.U
 		return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
 	}
 
-	// Update the entity with generated values
+	// Update the entity with generated values, or with the refreshed row's ID and creation time
 	device.ID = deviceM.ID
 	device.TokenRefreshedAt = deviceM.TokenRefreshedAt
 	device.CreatedAt = deviceM.CreatedAt
@@ -184,6 +204,8 @@ func (repo *deviceRepository) UpdateFCMToken(ctx context.Context, deviceID uuid.
 		UpdateSimple(
 			repo.q.UserDeviceModel.FCMToken.Value(fcmToken),
 			repo.q.UserDeviceModel.TokenRefreshedAt.Value(now),
+			repo.q.UserDeviceModel.InvalidTokenStrikes.Value(0),
+			repo.q.UserDeviceModel.InvalidTokenFirstAt.Null(),
 		)
 
 	if err != nil {
@@ -201,6 +223,55 @@ func (repo *deviceRepository) UpdateFCMToken(ctx context.Context, deviceID uuid.
 	return nil
 }
 
+// UpdateDeviceClientInfo updates the platform and app version reported by the device's client.
+func (repo *deviceRepository) UpdateDeviceClientInfo(ctx context.Context, deviceID uuid.UUID, platform, appVersion string) error {
+	result, err := repo.q.UserDeviceModel.WithContext(ctx).
+		Where(repo.q.UserDeviceModel.ID.Eq(deviceID)).
+		UpdateSimple(
+			repo.q.UserDeviceModel.Platform.Value(platform),
+			repo.q.UserDeviceModel.AppVersion.Value(appVersion),
+		)
+	if err != nil {
+		return replaceWithSourceStack(err, domainerrors.ErrDeviceUpdateFailed)
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrDeviceNotFound
+	}
+
+	return nil
+}
+
+// UpdateDeviceNotificationSettings replaces the notification overrides of a specific device; nil settings are cleared.
+func (repo *deviceRepository) UpdateDeviceNotificationSettings(
+	ctx context.Context,
+	deviceID uuid.UUID,
+	settings entity.DeviceNotificationSettings,
+) error {
+	enabled := repo.q.UserDeviceModel.NotificationsEnabled.Null()
+	if settings.NotificationsEnabled != nil {
+		enabled = repo.q.UserDeviceModel.NotificationsEnabled.Value(*settings.NotificationsEnabled)
+	}
+	quietStart, quietEnd := repo.q.UserDeviceModel.QuietHoursStart.Null(), repo.q.UserDeviceModel.QuietHoursEnd.Null()
+	if settings.HasQuietHours() {
+		quietStart = repo.q.UserDeviceModel.QuietHoursStart.Value(*settings.QuietHoursStart)
+		quietEnd = repo.q.UserDeviceModel.QuietHoursEnd.Value(*settings.QuietHoursEnd)
+	}
+
+	result, err := repo.q.UserDeviceModel.WithContext(ctx).
+		Where(repo.q.UserDeviceModel.ID.Eq(deviceID), repo.q.UserDeviceModel.DeletedAt.IsNull()).
+		UpdateSimple(enabled, quietStart, quietEnd)
+	if err != nil {
+		return replaceWithSourceStack(err, domainerrors.ErrDeviceUpdateFailed)
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrDeviceNotFound
+	}
+
+	return nil
+}
+
 // SetDeviceActive updates the active state for a specific device without deleting it.
 func (repo *deviceRepository) SetDeviceActive(ctx context.Context, id uuid.UUID, isActive bool) error {
 	result, err := repo.q.UserDeviceModel.WithContext(ctx).
@@ -232,6 +303,8 @@ func (repo *deviceRepository) RestoreAndUpdateDevice(ctx context.Context, userID
 			repo.q.UserDeviceModel.FCMToken.Value(fcmToken),
 			repo.q.UserDeviceModel.TokenRefreshedAt.Value(now),
 			repo.q.UserDeviceModel.IsActive.Value(true),
+			repo.q.UserDeviceModel.InvalidTokenStrikes.Value(0),
+			repo.q.UserDeviceModel.InvalidTokenFirstAt.Null(),
 		)
 	if err != nil {
 		if isUniqueConstraintViolation(err) {
@@ -268,9 +341,133 @@ func (repo *deviceRepository) SoftDeleteStaleDevices(ctx context.Context, staleD
 	return result.RowsAffected, nil
 }
 
+// RecordInvalidTokenStrike counts an invalid-token response for a device and returns its consecutive strikes.
+// The device row is locked so concurrent sends to the same device count each strike once.
+func (repo *deviceRepository) RecordInvalidTokenStrike(ctx context.Context, id uuid.UUID, at time.Time, window time.Duration) (int, error) {
+	strikes := 0
+	err := repo.q.Transaction(func(tx *query.Query) error {
+		devices := tx.UserDeviceModel
+		device, err := devices.WithContext(ctx).
+			Clauses(clause.Locking{Strength: rowLockStrengthUpdate}).
+			Where(devices.ID.Eq(id)).
+			Take()
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+
+			return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+		}
+
+		strikes = 1
+		firstAt := at
+		if device.InvalidTokenFirstAt != nil && at.Sub(*device.InvalidTokenFirstAt) <= window {
+			strikes = device.InvalidTokenStrikes + 1
+			firstAt = *device.InvalidTokenFirstAt
+		}
+
+		if _, err := devices.WithContext(ctx).
+			Where(devices.ID.Eq(id)).
+			UpdateSimple(
+				devices.InvalidTokenStrikes.Value(strikes),
+				devices.InvalidTokenFirstAt.Value(firstAt),
+			); err != nil {
+			return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return strikes, nil
+}
+
+// ResetInvalidTokenStrikes clears the invalid-token strikes of the given devices.
+// Devices without strikes are left untouched so a successful send does not rewrite every row.
+func (repo *deviceRepository) ResetInvalidTokenStrikes(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if _, err := repo.q.UserDeviceModel.WithContext(ctx).
+		Where(
+			repo.q.UserDeviceModel.ID.In(uuidToDriverValues(ids)...),
+			repo.q.UserDeviceModel.InvalidTokenStrikes.Gt(0),
+		).
+		UpdateSimple(
+			repo.q.UserDeviceModel.InvalidTokenStrikes.Value(0),
+			repo.q.UserDeviceModel.InvalidTokenFirstAt.Null(),
+		); err != nil {
+		return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	return nil
+}
+
+// RecordDeliverySuccess stamps the given devices as last delivered to at.
+func (repo *deviceRepository) RecordDeliverySuccess(ctx context.Context, ids []uuid.UUID, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if _, err := repo.q.UserDeviceModel.WithContext(ctx).
+		Where(repo.q.UserDeviceModel.ID.In(uuidToDriverValues(ids)...)).
+		UpdateSimple(repo.q.UserDeviceModel.LastSuccessAt.Value(at)); err != nil {
+		return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	return nil
+}
+
+// FindStaleDevices retrieves up to limit live devices, after afterID in ID order, whose last successful
+// delivery predates olderThan. Devices never delivered to are measured from their creation.
+func (repo *deviceRepository) FindStaleDevices(ctx context.Context, olderThan time.Time, afterID uuid.UUID, limit int) ([]*entity.UserDevice, error) {
+	query := repo.q.UserDeviceModel.WithContext(ctx).
+		Where(field.NewUnsafeFieldRaw("COALESCE(user_devices.last_success_at, user_devices.created_at) < ?", olderThan))
+	if afterID != uuid.Nil {
+		query = query.Where(repo.q.UserDeviceModel.ID.Gt(afterID))
+	}
+
+	devicesM, err := query.
+		Order(repo.q.UserDeviceModel.ID).
+		Limit(limit).
+		Find()
+	if err != nil {
+		return nil, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	devices := make([]*entity.UserDevice, 0, len(devicesM))
+	for _, deviceM := range devicesM {
+		devices = append(devices, toDeviceDomain(deviceM))
+	}
+
+	return devices, nil
+}
+
+// DeleteDevices removes the devices with the given IDs (soft delete) and returns how many were deleted.
+// Devices already deleted are not counted.
+func (repo *deviceRepository) DeleteDevices(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result, err := repo.q.UserDeviceModel.WithContext(ctx).
+		Where(repo.q.UserDeviceModel.ID.In(uuidToDriverValues(ids)...)).
+		Delete()
+	if err != nil {
+		return 0, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	return result.RowsAffected, nil
+}
+
 // DeleteDevice removes a device by its ID (soft delete).
+// Deleting a missing or already soft-deleted device is treated as success so
+// concurrent invalid-token cleanups do not race into spurious failures.
 func (repo *deviceRepository) DeleteDevice(ctx context.Context, id uuid.UUID) error {
-	result, err := repo.q.UserDeviceModel.WithContext(ctx).
+	_, err := repo.q.UserDeviceModel.WithContext(ctx).
 		Where(repo.q.UserDeviceModel.ID.Eq(id)).
 		Delete()
 
@@ -278,10 +475,6 @@ func (repo *deviceRepository) DeleteDevice(ctx context.Context, id uuid.UUID) er
 		return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
 	}
 
-	if result.RowsAffected == 0 {
-		return domainerrors.ErrDeviceNotFound
-	}
-
 	return nil
 }
 
@@ -299,10 +492,16 @@ func toDeviceDomain(data *model.UserDeviceModel) *entity.UserDevice {
 		FCMToken:         data.FCMToken,
 		DeviceID:         data.DeviceID,
 		Platform:         data.Platform,
+		AppVersion:       data.AppVersion,
 		IsActive:         data.IsActive,
 		TokenRefreshedAt: data.TokenRefreshedAt,
 		CreatedAt:        data.CreatedAt,
 		UpdatedAt:        data.UpdatedAt,
+		DeviceNotificationSettings: entity.DeviceNotificationSettings{
+			NotificationsEnabled: data.NotificationsEnabled,
+			QuietHoursStart:      data.QuietHoursStart,
+			QuietHoursEnd:        data.QuietHoursEnd,
+		},
 	}
 }
 
@@ -318,9 +517,14 @@ func fromDeviceDomain(data *entity.UserDevice) *model.UserDeviceModel {
 		FCMToken:         data.FCMToken,
 		DeviceID:         data.DeviceID,
 		Platform:         data.Platform,
+		AppVersion:       data.AppVersion,
 		IsActive:         data.IsActive,
 		TokenRefreshedAt: data.TokenRefreshedAt,
 		CreatedAt:        data.CreatedAt,
 		UpdatedAt:        data.UpdatedAt,
+
+		NotificationsEnabled: data.NotificationsEnabled,
+		QuietHoursStart:      data.QuietHoursStart,
+		QuietHoursEnd:        data.QuietHoursEnd,
 	}
 }
@@ -0,0 +1,257 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
+	"radar/internal/infra/persistence/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestDeviceRepository_DeleteDevice_NoAffectedRowsIsSuccess(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	require.NoError(t, err)
+
+	// Dry-run statements affect no rows, which matches deleting an already-deleted device.
+	repo := NewDeviceRepository(db)
+
+	require.NoError(t, repo.DeleteDevice(context.Background(), uuid.New()))
+}
+
+func TestDeviceRepository_ResetInvalidTokenStrikes_OnlyTouchesStruckDevices(t *testing.T) {
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	repo := NewDeviceRepository(db)
+
+	require.NoError(t, repo.ResetInvalidTokenStrikes(context.Background(), nil))
+	require.Empty(t, sqlLogger.queries, "no devices means no statement")
+
+	require.NoError(t, repo.ResetInvalidTokenStrikes(context.Background(), []uuid.UUID{uuid.New(), uuid.New()}))
+	require.Len(t, sqlLogger.queries, 1)
+
+	sql := strings.ReplaceAll(sqlLogger.queries[0], `"`, "")
+	assert.Contains(t, sql, "invalid_token_strikes=0")
+	assert.Contains(t, sql, "invalid_token_first_at=NULL")
+	assert.Contains(t, sql, "invalid_token_strikes > 0")
+	assert.Contains(t, sql, "deleted_at IS NULL")
+}
+
+func TestDeviceRepository_FindStaleDevices_SelectsLiveDevicesPastCutoff(t *testing.T) {
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	repo := NewDeviceRepository(db)
+
+	cutoff := time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
+	devices, err := repo.FindStaleDevices(context.Background(), cutoff, uuid.Nil, 100)
+	require.NoError(t, err)
+	assert.Empty(t, devices)
+
+	afterID := uuid.MustParse("5f0c8a4e-7d3b-4a7e-9c1f-2b6d8e4a1c90")
+	_, err = repo.FindStaleDevices(context.Background(), cutoff, afterID, 100)
+	require.NoError(t, err)
+	require.Len(t, sqlLogger.queries, 2)
+
+	first := strings.ReplaceAll(sqlLogger.queries[0], `"`, "")
+	// Devices never delivered to are measured from when they were registered
+	assert.Contains(t, first, "COALESCE(user_devices.last_success_at, user_devices.created_at) < '2026-08-01 00:00:00")
+	assert.Contains(t, first, "deleted_at IS NULL")
+	assert.Contains(t, first, "ORDER BY user_devices.id LIMIT 100")
+	assert.NotContains(t, first, "user_devices.id >")
+
+	next := strings.ReplaceAll(sqlLogger.queries[1], `"`, "")
+	assert.Contains(t, next, "user_devices.id > '"+afterID.String()+"'")
+}
+
+func TestDeviceRepository_RecordDeliverySuccess_StampsDevices(t *testing.T) {
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	repo := NewDeviceRepository(db)
+	at := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, repo.RecordDeliverySuccess(context.Background(), nil, at))
+	require.Empty(t, sqlLogger.queries, "no devices means no statement")
+
+	require.NoError(t, repo.RecordDeliverySuccess(context.Background(), []uuid.UUID{uuid.New()}, at))
+	require.Len(t, sqlLogger.queries, 1)
+
+	sql := strings.ReplaceAll(sqlLogger.queries[0], `"`, "")
+	assert.Contains(t, sql, "last_success_at='2026-10-14 12:00:00")
+	assert.Contains(t, sql, "deleted_at IS NULL")
+}
+
+func TestDeviceRepository_UpdateDeviceNotificationSettings_ReplacesOverrides(t *testing.T) {
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	repo := NewDeviceRepository(db)
+	disabled := false
+	start, end := 22*60, 7*60
+
+	// Dry-run statements affect no rows, which is reported as a missing device
+	err = repo.UpdateDeviceNotificationSettings(context.Background(), uuid.New(), entity.DeviceNotificationSettings{NotificationsEnabled: &disabled})
+	require.ErrorIs(t, err, domainerrors.ErrDeviceNotFound)
+	err = repo.UpdateDeviceNotificationSettings(context.Background(), uuid.New(), entity.DeviceNotificationSettings{QuietHoursStart: &start, QuietHoursEnd: &end})
+	require.ErrorIs(t, err, domainerrors.ErrDeviceNotFound)
+	require.Len(t, sqlLogger.queries, 2)
+
+	// Omitted settings are cleared so the device inherits them again
+	sql := strings.ReplaceAll(sqlLogger.queries[0], `"`, "")
+	assert.Contains(t, sql, "notifications_enabled=false")
+	assert.Contains(t, sql, "quiet_hours_start=NULL")
+	assert.Contains(t, sql, "quiet_hours_end=NULL")
+	assert.Contains(t, sql, "deleted_at IS NULL")
+
+	sql = strings.ReplaceAll(sqlLogger.queries[1], `"`, "")
+	assert.Contains(t, sql, "notifications_enabled=NULL")
+	assert.Contains(t, sql, "quiet_hours_start=1320")
+	assert.Contains(t, sql, "quiet_hours_end=420")
+}
+
+func TestDeviceRepository_DeleteDevices_SoftDeletesBatch(t *testing.T) {
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	repo := NewDeviceRepository(db)
+
+	deleted, err := repo.DeleteDevices(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+	require.Empty(t, sqlLogger.queries, "no devices means no statement")
+
+	_, err = repo.DeleteDevices(context.Background(), []uuid.UUID{uuid.New(), uuid.New()})
+	require.NoError(t, err)
+	require.Len(t, sqlLogger.queries, 1)
+
+	sql := strings.ReplaceAll(sqlLogger.queries[0], `"`, "")
+	assert.Contains(t, sql, "UPDATE user_devices SET deleted_at=")
+	assert.Contains(t, sql, "user_devices.id IN (")
+}
+
+func TestDeviceRepository_CreateDevice_UpsertsOnUserAndToken(t *testing.T) {
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	repo := NewDeviceRepository(db)
+	userID := uuid.New()
+	firstAt := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	secondAt := firstAt.Add(24 * time.Hour)
+
+	// The app registers the same token again on its next launch, under a new client device ID
+	for i, at := range []time.Time{firstAt, secondAt} {
+		require.NoError(t, repo.CreateDevice(context.Background(), &entity.UserDevice{
+			ID:               uuid.New(),
+			UserID:           userID,
+			FCMToken:         "token-1",
+			DeviceID:         fmt.Sprintf("install-%d", i),
+			Platform:         "ios",
+			IsActive:         true,
+			TokenRefreshedAt: at,
+			CreatedAt:        at,
+			UpdatedAt:        at,
+		}))
+	}
+	require.Len(t, sqlLogger.queries, 2)
+
+	for i, at := range []string{"2026-10-01 08:00:00", "2026-10-02 08:00:00"} {
+		sql := strings.Join(strings.Fields(strings.ReplaceAll(sqlLogger.queries[i], `"`, "")), " ")
+		// A live row for the user's token is updated in place rather than duplicated
+		assert.Contains(t, sql, "ON CONFLICT (user_id,fcm_token) WHERE deleted_at IS NULL DO UPDATE SET")
+		assert.Contains(t, sql, "device_id=excluded.device_id")
+		assert.Contains(t, sql, "token_refreshed_at=excluded.token_refreshed_at")
+		assert.Contains(t, sql, "updated_at=excluded.updated_at")
+		assert.NotContains(t, sql, "created_at=excluded.created_at")
+		assert.Contains(t, sql, "RETURNING *")
+		assert.Contains(t, sql, fmt.Sprintf("'install-%d'", i))
+		assert.Contains(t, sql, at)
+	}
+}
+
+// TestDeviceRepository_CreateDevice_RegisteringTwiceKeepsOneRow runs against a migrated database named by
+// POSTGRES_TEST_DSN, inside a transaction that is rolled back.
+func TestDeviceRepository_CreateDevice_RegisteringTwiceKeepsOneRow(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("Skipping device registration test: POSTGRES_TEST_DSN env var not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	tx := db.Begin()
+	require.NoError(t, tx.Error)
+	t.Cleanup(func() { tx.Rollback() })
+
+	userID := uuid.New()
+	require.NoError(t, tx.Exec("INSERT INTO users (id, email) VALUES (?, ?)", userID, userID.String()+"@example.test").Error)
+
+	ctx := context.Background()
+	repo := NewDeviceRepository(tx)
+	firstAt := time.Now().UTC().Add(-24 * time.Hour).Truncate(time.Microsecond)
+	secondAt := firstAt.Add(24 * time.Hour)
+
+	first := &entity.UserDevice{
+		ID: uuid.New(), UserID: userID, FCMToken: "token-1", DeviceID: "install-1", Platform: "ios", IsActive: true,
+		TokenRefreshedAt: firstAt, CreatedAt: firstAt, UpdatedAt: firstAt,
+	}
+	require.NoError(t, repo.CreateDevice(ctx, first))
+
+	// The app registers the same token again under a new client device ID
+	second := &entity.UserDevice{
+		ID: uuid.New(), UserID: userID, FCMToken: "token-1", DeviceID: "install-2", Platform: "ios", IsActive: true,
+		TokenRefreshedAt: secondAt, CreatedAt: secondAt, UpdatedAt: secondAt,
+	}
+	require.NoError(t, repo.CreateDevice(ctx, second))
+
+	var rows []model.UserDeviceModel
+	require.NoError(t, tx.Where("user_id = ? AND deleted_at IS NULL", userID).Find(&rows).Error)
+	require.Len(t, rows, 1)
+	assert.Equal(t, first.ID, rows[0].ID)
+	assert.Equal(t, "install-2", rows[0].DeviceID)
+	assert.True(t, secondAt.Equal(rows[0].TokenRefreshedAt))
+	assert.True(t, secondAt.Equal(rows[0].UpdatedAt))
+	assert.True(t, firstAt.Equal(rows[0].CreatedAt))
+
+	// The returned entity describes the refreshed row
+	assert.Equal(t, first.ID, second.ID)
+	assert.True(t, firstAt.Equal(second.CreatedAt))
+}
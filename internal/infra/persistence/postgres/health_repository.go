@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+
+	domainerrors "radar/internal/domain/errors"
+	"radar/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// healthRepository implements the repository.HealthRepository interface.
+type healthRepository struct {
+	db *gorm.DB
+}
+
+// NewHealthRepository is the constructor for healthRepository.
+func NewHealthRepository(db *gorm.DB) repository.HealthRepository {
+	return &healthRepository{db: db}
+}
+
+// Ping checks that the connection pool can reach PostgreSQL.
+func (repo *healthRepository) Ping(ctx context.Context) error {
+	sqlDB, err := repo.db.DB()
+	if err != nil {
+		return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
+	"radar/internal/domain/repository"
+	"radar/internal/infra/persistence/model"
+	"radar/internal/infra/persistence/postgres/query"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// merchantSettingsRepository implements the repository.MerchantSettingsRepository interface.
+type merchantSettingsRepository struct {
+	q *query.Query
+}
+
+// NewMerchantSettingsRepository is the constructor for merchantSettingsRepository.
+func NewMerchantSettingsRepository(db *gorm.DB) repository.MerchantSettingsRepository {
+	return &merchantSettingsRepository{
+		q: query.Use(db),
+	}
+}
+
+// FindMerchantSettings retrieves the settings row for a merchant.
+func (repo *merchantSettingsRepository) FindMerchantSettings(ctx context.Context, merchantID uuid.UUID) (*entity.MerchantSettings, error) {
+	settingsM, err := repo.q.MerchantSettingsModel.WithContext(ctx).
+		Where(repo.q.MerchantSettingsModel.MerchantID.Eq(merchantID)).
+		First()
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, replaceWithSourceStack(err, domainerrors.ErrMerchantSettingsNotFound)
+		}
+
+		return nil, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	return toMerchantSettingsDomain(settingsM), nil
+}
+
+// UpsertMerchantSettings creates or replaces the settings row for a merchant.
+func (repo *merchantSettingsRepository) UpsertMerchantSettings(ctx context.Context, settings *entity.MerchantSettings) error {
+	settingsM := fromMerchantSettingsDomain(settings)
+
+	err := repo.q.MerchantSettingsModel.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "merchant_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"max_notification_radius",
+				"broadcasts_per_hour",
+				"distance_unit",
+				"rotate_notification_templates",
+				"updated_at",
+			}),
+		}).
+		Create(settingsM)
+	if err != nil {
+		if isForeignKeyConstraintViolation(err) {
+			return replaceWithSourceStack(err, domainerrors.ErrMerchantNotFound)
+		}
+
+		return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	settings.UpdatedAt = settingsM.UpdatedAt
+
+	return nil
+}
+
+// --- Mapper Functions ---
+
+// toMerchantSettingsDomain converts a GORM MerchantSettingsModel to a domain MerchantSettings entity.
+func toMerchantSettingsDomain(data *model.MerchantSettingsModel) *entity.MerchantSettings {
+	if data == nil {
+		return nil
+	}
+
+	return &entity.MerchantSettings{
+		MerchantID:                  data.MerchantID,
+		MaxNotificationRadius:       data.MaxNotificationRadius,
+		BroadcastsPerHour:           data.BroadcastsPerHour,
+		DistanceUnit:                data.DistanceUnit,
+		RotateNotificationTemplates: data.RotateNotificationTemplates,
+		CreatedAt:                   data.CreatedAt,
+		UpdatedAt:                   data.UpdatedAt,
+	}
+}
+
+// fromMerchantSettingsDomain converts a domain MerchantSettings entity to a GORM MerchantSettingsModel.
+func fromMerchantSettingsDomain(data *entity.MerchantSettings) *model.MerchantSettingsModel {
+	if data == nil {
+		return nil
+	}
+
+	return &model.MerchantSettingsModel{
+		MerchantID:                  data.MerchantID,
+		MaxNotificationRadius:       data.MaxNotificationRadius,
+		BroadcastsPerHour:           data.BroadcastsPerHour,
+		DistanceUnit:                data.DistanceUnit,
+		RotateNotificationTemplates: data.RotateNotificationTemplates,
+		CreatedAt:                   data.CreatedAt,
+		UpdatedAt:                   data.UpdatedAt,
+	}
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+
+	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
+	"radar/internal/domain/repository"
+	"radar/internal/infra/persistence/model"
+	"radar/internal/infra/persistence/postgres/query"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// notificationPreferenceRepository implements the repository.NotificationPreferenceRepository interface.
+type notificationPreferenceRepository struct {
+	q *query.Query
+}
+
+// NewNotificationPreferenceRepository is the constructor for notificationPreferenceRepository.
+func NewNotificationPreferenceRepository(db *gorm.DB) repository.NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{
+		q: query.Use(db),
+	}
+}
+
+// FindNotificationPreferences retrieves the saved preferences of the given users.
+func (repo *notificationPreferenceRepository) FindNotificationPreferences(ctx context.Context, userIDs []uuid.UUID) ([]*entity.NotificationPreference, error) {
+	if len(userIDs) == 0 {
+		return []*entity.NotificationPreference{}, nil
+	}
+
+	preferenceMs, err := repo.q.NotificationPreferenceModel.WithContext(ctx).
+		Where(repo.q.NotificationPreferenceModel.UserID.In(uuidToDriverValues(userIDs)...)).
+		Find()
+	if err != nil {
+		return nil, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	preferences := make([]*entity.NotificationPreference, len(preferenceMs))
+	for idx, preferenceM := range preferenceMs {
+		preferences[idx] = toNotificationPreferenceDomain(preferenceM)
+	}
+
+	return preferences, nil
+}
+
+// FindMerchantCategoryID retrieves the discovery category a merchant's broadcasts are filed under.
+// A merchant without a profile is treated as uncategorized.
+func (repo *notificationPreferenceRepository) FindMerchantCategoryID(ctx context.Context, merchantID uuid.UUID) (*uuid.UUID, error) {
+	merchantProfile := repo.q.MerchantProfileModel
+	profileMs, err := merchantProfile.WithContext(ctx).
+		Select(merchantProfile.DiscoveryCategoryID).
+		Where(merchantProfile.UserID.Eq(merchantID)).
+		Limit(1).
+		Find()
+	if err != nil {
+		return nil, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+	if len(profileMs) == 0 {
+		return nil, nil
+	}
+
+	return profileMs[0].DiscoveryCategoryID, nil
+}
+
+// --- Mapper Functions ---
+
+// toNotificationPreferenceDomain converts a GORM NotificationPreferenceModel to a domain NotificationPreference entity.
+func toNotificationPreferenceDomain(data *model.NotificationPreferenceModel) *entity.NotificationPreference {
+	if data == nil {
+		return nil
+	}
+
+	return &entity.NotificationPreference{
+		UserID:              data.UserID,
+		Timezone:            data.Timezone,
+		QuietHoursStart:     data.QuietHoursStart,
+		QuietHoursEnd:       data.QuietHoursEnd,
+		OptedOutCategoryIDs: data.OptedOutCategoryIDs,
+		CreatedAt:           data.CreatedAt,
+		UpdatedAt:           data.UpdatedAt,
+	}
+}
@@ -3,22 +3,55 @@ package postgres
 import (
 	"context"
 	"errors"
+	"time"
 
 	"radar/internal/domain/entity"
 	domainerrors "radar/internal/domain/errors"
+	"radar/internal/domain/policy"
 	"radar/internal/domain/repository"
 	"radar/internal/infra/persistence/model"
 	"radar/internal/infra/persistence/postgres/query"
 
 	"github.com/google/uuid"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// notificationLogPurgeBatchSize bounds how many log rows a single purge statement deletes.
+const notificationLogPurgeBatchSize = 5000
+
 // notificationRepository implements the repository.NotificationRepository interface.
 type notificationRepository struct {
 	q *query.Query
 }
 
+// receivedNotificationModel is a notification log row joined with its notification and merchant store name.
+type receivedNotificationModel struct {
+	LogID          uuid.UUID  `gorm:"column:log_id"`
+	NotificationID uuid.UUID  `gorm:"column:notification_id"`
+	DeviceID       uuid.UUID  `gorm:"column:device_id"`
+	Status         string     `gorm:"column:status"`
+	SentAt         time.Time  `gorm:"column:sent_at"`
+	OpenedAt       *time.Time `gorm:"column:opened_at"`
+	MerchantID     uuid.UUID  `gorm:"column:merchant_id"`
+	StoreName      string     `gorm:"column:store_name"`
+	LocationName   string     `gorm:"column:location_name"`
+	FullAddress    string     `gorm:"column:full_address"`
+	Latitude       float64    `gorm:"column:latitude"`
+	Longitude      float64    `gorm:"column:longitude"`
+	HintMessage    string     `gorm:"column:hint_message"`
+	PublishedAt    time.Time  `gorm:"column:published_at"`
+}
+
+// broadcastDayStatsModel is one UTC day of a merchant's notifications aggregated by SumBroadcastsByDay.
+type broadcastDayStatsModel struct {
+	Day        time.Time `gorm:"column:day"`
+	Broadcasts int       `gorm:"column:broadcasts"`
+	Recipients int       `gorm:"column:recipients"`
+}
+
 // NewNotificationRepository is the constructor for notificationRepository.
 func NewNotificationRepository(db *gorm.DB) repository.NotificationRepository {
 	return &notificationRepository{
@@ -28,9 +61,46 @@ func NewNotificationRepository(db *gorm.DB) repository.NotificationRepository {
 
 // CreateNotification persists a new merchant location notification.
 func (repo *notificationRepository) CreateNotification(ctx context.Context, notification *entity.MerchantLocationNotification) error {
+	return createNotification(ctx, repo.q, notification)
+}
+
+// CreateNotificationOutsideCooldown persists an immediate notification unless the merchant already sent one from
+// the same location within cooldown of its publish time. The check and the insert run in one transaction holding
+// the merchant profile row lock, so concurrent broadcasts from the same merchant cannot both pass the check.
+func (repo *notificationRepository) CreateNotificationOutsideCooldown(
+	ctx context.Context,
+	notification *entity.MerchantLocationNotification,
+	cooldown time.Duration,
+) (*time.Time, error) {
+	var blockedBy *time.Time
+	err := repo.q.Transaction(func(tx *query.Query) error {
+		latest, err := lockAndFindLatestPublishedAt(ctx, tx, notification, cooldown)
+		if err != nil {
+			return err
+		}
+		if latest != nil {
+			blockedBy = latest
+
+			return nil
+		}
+
+		return createNotification(ctx, tx, notification)
+	})
+	if err != nil {
+		if _, ok := errors.AsType[domainerrors.AppError](err); ok {
+			return nil, err //nolint:wrapcheck // preserve the original classified error
+		}
+
+		return nil, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	return blockedBy, nil
+}
+
+func createNotification(ctx context.Context, q *query.Query, notification *entity.MerchantLocationNotification) error {
 	notificationM := fromNotificationDomain(notification)
 
-	if err := repo.q.MerchantLocationNotificationModel.WithContext(ctx).Create(notificationM); err != nil {
+	if err := q.MerchantLocationNotificationModel.WithContext(ctx).Create(notificationM); err != nil {
 		if isForeignKeyConstraintViolation(err) || isNotNullConstraintViolation(err) {
 			return replaceWithSourceStack(err, domainerrors.ErrNotificationCreateFailed)
 		}
@@ -110,6 +180,27 @@ func (repo *notificationRepository) UpdateNotificationStatus(ctx context.Context
 	return nil
 }
 
+// AddNotificationResults increments the total sent and failed counts for a notification.
+func (repo *notificationRepository) AddNotificationResults(ctx context.Context, id uuid.UUID, sent, failed int) error {
+	notifications := repo.q.MerchantLocationNotificationModel
+	result, err := notifications.WithContext(ctx).
+		Where(notifications.ID.Eq(id)).
+		UpdateSimple(
+			notifications.TotalSent.Add(sent),
+			notifications.TotalFailed.Add(failed),
+		)
+
+	if err != nil {
+		return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrNotificationNotFound
+	}
+
+	return nil
+}
+
 // CreateNotificationLog persists a single notification log entry.
 func (repo *notificationRepository) CreateNotificationLog(ctx context.Context, log *entity.NotificationLog) error {
 	logM := fromNotificationLogDomain(log)
@@ -159,6 +250,394 @@ func (repo *notificationRepository) BatchCreateNotificationLogs(ctx context.Cont
 	return nil
 }
 
+// FindLoggedDeviceIDs returns the distinct devices with a log for the notification.
+func (repo *notificationRepository) FindLoggedDeviceIDs(ctx context.Context, notificationID uuid.UUID) ([]uuid.UUID, error) {
+	logs := repo.q.NotificationLogModel
+	deviceIDs := make([]uuid.UUID, 0)
+	if err := logs.WithContext(ctx).
+		Distinct(logs.DeviceID).
+		Where(logs.NotificationID.Eq(notificationID)).
+		Pluck(logs.DeviceID, &deviceIDs); err != nil {
+		return nil, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	return deviceIDs, nil
+}
+
+// FindNotificationLogsByUser retrieves the user's delivery logs with their notifications, newest first.
+func (repo *notificationRepository) FindNotificationLogsByUser(
+	ctx context.Context,
+	userID uuid.UUID,
+	limit, offset int,
+) ([]*entity.ReceivedNotification, error) {
+	logs := repo.q.NotificationLogModel
+	notifications := repo.q.MerchantLocationNotificationModel
+	merchant := repo.q.MerchantProfileModel
+
+	query := logs.WithContext(ctx).
+		Select(
+			logs.ID.As("log_id"), logs.NotificationID, logs.DeviceID, logs.Status, logs.SentAt, logs.OpenedAt,
+			notifications.MerchantID, merchant.StoreName, notifications.LocationName, notifications.FullAddress,
+			notifications.Latitude, notifications.Longitude, notifications.HintMessage, notifications.PublishedAt,
+		).
+		Join(notifications, notifications.ID.EqCol(logs.NotificationID)).
+		LeftJoin(merchant, merchant.UserID.EqCol(notifications.MerchantID)).
+		Where(logs.UserID.Eq(userID)).
+		Order(logs.SentAt.Desc(), logs.ID.Desc())
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var rows []receivedNotificationModel
+	if err := query.Scan(&rows); err != nil {
+		return nil, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	received := make([]*entity.ReceivedNotification, 0, len(rows))
+	for idx := range rows {
+		received = append(received, toReceivedNotificationDomain(&rows[idx]))
+	}
+
+	return received, nil
+}
+
+// SumBroadcastsByDay aggregates the merchant's sent notifications in [from, to) by UTC day, oldest first.
+func (repo *notificationRepository) SumBroadcastsByDay(
+	ctx context.Context,
+	merchantID uuid.UUID,
+	from, to time.Time,
+) ([]*entity.BroadcastDayStats, error) {
+	notifications := repo.q.MerchantLocationNotificationModel
+	day := field.NewUnsafeFieldRaw("day")
+
+	query := notifications.WithContext(ctx).
+		Select(
+			field.NewUnsafeFieldRaw("date_trunc('day', published_at, 'UTC')").As("day"),
+			notifications.ID.Count().As("broadcasts"),
+			field.NewUnsafeFieldRaw("coalesce(sum(total_sent), 0)").As("recipients"),
+		).
+		Where(
+			notifications.MerchantID.Eq(merchantID),
+			notifications.PublishedAt.Gte(from),
+			notifications.PublishedAt.Lt(to),
+			notifications.WithContext(ctx).
+				Where(notifications.ScheduleStatus.IsNull()).
+				Or(notifications.ScheduleStatus.Eq(string(entity.ScheduleStatusDispatched))),
+		).
+		Group(day).
+		Order(day)
+
+	var rows []broadcastDayStatsModel
+	if err := query.Scan(&rows); err != nil {
+		return nil, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	stats := make([]*entity.BroadcastDayStats, 0, len(rows))
+	for idx := range rows {
+		stats = append(stats, &entity.BroadcastDayStats{
+			Day:        rows[idx].Day.UTC(),
+			Broadcasts: rows[idx].Broadcasts,
+			Recipients: rows[idx].Recipients,
+		})
+	}
+
+	return stats, nil
+}
+
+// MarkNotificationOpened stamps every sent log of the user for the notification with openedAt and,
+// when any log was still unopened, increments the notification's open count by one.
+// Concurrent opens serialize on the log rows, so only one of them increments the count.
+func (repo *notificationRepository) MarkNotificationOpened(
+	ctx context.Context,
+	notificationID, userID uuid.UUID,
+	openedAt time.Time,
+) (bool, error) {
+	opened := false
+	err := repo.q.Transaction(func(tx *query.Query) error {
+		logs := tx.NotificationLogModel
+		received := []gen.Condition{
+			logs.NotificationID.Eq(notificationID),
+			logs.UserID.Eq(userID),
+			logs.Status.Eq(policy.NotificationLogStatusSent),
+		}
+
+		result, err := logs.WithContext(ctx).
+			Where(received...).
+			Where(logs.OpenedAt.IsNull()).
+			UpdateSimple(logs.OpenedAt.Value(openedAt))
+		if err != nil {
+			return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+		}
+
+		if result.RowsAffected == 0 {
+			count, err := logs.WithContext(ctx).Where(received...).Count()
+			if err != nil {
+				return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+			}
+			if count == 0 {
+				return domainerrors.ErrNotificationNotFound
+			}
+
+			return nil
+		}
+
+		notifications := tx.MerchantLocationNotificationModel
+		if _, err := notifications.WithContext(ctx).
+			Where(notifications.ID.Eq(notificationID)).
+			UpdateSimple(notifications.TotalOpened.Add(1)); err != nil {
+			return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+		}
+		opened = true
+
+		return nil
+	})
+	if err != nil {
+		if _, ok := errors.AsType[domainerrors.AppError](err); ok {
+			return false, err //nolint:wrapcheck // preserve the original classified error
+		}
+
+		return false, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	return opened, nil
+}
+
+// PurgeOldNotificationLogs deletes log entries sent before olderThan and returns the number removed.
+// Rows are deleted in batches selected through the sent_at index so a large backlog does not hold
+// long locks; the parent notifications and their summary counts are left untouched.
+func (repo *notificationRepository) PurgeOldNotificationLogs(ctx context.Context, olderThan time.Time) (int64, error) {
+	logs := repo.q.NotificationLogModel
+
+	var total int64
+	for {
+		batch := logs.WithContext(ctx).
+			Select(logs.ID).
+			Where(logs.SentAt.Lt(olderThan)).
+			Limit(notificationLogPurgeBatchSize)
+
+		result, err := logs.WithContext(ctx).
+			Where(logs.WithContext(ctx).Columns(logs.ID).In(batch)).
+			Delete()
+		if err != nil {
+			return total, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+		}
+
+		total += result.RowsAffected
+		if result.RowsAffected < notificationLogPurgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// lockAndFindLatestPublishedAt locks the merchant's profile row and returns the publish time of the merchant's
+// most recent sent notification from the notification's location when it falls within cooldown of the
+// notification's publish time, or nil when the notification may be sent. A non-positive cooldown only takes the lock.
+func lockAndFindLatestPublishedAt(
+	ctx context.Context,
+	tx *query.Query,
+	notification *entity.MerchantLocationNotification,
+	cooldown time.Duration,
+) (*time.Time, error) {
+	merchantProfile := tx.MerchantProfileModel
+	if _, err := merchantProfile.WithContext(ctx).
+		Select(merchantProfile.UserID).
+		Clauses(clause.Locking{Strength: rowLockStrengthUpdate}).
+		Where(merchantProfile.UserID.Eq(notification.MerchantID)).
+		Take(); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, replaceWithSourceStack(err, domainerrors.ErrMerchantNotFound)
+		}
+
+		return nil, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+	if cooldown <= 0 {
+		return nil, nil
+	}
+
+	latest, err := findLatestPublishedAt(
+		ctx, tx, notification.MerchantID, notification.AddressID, notification.Latitude, notification.Longitude,
+	)
+	if err != nil || latest == nil {
+		return nil, err
+	}
+	if !latest.After(notification.PublishedAt.Add(-cooldown)) {
+		return nil, nil
+	}
+
+	return latest, nil
+}
+
+// findLatestPublishedAt returns the publish time of the merchant's most recent sent notification from a location.
+// A saved address matches by addressID; otherwise the exact coordinates of an ad-hoc location match. Pending and
+// canceled scheduled notifications were never sent, so only immediate and dispatched ones count.
+func findLatestPublishedAt(
+	ctx context.Context,
+	q *query.Query,
+	merchantID uuid.UUID,
+	addressID *uuid.UUID,
+	latitude, longitude float64,
+) (*time.Time, error) {
+	notifications := q.MerchantLocationNotificationModel
+	query := notifications.WithContext(ctx).
+		Where(
+			notifications.MerchantID.Eq(merchantID),
+			notifications.WithContext(ctx).
+				Where(notifications.ScheduleStatus.IsNull()).
+				Or(notifications.ScheduleStatus.Eq(string(entity.ScheduleStatusDispatched))),
+		)
+	if addressID != nil {
+		query = query.Where(notifications.AddressID.Eq(*addressID))
+	} else {
+		query = query.Where(
+			notifications.AddressID.IsNull(),
+			notifications.Latitude.Eq(latitude),
+			notifications.Longitude.Eq(longitude),
+		)
+	}
+
+	notificationM, err := query.Order(notifications.PublishedAt.Desc()).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	return &notificationM.PublishedAt, nil
+}
+
+// FindDueScheduledNotifications retrieves pending scheduled notifications due at or before dueBy, and those
+// whose claim was taken before claimedBefore, oldest first.
+func (repo *notificationRepository) FindDueScheduledNotifications(
+	ctx context.Context,
+	dueBy, claimedBefore time.Time,
+	limit int,
+) ([]*entity.MerchantLocationNotification, error) {
+	notifications := repo.q.MerchantLocationNotificationModel
+	query := notifications.WithContext(ctx).
+		Where(
+			notifications.ScheduleStatus.Eq(string(entity.ScheduleStatusPending)),
+			notifications.ScheduledAt.Lte(dueBy),
+		).
+		Or(
+			notifications.ScheduleStatus.Eq(string(entity.ScheduleStatusClaimed)),
+			notifications.ClaimedAt.Lt(claimedBefore),
+		).
+		Order(notifications.ScheduledAt)
+
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	notificationModels, err := query.Find()
+	if err != nil {
+		return nil, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	due := make([]*entity.MerchantLocationNotification, 0, len(notificationModels))
+	for _, notificationM := range notificationModels {
+		due = append(due, toNotificationDomain(notificationM))
+	}
+
+	return due, nil
+}
+
+// ClaimScheduledNotification moves a due notification from pending, or from an expired claim, to claimed, or to
+// canceled when the merchant already sent one from the same location within cooldown of its publish time. It runs
+// in one transaction holding the merchant profile row lock, the same lock immediate broadcasts take for their
+// cooldown check.
+func (repo *notificationRepository) ClaimScheduledNotification(
+	ctx context.Context,
+	notification *entity.MerchantLocationNotification,
+	cooldown time.Duration,
+	claimedBefore time.Time,
+) (repository.ScheduleClaim, error) {
+	claim := repository.ScheduleClaimLost
+	err := repo.q.Transaction(func(tx *query.Query) error {
+		latest, err := lockAndFindLatestPublishedAt(ctx, tx, notification, cooldown)
+		if err != nil {
+			return err
+		}
+
+		to, outcome := entity.ScheduleStatusClaimed, repository.ScheduleClaimed
+		if latest != nil {
+			to, outcome = entity.ScheduleStatusCanceled, repository.ScheduleClaimInCooldown
+		}
+
+		result, err := claimScheduledNotification(ctx, tx, notification.ID, to, claimedBefore)
+		if err != nil {
+			return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+		}
+		if result.RowsAffected > 0 {
+			claim = outcome
+		}
+
+		return nil
+	})
+	if err != nil {
+		if _, ok := errors.AsType[domainerrors.AppError](err); ok {
+			return repository.ScheduleClaimLost, err //nolint:wrapcheck // preserve the original classified error
+		}
+
+		return repository.ScheduleClaimLost, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	return claim, nil
+}
+
+// claimScheduledNotification moves the notification to status to, stamping the claim time, while it is pending
+// or its claim was taken before claimedBefore
+func claimScheduledNotification(
+	ctx context.Context,
+	q *query.Query,
+	id uuid.UUID,
+	to entity.ScheduleStatus,
+	claimedBefore time.Time,
+) (gen.ResultInfo, error) {
+	notifications := q.MerchantLocationNotificationModel
+
+	return notifications.WithContext(ctx).
+		Where(
+			notifications.ID.Eq(id),
+			notifications.WithContext(ctx).
+				Where(notifications.ScheduleStatus.Eq(string(entity.ScheduleStatusPending))).
+				Or(
+					notifications.ScheduleStatus.Eq(string(entity.ScheduleStatusClaimed)),
+					notifications.ClaimedAt.Lt(claimedBefore),
+				),
+		).
+		UpdateSimple(
+			notifications.ScheduleStatus.Value(string(to)),
+			notifications.ClaimedAt.Value(time.Now()),
+		)
+}
+
+// UpdateScheduleStatus moves a scheduled notification from one status to another in a single conditional
+// update, so concurrent dispatches and cancellations cannot both win.
+func (repo *notificationRepository) UpdateScheduleStatus(
+	ctx context.Context,
+	id uuid.UUID,
+	from, to entity.ScheduleStatus,
+) (bool, error) {
+	notifications := repo.q.MerchantLocationNotificationModel
+	result, err := notifications.WithContext(ctx).
+		Where(
+			notifications.ID.Eq(id),
+			notifications.ScheduleStatus.Eq(string(from)),
+		).
+		UpdateSimple(notifications.ScheduleStatus.Value(string(to)))
+	if err != nil {
+		return false, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
 // --- Mapper Functions ---
 
 // toNotificationDomain converts a GORM MerchantLocationNotificationModel to a domain MerchantLocationNotification entity.
@@ -178,9 +657,13 @@ func toNotificationDomain(data *model.MerchantLocationNotificationModel) *entity
 		HintMessage:  data.HintMessage,
 		TotalSent:    data.TotalSent,
 		TotalFailed:  data.TotalFailed,
+		TotalOpened:  data.TotalOpened,
 		PublishedAt:  data.PublishedAt,
 		CreatedAt:    data.CreatedAt,
 		UpdatedAt:    data.UpdatedAt,
+
+		ScheduledAt:    data.ScheduledAt,
+		ScheduleStatus: scheduleStatusFromColumn(data.ScheduleStatus),
 	}
 }
 
@@ -201,10 +684,34 @@ func fromNotificationDomain(data *entity.MerchantLocationNotification) *model.Me
 		HintMessage:  data.HintMessage,
 		TotalSent:    data.TotalSent,
 		TotalFailed:  data.TotalFailed,
+		TotalOpened:  data.TotalOpened,
 		PublishedAt:  data.PublishedAt,
 		CreatedAt:    data.CreatedAt,
 		UpdatedAt:    data.UpdatedAt,
+
+		ScheduledAt:    data.ScheduledAt,
+		ScheduleStatus: scheduleStatusColumn(data.ScheduleStatus),
+	}
+}
+
+// scheduleStatusFromColumn maps a NULL status to the empty status of a notification that was never scheduled.
+func scheduleStatusFromColumn(status *string) entity.ScheduleStatus {
+	if status == nil {
+		return ""
+	}
+
+	return entity.ScheduleStatus(*status)
+}
+
+// scheduleStatusColumn stores an empty status as NULL, which marks a notification that was never scheduled.
+func scheduleStatusColumn(status entity.ScheduleStatus) *string {
+	if status == "" {
+		return nil
 	}
+
+	value := string(status)
+
+	return &value
 }
 
 // fromNotificationLogDomain converts a domain NotificationLog entity to a GORM NotificationLogModel.
@@ -222,5 +729,26 @@ func fromNotificationLogDomain(data *entity.NotificationLog) *model.Notification
 		FCMMessageID:   data.FCMMessageID,
 		ErrorMessage:   data.ErrorMessage,
 		SentAt:         data.SentAt,
+		OpenedAt:       data.OpenedAt,
+	}
+}
+
+// toReceivedNotificationDomain converts a joined notification log row to a domain ReceivedNotification entity.
+func toReceivedNotificationDomain(data *receivedNotificationModel) *entity.ReceivedNotification {
+	return &entity.ReceivedNotification{
+		LogID:          data.LogID,
+		NotificationID: data.NotificationID,
+		DeviceID:       data.DeviceID,
+		Status:         data.Status,
+		SentAt:         data.SentAt,
+		OpenedAt:       data.OpenedAt,
+		MerchantID:     data.MerchantID,
+		StoreName:      data.StoreName,
+		LocationName:   data.LocationName,
+		FullAddress:    data.FullAddress,
+		Latitude:       data.Latitude,
+		Longitude:      data.Longitude,
+		HintMessage:    data.HintMessage,
+		PublishedAt:    data.PublishedAt,
 	}
 }
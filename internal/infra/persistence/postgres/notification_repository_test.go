@@ -0,0 +1,310 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
+	"radar/internal/infra/persistence/postgres/query"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestNotificationRepository_PurgeOldNotificationLogs_OnlyDeletesLogsBeforeCutoff(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	require.NoError(t, err)
+
+	var statements []string
+	var vars [][]any
+	require.NoError(t, db.Callback().Delete().After("gorm:delete").Register("test:capture", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+		vars = append(vars, tx.Statement.Vars)
+	}))
+
+	cutoff := time.Date(2026, 7, 16, 3, 0, 0, 0, time.UTC)
+	repo := NewNotificationRepository(db)
+
+	// Dry-run statements affect no rows, so the batch loop stops after one statement.
+	purged, err := repo.PurgeOldNotificationLogs(context.Background(), cutoff)
+
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+	require.Len(t, statements, 1)
+	assert.Contains(t, statements[0], `DELETE FROM "notification_logs"`)
+	assert.Contains(t, statements[0], `"sent_at" < `, "logs sent exactly at the cutoff must be kept")
+	assert.Contains(t, vars[0], cutoff)
+
+	// Summary counts live on merchant_location_notifications, which the purge never touches
+	assert.NotContains(t, statements[0], "merchant_location_notifications")
+}
+
+func TestFindLatestPublishedAt_MatchesLocation(t *testing.T) {
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	q := query.Use(db)
+	addressID := uuid.New()
+
+	_, err = findLatestPublishedAt(context.Background(), q, uuid.New(), &addressID, 25.0, 121.0)
+	require.NoError(t, err)
+	_, err = findLatestPublishedAt(context.Background(), q, uuid.New(), nil, 25.0, 121.0)
+	require.NoError(t, err)
+
+	require.Len(t, sqlLogger.queries, 2)
+	queries := make([]string, len(sqlLogger.queries))
+	for idx, query := range sqlLogger.queries {
+		queries[idx] = strings.ReplaceAll(strings.ReplaceAll(query, `"`, ""), "merchant_location_notifications.", "")
+		// Pending and canceled scheduled notifications were never sent, so they do not start a cooldown
+		assert.Contains(t, queries[idx], "(schedule_status IS NULL OR schedule_status = 'dispatched')")
+		assert.Contains(t, queries[idx], "ORDER BY published_at DESC")
+	}
+	assert.Contains(t, queries[0], "address_id = '"+addressID.String()+"'")
+	assert.NotContains(t, queries[0], "latitude")
+	assert.Contains(t, queries[1], "address_id IS NULL AND latitude = 25 AND longitude = 121")
+}
+
+func TestLockAndFindLatestPublishedAt_LocksMerchantBeforeLookup(t *testing.T) {
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	q := query.Use(db)
+	notification := &entity.MerchantLocationNotification{
+		MerchantID:  uuid.New(),
+		Latitude:    25.0,
+		Longitude:   121.0,
+		PublishedAt: time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC),
+	}
+
+	latest, err := lockAndFindLatestPublishedAt(context.Background(), q, notification, 15*time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, latest)
+
+	// The merchant row lock comes first, so a concurrent publish waits before it looks for the latest broadcast
+	require.Len(t, sqlLogger.queries, 2)
+	assert.Contains(t, sqlLogger.queries[0], `FROM "merchant_profiles"`)
+	assert.Contains(t, sqlLogger.queries[0], "FOR UPDATE")
+	assert.Contains(t, sqlLogger.queries[0], notification.MerchantID.String())
+	assert.Contains(t, sqlLogger.queries[1], `FROM "merchant_location_notifications"`)
+
+	// Without a cooldown only the lock is taken
+	sqlLogger.queries = nil
+	_, err = lockAndFindLatestPublishedAt(context.Background(), q, notification, 0)
+	require.NoError(t, err)
+	require.Len(t, sqlLogger.queries, 1)
+	assert.Contains(t, sqlLogger.queries[0], "FOR UPDATE")
+}
+
+func TestNotificationRepository_FindDueScheduledNotifications_IncludesExpiredClaims(t *testing.T) {
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	repo := NewNotificationRepository(db)
+	dueBy := time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC)
+
+	_, _ = repo.FindDueScheduledNotifications(context.Background(), dueBy, dueBy.Add(-15*time.Minute), 100)
+
+	require.Len(t, sqlLogger.queries, 1)
+	query := strings.ReplaceAll(sqlLogger.queries[0], `"`, "")
+	// Due pending rows, or claims an abandoned run took before the lease cutoff
+	assert.Contains(t, query, "WHERE merchant_location_notifications.schedule_status = 'pending' AND merchant_location_notifications.scheduled_at <= '2026-10-14 09:30:00' "+
+		"OR (merchant_location_notifications.schedule_status = 'claimed' AND merchant_location_notifications.claimed_at < '2026-10-14 09:15:00')")
+	assert.Contains(t, query, "LIMIT 100")
+}
+
+func TestClaimScheduledNotification_ClaimsPendingOrExpiredRows(t *testing.T) {
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	id := uuid.New()
+	claimedBefore := time.Date(2026, 10, 14, 9, 15, 0, 0, time.UTC)
+
+	_, err = claimScheduledNotification(context.Background(), query.Use(db), id, entity.ScheduleStatusClaimed, claimedBefore)
+	require.NoError(t, err)
+
+	require.Len(t, sqlLogger.queries, 1)
+	statement := strings.ReplaceAll(sqlLogger.queries[0], `"`, "")
+	assert.Contains(t, statement, "SET schedule_status='claimed',claimed_at=")
+	assert.Contains(t, statement, "id = '"+id.String()+"'")
+	// A claim held by a live run is not taken over; one older than the lease is
+	assert.Contains(t, statement, "(merchant_location_notifications.schedule_status = 'pending' OR (merchant_location_notifications.schedule_status = 'claimed' AND merchant_location_notifications.claimed_at < '2026-10-14 09:15:00'))")
+}
+
+func TestNotificationRepository_FindNotificationLogsByUser_JoinsNotificationAndStore(t *testing.T) {
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	repo := NewNotificationRepository(db)
+	userID := uuid.New()
+
+	// A dry run has no rows to scan, so only the statement is checked
+	_, _ = repo.FindNotificationLogsByUser(context.Background(), userID, 20, 40)
+
+	require.Len(t, sqlLogger.queries, 1)
+	query := strings.ReplaceAll(sqlLogger.queries[0], `"`, "")
+	assert.Contains(t, query, "notification_logs.id AS log_id")
+	assert.Contains(t, query, "merchant_profiles.store_name")
+	assert.Contains(t, query, "INNER JOIN merchant_location_notifications ON merchant_location_notifications.id = notification_logs.notification_id")
+	assert.Contains(t, query, "LEFT JOIN merchant_profiles ON merchant_profiles.user_id = merchant_location_notifications.merchant_id")
+	assert.Contains(t, query, "notification_logs.user_id = '"+userID.String()+"'")
+	// Failed deliveries are listed alongside sent ones
+	assert.NotContains(t, query, "notification_logs.status =")
+	assert.Contains(t, query, "ORDER BY notification_logs.sent_at DESC,notification_logs.id DESC LIMIT 20 OFFSET 40")
+}
+
+func TestNotificationRepository_SumBroadcastsByDay_GroupsByUTCDay(t *testing.T) {
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	repo := NewNotificationRepository(db)
+	merchantID := uuid.New()
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	// A dry run has no rows to scan, so only the statement is checked
+	_, _ = repo.SumBroadcastsByDay(context.Background(), merchantID, from, from.AddDate(0, 0, 7))
+
+	require.Len(t, sqlLogger.queries, 1)
+	query := strings.ReplaceAll(sqlLogger.queries[0], `"`, "")
+	assert.Contains(t, query, "date_trunc('day', published_at, 'UTC') AS day")
+	assert.Contains(t, query, "COUNT(merchant_location_notifications.id) AS broadcasts")
+	assert.Contains(t, query, "coalesce(sum(total_sent), 0) AS recipients")
+	assert.Contains(t, query, "merchant_location_notifications.merchant_id = '"+merchantID.String()+"'")
+	assert.Contains(t, query, "merchant_location_notifications.published_at >= '2026-10-01 00:00:00'")
+	assert.Contains(t, query, "merchant_location_notifications.published_at < '2026-10-08 00:00:00'")
+	assert.Contains(t, query, "merchant_location_notifications.schedule_status IS NULL OR merchant_location_notifications.schedule_status = 'dispatched'")
+	assert.Contains(t, query, "GROUP BY day ORDER BY day")
+}
+
+// TestNotificationRepository_SumBroadcastsByDay_BucketsSentBroadcasts runs against a migrated database named by
+// POSTGRES_TEST_DSN, inside a transaction that is rolled back.
+func TestNotificationRepository_SumBroadcastsByDay_BucketsSentBroadcasts(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("Skipping broadcast stats test: POSTGRES_TEST_DSN env var not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	tx := db.Begin()
+	require.NoError(t, tx.Error)
+	t.Cleanup(func() { tx.Rollback() })
+
+	// Days are UTC whatever the session time zone; in Taipei the late broadcast below falls on the next day
+	require.NoError(t, tx.Exec("SET LOCAL TIME ZONE 'Asia/Taipei'").Error)
+
+	merchantID, otherMerchantID := uuid.New(), uuid.New()
+	for _, userID := range []uuid.UUID{merchantID, otherMerchantID} {
+		require.NoError(t, tx.Exec("INSERT INTO users (id, email) VALUES (?, ?)", userID, userID.String()+"@example.test").Error)
+	}
+
+	broadcasts := []struct {
+		merchantID     uuid.UUID
+		publishedAt    string
+		totalSent      int
+		scheduleStatus *string
+	}{
+		{merchantID, "2026-10-01 01:00:00+00", 2, nil},
+		{merchantID, "2026-10-01 23:30:00+00", 3, nil},
+		{merchantID, "2026-10-03 12:00:00+00", 5, new(string(entity.ScheduleStatusDispatched))},
+		// Scheduled broadcasts that have not gone out, and broadcasts outside the range or of another merchant
+		{merchantID, "2026-10-03 13:00:00+00", 7, new(string(entity.ScheduleStatusPending))},
+		{merchantID, "2026-10-03 14:00:00+00", 7, new(string(entity.ScheduleStatusCanceled))},
+		{merchantID, "2026-09-30 23:59:59+00", 7, nil},
+		{merchantID, "2026-10-08 00:00:00+00", 7, nil},
+		{otherMerchantID, "2026-10-01 12:00:00+00", 7, nil},
+	}
+	for _, broadcast := range broadcasts {
+		require.NoError(t, tx.Exec(
+			`INSERT INTO merchant_location_notifications
+				(merchant_id, location_name, full_address, latitude, longitude, total_sent, published_at, schedule_status)
+			VALUES (?, 'Stall', 'Taipei', 25.033, 121.5654, ?, ?, ?)`,
+			broadcast.merchantID, broadcast.totalSent, broadcast.publishedAt, broadcast.scheduleStatus,
+		).Error)
+	}
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	stats, err := NewNotificationRepository(tx).SumBroadcastsByDay(context.Background(), merchantID, from, from.AddDate(0, 0, 7))
+	require.NoError(t, err)
+
+	assert.Equal(t, []*entity.BroadcastDayStats{
+		{Day: from, Broadcasts: 2, Recipients: 5},
+		{Day: from.AddDate(0, 0, 2), Broadcasts: 1, Recipients: 5},
+	}, stats)
+}
+
+func TestNotificationRepository_AddNotificationResults_IncrementsTotals(t *testing.T) {
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	repo := NewNotificationRepository(db)
+	id := uuid.New()
+
+	// A dry run affects no rows, which reads as a missing notification
+	err = repo.AddNotificationResults(context.Background(), id, 3, 1)
+	require.ErrorIs(t, err, domainerrors.ErrNotificationNotFound)
+
+	require.Len(t, sqlLogger.queries, 1)
+	statement := strings.ReplaceAll(sqlLogger.queries[0], `"`, "")
+	// A redelivery adds to the totals of the first attempt instead of replacing them
+	assert.Contains(t, statement, "SET total_sent=merchant_location_notifications.total_sent+3,total_failed=merchant_location_notifications.total_failed+1")
+	assert.Contains(t, statement, "id = '"+id.String()+"'")
+}
+
+func TestNotificationRepository_FindLoggedDeviceIDs_FiltersByNotification(t *testing.T) {
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	repo := NewNotificationRepository(db)
+	notificationID := uuid.New()
+
+	_, _ = repo.FindLoggedDeviceIDs(context.Background(), notificationID)
+
+	require.Len(t, sqlLogger.queries, 1)
+	query := strings.ReplaceAll(sqlLogger.queries[0], `"`, "")
+	assert.Contains(t, query, "SELECT DISTINCT notification_logs.device_id FROM notification_logs")
+	assert.Contains(t, query, "notification_logs.notification_id = '"+notificationID.String()+"'")
+	// Failed logs count too, so a permanently failed device is not retried
+	assert.NotContains(t, query, "status")
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domainerrors "radar/internal/domain/errors"
+	"radar/internal/domain/repository"
+	"radar/internal/infra/persistence/model"
+	"radar/internal/infra/persistence/postgres/query"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// messageClaimProcessing marks a message a delivery is still working on.
+	messageClaimProcessing = "processing"
+	// messageClaimProcessed marks a message whose side effects are done.
+	messageClaimProcessed = "processed"
+)
+
+type pubSubMessageRepository struct {
+	q *query.Query
+}
+
+// NewPubSubMessageRepository is the constructor for pubSubMessageRepository.
+func NewPubSubMessageRepository(db *gorm.DB) repository.PubSubMessageRepository {
+	return &pubSubMessageRepository{q: query.Use(db)}
+}
+
+// ClaimMessage inserts a processing record for the message, or takes over a record whose lease has
+// lapsed. When neither happens the existing record decides whether the message is done or in flight.
+func (repo *pubSubMessageRepository) ClaimMessage(
+	ctx context.Context,
+	messageID string,
+	now, leaseUntil time.Time,
+) (repository.MessageClaim, error) {
+	claims := repo.q.PubSubMessageClaimModel
+
+	result := claims.WithContext(ctx).UnderlyingDB().
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "message_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status", "expires_at"}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "pubsub_message_claims.expires_at <= ?", Vars: []any{now}},
+			}},
+		}).
+		Create(&model.PubSubMessageClaimModel{
+			MessageID: messageID,
+			Status:    messageClaimProcessing,
+			ExpiresAt: leaseUntil,
+		})
+	if result.Error != nil {
+		return repository.MessageInFlight, replaceWithSourceStack(result.Error, domainerrors.ErrPersistenceFailed)
+	}
+	if result.RowsAffected > 0 {
+		return repository.MessageClaimed, nil
+	}
+
+	existing, err := claims.WithContext(ctx).Where(claims.MessageID.Eq(messageID)).Take()
+	if err != nil {
+		// The record was purged between the two statements; let the next delivery claim it
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return repository.MessageInFlight, nil
+		}
+
+		return repository.MessageInFlight, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+	if existing.Status == messageClaimProcessed {
+		return repository.MessageProcessed, nil
+	}
+
+	return repository.MessageInFlight, nil
+}
+
+// CompleteMessage marks the message processed and keeps the record until expiresAt.
+func (repo *pubSubMessageRepository) CompleteMessage(ctx context.Context, messageID string, expiresAt time.Time) error {
+	claims := repo.q.PubSubMessageClaimModel
+
+	if _, err := claims.WithContext(ctx).
+		Where(claims.MessageID.Eq(messageID)).
+		UpdateSimple(
+			claims.Status.Value(messageClaimProcessed),
+			claims.ExpiresAt.Value(expiresAt),
+		); err != nil {
+		return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	return nil
+}
+
+// ReleaseMessage deletes a processing record. A processed record is kept so a late release cannot
+// reopen a message that already finished.
+func (repo *pubSubMessageRepository) ReleaseMessage(ctx context.Context, messageID string) error {
+	claims := repo.q.PubSubMessageClaimModel
+
+	if _, err := claims.WithContext(ctx).
+		Where(claims.MessageID.Eq(messageID), claims.Status.Eq(messageClaimProcessing)).
+		Delete(); err != nil {
+		return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	return nil
+}
+
+// PurgeExpiredMessages deletes records that expired before the cutoff and returns the number removed.
+func (repo *pubSubMessageRepository) PurgeExpiredMessages(ctx context.Context, before time.Time) (int64, error) {
+	claims := repo.q.PubSubMessageClaimModel
+
+	result, err := claims.WithContext(ctx).Where(claims.ExpiresAt.Lt(before)).Delete()
+	if err != nil {
+		return 0, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"radar/internal/domain/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newDryRunPubSubMessageRepository(t *testing.T) (repository.PubSubMessageRepository, *captureSQLLogger) {
+	t.Helper()
+
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	return NewPubSubMessageRepository(db), sqlLogger
+}
+
+func TestPubSubMessageRepository_ClaimMessage_TakesOverOnlyLapsedClaims(t *testing.T) {
+	repo, sqlLogger := newDryRunPubSubMessageRepository(t)
+	now := time.Date(2026, 10, 15, 3, 0, 0, 0, time.UTC)
+
+	// Dry-run statements affect no rows, which reads as a message another delivery still holds
+	claim, err := repo.ClaimMessage(context.Background(), "msg-1", now, now.Add(10*time.Minute))
+
+	require.NoError(t, err)
+	assert.Equal(t, repository.MessageInFlight, claim)
+	require.Len(t, sqlLogger.queries, 2)
+	assert.Contains(t, sqlLogger.queries[0], `INSERT INTO "pubsub_message_claims"`)
+	assert.Contains(t, sqlLogger.queries[0], `ON CONFLICT ("message_id") DO UPDATE SET "status"="excluded"."status"`)
+	assert.Contains(t, sqlLogger.queries[0], "WHERE pubsub_message_claims.expires_at <= '2026-10-15 03:00:00'")
+	assert.Contains(t, sqlLogger.queries[1], `"pubsub_message_claims"."message_id" = 'msg-1'`)
+}
+
+func TestPubSubMessageRepository_ReleaseMessage_KeepsProcessedRecords(t *testing.T) {
+	repo, sqlLogger := newDryRunPubSubMessageRepository(t)
+
+	require.NoError(t, repo.ReleaseMessage(context.Background(), "msg-1"))
+
+	require.Len(t, sqlLogger.queries, 1)
+	assert.Contains(t, sqlLogger.queries[0], `DELETE FROM "pubsub_message_claims"`)
+	assert.Contains(t, sqlLogger.queries[0], `"pubsub_message_claims"."status" = 'processing'`)
+}
+
+func TestPubSubMessageRepository_PurgeExpiredMessages_DeletesBeforeCutoff(t *testing.T) {
+	repo, sqlLogger := newDryRunPubSubMessageRepository(t)
+	cutoff := time.Date(2026, 10, 15, 3, 0, 0, 0, time.UTC)
+
+	purged, err := repo.PurgeExpiredMessages(context.Background(), cutoff)
+
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+	require.Len(t, sqlLogger.queries, 1)
+	assert.Contains(t, sqlLogger.queries[0], `DELETE FROM "pubsub_message_claims"`)
+	assert.Contains(t, sqlLogger.queries[0], `"pubsub_message_claims"."expires_at" < '2026-10-15 03:00:00'`)
+}
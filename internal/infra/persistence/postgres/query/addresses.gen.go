@@ -36,6 +36,7 @@ func newAddressModel(db *gorm.DB, opts ...gen.DOOption) addressModel {
 	_addressModel.Longitude = field.NewFloat64(tableName, "longitude")
 	_addressModel.IsPrimary = field.NewBool(tableName, "is_primary")
 	_addressModel.IsActive = field.NewBool(tableName, "is_active")
+	_addressModel.GeofencePolygon = field.NewField(tableName, "geofence_polygon")
 	_addressModel.CreatedAt = field.NewTime(tableName, "created_at")
 	_addressModel.UpdatedAt = field.NewTime(tableName, "updated_at")
 	_addressModel.DeletedAt = field.NewField(tableName, "deleted_at")
@@ -58,6 +59,7 @@ type addressModel struct {
 	Longitude         field.Float64
 	IsPrimary         field.Bool
 	IsActive          field.Bool
+	GeofencePolygon   field.Field
 	CreatedAt         field.Time
 	UpdatedAt         field.Time
 	DeletedAt         field.Field
@@ -86,6 +88,7 @@ func (a *addressModel) updateTableName(table string) *addressModel {
 	a.Longitude = field.NewFloat64(table, "longitude")
 	a.IsPrimary = field.NewBool(table, "is_primary")
 	a.IsActive = field.NewBool(table, "is_active")
+	a.GeofencePolygon = field.NewField(table, "geofence_polygon")
 	a.CreatedAt = field.NewTime(table, "created_at")
 	a.UpdatedAt = field.NewTime(table, "updated_at")
 	a.DeletedAt = field.NewField(table, "deleted_at")
@@ -117,7 +120,7 @@ func (a *addressModel) GetFieldByName(fieldName string) (field.OrderExpr, bool)
 }
 
 func (a *addressModel) fillFieldMap() {
-	a.fieldMap = make(map[string]field.Expr, 12)
+	a.fieldMap = make(map[string]field.Expr, 13)
 	a.fieldMap["id"] = a.ID
 	a.fieldMap["user_profile_id"] = a.UserProfileID
 	a.fieldMap["merchant_profile_id"] = a.MerchantProfileID
@@ -127,6 +130,7 @@ func (a *addressModel) fillFieldMap() {
 	a.fieldMap["longitude"] = a.Longitude
 	a.fieldMap["is_primary"] = a.IsPrimary
 	a.fieldMap["is_active"] = a.IsActive
+	a.fieldMap["geofence_polygon"] = a.GeofencePolygon
 	a.fieldMap["created_at"] = a.CreatedAt
 	a.fieldMap["updated_at"] = a.UpdatedAt
 	a.fieldMap["deleted_at"] = a.DeletedAt
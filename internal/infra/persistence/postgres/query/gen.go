@@ -18,6 +18,7 @@ import (
 func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 	return &Query{
 		db:                                db,
+		APITokenModel:                     newAPITokenModel(db, opts...),
 		AddressModel:                      newAddressModel(db, opts...),
 		AuthenticationModel:               newAuthenticationModel(db, opts...),
 		DiscoveryCategoryModel:            newDiscoveryCategoryModel(db, opts...),
@@ -27,7 +28,10 @@ func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 		MenuItemModel:                     newMenuItemModel(db, opts...),
 		MerchantLocationNotificationModel: newMerchantLocationNotificationModel(db, opts...),
 		MerchantProfileModel:              newMerchantProfileModel(db, opts...),
+		MerchantSettingsModel:             newMerchantSettingsModel(db, opts...),
 		NotificationLogModel:              newNotificationLogModel(db, opts...),
+		NotificationPreferenceModel:       newNotificationPreferenceModel(db, opts...),
+		PubSubMessageClaimModel:           newPubSubMessageClaimModel(db, opts...),
 		RefreshTokenModel:                 newRefreshTokenModel(db, opts...),
 		UserDeviceModel:                   newUserDeviceModel(db, opts...),
 		UserMerchantSubscriptionModel:     newUserMerchantSubscriptionModel(db, opts...),
@@ -39,6 +43,7 @@ func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 type Query struct {
 	db *gorm.DB
 
+	APITokenModel                     aPITokenModel
 	AddressModel                      addressModel
 	AuthenticationModel               authenticationModel
 	DiscoveryCategoryModel            discoveryCategoryModel
@@ -48,7 +53,10 @@ type Query struct {
 	MenuItemModel                     menuItemModel
 	MerchantLocationNotificationModel merchantLocationNotificationModel
 	MerchantProfileModel              merchantProfileModel
+	MerchantSettingsModel             merchantSettingsModel
 	NotificationLogModel              notificationLogModel
+	NotificationPreferenceModel       notificationPreferenceModel
+	PubSubMessageClaimModel           pubSubMessageClaimModel
 	RefreshTokenModel                 refreshTokenModel
 	UserDeviceModel                   userDeviceModel
 	UserMerchantSubscriptionModel     userMerchantSubscriptionModel
@@ -61,6 +69,7 @@ func (q *Query) Available() bool { return q.db != nil }
 func (q *Query) clone(db *gorm.DB) *Query {
 	return &Query{
 		db:                                db,
+		APITokenModel:                     q.APITokenModel.clone(db),
 		AddressModel:                      q.AddressModel.clone(db),
 		AuthenticationModel:               q.AuthenticationModel.clone(db),
 		DiscoveryCategoryModel:            q.DiscoveryCategoryModel.clone(db),
@@ -70,7 +79,10 @@ func (q *Query) clone(db *gorm.DB) *Query {
 		MenuItemModel:                     q.MenuItemModel.clone(db),
 		MerchantLocationNotificationModel: q.MerchantLocationNotificationModel.clone(db),
 		MerchantProfileModel:              q.MerchantProfileModel.clone(db),
+		MerchantSettingsModel:             q.MerchantSettingsModel.clone(db),
 		NotificationLogModel:              q.NotificationLogModel.clone(db),
+		NotificationPreferenceModel:       q.NotificationPreferenceModel.clone(db),
+		PubSubMessageClaimModel:           q.PubSubMessageClaimModel.clone(db),
 		RefreshTokenModel:                 q.RefreshTokenModel.clone(db),
 		UserDeviceModel:                   q.UserDeviceModel.clone(db),
 		UserMerchantSubscriptionModel:     q.UserMerchantSubscriptionModel.clone(db),
@@ -90,6 +102,7 @@ func (q *Query) WriteDB() *Query {
 func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 	return &Query{
 		db:                                db,
+		APITokenModel:                     q.APITokenModel.replaceDB(db),
 		AddressModel:                      q.AddressModel.replaceDB(db),
 		AuthenticationModel:               q.AuthenticationModel.replaceDB(db),
 		DiscoveryCategoryModel:            q.DiscoveryCategoryModel.replaceDB(db),
@@ -99,7 +112,10 @@ func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 		MenuItemModel:                     q.MenuItemModel.replaceDB(db),
 		MerchantLocationNotificationModel: q.MerchantLocationNotificationModel.replaceDB(db),
 		MerchantProfileModel:              q.MerchantProfileModel.replaceDB(db),
+		MerchantSettingsModel:             q.MerchantSettingsModel.replaceDB(db),
 		NotificationLogModel:              q.NotificationLogModel.replaceDB(db),
+		NotificationPreferenceModel:       q.NotificationPreferenceModel.replaceDB(db),
+		PubSubMessageClaimModel:           q.PubSubMessageClaimModel.replaceDB(db),
 		RefreshTokenModel:                 q.RefreshTokenModel.replaceDB(db),
 		UserDeviceModel:                   q.UserDeviceModel.replaceDB(db),
 		UserMerchantSubscriptionModel:     q.UserMerchantSubscriptionModel.replaceDB(db),
@@ -109,6 +125,7 @@ func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 }
 
 type queryCtx struct {
+	APITokenModel                     *aPITokenModelDo
 	AddressModel                      *addressModelDo
 	AuthenticationModel               *authenticationModelDo
 	DiscoveryCategoryModel            *discoveryCategoryModelDo
@@ -118,7 +135,10 @@ type queryCtx struct {
 	MenuItemModel                     *menuItemModelDo
 	MerchantLocationNotificationModel *merchantLocationNotificationModelDo
 	MerchantProfileModel              *merchantProfileModelDo
+	MerchantSettingsModel             *merchantSettingsModelDo
 	NotificationLogModel              *notificationLogModelDo
+	NotificationPreferenceModel       *notificationPreferenceModelDo
+	PubSubMessageClaimModel           *pubSubMessageClaimModelDo
 	RefreshTokenModel                 *refreshTokenModelDo
 	UserDeviceModel                   *userDeviceModelDo
 	UserMerchantSubscriptionModel     *userMerchantSubscriptionModelDo
@@ -128,6 +148,7 @@ type queryCtx struct {
 
 func (q *Query) WithContext(ctx context.Context) *queryCtx {
 	return &queryCtx{
+		APITokenModel:                     q.APITokenModel.WithContext(ctx),
 		AddressModel:                      q.AddressModel.WithContext(ctx),
 		AuthenticationModel:               q.AuthenticationModel.WithContext(ctx),
 		DiscoveryCategoryModel:            q.DiscoveryCategoryModel.WithContext(ctx),
@@ -137,7 +158,10 @@ func (q *Query) WithContext(ctx context.Context) *queryCtx {
 		MenuItemModel:                     q.MenuItemModel.WithContext(ctx),
 		MerchantLocationNotificationModel: q.MerchantLocationNotificationModel.WithContext(ctx),
 		MerchantProfileModel:              q.MerchantProfileModel.WithContext(ctx),
+		MerchantSettingsModel:             q.MerchantSettingsModel.WithContext(ctx),
 		NotificationLogModel:              q.NotificationLogModel.WithContext(ctx),
+		NotificationPreferenceModel:       q.NotificationPreferenceModel.WithContext(ctx),
+		PubSubMessageClaimModel:           q.PubSubMessageClaimModel.WithContext(ctx),
 		RefreshTokenModel:                 q.RefreshTokenModel.WithContext(ctx),
 		UserDeviceModel:                   q.UserDeviceModel.WithContext(ctx),
 		UserMerchantSubscriptionModel:     q.UserMerchantSubscriptionModel.WithContext(ctx),
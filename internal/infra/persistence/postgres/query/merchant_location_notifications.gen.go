@@ -37,9 +37,13 @@ func newMerchantLocationNotificationModel(db *gorm.DB, opts ...gen.DOOption) mer
 	_merchantLocationNotificationModel.HintMessage = field.NewString(tableName, "hint_message")
 	_merchantLocationNotificationModel.TotalSent = field.NewInt(tableName, "total_sent")
 	_merchantLocationNotificationModel.TotalFailed = field.NewInt(tableName, "total_failed")
+	_merchantLocationNotificationModel.TotalOpened = field.NewInt(tableName, "total_opened")
 	_merchantLocationNotificationModel.PublishedAt = field.NewTime(tableName, "published_at")
 	_merchantLocationNotificationModel.CreatedAt = field.NewTime(tableName, "created_at")
 	_merchantLocationNotificationModel.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_merchantLocationNotificationModel.ScheduledAt = field.NewTime(tableName, "scheduled_at")
+	_merchantLocationNotificationModel.ScheduleStatus = field.NewString(tableName, "schedule_status")
+	_merchantLocationNotificationModel.ClaimedAt = field.NewTime(tableName, "claimed_at")
 
 	_merchantLocationNotificationModel.fillFieldMap()
 
@@ -49,20 +53,24 @@ func newMerchantLocationNotificationModel(db *gorm.DB, opts ...gen.DOOption) mer
 type merchantLocationNotificationModel struct {
 	merchantLocationNotificationModelDo merchantLocationNotificationModelDo
 
-	ALL          field.Asterisk
-	ID           field.Field
-	MerchantID   field.Field
-	AddressID    field.Field
-	LocationName field.String
-	FullAddress  field.String
-	Latitude     field.Float64
-	Longitude    field.Float64
-	HintMessage  field.String
-	TotalSent    field.Int
-	TotalFailed  field.Int
-	PublishedAt  field.Time
-	CreatedAt    field.Time
-	UpdatedAt    field.Time
+	ALL            field.Asterisk
+	ID             field.Field
+	MerchantID     field.Field
+	AddressID      field.Field
+	LocationName   field.String
+	FullAddress    field.String
+	Latitude       field.Float64
+	Longitude      field.Float64
+	HintMessage    field.String
+	TotalSent      field.Int
+	TotalFailed    field.Int
+	TotalOpened    field.Int
+	PublishedAt    field.Time
+	CreatedAt      field.Time
+	UpdatedAt      field.Time
+	ScheduledAt    field.Time
+	ScheduleStatus field.String
+	ClaimedAt      field.Time
 
 	fieldMap map[string]field.Expr
 }
@@ -89,9 +97,13 @@ func (m *merchantLocationNotificationModel) updateTableName(table string) *merch
 	m.HintMessage = field.NewString(table, "hint_message")
 	m.TotalSent = field.NewInt(table, "total_sent")
 	m.TotalFailed = field.NewInt(table, "total_failed")
+	m.TotalOpened = field.NewInt(table, "total_opened")
 	m.PublishedAt = field.NewTime(table, "published_at")
 	m.CreatedAt = field.NewTime(table, "created_at")
 	m.UpdatedAt = field.NewTime(table, "updated_at")
+	m.ScheduledAt = field.NewTime(table, "scheduled_at")
+	m.ScheduleStatus = field.NewString(table, "schedule_status")
+	m.ClaimedAt = field.NewTime(table, "claimed_at")
 
 	m.fillFieldMap()
 
@@ -124,7 +136,7 @@ func (m *merchantLocationNotificationModel) GetFieldByName(fieldName string) (fi
 }
 
 func (m *merchantLocationNotificationModel) fillFieldMap() {
-	m.fieldMap = make(map[string]field.Expr, 13)
+	m.fieldMap = make(map[string]field.Expr, 17)
 	m.fieldMap["id"] = m.ID
 	m.fieldMap["merchant_id"] = m.MerchantID
 	m.fieldMap["address_id"] = m.AddressID
@@ -135,9 +147,13 @@ func (m *merchantLocationNotificationModel) fillFieldMap() {
 	m.fieldMap["hint_message"] = m.HintMessage
 	m.fieldMap["total_sent"] = m.TotalSent
 	m.fieldMap["total_failed"] = m.TotalFailed
+	m.fieldMap["total_opened"] = m.TotalOpened
 	m.fieldMap["published_at"] = m.PublishedAt
 	m.fieldMap["created_at"] = m.CreatedAt
 	m.fieldMap["updated_at"] = m.UpdatedAt
+	m.fieldMap["scheduled_at"] = m.ScheduledAt
+	m.fieldMap["schedule_status"] = m.ScheduleStatus
+	m.fieldMap["claimed_at"] = m.ClaimedAt
 }
 
 func (m merchantLocationNotificationModel) clone(db *gorm.DB) merchantLocationNotificationModel {
@@ -35,6 +35,7 @@ func newNotificationLogModel(db *gorm.DB, opts ...gen.DOOption) notificationLogM
 	_notificationLogModel.FCMMessageID = field.NewString(tableName, "fcm_message_id")
 	_notificationLogModel.ErrorMessage = field.NewString(tableName, "error_message")
 	_notificationLogModel.SentAt = field.NewTime(tableName, "sent_at")
+	_notificationLogModel.OpenedAt = field.NewTime(tableName, "opened_at")
 
 	_notificationLogModel.fillFieldMap()
 
@@ -53,6 +54,7 @@ type notificationLogModel struct {
 	FCMMessageID   field.String
 	ErrorMessage   field.String
 	SentAt         field.Time
+	OpenedAt       field.Time
 
 	fieldMap map[string]field.Expr
 }
@@ -77,6 +79,7 @@ func (n *notificationLogModel) updateTableName(table string) *notificationLogMod
 	n.FCMMessageID = field.NewString(table, "fcm_message_id")
 	n.ErrorMessage = field.NewString(table, "error_message")
 	n.SentAt = field.NewTime(table, "sent_at")
+	n.OpenedAt = field.NewTime(table, "opened_at")
 
 	n.fillFieldMap()
 
@@ -105,7 +108,7 @@ func (n *notificationLogModel) GetFieldByName(fieldName string) (field.OrderExpr
 }
 
 func (n *notificationLogModel) fillFieldMap() {
-	n.fieldMap = make(map[string]field.Expr, 8)
+	n.fieldMap = make(map[string]field.Expr, 9)
 	n.fieldMap["id"] = n.ID
 	n.fieldMap["notification_id"] = n.NotificationID
 	n.fieldMap["user_id"] = n.UserID
@@ -114,6 +117,7 @@ func (n *notificationLogModel) fillFieldMap() {
 	n.fieldMap["fcm_message_id"] = n.FCMMessageID
 	n.fieldMap["error_message"] = n.ErrorMessage
 	n.fieldMap["sent_at"] = n.SentAt
+	n.fieldMap["opened_at"] = n.OpenedAt
 }
 
 func (n notificationLogModel) clone(db *gorm.DB) notificationLogModel {
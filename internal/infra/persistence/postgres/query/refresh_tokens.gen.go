@@ -34,6 +34,7 @@ func newRefreshTokenModel(db *gorm.DB, opts ...gen.DOOption) refreshTokenModel {
 	_refreshTokenModel.IsRevoked = field.NewBool(tableName, "is_revoked")
 	_refreshTokenModel.ReplacedBy = field.NewField(tableName, "replaced_by")
 	_refreshTokenModel.ExpiresAt = field.NewTime(tableName, "expires_at")
+	_refreshTokenModel.LastUsedAt = field.NewTime(tableName, "last_used_at")
 	_refreshTokenModel.CreatedAt = field.NewTime(tableName, "created_at")
 
 	_refreshTokenModel.fillFieldMap()
@@ -52,6 +53,7 @@ type refreshTokenModel struct {
 	IsRevoked  field.Bool
 	ReplacedBy field.Field
 	ExpiresAt  field.Time
+	LastUsedAt field.Time
 	CreatedAt  field.Time
 
 	fieldMap map[string]field.Expr
@@ -76,6 +78,7 @@ func (r *refreshTokenModel) updateTableName(table string) *refreshTokenModel {
 	r.IsRevoked = field.NewBool(table, "is_revoked")
 	r.ReplacedBy = field.NewField(table, "replaced_by")
 	r.ExpiresAt = field.NewTime(table, "expires_at")
+	r.LastUsedAt = field.NewTime(table, "last_used_at")
 	r.CreatedAt = field.NewTime(table, "created_at")
 
 	r.fillFieldMap()
@@ -105,7 +108,7 @@ func (r *refreshTokenModel) GetFieldByName(fieldName string) (field.OrderExpr, b
 }
 
 func (r *refreshTokenModel) fillFieldMap() {
-	r.fieldMap = make(map[string]field.Expr, 8)
+	r.fieldMap = make(map[string]field.Expr, 9)
 	r.fieldMap["id"] = r.ID
 	r.fieldMap["user_id"] = r.UserID
 	r.fieldMap["token_hash"] = r.TokenHash
@@ -113,6 +116,7 @@ func (r *refreshTokenModel) fillFieldMap() {
 	r.fieldMap["is_revoked"] = r.IsRevoked
 	r.fieldMap["replaced_by"] = r.ReplacedBy
 	r.fieldMap["expires_at"] = r.ExpiresAt
+	r.fieldMap["last_used_at"] = r.LastUsedAt
 	r.fieldMap["created_at"] = r.CreatedAt
 }
 
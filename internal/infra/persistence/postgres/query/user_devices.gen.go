@@ -32,8 +32,15 @@ func newUserDeviceModel(db *gorm.DB, opts ...gen.DOOption) userDeviceModel {
 	_userDeviceModel.FCMToken = field.NewString(tableName, "fcm_token")
 	_userDeviceModel.DeviceID = field.NewString(tableName, "device_id")
 	_userDeviceModel.Platform = field.NewString(tableName, "platform")
+	_userDeviceModel.AppVersion = field.NewString(tableName, "app_version")
 	_userDeviceModel.IsActive = field.NewBool(tableName, "is_active")
 	_userDeviceModel.TokenRefreshedAt = field.NewTime(tableName, "token_refreshed_at")
+	_userDeviceModel.InvalidTokenStrikes = field.NewInt(tableName, "invalid_token_strikes")
+	_userDeviceModel.InvalidTokenFirstAt = field.NewTime(tableName, "invalid_token_first_at")
+	_userDeviceModel.LastSuccessAt = field.NewTime(tableName, "last_success_at")
+	_userDeviceModel.NotificationsEnabled = field.NewBool(tableName, "notifications_enabled")
+	_userDeviceModel.QuietHoursStart = field.NewInt(tableName, "quiet_hours_start")
+	_userDeviceModel.QuietHoursEnd = field.NewInt(tableName, "quiet_hours_end")
 	_userDeviceModel.CreatedAt = field.NewTime(tableName, "created_at")
 	_userDeviceModel.UpdatedAt = field.NewTime(tableName, "updated_at")
 	_userDeviceModel.DeletedAt = field.NewField(tableName, "deleted_at")
@@ -46,17 +53,24 @@ func newUserDeviceModel(db *gorm.DB, opts ...gen.DOOption) userDeviceModel {
 type userDeviceModel struct {
 	userDeviceModelDo userDeviceModelDo
 
-	ALL              field.Asterisk
-	ID               field.Field
-	UserID           field.Field
-	FCMToken         field.String
-	DeviceID         field.String
-	Platform         field.String
-	IsActive         field.Bool
-	TokenRefreshedAt field.Time
-	CreatedAt        field.Time
-	UpdatedAt        field.Time
-	DeletedAt        field.Field
+	ALL                  field.Asterisk
+	ID                   field.Field
+	UserID               field.Field
+	FCMToken             field.String
+	DeviceID             field.String
+	Platform             field.String
+	AppVersion           field.String
+	IsActive             field.Bool
+	TokenRefreshedAt     field.Time
+	InvalidTokenStrikes  field.Int
+	InvalidTokenFirstAt  field.Time
+	LastSuccessAt        field.Time
+	NotificationsEnabled field.Bool
+	QuietHoursStart      field.Int
+	QuietHoursEnd        field.Int
+	CreatedAt            field.Time
+	UpdatedAt            field.Time
+	DeletedAt            field.Field
 
 	fieldMap map[string]field.Expr
 }
@@ -78,8 +92,15 @@ func (u *userDeviceModel) updateTableName(table string) *userDeviceModel {
 	u.FCMToken = field.NewString(table, "fcm_token")
 	u.DeviceID = field.NewString(table, "device_id")
 	u.Platform = field.NewString(table, "platform")
+	u.AppVersion = field.NewString(table, "app_version")
 	u.IsActive = field.NewBool(table, "is_active")
 	u.TokenRefreshedAt = field.NewTime(table, "token_refreshed_at")
+	u.InvalidTokenStrikes = field.NewInt(table, "invalid_token_strikes")
+	u.InvalidTokenFirstAt = field.NewTime(table, "invalid_token_first_at")
+	u.LastSuccessAt = field.NewTime(table, "last_success_at")
+	u.NotificationsEnabled = field.NewBool(table, "notifications_enabled")
+	u.QuietHoursStart = field.NewInt(table, "quiet_hours_start")
+	u.QuietHoursEnd = field.NewInt(table, "quiet_hours_end")
 	u.CreatedAt = field.NewTime(table, "created_at")
 	u.UpdatedAt = field.NewTime(table, "updated_at")
 	u.DeletedAt = field.NewField(table, "deleted_at")
@@ -111,14 +132,21 @@ func (u *userDeviceModel) GetFieldByName(fieldName string) (field.OrderExpr, boo
 }
 
 func (u *userDeviceModel) fillFieldMap() {
-	u.fieldMap = make(map[string]field.Expr, 10)
+	u.fieldMap = make(map[string]field.Expr, 17)
 	u.fieldMap["id"] = u.ID
 	u.fieldMap["user_id"] = u.UserID
 	u.fieldMap["fcm_token"] = u.FCMToken
 	u.fieldMap["device_id"] = u.DeviceID
 	u.fieldMap["platform"] = u.Platform
+	u.fieldMap["app_version"] = u.AppVersion
 	u.fieldMap["is_active"] = u.IsActive
 	u.fieldMap["token_refreshed_at"] = u.TokenRefreshedAt
+	u.fieldMap["invalid_token_strikes"] = u.InvalidTokenStrikes
+	u.fieldMap["invalid_token_first_at"] = u.InvalidTokenFirstAt
+	u.fieldMap["last_success_at"] = u.LastSuccessAt
+	u.fieldMap["notifications_enabled"] = u.NotificationsEnabled
+	u.fieldMap["quiet_hours_start"] = u.QuietHoursStart
+	u.fieldMap["quiet_hours_end"] = u.QuietHoursEnd
 	u.fieldMap["created_at"] = u.CreatedAt
 	u.fieldMap["updated_at"] = u.UpdatedAt
 	u.fieldMap["deleted_at"] = u.DeletedAt
@@ -32,6 +32,7 @@ func newUserMerchantSubscriptionModel(db *gorm.DB, opts ...gen.DOOption) userMer
 	_userMerchantSubscriptionModel.MerchantID = field.NewField(tableName, "merchant_id")
 	_userMerchantSubscriptionModel.IsActive = field.NewBool(tableName, "is_active")
 	_userMerchantSubscriptionModel.NotificationRadius = field.NewFloat64(tableName, "notification_radius")
+	_userMerchantSubscriptionModel.SnoozedUntil = field.NewTime(tableName, "snoozed_until")
 	_userMerchantSubscriptionModel.SubscribedAt = field.NewTime(tableName, "subscribed_at")
 	_userMerchantSubscriptionModel.UpdatedAt = field.NewTime(tableName, "updated_at")
 	_userMerchantSubscriptionModel.DeletedAt = field.NewField(tableName, "deleted_at")
@@ -50,6 +51,7 @@ type userMerchantSubscriptionModel struct {
 	MerchantID         field.Field
 	IsActive           field.Bool
 	NotificationRadius field.Float64
+	SnoozedUntil       field.Time
 	SubscribedAt       field.Time
 	UpdatedAt          field.Time
 	DeletedAt          field.Field
@@ -74,6 +76,7 @@ func (u *userMerchantSubscriptionModel) updateTableName(table string) *userMerch
 	u.MerchantID = field.NewField(table, "merchant_id")
 	u.IsActive = field.NewBool(table, "is_active")
 	u.NotificationRadius = field.NewFloat64(table, "notification_radius")
+	u.SnoozedUntil = field.NewTime(table, "snoozed_until")
 	u.SubscribedAt = field.NewTime(table, "subscribed_at")
 	u.UpdatedAt = field.NewTime(table, "updated_at")
 	u.DeletedAt = field.NewField(table, "deleted_at")
@@ -109,12 +112,13 @@ func (u *userMerchantSubscriptionModel) GetFieldByName(fieldName string) (field.
 }
 
 func (u *userMerchantSubscriptionModel) fillFieldMap() {
-	u.fieldMap = make(map[string]field.Expr, 8)
+	u.fieldMap = make(map[string]field.Expr, 9)
 	u.fieldMap["id"] = u.ID
 	u.fieldMap["user_id"] = u.UserID
 	u.fieldMap["merchant_id"] = u.MerchantID
 	u.fieldMap["is_active"] = u.IsActive
 	u.fieldMap["notification_radius"] = u.NotificationRadius
+	u.fieldMap["snoozed_until"] = u.SnoozedUntil
 	u.fieldMap["subscribed_at"] = u.SubscribedAt
 	u.fieldMap["updated_at"] = u.UpdatedAt
 	u.fieldMap["deleted_at"] = u.DeletedAt
@@ -29,6 +29,7 @@ func newUserProfileModel(db *gorm.DB, opts ...gen.DOOption) userProfileModel {
 	_userProfileModel.ALL = field.NewAsterisk(tableName)
 	_userProfileModel.UserID = field.NewField(tableName, "user_id")
 	_userProfileModel.LoyaltyPoints = field.NewInt(tableName, "loyalty_points")
+	_userProfileModel.BroadcastsSnoozedUntil = field.NewTime(tableName, "broadcasts_snoozed_until")
 	_userProfileModel.CreatedAt = field.NewTime(tableName, "created_at")
 	_userProfileModel.UpdatedAt = field.NewTime(tableName, "updated_at")
 	_userProfileModel.Addresses = userProfileModelHasManyAddresses{
@@ -45,12 +46,13 @@ func newUserProfileModel(db *gorm.DB, opts ...gen.DOOption) userProfileModel {
 type userProfileModel struct {
 	userProfileModelDo userProfileModelDo
 
-	ALL           field.Asterisk
-	UserID        field.Field
-	LoyaltyPoints field.Int
-	CreatedAt     field.Time
-	UpdatedAt     field.Time
-	Addresses     userProfileModelHasManyAddresses
+	ALL                    field.Asterisk
+	UserID                 field.Field
+	LoyaltyPoints          field.Int
+	BroadcastsSnoozedUntil field.Time
+	CreatedAt              field.Time
+	UpdatedAt              field.Time
+	Addresses              userProfileModelHasManyAddresses
 
 	fieldMap map[string]field.Expr
 }
@@ -69,6 +71,7 @@ func (u *userProfileModel) updateTableName(table string) *userProfileModel {
 	u.ALL = field.NewAsterisk(table)
 	u.UserID = field.NewField(table, "user_id")
 	u.LoyaltyPoints = field.NewInt(table, "loyalty_points")
+	u.BroadcastsSnoozedUntil = field.NewTime(table, "broadcasts_snoozed_until")
 	u.CreatedAt = field.NewTime(table, "created_at")
 	u.UpdatedAt = field.NewTime(table, "updated_at")
 
@@ -99,9 +102,10 @@ func (u *userProfileModel) GetFieldByName(fieldName string) (field.OrderExpr, bo
 }
 
 func (u *userProfileModel) fillFieldMap() {
-	u.fieldMap = make(map[string]field.Expr, 5)
+	u.fieldMap = make(map[string]field.Expr, 6)
 	u.fieldMap["user_id"] = u.UserID
 	u.fieldMap["loyalty_points"] = u.LoyaltyPoints
+	u.fieldMap["broadcasts_snoozed_until"] = u.BroadcastsSnoozedUntil
 	u.fieldMap["created_at"] = u.CreatedAt
 	u.fieldMap["updated_at"] = u.UpdatedAt
 
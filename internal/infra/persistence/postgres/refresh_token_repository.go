@@ -169,6 +169,23 @@ func (repo *refreshTokenRepository) UpdateRefreshToken(ctx context.Context, toke
 	return nil
 }
 
+// TouchRefreshToken records the last time a refresh token was used.
+func (repo *refreshTokenRepository) TouchRefreshToken(ctx context.Context, tokenHash string, usedAt time.Time) error {
+	result, err := repo.q.RefreshTokenModel.WithContext(ctx).
+		Where(repo.q.RefreshTokenModel.TokenHash.Eq(tokenHash)).
+		Update(repo.q.RefreshTokenModel.LastUsedAt, usedAt)
+	if err != nil {
+		return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	// If no rows were affected, it means the token was not found.
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrRefreshTokenNotFound
+	}
+
+	return nil
+}
+
 // DeleteRefreshToken removes a refresh token by its ID, effectively ending a session.
 func (repo *refreshTokenRepository) DeleteRefreshToken(ctx context.Context, id uuid.UUID) error {
 	result, err := repo.q.RefreshTokenModel.WithContext(ctx).
@@ -216,16 +233,17 @@ func (repo *refreshTokenRepository) DeleteRefreshTokensByUserID(ctx context.Cont
 	return nil
 }
 
-// DeleteExpiredRefreshTokens removes all expired refresh tokens from the database.
-func (repo *refreshTokenRepository) DeleteExpiredRefreshTokens(ctx context.Context, revokedRetentionDays int) error {
-	now := time.Now()
+// DeleteExpiredRefreshTokens removes the refresh tokens expired at now, or revoked before the retention cutoff,
+// and returns how many were removed.
+func (repo *refreshTokenRepository) DeleteExpiredRefreshTokens(ctx context.Context, now time.Time, revokedRetentionDays int) (int64, error) {
 	revokedCutoff := now.AddDate(0, 0, -revokedRetentionDays)
 
-	if err := deleteExpiredRefreshTokensQuery(repo.q.RefreshTokenModel.WithContext(ctx).UnderlyingDB(), now, revokedCutoff).Error; err != nil {
-		return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	result := deleteExpiredRefreshTokensQuery(repo.q.RefreshTokenModel.WithContext(ctx).UnderlyingDB(), now, revokedCutoff)
+	if result.Error != nil {
+		return 0, replaceWithSourceStack(result.Error, domainerrors.ErrPersistenceFailed)
 	}
 
-	return nil
+	return result.RowsAffected, nil
 }
 
 func deleteExpiredRefreshTokensQuery(db *gorm.DB, now, revokedCutoff time.Time) *gorm.DB {
@@ -297,6 +315,7 @@ func toRefreshTokenDomain(data *model.RefreshTokenModel) *entity.RefreshToken {
 		IsRevoked:  data.IsRevoked,
 		ReplacedBy: data.ReplacedBy,
 		ExpiresAt:  data.ExpiresAt,
+		LastUsedAt: data.LastUsedAt,
 		CreatedAt:  data.CreatedAt,
 	}
 }
@@ -315,6 +334,7 @@ func fromRefreshTokenDomain(data *entity.RefreshToken) *model.RefreshTokenModel
 		IsRevoked:  data.IsRevoked,
 		ReplacedBy: data.ReplacedBy,
 		ExpiresAt:  data.ExpiresAt,
+		LastUsedAt: data.LastUsedAt,
 		CreatedAt:  data.CreatedAt,
 	}
 }
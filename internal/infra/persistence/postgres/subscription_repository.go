@@ -1,8 +1,13 @@
 package postgres
 
 import (
+	"cmp"
 	"context"
 	"database/sql/driver"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	"radar/internal/domain/entity"
@@ -13,6 +18,9 @@ import (
 	"radar/internal/infra/persistence/postgres/query"
 
 	"github.com/google/uuid"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+	"gorm.io/gen/field"
 	"gorm.io/gorm"
 )
 
@@ -193,6 +201,60 @@ func (repo *subscriptionRepository) UpdateNotificationRadius(ctx context.Context
 	return nil
 }
 
+// UpdateNotificationRadiusByUser sets the notification radius on every active subscription of a user in one statement.
+func (repo *subscriptionRepository) UpdateNotificationRadiusByUser(ctx context.Context, userID uuid.UUID, radius float64) (int64, error) {
+	sub := repo.q.UserMerchantSubscriptionModel
+
+	result, err := sub.WithContext(ctx).
+		Where(sub.UserID.Eq(userID), sub.IsActive.Is(true), sub.DeletedAt.IsNull()).
+		Update(sub.NotificationRadius, radius)
+
+	if err != nil {
+		return 0, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// UpdateSnoozedUntil sets or clears (nil) the per-merchant broadcast snooze for a subscription.
+func (repo *subscriptionRepository) UpdateSnoozedUntil(ctx context.Context, subscriptionID uuid.UUID, until *time.Time) error {
+	sub := repo.q.UserMerchantSubscriptionModel
+
+	result, err := sub.WithContext(ctx).
+		Where(sub.ID.Eq(subscriptionID), sub.DeletedAt.IsNull()).
+		Update(sub.SnoozedUntil, until)
+
+	if err != nil {
+		return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+// UpdateGlobalSnoozedUntil sets or clears (nil) the user's broadcast snooze across all merchants.
+// A user without a profile row yields ErrUserProfileNotFound.
+func (repo *subscriptionRepository) UpdateGlobalSnoozedUntil(ctx context.Context, userID uuid.UUID, until *time.Time) error {
+	profile := repo.q.UserProfileModel
+
+	result, err := profile.WithContext(ctx).
+		Where(profile.UserID.Eq(userID)).
+		Update(profile.BroadcastsSnoozedUntil, until)
+
+	if err != nil {
+		return replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	if result.RowsAffected == 0 {
+		return domainerrors.ErrUserProfileNotFound
+	}
+
+	return nil
+}
+
 // DeleteSubscription removes a subscription by its ID (soft delete).
 func (repo *subscriptionRepository) DeleteSubscription(ctx context.Context, subscriptionID uuid.UUID) error {
 	result, err := repo.q.UserMerchantSubscriptionModel.WithContext(ctx).
@@ -248,22 +310,66 @@ func (repo *subscriptionRepository) FindSubscribersWithinRadius(ctx context.Cont
 
 type subscriberAddressModel struct {
 	model.AddressModel
-	NotificationRadius float64 `gorm:"column:notification_radius"`
+	NotificationRadius     float64    `gorm:"column:notification_radius"`
+	SnoozedUntil           *time.Time `gorm:"column:snoozed_until"`
+	BroadcastsSnoozedUntil *time.Time `gorm:"column:broadcasts_snoozed_until"`
+	MerchantUserID         *uuid.UUID `gorm:"column:merchant_user_id"` // Set when the subscriber also has a merchant account
+}
+
+// subscriberAreaCondition matches addresses whose geofence polygon contains the source point, or, for addresses
+// without a polygon, that lie within the subscription's notification radius of it. The verbs take the source
+// longitude and latitude SQL expressions, each used twice.
+const subscriberAreaCondition = "((addresses.geofence_polygon IS NULL AND " +
+	"ST_DWithin(addresses.location::geography, ST_SetSRID(ST_MakePoint(%[1]s, %[2]s), 4326)::geography, user_merchant_subscriptions.notification_radius)) OR " +
+	"ST_Covers(addresses.geofence_polygon, ST_SetSRID(ST_MakePoint(%[1]s, %[2]s), 4326)))"
+
+// subscriberSearchCondition is the index-backed prefilter of the subscriber radius lookups: addresses inside
+// the search box, served by the location GIST index, or with a geofence polygon over the source point, served by the
+// polygon index. The verbs take the SQL expressions of the box as min longitude, min latitude, max longitude,
+// max latitude, then of the source longitude and latitude.
+const subscriberSearchCondition = "(addresses.location && ST_MakeEnvelope(%s, %s, %s, %s, 4326) OR " +
+	"addresses.geofence_polygon && ST_SetSRID(ST_MakePoint(%s, %s), 4326))"
+
+// subscriberSearchPadding widens the search box past the largest notification radius, which covers the
+// difference between the spherical box and the spheroid distances PostGIS compares against the radius
+const subscriberSearchPadding = 1.1
+
+// subscriberSearchBounds returns a box around the point that contains every location within radiusMeters of it
+func subscriberSearchBounds(lat, lng, radiusMeters float64) orb.Bound {
+	return geo.NewBoundAroundPoint(orb.Point{lng, lat}, radiusMeters*subscriberSearchPadding)
 }
 
 // FindSubscriberAddressesWithinRadius performs a PostGIS geographic query to find all active addresses
 // within the notification radius of the merchant's location for active subscriptions.
 // Returns addresses with their subscription notification radius to avoid N+1 lookups.
+// Candidates are first narrowed to a box sized by the merchant's largest notification radius, so the spatial
+// indexes serve the query instead of a scan of every subscriber address.
 func (repo *subscriptionRepository) FindSubscriberAddressesWithinRadius(ctx context.Context, merchantID uuid.UUID, merchantLat, merchantLon float64) ([]*entity.SubscriberAddress, error) {
+	maxRadius, err := repo.maxNotificationRadius(ctx, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	bounds := subscriberSearchBounds(merchantLat, merchantLon, maxRadius)
+
 	addressQuery := repo.q.AddressModel
 	subscriptionQuery := repo.q.UserMerchantSubscriptionModel
+	profileQuery := repo.q.UserProfileModel
+	merchantQuery := repo.q.MerchantProfileModel
 
 	// Construct complex query using fluent API for structure and UnderlyingDB for PostGIS specifics
 	var addressModels []*subscriberAddressModel
-	err := addressQuery.WithContext(ctx).
+	err = addressQuery.WithContext(ctx).
 		Distinct().
-		Select(addressQuery.ALL, subscriptionQuery.NotificationRadius).
+		Select(
+			addressQuery.ALL,
+			subscriptionQuery.NotificationRadius,
+			subscriptionQuery.SnoozedUntil,
+			profileQuery.BroadcastsSnoozedUntil,
+			merchantQuery.UserID.As("merchant_user_id"),
+		).
 		Join(subscriptionQuery, subscriptionQuery.UserID.EqCol(addressQuery.UserProfileID)).
+		LeftJoin(profileQuery, profileQuery.UserID.EqCol(addressQuery.UserProfileID)).
+		LeftJoin(merchantQuery, merchantQuery.UserID.EqCol(addressQuery.UserProfileID), merchantQuery.DeletedAt.IsNull()).
 		Where(
 			addressQuery.UserProfileID.IsNotNull(),
 			addressQuery.IsActive.Is(true),
@@ -272,7 +378,9 @@ func (repo *subscriptionRepository) FindSubscriberAddressesWithinRadius(ctx cont
 			subscriptionQuery.IsActive.Is(true),
 			subscriptionQuery.DeletedAt.IsNull(),
 		).UnderlyingDB().
-		Where("ST_DWithin(addresses.location::geography, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography, user_merchant_subscriptions.notification_radius)", merchantLon, merchantLat).
+		Where(fmt.Sprintf(subscriberSearchCondition, "?", "?", "?", "?", "?", "?"),
+			bounds.Min.Lon(), bounds.Min.Lat(), bounds.Max.Lon(), bounds.Max.Lat(), merchantLon, merchantLat).
+		Where(fmt.Sprintf(subscriberAreaCondition, "?", "?"), merchantLon, merchantLat, merchantLon, merchantLat).
 		Find(&addressModels).Error
 
 	if err != nil {
@@ -287,8 +395,204 @@ func (repo *subscriptionRepository) FindSubscriberAddressesWithinRadius(ctx cont
 	return addresses, nil
 }
 
-// FindDevicesForUsers retrieves healthy devices for a list of user IDs.
-func (repo *subscriptionRepository) FindDevicesForUsers(ctx context.Context, userIDs []uuid.UUID, healthyWindowDays int) ([]*entity.UserDevice, error) {
+// maxNotificationRadius returns the largest notification radius among the merchant's active subscriptions, or 0
+// when it has none
+func (repo *subscriptionRepository) maxNotificationRadius(ctx context.Context, merchantID uuid.UUID) (float64, error) {
+	subscriptionQuery := repo.q.UserMerchantSubscriptionModel
+
+	var rows []struct {
+		MaxRadius float64 `gorm:"column:max_radius"`
+	}
+	err := subscriptionQuery.WithContext(ctx).
+		Select(field.NewUnsafeFieldRaw("coalesce(max(notification_radius), 0)").As("max_radius")).
+		Where(
+			subscriptionQuery.MerchantID.Eq(merchantID),
+			subscriptionQuery.IsActive.Is(true),
+			subscriptionQuery.DeletedAt.IsNull(),
+		).UnderlyingDB().
+		Find(&rows).Error
+	if err != nil {
+		return 0, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	return rows[0].MaxRadius, nil
+}
+
+// maxNotificationRadii returns the largest notification radius among each merchant's active subscriptions.
+// Merchants without one are missing from the map, which reads as a radius of 0.
+func (repo *subscriptionRepository) maxNotificationRadii(ctx context.Context, merchantIDs []uuid.UUID) (map[uuid.UUID]float64, error) {
+	subscriptionQuery := repo.q.UserMerchantSubscriptionModel
+
+	ids := make([]driver.Valuer, len(merchantIDs))
+	for idx, id := range merchantIDs {
+		ids[idx] = id
+	}
+
+	var rows []struct {
+		MerchantID uuid.UUID `gorm:"column:merchant_id"`
+		MaxRadius  float64   `gorm:"column:max_radius"`
+	}
+	err := subscriptionQuery.WithContext(ctx).
+		Select(subscriptionQuery.MerchantID, field.NewUnsafeFieldRaw("coalesce(max(notification_radius), 0)").As("max_radius")).
+		Where(
+			subscriptionQuery.MerchantID.In(ids...),
+			subscriptionQuery.IsActive.Is(true),
+			subscriptionQuery.DeletedAt.IsNull(),
+		).
+		Group(subscriptionQuery.MerchantID).
+		UnderlyingDB().
+		Find(&rows).Error
+	if err != nil {
+		return nil, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	radii := make(map[uuid.UUID]float64, len(rows))
+	for _, row := range rows {
+		radii[row.MerchantID] = row.MaxRadius
+	}
+
+	return radii, nil
+}
+
+// subscriberSourceBatchSize bounds how many source locations one tile lookup query carries
+const subscriberSourceBatchSize = 200
+
+// subscriberCandidateModel is a subscriber address row tagged with the source location it matched
+type subscriberCandidateModel struct {
+	subscriberAddressModel
+	SourceIndex int `gorm:"column:source_index"`
+}
+
+// FindSubscriberAddressesByTile looks up the subscriber addresses within radius of each source location,
+// carrying up to subscriberSourceBatchSize sources per query as an inline VALUES list, and buckets them by tile.
+func (repo *subscriptionRepository) FindSubscriberAddressesByTile(
+	ctx context.Context,
+	sources []repository.SubscriberSource,
+	zoom int,
+) ([]*entity.SubscriberTileBucket, error) {
+	candidates := make([]entity.SubscriberCandidate, 0, len(sources))
+	for start := 0; start < len(sources); start += subscriberSourceBatchSize {
+		batch, err := repo.findSubscriberCandidates(ctx, sources[start:min(start+subscriberSourceBatchSize, len(sources))], start)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, batch...)
+	}
+
+	return bucketSubscriberCandidatesByTile(candidates, zoom), nil
+}
+
+// findSubscriberCandidates runs one batched radius lookup; offset is the index of the first source in the full list.
+// Like FindSubscriberAddressesWithinRadius, each source carries a search box sized by its merchant's largest
+// notification radius, so the spatial indexes serve every source instead of a scan of every subscriber address.
+func (repo *subscriptionRepository) findSubscriberCandidates(
+	ctx context.Context,
+	sources []repository.SubscriberSource,
+	offset int,
+) ([]entity.SubscriberCandidate, error) {
+	merchantIDs := make([]uuid.UUID, 0, len(sources))
+	for _, source := range sources {
+		if !slices.Contains(merchantIDs, source.MerchantID) {
+			merchantIDs = append(merchantIDs, source.MerchantID)
+		}
+	}
+	maxRadii, err := repo.maxNotificationRadii(ctx, merchantIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	addressQuery := repo.q.AddressModel
+	subscriptionQuery := repo.q.UserMerchantSubscriptionModel
+	profileQuery := repo.q.UserProfileModel
+	merchantQuery := repo.q.MerchantProfileModel
+
+	values := make([]string, len(sources))
+	args := make([]any, 0, len(sources)*8)
+	for idx, source := range sources {
+		bounds := subscriberSearchBounds(source.Latitude, source.Longitude, maxRadii[source.MerchantID])
+		values[idx] = "(?::uuid, ?::double precision, ?::double precision, " +
+			"?::double precision, ?::double precision, ?::double precision, ?::double precision, ?::integer)"
+		args = append(args, source.MerchantID, source.Longitude, source.Latitude,
+			bounds.Min.Lon(), bounds.Min.Lat(), bounds.Max.Lon(), bounds.Max.Lat(), offset+idx)
+	}
+	sourcesJoin := "JOIN (VALUES " + strings.Join(values, ", ") + ")" +
+		" AS subscriber_sources(merchant_id, lng, lat, min_lng, min_lat, max_lng, max_lat, source_index)" +
+		" ON subscriber_sources.merchant_id = user_merchant_subscriptions.merchant_id"
+
+	var candidateModels []*subscriberCandidateModel
+	err = addressQuery.WithContext(ctx).
+		Distinct().
+		Select(
+			addressQuery.ALL,
+			subscriptionQuery.NotificationRadius,
+			subscriptionQuery.SnoozedUntil,
+			profileQuery.BroadcastsSnoozedUntil,
+			merchantQuery.UserID.As("merchant_user_id"),
+			field.NewInt("subscriber_sources", "source_index"),
+		).
+		Join(subscriptionQuery, subscriptionQuery.UserID.EqCol(addressQuery.UserProfileID)).
+		LeftJoin(profileQuery, profileQuery.UserID.EqCol(addressQuery.UserProfileID)).
+		LeftJoin(merchantQuery, merchantQuery.UserID.EqCol(addressQuery.UserProfileID), merchantQuery.DeletedAt.IsNull()).
+		Where(
+			addressQuery.UserProfileID.IsNotNull(),
+			addressQuery.IsActive.Is(true),
+			addressQuery.DeletedAt.IsNull(),
+			subscriptionQuery.IsActive.Is(true),
+			subscriptionQuery.DeletedAt.IsNull(),
+		).UnderlyingDB().
+		Joins(sourcesJoin, args...).
+		Where(fmt.Sprintf(subscriberSearchCondition,
+			"subscriber_sources.min_lng", "subscriber_sources.min_lat", "subscriber_sources.max_lng", "subscriber_sources.max_lat",
+			"subscriber_sources.lng", "subscriber_sources.lat")).
+		Where(fmt.Sprintf(subscriberAreaCondition, "subscriber_sources.lng", "subscriber_sources.lat")).
+		Order("subscriber_sources.source_index, addresses.id").
+		Find(&candidateModels).Error
+
+	if err != nil {
+		return nil, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	candidates := make([]entity.SubscriberCandidate, 0, len(candidateModels))
+	for _, candidateM := range candidateModels {
+		candidates = append(candidates, entity.SubscriberCandidate{
+			SourceIndex: candidateM.SourceIndex,
+			Address:     toSubscriberAddressDomain(&candidateM.subscriberAddressModel),
+		})
+	}
+
+	return candidates, nil
+}
+
+// bucketSubscriberCandidatesByTile groups candidates by the tile of their address, keeping their order within a bucket
+func bucketSubscriberCandidatesByTile(candidates []entity.SubscriberCandidate, zoom int) []*entity.SubscriberTileBucket {
+	buckets := make(map[entity.MapTile]*entity.SubscriberTileBucket)
+	for _, candidate := range candidates {
+		tile := entity.TileAt(candidate.Address.Latitude, candidate.Address.Longitude, zoom)
+		bucket, ok := buckets[tile]
+		if !ok {
+			bucket = &entity.SubscriberTileBucket{Tile: tile}
+			buckets[tile] = bucket
+		}
+		bucket.Candidates = append(bucket.Candidates, candidate)
+	}
+
+	ordered := slices.SortedFunc(maps.Values(buckets), func(a, b *entity.SubscriberTileBucket) int {
+		return cmp.Or(cmp.Compare(a.Tile.Y, b.Tile.Y), cmp.Compare(a.Tile.X, b.Tile.X))
+	})
+
+	return ordered
+}
+
+// FindDevicesForUsers retrieves healthy devices for a list of user IDs that match the target filter.
+func (repo *subscriptionRepository) FindDevicesForUsers(
+	ctx context.Context,
+	userIDs []uuid.UUID,
+	healthyWindowDays int,
+	target repository.DeviceTargetFilter,
+) ([]*entity.UserDevice, error) {
 	if len(userIDs) == 0 {
 		return []*entity.UserDevice{}, nil
 	}
@@ -304,13 +608,18 @@ func (repo *subscriptionRepository) FindDevicesForUsers(ctx context.Context, use
 		ids[i] = id
 	}
 
-	deviceModels, err := repo.q.UserDeviceModel.WithContext(ctx).
+	query := repo.q.UserDeviceModel.WithContext(ctx).
 		Where(
 			repo.q.UserDeviceModel.UserID.In(ids...),
 			repo.q.UserDeviceModel.IsActive.Is(true),
 			repo.q.UserDeviceModel.DeletedAt.IsNull(),
 			repo.q.UserDeviceModel.TokenRefreshedAt.Gt(cutoff),
-		).
+		)
+	if len(target.Platforms) > 0 {
+		query = query.Where(repo.q.UserDeviceModel.Platform.In(target.Platforms...))
+	}
+
+	deviceModels, err := query.
 		Order(repo.q.UserDeviceModel.CreatedAt.Desc()).
 		Find()
 
@@ -318,12 +627,21 @@ func (repo *subscriptionRepository) FindDevicesForUsers(ctx context.Context, use
 		return nil, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
 	}
 
+	return toTargetedDevicesDomain(deviceModels, target.MinAppVersion), nil
+}
+
+// toTargetedDevicesDomain maps device models, dropping those below the minimum app version.
+// Versions are compared in Go because dotted version strings do not order correctly in SQL.
+func toTargetedDevicesDomain(deviceModels []*model.UserDeviceModel, minAppVersion string) []*entity.UserDevice {
 	devices := make([]*entity.UserDevice, 0, len(deviceModels))
 	for _, deviceM := range deviceModels {
+		if !policy.AppVersionAtLeast(deviceM.AppVersion, minAppVersion) {
+			continue
+		}
 		devices = append(devices, toDeviceDomain(deviceM))
 	}
 
-	return devices, nil
+	return devices
 }
 
 // FindSubscriberAddressesByUserIDs retrieves addresses for specific user IDs who subscribe to a merchant.
@@ -341,12 +659,22 @@ func (repo *subscriptionRepository) FindSubscriberAddressesByUserIDs(ctx context
 
 	addressQuery := repo.q.AddressModel
 	subscriptionQuery := repo.q.UserMerchantSubscriptionModel
+	profileQuery := repo.q.UserProfileModel
+	merchantQuery := repo.q.MerchantProfileModel
 
 	var addressModels []*subscriberAddressModel
 	err := addressQuery.WithContext(ctx).
 		Distinct().
-		Select(addressQuery.ALL, subscriptionQuery.NotificationRadius).
+		Select(
+			addressQuery.ALL,
+			subscriptionQuery.NotificationRadius,
+			subscriptionQuery.SnoozedUntil,
+			profileQuery.BroadcastsSnoozedUntil,
+			merchantQuery.UserID.As("merchant_user_id"),
+		).
 		Join(subscriptionQuery, subscriptionQuery.UserID.EqCol(addressQuery.UserProfileID)).
+		LeftJoin(profileQuery, profileQuery.UserID.EqCol(addressQuery.UserProfileID)).
+		LeftJoin(merchantQuery, merchantQuery.UserID.EqCol(addressQuery.UserProfileID), merchantQuery.DeletedAt.IsNull()).
 		Where(
 			addressQuery.UserProfileID.In(ids...),
 			addressQuery.IsActive.Is(true),
@@ -383,6 +711,7 @@ func toSubscriptionDomain(data *model.UserMerchantSubscriptionModel) *entity.Use
 		MerchantID:         data.MerchantID,
 		IsActive:           data.IsActive,
 		NotificationRadius: data.NotificationRadius,
+		SnoozedUntil:       data.SnoozedUntil,
 		SubscribedAt:       data.SubscribedAt,
 		UpdatedAt:          data.UpdatedAt,
 	}
@@ -411,6 +740,7 @@ func fromSubscriptionDomain(data *entity.UserMerchantSubscription) *model.UserMe
 		MerchantID:         data.MerchantID,
 		IsActive:           data.IsActive,
 		NotificationRadius: data.NotificationRadius,
+		SnoozedUntil:       data.SnoozedUntil,
 		SubscribedAt:       data.SubscribedAt,
 		UpdatedAt:          data.UpdatedAt,
 	}
@@ -429,5 +759,19 @@ func toSubscriberAddressDomain(data *subscriberAddressModel) *entity.SubscriberA
 	return &entity.SubscriberAddress{
 		Address:            *address,
 		NotificationRadius: data.NotificationRadius,
+		SnoozedUntil:       latestSnooze(data.SnoozedUntil, data.BroadcastsSnoozedUntil),
+		IsMerchant:         data.MerchantUserID != nil,
+	}
+}
+
+// latestSnooze returns the later of the per-merchant and global snooze times, or nil if neither is set
+func latestSnooze(merchantSnooze, globalSnooze *time.Time) *time.Time {
+	switch {
+	case merchantSnooze == nil:
+		return globalSnooze
+	case globalSnooze == nil || merchantSnooze.After(*globalSnooze):
+		return merchantSnooze
+	default:
+		return globalSnooze
 	}
 }
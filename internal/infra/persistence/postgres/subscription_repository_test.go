@@ -0,0 +1,374 @@
+package postgres
+
+import (
+	"context"
+	"math/rand/v2"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
+	"radar/internal/domain/repository"
+	"radar/internal/infra/persistence/model"
+
+	"github.com/google/uuid"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/encoding/ewkb"
+	"github.com/paulmach/orb/geo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestToSubscriberAddressDomain_MergesSnoozes(t *testing.T) {
+	earlier := time.Now().Add(time.Hour)
+	later := time.Now().Add(2 * time.Hour)
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		merchantSnooze *time.Time
+		globalSnooze   *time.Time
+		want           *time.Time
+	}{
+		{name: "no snooze", want: nil},
+		{name: "merchant snooze only", merchantSnooze: &earlier, want: &earlier},
+		{name: "global snooze applies to any merchant", globalSnooze: &later, want: &later},
+		{name: "later merchant snooze wins", merchantSnooze: &later, globalSnooze: &earlier, want: &later},
+		{name: "later global snooze wins", merchantSnooze: &earlier, globalSnooze: &later, want: &later},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := toSubscriberAddressDomain(&subscriberAddressModel{
+				AddressModel:           model.AddressModel{ID: uuid.New(), UserProfileID: &userID},
+				NotificationRadius:     1000,
+				SnoozedUntil:           tt.merchantSnooze,
+				BroadcastsSnoozedUntil: tt.globalSnooze,
+			})
+
+			require.NotNil(t, address)
+			assert.Equal(t, tt.want, address.SnoozedUntil)
+		})
+	}
+}
+
+func TestToSubscriberAddressDomain_MarksMerchantSubscribers(t *testing.T) {
+	userID := uuid.New()
+
+	consumer := toSubscriberAddressDomain(&subscriberAddressModel{
+		AddressModel: model.AddressModel{ID: uuid.New(), UserProfileID: &userID},
+	})
+	merchant := toSubscriberAddressDomain(&subscriberAddressModel{
+		AddressModel:   model.AddressModel{ID: uuid.New(), UserProfileID: &userID},
+		MerchantUserID: &userID,
+	})
+
+	assert.False(t, consumer.IsMerchant)
+	assert.True(t, merchant.IsMerchant)
+}
+
+func TestToSubscriberAddressDomain_DecodesGeofence(t *testing.T) {
+	userID := uuid.New()
+	polygon := orb.Polygon{{{121.55, 25.02}, {121.58, 25.02}, {121.58, 25.05}, {121.55, 25.02}}}
+	stored, err := ewkb.MarshalToHex(polygon, 4326)
+	require.NoError(t, err)
+
+	// PostGIS returns the column as hex EWKB text
+	var fencedM model.AddressModel
+	require.NoError(t, fencedM.GeofencePolygon.Scan(stored))
+	fencedM.ID, fencedM.UserProfileID = uuid.New(), &userID
+	var unfencedM model.AddressModel
+	require.NoError(t, unfencedM.GeofencePolygon.Scan(nil))
+	unfencedM.ID, unfencedM.UserProfileID = uuid.New(), &userID
+
+	fenced := toSubscriberAddressDomain(&subscriberAddressModel{AddressModel: fencedM})
+	unfenced := toSubscriberAddressDomain(&subscriberAddressModel{AddressModel: unfencedM})
+
+	require.True(t, fenced.HasGeofence())
+	assert.Len(t, fenced.GeofencePolygon[0], 4)
+	assert.True(t, fenced.InGeofence(25.03, 121.57))
+	assert.False(t, fenced.InGeofence(25.06, 121.57))
+	assert.False(t, unfenced.HasGeofence())
+
+	// A geometry that is not a polygon fails the scan instead of reading as no geofence
+	point, err := ewkb.MarshalToHex(orb.Point{121.55, 25.02}, 4326)
+	require.NoError(t, err)
+	var malformed model.Polygon
+	require.Error(t, malformed.Scan(point))
+}
+
+func TestSubscriptionRepository_FindSubscriberAddressesWithinRadius_MatchesGeofence(t *testing.T) {
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	repo := NewSubscriptionRepository(db)
+
+	_, err = repo.FindSubscriberAddressesWithinRadius(context.Background(), uuid.New(), 25.033, 121.5654)
+	require.NoError(t, err)
+	require.Len(t, sqlLogger.queries, 2)
+
+	sql := strings.ReplaceAll(sqlLogger.queries[1], `"`, "")
+	// The polygon column comes back with addresses.* and is decoded into the address
+	assert.Contains(t, sql, "SELECT DISTINCT addresses.*,")
+	// Addresses with a polygon match from inside it; the rest keep the radius
+	assert.Contains(t, sql, "((addresses.geofence_polygon IS NULL AND ST_DWithin(addresses.location::geography, ST_SetSRID(ST_MakePoint(121.5654, 25.033), 4326)::geography, user_merchant_subscriptions.notification_radius)) OR "+
+		"ST_Covers(addresses.geofence_polygon, ST_SetSRID(ST_MakePoint(121.5654, 25.033), 4326)))")
+}
+
+func TestSubscriptionRepository_FindSubscriberAddressesWithinRadius_PrefiltersBySearchBox(t *testing.T) {
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	// Dry runs read no rows, so answer the largest radius lookup as if the merchant had a 2km subscription
+	const maxRadius = 2000.0
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:max_radius", func(tx *gorm.DB) {
+		rows := reflect.ValueOf(tx.Statement.Dest).Elem()
+		if rows.Kind() != reflect.Slice || rows.Type().Elem().Kind() != reflect.Struct {
+			return
+		}
+		row := reflect.New(rows.Type().Elem()).Elem()
+		if radius := row.FieldByName("MaxRadius"); radius.IsValid() {
+			radius.SetFloat(maxRadius)
+			rows.Set(reflect.Append(rows, row))
+		}
+	}))
+
+	repo := NewSubscriptionRepository(db)
+	merchantID := uuid.New()
+
+	_, err = repo.FindSubscriberAddressesWithinRadius(context.Background(), merchantID, 25.033, 121.5654)
+	require.NoError(t, err)
+	require.Len(t, sqlLogger.queries, 2)
+
+	// The box is sized by the merchant's largest radius, read first
+	radiusSQL := strings.ReplaceAll(sqlLogger.queries[0], `"`, "")
+	assert.Contains(t, radiusSQL, "SELECT coalesce(max(notification_radius), 0) AS max_radius FROM user_merchant_subscriptions")
+	assert.Contains(t, radiusSQL, "user_merchant_subscriptions.merchant_id = '"+merchantID.String()+"'")
+	assert.Contains(t, radiusSQL, "user_merchant_subscriptions.is_active = true")
+
+	// The address query is narrowed to the box around that radius before the exact distance check;
+	// geofenced addresses still match through their polygon
+	bounds := subscriberSearchBounds(25.033, 121.5654, maxRadius)
+	require.Greater(t, bounds.Max.Lat()-bounds.Min.Lat(), 0.0)
+	formatCoord := func(value float64) string { return strconv.FormatFloat(value, 'f', -1, 64) }
+	sql := strings.ReplaceAll(sqlLogger.queries[1], `"`, "")
+	assert.Contains(t, sql, "AND ((addresses.location && ST_MakeEnvelope("+
+		formatCoord(bounds.Min.Lon())+", "+formatCoord(bounds.Min.Lat())+", "+formatCoord(bounds.Max.Lon())+", "+formatCoord(bounds.Max.Lat())+", 4326) OR "+
+		"addresses.geofence_polygon && ST_SetSRID(ST_MakePoint(121.5654, 25.033), 4326))) "+
+		"AND (((addresses.geofence_polygon IS NULL AND ST_DWithin(")
+}
+
+// seededSubscriberPoints scatters points around center, a share of them close to the edge of their radius
+func seededSubscriberPoints(seed uint64, center orb.Point, count int, maxRadius float64) ([]orb.Point, []float64) {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	points := make([]orb.Point, count)
+	radii := make([]float64, count)
+	for idx := range points {
+		radii[idx] = 100 + rng.Float64()*(maxRadius-100)
+		distance := rng.Float64() * maxRadius * 1.5
+		if idx%4 == 0 {
+			distance = radii[idx] * (0.99 + rng.Float64()*0.02)
+		}
+		points[idx] = geo.PointAtBearingAndDistance(center, rng.Float64()*360, distance)
+	}
+
+	return points, radii
+}
+
+func TestSubscriberSearchBounds_MatchesBruteForce(t *testing.T) {
+	for _, center := range []orb.Point{{121.5654, 25.033}, {174.7633, -36.8485}, {18.9553, 69.6492}} {
+		const maxRadius = 5000.0
+		points, radii := seededSubscriberPoints(uint64(center[1]*1000), center, 20000, maxRadius)
+		bounds := subscriberSearchBounds(center.Lat(), center.Lon(), maxRadius)
+
+		var inRadius, prefiltered, candidates int
+		for idx, point := range points {
+			within := geo.Distance(center, point) <= radii[idx]
+			if within {
+				inRadius++
+			}
+			if !bounds.Contains(point) {
+				assert.False(t, within, "point %v within its radius is outside the search box", point)
+
+				continue
+			}
+			candidates++
+			if within {
+				prefiltered++
+			}
+		}
+
+		assert.Equal(t, inRadius, prefiltered, "%v", center)
+		assert.Less(t, candidates, len(points), "the box narrows the candidates around %v", center)
+	}
+}
+
+func TestSubscriptionRepository_UpdateNotificationRadiusByUser_OnlyUpdatesActiveSubscriptions(t *testing.T) {
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	repo := NewSubscriptionRepository(db)
+	userID := uuid.New()
+
+	_, err = repo.UpdateNotificationRadiusByUser(context.Background(), userID, 2500)
+	require.NoError(t, err)
+	require.Len(t, sqlLogger.queries, 1)
+
+	// Inactive and deleted subscriptions keep their radius
+	sql := strings.ReplaceAll(sqlLogger.queries[0], `"`, "")
+	assert.Contains(t, sql, "UPDATE user_merchant_subscriptions SET notification_radius=2500")
+	assert.Contains(t, sql, "WHERE user_merchant_subscriptions.user_id = '"+userID.String()+"' "+
+		"AND user_merchant_subscriptions.is_active = true AND user_merchant_subscriptions.deleted_at IS NULL")
+}
+
+func TestSubscriptionRepository_UpdateGlobalSnoozedUntil_MissingProfile(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	require.NoError(t, err)
+
+	repo := NewSubscriptionRepository(db)
+	until := time.Now().Add(time.Hour)
+
+	// A dry run updates no profile row, as for a user who has none
+	err = repo.UpdateGlobalSnoozedUntil(context.Background(), uuid.New(), &until)
+
+	require.ErrorIs(t, err, domainerrors.ErrUserProfileNotFound)
+	assert.NotErrorIs(t, err, domainerrors.ErrUserNotFound)
+}
+
+func TestToTargetedDevicesDomain_FiltersByMinAppVersion(t *testing.T) {
+	current := &model.UserDeviceModel{ID: uuid.New(), Platform: "ios", AppVersion: "2.4.0"}
+	newer := &model.UserDeviceModel{ID: uuid.New(), Platform: "android", AppVersion: "2.10.1"}
+	outdated := &model.UserDeviceModel{ID: uuid.New(), Platform: "android", AppVersion: "2.3.9"}
+	unreported := &model.UserDeviceModel{ID: uuid.New(), Platform: "web"}
+	deviceModels := []*model.UserDeviceModel{current, newer, outdated, unreported}
+
+	tests := []struct {
+		name          string
+		minAppVersion string
+		want          []uuid.UUID
+	}{
+		{name: "no minimum keeps every device", want: []uuid.UUID{current.ID, newer.ID, outdated.ID, unreported.ID}},
+		{name: "minimum drops outdated and unreported versions", minAppVersion: "2.4.0", want: []uuid.UUID{current.ID, newer.ID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			devices := toTargetedDevicesDomain(deviceModels, tt.minAppVersion)
+
+			ids := make([]uuid.UUID, 0, len(devices))
+			for _, device := range devices {
+				ids = append(ids, device.ID)
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func TestBucketSubscriberCandidatesByTile(t *testing.T) {
+	subscriberAt := func(lat, lng float64) *entity.SubscriberAddress {
+		return &entity.SubscriberAddress{Address: entity.Address{ID: uuid.New(), Latitude: lat, Longitude: lng}}
+	}
+	taipei101 := subscriberAt(25.0330, 121.5654)
+	xinyi := subscriberAt(25.0335, 121.5660) // Same zoom 14 tile as taipei101
+	mainStation := subscriberAt(25.0478, 121.5170)
+	penghu := subscriberAt(23.5711, 119.5793)
+
+	buckets := bucketSubscriberCandidatesByTile([]entity.SubscriberCandidate{
+		{SourceIndex: 0, Address: taipei101},
+		{SourceIndex: 0, Address: mainStation},
+		{SourceIndex: 1, Address: xinyi},
+		{SourceIndex: 1, Address: taipei101},
+		{SourceIndex: 2, Address: penghu},
+	}, 14)
+
+	require.Len(t, buckets, 3)
+
+	// Buckets run north to south, then west to east
+	assert.Equal(t, entity.MapTile{Zoom: 14, X: 13722, Y: 7013}, buckets[0].Tile)
+	assert.Equal(t, []entity.SubscriberCandidate{{SourceIndex: 0, Address: mainStation}}, buckets[0].Candidates)
+
+	// Both sources with subscribers in the Xinyi tile share its bucket, and an address keeps one entry per source
+	assert.Equal(t, entity.MapTile{Zoom: 14, X: 13724, Y: 7014}, buckets[1].Tile)
+	assert.Equal(t, []entity.SubscriberCandidate{
+		{SourceIndex: 0, Address: taipei101},
+		{SourceIndex: 1, Address: xinyi},
+		{SourceIndex: 1, Address: taipei101},
+	}, buckets[1].Candidates)
+
+	assert.Equal(t, entity.MapTile{Zoom: 14, X: 13634, Y: 7087}, buckets[2].Tile)
+	assert.Equal(t, []entity.SubscriberCandidate{{SourceIndex: 2, Address: penghu}}, buckets[2].Candidates)
+
+	// A coarser zoom merges the Taipei tiles
+	assert.Len(t, bucketSubscriberCandidatesByTile([]entity.SubscriberCandidate{
+		{SourceIndex: 0, Address: taipei101},
+		{SourceIndex: 0, Address: mainStation},
+	}, 10), 1)
+}
+
+func TestSubscriptionRepository_FindSubscriberAddressesByTile_BatchesSources(t *testing.T) {
+	sqlLogger := &captureSQLLogger{}
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  "host=localhost user=test password=test dbname=test sslmode=disable",
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true, Logger: sqlLogger})
+	require.NoError(t, err)
+
+	repo := NewSubscriptionRepository(db)
+
+	buckets, err := repo.FindSubscriberAddressesByTile(context.Background(), nil, 14)
+	require.NoError(t, err)
+	assert.Empty(t, buckets)
+	require.Empty(t, sqlLogger.queries, "no sources means no query")
+
+	sources := make([]repository.SubscriberSource, subscriberSourceBatchSize+1)
+	for idx := range sources {
+		sources[idx] = repository.SubscriberSource{MerchantID: uuid.New(), Latitude: 25.0330, Longitude: 121.5654}
+	}
+
+	_, err = repo.FindSubscriberAddressesByTile(context.Background(), sources, 14)
+	require.NoError(t, err)
+	require.Len(t, sqlLogger.queries, 4, "a radius and a candidate query per batch of sources")
+
+	// Each batch first reads the largest radius of every merchant in it
+	radiusSQL := strings.ReplaceAll(sqlLogger.queries[0], `"`, "")
+	assert.Contains(t, radiusSQL, "SELECT user_merchant_subscriptions.merchant_id,coalesce(max(notification_radius), 0) AS max_radius FROM user_merchant_subscriptions")
+	assert.Contains(t, radiusSQL, "user_merchant_subscriptions.merchant_id IN ('"+sources[0].MerchantID.String()+"'")
+	assert.Contains(t, radiusSQL, "GROUP BY user_merchant_subscriptions.merchant_id")
+
+	sql := strings.ReplaceAll(sqlLogger.queries[1], `"`, "")
+	assert.Contains(t, sql, "AS subscriber_sources(merchant_id, lng, lat, min_lng, min_lat, max_lng, max_lat, source_index)")
+	assert.Contains(t, sql, "subscriber_sources.merchant_id = user_merchant_subscriptions.merchant_id")
+	// Every source is narrowed to its own box before the exact radius check
+	assert.Contains(t, sql, "(addresses.location && ST_MakeEnvelope(subscriber_sources.min_lng, subscriber_sources.min_lat, subscriber_sources.max_lng, subscriber_sources.max_lat, 4326) OR "+
+		"addresses.geofence_polygon && ST_SetSRID(ST_MakePoint(subscriber_sources.lng, subscriber_sources.lat), 4326))")
+	assert.Contains(t, sql, "ST_MakePoint(subscriber_sources.lng, subscriber_sources.lat)")
+	assert.Contains(t, sql, "ORDER BY subscriber_sources.source_index, addresses.id")
+	assert.Equal(t, subscriberSourceBatchSize, strings.Count(sql, "::uuid"))
+	// Dry runs read no subscriptions, so each box shrinks to its source point
+	assert.Contains(t, sql, "('"+sources[0].MerchantID.String()+"'::uuid, 121.5654::double precision, 25.033::double precision, "+
+		"121.5654::double precision, 25.033::double precision, 121.5654::double precision, 25.033::double precision, 0::integer)")
+
+	// The second batch carries the remaining source with its index in the full list
+	assert.Equal(t, 1, strings.Count(sqlLogger.queries[3], "::uuid"))
+	assert.Contains(t, sqlLogger.queries[3], "200::integer")
+}
@@ -170,6 +170,22 @@ func (repo *userRepository) Update(ctx context.Context, user *entity.User) error
 	return nil
 }
 
+// IsBusinessLicenseTaken reports whether an active merchant profile other than excludeUserID
+// already holds the business license. Soft-deleted profiles are ignored, matching the unique index.
+func (repo *userRepository) IsBusinessLicenseTaken(ctx context.Context, businessLicense string, excludeUserID uuid.UUID) (bool, error) {
+	merchantProfile := repo.q.MerchantProfileModel
+
+	count, err := merchantProfile.WithContext(ctx).
+		Where(merchantProfile.BusinessLicense.Eq(businessLicense)).
+		Where(merchantProfile.UserID.Neq(excludeUserID)).
+		Count()
+	if err != nil {
+		return false, replaceWithSourceStack(err, domainerrors.ErrPersistenceFailed)
+	}
+
+	return count > 0, nil
+}
+
 // --- Mapper Functions ---
 // These helpers convert between domain entities and persistence models.
 
@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"log/slog"
 	"math"
+	"runtime"
 	"sync"
 	"time"
 
 	"radar/internal/infra/routing/loader"
+	"radar/internal/usecase"
 )
 
 // ErrSnapDistanceExceeded is returned when a coordinate is too far from the road network
@@ -22,6 +24,9 @@ var ErrEngineNotReady = errors.New("routing engine not ready")
 // ErrUnreachable is returned when no route exists between two points
 var ErrUnreachable = errors.New("destination is unreachable")
 
+// ErrDanglingEdges is returned when too many edges or shortcuts reference vertices outside the loaded range
+var ErrDanglingEdges = errors.New("routing graph has too many dangling edges")
+
 // Coordinate represents a geographic coordinate
 type Coordinate struct {
 	Lat float64
@@ -35,6 +40,9 @@ type RouteResult struct {
 	Distance    float64       // Road network distance in meters
 	Duration    time.Duration // Estimated travel time
 	IsReachable bool          // Whether the destination is reachable via road network
+
+	// Why the destination is unreachable; empty when reachable
+	UnreachableReason usecase.UnreachableReason
 }
 
 // NearestNodeResult represents the result of finding the nearest road network node
@@ -48,12 +56,47 @@ type NearestNodeResult struct {
 
 // EngineConfig holds configuration for the routing engine
 type EngineConfig struct {
-	MaxSnapDistanceMeters     float64 // Maximum distance to snap GPS to road network
-	DefaultSpeedKmH           float64 // Default speed for ETA calculation
+	MaxSnapDistanceMeters     float64 // Maximum distance to snap GPS to road network, for profiles that set none
+	DefaultSpeedKmH           float64 // Speed for ETA calculation, for profiles that set none
 	MaxQueryRadiusMeters      float64 // Maximum query radius
-	OneToManyWorkers          int     // Concurrent workers for One-to-Many
+	OneToManyWorkers          int     // Most concurrent workers for One-to-Many
+	WorkersPerCPU             int     // Most One-to-Many workers per GOMAXPROCS, as the searches are CPU-bound (0 disables)
 	PreFilterRadiusMultiplier float64 // Haversine pre-filter multiplier
 	GridCellSizeKm            float64 // Grid cell size for spatial index
+	MaxSkippedEdgeFraction    float64 // Fraction of edges and shortcuts with out-of-range endpoints tolerated at load
+
+	// Travel profiles queries can select, and the one ProfileDefault selects
+	Profiles       map[Profile]ProfileSettings
+	DefaultProfile Profile
+
+	// Map graph.bin from the data directory instead of parsing the CSV files; suited to country-scale graphs
+	MemoryMap bool
+
+	// Answer queries with plain Dijkstra over every arc instead of the CH query, for debugging contracted data
+	ForceDijkstra bool
+
+	// Honor restrictions.csv. The CH query does not model turns, so every query then runs the slower
+	// turn-aware Dijkstra search; when unset the file is ignored and routes may use forbidden turns.
+	TurnRestrictions bool
+}
+
+// LoadReport summarizes how the loaded edges and shortcuts were added to the graph
+type LoadReport struct {
+	Edges            int // Edges read from edges.csv
+	Shortcuts        int // Shortcuts read from shortcuts.csv
+	SkippedEdges     int // Edges skipped because an endpoint is outside the vertex range
+	SkippedShortcuts int // Shortcuts skipped because an endpoint is outside the vertex range
+	Restrictions     int // Turn restrictions applied from restrictions.csv; 0 unless TurnRestrictions is set
+}
+
+// SkippedFraction returns the share of edges and shortcuts that were skipped
+func (r LoadReport) SkippedFraction() float64 {
+	total := r.Edges + r.Shortcuts
+	if total == 0 {
+		return 0
+	}
+
+	return float64(r.SkippedEdges+r.SkippedShortcuts) / float64(total)
 }
 
 // DefaultEngineConfig returns sensible defaults for Taiwan
@@ -63,35 +106,37 @@ func DefaultEngineConfig() EngineConfig {
 		DefaultSpeedKmH:           30,    // 30 km/h urban scooter speed
 		MaxQueryRadiusMeters:      10000, // 10 km
 		OneToManyWorkers:          20,
+		WorkersPerCPU:             2,
 		PreFilterRadiusMultiplier: 1.3,
-		GridCellSizeKm:            1.0, // 1km grid cells
+		GridCellSizeKm:            1.0,  // 1km grid cells
+		MaxSkippedEdgeFraction:    0.01, // 1% dangling references
+		Profiles:                  DefaultProfiles(),
+		DefaultProfile:            ProfileScooter,
 	}
 }
 
 // Engine wraps the CH routing functionality
 type Engine struct {
-	config    EngineConfig
-	spatial   *GridIndex
-	vertices  []loader.Vertex
-	edges     []loader.Edge
-	shortcuts []loader.Shortcut
-	metadata  *loader.RoutingMetadata
-	logger    *slog.Logger
-	ready     bool
-	mu        sync.RWMutex
+	config   EngineConfig
+	spatial  *GridIndex
+	metadata *loader.RoutingMetadata
+	report   LoadReport
+	logger   *slog.Logger
+	ready    bool
+	mu       sync.RWMutex
 
-	// Adjacency list for graph traversal
-	// adjList[v] contains pairs of (neighbor_vertex, edge_weight)
-	adjList [][]edgeEntry
+	// Vertices and their adjacency for graph traversal, on the heap or in a mapping
+	graph *loader.CSRGraph
 
-	// CH graph integration will be added when LdDl/ch is imported
-	// chGraph   *ch.Graph
-	// queryPool *ch.QueryPool
-}
+	// Forbidden turns; empty when the data was converted without restrictions
+	restrictions map[loader.Restriction]struct{}
 
-type edgeEntry struct {
-	to     int
-	weight float64
+	// Reversed downward arcs for the backward CH search; nil when the data has no shortcuts
+	downward *downwardGraph
+
+	// Mappings made by LoadData. Queries may still hold a graph from an earlier load,
+	// so mappings are only released by Close.
+	mappings []*loader.MappedGraph
 }
 
 // NewEngine creates a new routing engine instance
@@ -104,15 +149,18 @@ func NewEngine(config EngineConfig, logger *slog.Logger) *Engine {
 		config: config,
 		logger: logger,
 		ready:  false,
+		graph:  &loader.CSRGraph{Offsets: []int64{0}},
 	}
 }
 
-// LoadData loads routing data from the specified directory
+// LoadData loads routing data from the specified directory: the CSV files by default,
+// or the memory-mapped graph.bin when MemoryMap is set
 func (e *Engine) LoadData(dataDir string) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
 	e.ready = false
+	startedAt := time.Now()
 
 	// Load metadata
 	metadata, err := loader.LoadMetadata(dataDir)
@@ -127,68 +175,154 @@ func (e *Engine) LoadData(dataDir string) error {
 	}
 
 	// Load graph data
-	csvLoader := loader.NewCSVLoader(dataDir)
-	graphData, err := csvLoader.Load()
+	graph, restrictions, err := e.loadGraph(dataDir)
 	if err != nil {
-		return fmt.Errorf("load routing graph data: %w", err)
+		return err
 	}
+	e.graph = graph
+	e.restrictions = restrictionSet(restrictions)
 
-	e.vertices = graphData.Vertices
-	e.edges = graphData.Edges
-	e.shortcuts = graphData.Shortcuts
+	// The CH query needs the contraction; uncontracted data is searched with Dijkstra
+	e.downward = nil
+	if graph.Shortcuts > graph.SkippedShortcuts {
+		e.downward = buildDownwardGraph(graph)
+	}
 
 	// Build spatial index
 	e.spatial = NewGridIndex(e.config.GridCellSizeKm)
-	e.spatial.Build(e.vertices)
+	e.spatial.Build(e.graph.Vertices)
 
-	// Build adjacency list
-	e.buildAdjacencyList()
+	e.report = LoadReport{
+		Edges:            graph.Edges,
+		Shortcuts:        graph.Shortcuts,
+		SkippedEdges:     graph.SkippedEdges,
+		SkippedShortcuts: graph.SkippedShortcuts,
+		Restrictions:     len(restrictions),
+	}
+	if err := e.checkLoadReport(); err != nil {
+		return err
+	}
 
 	// Log startup info
 	e.logMetadata()
+	if len(e.restrictions) > 0 && e.downward != nil {
+		e.logger.Warn("Turn restrictions disable the CH query, so every route is searched with turn-aware Dijkstra",
+			"restrictions", e.report.Restrictions,
+			"shortcuts", e.report.Shortcuts,
+		)
+	}
 
 	e.ready = true
 	e.logger.Info("Routing engine loaded successfully",
-		"vertices", len(e.vertices),
-		"edges", len(e.edges),
-		"shortcuts", len(e.shortcuts),
+		"vertices", len(e.graph.Vertices),
+		"edges", e.report.Edges,
+		"shortcuts", e.report.Shortcuts,
+		"skipped_edges", e.report.SkippedEdges,
+		"skipped_shortcuts", e.report.SkippedShortcuts,
+		"restrictions", e.report.Restrictions,
+		"query", e.queryKind(),
+		"memory_mapped", e.config.MemoryMap,
+		"load_duration", time.Since(startedAt),
+		"graph_bytes", e.graph.SizeBytes(),
+		"resident_bytes", e.residentBytes(),
 	)
 
 	return nil
 }
 
-func (e *Engine) buildAdjacencyList() {
-	if len(e.vertices) == 0 {
-		return
+// loadGraph maps the binary graph when MemoryMap is set and otherwise parses the CSV files.
+// The binary graph has no restrictions section, so they are always read from restrictions.csv.
+func (e *Engine) loadGraph(dataDir string) (*loader.CSRGraph, []loader.Restriction, error) {
+	csvLoader := loader.NewCSVLoader(dataDir)
+
+	var (
+		graph        *loader.CSRGraph
+		restrictions []loader.Restriction
+	)
+	if e.config.MemoryMap {
+		var err error
+		restrictions, err = csvLoader.LoadRestrictions()
+		if err != nil {
+			return nil, nil, fmt.Errorf("load routing restrictions: %w", err)
+		}
+
+		mapped, err := loader.MapBinaryGraph(dataDir)
+		if err != nil {
+			return nil, nil, fmt.Errorf("map routing graph: %w", err)
+		}
+		e.mappings = append(e.mappings, mapped)
+		graph = mapped.Graph
+	} else {
+		graphData, err := csvLoader.Load()
+		if err != nil {
+			return nil, nil, fmt.Errorf("load routing graph data: %w", err)
+		}
+		graph, restrictions = loader.BuildCSRGraph(graphData), graphData.Restrictions
+	}
+
+	if !e.config.TurnRestrictions && len(restrictions) > 0 {
+		e.logger.Info("Ignoring turn restrictions because they are not enabled; routes may use forbidden turns",
+			"restrictions", len(restrictions),
+		)
+
+		return graph, nil, nil
 	}
 
-	e.adjList = make([][]edgeEntry, len(e.vertices))
-	e.addEdgesToAdjList()
-	e.addShortcutsToAdjList()
+	return graph, restrictions, nil
 }
 
-func (e *Engine) addEdgesToAdjList() {
-	for _, edge := range e.edges {
-		from := int(edge.From)
-		toNode := int(edge.To)
-		if e.isValidVertexRange(from, toNode) {
-			e.adjList[from] = append(e.adjList[from], edgeEntry{to: toNode, weight: edge.Weight})
-		}
+// restrictionSet indexes restrictions for lookup during relaxation
+func restrictionSet(restrictions []loader.Restriction) map[loader.Restriction]struct{} {
+	set := make(map[loader.Restriction]struct{}, len(restrictions))
+	for _, restriction := range restrictions {
+		set[restriction] = struct{}{}
 	}
+
+	return set
 }
 
-func (e *Engine) addShortcutsToAdjList() {
-	for _, shortcut := range e.shortcuts {
-		from := int(shortcut.From)
-		toNode := int(shortcut.To)
-		if e.isValidVertexRange(from, toNode) {
-			e.adjList[from] = append(e.adjList[from], edgeEntry{to: toNode, weight: shortcut.Weight})
-		}
+// residentBytes returns how much of the graph is in physical memory: the whole graph when it was parsed
+// onto the heap, or the pages of the mapping read so far
+func (e *Engine) residentBytes() int64 {
+	if !e.config.MemoryMap || len(e.mappings) == 0 {
+		return e.graph.SizeBytes()
 	}
+
+	resident, ok := e.mappings[len(e.mappings)-1].ResidentBytes()
+	if !ok {
+		return -1
+	}
+
+	return resident
+}
+
+// checkLoadReport warns about dangling edges and fails the load when they exceed the configured fraction
+func (e *Engine) checkLoadReport() error {
+	if e.report.SkippedEdges == 0 && e.report.SkippedShortcuts == 0 {
+		return nil
+	}
+
+	fraction := e.report.SkippedFraction()
+	e.logger.Warn("Skipped edges with out-of-range vertices",
+		"skipped_edges", e.report.SkippedEdges,
+		"skipped_shortcuts", e.report.SkippedShortcuts,
+		"skipped_fraction", fraction,
+	)
+
+	if fraction > e.config.MaxSkippedEdgeFraction {
+		return fmt.Errorf("%w: skipped %d of %d edges and %d of %d shortcuts (%.2f%%, limit %.2f%%)",
+			ErrDanglingEdges,
+			e.report.SkippedEdges, e.report.Edges,
+			e.report.SkippedShortcuts, e.report.Shortcuts,
+			fraction*100, e.config.MaxSkippedEdgeFraction*100,
+		)
+	}
+
+	return nil
 }
 
 func (e *Engine) isValidVertexRange(from, toNode int) bool {
-	return from >= 0 && from < len(e.vertices) && toNode >= 0 && toNode < len(e.vertices)
+	return from >= 0 && from < len(e.graph.Vertices) && toNode >= 0 && toNode < len(e.graph.Vertices)
 }
 
 func (e *Engine) logMetadata() {
@@ -217,8 +351,18 @@ func (e *Engine) IsReady() bool {
 	return e.ready
 }
 
-// FindNearestNode finds the nearest road network node to a coordinate
+// FindNearestNode finds the nearest road network node to a coordinate, within the default profile's snap distance
 func (e *Engine) FindNearestNode(ctx context.Context, coord Coordinate) (*NearestNodeResult, error) {
+	settings, err := e.profileSettings(ProfileDefault)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.snap(ctx, coord, settings.MaxSnapDistanceMeters)
+}
+
+// snap finds the nearest road network node to a coordinate, failing when it is farther than maxDistanceMeters
+func (e *Engine) snap(_ context.Context, coord Coordinate, maxDistanceMeters float64) (*NearestNodeResult, error) {
 	if !e.IsReady() {
 		return nil, ErrEngineNotReady
 	}
@@ -242,7 +386,7 @@ func (e *Engine) FindNearestNode(ctx context.Context, coord Coordinate) (*Neares
 		Distance: distance,
 		NodeLat:  vertex.Lat,
 		NodeLng:  vertex.Lng,
-		IsValid:  distance <= e.config.MaxSnapDistanceMeters,
+		IsValid:  distance <= maxDistanceMeters,
 	}
 
 	if !result.IsValid {
@@ -252,35 +396,40 @@ func (e *Engine) FindNearestNode(ctx context.Context, coord Coordinate) (*Neares
 	return result, nil
 }
 
-// ShortestPath calculates the shortest path between two coordinates
-func (e *Engine) ShortestPath(ctx context.Context, from, target Coordinate) (*RouteResult, error) {
+// ShortestPath calculates the shortest path between two coordinates, snapping and timing it for profile
+func (e *Engine) ShortestPath(ctx context.Context, profile Profile, from, target Coordinate) (*RouteResult, error) {
 	if !e.IsReady() {
 		return nil, ErrEngineNotReady
 	}
 
+	settings, err := e.profileSettings(profile)
+	if err != nil {
+		return nil, err
+	}
+
 	// Snap source
-	srcNode, err := e.FindNearestNode(ctx, from)
+	srcNode, err := e.snap(ctx, from, settings.MaxSnapDistanceMeters)
 	if err != nil {
-		return &RouteResult{IsReachable: false}, err
+		return &RouteResult{IsReachable: false, UnreachableReason: usecase.UnreachableReasonOffNetwork}, err
 	}
 
 	// Snap target
-	dstNode, err := e.FindNearestNode(ctx, target)
+	dstNode, err := e.snap(ctx, target, settings.MaxSnapDistanceMeters)
 	if err != nil {
-		return &RouteResult{IsReachable: false}, err
+		return &RouteResult{IsReachable: false, UnreachableReason: usecase.UnreachableReasonOffNetwork}, err
 	}
 
-	// Calculate shortest path using Dijkstra (placeholder until CH integration)
-	distance, reachable := e.dijkstra(srcNode.NodeID, dstNode.NodeID)
+	distance, reachable := e.route(srcNode.NodeID, dstNode.NodeID)
 
 	if !reachable {
 		return &RouteResult{
-			IsReachable: false,
+			IsReachable:       false,
+			UnreachableReason: usecase.UnreachableReasonDisconnected,
 		}, nil
 	}
 
 	// Calculate ETA
-	duration := e.calculateDuration(distance)
+	duration := calculateDuration(distance, settings.SpeedKmH)
 
 	return &RouteResult{
 		Distance:    distance,
@@ -295,16 +444,26 @@ type snapResult struct {
 	targetNode  int
 }
 
-// OneToMany calculates routes from one source to multiple targets
-func (e *Engine) OneToMany(ctx context.Context, from Coordinate, targets []Coordinate) ([]RouteResult, error) {
+// OneToMany calculates routes from one source to multiple targets, snapping and timing them for profile
+func (e *Engine) OneToMany(ctx context.Context, profile Profile, from Coordinate, targets []Coordinate) ([]RouteResult, error) {
 	if !e.IsReady() {
 		return nil, ErrEngineNotReady
 	}
 
+	settings, err := e.profileSettings(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nothing to route; skip snapping the source so an off-network source cannot fail an empty query
+	if len(targets) == 0 {
+		return []RouteResult{}, nil
+	}
+
 	results := make([]RouteResult, len(targets))
 
 	// Snap source (fail fast if source is invalid)
-	srcNode, err := e.FindNearestNode(ctx, from)
+	srcNode, err := e.snap(ctx, from, settings.MaxSnapDistanceMeters)
 	if err != nil {
 		return e.markAllUnreachable(results), err
 	}
@@ -315,34 +474,44 @@ func (e *Engine) OneToMany(ctx context.Context, from Coordinate, targets []Coord
 
 	// Mark non-candidates as unreachable
 	for idx := range results {
-		results[idx] = RouteResult{TargetIdx: idx, IsReachable: false}
+		results[idx] = RouteResult{TargetIdx: idx, IsReachable: false, UnreachableReason: usecase.UnreachableReasonOutOfRange}
 	}
 
 	// Snap targets and prepare for routing
-	snapped := e.snapTargets(ctx, candidateIdxs, targets)
+	snapped := e.snapTargets(ctx, candidateIdxs, targets, results, settings.MaxSnapDistanceMeters)
 	if len(snapped) == 0 {
 		return results, nil
 	}
 
 	// Route to all snapped targets using worker pool
-	return e.routeWithWorkerPool(ctx, srcNode.NodeID, snapped, results)
+	return e.routeWithWorkerPool(ctx, srcNode.NodeID, snapped, results, settings.SpeedKmH)
 }
 
+// markAllUnreachable marks every target unreachable because the source could not be snapped
 func (e *Engine) markAllUnreachable(results []RouteResult) []RouteResult {
 	for idx := range results {
-		results[idx] = RouteResult{TargetIdx: idx, IsReachable: false}
+		results[idx] = RouteResult{TargetIdx: idx, IsReachable: false, UnreachableReason: usecase.UnreachableReasonOffNetwork}
 	}
 
 	return results
 }
 
-func (e *Engine) snapTargets(ctx context.Context, candidateIdxs []int, targets []Coordinate) []snapResult {
+// snapTargets snaps the candidate targets, marking those that cannot be snapped as off-network in results
+func (e *Engine) snapTargets(
+	ctx context.Context,
+	candidateIdxs []int,
+	targets []Coordinate,
+	results []RouteResult,
+	maxSnapDistanceMeters float64,
+) []snapResult {
 	snapped := make([]snapResult, 0, len(candidateIdxs))
 
 	for _, idx := range candidateIdxs {
-		nearestNode, snapErr := e.FindNearestNode(ctx, targets[idx])
+		nearestNode, snapErr := e.snap(ctx, targets[idx], maxSnapDistanceMeters)
 		if snapErr != nil {
 			// Target too far from road network
+			results[idx].UnreachableReason = usecase.UnreachableReasonOffNetwork
+
 			continue
 		}
 		snapped = append(snapped, snapResult{originalIdx: idx, targetNode: nearestNode.NodeID})
@@ -351,29 +520,48 @@ func (e *Engine) snapTargets(ctx context.Context, candidateIdxs []int, targets [
 	return snapped
 }
 
-func (e *Engine) routeWithWorkerPool(ctx context.Context, srcNodeID int, snapped []snapResult, results []RouteResult) ([]RouteResult, error) {
-	workerCount := min(e.config.OneToManyWorkers, len(snapped))
-	if workerCount <= 0 {
-		return results, nil
+// oneToManyWorkers sizes the worker pool for a query: no more workers than targets, the
+// configured cap, or WorkersPerCPU per GOMAXPROCS, and at least one
+func (e *Engine) oneToManyWorkers(targets int) int {
+	workers := min(e.config.OneToManyWorkers, targets)
+	if e.config.WorkersPerCPU > 0 {
+		workers = min(workers, runtime.GOMAXPROCS(0)*e.config.WorkersPerCPU)
+	}
+
+	return max(workers, 1)
+}
+
+// routeWithWorkerPool routes the snapped targets concurrently. When ctx is canceled it stops
+// dispatching and returns the context error; targets not routed by then are unreachable without
+// a reason.
+func (e *Engine) routeWithWorkerPool(ctx context.Context, srcNodeID int, snapped []snapResult, results []RouteResult, speedKmH float64) ([]RouteResult, error) {
+	workerCount := e.oneToManyWorkers(len(snapped))
+
+	for _, job := range snapped {
+		results[job.originalIdx] = RouteResult{TargetIdx: job.originalIdx, IsReachable: false}
 	}
 
-	jobs := make(chan snapResult, len(snapped))
+	jobs := make(chan snapResult)
 	resultsCh := make(chan routingResult, len(snapped))
 
 	// Start workers
 	var waitGroup sync.WaitGroup
 	for range workerCount {
 		waitGroup.Go(func() {
-			e.routingWorker(ctx, srcNodeID, jobs, resultsCh)
+			e.routingWorker(ctx, srcNodeID, speedKmH, jobs, resultsCh)
 		})
 	}
 
-	// Send jobs
+	// Send jobs until the request is canceled
 	go func() {
+		defer close(jobs)
 		for _, job := range snapped {
-			jobs <- job
+			select {
+			case jobs <- job:
+			case <-ctx.Done():
+				return
+			}
 		}
-		close(jobs)
 	}()
 
 	// Collect results
@@ -385,6 +573,9 @@ func (e *Engine) routeWithWorkerPool(ctx context.Context, srcNodeID int, snapped
 	for res := range resultsCh {
 		results[res.idx] = res.result
 	}
+	if err := ctx.Err(); err != nil {
+		return results, err
+	}
 
 	return results, nil
 }
@@ -394,19 +585,22 @@ type routingResult struct {
 	result RouteResult
 }
 
-func (e *Engine) routingWorker(ctx context.Context, srcNodeID int, jobs <-chan snapResult, resultsCh chan<- routingResult) {
+func (e *Engine) routingWorker(ctx context.Context, srcNodeID int, speedKmH float64, jobs <-chan snapResult, resultsCh chan<- routingResult) {
 	for job := range jobs {
 		if ctx.Err() != nil {
 			return
 		}
 
-		distance, reachable := e.dijkstra(srcNodeID, job.targetNode)
+		distance, reachable := e.route(srcNodeID, job.targetNode)
 		result := RouteResult{
 			TargetIdx:   job.originalIdx,
 			Distance:    distance,
-			Duration:    e.calculateDuration(distance),
+			Duration:    calculateDuration(distance, speedKmH),
 			IsReachable: reachable,
 		}
+		if !reachable {
+			result.UnreachableReason = usecase.UnreachableReasonDisconnected
+		}
 
 		resultsCh <- routingResult{idx: job.originalIdx, result: result}
 	}
@@ -424,19 +618,69 @@ func (e *Engine) preFilterByHaversine(source Coordinate, targets []Coordinate, r
 	return candidates
 }
 
-func (e *Engine) calculateDuration(distanceMeters float64) time.Duration {
-	if e.config.DefaultSpeedKmH <= 0 {
+// calculateDuration estimates the travel time over distanceMeters at speedKmH
+func calculateDuration(distanceMeters, speedKmH float64) time.Duration {
+	if speedKmH <= 0 {
 		return 0
 	}
 	// distance (m) / speed (km/h) = time in hours
 	// time (hours) = distance (km) / speed (km/h)
 	// time (seconds) = (distance_m / 1000) / speed_kmh * 3600
-	speedMps := e.config.DefaultSpeedKmH * 1000 / 3600 // meters per second
+	speedMps := speedKmH * 1000 / 3600 // meters per second
 	seconds := distanceMeters / speedMps
 
 	return time.Duration(seconds * float64(time.Second))
 }
 
+// Query algorithms route picks from
+const (
+	queryCH           = "ch"
+	queryDijkstra     = "dijkstra"
+	queryRestrictions = "dijkstra_restrictions"
+)
+
+// queryKind returns the algorithm route uses for the loaded data. Turn restrictions need the
+// turn-aware search, which the CH query does not model, and the CH query needs shortcuts.
+func (e *Engine) queryKind() string {
+	switch {
+	case len(e.restrictions) > 0:
+		return queryRestrictions
+	case e.downward != nil && !e.config.ForceDijkstra:
+		return queryCH
+	default:
+		return queryDijkstra
+	}
+}
+
+// QueryKind returns the algorithm routes are answered with: ch, dijkstra, or dijkstra_restrictions
+func (e *Engine) QueryKind() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.queryKind()
+}
+
+// route returns the road distance from source to target in meters and whether target is reachable
+func (e *Engine) route(source, target int) (float64, bool) {
+	if !e.isValidVertexRange(source, target) {
+		return 0, false
+	}
+
+	// Same node
+	if source == target {
+		return 0, true
+	}
+
+	switch e.queryKind() {
+	case queryRestrictions:
+		return e.dijkstraWithRestrictions(source, target)
+	case queryCH:
+		return e.chQuery(source, target)
+	default:
+		return e.dijkstra(source, target)
+	}
+}
+
 // dijkstra performs Dijkstra's shortest path algorithm using a heap-based priority queue.
 // Time complexity: O(E log V) where E is edges and V is vertices.
 // Returns (distance in meters, reachable)
@@ -452,7 +696,7 @@ func (e *Engine) dijkstra(source, target int) (float64, bool) {
 
 	distances := e.initializeDistances(source)
 
-	prioQueue := make(priorityQueue, 0, len(e.vertices))
+	prioQueue := make(priorityQueue, 0, len(e.graph.Vertices))
 	heap.Push(&prioQueue, &pqItem{node: source, dist: 0})
 
 	for prioQueue.Len() > 0 {
@@ -466,11 +710,12 @@ func (e *Engine) dijkstra(source, target int) (float64, bool) {
 			continue
 		}
 
-		for _, edge := range e.adjList[current.node] {
-			newDist := distances[current.node] + edge.weight
-			if newDist < distances[edge.to] {
-				distances[edge.to] = newDist
-				heap.Push(&prioQueue, &pqItem{node: edge.to, dist: newDist})
+		for _, arc := range e.graph.Neighbors(current.node) {
+			next := int(arc.To)
+			newDist := distances[current.node] + arc.Weight
+			if newDist < distances[next] {
+				distances[next] = newDist
+				heap.Push(&prioQueue, &pqItem{node: next, dist: newDist})
 			}
 		}
 	}
@@ -478,9 +723,66 @@ func (e *Engine) dijkstra(source, target int) (float64, bool) {
 	return 0, false
 }
 
+// turnState is a vertex together with the vertex the route arrived from, or -1 at the source
+type turnState struct {
+	prev int
+	node int
+}
+
+// dijkstraWithRestrictions runs Dijkstra over (previous, current) vertex pairs so a vertex can be
+// settled once per approach, and skips every edge whose turn at the current vertex is restricted.
+// It relaxes base edges only: a shortcut passes its via vertices without turning at them, so
+// following one could take a restricted turn.
+func (e *Engine) dijkstraWithRestrictions(source, target int) (float64, bool) {
+	start := turnState{prev: -1, node: source}
+	distances := map[turnState]float64{start: 0}
+
+	prioQueue := make(priorityQueue, 0, len(e.graph.Vertices))
+	heap.Push(&prioQueue, &pqItem{node: source, prev: -1, dist: 0})
+
+	for prioQueue.Len() > 0 {
+		current := heap.Pop(&prioQueue).(*pqItem)
+
+		if current.node == target {
+			return current.dist, true
+		}
+
+		state := turnState{prev: current.prev, node: current.node}
+		if current.dist > distances[state] {
+			continue
+		}
+
+		for _, arc := range e.graph.BaseEdges(current.node) {
+			next := int(arc.To)
+			if e.isRestricted(current.prev, current.node, next) {
+				continue
+			}
+
+			nextState := turnState{prev: current.node, node: next}
+			newDist := current.dist + arc.Weight
+			if known, seen := distances[nextState]; !seen || newDist < known {
+				distances[nextState] = newDist
+				heap.Push(&prioQueue, &pqItem{node: next, prev: current.node, dist: newDist})
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// isRestricted reports whether turning from prev onto next at via is forbidden
+func (e *Engine) isRestricted(prev, via, next int) bool {
+	if prev < 0 {
+		return false
+	}
+	_, restricted := e.restrictions[loader.Restriction{From: int64(prev), Via: int64(via), To: int64(next)}]
+
+	return restricted
+}
+
 func (e *Engine) initializeDistances(source int) []float64 {
 	const inf = math.MaxFloat64
-	distances := make([]float64, len(e.vertices))
+	distances := make([]float64, len(e.graph.Vertices))
 	for idx := range distances {
 		distances[idx] = inf
 	}
@@ -492,6 +794,7 @@ func (e *Engine) initializeDistances(source int) []float64 {
 // pqItem represents an item in the priority queue.
 type pqItem struct {
 	node int
+	prev int // Vertex the item was reached from; only tracked when restrictions apply
 	dist float64
 }
 
@@ -547,3 +850,38 @@ func haversineMeters(lat1, lng1, lat2, lng2 float64) float64 {
 func (e *Engine) GetMetadata() *loader.RoutingMetadata {
 	return e.metadata
 }
+
+// Close releases the graph mappings. Call it only once the engine no longer serves queries.
+func (e *Engine) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.ready = false
+	e.graph = &loader.CSRGraph{Offsets: []int64{0}}
+	e.spatial = nil
+
+	var errs []error
+	for _, mapped := range e.mappings {
+		errs = append(errs, mapped.Close())
+	}
+	e.mappings = nil
+
+	return errors.Join(errs...)
+}
+
+// Vertices returns the vertices of the loaded graph. The slice may be backed by the graph mapping, so callers
+// must not modify it or use it after Close.
+func (e *Engine) Vertices() []loader.Vertex {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.graph.Vertices
+}
+
+// GetLoadReport returns the edge and shortcut counts from the last load
+func (e *Engine) GetLoadReport() LoadReport {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.report
+}
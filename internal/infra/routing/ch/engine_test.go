@@ -1,17 +1,22 @@
 package ch
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"radar/internal/infra/routing/loader"
+	"radar/internal/usecase"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -85,6 +90,179 @@ func TestEngine_LoadData_MissingDirectory(t *testing.T) {
 	assert.False(t, engine.IsReady())
 }
 
+// writeDanglingEdgeData writes a three-vertex graph whose edges and shortcuts include references to missing vertices
+func writeDanglingEdgeData(t *testing.T) string {
+	tmpDir := t.TempDir()
+
+	verticesCSV := `id,lat,lng,order_pos,importance
+0,25.0330,121.5654,0,1
+1,25.0478,121.5170,1,2
+2,25.0400,121.5400,2,3
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "vertices.csv"), []byte(verticesCSV), 0644))
+
+	// Vertices 7 and 9 do not exist
+	edgesCSV := `from,to,weight
+0,1,2000
+1,2,1500
+2,7,900
+9,0,1200
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "edges.csv"), []byte(edgesCSV), 0644))
+
+	shortcutsCSV := `from,to,weight,via_node
+0,2,3500,1
+0,9,4000,1
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "shortcuts.csv"), []byte(shortcutsCSV), 0644))
+
+	return tmpDir
+}
+
+func TestEngine_LoadData_ReportsDanglingEdges(t *testing.T) {
+	dataDir := writeDanglingEdgeData(t)
+
+	config := DefaultEngineConfig()
+	config.MaxSkippedEdgeFraction = 0.5
+	engine := NewEngine(config, nil)
+	require.NoError(t, engine.LoadData(dataDir))
+
+	assert.True(t, engine.IsReady())
+	assert.Equal(t, LoadReport{Edges: 4, Shortcuts: 2, SkippedEdges: 2, SkippedShortcuts: 1}, engine.GetLoadReport())
+	assert.InDelta(t, 0.5, engine.GetLoadReport().SkippedFraction(), 1e-9)
+}
+
+func TestEngine_LoadData_TooManyDanglingEdges(t *testing.T) {
+	dataDir := writeDanglingEdgeData(t)
+
+	engine := NewEngine(DefaultEngineConfig(), nil)
+	err := engine.LoadData(dataDir)
+
+	require.ErrorIs(t, err, ErrDanglingEdges)
+	assert.Contains(t, err.Error(), "skipped 2 of 4 edges and 1 of 2 shortcuts")
+	assert.False(t, engine.IsReady())
+	assert.Equal(t, 3, engine.GetLoadReport().SkippedEdges+engine.GetLoadReport().SkippedShortcuts)
+}
+
+// packTestData writes graph.bin next to the CSV files in dataDir
+func packTestData(t *testing.T, dataDir string) {
+	t.Helper()
+
+	graphData, err := loader.NewCSVLoader(dataDir).Load()
+	require.NoError(t, err)
+	source, err := loader.SourceFingerprint(dataDir)
+	require.NoError(t, err)
+	require.NoError(t, loader.WriteBinaryGraph(filepath.Join(dataDir, loader.BinaryGraphFile), loader.BuildCSRGraph(graphData), source))
+}
+
+func TestEngine_LoadData_MemoryMap(t *testing.T) {
+	dataDir := setupTestDataDir(t)
+	packTestData(t, dataDir)
+
+	csvEngine := NewEngine(DefaultEngineConfig(), nil)
+	require.NoError(t, csvEngine.LoadData(dataDir))
+
+	config := DefaultEngineConfig()
+	config.MemoryMap = true
+	engine := NewEngine(config, nil)
+	require.NoError(t, engine.LoadData(dataDir))
+	t.Cleanup(func() { assert.NoError(t, engine.Close()) })
+
+	assert.True(t, engine.IsReady())
+	assert.Equal(t, csvEngine.GetLoadReport(), engine.GetLoadReport())
+	assert.Equal(t, "taiwan", engine.GetMetadata().Source.Region)
+
+	ctx := context.Background()
+	source := Coordinate{Lat: 25.0330, Lng: 121.5654}
+	targets := []Coordinate{{Lat: 25.0478, Lng: 121.5170}, {Lat: 25.0400, Lng: 121.5400}, {Lat: 23.5711, Lng: 119.5793}}
+
+	want, err := csvEngine.OneToMany(ctx, ProfileDefault, source, targets)
+	require.NoError(t, err)
+	got, err := engine.OneToMany(ctx, ProfileDefault, source, targets)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestEngine_LoadData_MemoryMapReportsDanglingEdges(t *testing.T) {
+	dataDir := writeDanglingEdgeData(t)
+	packTestData(t, dataDir)
+
+	config := DefaultEngineConfig()
+	config.MemoryMap = true
+	engine := NewEngine(config, nil)
+	err := engine.LoadData(dataDir)
+	t.Cleanup(func() { assert.NoError(t, engine.Close()) })
+
+	require.ErrorIs(t, err, ErrDanglingEdges)
+	assert.Equal(t, LoadReport{Edges: 4, Shortcuts: 2, SkippedEdges: 2, SkippedShortcuts: 1}, engine.GetLoadReport())
+}
+
+func TestEngine_LoadData_MemoryMapWithoutBinaryGraph(t *testing.T) {
+	config := DefaultEngineConfig()
+	config.MemoryMap = true
+	engine := NewEngine(config, nil)
+
+	err := engine.LoadData(setupTestDataDir(t))
+
+	require.ErrorIs(t, err, os.ErrNotExist)
+	assert.False(t, engine.IsReady())
+}
+
+func TestEngine_LoadData_MemoryMapRejectsStaleBinaryGraph(t *testing.T) {
+	dataDir := setupTestDataDir(t)
+	packTestData(t, dataDir)
+
+	// Re-preparing the data rewrites metadata.json, leaving graph.bin from the previous run
+	metadataPath := filepath.Join(dataDir, "metadata.json")
+	metadata, err := os.ReadFile(metadataPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(metadataPath, append(metadata, '\n'), 0644))
+
+	config := DefaultEngineConfig()
+	config.MemoryMap = true
+	engine := NewEngine(config, nil)
+
+	err = engine.LoadData(dataDir)
+
+	require.ErrorIs(t, err, loader.ErrStaleBinaryGraph)
+	assert.False(t, engine.IsReady())
+}
+
+func TestEngine_Warmup(t *testing.T) {
+	assert.ErrorIs(t, NewEngine(DefaultEngineConfig(), nil).Warmup(context.Background()), ErrEngineNotReady)
+
+	dataDir := setupTestDataDir(t)
+	packTestData(t, dataDir)
+
+	for _, memoryMap := range []bool{false, true} {
+		t.Run(fmt.Sprintf("memory map %v", memoryMap), func(t *testing.T) {
+			config := DefaultEngineConfig()
+			config.MemoryMap = memoryMap
+			engine := NewEngine(config, nil)
+			require.NoError(t, engine.LoadData(dataDir))
+			t.Cleanup(func() { assert.NoError(t, engine.Close()) })
+
+			require.NoError(t, engine.Warmup(context.Background()))
+
+			canceled, cancel := context.WithCancel(context.Background())
+			cancel()
+			require.ErrorIs(t, engine.Warmup(canceled), context.Canceled)
+		})
+	}
+}
+
+func TestWarmupTargets(t *testing.T) {
+	source := Coordinate{Lat: 25.0330, Lng: 121.5654}
+
+	targets := warmupTargets(source)
+
+	require.Len(t, targets, len(warmupDistancesMeters)*4)
+	for idx, target := range targets {
+		want := warmupDistancesMeters[idx/4]
+		assert.InDelta(t, want, haversineMeters(source.Lat, source.Lng, target.Lat, target.Lng), want*0.01)
+	}
+}
+
 func TestEngine_FindNearestNode(t *testing.T) {
 	dataDir := setupTestDataDir(t)
 
@@ -131,7 +309,7 @@ func TestEngine_ShortestPath(t *testing.T) {
 	from := Coordinate{Lat: 25.0330, Lng: 121.5654} // Near vertex 0
 	to := Coordinate{Lat: 25.0478, Lng: 121.5170}   // Near vertex 1
 
-	result, err := engine.ShortestPath(ctx, from, to)
+	result, err := engine.ShortestPath(ctx, ProfileDefault, from, to)
 	require.NoError(t, err)
 	assert.True(t, result.IsReachable)
 	assert.Greater(t, result.Distance, 0.0)
@@ -148,7 +326,7 @@ func TestEngine_ShortestPath_SamePoint(t *testing.T) {
 
 	// Same point should return 0 distance
 	point := Coordinate{Lat: 25.0330, Lng: 121.5654}
-	result, err := engine.ShortestPath(ctx, point, point)
+	result, err := engine.ShortestPath(ctx, ProfileDefault, point, point)
 	require.NoError(t, err)
 	assert.True(t, result.IsReachable)
 	assert.Equal(t, 0.0, result.Distance)
@@ -167,9 +345,25 @@ func TestEngine_ShortestPath_IslandUnreachable(t *testing.T) {
 	taipei := Coordinate{Lat: 25.0330, Lng: 121.5654}
 	penghu := Coordinate{Lat: 23.5711, Lng: 119.5793}
 
-	result, err := engine.ShortestPath(ctx, taipei, penghu)
+	result, err := engine.ShortestPath(ctx, ProfileDefault, taipei, penghu)
 	require.NoError(t, err)
 	assert.False(t, result.IsReachable, "Penghu should be unreachable from Taiwan main island via road network")
+	assert.Equal(t, usecase.UnreachableReasonDisconnected, result.UnreachableReason)
+}
+
+func TestEngine_ShortestPath_OffNetworkReason(t *testing.T) {
+	dataDir := setupTestDataDir(t)
+
+	engine := NewEngine(DefaultEngineConfig(), nil)
+	require.NoError(t, engine.LoadData(dataDir))
+
+	taipei := Coordinate{Lat: 25.0330, Lng: 121.5654}
+	strait := Coordinate{Lat: 24.5, Lng: 119.5}
+
+	result, err := engine.ShortestPath(context.Background(), ProfileDefault, taipei, strait)
+	require.ErrorIs(t, err, ErrSnapDistanceExceeded)
+	assert.False(t, result.IsReachable)
+	assert.Equal(t, usecase.UnreachableReasonOffNetwork, result.UnreachableReason)
 }
 
 func TestEngine_OneToMany(t *testing.T) {
@@ -190,7 +384,7 @@ func TestEngine_OneToMany(t *testing.T) {
 		{Lat: 23.5711, Lng: 119.5793}, // Penghu (unreachable)
 	}
 
-	results, err := engine.OneToMany(ctx, source, targets)
+	results, err := engine.OneToMany(ctx, ProfileDefault, source, targets)
 	require.NoError(t, err)
 	require.Len(t, results, 3)
 
@@ -202,6 +396,45 @@ func TestEngine_OneToMany(t *testing.T) {
 	assert.False(t, results[2].IsReachable, "Target 2 (Penghu) should be unreachable")
 }
 
+func TestEngine_OneToMany_UnreachableReasons(t *testing.T) {
+	dataDir := setupTestDataDir(t)
+
+	config := DefaultEngineConfig()
+	config.MaxQueryRadiusMeters = 250000 // Cover Penghu so it is routed rather than pre-filtered
+
+	engine := NewEngine(config, nil)
+	require.NoError(t, engine.LoadData(dataDir))
+
+	ctx := context.Background()
+	source := Coordinate{Lat: 25.0330, Lng: 121.5654} // Near vertex 0
+	targets := []Coordinate{
+		{Lat: 25.0478, Lng: 121.5170}, // Near vertex 1 (reachable)
+		{Lat: 25.0330, Lng: 121.5800}, // ~1.5km from any vertex (off network)
+		{Lat: 23.5711, Lng: 119.5793}, // Penghu (snaps, but no road connection)
+		{Lat: 21.9000, Lng: 120.8500}, // Southern tip, beyond the query radius
+	}
+
+	results, err := engine.OneToMany(ctx, ProfileDefault, source, targets)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	assert.True(t, results[0].IsReachable)
+	assert.Empty(t, results[0].UnreachableReason)
+	assert.Equal(t, usecase.UnreachableReasonOffNetwork, results[1].UnreachableReason)
+	assert.Equal(t, usecase.UnreachableReasonDisconnected, results[2].UnreachableReason)
+	assert.Equal(t, usecase.UnreachableReasonOutOfRange, results[3].UnreachableReason)
+	for _, result := range results[1:] {
+		assert.False(t, result.IsReachable)
+	}
+
+	// An off-network source leaves every target unroutable
+	results, err = engine.OneToMany(ctx, ProfileDefault, Coordinate{Lat: 24.5, Lng: 119.5}, targets)
+	require.ErrorIs(t, err, ErrSnapDistanceExceeded)
+	for _, result := range results {
+		assert.Equal(t, usecase.UnreachableReasonOffNetwork, result.UnreachableReason)
+	}
+}
+
 func TestEngine_OneToMany_Empty(t *testing.T) {
 	dataDir := setupTestDataDir(t)
 
@@ -210,12 +443,25 @@ func TestEngine_OneToMany_Empty(t *testing.T) {
 
 	ctx := context.Background()
 
-	source := Coordinate{Lat: 25.0330, Lng: 121.5654}
-	targets := []Coordinate{}
+	tests := []struct {
+		name    string
+		source  Coordinate
+		targets []Coordinate
+	}{
+		{name: "empty targets", source: Coordinate{Lat: 25.0330, Lng: 121.5654}, targets: []Coordinate{}},
+		{name: "nil targets", source: Coordinate{Lat: 25.0330, Lng: 121.5654}, targets: nil},
+		// A source far from any vertex would fail to snap, so success shows snapping was skipped
+		{name: "off-network source", source: Coordinate{Lat: 0, Lng: 0}, targets: nil},
+	}
 
-	results, err := engine.OneToMany(ctx, source, targets)
-	require.NoError(t, err)
-	assert.Empty(t, results)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := engine.OneToMany(ctx, ProfileDefault, tt.source, tt.targets)
+			require.NoError(t, err)
+			assert.NotNil(t, results)
+			assert.Empty(t, results)
+		})
+	}
 }
 
 func TestEngine_NotReady(t *testing.T) {
@@ -229,10 +475,10 @@ func TestEngine_NotReady(t *testing.T) {
 	_, err := engine.FindNearestNode(ctx, Coordinate{Lat: 25.0, Lng: 121.0})
 	assert.ErrorIs(t, err, ErrEngineNotReady)
 
-	_, err = engine.ShortestPath(ctx, Coordinate{Lat: 25.0, Lng: 121.0}, Coordinate{Lat: 25.1, Lng: 121.1})
+	_, err = engine.ShortestPath(ctx, ProfileDefault, Coordinate{Lat: 25.0, Lng: 121.0}, Coordinate{Lat: 25.1, Lng: 121.1})
 	assert.ErrorIs(t, err, ErrEngineNotReady)
 
-	_, err = engine.OneToMany(ctx, Coordinate{Lat: 25.0, Lng: 121.0}, []Coordinate{{Lat: 25.1, Lng: 121.1}})
+	_, err = engine.OneToMany(ctx, ProfileDefault, Coordinate{Lat: 25.0, Lng: 121.0}, []Coordinate{{Lat: 25.1, Lng: 121.1}})
 	assert.ErrorIs(t, err, ErrEngineNotReady)
 }
 
@@ -278,7 +524,7 @@ func TestEngine_OneToMany_Concurrent(t *testing.T) {
 	errCh := make(chan error, numGoroutines)
 	for range numGoroutines {
 		go func() {
-			results, err := engine.OneToMany(ctx, source, targets)
+			results, err := engine.OneToMany(ctx, ProfileDefault, source, targets)
 			if err != nil {
 				errCh <- err
 
@@ -300,6 +546,107 @@ func TestEngine_OneToMany_Concurrent(t *testing.T) {
 	}
 }
 
+func TestEngine_OneToManyWorkers(t *testing.T) {
+	config := DefaultEngineConfig()
+	config.OneToManyWorkers = 8
+	config.WorkersPerCPU = 1
+	cpuBound := runtime.GOMAXPROCS(0)
+
+	engine := NewEngine(config, nil)
+	assert.Equal(t, 1, engine.oneToManyWorkers(1), "a single target needs one worker")
+	assert.Equal(t, min(3, cpuBound), engine.oneToManyWorkers(3))
+	assert.Equal(t, min(8, cpuBound), engine.oneToManyWorkers(1000))
+
+	// Without the CPU bound the configured cap applies, and a non-positive cap still routes
+	engine.config.WorkersPerCPU = 0
+	assert.Equal(t, 8, engine.oneToManyWorkers(1000))
+	engine.config.OneToManyWorkers = 0
+	assert.Equal(t, 1, engine.oneToManyWorkers(1000))
+}
+
+// cancelAfterChecks is a context that cancels itself on the n-th call to Err
+type cancelAfterChecks struct {
+	context.Context
+	cancel context.CancelFunc
+	checks atomic.Int32
+	n      int32
+}
+
+func (c *cancelAfterChecks) Err() error {
+	if c.checks.Add(1) == c.n {
+		c.cancel()
+	}
+
+	return c.Context.Err()
+}
+
+func TestEngine_OneToMany_CancelMidFlight(t *testing.T) {
+	dataDir := setupTestDataDir(t)
+
+	config := DefaultEngineConfig()
+	config.OneToManyWorkers = 1 // One worker routes the targets in order
+
+	engine := NewEngine(config, nil)
+	require.NoError(t, engine.LoadData(dataDir))
+
+	parent, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The worker checks the context before each target, so the third check cancels after two routes
+	ctx := &cancelAfterChecks{Context: parent, cancel: cancel, n: 3}
+
+	source := Coordinate{Lat: 25.0330, Lng: 121.5654}
+	targets := make([]Coordinate, 10)
+	for idx := range targets {
+		targets[idx] = Coordinate{Lat: 25.0478, Lng: 121.5170} // Near vertex 1 (reachable)
+	}
+
+	start := time.Now()
+	results, err := engine.OneToMany(ctx, ProfileDefault, source, targets)
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+	require.Len(t, results, len(targets))
+	for idx, result := range results {
+		assert.Equal(t, idx, result.TargetIdx)
+		if idx < 2 {
+			assert.True(t, result.IsReachable, "target %d was routed before the cancellation", idx)
+
+			continue
+		}
+		// Targets never routed are unreachable without a reason rather than reported as out of range
+		assert.False(t, result.IsReachable, "target %d", idx)
+		assert.Empty(t, result.UnreachableReason, "target %d", idx)
+	}
+}
+
+func BenchmarkEngine_OneToMany_TargetCount(b *testing.B) {
+	tmpDir := setupBenchmarkData(b, 0.1, 0.1)
+
+	engine := NewEngine(DefaultEngineConfig(), nil)
+	if err := engine.LoadData(tmpDir); err != nil {
+		b.Fatalf("Failed to load data: %v", err)
+	}
+
+	source := Coordinate{Lat: 24.0, Lng: 121.0}
+	ctx := context.Background()
+
+	for _, count := range []int{1, 10, 100, 500} {
+		targets := make([]Coordinate, count)
+		for i := range targets {
+			targets[i] = Coordinate{
+				Lat: 23.95 + float64(i%10)*0.01,
+				Lng: 120.95 + float64(i/10%10)*0.01,
+			}
+		}
+
+		b.Run(fmt.Sprintf("targets_%d", count), func(b *testing.B) {
+			for b.Loop() {
+				_, _ = engine.OneToMany(ctx, ProfileDefault, source, targets)
+			}
+		})
+	}
+}
+
 func BenchmarkEngine_OneToMany(b *testing.B) {
 	tmpDir := setupBenchmarkData(b, 0.05, 0.05)
 
@@ -323,7 +670,7 @@ func BenchmarkEngine_OneToMany(b *testing.B) {
 	ctx := context.Background()
 
 	for b.Loop() {
-		_, _ = engine.OneToMany(ctx, source, targets)
+		_, _ = engine.OneToMany(ctx, ProfileDefault, source, targets)
 	}
 }
 
@@ -394,7 +741,7 @@ func BenchmarkEngine_ShortestPath(b *testing.B) {
 	ctx := context.Background()
 
 	for b.Loop() {
-		_, _ = engine.ShortestPath(ctx, from, to)
+		_, _ = engine.ShortestPath(ctx, ProfileDefault, from, to)
 	}
 }
 
@@ -427,6 +774,176 @@ func BenchmarkEngine_OneToMany_WithPreFilter(b *testing.B) {
 	ctx := context.Background()
 
 	for b.Loop() {
-		_, _ = engine.OneToMany(ctx, source, targets)
+		_, _ = engine.OneToMany(ctx, ProfileDefault, source, targets)
 	}
 }
+
+func TestEngine_Profiles_ETAFollowsProfileSpeed(t *testing.T) {
+	engine := NewEngine(DefaultEngineConfig(), nil)
+	require.NoError(t, engine.LoadData(setupTestDataDir(t)))
+
+	ctx := context.Background()
+	from := Coordinate{Lat: 25.0330, Lng: 121.5654}
+	to := Coordinate{Lat: 25.0478, Lng: 121.5170}
+
+	scooter, err := engine.ShortestPath(ctx, ProfileScooter, from, to)
+	require.NoError(t, err)
+	walking, err := engine.ShortestPath(ctx, ProfileWalking, from, to)
+	require.NoError(t, err)
+	defaulted, err := engine.ShortestPath(ctx, ProfileDefault, from, to)
+	require.NoError(t, err)
+
+	// The same road distance takes six times as long on foot at 5 km/h as by scooter at 30 km/h
+	assert.InDelta(t, scooter.Distance, walking.Distance, 1e-9)
+	assert.InDelta(t, 6*scooter.Duration.Seconds(), walking.Duration.Seconds(), 1e-6)
+	assert.Equal(t, scooter.Duration, defaulted.Duration, "the default profile is scooter")
+
+	results, err := engine.OneToMany(ctx, ProfileCycling, from, []Coordinate{to})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.InDelta(t, 2*scooter.Duration.Seconds(), results[0].Duration.Seconds(), 1e-6)
+}
+
+func TestEngine_Profiles_SnapDistancePerProfile(t *testing.T) {
+	engine := NewEngine(DefaultEngineConfig(), nil)
+	require.NoError(t, engine.LoadData(setupTestDataDir(t)))
+
+	ctx := context.Background()
+	// About 220m north of vertex 0: within the scooter's 500m snap but beyond the walker's 150m
+	from := Coordinate{Lat: 25.0350, Lng: 121.5654}
+	to := Coordinate{Lat: 25.0478, Lng: 121.5170}
+
+	scooter, err := engine.ShortestPath(ctx, ProfileScooter, from, to)
+	require.NoError(t, err)
+	assert.True(t, scooter.IsReachable)
+
+	walking, err := engine.ShortestPath(ctx, ProfileWalking, from, to)
+	require.ErrorIs(t, err, ErrSnapDistanceExceeded)
+	assert.Equal(t, usecase.UnreachableReasonOffNetwork, walking.UnreachableReason)
+}
+
+func TestEngine_Profiles_UnknownProfile(t *testing.T) {
+	engine := NewEngine(DefaultEngineConfig(), nil)
+	require.NoError(t, engine.LoadData(setupTestDataDir(t)))
+
+	point := Coordinate{Lat: 25.0330, Lng: 121.5654}
+
+	_, err := engine.ShortestPath(context.Background(), "hovercraft", point, point)
+	require.ErrorIs(t, err, ErrUnknownProfile)
+	_, err = engine.OneToMany(context.Background(), "hovercraft", point, []Coordinate{point})
+	require.ErrorIs(t, err, ErrUnknownProfile)
+}
+
+// setupRestrictedDataDir writes a graph where the direct 0->1->2 route is a forbidden turn at 1.
+// Vertex 1 can also be reached through 4, and 0->3->2 is a longer route that avoids vertex 1.
+func setupRestrictedDataDir(t *testing.T, restrictionsCSV string) string {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"vertices.csv": `id,lat,lng,order_pos,importance
+0,25.0300,121.5600,0,1
+1,25.0310,121.5600,1,1
+2,25.0320,121.5600,2,1
+3,25.0310,121.5620,3,1
+4,25.0305,121.5590,4,1
+`,
+		"edges.csv": `from,to,weight
+0,1,100
+1,2,100
+0,4,50
+4,1,100
+0,3,200
+3,2,200
+`,
+	}
+	if restrictionsCSV != "" {
+		files["restrictions.csv"] = restrictionsCSV
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	return tmpDir
+}
+
+// restrictedEngineConfig returns the default config with turn restrictions enabled
+func restrictedEngineConfig() EngineConfig {
+	config := DefaultEngineConfig()
+	config.TurnRestrictions = true
+
+	return config
+}
+
+func TestEngine_Restrictions_IgnoredUnlessEnabled(t *testing.T) {
+	dataDir := setupRestrictedDataDir(t, "from,via,to\n0,1,2\n")
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "shortcuts.csv"), []byte("from,to,weight,via_node\n0,2,200,1\n"), 0644))
+
+	var logs bytes.Buffer
+	engine := NewEngine(DefaultEngineConfig(), slog.New(slog.NewJSONHandler(&logs, nil)))
+	require.NoError(t, engine.LoadData(dataDir))
+
+	// The forbidden turn is taken and the contraction stays in use
+	distance, reachable := engine.route(0, 2)
+	require.True(t, reachable)
+	assert.InDelta(t, 200, distance, 1e-9)
+	assert.Equal(t, 0, engine.GetLoadReport().Restrictions)
+	assert.Equal(t, queryCH, engine.QueryKind())
+	assert.Contains(t, logs.String(), `"msg":"Ignoring turn restrictions because they are not enabled`)
+	assert.NotContains(t, logs.String(), "Turn restrictions disable the CH query")
+}
+
+func TestEngine_Restrictions_SkipForbiddenTurn(t *testing.T) {
+	unrestricted := NewEngine(DefaultEngineConfig(), nil)
+	require.NoError(t, unrestricted.LoadData(setupRestrictedDataDir(t, "")))
+	distance, reachable := unrestricted.route(0, 2)
+	require.True(t, reachable)
+	assert.InDelta(t, 200, distance, 1e-9)
+
+	engine := NewEngine(restrictedEngineConfig(), nil)
+	require.NoError(t, engine.LoadData(setupRestrictedDataDir(t, "from,via,to\n0,1,2\n")))
+	assert.Equal(t, 1, engine.GetLoadReport().Restrictions)
+
+	// Vertex 1 is first settled from 0, where the turn onto 2 is forbidden; approaching it through 4 is allowed
+	distance, reachable = engine.route(0, 2)
+	require.True(t, reachable)
+	assert.InDelta(t, 250, distance, 1e-9)
+
+	// The restriction only applies to routes that arrive from 0
+	distance, reachable = engine.route(4, 2)
+	require.True(t, reachable)
+	assert.InDelta(t, 200, distance, 1e-9)
+}
+
+func TestEngine_Restrictions_FallBackToDetour(t *testing.T) {
+	engine := NewEngine(restrictedEngineConfig(), nil)
+	require.NoError(t, engine.LoadData(setupRestrictedDataDir(t, "from,via,to\n0,1,2\n4,1,2\n")))
+
+	distance, reachable := engine.route(0, 2)
+	require.True(t, reachable)
+	assert.InDelta(t, 400, distance, 1e-9, "both approaches to 1 are restricted, leaving 0->3->2")
+
+	// Turning off at 1 is still allowed when 1 is the destination
+	distance, reachable = engine.route(0, 1)
+	require.True(t, reachable)
+	assert.InDelta(t, 100, distance, 1e-9)
+}
+
+func TestEngine_Restrictions_ShortcutSpanningRestrictedVia(t *testing.T) {
+	dataDir := setupRestrictedDataDir(t, "from,via,to\n0,1,2\n")
+	// The contraction of vertex 1 adds 0->2 over the forbidden turn
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "shortcuts.csv"), []byte("from,to,weight,via_node\n0,2,200,1\n"), 0644))
+
+	var logs bytes.Buffer
+	engine := NewEngine(restrictedEngineConfig(), slog.New(slog.NewJSONHandler(&logs, nil)))
+	require.NoError(t, engine.LoadData(dataDir))
+	assert.Equal(t, 1, engine.GetLoadReport().Shortcuts)
+
+	distance, reachable := engine.route(0, 2)
+	require.True(t, reachable)
+	assert.InDelta(t, 250, distance, 1e-9, "the shortcut would skip the restricted turn at 1")
+
+	// The contraction goes unused, which the load warns about and the metadata reports
+	assert.Contains(t, logs.String(), `"level":"WARN","msg":"Turn restrictions disable the CH query`)
+	assert.Equal(t, queryRestrictions, engine.QueryKind())
+	assert.Equal(t, queryRestrictions, NewRoutingService(engine).Metadata().Query)
+}
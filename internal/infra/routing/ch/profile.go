@@ -0,0 +1,67 @@
+package ch
+
+import (
+	"fmt"
+
+	"radar/internal/usecase"
+)
+
+// ErrUnknownProfile is returned when a query names a profile the engine is not configured with.
+// It wraps usecase.ErrUnknownRoutingProfile so callers see the same error from every backend.
+var ErrUnknownProfile = fmt.Errorf("ch: %w", usecase.ErrUnknownRoutingProfile)
+
+// Profile names a travel mode with its own ETA speed and snap distance
+type Profile string
+
+const (
+	// ProfileDefault selects EngineConfig.DefaultProfile
+	ProfileDefault Profile = ""
+	// ProfileScooter is urban scooter travel, the engine's original assumption
+	ProfileScooter Profile = "scooter"
+	// ProfileCycling is bicycle travel
+	ProfileCycling Profile = "cycling"
+	// ProfileWalking is travel on foot
+	ProfileWalking Profile = "walking"
+)
+
+// ProfileSettings holds the per-profile query parameters. A zero field inherits the engine-wide
+// DefaultSpeedKmH or MaxSnapDistanceMeters.
+type ProfileSettings struct {
+	SpeedKmH              float64 // Speed for ETA calculation
+	MaxSnapDistanceMeters float64 // Maximum distance to snap GPS to road network
+}
+
+// DefaultProfiles returns the built-in profiles. Scooter inherits the engine-wide values, so deployments
+// that tune those keep their behavior; walkers and cyclists are slower and start closer to the road.
+func DefaultProfiles() map[Profile]ProfileSettings {
+	return map[Profile]ProfileSettings{
+		ProfileScooter: {},
+		ProfileCycling: {SpeedKmH: 15, MaxSnapDistanceMeters: 300},
+		ProfileWalking: {SpeedKmH: 5, MaxSnapDistanceMeters: 150},
+	}
+}
+
+// profileSettings resolves profile to its settings with inherited values filled in
+func (e *Engine) profileSettings(profile Profile) (ProfileSettings, error) {
+	if profile == ProfileDefault {
+		profile = e.config.DefaultProfile
+	}
+
+	settings := ProfileSettings{}
+	if profile != ProfileDefault {
+		configured, ok := e.config.Profiles[profile]
+		if !ok {
+			return ProfileSettings{}, fmt.Errorf("%w: %q", ErrUnknownProfile, profile)
+		}
+		settings = configured
+	}
+
+	if settings.SpeedKmH <= 0 {
+		settings.SpeedKmH = e.config.DefaultSpeedKmH
+	}
+	if settings.MaxSnapDistanceMeters <= 0 {
+		settings.MaxSnapDistanceMeters = e.config.MaxSnapDistanceMeters
+	}
+
+	return settings, nil
+}
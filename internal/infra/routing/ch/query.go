@@ -0,0 +1,159 @@
+package ch
+
+import (
+	"container/heap"
+	"math"
+
+	"radar/internal/infra/routing/loader"
+)
+
+// downwardGraph holds, for each vertex v, the arcs u->v that arrive from a higher-ranked u,
+// reversed so the backward search from a target can walk them upward. The forward search filters
+// the vertex's own outgoing arcs by rank and needs no copy of them.
+type downwardGraph struct {
+	offsets []int64
+	arcs    []loader.Arc // Arc.To is the higher-ranked tail u
+}
+
+// buildDownwardGraph collects the reversed downward arcs of a contracted graph
+func buildDownwardGraph(graph *loader.CSRGraph) *downwardGraph {
+	down := &downwardGraph{offsets: make([]int64, len(graph.Vertices)+1)}
+
+	for vertex := range graph.Vertices {
+		for _, arc := range graph.Neighbors(vertex) {
+			if ranksAbove(graph, vertex, int(arc.To)) {
+				down.offsets[arc.To+1]++
+			}
+		}
+	}
+	for idx := 1; idx < len(down.offsets); idx++ {
+		down.offsets[idx] += down.offsets[idx-1]
+	}
+
+	down.arcs = make([]loader.Arc, down.offsets[len(down.offsets)-1])
+	next := make([]int64, len(graph.Vertices))
+	copy(next, down.offsets)
+	for vertex := range graph.Vertices {
+		for _, arc := range graph.Neighbors(vertex) {
+			if ranksAbove(graph, vertex, int(arc.To)) {
+				down.arcs[next[arc.To]] = loader.Arc{To: int64(vertex), Weight: arc.Weight}
+				next[arc.To]++
+			}
+		}
+	}
+
+	return down
+}
+
+func (d *downwardGraph) inbound(vertex int) []loader.Arc {
+	return d.arcs[d.offsets[vertex]:d.offsets[vertex+1]]
+}
+
+// ranksAbove reports whether vertex a was contracted after b. Ties on the contraction order are
+// broken by vertex ID so the ranks form a total order.
+func ranksAbove(graph *loader.CSRGraph, a, b int) bool {
+	orderA, orderB := graph.Vertices[a].OrderPos, graph.Vertices[b].OrderPos
+	if orderA != orderB {
+		return orderA > orderB
+	}
+
+	return a > b
+}
+
+// infinity marks a vertex no search has reached
+const infinity = math.MaxFloat64
+
+// chSearch is one direction of a bidirectional CH query
+type chSearch struct {
+	distances map[int]float64
+	queue     priorityQueue
+}
+
+func newCHSearch(start int) *chSearch {
+	search := &chSearch{distances: map[int]float64{start: 0}}
+	heap.Push(&search.queue, &pqItem{node: start, dist: 0})
+
+	return search
+}
+
+// minDist is the distance of the next vertex the search would settle
+func (s *chSearch) minDist() float64 {
+	if s.queue.Len() == 0 {
+		return infinity
+	}
+
+	return s.queue[0].dist
+}
+
+// relax records a tentative distance to vertex and queues it when it improves
+func (s *chSearch) relax(vertex int, dist float64) {
+	if known, seen := s.distances[vertex]; seen && dist >= known {
+		return
+	}
+	s.distances[vertex] = dist
+	heap.Push(&s.queue, &pqItem{node: vertex, dist: dist})
+}
+
+// chQuery runs a bidirectional contraction hierarchy query: the forward search from source and
+// the backward search from target only move to higher-ranked vertices, and the shortest route is
+// the best sum of the two distances over the vertices both searches reach.
+func (e *Engine) chQuery(source, target int) (float64, bool) {
+	forward, backward := newCHSearch(source), newCHSearch(target)
+	best := infinity
+
+	for {
+		// Neither search can improve on a route shorter than its next vertex
+		if min(forward.minDist(), backward.minDist()) >= best {
+			break
+		}
+
+		if forward.minDist() <= backward.minDist() {
+			best = e.settleForward(forward, backward, best)
+		} else {
+			best = e.settleBackward(backward, forward, best)
+		}
+	}
+
+	if best == infinity {
+		return 0, false
+	}
+
+	return best, true
+}
+
+// settleForward settles the closest vertex of the forward search and relaxes its upward arcs
+func (e *Engine) settleForward(forward, backward *chSearch, best float64) float64 {
+	current := heap.Pop(&forward.queue).(*pqItem)
+	if current.dist > forward.distances[current.node] {
+		return best
+	}
+	if dist, met := backward.distances[current.node]; met {
+		best = min(best, current.dist+dist)
+	}
+
+	for _, arc := range e.graph.Neighbors(current.node) {
+		if ranksAbove(e.graph, int(arc.To), current.node) {
+			forward.relax(int(arc.To), current.dist+arc.Weight)
+		}
+	}
+
+	return best
+}
+
+// settleBackward settles the closest vertex of the backward search and relaxes the reversed
+// arcs that reach it from higher-ranked vertices
+func (e *Engine) settleBackward(backward, forward *chSearch, best float64) float64 {
+	current := heap.Pop(&backward.queue).(*pqItem)
+	if current.dist > backward.distances[current.node] {
+		return best
+	}
+	if dist, met := forward.distances[current.node]; met {
+		best = min(best, current.dist+dist)
+	}
+
+	for _, arc := range e.downward.inbound(current.node) {
+		backward.relax(int(arc.To), current.dist+arc.Weight)
+	}
+
+	return best
+}
@@ -0,0 +1,165 @@
+package ch
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"radar/internal/infra/routing/loader"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// contractInIDOrder contracts vertices in ID order, adding a shortcut for every pair of arcs through
+// the contracted vertex. Skipping witness searches adds more shortcuts than needed, but every
+// shortest path is still covered, so the result is a valid hierarchy to test queries against.
+func contractInIDOrder(vertexCount int, edges []loader.Edge) []loader.Shortcut {
+	const none = -1.0
+	weights := make([][]float64, vertexCount)
+	for from := range weights {
+		weights[from] = make([]float64, vertexCount)
+		for to := range weights[from] {
+			weights[from][to] = none
+		}
+	}
+	for _, edge := range edges {
+		if current := weights[edge.From][edge.To]; current == none || edge.Weight < current {
+			weights[edge.From][edge.To] = edge.Weight
+		}
+	}
+
+	var shortcuts []loader.Shortcut
+	for via := range vertexCount {
+		for from := via + 1; from < vertexCount; from++ {
+			if weights[from][via] == none {
+				continue
+			}
+			for to := via + 1; to < vertexCount; to++ {
+				if to == from || weights[via][to] == none {
+					continue
+				}
+
+				weight := weights[from][via] + weights[via][to]
+				if current := weights[from][to]; current != none && current <= weight {
+					continue
+				}
+				weights[from][to] = weight
+				shortcuts = append(shortcuts, loader.Shortcut{From: int64(from), To: int64(to), Weight: weight, ViaNode: int64(via)})
+			}
+		}
+	}
+
+	return shortcuts
+}
+
+// setupContractedDataDir writes a random directed graph and its hierarchy. The contraction order is
+// the reverse of the vertex ID, stored in order_pos, so ranks do not follow the row order.
+func setupContractedDataDir(t *testing.T, vertexCount, edgeCount int, seed uint64) string {
+	rng := rand.New(rand.NewPCG(seed, seed))
+
+	// Contract by a shuffled order: vertex order[i] is the i-th contracted
+	order := rng.Perm(vertexCount)
+	position := make([]int, vertexCount)
+	for pos, vertex := range order {
+		position[vertex] = pos
+	}
+
+	var edges, renumbered []loader.Edge
+	for range edgeCount {
+		from, to := rng.IntN(vertexCount), rng.IntN(vertexCount)
+		if from == to {
+			continue
+		}
+		edge := loader.Edge{From: int64(from), To: int64(to), Weight: float64(10 + rng.IntN(990))}
+		edges = append(edges, edge)
+		renumbered = append(renumbered, loader.Edge{From: int64(position[from]), To: int64(position[to]), Weight: edge.Weight})
+	}
+
+	// Contract in position order, then map the shortcuts back to vertex IDs
+	var shortcuts []loader.Shortcut
+	for _, shortcut := range contractInIDOrder(vertexCount, renumbered) {
+		shortcuts = append(shortcuts, loader.Shortcut{
+			From:    int64(order[shortcut.From]),
+			To:      int64(order[shortcut.To]),
+			Weight:  shortcut.Weight,
+			ViaNode: int64(order[shortcut.ViaNode]),
+		})
+	}
+
+	var vertices, edgeRows, shortcutRows strings.Builder
+	vertices.WriteString("id,lat,lng,order_pos,importance\n")
+	for vertex := range vertexCount {
+		fmt.Fprintf(&vertices, "%d,%.6f,%.6f,%d,1\n", vertex, 25.0+float64(vertex)*0.001, 121.5, position[vertex])
+	}
+	edgeRows.WriteString("from,to,weight\n")
+	for _, edge := range edges {
+		fmt.Fprintf(&edgeRows, "%d,%d,%g\n", edge.From, edge.To, edge.Weight)
+	}
+	shortcutRows.WriteString("from,to,weight,via_node\n")
+	for _, shortcut := range shortcuts {
+		fmt.Fprintf(&shortcutRows, "%d,%d,%g,%d\n", shortcut.From, shortcut.To, shortcut.Weight, shortcut.ViaNode)
+	}
+
+	tmpDir := t.TempDir()
+	for name, content := range map[string]string{
+		"vertices.csv":  vertices.String(),
+		"edges.csv":     edgeRows.String(),
+		"shortcuts.csv": shortcutRows.String(),
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+
+	return tmpDir
+}
+
+func TestEngine_CHQuery_MatchesDijkstra(t *testing.T) {
+	for _, seed := range []uint64{1, 2, 3} {
+		t.Run(fmt.Sprintf("seed_%d", seed), func(t *testing.T) {
+			const vertexCount = 40
+			dataDir := setupContractedDataDir(t, vertexCount, 90, seed)
+
+			engine := NewEngine(DefaultEngineConfig(), nil)
+			require.NoError(t, engine.LoadData(dataDir))
+			require.Positive(t, engine.GetLoadReport().Shortcuts)
+			require.Equal(t, queryCH, engine.queryKind())
+
+			config := DefaultEngineConfig()
+			config.ForceDijkstra = true
+			reference := NewEngine(config, nil)
+			require.NoError(t, reference.LoadData(dataDir))
+			require.Equal(t, queryDijkstra, reference.queryKind())
+
+			reachablePairs := 0
+			for source := range vertexCount {
+				for target := range vertexCount {
+					want, wantReachable := reference.route(source, target)
+					got, gotReachable := engine.route(source, target)
+
+					require.Equal(t, wantReachable, gotReachable, "%d -> %d", source, target)
+					assert.InDelta(t, want, got, 1e-9, "%d -> %d", source, target)
+					if wantReachable {
+						reachablePairs++
+					}
+				}
+			}
+			// The graph is sparse enough that some pairs are disconnected and dense enough that most are not
+			assert.Greater(t, reachablePairs, vertexCount*vertexCount/2)
+			assert.Less(t, reachablePairs, vertexCount*vertexCount)
+		})
+	}
+}
+
+func TestEngine_CHQuery_UncontractedDataUsesDijkstra(t *testing.T) {
+	engine := NewEngine(DefaultEngineConfig(), nil)
+	require.NoError(t, engine.LoadData(setupTestDataDir(t)))
+
+	// Without shortcuts the rank-restricted searches would miss routes through lower-ranked vertices
+	assert.Equal(t, queryDijkstra, engine.queryKind())
+	distance, reachable := engine.route(1, 0)
+	require.True(t, reachable)
+	assert.InDelta(t, 4000, distance, 1e-9)
+}
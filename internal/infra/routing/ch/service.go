@@ -0,0 +1,165 @@
+package ch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"radar/internal/usecase"
+)
+
+// chRoutingService adapts the CH engine to the RoutingUsecase interface.
+// Coordinates that cannot be snapped to the road network are reported as unreachable.
+type chRoutingService struct {
+	engine *Engine
+}
+
+// NewRoutingService wraps a loaded engine as a RoutingUsecase
+func NewRoutingService(engine *Engine) usecase.RoutingUsecase {
+	return &chRoutingService{engine: engine}
+}
+
+// OneToMany calculates routes from one source to multiple targets
+func (s *chRoutingService) OneToMany(ctx context.Context, profile string, source usecase.Coordinate, targets []usecase.Coordinate) (*usecase.OneToManyResult, error) {
+	startTime := time.Now()
+
+	chTargets := make([]Coordinate, len(targets))
+	for idx, target := range targets {
+		chTargets[idx] = toCHCoordinate(target)
+	}
+
+	routes, err := s.engine.OneToMany(ctx, Profile(profile), toCHCoordinate(source), chTargets)
+	if err != nil && !errors.Is(err, ErrSnapDistanceExceeded) {
+		return nil, fmt.Errorf("ch one-to-many: %w", err)
+	}
+
+	results := make([]usecase.RouteResult, len(targets))
+	for idx, target := range targets {
+		results[idx] = usecase.RouteResult{Source: source, Target: target}
+		if idx < len(routes) {
+			results[idx] = toRouteResult(source, target, routes[idx])
+		}
+	}
+
+	return &usecase.OneToManyResult{
+		Source:   source,
+		Targets:  targets,
+		Results:  results,
+		Duration: time.Since(startTime),
+	}, nil
+}
+
+// ManyToMany routes each source with OneToMany; the contraction hierarchy is built once at load time
+func (s *chRoutingService) ManyToMany(ctx context.Context, profile string, sources, targets []usecase.Coordinate) ([]*usecase.OneToManyResult, error) {
+	return usecase.RouteEachSource(ctx, s, profile, sources, targets)
+}
+
+// FindNearestNode finds the nearest road network node to a coordinate
+func (s *chRoutingService) FindNearestNode(ctx context.Context, _ string, coord usecase.Coordinate) (*usecase.NodeInfo, bool, error) {
+	nearest, err := s.engine.FindNearestNode(ctx, toCHCoordinate(coord))
+	if errors.Is(err, ErrSnapDistanceExceeded) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("ch find nearest node: %w", err)
+	}
+
+	return &usecase.NodeInfo{
+		ID:       usecase.NodeID(nearest.NodeID),
+		Location: usecase.Coordinate{Lat: nearest.NodeLat, Lng: nearest.NodeLng},
+	}, true, nil
+}
+
+// SnapBatch snaps each coordinate through the engine's spatial index
+func (s *chRoutingService) SnapBatch(ctx context.Context, profile string, coords []usecase.Coordinate) ([]usecase.NodeInfo, []bool, error) {
+	nodes := make([]usecase.NodeInfo, len(coords))
+	found := make([]bool, len(coords))
+
+	for idx, coord := range coords {
+		node, ok, err := s.FindNearestNode(ctx, profile, coord)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			nodes[idx] = *node
+			found[idx] = true
+		}
+	}
+
+	return nodes, found, nil
+}
+
+// CalculateDistance calculates road distance between two coordinates
+func (s *chRoutingService) CalculateDistance(ctx context.Context, profile string, source, target usecase.Coordinate) (*usecase.RouteResult, error) {
+	route, err := s.engine.ShortestPath(ctx, Profile(profile), toCHCoordinate(source), toCHCoordinate(target))
+	if errors.Is(err, ErrSnapDistanceExceeded) {
+		return &usecase.RouteResult{Source: source, Target: target, UnreachableReason: usecase.UnreachableReasonOffNetwork}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ch shortest path: %w", err)
+	}
+
+	result := toRouteResult(source, target, *route)
+
+	return &result, nil
+}
+
+// CalculateRoute calculates road distance between two coordinates.
+// The engine does not unpack shortcuts back into road nodes, so the result carries no geometry.
+func (s *chRoutingService) CalculateRoute(ctx context.Context, profile string, source, target usecase.Coordinate) (*usecase.RouteResult, error) {
+	return s.CalculateDistance(ctx, profile, source, target)
+}
+
+// NearestRoadName finds no road, as the prepared CH data carries no road names
+func (s *chRoutingService) NearestRoadName(context.Context, usecase.Coordinate) (string, bool, error) {
+	return "", false, nil
+}
+
+// IsReady returns whether the engine has loaded its routing data
+func (s *chRoutingService) IsReady() bool {
+	return s.engine.IsReady()
+}
+
+// Metadata reports the provenance and size of the loaded CH dataset.
+// Only the backend, readiness, and query algorithm are set when the data directory had no metadata.json.
+func (s *chRoutingService) Metadata() usecase.RoutingMetadata {
+	metadata := usecase.RoutingMetadata{Backend: "ch", Ready: s.engine.IsReady()}
+	if metadata.Ready {
+		metadata.Query = s.engine.QueryKind()
+	}
+
+	loaded := s.engine.GetMetadata()
+	if loaded == nil {
+		return metadata
+	}
+
+	metadata.DataVersion = loaded.Version
+	metadata.Region = loaded.Source.Region
+	metadata.OSMTimestamp = loaded.Source.OSMTimestamp
+	metadata.GeneratedAt = loaded.Processing.GeneratedAt
+	metadata.Profile = loaded.Processing.Profile
+	metadata.VerticesCount = loaded.Output.VerticesCount
+	metadata.EdgesCount = loaded.Output.EdgesCount
+	metadata.ShortcutsCount = loaded.Output.ShortcutsCount
+
+	return metadata
+}
+
+func toCHCoordinate(coord usecase.Coordinate) Coordinate {
+	return Coordinate{Lat: coord.Lat, Lng: coord.Lng}
+}
+
+func toRouteResult(source, target usecase.Coordinate, route RouteResult) usecase.RouteResult {
+	if !route.IsReachable {
+		return usecase.RouteResult{Source: source, Target: target, UnreachableReason: route.UnreachableReason}
+	}
+
+	return usecase.RouteResult{
+		Source:      source,
+		Target:      target,
+		DistanceKm:  route.Distance / 1000,
+		DurationMin: route.Duration.Minutes(),
+		IsReachable: true,
+	}
+}
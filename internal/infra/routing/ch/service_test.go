@@ -0,0 +1,99 @@
+package ch
+
+import (
+	"context"
+	"testing"
+
+	"radar/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRoutingService(t *testing.T) usecase.RoutingUsecase {
+	engine := NewEngine(DefaultEngineConfig(), nil)
+	require.NoError(t, engine.LoadData(setupTestDataDir(t)))
+
+	return NewRoutingService(engine)
+}
+
+func TestRoutingService_OneToMany(t *testing.T) {
+	svc := newTestRoutingService(t)
+	source := usecase.Coordinate{Lat: 25.0330, Lng: 121.5654}
+	targets := []usecase.Coordinate{
+		{Lat: 25.0478, Lng: 121.5170}, // vertex 1
+		{Lat: 23.5711, Lng: 119.5793}, // Penghu, not connected
+	}
+
+	result, err := svc.OneToMany(context.Background(), usecase.DefaultRoutingProfile, source, targets)
+
+	require.NoError(t, err)
+	require.Len(t, result.Results, 2)
+	assert.True(t, result.Results[0].IsReachable)
+	assert.InDelta(t, 2.0, result.Results[0].DistanceKm, 0.001)
+	assert.InDelta(t, 4.0, result.Results[0].DurationMin, 0.001) // 2km at 30 km/h
+	assert.Equal(t, targets[0], result.Results[0].Target)
+	assert.False(t, result.Results[1].IsReachable)
+	assert.Equal(t, source, result.Results[1].Source)
+	assert.Equal(t, usecase.UnreachableReasonOutOfRange, result.Results[1].UnreachableReason)
+}
+
+func TestRoutingService_OffNetworkSourceIsUnreachable(t *testing.T) {
+	svc := newTestRoutingService(t)
+	source := usecase.Coordinate{Lat: 24.0, Lng: 120.5}
+	targets := []usecase.Coordinate{{Lat: 25.0478, Lng: 121.5170}}
+
+	result, err := svc.OneToMany(context.Background(), usecase.DefaultRoutingProfile, source, targets)
+	require.NoError(t, err)
+	require.Len(t, result.Results, 1)
+	assert.False(t, result.Results[0].IsReachable)
+	assert.Equal(t, usecase.UnreachableReasonOffNetwork, result.Results[0].UnreachableReason)
+
+	route, err := svc.CalculateDistance(context.Background(), usecase.DefaultRoutingProfile, source, targets[0])
+	require.NoError(t, err)
+	assert.False(t, route.IsReachable)
+	assert.Equal(t, usecase.UnreachableReasonOffNetwork, route.UnreachableReason)
+
+	node, found, err := svc.FindNearestNode(context.Background(), usecase.DefaultRoutingProfile, source)
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.Nil(t, node)
+}
+
+func TestRoutingService_SnapBatch(t *testing.T) {
+	svc := newTestRoutingService(t)
+
+	nodes, found, err := svc.SnapBatch(context.Background(), usecase.DefaultRoutingProfile, []usecase.Coordinate{
+		{Lat: 25.0335, Lng: 121.5660},
+		{Lat: 24.0, Lng: 120.5},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false}, found)
+	assert.Equal(t, usecase.NodeID(0), nodes[0].ID)
+	assert.InDelta(t, 25.0330, nodes[0].Location.Lat, 0.0001)
+}
+
+func TestRoutingService_NotReady(t *testing.T) {
+	svc := NewRoutingService(NewEngine(DefaultEngineConfig(), nil))
+
+	assert.False(t, svc.IsReady())
+	_, err := svc.OneToMany(context.Background(), usecase.DefaultRoutingProfile, usecase.Coordinate{}, []usecase.Coordinate{{}})
+	assert.ErrorIs(t, err, ErrEngineNotReady)
+}
+
+func TestRoutingService_Metadata(t *testing.T) {
+	metadata := newTestRoutingService(t).Metadata()
+
+	assert.Equal(t, "ch", metadata.Backend)
+	assert.True(t, metadata.Ready)
+	assert.Equal(t, "1.0", metadata.DataVersion)
+	assert.Equal(t, "taiwan", metadata.Region)
+	assert.Equal(t, "scooter", metadata.Profile)
+	assert.Equal(t, int64(4), metadata.VerticesCount)
+	assert.Equal(t, int64(4), metadata.EdgesCount)
+	assert.Equal(t, queryDijkstra, metadata.Query)
+
+	// An engine without data reports only that it is not ready
+	assert.Equal(t, usecase.RoutingMetadata{Backend: "ch"}, NewRoutingService(NewEngine(DefaultEngineConfig(), nil)).Metadata())
+}
@@ -0,0 +1,88 @@
+package ch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// warmupSources is how many vertices, spread over the graph, Warmup routes from
+const warmupSources = 8
+
+// warmupDistancesMeters are the straight-line distances Warmup places targets at around each source,
+// spanning typical notification radii
+var warmupDistancesMeters = []float64{500, 2000, 5000}
+
+// metersPerDegreeLat approximates the length of one degree of latitude
+const metersPerDegreeLat = 111320.0
+
+// Warmup runs a few representative one-to-many queries so the vertices and adjacency they touch are paged in
+// before the engine serves traffic. It matters most for a memory-mapped graph, whose pages are otherwise
+// read from disk by the first real queries. Sources are spread evenly over the vertices and each routes
+// to targets around it in four directions.
+func (e *Engine) Warmup(ctx context.Context) error {
+	if !e.IsReady() {
+		return ErrEngineNotReady
+	}
+
+	e.mu.RLock()
+	graph := e.graph
+	e.mu.RUnlock()
+
+	startedAt := time.Now()
+	queries, reachable := 0, 0
+
+	stride := max(len(graph.Vertices)/warmupSources, 1)
+	for idx := 0; idx < len(graph.Vertices) && queries < warmupSources; idx += stride {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("routing engine warmup: %w", err)
+		}
+
+		source := Coordinate{Lat: graph.Vertices[idx].Lat, Lng: graph.Vertices[idx].Lng}
+		results, err := e.OneToMany(ctx, ProfileDefault, source, warmupTargets(source))
+		if err != nil && !errors.Is(err, ErrSnapDistanceExceeded) {
+			return fmt.Errorf("routing engine warmup: %w", err)
+		}
+
+		queries++
+		for _, result := range results {
+			if result.IsReachable {
+				reachable++
+			}
+		}
+	}
+
+	e.mu.RLock()
+	resident := e.residentBytes()
+	e.mu.RUnlock()
+
+	e.logger.Info("Routing engine warmed up",
+		"queries", queries,
+		"reachable_routes", reachable,
+		"warmup_duration", time.Since(startedAt),
+		"resident_bytes", resident,
+	)
+
+	return nil
+}
+
+// warmupTargets places targets north, east, south, and west of source at each warmup distance
+func warmupTargets(source Coordinate) []Coordinate {
+	metersPerDegreeLng := metersPerDegreeLat * math.Cos(source.Lat*math.Pi/180)
+
+	targets := make([]Coordinate, 0, len(warmupDistancesMeters)*4)
+	for _, meters := range warmupDistancesMeters {
+		dLat := meters / metersPerDegreeLat
+		dLng := meters / max(metersPerDegreeLng, 1)
+		targets = append(targets,
+			Coordinate{Lat: source.Lat + dLat, Lng: source.Lng},
+			Coordinate{Lat: source.Lat, Lng: source.Lng + dLng},
+			Coordinate{Lat: source.Lat - dLat, Lng: source.Lng},
+			Coordinate{Lat: source.Lat, Lng: source.Lng - dLng},
+		)
+	}
+
+	return targets
+}
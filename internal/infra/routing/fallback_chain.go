@@ -0,0 +1,385 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"radar/internal/infra/routing/pmtiles"
+	"radar/internal/usecase"
+)
+
+var (
+	// errFallbackChainExhausted is returned when every backend in the fallback chain failed
+	errFallbackChainExhausted = errors.New("every routing backend in the fallback chain failed")
+
+	// errNoReloadableBackend is returned when a reload finds no PMTiles backend in the fallback chain
+	errNoReloadableBackend = errors.New("no routing backend in the fallback chain reloads a PMTiles source")
+)
+
+// fallbackStrategy is one backend in the fallback chain
+type fallbackStrategy struct {
+	name    string
+	router  usecase.RoutingUsecase
+	timeout time.Duration // Time limit for one attempt; 0 leaves it to the caller's context
+}
+
+// attemptContext bounds one attempt by the strategy's timeout
+func (s fallbackStrategy) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, s.timeout)
+}
+
+// fallbackChainService tries its strategies in order. Each target goes to the next strategy until one
+// routes it over the road network, and the best answer seen is kept when none does.
+type fallbackChainService struct {
+	strategies []fallbackStrategy
+	logger     *slog.Logger
+}
+
+func newFallbackChainService(strategies []fallbackStrategy, logger *slog.Logger) *fallbackChainService {
+	return &fallbackChainService{strategies: strategies, logger: logger}
+}
+
+// resultRank orders answers: a road route beats a straight-line estimate, which beats an unreachable target
+func resultRank(result usecase.RouteResult) int {
+	switch {
+	case result.IsReachable && !result.IsEstimate:
+		return 2
+	case result.IsReachable:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// routed reports whether a result ends the chain for its target
+func routed(result usecase.RouteResult) bool {
+	return resultRank(result) == 2
+}
+
+func (c *fallbackChainService) OneToMany(ctx context.Context, profile string, source usecase.Coordinate, targets []usecase.Coordinate) (*usecase.OneToManyResult, error) {
+	return c.resolve(ctx, profile, c.strategies, source, targets, nil)
+}
+
+// resolve routes the targets not yet routed in results through strategies in order.
+// A nil results means no strategy has answered for this source yet.
+func (c *fallbackChainService) resolve(
+	ctx context.Context,
+	profile string,
+	strategies []fallbackStrategy,
+	source usecase.Coordinate,
+	targets []usecase.Coordinate,
+	results []usecase.RouteResult,
+) (*usecase.OneToManyResult, error) {
+	startedAt := time.Now()
+
+	answered := results != nil
+	if !answered {
+		results = make([]usecase.RouteResult, len(targets))
+		for idx, target := range targets {
+			results[idx] = usecase.RouteResult{Source: source, Target: target}
+		}
+	}
+
+	pending := make([]int, 0, len(targets))
+	for idx := range targets {
+		if !routed(results[idx]) {
+			pending = append(pending, idx)
+		}
+	}
+
+	var lastErr error
+	for _, strategy := range strategies {
+		if len(pending) == 0 {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		batch := make([]usecase.Coordinate, len(pending))
+		for idx, target := range pending {
+			batch[idx] = targets[target]
+		}
+
+		attemptCtx, cancel := strategy.attemptContext(ctx)
+		result, err := strategy.router.OneToMany(attemptCtx, profile, source, batch)
+		cancel()
+		if err != nil {
+			c.attemptFailed(strategy, err)
+			lastErr = err
+
+			continue
+		}
+
+		next := pending[:0:0]
+		for idx, target := range pending {
+			if idx < len(result.Results) && (!answered || resultRank(result.Results[idx]) > resultRank(results[target])) {
+				results[target] = result.Results[idx]
+			}
+			if !routed(results[target]) {
+				next = append(next, target)
+			}
+		}
+		pending = next
+		answered = true
+	}
+
+	if !answered && len(targets) > 0 {
+		return nil, fmt.Errorf("%w: %w", errFallbackChainExhausted, lastErr)
+	}
+
+	return &usecase.OneToManyResult{
+		Source:   source,
+		Targets:  targets,
+		Results:  results,
+		Duration: time.Since(startedAt),
+	}, nil
+}
+
+// ManyToMany sends the whole matrix to the first strategy, so a backend that shares one graph between
+// sources still does, and resolves each source's remaining targets through the rest of the chain
+func (c *fallbackChainService) ManyToMany(ctx context.Context, profile string, sources, targets []usecase.Coordinate) ([]*usecase.OneToManyResult, error) {
+	first := c.strategies[0]
+
+	attemptCtx, cancel := first.attemptContext(ctx)
+	batch, err := first.router.ManyToMany(attemptCtx, profile, sources, targets)
+	cancel()
+	if err != nil {
+		c.attemptFailed(first, err)
+
+		return usecase.RouteEachSource(ctx, c, profile, sources, targets)
+	}
+
+	results := make([]*usecase.OneToManyResult, len(sources))
+	for idx, source := range sources {
+		var initial []usecase.RouteResult
+		if idx < len(batch) && batch[idx] != nil && len(batch[idx].Results) == len(targets) {
+			initial = batch[idx].Results
+		}
+
+		strategies := c.strategies[1:]
+		if initial == nil {
+			strategies = c.strategies
+		}
+
+		result, err := c.resolve(ctx, profile, strategies, source, targets, initial)
+		if err != nil {
+			return nil, err
+		}
+		results[idx] = result
+	}
+
+	return results, nil
+}
+
+func (c *fallbackChainService) FindNearestNode(ctx context.Context, profile string, coord usecase.Coordinate) (*usecase.NodeInfo, bool, error) {
+	var (
+		best    *usecase.NodeInfo
+		lastErr error
+	)
+	for _, strategy := range c.strategies {
+		attemptCtx, cancel := strategy.attemptContext(ctx)
+		node, found, err := strategy.router.FindNearestNode(attemptCtx, profile, coord)
+		cancel()
+		if err != nil {
+			c.attemptFailed(strategy, err)
+			lastErr = err
+
+			continue
+		}
+		if found {
+			return node, true, nil
+		}
+		if best == nil {
+			best = node
+		}
+	}
+
+	if best == nil && lastErr != nil {
+		return nil, false, fmt.Errorf("%w: %w", errFallbackChainExhausted, lastErr)
+	}
+
+	return best, false, nil
+}
+
+func (c *fallbackChainService) SnapBatch(ctx context.Context, profile string, coords []usecase.Coordinate) ([]usecase.NodeInfo, []bool, error) {
+	nodes := make([]usecase.NodeInfo, len(coords))
+	found := make([]bool, len(coords))
+
+	pending := make([]int, len(coords))
+	for idx := range coords {
+		pending[idx] = idx
+	}
+
+	var lastErr error
+	answered := false
+	for _, strategy := range c.strategies {
+		if len(pending) == 0 {
+			break
+		}
+
+		batch := make([]usecase.Coordinate, len(pending))
+		for idx, coord := range pending {
+			batch[idx] = coords[coord]
+		}
+
+		attemptCtx, cancel := strategy.attemptContext(ctx)
+		batchNodes, batchFound, err := strategy.router.SnapBatch(attemptCtx, profile, batch)
+		cancel()
+		if err != nil {
+			c.attemptFailed(strategy, err)
+			lastErr = err
+
+			continue
+		}
+
+		next := pending[:0:0]
+		for idx, coord := range pending {
+			if idx < len(batchFound) && batchFound[idx] {
+				nodes[coord], found[coord] = batchNodes[idx], true
+
+				continue
+			}
+			if !answered && idx < len(batchNodes) {
+				nodes[coord] = batchNodes[idx]
+			}
+			next = append(next, coord)
+		}
+		pending = next
+		answered = true
+	}
+
+	if !answered && len(coords) > 0 {
+		return nil, nil, fmt.Errorf("%w: %w", errFallbackChainExhausted, lastErr)
+	}
+
+	return nodes, found, nil
+}
+
+func (c *fallbackChainService) CalculateDistance(ctx context.Context, profile string, source, target usecase.Coordinate) (*usecase.RouteResult, error) {
+	result, err := c.OneToMany(ctx, profile, source, []usecase.Coordinate{target})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result.Results[0], nil
+}
+
+func (c *fallbackChainService) CalculateRoute(ctx context.Context, profile string, source, target usecase.Coordinate) (*usecase.RouteResult, error) {
+	var (
+		best    *usecase.RouteResult
+		lastErr error
+	)
+	for _, strategy := range c.strategies {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		attemptCtx, cancel := strategy.attemptContext(ctx)
+		result, err := strategy.router.CalculateRoute(attemptCtx, profile, source, target)
+		cancel()
+		if err != nil {
+			c.attemptFailed(strategy, err)
+			lastErr = err
+
+			continue
+		}
+		if best == nil || resultRank(*result) > resultRank(*best) {
+			best = result
+		}
+		if routed(*best) {
+			break
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("%w: %w", errFallbackChainExhausted, lastErr)
+	}
+
+	return best, nil
+}
+
+// NearestRoadName returns the first road name a strategy finds, in chain order
+func (c *fallbackChainService) NearestRoadName(ctx context.Context, coord usecase.Coordinate) (string, bool, error) {
+	var lastErr error
+	answered := false
+	for _, strategy := range c.strategies {
+		attemptCtx, cancel := strategy.attemptContext(ctx)
+		name, found, err := strategy.router.NearestRoadName(attemptCtx, coord)
+		cancel()
+		if err != nil {
+			c.attemptFailed(strategy, err)
+			lastErr = err
+
+			continue
+		}
+		if found {
+			return name, true, nil
+		}
+		answered = true
+	}
+
+	if !answered && lastErr != nil {
+		return "", false, fmt.Errorf("%w: %w", errFallbackChainExhausted, lastErr)
+	}
+
+	return "", false, nil
+}
+
+// IsReady reports whether any strategy can answer queries
+func (c *fallbackChainService) IsReady() bool {
+	for _, strategy := range c.strategies {
+		if strategy.router.IsReady() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Metadata describes the first ready strategy, or the first strategy when none is ready
+func (c *fallbackChainService) Metadata() usecase.RoutingMetadata {
+	for _, strategy := range c.strategies {
+		if strategy.router.IsReady() {
+			return strategy.router.Metadata()
+		}
+	}
+
+	return c.strategies[0].router.Metadata()
+}
+
+// Reload switches every PMTiles backend in the chain to the archive at newSource
+func (c *fallbackChainService) Reload(ctx context.Context, newSource string) error {
+	var errs []error
+	reloaded := false
+	for _, strategy := range c.strategies {
+		reloader, ok := strategy.router.(pmtiles.SourceReloader)
+		if !ok {
+			continue
+		}
+		reloaded = true
+		if err := reloader.Reload(ctx, newSource); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", strategy.name, err))
+		}
+	}
+	if !reloaded {
+		return errNoReloadableBackend
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *fallbackChainService) attemptFailed(strategy fallbackStrategy, err error) {
+	c.logger.Warn("Routing fallback attempt failed, trying the next backend",
+		slog.String("backend", strategy.name),
+		slog.Duration("timeout", strategy.timeout),
+		slog.String("error", err.Error()),
+	)
+}
@@ -0,0 +1,241 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"radar/config"
+	"radar/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+)
+
+// chainTestRouter answers OneToMany with a fixed result per target and records each call
+type chainTestRouter struct {
+	usecase.RoutingUsecase
+
+	name    string
+	calls   *[]string
+	results func(target usecase.Coordinate) usecase.RouteResult
+	err     error
+	block   bool // Wait for the attempt's context to end instead of answering
+}
+
+func (r *chainTestRouter) OneToMany(ctx context.Context, _ string, source usecase.Coordinate, targets []usecase.Coordinate) (*usecase.OneToManyResult, error) {
+	*r.calls = append(*r.calls, r.name)
+	if r.block {
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	results := make([]usecase.RouteResult, len(targets))
+	for idx, target := range targets {
+		results[idx] = r.results(target)
+		results[idx].Source, results[idx].Target = source, target
+	}
+
+	return &usecase.OneToManyResult{Source: source, Targets: targets, Results: results}, nil
+}
+
+func (r *chainTestRouter) ManyToMany(ctx context.Context, profile string, sources, targets []usecase.Coordinate) ([]*usecase.OneToManyResult, error) {
+	return usecase.RouteEachSource(ctx, r, profile, sources, targets)
+}
+
+func (r *chainTestRouter) IsReady() bool { return true }
+
+func roadRoute(km float64) func(usecase.Coordinate) usecase.RouteResult {
+	return func(usecase.Coordinate) usecase.RouteResult {
+		return usecase.RouteResult{DistanceKm: km, IsReachable: true}
+	}
+}
+
+func estimate(usecase.Coordinate) usecase.RouteResult {
+	return usecase.RouteResult{DistanceKm: 9, IsReachable: true, IsEstimate: true}
+}
+
+func unreachable(usecase.Coordinate) usecase.RouteResult {
+	return usecase.RouteResult{UnreachableReason: usecase.UnreachableReasonDisconnected}
+}
+
+func newTestChain(routers ...*chainTestRouter) *fallbackChainService {
+	strategies := make([]fallbackStrategy, len(routers))
+	for idx, router := range routers {
+		strategies[idx] = fallbackStrategy{name: router.name, router: router, timeout: 50 * time.Millisecond}
+	}
+
+	return newFallbackChainService(strategies, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestFallbackChain_OneToMany_FollowsConfiguredOrder(t *testing.T) {
+	var calls []string
+	chain := newTestChain(
+		&chainTestRouter{name: "pmtiles", calls: &calls, results: estimate},
+		&chainTestRouter{name: "ch", calls: &calls, err: errors.New("ch data not loaded")},
+		&chainTestRouter{name: "haversine", calls: &calls, block: true},
+	)
+
+	result, err := chain.OneToMany(context.Background(), usecase.DefaultRoutingProfile, usecase.Coordinate{Lat: 25.03, Lng: 121.56}, []usecase.Coordinate{{Lat: 25.04, Lng: 121.55}})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pmtiles", "ch", "haversine"}, calls)
+	// The timed-out and failed attempts leave the first backend's estimate in place
+	require.Len(t, result.Results, 1)
+	assert.True(t, result.Results[0].IsEstimate)
+	assert.Equal(t, 9.0, result.Results[0].DistanceKm)
+}
+
+func TestFallbackChain_OneToMany_ShortCircuitsOnSuccess(t *testing.T) {
+	var calls []string
+	chain := newTestChain(
+		&chainTestRouter{name: "pmtiles", calls: &calls, results: unreachable},
+		&chainTestRouter{name: "ch", calls: &calls, results: roadRoute(1.2)},
+		&chainTestRouter{name: "haversine", calls: &calls, results: estimate},
+	)
+
+	result, err := chain.OneToMany(context.Background(), usecase.DefaultRoutingProfile, usecase.Coordinate{Lat: 25.03, Lng: 121.56}, []usecase.Coordinate{{Lat: 25.04, Lng: 121.55}})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pmtiles", "ch"}, calls)
+	assert.False(t, result.Results[0].IsEstimate)
+	assert.Equal(t, 1.2, result.Results[0].DistanceKm)
+}
+
+func TestFallbackChain_OneToMany_RetriesOnlyUnroutedTargets(t *testing.T) {
+	near := usecase.Coordinate{Lat: 25.04, Lng: 121.55}
+	far := usecase.Coordinate{Lat: 23.57, Lng: 119.58}
+
+	var calls []string
+	var retried []usecase.Coordinate
+	chain := newTestChain(
+		&chainTestRouter{name: "pmtiles", calls: &calls, results: func(target usecase.Coordinate) usecase.RouteResult {
+			if target == near {
+				return usecase.RouteResult{DistanceKm: 1.5, IsReachable: true}
+			}
+
+			return usecase.RouteResult{}
+		}},
+		&chainTestRouter{name: "haversine", calls: &calls, results: func(target usecase.Coordinate) usecase.RouteResult {
+			retried = append(retried, target)
+
+			return estimate(target)
+		}},
+	)
+
+	result, err := chain.OneToMany(context.Background(), usecase.DefaultRoutingProfile, usecase.Coordinate{Lat: 25.03, Lng: 121.56}, []usecase.Coordinate{near, far})
+
+	require.NoError(t, err)
+	assert.Equal(t, []usecase.Coordinate{far}, retried)
+	assert.Equal(t, 1.5, result.Results[0].DistanceKm)
+	assert.Equal(t, far, result.Results[1].Target)
+	assert.True(t, result.Results[1].IsEstimate)
+}
+
+func TestFallbackChain_OneToMany_AllBackendsFail(t *testing.T) {
+	var calls []string
+	chain := newTestChain(
+		&chainTestRouter{name: "pmtiles", calls: &calls, block: true},
+		&chainTestRouter{name: "ch", calls: &calls, err: errors.New("ch data not loaded")},
+	)
+
+	_, err := chain.OneToMany(context.Background(), usecase.DefaultRoutingProfile, usecase.Coordinate{}, []usecase.Coordinate{{Lat: 25.04, Lng: 121.55}})
+
+	require.ErrorIs(t, err, errFallbackChainExhausted)
+	assert.Equal(t, []string{"pmtiles", "ch"}, calls)
+}
+
+func TestFallbackChain_ManyToMany(t *testing.T) {
+	var calls []string
+	chain := newTestChain(
+		&chainTestRouter{name: "pmtiles", calls: &calls, results: unreachable},
+		&chainTestRouter{name: "ch", calls: &calls, results: roadRoute(2)},
+	)
+
+	results, err := chain.ManyToMany(context.Background(), usecase.DefaultRoutingProfile,
+		[]usecase.Coordinate{{Lat: 25.03, Lng: 121.56}, {Lat: 25.05, Lng: 121.52}},
+		[]usecase.Coordinate{{Lat: 25.04, Lng: 121.55}},
+	)
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, []string{"pmtiles", "pmtiles", "ch", "ch"}, calls)
+	for _, result := range results {
+		assert.Equal(t, 2.0, result.Results[0].DistanceKm)
+	}
+}
+
+// reloadingTestRouter records the sources a chain reload passes to it
+type reloadingTestRouter struct {
+	*chainTestRouter
+
+	sources []string
+	err     error
+}
+
+func (r *reloadingTestRouter) Reload(_ context.Context, newSource string) error {
+	r.sources = append(r.sources, newSource)
+
+	return r.err
+}
+
+func TestFallbackChain_Reload(t *testing.T) {
+	var calls []string
+	primary := &reloadingTestRouter{chainTestRouter: &chainTestRouter{name: "pmtiles", calls: &calls}}
+	secondary := &reloadingTestRouter{chainTestRouter: &chainTestRouter{name: "pmtiles-mirror", calls: &calls}, err: errors.New("open failed")}
+	chain := newFallbackChainService([]fallbackStrategy{
+		{name: "pmtiles", router: primary},
+		{name: "ch", router: &chainTestRouter{name: "ch", calls: &calls}},
+		{name: "pmtiles-mirror", router: secondary},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	err := chain.Reload(context.Background(), "/data/new.pmtiles")
+
+	require.ErrorContains(t, err, "pmtiles-mirror: open failed")
+	assert.Equal(t, []string{"/data/new.pmtiles"}, primary.sources)
+	assert.Equal(t, []string{"/data/new.pmtiles"}, secondary.sources)
+
+	// A chain without a PMTiles backend has nothing to reload
+	chain = newTestChain(&chainTestRouter{name: "ch", calls: &calls})
+	require.ErrorIs(t, chain.Reload(context.Background(), "/data/new.pmtiles"), errNoReloadableBackend)
+}
+
+func TestNewRoutingService_FallbackChain(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	svc, err := NewRoutingService(ServiceParams{
+		Lifecycle: fxtest.NewLifecycle(t),
+		Config: &config.RoutingConfig{
+			FallbackChain:    []string{"CH", "haversine"},
+			FallbackTimeouts: map[string]time.Duration{"ch": time.Second},
+			CH:               config.CHRoutingConfig{DataDir: writeCHTestData(t)},
+		},
+		Logger: logger,
+	})
+	require.NoError(t, err)
+
+	chain, ok := svc.(*fallbackChainService)
+	require.True(t, ok, "expected a fallback chain, got %T", svc)
+	require.Len(t, chain.strategies, 2)
+	assert.Equal(t, "ch", chain.strategies[0].name)
+	assert.Equal(t, time.Second, chain.strategies[0].timeout)
+	assert.Equal(t, "haversine", chain.strategies[1].name)
+	assert.Equal(t, 2*time.Second, chain.strategies[1].timeout)
+	assert.True(t, svc.IsReady())
+	assert.Equal(t, config.RoutingBackendCH, svc.Metadata().Backend)
+
+	// A target beyond the CH graph is answered by the Haversine estimate
+	result, err := svc.CalculateDistance(context.Background(), usecase.DefaultRoutingProfile,
+		usecase.Coordinate{Lat: 25.0330, Lng: 121.5654}, usecase.Coordinate{Lat: 23.5711, Lng: 119.5793})
+	require.NoError(t, err)
+	assert.True(t, result.IsReachable)
+	assert.True(t, result.IsEstimate)
+}
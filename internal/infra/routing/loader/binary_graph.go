@@ -0,0 +1,351 @@
+package loader
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"unsafe"
+)
+
+// BinaryGraphFile is the name of the binary graph written next to the CSV files
+const BinaryGraphFile = "graph.bin"
+
+// binaryGraphMagic and binaryGraphVersion identify the binary graph layout
+var binaryGraphMagic = [4]byte{'R', 'C', 'H', 'G'}
+
+const binaryGraphVersion uint32 = 3
+
+var (
+	// ErrInvalidBinaryGraph is returned when a binary graph file is truncated, has an unknown layout,
+	// or its adjacency points outside the graph
+	ErrInvalidBinaryGraph = errors.New("invalid binary routing graph")
+	// ErrStaleBinaryGraph is returned when a binary graph was packed from other data than the metadata.json next to it
+	ErrStaleBinaryGraph = errors.New("binary routing graph is stale")
+)
+
+// Arc is one outgoing edge or shortcut in the adjacency of a vertex
+type Arc struct {
+	To     int64   // Target vertex ID
+	Weight float64 // Edge or shortcut weight
+}
+
+// CSRGraph holds the vertices and their adjacency in compressed sparse row form:
+// the arcs leaving vertex v are Arcs[Offsets[v]:Offsets[v+1]], base edges before shortcuts,
+// and its base edges end at EdgeEnds[v].
+// Edges and shortcuts whose endpoints are outside the vertex range are skipped and counted.
+type CSRGraph struct {
+	Vertices []Vertex
+	Offsets  []int64
+	EdgeEnds []int64
+	Arcs     []Arc
+
+	Edges            int // Edges in the source data
+	Shortcuts        int // Shortcuts in the source data
+	SkippedEdges     int // Edges skipped because an endpoint is outside the vertex range
+	SkippedShortcuts int // Shortcuts skipped because an endpoint is outside the vertex range
+}
+
+// binaryGraphHeader is the fixed-size header at the start of a binary graph file.
+// The vertex, offset, edge end, and arc sections follow in that order, each in the in-memory layout of its type.
+type binaryGraphHeader struct {
+	Magic            [4]byte
+	Version          uint32
+	VertexCount      uint64
+	ArcCount         uint64
+	EdgeCount        uint64
+	ShortcutCount    uint64
+	SkippedEdges     uint64
+	SkippedShortcuts uint64
+	Source           [sha256.Size]byte // SourceFingerprint of the data directory the graph was packed from
+}
+
+const (
+	binaryGraphHeaderSize = int64(unsafe.Sizeof(binaryGraphHeader{}))
+	vertexSize            = int64(unsafe.Sizeof(Vertex{}))
+	offsetSize            = int64(unsafe.Sizeof(int64(0)))
+	arcSize               = int64(unsafe.Sizeof(Arc{}))
+)
+
+// BuildCSRGraph builds the adjacency of the loaded graph data
+func BuildCSRGraph(data *GraphData) *CSRGraph {
+	graph := &CSRGraph{
+		Vertices:  data.Vertices,
+		Offsets:   make([]int64, len(data.Vertices)+1),
+		Edges:     len(data.Edges),
+		Shortcuts: len(data.Shortcuts),
+	}
+
+	// Count the arcs leaving each vertex, then turn the counts into offsets
+	for _, edge := range data.Edges {
+		if graph.inRange(edge.From, edge.To) {
+			graph.Offsets[edge.From+1]++
+		} else {
+			graph.SkippedEdges++
+		}
+	}
+	for _, shortcut := range data.Shortcuts {
+		if graph.inRange(shortcut.From, shortcut.To) {
+			graph.Offsets[shortcut.From+1]++
+		} else {
+			graph.SkippedShortcuts++
+		}
+	}
+	for idx := 1; idx < len(graph.Offsets); idx++ {
+		graph.Offsets[idx] += graph.Offsets[idx-1]
+	}
+
+	graph.Arcs = make([]Arc, graph.Offsets[len(graph.Offsets)-1])
+	next := make([]int64, len(data.Vertices))
+	copy(next, graph.Offsets)
+	for _, edge := range data.Edges {
+		if graph.inRange(edge.From, edge.To) {
+			graph.Arcs[next[edge.From]] = Arc{To: edge.To, Weight: edge.Weight}
+			next[edge.From]++
+		}
+	}
+	graph.EdgeEnds = slices.Clone(next)
+	for _, shortcut := range data.Shortcuts {
+		if graph.inRange(shortcut.From, shortcut.To) {
+			graph.Arcs[next[shortcut.From]] = Arc{To: shortcut.To, Weight: shortcut.Weight}
+			next[shortcut.From]++
+		}
+	}
+
+	return graph
+}
+
+func (g *CSRGraph) inRange(from, to int64) bool {
+	count := int64(len(g.Vertices))
+
+	return from >= 0 && from < count && to >= 0 && to < count
+}
+
+// Neighbors returns the arcs leaving a vertex
+func (g *CSRGraph) Neighbors(vertex int) []Arc {
+	return g.Arcs[g.Offsets[vertex]:g.Offsets[vertex+1]]
+}
+
+// BaseEdges returns the edges leaving a vertex without its shortcuts
+func (g *CSRGraph) BaseEdges(vertex int) []Arc {
+	return g.Arcs[g.Offsets[vertex]:g.EdgeEnds[vertex]]
+}
+
+// SizeBytes returns the size of the vertex, offset, edge end, and arc arrays
+func (g *CSRGraph) SizeBytes() int64 {
+	return int64(len(g.Vertices))*vertexSize + int64(len(g.Offsets)+len(g.EdgeEnds))*offsetSize + int64(len(g.Arcs))*arcSize
+}
+
+// SourceFingerprint identifies the routing data a binary graph is packed from: the SHA-256 of the metadata.json in
+// dataDir, which records the checksums of the CSV files it describes. A missing metadata file hashes as empty,
+// so the graph still loads where the data ships without one but any regenerated metadata is detected.
+func SourceFingerprint(dataDir string) ([sha256.Size]byte, error) {
+	path := filepath.Join(dataDir, metadataFile)
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return [sha256.Size]byte{}, fmt.Errorf("read routing metadata %s: %w", path, err)
+	}
+
+	return sha256.Sum256(data), nil
+}
+
+// WriteBinaryGraph writes the graph to path in the layout MapBinaryGraph reads, recording source,
+// the SourceFingerprint of the data it was built from
+func WriteBinaryGraph(path string, graph *CSRGraph, source [sha256.Size]byte) (err error) {
+	if !hostIsLittleEndian() {
+		return errors.New("binary routing graphs require a little-endian host")
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("close %s: %w", path, closeErr)
+		}
+	}()
+
+	header := binaryGraphHeader{
+		Magic:            binaryGraphMagic,
+		Version:          binaryGraphVersion,
+		VertexCount:      uint64(len(graph.Vertices)),
+		ArcCount:         uint64(len(graph.Arcs)),
+		EdgeCount:        uint64(graph.Edges),
+		ShortcutCount:    uint64(graph.Shortcuts),
+		SkippedEdges:     uint64(graph.SkippedEdges),
+		SkippedShortcuts: uint64(graph.SkippedShortcuts),
+		Source:           source,
+	}
+
+	writer := bufio.NewWriter(file)
+	if err := binary.Write(writer, binary.LittleEndian, header); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	// The sections are written in their in-memory layout so MapBinaryGraph can use them in place
+	for _, section := range [][]byte{sectionBytes(graph.Vertices), sectionBytes(graph.Offsets), sectionBytes(graph.EdgeEnds), sectionBytes(graph.Arcs)} {
+		if _, err := writer.Write(section); err != nil {
+			return fmt.Errorf("write %s: %w", path, err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// MappedGraph is a binary graph mapped read-only into memory. Its slices point into the mapping,
+// so pages are read from the file on first access and shared with the page cache instead of the heap.
+type MappedGraph struct {
+	Graph *CSRGraph
+	data  []byte
+}
+
+// MapBinaryGraph maps the binary graph file in dataDir. It fails with ErrStaleBinaryGraph when the graph
+// was packed before the data in dataDir was regenerated. The graph must not be used after Close.
+func MapBinaryGraph(dataDir string) (*MappedGraph, error) {
+	if !hostIsLittleEndian() {
+		return nil, errors.New("binary routing graphs require a little-endian host")
+	}
+
+	source, err := SourceFingerprint(dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dataDir, BinaryGraphFile)
+	data, err := mapFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("map %s: %w", path, err)
+	}
+
+	graph, err := decodeBinaryGraph(data, source)
+	if err != nil {
+		_ = unmapFile(data)
+
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return &MappedGraph{Graph: graph, data: data}, nil
+}
+
+// decodeBinaryGraph checks the header against source and points the graph's slices at the sections of data.
+// The adjacency is checked in full, so a corrupt file fails here instead of indexing out of range in a query;
+// this reads the offset and arc sections once, which also pages them in.
+func decodeBinaryGraph(data []byte, source [sha256.Size]byte) (*CSRGraph, error) {
+	if int64(len(data)) < binaryGraphHeaderSize {
+		return nil, fmt.Errorf("%w: file is shorter than its header", ErrInvalidBinaryGraph)
+	}
+
+	header := (*binaryGraphHeader)(unsafe.Pointer(&data[0]))
+	if header.Magic != binaryGraphMagic || header.Version != binaryGraphVersion {
+		return nil, fmt.Errorf("%w: unknown format %q version %d", ErrInvalidBinaryGraph, header.Magic[:], header.Version)
+	}
+	if header.Source != source {
+		return nil, fmt.Errorf("%w: packed from other data than %s; rerun pack", ErrStaleBinaryGraph, metadataFile)
+	}
+
+	size := int64(len(data))
+	if header.VertexCount > uint64(size/vertexSize) || header.ArcCount > uint64(size/arcSize) {
+		return nil, fmt.Errorf("%w: header counts exceed the file size", ErrInvalidBinaryGraph)
+	}
+
+	vertexCount, arcCount := int64(header.VertexCount), int64(header.ArcCount)
+	verticesAt := binaryGraphHeaderSize
+	offsetsAt := verticesAt + vertexCount*vertexSize
+	edgeEndsAt := offsetsAt + (vertexCount+1)*offsetSize
+	arcsAt := edgeEndsAt + vertexCount*offsetSize
+	if want := arcsAt + arcCount*arcSize; size != want {
+		return nil, fmt.Errorf("%w: file is %d bytes, header describes %d", ErrInvalidBinaryGraph, size, want)
+	}
+
+	graph := &CSRGraph{
+		Vertices:         sectionSlice[Vertex](data, verticesAt, vertexCount),
+		Offsets:          sectionSlice[int64](data, offsetsAt, vertexCount+1),
+		EdgeEnds:         sectionSlice[int64](data, edgeEndsAt, vertexCount),
+		Arcs:             sectionSlice[Arc](data, arcsAt, arcCount),
+		Edges:            int(header.EdgeCount),
+		Shortcuts:        int(header.ShortcutCount),
+		SkippedEdges:     int(header.SkippedEdges),
+		SkippedShortcuts: int(header.SkippedShortcuts),
+	}
+	if err := graph.checkAdjacency(); err != nil {
+		return nil, err
+	}
+
+	return graph, nil
+}
+
+// checkAdjacency verifies that the offsets partition the arcs in order, each vertex's base edges end within
+// its arcs, and every arc points at a vertex of the graph
+func (g *CSRGraph) checkAdjacency() error {
+	vertexCount, arcCount := int64(len(g.Vertices)), int64(len(g.Arcs))
+	if g.Offsets[0] != 0 || g.Offsets[vertexCount] != arcCount {
+		return fmt.Errorf("%w: adjacency offsets do not cover the arcs", ErrInvalidBinaryGraph)
+	}
+	for vertex := range vertexCount {
+		start, edgeEnd, end := g.Offsets[vertex], g.EdgeEnds[vertex], g.Offsets[vertex+1]
+		if start > edgeEnd || edgeEnd > end {
+			return fmt.Errorf("%w: adjacency offsets of vertex %d are out of order", ErrInvalidBinaryGraph, vertex)
+		}
+	}
+	for idx, arc := range g.Arcs {
+		if arc.To < 0 || arc.To >= vertexCount {
+			return fmt.Errorf("%w: arc %d points at vertex %d outside the graph", ErrInvalidBinaryGraph, idx, arc.To)
+		}
+	}
+
+	return nil
+}
+
+// sectionSlice views count values of T starting at byte offset at of data
+func sectionSlice[T any](data []byte, at, count int64) []T {
+	if count == 0 {
+		return []T{}
+	}
+
+	return unsafe.Slice((*T)(unsafe.Pointer(&data[at])), count)
+}
+
+// sectionBytes views the values of a fixed-size type as their in-memory bytes
+func sectionBytes[T any](values []T) []byte {
+	if len(values) == 0 {
+		return nil
+	}
+
+	var zero T
+
+	return unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(values))), len(values)*int(unsafe.Sizeof(zero)))
+}
+
+func hostIsLittleEndian() bool {
+	return binary.NativeEndian.Uint16([]byte{1, 0}) == 1
+}
+
+// SizeBytes returns the size of the mapping
+func (m *MappedGraph) SizeBytes() int64 {
+	return int64(len(m.data))
+}
+
+// ResidentBytes returns how much of the mapping is currently in physical memory.
+// ok is false when the platform cannot report it.
+func (m *MappedGraph) ResidentBytes() (resident int64, ok bool) {
+	return residentBytes(m.data)
+}
+
+// Close unmaps the graph
+func (m *MappedGraph) Close() error {
+	if m.data == nil {
+		return nil
+	}
+
+	data := m.data
+	m.data, m.Graph = nil, nil
+
+	return unmapFile(data)
+}
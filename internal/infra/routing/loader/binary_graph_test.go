@@ -0,0 +1,136 @@
+package loader
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testGraphData() *GraphData {
+	return &GraphData{
+		Vertices: []Vertex{
+			{ID: 0, Lat: 25.0330, Lng: 121.5654, OrderPos: 0, Importance: 1},
+			{ID: 1, Lat: 25.0478, Lng: 121.5170, OrderPos: 1, Importance: 2},
+			{ID: 2, Lat: 25.0400, Lng: 121.5400, OrderPos: 2, Importance: 3},
+		},
+		// Vertex 7 does not exist
+		Edges:     []Edge{{From: 1, To: 2, Weight: 1500}, {From: 0, To: 1, Weight: 2000}, {From: 2, To: 7, Weight: 900}},
+		Shortcuts: []Shortcut{{From: 0, To: 2, Weight: 3500, ViaNode: 1}},
+	}
+}
+
+func TestBuildCSRGraph(t *testing.T) {
+	graph := BuildCSRGraph(testGraphData())
+
+	assert.Equal(t, []int64{0, 2, 3, 3}, graph.Offsets)
+	// Base edges come before shortcuts
+	assert.Equal(t, []Arc{{To: 1, Weight: 2000}, {To: 2, Weight: 3500}}, graph.Neighbors(0))
+	assert.Equal(t, []Arc{{To: 1, Weight: 2000}}, graph.BaseEdges(0))
+	assert.Equal(t, []Arc{{To: 2, Weight: 1500}}, graph.Neighbors(1))
+	assert.Empty(t, graph.Neighbors(2))
+	assert.Equal(t, 3, graph.Edges)
+	assert.Equal(t, 1, graph.Shortcuts)
+	assert.Equal(t, 1, graph.SkippedEdges)
+	assert.Equal(t, 0, graph.SkippedShortcuts)
+	assert.Equal(t, int64(3*40+7*8+3*16), graph.SizeBytes())
+}
+
+func TestMapBinaryGraph_RoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	graph := BuildCSRGraph(testGraphData())
+	require.NoError(t, WriteBinaryGraph(filepath.Join(tmpDir, BinaryGraphFile), graph, packSource(t, tmpDir)))
+
+	mapped, err := MapBinaryGraph(tmpDir)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, mapped.Close()) })
+
+	assert.Equal(t, graph, mapped.Graph)
+	assert.Equal(t, binaryGraphHeaderSize+graph.SizeBytes(), mapped.SizeBytes())
+
+	resident, ok := mapped.ResidentBytes()
+	if ok {
+		assert.Positive(t, resident)
+		assert.LessOrEqual(t, resident, mapped.SizeBytes())
+	}
+}
+
+// packSource returns the SourceFingerprint of dataDir
+func packSource(t *testing.T, dataDir string) [32]byte {
+	t.Helper()
+
+	source, err := SourceFingerprint(dataDir)
+	require.NoError(t, err)
+
+	return source
+}
+
+func TestMapBinaryGraph_Invalid(t *testing.T) {
+	valid := t.TempDir()
+	graph := BuildCSRGraph(testGraphData())
+	require.NoError(t, WriteBinaryGraph(filepath.Join(valid, BinaryGraphFile), graph, packSource(t, valid)))
+	data, err := os.ReadFile(filepath.Join(valid, BinaryGraphFile))
+	require.NoError(t, err)
+
+	badMagic := append([]byte("XXXX"), data[4:]...)
+
+	// Section layout from the header: 3 vertices, 4 offsets, 3 edge ends, then the arcs
+	offsetsAt := binaryGraphHeaderSize + 3*vertexSize
+	edgeEndsAt := offsetsAt + 4*offsetSize
+	arcsAt := edgeEndsAt + 3*offsetSize
+	patch := func(at int64, value int64) []byte {
+		patched := slices.Clone(data)
+		binary.LittleEndian.PutUint64(patched[at:], uint64(value))
+
+		return patched
+	}
+
+	tests := map[string][]byte{
+		"truncated header":   data[:10],
+		"truncated sections": data[:len(data)-8],
+		"unknown format":     badMagic,
+		// Vertex 1 starts after vertex 2, although the first and last offsets still cover the arcs
+		"decreasing offsets": patch(offsetsAt+2*offsetSize, 1),
+		"edge end past arcs": patch(edgeEndsAt, 3),
+		"arc outside graph":  patch(arcsAt, 7),
+		"negative arc":       patch(arcsAt+arcSize, -1),
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, BinaryGraphFile), content, 0644))
+
+			_, err := MapBinaryGraph(tmpDir)
+
+			require.ErrorIs(t, err, ErrInvalidBinaryGraph)
+		})
+	}
+}
+
+func TestMapBinaryGraph_Stale(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, metadataFile), []byte(`{"version":"1"}`), 0644))
+	require.NoError(t, WriteBinaryGraph(filepath.Join(tmpDir, BinaryGraphFile), BuildCSRGraph(testGraphData()), packSource(t, tmpDir)))
+
+	mapped, err := MapBinaryGraph(tmpDir)
+	require.NoError(t, err)
+	require.NoError(t, mapped.Close())
+
+	// Regenerated data comes with new metadata, so the graph packed from the old data is refused
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, metadataFile), []byte(`{"version":"2"}`), 0644))
+
+	_, err = MapBinaryGraph(tmpDir)
+
+	require.ErrorIs(t, err, ErrStaleBinaryGraph)
+}
+
+func TestMapBinaryGraph_MissingFile(t *testing.T) {
+	_, err := MapBinaryGraph(t.TempDir())
+
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -34,11 +34,20 @@ type Shortcut struct {
 	ViaNode int64   // Intermediate node this shortcut bypasses
 }
 
+// Restriction forbids turning from From onto To at the via vertex: a route may not
+// traverse the edge From->Via and then the edge Via->To
+type Restriction struct {
+	From int64 // Vertex the route arrives from
+	Via  int64 // Vertex where the turn is made
+	To   int64 // Vertex the route may not continue to
+}
+
 // GraphData holds all loaded graph data
 type GraphData struct {
-	Vertices  []Vertex
-	Edges     []Edge
-	Shortcuts []Shortcut
+	Vertices     []Vertex
+	Edges        []Edge
+	Shortcuts    []Shortcut
+	Restrictions []Restriction
 }
 
 // CSVLoader handles loading of routing data from CSV files
@@ -68,10 +77,16 @@ func (l *CSVLoader) Load() (*GraphData, error) {
 		return nil, err
 	}
 
+	restrictions, err := l.LoadRestrictions()
+	if err != nil {
+		return nil, err
+	}
+
 	return &GraphData{
-		Vertices:  vertices,
-		Edges:     edges,
-		Shortcuts: shortcuts,
+		Vertices:     vertices,
+		Edges:        edges,
+		Shortcuts:    shortcuts,
+		Restrictions: restrictions,
 	}, nil
 }
 
@@ -215,6 +230,56 @@ func (l *CSVLoader) LoadShortcuts() ([]Shortcut, error) {
 	return shortcuts, nil
 }
 
+// LoadRestrictions loads turn restrictions from restrictions.csv
+// Expected CSV format: from,via,to
+func (l *CSVLoader) LoadRestrictions() ([]Restriction, error) {
+	path := filepath.Join(l.dataDir, "restrictions.csv")
+	file, err := os.Open(path)
+	if err != nil {
+		// Restrictions file is optional if the data was converted without restrictions
+		if os.IsNotExist(err) {
+			return []Restriction{}, nil
+		}
+
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+
+	// Skip header row
+	if _, err := reader.Read(); err != nil {
+		return nil, fmt.Errorf("read %s header: %w", path, err)
+	}
+
+	var restrictions []Restriction
+	lineNum := 1
+
+	for {
+		record, readErr := reader.Read()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("read %s line %d: %w", path, lineNum+1, readErr)
+		}
+		lineNum++
+
+		if len(record) < 3 {
+			return nil, fmt.Errorf("invalid restrictions.csv format at line %d: expected 3 columns, got %d", lineNum, len(record))
+		}
+
+		restriction, parseErr := parseRestriction(record, lineNum)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+
+		restrictions = append(restrictions, restriction)
+	}
+
+	return restrictions, nil
+}
+
 func parseVertex(record []string, _ int) (Vertex, error) {
 	vertexID, err := strconv.ParseInt(record[0], 10, 64)
 	if err != nil {
@@ -301,3 +366,26 @@ func parseShortcut(record []string, _ int) (Shortcut, error) {
 		ViaNode: viaNode,
 	}, nil
 }
+
+func parseRestriction(record []string, _ int) (Restriction, error) {
+	from, err := strconv.ParseInt(record[0], 10, 64)
+	if err != nil {
+		return Restriction{}, fmt.Errorf("parse restriction from vertex: %w", err)
+	}
+
+	via, err := strconv.ParseInt(record[1], 10, 64)
+	if err != nil {
+		return Restriction{}, fmt.Errorf("parse restriction via vertex: %w", err)
+	}
+
+	toVertex, err := strconv.ParseInt(record[2], 10, 64)
+	if err != nil {
+		return Restriction{}, fmt.Errorf("parse restriction to vertex: %w", err)
+	}
+
+	return Restriction{
+		From: from,
+		Via:  via,
+		To:   toVertex,
+	}, nil
+}
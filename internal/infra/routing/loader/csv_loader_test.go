@@ -91,6 +91,32 @@ func TestCSVLoader_LoadShortcuts_MissingFile(t *testing.T) {
 	assert.Empty(t, shortcuts)
 }
 
+func TestCSVLoader_LoadRestrictions(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	restrictionsCSV := `from,via,to
+0,1,2
+3,1,0
+`
+	err := os.WriteFile(filepath.Join(tmpDir, "restrictions.csv"), []byte(restrictionsCSV), 0644)
+	require.NoError(t, err)
+
+	loader := NewCSVLoader(tmpDir)
+	restrictions, err := loader.LoadRestrictions()
+	require.NoError(t, err)
+
+	assert.Equal(t, []Restriction{{From: 0, Via: 1, To: 2}, {From: 3, Via: 1, To: 0}}, restrictions)
+}
+
+func TestCSVLoader_LoadRestrictions_MissingFile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	loader := NewCSVLoader(tmpDir)
+	restrictions, err := loader.LoadRestrictions()
+	require.NoError(t, err) // Data converted with --no-restrictions has no restrictions.csv
+	assert.Empty(t, restrictions)
+}
+
 func TestCSVLoader_Load_Full(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -117,6 +143,7 @@ func TestCSVLoader_Load_Full(t *testing.T) {
 	assert.Len(t, data.Vertices, 2)
 	assert.Len(t, data.Edges, 1)
 	assert.Len(t, data.Shortcuts, 1)
+	assert.Empty(t, data.Restrictions)
 }
 
 func TestCSVLoader_LoadVertices_InvalidFormat(t *testing.T) {
@@ -53,9 +53,12 @@ type FileInfo struct {
 	MD5       string `json:"md5,omitempty"`
 }
 
+// metadataFile is the name of the metadata written next to the CSV files
+const metadataFile = "metadata.json"
+
 // LoadMetadata loads and parses the metadata.json file from the given directory
 func LoadMetadata(dataDir string) (*RoutingMetadata, error) {
-	metadataPath := filepath.Join(dataDir, "metadata.json")
+	metadataPath := filepath.Join(dataDir, metadataFile)
 
 	data, err := os.ReadFile(metadataPath)
 	if err != nil {
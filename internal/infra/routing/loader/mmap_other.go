@@ -0,0 +1,17 @@
+//go:build !unix
+
+package loader
+
+import "os"
+
+// mappedWithMmap reports whether mapFile maps files instead of reading them
+const mappedWithMmap = false
+
+// mapFile reads the whole file into memory on platforms without mmap support
+func mapFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func unmapFile([]byte) error {
+	return nil
+}
@@ -0,0 +1,42 @@
+//go:build unix
+
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// mappedWithMmap reports whether mapFile maps files instead of reading them
+const mappedWithMmap = true
+
+// mapFile maps the whole file read-only
+func mapFile(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() == 0 {
+		return nil, errors.New("file is empty")
+	}
+
+	data, err := unix.Mmap(int(file.Fd()), 0, int(info.Size()), unix.PROT_READ, unix.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("mmap: %w", err)
+	}
+
+	return data, nil
+}
+
+func unmapFile(data []byte) error {
+	return unix.Munmap(data)
+}
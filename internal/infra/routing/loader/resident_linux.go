@@ -0,0 +1,36 @@
+package loader
+
+import (
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// residentBytes counts the pages of a mapping that are in physical memory using mincore(2)
+func residentBytes(data []byte) (int64, bool) {
+	if len(data) == 0 {
+		return 0, true
+	}
+
+	pageSize := os.Getpagesize()
+	pages := make([]byte, (len(data)+pageSize-1)/pageSize)
+	_, _, errno := unix.Syscall(
+		unix.SYS_MINCORE,
+		uintptr(unsafe.Pointer(&data[0])),
+		uintptr(len(data)),
+		uintptr(unsafe.Pointer(&pages[0])),
+	)
+	if errno != 0 {
+		return 0, false
+	}
+
+	var resident int64
+	for _, page := range pages {
+		if page&1 != 0 {
+			resident += int64(pageSize)
+		}
+	}
+
+	return min(resident, int64(len(data))), true
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package loader
+
+// residentBytes reports the whole mapping as resident only where it was read into memory
+func residentBytes(data []byte) (int64, bool) {
+	if !mappedWithMmap {
+		return int64(len(data)), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,238 @@
+package pmtiles
+
+import (
+	"container/heap"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/paulmach/orb"
+)
+
+// RoadClass ranks roads for the composite cost; the zero value covers residential and unknown roads
+type RoadClass uint8
+
+const (
+	RoadClassLocal RoadClass = iota
+	RoadClassTertiary
+	RoadClassSecondary
+	RoadClassPrimary // motorway, trunk, and primary
+)
+
+// roadClassPenalties scales the road class weight, from no penalty on primary roads to the full weight on local ones
+var roadClassPenalties = [...]float64{
+	RoadClassLocal:     1.0,
+	RoadClassTertiary:  0.5,
+	RoadClassSecondary: 0.25,
+	RoadClassPrimary:   0,
+}
+
+// Turns whose heading changes by more than this pay the turn penalty
+const turnThresholdDegrees = 45.0
+
+// roadClassFor maps a highway tag, including its _link variant, to a road class
+func roadClassFor(highway string) RoadClass {
+	switch strings.TrimSuffix(highway, "_link") {
+	case "motorway", "trunk", roadTypePrimary:
+		return RoadClassPrimary
+	case roadTypeSecondary:
+		return RoadClassSecondary
+	case "tertiary":
+		return RoadClassTertiary
+	default:
+		return RoadClassLocal
+	}
+}
+
+func (c RoadClass) penalty() float64 {
+	if int(c) >= len(roadClassPenalties) {
+		return roadClassPenalties[RoadClassLocal]
+	}
+
+	return roadClassPenalties[c]
+}
+
+// CostWeights combines travel time, turns, and road class into one edge cost.
+// An edge costs Duration × (DurationWeight + RoadClassWeight × class penalty), plus
+// TurnPenaltySeconds when entering it turns sharper than turnThresholdDegrees.
+// The zero value is disabled and leaves the pathfinder minimizing distance.
+type CostWeights struct {
+	DurationWeight     float64 // Cost per second of travel time
+	TurnPenaltySeconds float64 // Cost added for each sharp turn
+	RoadClassWeight    float64 // Extra cost per second of travel on lower-class roads
+}
+
+// Enabled reports whether any weight is set
+func (w CostWeights) Enabled() bool {
+	return w.DurationWeight > 0 || w.TurnPenaltySeconds > 0 || w.RoadClassWeight > 0
+}
+
+// arrival is a search state: a node together with the node it was entered from, so turns can be priced.
+// from is zero at the source, since node IDs start at one.
+type arrival struct {
+	node NodeID
+	from NodeID
+}
+
+// costLabel represents an arrival in the composite cost priority queue
+type costLabel struct {
+	arrival  arrival
+	cost     float64
+	distance float64
+	duration float64
+	index    int // Index in the heap
+}
+
+// costQueue implements heap.Interface ordered by composite cost
+type costQueue []*costLabel
+
+func (q costQueue) Len() int { return len(q) }
+
+func (q costQueue) Less(i, j int) bool { return q[i].cost < q[j].cost }
+
+func (q costQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *costQueue) Push(x any) {
+	label := x.(*costLabel)
+	label.index = len(*q)
+	*q = append(*q, label)
+}
+
+func (q *costQueue) Pop() any {
+	old := *q
+	n := len(old)
+	label := old[n-1]
+	old[n-1] = nil
+	label.index = -1
+	*q = old[0 : n-1]
+
+	return label
+}
+
+// compositeShortestPath finds the minimum composite cost path from source to target, including its geometry
+func (pf *Pathfinder) compositeShortestPath(sourceID, targetID NodeID) PathResult {
+	result := pf.compositePathToMany(sourceID, []NodeID{targetID}, true)[0]
+	if !result.IsReachable {
+		return result
+	}
+
+	return pf.withGeometry(result)
+}
+
+// compositePathToMany finds the minimum composite cost paths from source to multiple targets.
+// The search runs over arrivals rather than nodes so a turn penalty depends on the incoming edge.
+// Results report the distance and duration of the chosen path, plus its node sequence when trackPath is set.
+func (pf *Pathfinder) compositePathToMany(sourceID NodeID, targetIDs []NodeID, trackPath bool) []PathResult {
+	results := make([]PathResult, len(targetIDs))
+
+	targetSet, remainingTargets := pf.initTargetSet(targetIDs)
+	if !pf.graph.hasNode(sourceID) || remainingTargets == 0 {
+		return results
+	}
+
+	start := arrival{node: sourceID}
+	costs := map[arrival]float64{start: 0}
+	settled := make(map[arrival]bool)
+	reached := make(map[NodeID]bool)
+	previous := make(map[arrival]arrival)
+
+	queue := make(costQueue, 0)
+	heap.Init(&queue)
+	heap.Push(&queue, &costLabel{arrival: start})
+
+	for queue.Len() > 0 && remainingTargets > 0 {
+		current := heap.Pop(&queue).(*costLabel)
+
+		if settled[current.arrival] {
+			continue
+		}
+		settled[current.arrival] = true
+
+		node := current.arrival.node
+		if idx, isTarget := targetSet[node]; isTarget && !reached[node] {
+			reached[node] = true
+			results[idx] = PathResult{
+				Distance:    current.distance,
+				Duration:    current.duration,
+				IsReachable: true,
+			}
+			if trackPath {
+				results[idx].Path = buildArrivalPath(previous, start, current.arrival)
+			}
+			remainingTargets--
+		}
+
+		for _, edge := range pf.graph.Edges[node] {
+			next := arrival{node: edge.To, from: node}
+			if settled[next] {
+				continue
+			}
+
+			newCost := current.cost + pf.edgeCost(current.arrival.from, node, edge)
+			if known, seen := costs[next]; seen && newCost >= known {
+				continue
+			}
+
+			costs[next] = newCost
+			if trackPath {
+				previous[next] = current.arrival
+			}
+			heap.Push(&queue, &costLabel{
+				arrival:  next,
+				cost:     newCost,
+				distance: current.distance + edge.Distance,
+				duration: current.duration + edge.Duration,
+			})
+		}
+	}
+
+	return results
+}
+
+// buildArrivalPath reconstructs the node sequence from the start arrival to end using predecessor links
+func buildArrivalPath(previous map[arrival]arrival, start, end arrival) []NodeID {
+	path := []NodeID{end.node}
+	for current := end; current != start; {
+		current = previous[current]
+		path = append(path, current.node)
+	}
+	slices.Reverse(path)
+
+	return path
+}
+
+// edgeCost prices an edge entered at via after arriving from the given node
+func (pf *Pathfinder) edgeCost(from, via NodeID, edge Edge) float64 {
+	cost := edge.Duration * (pf.cost.DurationWeight + pf.cost.RoadClassWeight*edge.Class.penalty())
+	if pf.cost.TurnPenaltySeconds > 0 && from != 0 && pf.isSharpTurn(from, via, edge.To) {
+		cost += pf.cost.TurnPenaltySeconds
+	}
+
+	return cost
+}
+
+// isSharpTurn reports whether continuing from-via-to changes heading by more than turnThresholdDegrees
+func (pf *Pathfinder) isSharpTurn(from, via, to NodeID) bool {
+	fromPoint, fromOK := pf.graph.node(from)
+	viaPoint, viaOK := pf.graph.node(via)
+	toPoint, toOK := pf.graph.node(to)
+	if !fromOK || !viaOK || !toOK {
+		return false
+	}
+
+	return turnAngleDegrees(fromPoint, viaPoint, toPoint) > turnThresholdDegrees
+}
+
+// turnAngleDegrees returns the heading change at via in [0, 180], using a local equirectangular approximation
+func turnAngleDegrees(from, via, to orb.Point) float64 {
+	lngScale := math.Cos(via[1] * math.Pi / 180)
+
+	inX, inY := (via[0]-from[0])*lngScale, via[1]-from[1]
+	outX, outY := (to[0]-via[0])*lngScale, to[1]-via[1]
+
+	return math.Abs(math.Atan2(inX*outY-inY*outX, inX*outX+inY*outY)) * 180 / math.Pi
+}
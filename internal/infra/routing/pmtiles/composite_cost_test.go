@@ -0,0 +1,147 @@
+package pmtiles
+
+import (
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildArterialShortcutGraph connects A and C by a gently curving primary road and by a slightly
+// shorter residential shortcut that jogs south with two right-angle turns. Both have the same speed limit,
+// so the shortcut wins on distance and, narrowly, on duration.
+func buildArterialShortcutGraph(t *testing.T) (*RoadGraph, NodeID, NodeID) {
+	t.Helper()
+
+	graph := NewRoadGraph()
+	start := orb.Point{121.500, 25.000}
+	end := orb.Point{121.510, 25.000}
+
+	graph.AddSegment(&RoadSegment{
+		Points:   []orb.Point{start, {121.502, 25.002}, {121.505, 25.003}, {121.508, 25.002}, end},
+		Highway:  roadTypePrimary,
+		MaxSpeed: 40,
+	})
+	graph.AddSegment(&RoadSegment{
+		Points:   []orb.Point{start, {121.500, 24.999}, {121.510, 24.999}, end},
+		Highway:  roadTypeResidential,
+		MaxSpeed: 40,
+	})
+
+	source, _, ok := graph.FindNearestNode(start)
+	require.True(t, ok)
+	target, _, ok := graph.FindNearestNode(end)
+	require.True(t, ok)
+
+	return graph, source, target
+}
+
+func TestPathfinder_CompositeCost_PrefersArterialOverResidentialShortcut(t *testing.T) {
+	graph, source, target := buildArterialShortcutGraph(t)
+
+	shortest := NewPathfinder(graph).ShortestPath(source, target)
+	require.True(t, shortest.IsReachable)
+	assert.InDelta(t, 1231, shortest.Distance, 5, "distance routing takes the residential shortcut")
+
+	tests := []struct {
+		name         string
+		cost         CostWeights
+		wantDistance float64
+	}{
+		{name: "duration only", cost: CostWeights{DurationWeight: 1}, wantDistance: 1231},
+		{name: "road class preference", cost: CostWeights{DurationWeight: 1, RoadClassWeight: 0.5}, wantDistance: 1246},
+		{name: "turn penalty", cost: CostWeights{DurationWeight: 1, TurnPenaltySeconds: 10}, wantDistance: 1246},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pf := NewPathfinderWithCost(graph, tt.cost)
+
+			result := pf.ShortestPath(source, target)
+
+			require.True(t, result.IsReachable)
+			assert.InDelta(t, tt.wantDistance, result.Distance, 5)
+			assert.InDelta(t, result.Distance/1000/40*3600, result.Duration, 0.01)
+			assert.Equal(t, result, pf.AStarPath(source, target))
+			require.Len(t, result.Geometry, len(result.Path))
+			assert.Equal(t, source, result.Path[0])
+			assert.Equal(t, target, result.Path[len(result.Path)-1])
+
+			// The bulk query reports the same route without tracking its nodes
+			bulk := result
+			bulk.Path, bulk.Geometry = nil, nil
+			assert.Equal(t, []PathResult{bulk}, pf.ShortestPathToMany(source, []NodeID{target}))
+		})
+	}
+}
+
+func TestPathfinder_CompositeCost_ShortestPathToMany(t *testing.T) {
+	graph, source, target := buildArterialShortcutGraph(t)
+	isolated := graph.getOrCreateNode(orb.Point{121.600, 25.100})
+	pf := NewPathfinderWithCost(graph, CostWeights{DurationWeight: 1, RoadClassWeight: 0.5})
+
+	results := pf.ShortestPathToMany(source, []NodeID{target, source, isolated, NodeID(9999)})
+
+	require.Len(t, results, 4)
+	assert.True(t, results[0].IsReachable)
+	assert.InDelta(t, 1246, results[0].Distance, 5)
+	assert.Equal(t, PathResult{IsReachable: true}, results[1])
+	assert.False(t, results[2].IsReachable)
+	assert.False(t, results[3].IsReachable)
+}
+
+func TestCostWeights_Enabled(t *testing.T) {
+	assert.False(t, CostWeights{}.Enabled())
+	assert.True(t, CostWeights{DurationWeight: 1}.Enabled())
+	assert.True(t, CostWeights{TurnPenaltySeconds: 5}.Enabled())
+}
+
+func TestRoadClassFor(t *testing.T) {
+	tests := map[string]RoadClass{
+		"motorway":          RoadClassPrimary,
+		"trunk_link":        RoadClassPrimary,
+		roadTypePrimary:     RoadClassPrimary,
+		roadTypeSecondary:   RoadClassSecondary,
+		"secondary_link":    RoadClassSecondary,
+		"tertiary":          RoadClassTertiary,
+		roadTypeResidential: RoadClassLocal,
+		"service":           RoadClassLocal,
+		"":                  RoadClassLocal,
+	}
+
+	for highway, want := range tests {
+		assert.Equal(t, want, roadClassFor(highway), highway)
+	}
+}
+
+func TestRoadGraph_AddSegment_RecordsRoadClass(t *testing.T) {
+	graph := NewRoadGraph()
+	graph.AddSegment(&RoadSegment{
+		Points:  []orb.Point{{121.500, 25.000}, {121.501, 25.000}},
+		Highway: roadTypeSecondary,
+	})
+
+	for _, edges := range graph.Edges {
+		for _, edge := range edges {
+			assert.Equal(t, RoadClassSecondary, edge.Class)
+		}
+	}
+
+	merged := NewRoadGraph()
+	mergeGraphs(merged, graph)
+	for _, edges := range merged.Edges {
+		for _, edge := range edges {
+			assert.Equal(t, RoadClassSecondary, edge.Class)
+		}
+	}
+}
+
+func TestTurnAngleDegrees(t *testing.T) {
+	via := orb.Point{121.500, 25.000}
+
+	assert.InDelta(t, 0, turnAngleDegrees(orb.Point{121.499, 25.000}, via, orb.Point{121.501, 25.000}), 0.01)
+	assert.InDelta(t, 90, turnAngleDegrees(orb.Point{121.499, 25.000}, via, orb.Point{121.500, 25.001}), 0.01)
+	assert.InDelta(t, 90, turnAngleDegrees(orb.Point{121.499, 25.000}, via, orb.Point{121.500, 24.999}), 0.01)
+	assert.InDelta(t, 180, turnAngleDegrees(orb.Point{121.499, 25.000}, via, orb.Point{121.499, 25.000}), 0.01)
+}
@@ -0,0 +1,133 @@
+package pmtiles
+
+import (
+	"fmt"
+	"math"
+
+	"radar/internal/usecase"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/maptile"
+)
+
+// getTilesForCorridor returns the tiles within buffer tiles of the great-circle line from source to each
+// target, each tile once. Unlike the bounding box it skips the area far from every line, which for long,
+// thin routes is most of the box. It rejects non-finite coordinates and corridors of more than maxSpan²
+// tiles, the most a bounding box within maxSpan per axis can hold. A buffer of 0 keeps only the tiles the
+// lines cross.
+func getTilesForCorridor(source usecase.Coordinate, targets []usecase.Coordinate, zoom maptile.Zoom, buffer, maxSpan int) ([]maptile.Tile, error) {
+	for _, coord := range append([]usecase.Coordinate{source}, targets...) {
+		for _, value := range []float64{coord.Lat, coord.Lng} {
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				return nil, errInvalidTileBounds
+			}
+		}
+	}
+	buffer = max(buffer, 0)
+	maxTiles := maxSpan * maxSpan
+	corridor := newTileCorridor(zoom, buffer)
+
+	from := orb.Point{source.Lng, source.Lat}
+	fromTile := maptile.At(from, zoom)
+	if len(targets) == 0 {
+		corridor.addAround(fromTile)
+	}
+
+	for _, target := range targets {
+		to := orb.Point{target.Lng, target.Lat}
+		toTile := maptile.At(to, zoom)
+
+		// The line crosses at least one tile per step along its longer axis
+		spanX, spanY := tileDistance(fromTile.X, toTile.X), tileDistance(fromTile.Y, toTile.Y)
+		if max(spanX, spanY) >= int64(maxTiles) {
+			return nil, fmt.Errorf("%w: corridor covers more than %d tiles", errTileSpanExceeded, maxTiles)
+		}
+
+		// Sampling twice per tile crossed keeps consecutive samples in the same or adjacent tiles
+		steps := 2*(spanX+spanY) + 1
+		last := fromTile
+		corridor.addAround(fromTile)
+		for step := int64(1); step <= steps; step++ {
+			tile := maptile.At(greatCirclePoint(from, to, float64(step)/float64(steps)), zoom)
+			if tile == last {
+				continue
+			}
+			// A diagonal step may cut through the corner of either tile beside it, which no buffer covers when it is 0
+			if tile.X != last.X && tile.Y != last.Y {
+				corridor.addAround(maptile.Tile{X: tile.X, Y: last.Y, Z: zoom})
+				corridor.addAround(maptile.Tile{X: last.X, Y: tile.Y, Z: zoom})
+			}
+			last = tile
+			corridor.addAround(tile)
+
+			if len(corridor.tiles) > maxTiles {
+				return nil, fmt.Errorf("%w: corridor covers more than %d tiles", errTileSpanExceeded, maxTiles)
+			}
+		}
+	}
+
+	if len(corridor.tiles) > maxTiles {
+		return nil, fmt.Errorf("%w: corridor covers more than %d tiles", errTileSpanExceeded, maxTiles)
+	}
+
+	return corridor.tiles, nil
+}
+
+// tileCorridor collects the distinct tiles around the tiles a line passes through
+type tileCorridor struct {
+	zoom   maptile.Zoom
+	buffer int64
+	last   int64 // Highest tile index on either axis at zoom
+	seen   map[maptile.Tile]struct{}
+	tiles  []maptile.Tile
+}
+
+func newTileCorridor(zoom maptile.Zoom, buffer int) *tileCorridor {
+	return &tileCorridor{
+		zoom:   zoom,
+		buffer: int64(buffer),
+		last:   int64(1)<<zoom - 1,
+		seen:   make(map[maptile.Tile]struct{}),
+	}
+}
+
+// addAround adds the tiles within the buffer of center, clipped to the tile grid
+func (c *tileCorridor) addAround(center maptile.Tile) {
+	minX, maxX := max(int64(center.X)-c.buffer, 0), min(int64(center.X)+c.buffer, c.last)
+	minY, maxY := max(int64(center.Y)-c.buffer, 0), min(int64(center.Y)+c.buffer, c.last)
+
+	for x := minX; x <= maxX; x++ {
+		for y := minY; y <= maxY; y++ {
+			tile := maptile.Tile{X: uint32(x), Y: uint32(y), Z: c.zoom}
+			if _, ok := c.seen[tile]; ok {
+				continue
+			}
+			c.seen[tile] = struct{}{}
+			c.tiles = append(c.tiles, tile)
+		}
+	}
+}
+
+func tileDistance(a, b uint32) int64 {
+	return max(int64(a)-int64(b), int64(b)-int64(a))
+}
+
+// greatCirclePoint returns the point the given fraction of the way along the great circle from one point to another
+func greatCirclePoint(from, to orb.Point, fraction float64) orb.Point {
+	lat1, lng1 := from.Lat()*math.Pi/180, from.Lon()*math.Pi/180
+	lat2, lng2 := to.Lat()*math.Pi/180, to.Lon()*math.Pi/180
+
+	x1, y1, z1 := math.Cos(lat1)*math.Cos(lng1), math.Cos(lat1)*math.Sin(lng1), math.Sin(lat1)
+	x2, y2, z2 := math.Cos(lat2)*math.Cos(lng2), math.Cos(lat2)*math.Sin(lng2), math.Sin(lat2)
+
+	angle := math.Acos(max(min(x1*x2+y1*y2+z1*z2, 1), -1))
+	if angle < 1e-12 {
+		return from
+	}
+
+	a := math.Sin((1-fraction)*angle) / math.Sin(angle)
+	b := math.Sin(fraction*angle) / math.Sin(angle)
+	x, y, z := a*x1+b*x2, a*y1+b*y2, a*z1+b*z2
+
+	return orb.Point{math.Atan2(y, x) * 180 / math.Pi, math.Atan2(z, math.Hypot(x, y)) * 180 / math.Pi}
+}
@@ -0,0 +1,121 @@
+package pmtiles
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"radar/internal/usecase"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/maptile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTilesForCorridor_DiagonalPairLoadsFewerTilesThanBoundingBox(t *testing.T) {
+	// Roughly 60km apart on a diagonal across northern Taiwan
+	source := usecase.Coordinate{Lat: 24.75, Lng: 121.05}
+	target := usecase.Coordinate{Lat: 25.15, Lng: 121.50}
+	svc := &pmtilesRoutingService{zoomLevel: 14, maxTileSpan: testMaxTileSpan, corridorBufferTiles: 1}
+
+	boxTiles, err := svc.areaTiles(source, []usecase.Coordinate{target})
+	require.NoError(t, err)
+
+	svc.corridorTiles = true
+	corridorTiles, err := svc.areaTiles(source, []usecase.Coordinate{target})
+	require.NoError(t, err)
+
+	assert.Less(t, len(corridorTiles)*3, len(boxTiles), "corridor %d tiles, bounding box %d", len(corridorTiles), len(boxTiles))
+
+	// The corridor still covers both endpoints and every step between them
+	covered := make(map[maptile.Tile]bool, len(corridorTiles))
+	for _, tile := range corridorTiles {
+		assert.False(t, covered[tile], "tile %s listed twice", tileKey(tile))
+		covered[tile] = true
+	}
+	from, to := orb.Point{source.Lng, source.Lat}, orb.Point{target.Lng, target.Lat}
+	for step := 0; step <= 100; step++ {
+		point := greatCirclePoint(from, to, float64(step)/100)
+		assert.True(t, covered[maptile.At(point, 14)], "line point %v outside the corridor", point)
+	}
+}
+
+func TestGetTilesForCorridor_ZeroBufferKeepsCrossedTiles(t *testing.T) {
+	source := usecase.Coordinate{Lat: 24.75, Lng: 121.05}
+	target := usecase.Coordinate{Lat: 25.15, Lng: 121.50}
+
+	buffered, err := getTilesForCorridor(source, []usecase.Coordinate{target}, 14, 1, testMaxTileSpan)
+	require.NoError(t, err)
+	tiles, err := getTilesForCorridor(source, []usecase.Coordinate{target}, 14, 0, testMaxTileSpan)
+	require.NoError(t, err)
+
+	assert.Less(t, len(tiles)*2, len(buffered), "unbuffered %d tiles, buffered %d", len(tiles), len(buffered))
+	covered := make(map[maptile.Tile]bool, len(tiles))
+	for _, tile := range tiles {
+		covered[tile] = true
+	}
+	from, to := orb.Point{source.Lng, source.Lat}, orb.Point{target.Lng, target.Lat}
+	for step := 0; step <= 1000; step++ {
+		point := greatCirclePoint(from, to, float64(step)/1000)
+		assert.True(t, covered[maptile.At(point, 14)], "line point %v outside the corridor", point)
+	}
+}
+
+func TestGetTilesForCorridor_UnionsTargets(t *testing.T) {
+	source := usecase.Coordinate{Lat: 25.0330, Lng: 121.5654}
+	north := usecase.Coordinate{Lat: 25.10, Lng: 121.5654}
+	east := usecase.Coordinate{Lat: 25.0330, Lng: 121.65}
+
+	northTiles, err := getTilesForCorridor(source, []usecase.Coordinate{north}, 14, 1, testMaxTileSpan)
+	require.NoError(t, err)
+	eastTiles, err := getTilesForCorridor(source, []usecase.Coordinate{east}, 14, 1, testMaxTileSpan)
+	require.NoError(t, err)
+	bothTiles, err := getTilesForCorridor(source, []usecase.Coordinate{north, east}, 14, 1, testMaxTileSpan)
+	require.NoError(t, err)
+
+	// The two corridors share only the tiles around the source
+	assert.Len(t, bothTiles, len(northTiles)+len(eastTiles)-9)
+	assert.Subset(t, bothTiles, northTiles)
+	assert.Subset(t, bothTiles, eastTiles)
+}
+
+func TestGetTilesForCorridor_RejectsInvalidAreas(t *testing.T) {
+	source := usecase.Coordinate{Lat: 25.00, Lng: 121.00}
+
+	_, err := getTilesForCorridor(source, []usecase.Coordinate{{Lat: math.NaN(), Lng: 121.01}}, 14, 1, testMaxTileSpan)
+	require.ErrorIs(t, err, errInvalidTileBounds)
+
+	// A line several hundred kilometers long passes more tiles than a 4x4 box holds
+	_, err = getTilesForCorridor(source, []usecase.Coordinate{{Lat: 25.00, Lng: 125.00}}, 14, 1, 4)
+	require.ErrorIs(t, err, errTileSpanExceeded)
+
+	// The same line rejected by the bounding box span fits a corridor of the default size
+	_, err = getTilesForBounds(24.99, 25.01, 121.00, 122.00, 14, testMaxTileSpan)
+	require.ErrorIs(t, err, errTileSpanExceeded)
+	tiles, err := getTilesForCorridor(source, []usecase.Coordinate{{Lat: 25.00, Lng: 122.00}}, 14, 1, testMaxTileSpan)
+	require.NoError(t, err)
+	assert.NotEmpty(t, tiles)
+}
+
+func TestPMTilesService_BuildGraphForArea_CorridorTiles(t *testing.T) {
+	source := usecase.Coordinate{Lat: 25.0330, Lng: 121.5654}
+	targets := []usecase.Coordinate{{Lat: 25.0600, Lng: 121.6000}}
+	svc := newCachedTestService(source, targets, 0)
+	svc.corridorTiles = true
+
+	// Only the corridor is cached; a tile outside it would be fetched from the missing PMTiles server
+	corridor, err := getTilesForCorridor(source, targets, maptile.Zoom(svc.zoomLevel), 0, testMaxTileSpan)
+	require.NoError(t, err)
+	roads := svc.tileCache[tileKey(maptile.At(orb.Point{source.Lng, source.Lat}, maptile.Zoom(svc.zoomLevel)))]
+	svc.tileCache = make(map[string]*RoadGraph, len(corridor))
+	for _, tile := range corridor {
+		svc.tileCache[tileKey(tile)] = NewRoadGraph()
+	}
+	svc.tileCache[tileKey(maptile.At(orb.Point{source.Lng, source.Lat}, maptile.Zoom(svc.zoomLevel)))] = roads
+
+	graph, err := svc.buildGraphForArea(context.Background(), source, targets)
+
+	require.NoError(t, err)
+	assert.Positive(t, graph.edgeCount())
+}
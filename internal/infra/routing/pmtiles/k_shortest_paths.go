@@ -0,0 +1,189 @@
+package pmtiles
+
+import (
+	"container/heap"
+	"math"
+	"slices"
+)
+
+// maxKShortestPaths caps the number of alternative routes computed per query,
+// since each additional route costs roughly one Dijkstra run per node of the previous route.
+const maxKShortestPaths = 5
+
+// edgeKey identifies a directed edge by its endpoints
+type edgeKey struct {
+	from NodeID
+	to   NodeID
+}
+
+// KShortestPaths finds up to k loopless paths from source to target ordered by distance,
+// using Yen's algorithm. k is capped at maxKShortestPaths. Every returned result is
+// reachable and carries its node sequence in Path.
+func (pf *Pathfinder) KShortestPaths(sourceID, targetID NodeID, k int) []PathResult {
+	k = min(k, maxKShortestPaths)
+	if k <= 0 {
+		return nil
+	}
+
+	first := pf.shortestPathExcluding(sourceID, targetID, nil, nil)
+	if !first.IsReachable {
+		return nil
+	}
+
+	accepted := []PathResult{first}
+	var candidates []PathResult
+
+	for len(accepted) < k {
+		previous := accepted[len(accepted)-1].Path
+
+		for i := 0; i < len(previous)-1; i++ {
+			spurNode := previous[i]
+			rootPath := previous[:i+1]
+
+			// Block the next edge of every accepted path sharing this root so the spur must deviate
+			blockedEdges := make(map[edgeKey]bool)
+			for _, path := range accepted {
+				if len(path.Path) > i+1 && slices.Equal(path.Path[:i+1], rootPath) {
+					blockedEdges[edgeKey{from: path.Path[i], to: path.Path[i+1]}] = true
+				}
+			}
+
+			// Block root nodes before the spur node to keep the path loopless
+			blockedNodes := make(map[NodeID]bool, i)
+			for _, id := range rootPath[:i] {
+				blockedNodes[id] = true
+			}
+
+			spur := pf.shortestPathExcluding(spurNode, targetID, blockedNodes, blockedEdges)
+			if !spur.IsReachable {
+				continue
+			}
+
+			candidatePath := append(slices.Clone(rootPath), spur.Path[1:]...)
+			if containsPath(accepted, candidatePath) || containsPath(candidates, candidatePath) {
+				continue
+			}
+
+			candidate, ok := pf.pathCost(candidatePath)
+			if ok {
+				candidates = append(candidates, candidate)
+			}
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+
+		slices.SortStableFunc(candidates, func(a, b PathResult) int {
+			switch {
+			case a.Distance < b.Distance:
+				return -1
+			case a.Distance > b.Distance:
+				return 1
+			default:
+				return 0
+			}
+		})
+		accepted = append(accepted, candidates[0])
+		candidates = candidates[1:]
+	}
+
+	return accepted
+}
+
+// shortestPathExcluding runs Dijkstra from source to target while skipping blocked nodes and edges,
+// and records the node sequence of the resulting path
+func (pf *Pathfinder) shortestPathExcluding(sourceID, targetID NodeID, blockedNodes map[NodeID]bool, blockedEdges map[edgeKey]bool) PathResult {
+	if !pf.graph.hasNode(sourceID) || !pf.graph.hasNode(targetID) {
+		return PathResult{IsReachable: false}
+	}
+
+	distances, durations, visited := pf.initDijkstraState()
+	distances[sourceID] = 0
+	durations[sourceID] = 0
+	previous := make(map[NodeID]NodeID)
+
+	priorityQueue := make(priorityQueue, 0)
+	heap.Init(&priorityQueue)
+	heap.Push(&priorityQueue, &dijkstraNode{id: sourceID})
+
+	for priorityQueue.Len() > 0 {
+		current := heap.Pop(&priorityQueue).(*dijkstraNode)
+
+		if visited[current.id] {
+			continue
+		}
+		visited[current.id] = true
+
+		if current.id == targetID {
+			return PathResult{
+				Distance:    current.distance,
+				Duration:    current.duration,
+				IsReachable: true,
+				Path:        buildPath(previous, sourceID, targetID),
+			}
+		}
+
+		for _, edge := range pf.graph.Edges[current.id] {
+			if visited[edge.To] || blockedNodes[edge.To] || blockedEdges[edgeKey{from: current.id, to: edge.To}] {
+				continue
+			}
+
+			newDist := current.distance + edge.Distance
+			if newDist < distances[edge.To] {
+				distances[edge.To] = newDist
+				durations[edge.To] = current.duration + edge.Duration
+				previous[edge.To] = current.id
+				heap.Push(&priorityQueue, &dijkstraNode{
+					id:       edge.To,
+					distance: newDist,
+					duration: current.duration + edge.Duration,
+				})
+			}
+		}
+	}
+
+	return PathResult{IsReachable: false}
+}
+
+// pathCost sums distance and duration along a node sequence, using the shortest edge
+// between each consecutive pair. It returns false if any consecutive pair is not connected.
+func (pf *Pathfinder) pathCost(path []NodeID) (PathResult, bool) {
+	result := PathResult{IsReachable: true, Path: path}
+
+	for i := 0; i < len(path)-1; i++ {
+		best := Edge{Distance: math.MaxFloat64}
+		for _, edge := range pf.graph.Edges[path[i]] {
+			if edge.To == path[i+1] && edge.Distance < best.Distance {
+				best = edge
+			}
+		}
+		if best.Distance == math.MaxFloat64 {
+			return PathResult{}, false
+		}
+
+		result.Distance += best.Distance
+		result.Duration += best.Duration
+	}
+
+	return result, true
+}
+
+// buildPath reconstructs the node sequence from source to target using predecessor links
+func buildPath(previous map[NodeID]NodeID, sourceID, targetID NodeID) []NodeID {
+	path := []NodeID{targetID}
+	for current := targetID; current != sourceID; {
+		current = previous[current]
+		path = append(path, current)
+	}
+	slices.Reverse(path)
+
+	return path
+}
+
+// containsPath reports whether results already include the given node sequence
+func containsPath(results []PathResult, path []NodeID) bool {
+	return slices.ContainsFunc(results, func(result PathResult) bool {
+		return slices.Equal(result.Path, path)
+	})
+}
@@ -0,0 +1,110 @@
+package pmtiles
+
+import (
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTwoRouteGraph builds a graph with a short route A -> B -> D and a longer detour A -> C -> D
+func newTwoRouteGraph() *RoadGraph {
+	graph := NewRoadGraph()
+
+	graph.AddSegment(&RoadSegment{
+		Points: []orb.Point{
+			{121.50, 25.00}, // A
+			{121.51, 25.00}, // B
+			{121.52, 25.00}, // D
+		},
+		Highway:  "primary",
+		MaxSpeed: 50.0,
+	})
+	graph.AddSegment(&RoadSegment{
+		Points: []orb.Point{
+			{121.50, 25.00}, // A
+			{121.51, 25.02}, // C
+			{121.52, 25.00}, // D
+		},
+		Highway:  "primary",
+		MaxSpeed: 50.0,
+	})
+
+	return graph
+}
+
+func TestPathfinder_KShortestPaths_TwoRoutes(t *testing.T) {
+	graph := newTwoRouteGraph()
+	pf := NewPathfinder(graph)
+
+	nodeA := graph.pointMap[pointKey(orb.Point{121.50, 25.00})]
+	nodeB := graph.pointMap[pointKey(orb.Point{121.51, 25.00})]
+	nodeC := graph.pointMap[pointKey(orb.Point{121.51, 25.02})]
+	nodeD := graph.pointMap[pointKey(orb.Point{121.52, 25.00})]
+
+	results := pf.KShortestPaths(nodeA, nodeD, 3)
+
+	require.Len(t, results, 2)
+	assert.Equal(t, []NodeID{nodeA, nodeB, nodeD}, results[0].Path)
+	assert.Equal(t, []NodeID{nodeA, nodeC, nodeD}, results[1].Path)
+	assert.True(t, results[0].IsReachable)
+	assert.True(t, results[1].IsReachable)
+	assert.Less(t, results[0].Distance, results[1].Distance)
+	assert.Greater(t, results[1].Duration, 0.0)
+
+	// The first route matches plain Dijkstra
+	shortest := pf.ShortestPath(nodeA, nodeD)
+	assert.InDelta(t, shortest.Distance, results[0].Distance, 1e-9)
+	assert.InDelta(t, shortest.Duration, results[0].Duration, 1e-9)
+}
+
+func TestPathfinder_KShortestPaths_CapsK(t *testing.T) {
+	graph := NewRoadGraph()
+
+	// A ladder of parallel detours yields more loopless routes than the cap
+	for i := range maxKShortestPaths + 3 {
+		graph.AddSegment(&RoadSegment{
+			Points: []orb.Point{
+				{121.50, 25.00},
+				{121.51, 25.00 + float64(i+1)*0.01},
+				{121.52, 25.00},
+			},
+			Highway:  "primary",
+			MaxSpeed: 50.0,
+		})
+	}
+
+	pf := NewPathfinder(graph)
+	nodeA := graph.pointMap[pointKey(orb.Point{121.50, 25.00})]
+	nodeB := graph.pointMap[pointKey(orb.Point{121.52, 25.00})]
+
+	results := pf.KShortestPaths(nodeA, nodeB, 100)
+
+	require.Len(t, results, maxKShortestPaths)
+	for i := 1; i < len(results); i++ {
+		assert.LessOrEqual(t, results[i-1].Distance, results[i].Distance)
+		assert.NotEqual(t, results[i-1].Path, results[i].Path)
+	}
+}
+
+func TestPathfinder_KShortestPaths_Unreachable(t *testing.T) {
+	graph := NewRoadGraph()
+	graph.AddSegment(&RoadSegment{
+		Points:   []orb.Point{{121.50, 25.00}, {121.51, 25.00}},
+		Highway:  "primary",
+		MaxSpeed: 50.0,
+	})
+	graph.AddSegment(&RoadSegment{
+		Points:   []orb.Point{{121.60, 25.00}, {121.61, 25.00}},
+		Highway:  "primary",
+		MaxSpeed: 50.0,
+	})
+
+	pf := NewPathfinder(graph)
+	nodeA := graph.pointMap[pointKey(orb.Point{121.50, 25.00})]
+	nodeD := graph.pointMap[pointKey(orb.Point{121.60, 25.00})]
+
+	assert.Empty(t, pf.KShortestPaths(nodeA, nodeD, 3))
+	assert.Empty(t, pf.KShortestPaths(nodeA, nodeA+1, 0))
+}
@@ -3,6 +3,9 @@ package pmtiles
 import (
 	"fmt"
 	"math"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/paulmach/orb"
 	"github.com/paulmach/orb/encoding/mvt"
@@ -18,26 +21,71 @@ const (
 	roadTypeRoad        = "road"
 )
 
+// OSM maxspeed unit conversions to km/h
+const (
+	kmhPerMph    = 1.609344
+	kmhPerKnot   = 1.852
+	walkSpeedKmh = 5.0
+)
+
+// restrictedAccessValues are the OSM access values of roads the public may not use
+var restrictedAccessValues = []string{"private", "no"}
+
 // RoadSegment represents a road segment extracted from MVT data
 type RoadSegment struct {
-	Points    []orb.Point
-	Highway   string  // road type (e.g., "primary", "secondary", "residential")
-	MaxSpeed  float64 // max speed in km/h (0 if unknown)
+	Points    []orb.Point // in travel order for a one-way road
+	Highway   string      // road type (e.g., "primary", "secondary", "residential")
+	MaxSpeed  float64     // max speed in km/h (0 if unknown)
 	OneWay    bool
+	Access    string // access tag, lowercased (e.g., "private", "no"); empty when untagged
 	Name      string
 	FeatureID uint64
 }
 
+// IsRestricted reports whether the segment's access tag closes it to the public
+func (s *RoadSegment) IsRestricted() bool {
+	return slices.Contains(restrictedAccessValues, s.Access)
+}
+
+// DistanceTo returns the distance in meters from point to the closest point on the segment
+func (s *RoadSegment) DistanceTo(point orb.Point) float64 {
+	nearest := math.MaxFloat64
+	for idx := 1; idx < len(s.Points); idx++ {
+		projected, _ := projectOntoSegment(point, s.Points[idx-1], s.Points[idx])
+		nearest = min(nearest, haversineDistance(point, projected))
+	}
+
+	return nearest
+}
+
 // MVTParser handles parsing of MVT tiles to extract road network data
 type MVTParser struct {
 	roadLayerName string
+
+	// When set, roads tagged access=private or access=no are left out of the parsed segments
+	excludeRestrictedAccess bool
+}
+
+// MVTParserOption configures an MVTParser
+type MVTParserOption func(*MVTParser)
+
+// WithExcludeRestrictedAccess sets whether roads tagged access=private or access=no are left out of the parsed segments
+func WithExcludeRestrictedAccess(exclude bool) MVTParserOption {
+	return func(p *MVTParser) {
+		p.excludeRestrictedAccess = exclude
+	}
 }
 
 // NewMVTParser creates a new MVT parser
-func NewMVTParser(roadLayerName string) *MVTParser {
-	return &MVTParser{
+func NewMVTParser(roadLayerName string, opts ...MVTParserOption) *MVTParser {
+	parser := &MVTParser{
 		roadLayerName: roadLayerName,
 	}
+	for _, opt := range opts {
+		opt(parser)
+	}
+
+	return parser
 }
 
 // ParseTile parses MVT tile data and extracts road segments
@@ -75,9 +123,10 @@ func (p *MVTParser) ParseTile(data []byte, tile maptile.Tile) ([]RoadSegment, er
 
 	for _, feature := range roadLayer.Features {
 		segment, ok := p.extractRoadSegment(feature)
-		if ok {
-			segments = append(segments, segment)
+		if !ok || (p.excludeRestrictedAccess && segment.IsRestricted()) {
+			continue
 		}
+		segments = append(segments, segment)
 	}
 
 	return segments, nil
@@ -96,8 +145,15 @@ func (p *MVTParser) extractRoadSegment(feature *geojson.Feature) (RoadSegment, b
 	segment.FeatureID = p.parseFeatureID(feature.ID)
 	segment.Highway = p.getStringProperty(feature, "class", "highway", "type")
 	segment.Name = p.getStringProperty(feature, "name", "")
-	segment.OneWay = p.getBoolProperty(feature, "oneway")
-	segment.MaxSpeed = p.getSpeedForRoadType(segment.Highway)
+	segment.Access = strings.ToLower(strings.TrimSpace(p.getStringProperty(feature, "access")))
+	segment.MaxSpeed = p.getMaxSpeed(feature, segment.Highway)
+
+	// A reverse one-way road is traveled against its drawing direction
+	var reversed bool
+	segment.OneWay, reversed = parseOneWay(feature.Properties["oneway"])
+	if reversed {
+		slices.Reverse(segment.Points)
+	}
 
 	return segment, true
 }
@@ -185,24 +241,103 @@ func (p *MVTParser) getStringProperty(feature *geojson.Feature, keys ...string)
 	return ""
 }
 
-// getBoolProperty gets a boolean property from feature properties
-func (p *MVTParser) getBoolProperty(feature *geojson.Feature, key string) bool {
-	if val, ok := feature.Properties[key]; ok {
-		switch value := val.(type) {
-		case bool:
-			return value
-		case int:
-			return value != 0
-		case int64:
-			return value != 0
-		case float64:
-			return value != 0
-		case string:
-			return value == boolStringYes || value == "true" || value == "1"
+// parseOneWay reads an OSM oneway value. reversed is set for "-1" and "reverse", which restrict travel to
+// the opposite of the way's drawing direction; "yes", "true", and "1" restrict it to the drawing direction.
+func parseOneWay(val any) (oneWay, reversed bool) {
+	switch value := val.(type) {
+	case bool:
+		return value, false
+	case int:
+		return value != 0, value < 0
+	case int64:
+		return value != 0, value < 0
+	case float64:
+		return value != 0, value < 0
+	case string:
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case boolStringYes, "true", "1":
+			return true, false
+		case "-1", "reverse":
+			return true, true
 		}
 	}
 
-	return false
+	return false, false
+}
+
+// getMaxSpeed returns the feature's maxspeed in km/h, falling back to the road type default when it cannot be read
+func (p *MVTParser) getMaxSpeed(feature *geojson.Feature, highway string) float64 {
+	if speed, ok := parseMaxSpeed(feature.Properties["maxspeed"]); ok {
+		return speed
+	}
+
+	return p.getSpeedForRoadType(highway)
+}
+
+// parseMaxSpeed normalizes an OSM maxspeed value to km/h.
+// Bare numbers are km/h, "mph" and "knots" are converted, and "walk" is walking pace.
+// Conditional ("50 @ (22:00-06:00)") and multi-valued ("50;30") tags keep only the first speed.
+// Symbolic values such as "none" or "RU:urban" are reported as unparseable.
+func parseMaxSpeed(val any) (float64, bool) {
+	switch value := val.(type) {
+	case float64:
+		return validSpeed(value)
+	case int64:
+		return validSpeed(float64(value))
+	case uint64:
+		return validSpeed(float64(value))
+	case int:
+		return validSpeed(float64(value))
+	case string:
+		return parseMaxSpeedString(value)
+	default:
+		return 0, false
+	}
+}
+
+func parseMaxSpeedString(value string) (float64, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if idx := strings.IndexAny(value, "@;"); idx >= 0 {
+		value = strings.TrimSpace(value[:idx])
+	}
+	if value == "walk" {
+		return walkSpeedKmh, true
+	}
+
+	factor := 1.0
+	for _, unit := range []struct {
+		suffix string
+		factor float64
+	}{
+		{"mph", kmhPerMph},
+		{"knots", kmhPerKnot},
+		{"km/h", 1.0},
+		{"kmh", 1.0},
+		{"kph", 1.0},
+	} {
+		if trimmed, ok := strings.CutSuffix(value, unit.suffix); ok {
+			value = strings.TrimSpace(trimmed)
+			factor = unit.factor
+
+			break
+		}
+	}
+
+	speed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return validSpeed(speed * factor)
+}
+
+// validSpeed rejects non-positive and non-finite speeds
+func validSpeed(speed float64) (float64, bool) {
+	if speed <= 0 || math.IsInf(speed, 0) || math.IsNaN(speed) {
+		return 0, false
+	}
+
+	return speed, true
 }
 
 // getSpeedForRoadType returns default speed in km/h based on road type
@@ -1,6 +1,7 @@
 package pmtiles
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/paulmach/orb"
@@ -93,112 +94,6 @@ func TestMVTParser_getStringProperty(t *testing.T) {
 	}
 }
 
-func TestMVTParser_getBoolProperty(t *testing.T) {
-	parser := NewMVTParser("transportation")
-
-	tests := []struct {
-		name       string
-		properties map[string]any
-		key        string
-		expected   bool
-	}{
-		{
-			name:       "bool true",
-			properties: map[string]any{"oneway": true},
-			key:        "oneway",
-			expected:   true,
-		},
-		{
-			name:       "bool false",
-			properties: map[string]any{"oneway": false},
-			key:        "oneway",
-			expected:   false,
-		},
-		{
-			name:       "int 1",
-			properties: map[string]any{"oneway": 1},
-			key:        "oneway",
-			expected:   true,
-		},
-		{
-			name:       "int 0",
-			properties: map[string]any{"oneway": 0},
-			key:        "oneway",
-			expected:   false,
-		},
-		{
-			name:       "int64 1",
-			properties: map[string]any{"oneway": int64(1)},
-			key:        "oneway",
-			expected:   true,
-		},
-		{
-			name:       "float64 1",
-			properties: map[string]any{"oneway": float64(1.0)},
-			key:        "oneway",
-			expected:   true,
-		},
-		{
-			name:       "float64 0",
-			properties: map[string]any{"oneway": float64(0.0)},
-			key:        "oneway",
-			expected:   false,
-		},
-		{
-			name:       "string yes",
-			properties: map[string]any{"oneway": "yes"},
-			key:        "oneway",
-			expected:   true,
-		},
-		{
-			name:       "string true",
-			properties: map[string]any{"oneway": "true"},
-			key:        "oneway",
-			expected:   true,
-		},
-		{
-			name:       "string 1",
-			properties: map[string]any{"oneway": "1"},
-			key:        "oneway",
-			expected:   true,
-		},
-		{
-			name:       "string no",
-			properties: map[string]any{"oneway": "no"},
-			key:        "oneway",
-			expected:   false,
-		},
-		{
-			name:       "string false",
-			properties: map[string]any{"oneway": "false"},
-			key:        "oneway",
-			expected:   false,
-		},
-		{
-			name:       "key not exists",
-			properties: map[string]any{},
-			key:        "oneway",
-			expected:   false,
-		},
-		{
-			name:       "unsupported type",
-			properties: map[string]any{"oneway": []string{"yes"}},
-			key:        "oneway",
-			expected:   false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			feature := &geojson.Feature{
-				Properties: tt.properties,
-			}
-			result := parser.getBoolProperty(feature, tt.key)
-			assert.Equal(t, tt.expected, result)
-		})
-	}
-}
-
 func TestMVTParser_getSpeedForRoadType(t *testing.T) {
 	parser := NewMVTParser("transportation")
 
@@ -417,6 +312,135 @@ func TestMVTParser_extractRoadSegment_InvalidGeometry(t *testing.T) {
 	assert.False(t, ok)
 }
 
+func TestParseMaxSpeed(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected float64
+		ok       bool
+	}{
+		{name: "numeric km/h", value: float64(50), expected: 50, ok: true},
+		{name: "integer km/h", value: int64(80), expected: 80, ok: true},
+		{name: "bare string", value: "60", expected: 60, ok: true},
+		{name: "explicit km/h", value: "70 km/h", expected: 70, ok: true},
+		{name: "mph", value: "30 mph", expected: 30 * kmhPerMph, ok: true},
+		{name: "mph without space", value: "25mph", expected: 25 * kmhPerMph, ok: true},
+		{name: "knots", value: "10 knots", expected: 10 * kmhPerKnot, ok: true},
+		{name: "walk", value: "walk", expected: walkSpeedKmh, ok: true},
+		{name: "conditional", value: "30 @ (Mo-Fr 07:00-19:00)", expected: 30, ok: true},
+		{name: "conditional mph", value: "20 mph @ (school)", expected: 20 * kmhPerMph, ok: true},
+		{name: "multiple values", value: "50;30", expected: 50, ok: true},
+		{name: "none", value: "none", ok: false},
+		{name: "zone", value: "RU:urban", ok: false},
+		{name: "zero", value: "0", ok: false},
+		{name: "negative", value: float64(-10), ok: false},
+		{name: "empty", value: "", ok: false},
+		{name: "missing", value: nil, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			speed, ok := parseMaxSpeed(tt.value)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.InDelta(t, tt.expected, speed, 1e-9)
+		})
+	}
+}
+
+func TestMVTParser_ParseTile_MaxSpeedUnits(t *testing.T) {
+	parser := NewMVTParser("transportation")
+
+	maxSpeeds := []any{"50", "30 mph", "walk", "40 @ (22:00-06:00)", "none", float64(90)}
+	features := make([]*geojson.Feature, 0, len(maxSpeeds))
+	for idx, maxSpeed := range maxSpeeds {
+		offset := float64(idx) * 0.01
+		features = append(features, &geojson.Feature{
+			ID:         float64(idx + 1),
+			Geometry:   orb.LineString{{121.50 + offset, 25.00}, {121.505 + offset, 25.005}},
+			Properties: map[string]any{"class": "primary", "maxspeed": maxSpeed},
+		})
+	}
+
+	data, err := mvt.Marshal(mvt.Layers{&mvt.Layer{Name: "transportation", Features: features}})
+	require.NoError(t, err)
+
+	segments, err := parser.ParseTile(data, TileForTest())
+
+	require.NoError(t, err)
+	require.Len(t, segments, len(maxSpeeds))
+	assert.InDelta(t, 50.0, segments[0].MaxSpeed, 1e-9)
+	assert.InDelta(t, 30*kmhPerMph, segments[1].MaxSpeed, 1e-9)
+	assert.InDelta(t, walkSpeedKmh, segments[2].MaxSpeed, 1e-9)
+	assert.InDelta(t, 40.0, segments[3].MaxSpeed, 1e-9)
+	assert.InDelta(t, 60.0, segments[4].MaxSpeed, 1e-9) // Unparseable falls back to the primary default
+	assert.InDelta(t, 90.0, segments[5].MaxSpeed, 1e-9)
+}
+
+func TestMVTParser_ParseTile_ReverseOneWay(t *testing.T) {
+	parser := NewMVTParser("transportation")
+
+	oneWays := []any{"yes", "-1", "reverse", float64(-1), "no"}
+	features := make([]*geojson.Feature, 0, len(oneWays))
+	for idx, oneWay := range oneWays {
+		features = append(features, &geojson.Feature{
+			ID:         float64(idx + 1),
+			Geometry:   orb.LineString{{121.50, 25.00}, {121.502, 25.002}, {121.505, 25.005}},
+			Properties: map[string]any{"class": "primary", "oneway": oneWay},
+		})
+	}
+
+	data, err := mvt.Marshal(mvt.Layers{&mvt.Layer{Name: "transportation", Features: features}})
+	require.NoError(t, err)
+
+	segments, err := parser.ParseTile(data, TileForTest())
+
+	require.NoError(t, err)
+	require.Len(t, segments, len(oneWays))
+	forward := segments[0].Points
+	reversed := slices.Clone(forward)
+	slices.Reverse(reversed)
+
+	assert.True(t, segments[0].OneWay)
+	for _, segment := range segments[1:4] {
+		assert.True(t, segment.OneWay)
+		assert.Equal(t, reversed, segment.Points, "reverse one-way points follow the direction of travel")
+	}
+	assert.False(t, segments[4].OneWay)
+	assert.Equal(t, forward, segments[4].Points)
+}
+
+func TestMVTParser_ParseTile_RestrictedAccess(t *testing.T) {
+	accesses := []string{"", "private", "yes", "No"}
+	features := make([]*geojson.Feature, 0, len(accesses))
+	for idx, access := range accesses {
+		properties := map[string]any{"class": "residential"}
+		if access != "" {
+			properties["access"] = access
+		}
+		offset := float64(idx) * 0.01
+		features = append(features, &geojson.Feature{
+			ID:         float64(idx + 1),
+			Geometry:   orb.LineString{{121.50 + offset, 25.00}, {121.505 + offset, 25.005}},
+			Properties: properties,
+		})
+	}
+	data, err := mvt.Marshal(mvt.Layers{&mvt.Layer{Name: "transportation", Features: features}})
+	require.NoError(t, err)
+
+	parser := NewMVTParser("transportation")
+	segments, err := parser.ParseTile(data, TileForTest())
+	require.NoError(t, err)
+	require.Len(t, segments, len(accesses))
+	assert.Equal(t, []string{"", "private", "yes", "no"}, []string{segments[0].Access, segments[1].Access, segments[2].Access, segments[3].Access})
+
+	segments, err = NewMVTParser("transportation", WithExcludeRestrictedAccess(true)).ParseTile(data, TileForTest())
+	require.NoError(t, err)
+	require.Len(t, segments, 2)
+	assert.Equal(t, uint64(1), segments[0].FeatureID)
+	assert.Equal(t, uint64(3), segments[1].FeatureID)
+}
+
 func TestMVTParser_ParseTile_InvalidData(t *testing.T) {
 	parser := NewMVTParser("transportation")
 
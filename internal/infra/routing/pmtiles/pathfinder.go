@@ -16,14 +16,21 @@ type Edge struct {
 	To       NodeID
 	Distance float64 // Distance in meters
 	Duration float64 // Duration in seconds
+	Class    RoadClass
 }
 
 // RoadGraph represents the road network graph built from MVT data
 type RoadGraph struct {
-	Nodes    map[NodeID]orb.Point
+	Nodes    map[NodeID]orb.Point // Full-precision node coordinates; nil for a compact graph
 	Edges    map[NodeID][]Edge
 	nodeIdx  int64
 	pointMap map[string]NodeID // Maps "lat,lng" to NodeID for deduplication
+
+	// Node coordinates stored as float32 instead of Nodes; only set for a compact graph
+	compactNodes map[NodeID]compactPoint
+
+	// Edges shorter than this many meters are clamped up so no edge has a near-zero cost; 0 keeps measured lengths
+	minEdgeDistance float64
 }
 
 // NewRoadGraph creates a new empty road graph
@@ -36,6 +43,85 @@ func NewRoadGraph() *RoadGraph {
 	}
 }
 
+// compactPoint is a node coordinate held as float32 longitude and latitude.
+// float32 keeps sub-meter resolution at geographic magnitudes, finer than the ~1m pointKey rounding.
+type compactPoint [2]float32
+
+// NewCompactRoadGraph creates an empty road graph that stores node coordinates as float32,
+// halving coordinate memory on large graphs at the cost of sub-meter precision
+func NewCompactRoadGraph() *RoadGraph {
+	graph := NewRoadGraph()
+	graph.Nodes = nil
+	graph.compactNodes = make(map[NodeID]compactPoint)
+
+	return graph
+}
+
+// node returns the coordinate of a node regardless of its storage precision
+func (g *RoadGraph) node(id NodeID) (orb.Point, bool) {
+	if g.compactNodes != nil {
+		point, ok := g.compactNodes[id]
+
+		return orb.Point{float64(point[0]), float64(point[1])}, ok
+	}
+
+	point, ok := g.Nodes[id]
+
+	return point, ok
+}
+
+// hasNode reports whether the graph contains the node
+func (g *RoadGraph) hasNode(id NodeID) bool {
+	_, ok := g.node(id)
+
+	return ok
+}
+
+// nodeCount returns the number of nodes in the graph
+func (g *RoadGraph) nodeCount() int {
+	if g.compactNodes != nil {
+		return len(g.compactNodes)
+	}
+
+	return len(g.Nodes)
+}
+
+// edgeCount returns the number of directed edges in the graph
+func (g *RoadGraph) edgeCount() int {
+	count := 0
+	for _, edges := range g.Edges {
+		count += len(edges)
+	}
+
+	return count
+}
+
+// eachNode calls fn for every node in the graph
+func (g *RoadGraph) eachNode(fn func(id NodeID, point orb.Point)) {
+	if g.compactNodes != nil {
+		for id, point := range g.compactNodes {
+			fn(id, orb.Point{float64(point[0]), float64(point[1])})
+		}
+
+		return
+	}
+
+	for id, point := range g.Nodes {
+		fn(id, point)
+	}
+}
+
+// setNode stores a node coordinate at the graph's precision
+func (g *RoadGraph) setNode(id NodeID, point orb.Point) {
+	if g.compactNodes != nil {
+		g.compactNodes[id] = compactPoint{float32(point[0]), float32(point[1])}
+
+		return
+	}
+
+	g.Nodes[id] = point
+}
+
 // AddSegment adds a road segment to the graph
 func (g *RoadGraph) AddSegment(segment *RoadSegment) {
 	if len(segment.Points) < 2 {
@@ -48,19 +134,26 @@ func (g *RoadGraph) AddSegment(segment *RoadSegment) {
 	for i := 1; i < len(segment.Points); i++ {
 		currNodeID := g.getOrCreateNode(segment.Points[i])
 
-		// Calculate distance and duration
-		dist := haversineDistance(segment.Points[i-1], segment.Points[i])
+		// Skip degenerate self-loops from consecutive points that deduplicate to the same node
+		if currNodeID == prevNodeID {
+			continue
+		}
+
+		// Calculate distance and duration; duration follows the clamped distance
+		dist := max(haversineDistance(segment.Points[i-1], segment.Points[i]), g.minEdgeDistance)
 		speed := segment.MaxSpeed
 		if speed <= 0 {
 			speed = 30.0 // Default 30 km/h
 		}
 		duration := (dist / 1000.0 / speed) * 3600.0 // Convert to seconds
+		class := roadClassFor(segment.Highway)
 
 		// Add forward edge
 		g.Edges[prevNodeID] = append(g.Edges[prevNodeID], Edge{
 			To:       currNodeID,
 			Distance: dist,
 			Duration: duration,
+			Class:    class,
 		})
 
 		// Add reverse edge if not one-way
@@ -69,6 +162,7 @@ func (g *RoadGraph) AddSegment(segment *RoadSegment) {
 				To:       prevNodeID,
 				Distance: dist,
 				Duration: duration,
+				Class:    class,
 			})
 		}
 
@@ -87,7 +181,7 @@ func (g *RoadGraph) getOrCreateNode(point orb.Point) NodeID {
 
 	g.nodeIdx++
 	id := NodeID(g.nodeIdx)
-	g.Nodes[id] = point
+	g.setNode(id, point)
 	g.pointMap[key] = id
 
 	return id
@@ -110,34 +204,100 @@ func formatFloat(f float64) string {
 
 // FindNearestNode finds the nearest node to a given point
 func (g *RoadGraph) FindNearestNode(point orb.Point) (NodeID, float64, bool) {
-	if len(g.Nodes) == 0 {
+	if g.nodeCount() == 0 {
 		return 0, 0, false
 	}
 
 	var nearestID NodeID
 	nearestDist := math.MaxFloat64
 
-	for id, nodePoint := range g.Nodes {
+	g.eachNode(func(id NodeID, nodePoint orb.Point) {
 		dist := haversineDistance(point, nodePoint)
 		if dist < nearestDist {
 			nearestDist = dist
 			nearestID = id
 		}
-	}
+	})
 
 	return nearestID, nearestDist, true
 }
 
+// EdgeProjection describes the closest point on a road edge to a query point
+type EdgeProjection struct {
+	From     NodeID
+	To       NodeID
+	Point    orb.Point // Projected point on the edge
+	Offset   float64   // Fraction of the edge length from From to Point, in [0, 1]
+	Distance float64   // Distance in meters from the query point to Point
+}
+
+// FindNearestEdge finds the edge whose closest point is nearest to the given point
+func (g *RoadGraph) FindNearestEdge(point orb.Point) (EdgeProjection, bool) {
+	var nearest EdgeProjection
+	found := false
+
+	for fromID, edges := range g.Edges {
+		from, ok := g.node(fromID)
+		if !ok {
+			continue
+		}
+
+		for _, edge := range edges {
+			to, ok := g.node(edge.To)
+			if !ok {
+				continue
+			}
+
+			projected, offset := projectOntoSegment(point, from, to)
+			dist := haversineDistance(point, projected)
+			if !found || dist < nearest.Distance {
+				nearest = EdgeProjection{From: fromID, To: edge.To, Point: projected, Offset: offset, Distance: dist}
+				found = true
+			}
+		}
+	}
+
+	return nearest, found
+}
+
+// projectOntoSegment projects a point onto the segment from-to using a local equirectangular
+// approximation, which is accurate at road-edge scale. It returns the projected point and its
+// fractional offset from the start of the segment.
+func projectOntoSegment(point, from, to orb.Point) (orb.Point, float64) {
+	lngScale := math.Cos(from[1] * math.Pi / 180)
+
+	dx := (to[0] - from[0]) * lngScale
+	dy := to[1] - from[1]
+	lengthSquared := dx*dx + dy*dy
+	if lengthSquared == 0 {
+		return from, 0
+	}
+
+	px := (point[0] - from[0]) * lngScale
+	py := point[1] - from[1]
+	offset := math.Max(0, math.Min(1, (px*dx+py*dy)/lengthSquared))
+
+	return orb.Point{
+		from[0] + offset*(to[0]-from[0]),
+		from[1] + offset*(to[1]-from[1]),
+	}, offset
+}
+
 // PathResult represents the result of a path search
 type PathResult struct {
 	Distance    float64 // Total distance in meters
 	Duration    float64 // Total duration in seconds
 	IsReachable bool
+	Path        []NodeID // Node sequence from source to target; only populated by ShortestPath and KShortestPaths
+
+	// Coordinates of Path in order; only populated by ShortestPath so bulk queries skip the lookup
+	Geometry []orb.Point
 }
 
 // Pathfinder implements shortest path algorithms on the road graph
 type Pathfinder struct {
 	graph *RoadGraph
+	cost  CostWeights
 }
 
 // NewPathfinder creates a new pathfinder for the given graph
@@ -145,12 +305,19 @@ func NewPathfinder(graph *RoadGraph) *Pathfinder {
 	return &Pathfinder{graph: graph}
 }
 
+// NewPathfinderWithCost creates a pathfinder that minimizes the composite cost when the weights are enabled,
+// and shortest distance otherwise
+func NewPathfinderWithCost(graph *RoadGraph, cost CostWeights) *Pathfinder {
+	return &Pathfinder{graph: graph, cost: cost}
+}
+
 // dijkstraNode represents a node in the priority queue
 type dijkstraNode struct {
 	id       NodeID
 	distance float64
 	duration float64
-	index    int // Index in the heap
+	estimate float64 // A* lower bound on the remaining distance; zero for Dijkstra
+	index    int     // Index in the heap
 }
 
 // priorityQueue implements heap.Interface for Dijkstra's algorithm
@@ -159,7 +326,7 @@ type priorityQueue []*dijkstraNode
 func (pq priorityQueue) Len() int { return len(pq) }
 
 func (pq priorityQueue) Less(i, j int) bool {
-	return pq[i].distance < pq[j].distance
+	return pq[i].distance+pq[i].estimate < pq[j].distance+pq[j].estimate
 }
 
 func (pq priorityQueue) Swap(i, j int) {
@@ -186,19 +353,21 @@ func (pq *priorityQueue) Pop() any {
 	return node
 }
 
-// ShortestPath finds the shortest path from source to target using Dijkstra's algorithm
+// ShortestPath finds the shortest path from source to target using Dijkstra's algorithm.
+// Unlike ShortestPathToMany it tracks predecessors, so a reachable result carries its Path and Geometry.
 func (pf *Pathfinder) ShortestPath(sourceID, targetID NodeID) PathResult {
-	if _, exists := pf.graph.Nodes[sourceID]; !exists {
+	if !pf.graph.hasNode(sourceID) || !pf.graph.hasNode(targetID) {
 		return PathResult{IsReachable: false}
 	}
-	if _, exists := pf.graph.Nodes[targetID]; !exists {
-		return PathResult{IsReachable: false}
+	if pf.cost.Enabled() {
+		return pf.compositeShortestPath(sourceID, targetID)
 	}
 
 	// Initialize distances
 	distances, durations, visited := pf.initDijkstraState()
 	distances[sourceID] = 0
 	durations[sourceID] = 0
+	previous := make(map[NodeID]NodeID)
 
 	// Initialize priority queue
 	priorityQueue := make(priorityQueue, 0)
@@ -219,21 +388,114 @@ func (pf *Pathfinder) ShortestPath(sourceID, targetID NodeID) PathResult {
 
 		// Found target
 		if current.id == targetID {
-			return PathResult{
+			return pf.withGeometry(PathResult{
 				Distance:    current.distance,
 				Duration:    current.duration,
 				IsReachable: true,
-			}
+				Path:        buildPath(previous, sourceID, targetID),
+			})
 		}
 
 		// Process neighbors
-		pf.relaxEdges(current, distances, durations, visited, &priorityQueue)
+		pf.relaxEdges(current, distances, durations, visited, previous, &priorityQueue)
 	}
 
 	// Target not reachable
 	return PathResult{IsReachable: false}
 }
 
+// withGeometry fills a result's Geometry from the coordinates of its Path
+func (pf *Pathfinder) withGeometry(result PathResult) PathResult {
+	result.Geometry = make([]orb.Point, 0, len(result.Path))
+	for _, id := range result.Path {
+		if point, ok := pf.graph.node(id); ok {
+			result.Geometry = append(result.Geometry, point)
+		}
+	}
+
+	return result
+}
+
+// Float32 node coordinates can place two nodes up to this much farther apart than the
+// full-precision points their edge distance was computed from
+const compactHeuristicSlackMeters = 1.0
+
+// AStarPath finds the shortest path from source to target using A* search.
+// Edges are weighted by distance, so the straight-line distance to the target never
+// overestimates the remaining cost and the result matches ShortestPath while settling fewer nodes.
+// With composite cost weights there is no such bound, so it falls back to ShortestPath.
+func (pf *Pathfinder) AStarPath(sourceID, targetID NodeID) PathResult {
+	if pf.cost.Enabled() {
+		return pf.ShortestPath(sourceID, targetID)
+	}
+
+	sourcePoint, sourceExists := pf.graph.node(sourceID)
+	targetPoint, targetExists := pf.graph.node(targetID)
+	if !sourceExists || !targetExists {
+		return PathResult{IsReachable: false}
+	}
+
+	slack := 0.0
+	if pf.graph.compactNodes != nil {
+		slack = compactHeuristicSlackMeters
+	}
+	heuristic := func(point orb.Point) float64 {
+		return max(haversineDistance(point, targetPoint)-slack, 0)
+	}
+
+	distances := map[NodeID]float64{sourceID: 0}
+	visited := make(map[NodeID]bool)
+
+	priorityQueue := make(priorityQueue, 0)
+	heap.Init(&priorityQueue)
+	heap.Push(&priorityQueue, &dijkstraNode{
+		id:       sourceID,
+		estimate: heuristic(sourcePoint),
+	})
+
+	for priorityQueue.Len() > 0 {
+		current := heap.Pop(&priorityQueue).(*dijkstraNode)
+
+		if visited[current.id] {
+			continue
+		}
+		visited[current.id] = true
+
+		if current.id == targetID {
+			return PathResult{
+				Distance:    current.distance,
+				Duration:    current.duration,
+				IsReachable: true,
+			}
+		}
+
+		for _, edge := range pf.graph.Edges[current.id] {
+			if visited[edge.To] {
+				continue
+			}
+
+			newDist := current.distance + edge.Distance
+			if known, seen := distances[edge.To]; seen && newDist >= known {
+				continue
+			}
+			toPoint, ok := pf.graph.node(edge.To)
+			if !ok {
+				continue
+			}
+
+			distances[edge.To] = newDist
+			heap.Push(&priorityQueue, &dijkstraNode{
+				id:       edge.To,
+				distance: newDist,
+				duration: current.duration + edge.Duration,
+				estimate: heuristic(toPoint),
+			})
+		}
+	}
+
+	return PathResult{IsReachable: false}
+}
+
 // ShortestPathToMany finds shortest paths from source to multiple targets
 func (pf *Pathfinder) ShortestPathToMany(sourceID NodeID, targetIDs []NodeID) []PathResult {
 	results := make([]PathResult, len(targetIDs))
@@ -241,9 +503,12 @@ func (pf *Pathfinder) ShortestPathToMany(sourceID NodeID, targetIDs []NodeID) []
 	// Create a set of targets for quick lookup
 	targetSet, remainingTargets := pf.initTargetSet(targetIDs)
 
-	if _, exists := pf.graph.Nodes[sourceID]; !exists || remainingTargets == 0 {
+	if !pf.graph.hasNode(sourceID) || remainingTargets == 0 {
 		return results
 	}
+	if pf.cost.Enabled() {
+		return pf.compositePathToMany(sourceID, targetIDs, false)
+	}
 
 	// Initialize distances
 	distances, durations, visited := pf.initDijkstraState()
@@ -278,7 +543,7 @@ func (pf *Pathfinder) ShortestPathToMany(sourceID NodeID, targetIDs []NodeID) []
 		}
 
 		// Process neighbors
-		pf.relaxEdges(current, distances, durations, visited, &priorityQueue)
+		pf.relaxEdges(current, distances, durations, visited, nil, &priorityQueue)
 	}
 
 	return results
@@ -288,7 +553,7 @@ func (pf *Pathfinder) initTargetSet(targetIDs []NodeID) (map[NodeID]int, int) {
 	targetSet := make(map[NodeID]int)
 	remainingTargets := 0
 	for i, targetID := range targetIDs {
-		if _, exists := pf.graph.Nodes[targetID]; exists {
+		if pf.graph.hasNode(targetID) {
 			targetSet[targetID] = i
 			remainingTargets++
 		}
@@ -301,15 +566,22 @@ func (pf *Pathfinder) initDijkstraState() (map[NodeID]float64, map[NodeID]float6
 	distances := make(map[NodeID]float64)
 	durations := make(map[NodeID]float64)
 	visited := make(map[NodeID]bool)
-	for id := range pf.graph.Nodes {
+	pf.graph.eachNode(func(id NodeID, _ orb.Point) {
 		distances[id] = math.MaxFloat64
 		durations[id] = math.MaxFloat64
-	}
+	})
 
 	return distances, durations, visited
 }
 
-func (pf *Pathfinder) relaxEdges(current *dijkstraNode, distances, durations map[NodeID]float64, visited map[NodeID]bool, priorityQueue *priorityQueue) {
+// relaxEdges pushes improved neighbors of current, recording predecessors when previous is non-nil
+func (pf *Pathfinder) relaxEdges(
+	current *dijkstraNode,
+	distances, durations map[NodeID]float64,
+	visited map[NodeID]bool,
+	previous map[NodeID]NodeID,
+	priorityQueue *priorityQueue,
+) {
 	for _, edge := range pf.graph.Edges[current.id] {
 		if visited[edge.To] {
 			continue
@@ -319,6 +591,9 @@ func (pf *Pathfinder) relaxEdges(current *dijkstraNode, distances, durations map
 		if newDist < distances[edge.To] {
 			distances[edge.To] = newDist
 			durations[edge.To] = current.duration + edge.Duration
+			if previous != nil {
+				previous[edge.To] = current.id
+			}
 			heap.Push(priorityQueue, &dijkstraNode{
 				id:       edge.To,
 				distance: newDist,
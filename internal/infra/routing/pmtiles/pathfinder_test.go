@@ -173,6 +173,74 @@ func TestRoadGraph_AddSegment_NodeDeduplication(t *testing.T) {
 	assert.Len(t, graph.Nodes, 3)
 }
 
+func TestRoadGraph_AddSegment_ClampsNearZeroLengthEdge(t *testing.T) {
+	graph := NewRoadGraph()
+	graph.minEdgeDistance = 1.0
+
+	// The points round to distinct keys but are only ~0.2m apart
+	segment := &RoadSegment{
+		Points: []orb.Point{
+			{121.500004, 25.00},
+			{121.500006, 25.00},
+		},
+		Highway:  "primary",
+		MaxSpeed: 50.0,
+	}
+
+	graph.AddSegment(segment)
+
+	require.Len(t, graph.Nodes, 2)
+	from := graph.pointMap[pointKey(segment.Points[0])]
+	require.Len(t, graph.Edges[from], 1)
+	edge := graph.Edges[from][0]
+	assert.Equal(t, 1.0, edge.Distance)
+	assert.InDelta(t, 1.0/1000.0/50.0*3600.0, edge.Duration, 1e-9)
+
+	// Without a minimum the edge keeps its measured length
+	unclamped := NewRoadGraph()
+	unclamped.AddSegment(segment)
+	assert.Less(t, unclamped.Edges[from][0].Distance, 0.5)
+}
+
+func TestRoadGraph_AddSegment_CustomMinEdgeDistance(t *testing.T) {
+	graph := NewRoadGraph()
+	graph.minEdgeDistance = 5.0
+
+	segment := &RoadSegment{
+		Points: []orb.Point{
+			{121.50000, 25.00},
+			{121.50002, 25.00}, // ~2m apart
+		},
+		Highway: "service",
+	}
+
+	graph.AddSegment(segment)
+
+	from := graph.pointMap[pointKey(segment.Points[0])]
+	require.Len(t, graph.Edges[from], 1)
+	assert.Equal(t, 5.0, graph.Edges[from][0].Distance)
+	assert.Greater(t, graph.Edges[from][0].Duration, 0.0)
+}
+
+func TestRoadGraph_AddSegment_DropsSelfLoop(t *testing.T) {
+	graph := NewRoadGraph()
+
+	// Both points deduplicate to the same node key
+	segment := &RoadSegment{
+		Points: []orb.Point{
+			{121.500001, 25.00},
+			{121.500002, 25.00},
+		},
+		Highway:  "primary",
+		MaxSpeed: 50.0,
+	}
+
+	graph.AddSegment(segment)
+
+	assert.Len(t, graph.Nodes, 1)
+	assert.Empty(t, graph.Edges)
+}
+
 func TestRoadGraph_FindNearestNode(t *testing.T) {
 	graph := NewRoadGraph()
 
@@ -212,6 +280,59 @@ func TestRoadGraph_FindNearestNode_Empty(t *testing.T) {
 	assert.Equal(t, 0.0, distance)
 }
 
+func TestRoadGraph_FindNearestEdge(t *testing.T) {
+	graph := NewRoadGraph()
+
+	// A long road whose end nodes are ~500m from the query point
+	graph.AddSegment(&RoadSegment{Points: []orb.Point{{121.500, 25.000}, {121.510, 25.000}}, MaxSpeed: 50})
+	// A side road whose start node is the nearest node (~110m) but whose edge is farther than the long road
+	graph.AddSegment(&RoadSegment{Points: []orb.Point{{121.505, 25.0012}, {121.505, 25.0030}}, MaxSpeed: 30})
+
+	queryPoint := orb.Point{121.505, 25.0002}
+
+	nodeID, nodeDistance, found := graph.FindNearestNode(queryPoint)
+	require.True(t, found)
+	assert.Equal(t, orb.Point{121.505, 25.0012}, graph.Nodes[nodeID])
+
+	projection, found := graph.FindNearestEdge(queryPoint)
+	require.True(t, found)
+
+	// The closest edge is the long road, not the side road owning the nearest node
+	endpoints := []orb.Point{graph.Nodes[projection.From], graph.Nodes[projection.To]}
+	assert.ElementsMatch(t, []orb.Point{{121.500, 25.000}, {121.510, 25.000}}, endpoints)
+	assert.Less(t, projection.Distance, nodeDistance)
+
+	// The projection lies on the edge at the expected fraction
+	assert.InDelta(t, 25.000, projection.Point[1], 1e-9)
+	assert.InDelta(t, 121.505, projection.Point[0], 1e-9)
+	assert.InDelta(t, 0.5, projection.Offset, 1e-6)
+	assert.InDelta(t, haversineDistance(queryPoint, projection.Point), projection.Distance, 1e-9)
+	assert.InDelta(t, 22.2, projection.Distance, 0.5)
+
+	from := graph.Nodes[projection.From]
+	to := graph.Nodes[projection.To]
+	assert.InDelta(t, haversineDistance(from, to)*projection.Offset, haversineDistance(from, projection.Point), 0.01)
+}
+
+func TestRoadGraph_FindNearestEdge_ClampsToEndpoint(t *testing.T) {
+	graph := NewRoadGraph()
+	graph.AddSegment(&RoadSegment{Points: []orb.Point{{121.500, 25.000}, {121.501, 25.000}}, OneWay: true})
+
+	projection, found := graph.FindNearestEdge(orb.Point{121.503, 25.0001})
+
+	require.True(t, found)
+	assert.InDelta(t, 1.0, projection.Offset, 1e-9)
+	assert.Equal(t, orb.Point{121.501, 25.000}, projection.Point)
+}
+
+func TestRoadGraph_FindNearestEdge_Empty(t *testing.T) {
+	graph := NewRoadGraph()
+
+	_, found := graph.FindNearestEdge(orb.Point{121.5, 25.0})
+
+	assert.False(t, found)
+}
+
 func TestPointKey(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -733,8 +854,8 @@ func TestEdgeCalculation(t *testing.T) {
 	}
 }
 
-// TestDijkstraCorrectness verifies Dijkstra finds the shortest path
-func TestDijkstraCorrectness(t *testing.T) {
+// buildDiamondGraph builds a diamond network where A-C-D is shorter than A-B-D
+func buildDiamondGraph() *RoadGraph {
 	graph := NewRoadGraph()
 
 	// Create a diamond-shaped network:
@@ -791,6 +912,13 @@ func TestDijkstraCorrectness(t *testing.T) {
 	graph.AddSegment(segmentAC)
 	graph.AddSegment(segmentCD)
 
+	return graph
+}
+
+// TestDijkstraCorrectness verifies Dijkstra finds the shortest path
+func TestDijkstraCorrectness(t *testing.T) {
+	graph := buildDiamondGraph()
+
 	pf := NewPathfinder(graph)
 
 	nodeA := graph.pointMap[pointKey(orb.Point{121.50, 25.00})]
@@ -815,6 +943,78 @@ func TestDijkstraCorrectness(t *testing.T) {
 	assert.InDelta(t, expectedDirectDistance, result.Distance, expectedDirectDistance*0.1)
 }
 
+func TestPathfinder_ShortestPath_ReconstructsGeometry(t *testing.T) {
+	graph := buildDiamondGraph()
+	pf := NewPathfinder(graph)
+
+	nodeA := graph.pointMap[pointKey(orb.Point{121.50, 25.00})]
+	nodeC := graph.pointMap[pointKey(orb.Point{121.51, 25.00})]
+	nodeD := graph.pointMap[pointKey(orb.Point{121.52, 25.00})]
+
+	result := pf.ShortestPath(nodeA, nodeD)
+
+	require.True(t, result.IsReachable)
+	assert.Equal(t, []NodeID{nodeA, nodeC, nodeD}, result.Path)
+	assert.Equal(t, []orb.Point{{121.50, 25.00}, {121.51, 25.00}, {121.52, 25.00}}, result.Geometry)
+
+	same := pf.ShortestPath(nodeA, nodeA)
+	assert.Equal(t, []NodeID{nodeA}, same.Path)
+	assert.Equal(t, []orb.Point{{121.50, 25.00}}, same.Geometry)
+
+	// Bulk queries leave the path untracked
+	bulk := pf.ShortestPathToMany(nodeA, []NodeID{nodeD})
+	require.Len(t, bulk, 1)
+	assert.Nil(t, bulk[0].Path)
+	assert.Nil(t, bulk[0].Geometry)
+}
+
+func TestAStarPath_MatchesDijkstra(t *testing.T) {
+	for name, graph := range map[string]*RoadGraph{
+		"full precision": buildDiamondGraph(),
+		"compact":        compactCopy(buildDiamondGraph()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			pf := NewPathfinder(graph)
+
+			for _, sourceID := range graph.pointMap {
+				for _, targetID := range graph.pointMap {
+					want := pf.ShortestPath(sourceID, targetID)
+					got := pf.AStarPath(sourceID, targetID)
+
+					assert.Equal(t, want.IsReachable, got.IsReachable)
+					assert.InDelta(t, want.Distance, got.Distance, 1e-6)
+					assert.InDelta(t, want.Duration, got.Duration, 1e-6)
+				}
+			}
+		})
+	}
+}
+
+func TestAStarPath_UnreachableAndMissingNodes(t *testing.T) {
+	graph := NewRoadGraph()
+	graph.AddSegment(&RoadSegment{Points: []orb.Point{{121.50, 25.00}, {121.51, 25.00}}, Highway: "primary", OneWay: true})
+	pf := NewPathfinder(graph)
+
+	nodeA := graph.pointMap[pointKey(orb.Point{121.50, 25.00})]
+	nodeB := graph.pointMap[pointKey(orb.Point{121.51, 25.00})]
+
+	assert.True(t, pf.AStarPath(nodeA, nodeB).IsReachable)
+	assert.False(t, pf.AStarPath(nodeB, nodeA).IsReachable, "one-way edge cannot be traversed backwards")
+	assert.False(t, pf.AStarPath(nodeA, NodeID(999)).IsReachable)
+	assert.Equal(t, PathResult{IsReachable: true}, pf.AStarPath(nodeA, nodeA))
+}
+
+// compactCopy rebuilds a graph with float32 node coordinates, keeping its node IDs and edges
+func compactCopy(graph *RoadGraph) *RoadGraph {
+	compact := NewCompactRoadGraph()
+	compact.nodeIdx = graph.nodeIdx
+	compact.pointMap = graph.pointMap
+	compact.Edges = graph.Edges
+	graph.eachNode(compact.setNode)
+
+	return compact
+}
+
 // TestFloatingPointPrecision tests node deduplication with floating point values
 func TestFloatingPointPrecision(t *testing.T) {
 	graph := NewRoadGraph()
@@ -937,3 +1137,102 @@ func TestNaNHandling(t *testing.T) {
 		}
 	}
 }
+
+// buildComparisonGraphs builds the same small grid network at full and compact coordinate precision
+func buildComparisonGraphs() (*RoadGraph, *RoadGraph) {
+	segments := []RoadSegment{
+		{Points: []orb.Point{{121.5000, 25.0000}, {121.5050, 25.0000}, {121.5100, 25.0000}}, Highway: "primary", MaxSpeed: 50.0},
+		{Points: []orb.Point{{121.5000, 25.0000}, {121.5000, 25.0050}, {121.5050, 25.0050}}, Highway: "residential"},
+		{Points: []orb.Point{{121.5050, 25.0050}, {121.5050, 25.0000}}, Highway: "secondary", OneWay: true},
+		{Points: []orb.Point{{121.5050, 25.0050}, {121.5100, 25.0050}, {121.5100, 25.0000}}, Highway: "tertiary", MaxSpeed: 30.0},
+	}
+
+	full := NewRoadGraph()
+	compact := NewCompactRoadGraph()
+	for idx := range segments {
+		full.AddSegment(&segments[idx])
+		compact.AddSegment(&segments[idx])
+	}
+
+	return full, compact
+}
+
+func TestNewCompactRoadGraph(t *testing.T) {
+	graph := NewCompactRoadGraph()
+	graph.AddSegment(&RoadSegment{
+		Points:  []orb.Point{{121.5654, 25.0330}, {121.5170, 25.0478}},
+		Highway: "primary",
+	})
+
+	assert.Nil(t, graph.Nodes)
+	assert.Len(t, graph.compactNodes, 2)
+	assert.Equal(t, 2, graph.nodeCount())
+
+	for key, id := range graph.pointMap {
+		point, ok := graph.node(id)
+		require.True(t, ok, key)
+		assert.InDelta(t, 121.5, point[0], 0.1)
+		assert.InDelta(t, 25.0, point[1], 0.1)
+	}
+}
+
+func TestCompactRoadGraph_MatchesFullPrecision(t *testing.T) {
+	full, compact := buildComparisonGraphs()
+	require.Equal(t, full.nodeCount(), compact.nodeCount())
+
+	// float32 keeps geographic coordinates within about 1e-5 degrees (~1m)
+	for key, fullID := range full.pointMap {
+		compactID, ok := compact.pointMap[key]
+		require.True(t, ok, key)
+
+		fullPoint, _ := full.node(fullID)
+		compactPoint, ok := compact.node(compactID)
+		require.True(t, ok, key)
+		assert.InDelta(t, fullPoint[0], compactPoint[0], 1e-5)
+		assert.InDelta(t, fullPoint[1], compactPoint[1], 1e-5)
+	}
+
+	source := orb.Point{121.5001, 25.0001}
+	fullSource, _, ok := full.FindNearestNode(source)
+	require.True(t, ok)
+	compactSource, _, ok := compact.FindNearestNode(source)
+	require.True(t, ok)
+
+	fullTargets := make([]NodeID, 0, len(full.pointMap))
+	compactTargets := make([]NodeID, 0, len(full.pointMap))
+	for key, id := range full.pointMap {
+		fullTargets = append(fullTargets, id)
+		compactTargets = append(compactTargets, compact.pointMap[key])
+	}
+
+	fullResults := NewPathfinder(full).ShortestPathToMany(fullSource, fullTargets)
+	compactResults := NewPathfinder(compact).ShortestPathToMany(compactSource, compactTargets)
+	require.Len(t, compactResults, len(fullResults))
+
+	for idx := range fullResults {
+		assert.Equal(t, fullResults[idx].IsReachable, compactResults[idx].IsReachable)
+		assert.InDelta(t, fullResults[idx].Distance, compactResults[idx].Distance, 1.0)
+		assert.InDelta(t, fullResults[idx].Duration, compactResults[idx].Duration, 1.0)
+
+		single := NewPathfinder(compact).ShortestPath(compactSource, compactTargets[idx])
+		assert.InDelta(t, fullResults[idx].Distance, single.Distance, 1.0)
+	}
+}
+
+func TestMergeGraphs_CompactTarget(t *testing.T) {
+	full, _ := buildComparisonGraphs()
+	merged := NewCompactRoadGraph()
+
+	mergeGraphs(merged, full)
+
+	assert.Nil(t, merged.Nodes)
+	assert.Equal(t, full.nodeCount(), merged.nodeCount())
+	assert.Len(t, merged.Edges, len(full.Edges))
+	assert.Less(t, estimateGraphMemoryBytes(merged), estimateGraphMemoryBytes(full))
+
+	from, _, ok := merged.FindNearestNode(orb.Point{121.5000, 25.0000})
+	require.True(t, ok)
+	to, _, ok := merged.FindNearestNode(orb.Point{121.5100, 25.0050})
+	require.True(t, ok)
+	assert.True(t, NewPathfinder(merged).ShortestPath(from, to).IsReachable)
+}
@@ -0,0 +1,175 @@
+package pmtiles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"radar/config"
+	"radar/internal/usecase"
+)
+
+// profileRoutingService routes each query on the tileset of the profile it names.
+// Every profile has its own PMTiles server, parser, and tile cache; the first configured profile is the default.
+type profileRoutingService struct {
+	profiles       map[string]*pmtilesRoutingService
+	sources        map[string]string // Configured archive source of each profile, reopened by Reload
+	names          []string          // Profile names in configuration order
+	defaultProfile string
+}
+
+// newProfileRoutingService creates a tileset service for each configured profile
+func newProfileRoutingService(
+	cfg *config.PMTilesConfig,
+	largeGraph *LargeGraphDelegate,
+	estimateSpeedKmh float64,
+	logger *slog.Logger,
+) (*profileRoutingService, error) {
+	svc := &profileRoutingService{
+		profiles: make(map[string]*pmtilesRoutingService, len(cfg.Profiles)),
+		sources:  make(map[string]string, len(cfg.Profiles)),
+		names:    make([]string, 0, len(cfg.Profiles)),
+	}
+
+	for _, profile := range cfg.Profiles {
+		name := strings.TrimSpace(profile.Name)
+		tileset, err := newTilesetService(cfg, name, profile.Source, largeGraph, estimateSpeedKmh, logger.With(slog.String("profile", name)))
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+
+		svc.profiles[name] = tileset
+		svc.sources[name] = profile.Source
+		svc.names = append(svc.names, name)
+	}
+	svc.defaultProfile = svc.names[0]
+
+	logger.Info("PMTiles routing profiles initialized",
+		slog.Any("profiles", svc.names),
+		slog.String("default_profile", svc.defaultProfile),
+	)
+
+	return svc, nil
+}
+
+// tileset returns the service of profile, or of the default profile when profile is empty
+func (s *profileRoutingService) tileset(profile string) (*pmtilesRoutingService, error) {
+	if profile == usecase.DefaultRoutingProfile {
+		profile = s.defaultProfile
+	}
+
+	tileset, ok := s.profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", usecase.ErrUnknownRoutingProfile, profile)
+	}
+
+	return tileset, nil
+}
+
+// OneToMany routes from source to targets on the profile's tileset
+func (s *profileRoutingService) OneToMany(ctx context.Context, profile string, source usecase.Coordinate, targets []usecase.Coordinate) (*usecase.OneToManyResult, error) {
+	tileset, err := s.tileset(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return tileset.OneToMany(ctx, profile, source, targets)
+}
+
+// ManyToMany routes every source to every target on the profile's tileset
+func (s *profileRoutingService) ManyToMany(ctx context.Context, profile string, sources, targets []usecase.Coordinate) ([]*usecase.OneToManyResult, error) {
+	tileset, err := s.tileset(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return tileset.ManyToMany(ctx, profile, sources, targets)
+}
+
+// FindNearestNode finds the nearest node to coord on the profile's tileset
+func (s *profileRoutingService) FindNearestNode(ctx context.Context, profile string, coord usecase.Coordinate) (*usecase.NodeInfo, bool, error) {
+	tileset, err := s.tileset(profile)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return tileset.FindNearestNode(ctx, profile, coord)
+}
+
+// SnapBatch finds the nearest node to each coordinate on the profile's tileset
+func (s *profileRoutingService) SnapBatch(ctx context.Context, profile string, coords []usecase.Coordinate) ([]usecase.NodeInfo, []bool, error) {
+	tileset, err := s.tileset(profile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tileset.SnapBatch(ctx, profile, coords)
+}
+
+// CalculateDistance calculates the road distance between two coordinates on the profile's tileset
+func (s *profileRoutingService) CalculateDistance(ctx context.Context, profile string, source, target usecase.Coordinate) (*usecase.RouteResult, error) {
+	tileset, err := s.tileset(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return tileset.CalculateDistance(ctx, profile, source, target)
+}
+
+// CalculateRoute calculates the road route between two coordinates on the profile's tileset
+func (s *profileRoutingService) CalculateRoute(ctx context.Context, profile string, source, target usecase.Coordinate) (*usecase.RouteResult, error) {
+	tileset, err := s.tileset(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return tileset.CalculateRoute(ctx, profile, source, target)
+}
+
+// NearestRoadName finds the closest named road to coord on the default profile's tileset
+func (s *profileRoutingService) NearestRoadName(ctx context.Context, coord usecase.Coordinate) (string, bool, error) {
+	tileset, err := s.tileset(usecase.DefaultRoutingProfile)
+	if err != nil {
+		return "", false, err
+	}
+
+	return tileset.NearestRoadName(ctx, coord)
+}
+
+// IsReady reports whether every profile's archive has served a tile.
+// Each unready profile starts its own readiness probe.
+func (s *profileRoutingService) IsReady() bool {
+	ready := true
+	for _, name := range s.names {
+		if !s.profiles[name].IsReady() {
+			ready = false
+		}
+	}
+
+	return ready
+}
+
+// Metadata reports the default profile's archive, with readiness across all profiles
+func (s *profileRoutingService) Metadata() usecase.RoutingMetadata {
+	metadata := s.profiles[s.defaultProfile].Metadata()
+	metadata.Ready = s.IsReady()
+	metadata.Profile = s.defaultProfile
+
+	return metadata
+}
+
+// Reload reopens every profile's configured source, so extracts republished at the same paths are picked up.
+// newSource names the single-source archive and is ignored. A profile that fails to reload keeps its current
+// archive while the others switch.
+func (s *profileRoutingService) Reload(ctx context.Context, _ string) error {
+	var errs []error
+	for _, name := range s.names {
+		if err := s.profiles[name].Reload(ctx, s.sources[name]); err != nil {
+			errs = append(errs, fmt.Errorf("profile %q: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,138 @@
+package pmtiles
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"radar/config"
+	"radar/internal/usecase"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/maptile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPMTilesService_Profiles_RouteOnEachTileset(t *testing.T) {
+	source := usecase.Coordinate{Lat: 25.0330, Lng: 121.5600}
+	target := usecase.Coordinate{Lat: 25.0330, Lng: 121.5650}
+	// Each archive holds one zoom-13 tile, which zoom fallback serves for every zoom-14 tile around the pair
+	tile := maptile.At(orb.Point{source.Lng, source.Lat}, 13)
+	require.Equal(t, tile, maptile.At(orb.Point{target.Lng, target.Lat}, 13))
+
+	// Driving takes a detour north of the straight footpath
+	drivingPath := writeSingleTileArchive(t, tile, orb.LineString{
+		{source.Lng, source.Lat}, {121.5625, 25.0370}, {target.Lng, target.Lat},
+	})
+	walkingPath := writeSingleTileArchive(t, tile, orb.LineString{
+		{source.Lng, source.Lat}, {121.5625, source.Lat}, {target.Lng, target.Lat},
+	})
+
+	svc, err := NewPMTilesRoutingService(PMTilesServiceParams{
+		Config: &config.PMTilesConfig{
+			Enabled:      true,
+			RoadLayer:    "transportation",
+			ZoomLevel:    14,
+			ZoomFallback: true,
+			Profiles: []config.PMTilesProfileConfig{
+				{Name: "driving", Source: drivingPath},
+				{Name: "walking", Source: walkingPath},
+			},
+		},
+		Logger: slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError})),
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	driving, err := svc.CalculateDistance(ctx, "driving", source, target)
+	require.NoError(t, err)
+	walking, err := svc.CalculateDistance(ctx, "walking", source, target)
+	require.NoError(t, err)
+
+	assert.True(t, driving.IsReachable)
+	assert.False(t, driving.IsEstimate)
+	assert.True(t, walking.IsReachable)
+	assert.False(t, walking.IsEstimate)
+	assert.InDelta(t, 0.5, walking.DistanceKm, 0.05)
+	assert.Greater(t, driving.DistanceKm, walking.DistanceKm+0.3)
+
+	// The default profile is the first configured one
+	defaulted, err := svc.CalculateDistance(ctx, usecase.DefaultRoutingProfile, source, target)
+	require.NoError(t, err)
+	assert.InDelta(t, driving.DistanceKm, defaulted.DistanceKm, 1e-9)
+
+	route, err := svc.CalculateRoute(ctx, "walking", source, target)
+	require.NoError(t, err)
+	assert.InDelta(t, walking.DistanceKm, route.DistanceKm, 1e-9)
+
+	metadata := svc.Metadata()
+	assert.Equal(t, "driving", metadata.Profile)
+	assert.Equal(t, "roads", metadata.Tileset)
+}
+
+func TestPMTilesService_Profiles_UnknownProfile(t *testing.T) {
+	svc := &profileRoutingService{
+		profiles:       map[string]*pmtilesRoutingService{"walking": {}},
+		names:          []string{"walking"},
+		defaultProfile: "walking",
+	}
+	point := usecase.Coordinate{Lat: 25.0330, Lng: 121.5600}
+
+	_, err := svc.OneToMany(context.Background(), "cycling", point, []usecase.Coordinate{point})
+	require.ErrorIs(t, err, usecase.ErrUnknownRoutingProfile)
+	assert.Contains(t, err.Error(), `"cycling"`)
+
+	_, _, err = svc.SnapBatch(context.Background(), "cycling", []usecase.Coordinate{point})
+	require.ErrorIs(t, err, usecase.ErrUnknownRoutingProfile)
+}
+
+func TestPMTilesService_Profiles_Reload(t *testing.T) {
+	source := usecase.Coordinate{Lat: 25.0330, Lng: 121.5600}
+	target := usecase.Coordinate{Lat: 25.0330, Lng: 121.5650}
+	tile := maptile.At(orb.Point{source.Lng, source.Lat}, 14)
+	require.Equal(t, tile, maptile.At(orb.Point{target.Lng, target.Lat}, 14))
+
+	drivingPath := writeSingleTileArchive(t, tile, orb.LineString{{source.Lng, source.Lat}, {target.Lng, target.Lat}})
+	walkingPath := writeSingleTileArchive(t, tile, orb.LineString{{source.Lng, source.Lat}, {target.Lng, target.Lat}})
+
+	routing, err := NewPMTilesRoutingService(PMTilesServiceParams{
+		Config: &config.PMTilesConfig{
+			Enabled:   true,
+			RoadLayer: "transportation",
+			ZoomLevel: 14,
+			Profiles: []config.PMTilesProfileConfig{
+				{Name: "driving", Source: drivingPath},
+				{Name: "walking", Source: walkingPath},
+			},
+		},
+		Logger: slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError})),
+	})
+	require.NoError(t, err)
+	reloader, ok := routing.(SourceReloader)
+	require.True(t, ok, "a profile service must reload on SIGHUP like a single-source one")
+	svc := routing.(*profileRoutingService)
+	ctx := context.Background()
+
+	walking, err := svc.CalculateDistance(ctx, "walking", source, target)
+	require.NoError(t, err)
+	require.False(t, walking.IsEstimate)
+	walkingServer := svc.profiles["walking"].currentArchive().server
+
+	// A new walking extract without the road is published at the same path
+	republished := writeSingleTileArchive(t, tile, orb.LineString{{121.5590, 25.0334}, {121.5595, 25.0334}})
+	require.NoError(t, os.Rename(republished, walkingPath))
+
+	require.NoError(t, reloader.Reload(ctx, ""))
+
+	walking, err = svc.CalculateDistance(ctx, "walking", source, target)
+	require.NoError(t, err)
+	assert.True(t, walking.IsEstimate, "the walking profile must read the republished extract")
+	driving, err := svc.CalculateDistance(ctx, "driving", source, target)
+	require.NoError(t, err)
+	assert.False(t, driving.IsEstimate)
+
+	// The archive at the same location keeps its PMTiles server rather than starting another
+	assert.Same(t, walkingServer, svc.profiles["walking"].currentArchive().server)
+}
@@ -7,12 +7,15 @@ import (
 	"io"
 	"log"
 	"log/slog"
+	"math"
 	"net/http"
 	"net/url"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"radar/config"
@@ -22,15 +25,40 @@ import (
 	"github.com/paulmach/orb/maptile"
 	"github.com/protomaps/go-pmtiles/pmtiles"
 	"go.uber.org/fx"
-	_ "gocloud.dev/blob/gcsblob" // Register GCS blob driver for gs:// URLs
+	_ "gocloud.dev/blob/azureblob" // Register Azure Blob driver for azblob:// URLs
+	_ "gocloud.dev/blob/gcsblob"   // Register GCS blob driver for gs:// URLs
+	_ "gocloud.dev/blob/s3blob"    // Register S3 blob driver for s3:// URLs
 )
 
-const defaultRoadLayerName = "transportation"
+// defaultMaxSnapDistance is how far in meters a coordinate may be from a road to be snapped onto it
+const defaultMaxSnapDistance = 500.0
+
+var (
+	errInvalidTileBounds   = errors.New("tile bounds must be finite")
+	errTileSpanExceeded    = errors.New("tile range exceeds the maximum span")
+	errTileNotFound        = errors.New("tile not found")
+	errGraphMemoryExceeded = errors.New("merged graph exceeds the memory budget")
+	errLargeGraph          = errors.New("merged graph exceeds the large graph edge threshold")
+)
+
+// Approximate per-element memory cost of a RoadGraph, covering the map entries
+// for Nodes and pointMap (including the point key string) and a single Edge value.
+const (
+	approxNodeBytes = 96
+	approxEdgeBytes = 32
+
+	// A compact node stores two float32 coordinates instead of two float64 values
+	approxCompactNodeBytes = approxNodeBytes - 8
+)
+
+// How long a readiness probe may spend reading the archive header and its center tile
+const readinessProbeTimeout = 5 * time.Second
 
 // isCloudStorageScheme checks if the given URL scheme uses cloud storage bucket semantics.
 // For these schemes, gocloud.dev only uses the Host as bucket name and ignores the Path,
 // so we need to separate the bucket URL from the prefix (subdirectory path).
-// Add new schemes here to support additional cloud providers compatible with gocloud.dev.
+// Add new schemes here to support additional cloud providers compatible with gocloud.dev,
+// together with the driver import that registers them.
 func isCloudStorageScheme(scheme string) bool {
 	switch scheme {
 	case "gs", "s3", "azblob": // Google Cloud Storage, Amazon S3, Azure Blob Storage
@@ -40,103 +68,341 @@ func isCloudStorageScheme(scheme string) bool {
 	}
 }
 
-// pmtilesRoutingService implements RoutingUsecase using PMTiles for tile data
-type pmtilesRoutingService struct {
+// SourceReloader is implemented by routing services that can switch to an updated PMTiles archive
+// without a restart
+type SourceReloader interface {
+	Reload(ctx context.Context, newSource string) error
+}
+
+// tileArchive is one PMTiles archive and the server that reads tiles from it
+type tileArchive struct {
 	source      string
 	tilesetName string // Name of the tileset (extracted from filename, e.g., "walking" from "walking.pmtiles")
-	roadLayer   string
-	zoomLevel   int
-	logger      *slog.Logger
 	server      *pmtiles.Server
-	parser      *MVTParser
+
+	// Bucket and prefix the archive is served from, used to read its header
+	bucketURL    string
+	bucketPrefix string
+}
+
+// openTileArchive starts a PMTiles server for the archive at source
+func openTileArchive(source string, cacheSize int) (*tileArchive, error) {
+	// Parse source to extract bucket URL, prefix (subdirectory), and tileset name
+	// The PMTiles server expects a bucket URL and optional prefix for subdirectories
+	bucketURL, prefix, tilesetName := parseSourcePath(source)
+
+	// Create a silent logger for pmtiles (it requires *log.Logger)
+	silentLogger := log.New(io.Discard, "", 0)
+
+	// Create PMTiles server - handles local files, HTTP, and cloud storage
+	// bucketURL is the bucket/directory, prefix is the subdirectory path
+	server, err := pmtiles.NewServer(bucketURL, prefix, silentLogger, cacheSize, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PMTiles server: %w", err)
+	}
+
+	// Start the server (required for serving tiles)
+	server.Start()
+
+	return &tileArchive{
+		source:       source,
+		tilesetName:  tilesetName,
+		server:       server,
+		bucketURL:    bucketURL,
+		bucketPrefix: prefix,
+	}, nil
+}
+
+// reopen returns the archive at source, sharing a's server when source is read from the same bucket and prefix.
+// The shared server refetches its cached directories once the archive's etag changes, so an extract
+// republished at the same path is read afresh.
+func (a *tileArchive) reopen(source string, cacheSize int) (*tileArchive, error) {
+	bucketURL, prefix, tilesetName := parseSourcePath(source)
+	if bucketURL != a.bucketURL || prefix != a.bucketPrefix {
+		return openTileArchive(source, cacheSize)
+	}
+
+	return &tileArchive{
+		source:       source,
+		tilesetName:  tilesetName,
+		server:       a.server,
+		bucketURL:    bucketURL,
+		bucketPrefix: prefix,
+	}, nil
+}
+
+// pmtilesRoutingService implements RoutingUsecase using PMTiles for tile data
+type pmtilesRoutingService struct {
+	roadLayer string
+	zoomLevel int
+	logger    *slog.Logger
+	parser    *MVTParser
+
+	// Archive tiles are read from; Reload swaps it under tileCacheMu
+	archive   *tileArchive
+	cacheSize int
+	reloadMu  sync.Mutex // Serializes Reload
+
+	// Approximate memory budget for a merged graph (0 disables the check)
+	maxGraphMemoryBytes int64
+
+	// When set, targets without a road route are unreachable rather than Haversine estimates
+	disableHaversineFallback bool
+
+	// When set, nearest-node lookups also report the nearest edge projection
+	includeEdgeSnap bool
+
+	// Minimum edge length in meters applied when building tile graphs
+	minEdgeDistance float64
+
+	// Maximum snap distance in meters (0 uses defaultMaxSnapDistance); RoutingOptions on a call override it
+	maxSnapDistance float64
+
+	// Maximum tiles per axis a routing area may span
+	maxTileSpan int
+
+	// When set, routing areas cover only the tiles within corridorBufferTiles of each source-target line
+	corridorTiles       bool
+	corridorBufferTiles int
+
+	// When set, graphs store node coordinates as float32 to reduce memory
+	compactNodeCoordinates bool
+
+	// Composite edge cost weights; disabled weights route by shortest distance
+	routingCost CostWeights
+
+	// Speed in km/h that turns Haversine estimates into ETAs
+	estimateSpeedKmh float64
+
+	// Backend for queries whose graph is too large to build quickly; nil keeps them on the Haversine fallback
+	largeGraphRouter usecase.RoutingUsecase
+
+	// Profile delegated queries are routed on, resolved for this tileset; the caller's profile names a PMTiles
+	// tileset, which may have no counterpart in the large graph router
+	largeGraphProfile string
+
+	// Merged graph edge count above which a query is handed to largeGraphRouter (0 only delegates oversized areas)
+	largeGraphEdgeThreshold int
 
 	// Cache for loaded tiles
 	tileCache   map[string]*RoadGraph
 	tileCacheMu sync.RWMutex
+
+	// How long cached tile graphs are used before the source version is rechecked (0 never rechecks)
+	tileCacheMaxAge time.Duration
+	sourceVersion   func(ctx context.Context) (string, error)
+	now             func() time.Time
+
+	// Source version the cached tile graphs were built from and when it was last checked; guarded by tileCacheMu
+	tileCacheVersion   string
+	tileCacheCheckedAt time.Time
+
+	// When set, tiles missing at zoomLevel fall back to their ancestor at the archive's max zoom
+	zoomFallback   bool
+	archiveMaxZoom func(ctx context.Context) (maptile.Zoom, error)
+
+	// Archive max zoom once read; guarded by tileCacheMu and reset with the tile cache
+	maxZoom      maptile.Zoom
+	maxZoomKnown bool
+
+	// Readiness: set once any tile is fetched; probeTile fetches one when no query has yet
+	tileFetched atomic.Bool
+	probing     atomic.Bool
+	probeTile   func(ctx context.Context) error
 }
 
 // PMTilesServiceParams holds dependencies for PMTiles routing service
 type PMTilesServiceParams struct {
 	fx.In
 
-	Config *config.PMTilesConfig `optional:"true"`
-	Logger *slog.Logger
+	Config     *config.PMTilesConfig `optional:"true"`
+	Routing    *config.RoutingConfig `optional:"true"` // Supplies the ETA speed of Haversine estimates
+	LargeGraph *LargeGraphDelegate   `optional:"true"`
+	Logger     *slog.Logger
+}
+
+// LargeGraphDelegate routes the queries whose PMTiles graph would be too large to search quickly.
+// A query is delegated once its merged graph passes EdgeThreshold edges, or when its area exceeds
+// the tile span or memory budget that would otherwise send it to the Haversine fallback.
+type LargeGraphDelegate struct {
+	Router        usecase.RoutingUsecase // Backend for large queries, typically the CH engine
+	EdgeThreshold int                    // Merged graph edge count above which a query is delegated
+
+	// Router profile for each PMTiles profile; a profile without an entry uses the router's default
+	Profiles map[string]string
 }
 
 // NewPMTilesRoutingService creates a new PMTiles-based routing service
 func NewPMTilesRoutingService(params PMTilesServiceParams) (usecase.RoutingUsecase, error) {
-	cfg := params.Config
+	cfg := params.Config.WithDefaults()
 	logger := params.Logger
 
-	if cfg == nil || !cfg.Enabled {
-		logger.Info("PMTiles routing disabled, using Haversine fallback")
-
-		return newHaversineFallbackService(logger), nil
+	speedKmh := params.Routing.WithDefaults().DefaultSpeedKmh
+	if err := validateEstimateSpeed(speedKmh); err != nil {
+		return nil, err
 	}
 
-	if cfg.Source == "" {
-		return nil, errors.New("PMTiles source is required when enabled")
-	}
+	if !cfg.Enabled {
+		logger.Info("PMTiles routing disabled, using Haversine fallback")
+		if cfg.DisableHaversineFallback {
+			logger.Warn("disableHaversineFallback is ignored while PMTiles routing is disabled")
+		}
 
-	roadLayer := cfg.RoadLayer
-	if roadLayer == "" {
-		roadLayer = defaultRoadLayerName // Default OpenMapTiles layer name
+		return newHaversineFallbackService(logger, speedKmh), nil
 	}
 
-	zoomLevel := cfg.ZoomLevel
-	if zoomLevel == 0 {
-		zoomLevel = 14 // Default zoom level for routing
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid PMTiles config: %w", err)
 	}
 
-	// Parse source to extract bucket URL, prefix (subdirectory), and tileset name
-	// The PMTiles server expects a bucket URL and optional prefix for subdirectories
-	bucketURL, prefix, tilesetName := parseSourcePath(cfg.Source)
+	if len(cfg.Profiles) == 0 {
+		return newTilesetService(&cfg, usecase.DefaultRoutingProfile, cfg.Source, params.LargeGraph, speedKmh, logger)
+	}
 
-	// Create a silent logger for pmtiles (it requires *log.Logger)
-	silentLogger := log.New(io.Discard, "", 0)
+	return newProfileRoutingService(&cfg, params.LargeGraph, speedKmh, logger)
+}
 
-	// Create PMTiles server - handles local files, HTTP, and cloud storage
-	// bucketURL is the bucket/directory, prefix is the subdirectory path
-	cacheSize := 64 // Cache up to 64 tiles in memory
-	server, err := pmtiles.NewServer(bucketURL, prefix, silentLogger, cacheSize, "")
+// newTilesetService creates the routing service for the PMTiles archive at source, serving profile
+func newTilesetService(
+	cfg *config.PMTilesConfig,
+	profile string,
+	source string,
+	largeGraph *LargeGraphDelegate,
+	estimateSpeedKmh float64,
+	logger *slog.Logger,
+) (*pmtilesRoutingService, error) {
+	archive, err := openTileArchive(source, cfg.CacheSize)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create PMTiles server: %w", err)
+		return nil, err
 	}
 
-	// Start the server (required for serving tiles)
-	server.Start()
+	var largeGraphRouter usecase.RoutingUsecase
+	var largeGraphEdgeThreshold int
+	largeGraphProfile := usecase.DefaultRoutingProfile
+	if largeGraph != nil && largeGraph.Router != nil {
+		largeGraphRouter = largeGraph.Router
+		largeGraphEdgeThreshold = largeGraph.EdgeThreshold
+		largeGraphProfile = largeGraph.Profiles[profile]
+	}
 
 	svc := &pmtilesRoutingService{
-		source:      cfg.Source,
-		tilesetName: tilesetName,
-		roadLayer:   roadLayer,
-		zoomLevel:   zoomLevel,
-		logger:      logger,
-		server:      server,
-		parser:      NewMVTParser(roadLayer),
-		tileCache:   make(map[string]*RoadGraph),
+		roadLayer:           cfg.RoadLayer,
+		zoomLevel:           cfg.ZoomLevel,
+		logger:              logger,
+		archive:             archive,
+		cacheSize:           cfg.CacheSize,
+		parser:              NewMVTParser(cfg.RoadLayer, WithExcludeRestrictedAccess(cfg.ExcludeRestrictedAccess)),
+		maxGraphMemoryBytes: cfg.MaxGraphMemoryBytes,
+		tileCache:           make(map[string]*RoadGraph),
+
+		disableHaversineFallback: cfg.DisableHaversineFallback,
+		includeEdgeSnap:          cfg.IncludeEdgeSnap,
+		minEdgeDistance:          *cfg.MinEdgeDistanceMeters,
+		maxSnapDistance:          cfg.MaxSnapDistanceMeters,
+		maxTileSpan:              cfg.MaxTileSpan,
+		corridorTiles:            cfg.CorridorTiles,
+		corridorBufferTiles:      *cfg.CorridorBufferTiles,
+		compactNodeCoordinates:   cfg.CompactNodeCoordinates,
+		tileCacheMaxAge:          cfg.TileCacheMaxAge,
+		zoomFallback:             cfg.ZoomFallback,
+		largeGraphRouter:         largeGraphRouter,
+		largeGraphEdgeThreshold:  largeGraphEdgeThreshold,
+		largeGraphProfile:        largeGraphProfile,
+		estimateSpeedKmh:         estimateSpeedKmh,
+		now:                      time.Now,
+		routingCost: CostWeights{
+			DurationWeight:     cfg.RoutingCost.DurationWeight,
+			TurnPenaltySeconds: cfg.RoutingCost.TurnPenaltySeconds,
+			RoadClassWeight:    cfg.RoutingCost.RoadClassWeight,
+		},
 	}
 
+	svc.sourceVersion = svc.archiveVersion
+	svc.archiveMaxZoom = svc.readArchiveMaxZoom
+	svc.probeTile = svc.fetchCenterTile
+
 	logger.Info("PMTiles routing service initialized",
-		slog.String("source", cfg.Source),
-		slog.String("tileset", tilesetName),
-		slog.String("road_layer", roadLayer),
-		slog.Int("zoom_level", zoomLevel),
+		slog.String("source", source),
+		slog.String("tileset", archive.tilesetName),
+		slog.String("road_layer", cfg.RoadLayer),
+		slog.Int("zoom_level", cfg.ZoomLevel),
+		slog.Int("cache_size", cfg.CacheSize),
+		slog.Int64("max_graph_memory_bytes", svc.maxGraphMemoryBytes),
+		slog.Bool("haversine_fallback", !svc.disableHaversineFallback),
+		slog.Float64("min_edge_distance_m", svc.minEdgeDistance),
+		slog.Float64("max_snap_distance_m", svc.maxSnapDistance),
+		slog.Int("max_tile_span", svc.maxTileSpan),
+		slog.Bool("corridor_tiles", svc.corridorTiles),
+		slog.Bool("compact_node_coordinates", svc.compactNodeCoordinates),
+		slog.Bool("composite_routing_cost", svc.routingCost.Enabled()),
+		slog.Duration("tile_cache_max_age", svc.tileCacheMaxAge),
+		slog.Bool("zoom_fallback", svc.zoomFallback),
+		slog.Bool("large_graph_delegation", svc.largeGraphRouter != nil),
+		slog.Int("large_graph_edge_threshold", svc.largeGraphEdgeThreshold),
+		slog.String("large_graph_profile", svc.largeGraphProfile),
 	)
 
 	return svc, nil
 }
 
+// currentArchive returns the archive queries read tiles from
+func (s *pmtilesRoutingService) currentArchive() *tileArchive {
+	s.tileCacheMu.RLock()
+	defer s.tileCacheMu.RUnlock()
+
+	return s.archive
+}
+
+// Reload switches the service to the PMTiles archive at newSource without a restart. The new archive's
+// header is read before the swap, so a source that cannot be read leaves the current one serving.
+// The swap clears the tile cache; requests already reading tiles finish against the old archive, and the
+// graphs they build are not cached. A source in the same bucket and prefix keeps the current server.
+// go-pmtiles servers cannot be stopped, so each switch to another location leaves the old server and
+// its directory cache idle in memory until the process exits.
+func (s *pmtilesRoutingService) Reload(ctx context.Context, newSource string) error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	archive, err := s.currentArchive().reopen(newSource, s.cacheSize)
+	if err != nil {
+		return err
+	}
+	if _, err := archive.readHeader(ctx); err != nil {
+		return fmt.Errorf("reload PMTiles source: %w", err)
+	}
+
+	// The archive may be a different extract, so its version and max zoom are read again
+	s.tileCacheMu.Lock()
+	previous := s.archive
+	cachedTiles := len(s.tileCache)
+	s.archive = archive
+	s.tileCache = make(map[string]*RoadGraph)
+	s.tileCacheVersion = ""
+	s.tileCacheCheckedAt = time.Time{}
+	s.maxZoomKnown = false
+	s.tileCacheMu.Unlock()
+
+	s.logger.Info("PMTiles source reloaded",
+		slog.String("previous_source", redactSourceQuery(previous.source)),
+		slog.String("source", redactSourceQuery(newSource)),
+		slog.String("tileset", archive.tilesetName),
+		slog.Int("cleared_tiles", cachedTiles),
+	)
+
+	return nil
+}
+
 // tileKey creates a string key for a tile
 func tileKey(tile maptile.Tile) string {
 	return fmt.Sprintf("%d/%d/%d", tile.Z, tile.X, tile.Y)
 }
 
 // parseSourcePath extracts the bucket URL, prefix (subdirectory), and tileset name from a source path.
-// Supports: file://, gs://, http://, https://, and local file paths.
+// Supports: file://, gs://, s3://, azblob://, http://, https://, and local file paths.
 //
-// For cloud storage (gs://), the prefix is used to support subdirectories since
+// For cloud storage, the prefix is used to support subdirectories since
 // gocloud.dev only uses the Host as bucket name and ignores the Path.
+// Query parameters such as ?region= stay on the bucket URL for the blob driver.
 //
 // Returns:
 //   - bucketURL: The base bucket URL (e.g., "gs://my-bucket", "file:///path/to")
@@ -151,6 +417,7 @@ func tileKey(tile maptile.Tile) string {
 //   - "gs://my-bucket/walking.pmtiles" -> ("gs://my-bucket", "", "walking")
 //   - "gs://my-bucket/subdir/walking.pmtiles" -> ("gs://my-bucket", "subdir", "walking")
 //   - "gs://my-bucket/path/to/tiles/walking.pmtiles" -> ("gs://my-bucket", "path/to/tiles", "walking")
+//   - "s3://my-bucket/tiles/walking.pmtiles?region=ap-northeast-1" -> ("s3://my-bucket?region=ap-northeast-1", "tiles", "walking")
 func parseSourcePath(source string) (bucketURL, prefix, tilesetName string) {
 	// Handle local file path without scheme (e.g., "/path/to/file.pmtiles")
 	source = normalizeSourceToFileURL(source)
@@ -206,6 +473,9 @@ func parseCloudStoragePath(parsedURL *url.URL, dirPath, tilesetName string) (buc
 	// For cloud storage, the bucket is just scheme://host
 	// Any path becomes the prefix
 	bucketURL = parsedURL.Scheme + "://" + parsedURL.Host
+	if parsedURL.RawQuery != "" {
+		bucketURL += "?" + parsedURL.RawQuery
+	}
 
 	// dirPath is the prefix (subdirectory)
 	// Clean up: remove leading slash, handle root case
@@ -244,7 +514,7 @@ func parseStandardURLPath(parsedURL *url.URL, dirPath, tilesetName string) (buck
 }
 
 // OneToMany calculates routes from one source to multiple targets
-func (s *pmtilesRoutingService) OneToMany(ctx context.Context, source usecase.Coordinate, targets []usecase.Coordinate) (*usecase.OneToManyResult, error) {
+func (s *pmtilesRoutingService) OneToMany(ctx context.Context, _ string, source usecase.Coordinate, targets []usecase.Coordinate) (*usecase.OneToManyResult, error) {
 	startTime := time.Now()
 
 	if len(targets) == 0 {
@@ -257,14 +527,63 @@ func (s *pmtilesRoutingService) OneToMany(ctx context.Context, source usecase.Co
 	}
 
 	// Build road graph for the area covering source and all targets
-	graph := s.buildGraphForArea(ctx, source, targets)
+	graph, err := s.buildGraphForArea(ctx, source, targets)
+	if err != nil {
+		if result, ok := s.delegateOneToMany(ctx, err, source, targets); ok {
+			return result, nil
+		}
+
+		return s.haversineFallback(source, targets, startTime)
+	}
+
+	return s.oneToManyOnGraph(graph, source, targets, s.snapLimit(ctx), startTime)
+}
 
+// ManyToMany builds one road graph covering every source and target and routes each source on it.
+// When that graph cannot be built, each source falls back to its own OneToMany query.
+func (s *pmtilesRoutingService) ManyToMany(ctx context.Context, profile string, sources, targets []usecase.Coordinate) ([]*usecase.OneToManyResult, error) {
+	results := make([]*usecase.OneToManyResult, len(sources))
+	if len(sources) == 0 {
+		return results, nil
+	}
+
+	if len(targets) == 0 {
+		return usecase.RouteEachSource(ctx, s, profile, sources, targets)
+	}
+
+	startTime := time.Now()
+	graph, err := s.buildGraphForArea(ctx, sources[0], append(slices.Clone(sources[1:]), targets...))
+	if err != nil {
+		return usecase.RouteEachSource(ctx, s, profile, sources, targets)
+	}
+
+	maxSnap := s.snapLimit(ctx)
+	for idx, source := range sources {
+		result, err := s.oneToManyOnGraph(graph, source, targets, maxSnap, startTime)
+		if err != nil {
+			return nil, err
+		}
+		results[idx] = result
+	}
+
+	return results, nil
+}
+
+// oneToManyOnGraph routes source to targets on an already built graph, snapping points within maxSnap meters
+func (s *pmtilesRoutingService) oneToManyOnGraph(
+	graph *RoadGraph,
+	source usecase.Coordinate,
+	targets []usecase.Coordinate,
+	maxSnap float64,
+	startTime time.Time,
+) (*usecase.OneToManyResult, error) {
 	// Find nearest nodes
 	sourcePoint := orb.Point{source.Lng, source.Lat}
 	sourceNodeID, sourceSnapDist, found := graph.FindNearestNode(sourcePoint)
-	if !found || sourceSnapDist > 500 { // Max 500m snap distance
+	if !found || sourceSnapDist > maxSnap {
 		s.logger.Debug("Source too far from road network, using Haversine fallback",
 			slog.Float64("snap_distance", sourceSnapDist),
+			slog.Float64("max_snap_distance", maxSnap),
 		)
 
 		return s.haversineFallback(source, targets, startTime)
@@ -276,14 +595,14 @@ func (s *pmtilesRoutingService) OneToMany(ctx context.Context, source usecase.Co
 	for i, target := range targets {
 		targetPoint := orb.Point{target.Lng, target.Lat}
 		nodeID, snapDist, ok := graph.FindNearestNode(targetPoint)
-		if ok && snapDist <= 500 {
+		if ok && snapDist <= maxSnap {
 			targetNodeIDs[i] = nodeID
 			targetSnapDistances[i] = snapDist
 		}
 	}
 
 	// Run pathfinding
-	pathfinder := NewPathfinder(graph)
+	pathfinder := NewPathfinderWithCost(graph, s.routingCost)
 	pathResults := pathfinder.ShortestPathToMany(sourceNodeID, targetNodeIDs)
 
 	// Convert results
@@ -307,7 +626,7 @@ func (s *pmtilesRoutingService) OneToMany(ctx context.Context, source usecase.Co
 		}
 
 		// Fallback to Haversine for unreachable targets
-		results[idx] = s.haversineResult(source, target)
+		results[idx] = s.fallbackResult(source, target)
 	}
 
 	return &usecase.OneToManyResult{
@@ -318,28 +637,102 @@ func (s *pmtilesRoutingService) OneToMany(ctx context.Context, source usecase.Co
 	}, nil
 }
 
+// snapLimit returns the maximum snap distance for a call: the RoutingOptions override on ctx when set,
+// and otherwise the configured value
+func (s *pmtilesRoutingService) snapLimit(ctx context.Context) float64 {
+	if override := usecase.RoutingOptionsFromContext(ctx).MaxSnapDistanceMeters; override > 0 {
+		return override
+	}
+	if s.maxSnapDistance > 0 {
+		return s.maxSnapDistance
+	}
+
+	return defaultMaxSnapDistance
+}
+
 // FindNearestNode finds the nearest road network node to a coordinate
-func (s *pmtilesRoutingService) FindNearestNode(ctx context.Context, coord usecase.Coordinate) (*usecase.NodeInfo, bool, error) {
+func (s *pmtilesRoutingService) FindNearestNode(ctx context.Context, _ string, coord usecase.Coordinate) (*usecase.NodeInfo, bool, error) {
 	// Build a small graph around the coordinate
 	graph := s.buildGraphForPoint(ctx, coord)
 
 	point := orb.Point{coord.Lng, coord.Lat}
 	nodeID, snapDist, found := graph.FindNearestNode(point)
-	if !found || snapDist > 500 {
+	if !found || snapDist > s.snapLimit(ctx) {
 		return nil, false, nil
 	}
 
-	nodePoint := graph.Nodes[nodeID]
+	node := s.nodeInfo(graph, nodeID, point)
 
-	return &usecase.NodeInfo{
+	return &node, true, nil
+}
+
+// nodeInfo converts a graph node into a NodeInfo, adding the nearest edge projection when enabled
+func (s *pmtilesRoutingService) nodeInfo(graph *RoadGraph, nodeID NodeID, point orb.Point) usecase.NodeInfo {
+	nodePoint, _ := graph.node(nodeID)
+	node := usecase.NodeInfo{
 		ID:       usecase.NodeID(nodeID),
 		Location: usecase.Coordinate{Lat: nodePoint[1], Lng: nodePoint[0]},
-	}, true, nil
+	}
+
+	if s.includeEdgeSnap {
+		if projection, ok := graph.FindNearestEdge(point); ok {
+			node.Edge = &usecase.EdgeSnap{
+				FromID:    usecase.NodeID(projection.From),
+				ToID:      usecase.NodeID(projection.To),
+				Projected: usecase.Coordinate{Lat: projection.Point[1], Lng: projection.Point[0]},
+				Offset:    projection.Offset,
+				DistanceM: projection.Distance,
+			}
+		}
+	}
+
+	return node
+}
+
+// SnapBatch snaps multiple coordinates to their nearest road network nodes using a single
+// graph built from the union of each coordinate's surrounding tiles
+func (s *pmtilesRoutingService) SnapBatch(ctx context.Context, profile string, coords []usecase.Coordinate) ([]usecase.NodeInfo, []bool, error) {
+	nodes := make([]usecase.NodeInfo, len(coords))
+	found := make([]bool, len(coords))
+	if len(coords) == 0 {
+		return nodes, found, nil
+	}
+
+	graph, withinBudget := s.buildGraphForPoints(ctx, coords)
+	if !withinBudget {
+		// Fall back to per-point graphs, which stay small regardless of how spread out the batch is
+		for i, coord := range coords {
+			node, ok, err := s.FindNearestNode(ctx, profile, coord)
+			if err != nil {
+				return nil, nil, err
+			}
+			if ok {
+				nodes[i] = *node
+				found[i] = true
+			}
+		}
+
+		return nodes, found, nil
+	}
+
+	maxSnap := s.snapLimit(ctx)
+	for i, coord := range coords {
+		point := orb.Point{coord.Lng, coord.Lat}
+		nodeID, snapDist, ok := graph.FindNearestNode(point)
+		if !ok || snapDist > maxSnap {
+			continue
+		}
+
+		nodes[i] = s.nodeInfo(graph, nodeID, point)
+		found[i] = true
+	}
+
+	return nodes, found, nil
 }
 
 // CalculateDistance calculates road distance between two coordinates
-func (s *pmtilesRoutingService) CalculateDistance(ctx context.Context, source, target usecase.Coordinate) (*usecase.RouteResult, error) {
-	result, err := s.OneToMany(ctx, source, []usecase.Coordinate{target})
+func (s *pmtilesRoutingService) CalculateDistance(ctx context.Context, profile string, source, target usecase.Coordinate) (*usecase.RouteResult, error) {
+	result, err := s.OneToMany(ctx, profile, source, []usecase.Coordinate{target})
 	if err != nil {
 		return nil, err
 	}
@@ -349,18 +742,303 @@ func (s *pmtilesRoutingService) CalculateDistance(ctx context.Context, source, t
 	}
 
 	// Return Haversine fallback
-	hr := s.haversineResult(source, target)
+	hr := s.fallbackResult(source, target)
 
 	return &hr, nil
 }
 
-// IsReady returns whether the service is ready
+// CalculateRoute calculates the road route between two coordinates and traces its geometry.
+// When no road route exists it returns the same fallback as CalculateDistance, drawn as a straight line.
+func (s *pmtilesRoutingService) CalculateRoute(ctx context.Context, _ string, source, target usecase.Coordinate) (*usecase.RouteResult, error) {
+	graph, err := s.buildGraphForArea(ctx, source, []usecase.Coordinate{target})
+	if err != nil {
+		if result, ok := s.delegateRoute(ctx, err, source, target); ok {
+			return result, nil
+		}
+
+		return s.fallbackRoute(source, target), nil
+	}
+
+	sourcePoint := orb.Point{source.Lng, source.Lat}
+	targetPoint := orb.Point{target.Lng, target.Lat}
+	sourceNodeID, sourceSnapDist, sourceFound := graph.FindNearestNode(sourcePoint)
+	targetNodeID, targetSnapDist, targetFound := graph.FindNearestNode(targetPoint)
+	maxSnap := s.snapLimit(ctx)
+	if !sourceFound || sourceSnapDist > maxSnap || !targetFound || targetSnapDist > maxSnap {
+		return s.fallbackRoute(source, target), nil
+	}
+
+	pathResult := NewPathfinderWithCost(graph, s.routingCost).ShortestPath(sourceNodeID, targetNodeID)
+	if !pathResult.IsReachable {
+		return s.fallbackRoute(source, target), nil
+	}
+
+	// The route starts and ends at the requested coordinates, joined to the snapped nodes
+	geometry := make([]usecase.Coordinate, 0, len(pathResult.Geometry)+2)
+	geometry = append(geometry, source)
+	for _, point := range pathResult.Geometry {
+		geometry = append(geometry, usecase.Coordinate{Lat: point[1], Lng: point[0]})
+	}
+	geometry = append(geometry, target)
+
+	return &usecase.RouteResult{
+		Source:      source,
+		Target:      target,
+		DistanceKm:  (pathResult.Distance + sourceSnapDist + targetSnapDist) / 1000,
+		DurationMin: pathResult.Duration / 60,
+		IsReachable: true,
+		Geometry:    geometry,
+	}, nil
+}
+
+// fallbackRoute returns the fallback result for a route, with a straight line when it is a reachable estimate
+func (s *pmtilesRoutingService) fallbackRoute(source, target usecase.Coordinate) *usecase.RouteResult {
+	result := s.fallbackResult(source, target)
+	if result.IsReachable {
+		result.Geometry = []usecase.Coordinate{source, target}
+	}
+
+	return &result
+}
+
+// NearestRoadName returns the name of the closest named road segment in the tiles around coord.
+// Segments are parsed from the tiles on each call, as the cached tile graphs do not keep road names.
+func (s *pmtilesRoutingService) NearestRoadName(ctx context.Context, coord usecase.Coordinate) (string, bool, error) {
+	point := orb.Point{coord.Lng, coord.Lat}
+	limit := s.snapLimit(ctx)
+	parsed := make(map[string]struct{})
+
+	name, nearest := "", math.MaxFloat64
+	var readErr error
+	for _, t := range s.neighborhoodTiles(coord) {
+		segments, err := s.tileSegments(ctx, t, parsed)
+		if errors.Is(err, errTileNotFound) {
+			continue
+		}
+		if err != nil {
+			readErr = err
+
+			continue
+		}
+
+		for idx := range segments {
+			if segments[idx].Name == "" {
+				continue
+			}
+			if dist := segments[idx].DistanceTo(point); dist < nearest {
+				name, nearest = segments[idx].Name, dist
+			}
+		}
+	}
+
+	// When no tile could be read, report the failure rather than a miss
+	if len(parsed) == 0 && readErr != nil {
+		return "", false, readErr
+	}
+	if name == "" || nearest > limit {
+		return "", false, nil
+	}
+
+	return name, true, nil
+}
+
+// tileSegments parses the road segments of a tile, or of its ancestor when the archive stops at a
+// lower zoom. It returns no segments for a tile already in parsed, so an ancestor is read only once.
+func (s *pmtilesRoutingService) tileSegments(ctx context.Context, tile maptile.Tile, parsed map[string]struct{}) ([]RoadSegment, error) {
+	data, err := s.fetchTile(ctx, tile)
+	if errors.Is(err, errTileNotFound) {
+		if ancestor, ok := s.fallbackTile(ctx, tile); ok {
+			if _, seen := parsed[tileKey(ancestor)]; seen {
+				return nil, nil
+			}
+			tile = ancestor
+			data, err = s.fetchTile(ctx, tile)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	parsed[tileKey(tile)] = struct{}{}
+
+	return s.parser.ParseTile(data, tile)
+}
+
+// IsReady reports whether the archive has served at least one tile.
+// Until it has, each call starts a background probe of the archive's center tile, one at a time,
+// so readiness checks bring the service up without waiting for routing traffic.
 func (s *pmtilesRoutingService) IsReady() bool {
-	return s.server != nil
+	if archive := s.currentArchive(); archive == nil || archive.server == nil {
+		return false
+	}
+	if s.tileFetched.Load() {
+		return true
+	}
+
+	if s.probeTile != nil && s.probing.CompareAndSwap(false, true) {
+		go s.runReadinessProbe()
+	}
+
+	return false
+}
+
+// runReadinessProbe fetches one tile so the service can report ready
+func (s *pmtilesRoutingService) runReadinessProbe() {
+	defer s.probing.Store(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), readinessProbeTimeout)
+	defer cancel()
+
+	if err := s.probeTile(ctx); err != nil {
+		s.logger.Warn("PMTiles readiness probe failed", slog.String("error", err.Error()))
+	}
+}
+
+// Metadata reports the PMTiles archive and layer the road graph is built from
+func (s *pmtilesRoutingService) Metadata() usecase.RoutingMetadata {
+	archive := s.currentArchive()
+
+	return usecase.RoutingMetadata{
+		Backend:   config.RoutingBackendPMTiles,
+		Ready:     s.IsReady(),
+		Source:    redactSourceQuery(archive.source),
+		Tileset:   archive.tilesetName,
+		RoadLayer: s.roadLayer,
+		ZoomLevel: s.zoomLevel,
+	}
+}
+
+// redactSourceQuery drops the query string and user info from a source URL, since they can carry
+// credentials such as SAS tokens. Local paths are returned unchanged.
+func redactSourceQuery(source string) string {
+	parsedURL, err := url.Parse(source)
+	if err != nil || parsedURL.Scheme == "" {
+		return source
+	}
+
+	parsedURL.RawQuery = ""
+	parsedURL.User = nil
+
+	return parsedURL.String()
+}
+
+// delegateOneToMany hands a query whose graph was too large to build to the large graph router,
+// on the router profile resolved for this tileset.
+// It returns false when delegation is off, the failure was not about size, or the router failed.
+func (s *pmtilesRoutingService) delegateOneToMany(
+	ctx context.Context,
+	buildErr error,
+	source usecase.Coordinate,
+	targets []usecase.Coordinate,
+) (*usecase.OneToManyResult, bool) {
+	if !s.shouldDelegate(buildErr) {
+		return nil, false
+	}
+
+	result, err := s.largeGraphRouter.OneToMany(ctx, s.largeGraphProfile, source, targets)
+	if err != nil {
+		s.logger.Warn("Large graph router failed, using Haversine fallback", slog.String("error", err.Error()))
+
+		return nil, false
+	}
+
+	return result, true
+}
+
+// delegateRoute is delegateOneToMany for a single traced route
+func (s *pmtilesRoutingService) delegateRoute(
+	ctx context.Context,
+	buildErr error,
+	source, target usecase.Coordinate,
+) (*usecase.RouteResult, bool) {
+	if !s.shouldDelegate(buildErr) {
+		return nil, false
+	}
+
+	result, err := s.largeGraphRouter.CalculateRoute(ctx, s.largeGraphProfile, source, target)
+	if err != nil {
+		s.logger.Warn("Large graph router failed, using Haversine fallback", slog.String("error", err.Error()))
+
+		return nil, false
+	}
+
+	return result, true
+}
+
+// shouldDelegate reports whether a graph build failure is one the large graph router should answer
+func (s *pmtilesRoutingService) shouldDelegate(buildErr error) bool {
+	if s.largeGraphRouter == nil {
+		return false
+	}
+	if !errors.Is(buildErr, errLargeGraph) && !errors.Is(buildErr, errTileSpanExceeded) &&
+		!errors.Is(buildErr, errGraphMemoryExceeded) {
+		return false
+	}
+
+	s.logger.Debug("Delegating routing query to large graph router", slog.String("reason", buildErr.Error()))
+
+	return true
 }
 
-// buildGraphForArea builds a road graph covering the area between source and targets
-func (s *pmtilesRoutingService) buildGraphForArea(ctx context.Context, source usecase.Coordinate, targets []usecase.Coordinate) *RoadGraph {
+// buildGraphForArea builds a road graph covering the area between source and targets.
+// It fails when the area spans too many tiles, when the merged graph exceeds the configured memory budget,
+// or, with a large graph router, once the merged graph passes the large graph edge threshold.
+func (s *pmtilesRoutingService) buildGraphForArea(ctx context.Context, source usecase.Coordinate, targets []usecase.Coordinate) (*RoadGraph, error) {
+	tiles, err := s.areaTiles(source, targets)
+	if err != nil {
+		s.logger.Warn("Routing area rejected",
+			slog.String("error", err.Error()),
+		)
+
+		return nil, err
+	}
+
+	// Build combined graph
+	graph := s.newRoadGraph()
+	merged := make(map[*RoadGraph]struct{}, len(tiles))
+
+	for _, tile := range tiles {
+		tileGraph, err := s.loadTileGraph(ctx, tile)
+		if err != nil {
+			s.logger.Debug("Failed to load tile",
+				slog.String("tile", tileKey(tile)),
+				slog.String("error", err.Error()),
+			)
+
+			continue
+		}
+		mergeTileGraphOnce(graph, tileGraph, merged)
+
+		if s.exceedsGraphMemoryBudget(graph) {
+			s.logger.Warn("Merged graph memory budget exceeded",
+				slog.Int("tiles_requested", len(tiles)),
+				slog.Int("nodes", graph.nodeCount()),
+				slog.Int64("estimated_bytes", estimateGraphMemoryBytes(graph)),
+				slog.Int64("budget_bytes", s.maxGraphMemoryBytes),
+			)
+
+			return nil, errGraphMemoryExceeded
+		}
+		if s.exceedsLargeGraphThreshold(graph) {
+			s.logger.Debug("Merged graph passed the large graph edge threshold",
+				slog.Int("tiles_requested", len(tiles)),
+				slog.Int("edges", graph.edgeCount()),
+				slog.Int("threshold", s.largeGraphEdgeThreshold),
+			)
+
+			return nil, errLargeGraph
+		}
+	}
+
+	return graph, nil
+}
+
+// areaTiles returns the tiles of the routing area between source and targets: their padded bounding box,
+// or with corridor tiles only the tiles along each source-target line
+func (s *pmtilesRoutingService) areaTiles(source usecase.Coordinate, targets []usecase.Coordinate) ([]maptile.Tile, error) {
+	if s.corridorTiles {
+		return getTilesForCorridor(source, targets, maptile.Zoom(s.zoomLevel), s.corridorBufferTiles, s.maxTileSpan)
+	}
+
 	// Calculate bounding box
 	minLat, maxLat := source.Lat, source.Lat
 	minLng, maxLng := source.Lng, source.Lng
@@ -387,35 +1065,111 @@ func (s *pmtilesRoutingService) buildGraphForArea(ctx context.Context, source us
 	minLng -= padding
 	maxLng += padding
 
-	// Get required tiles
-	tiles := getTilesForBounds(minLat, maxLat, minLng, maxLng, maptile.Zoom(s.zoomLevel))
+	return getTilesForBounds(minLat, maxLat, minLng, maxLng, maptile.Zoom(s.zoomLevel), s.maxTileSpan)
+}
 
-	// Build combined graph
-	graph := NewRoadGraph()
+// exceedsLargeGraphThreshold reports whether the graph has grown past the size the large graph router takes over.
+// The remaining tiles are not loaded once it has, since the query will not be routed on this graph.
+func (s *pmtilesRoutingService) exceedsLargeGraphThreshold(graph *RoadGraph) bool {
+	if s.largeGraphRouter == nil || s.largeGraphEdgeThreshold <= 0 {
+		return false
+	}
 
-	for _, tile := range tiles {
-		tileGraph, err := s.loadTileGraph(ctx, tile)
-		if err != nil {
-			s.logger.Debug("Failed to load tile",
-				slog.String("tile", tileKey(tile)),
-				slog.String("error", err.Error()),
-			)
+	return graph.edgeCount() > s.largeGraphEdgeThreshold
+}
+
+// exceedsGraphMemoryBudget reports whether the graph is over the configured memory budget
+func (s *pmtilesRoutingService) exceedsGraphMemoryBudget(graph *RoadGraph) bool {
+	if s.maxGraphMemoryBytes <= 0 {
+		return false
+	}
 
+	return estimateGraphMemoryBytes(graph) > s.maxGraphMemoryBytes
+}
+
+// newRoadGraph creates an empty graph at the configured coordinate precision
+func (s *pmtilesRoutingService) newRoadGraph() *RoadGraph {
+	if s.compactNodeCoordinates {
+		return NewCompactRoadGraph()
+	}
+
+	return NewRoadGraph()
+}
+
+// estimateGraphMemoryBytes approximates the heap footprint of a graph from its node and edge counts
+func estimateGraphMemoryBytes(graph *RoadGraph) int64 {
+	nodeBytes := int64(approxNodeBytes)
+	if graph.compactNodes != nil {
+		nodeBytes = approxCompactNodeBytes
+	}
+
+	return int64(graph.nodeCount())*nodeBytes + int64(graph.edgeCount())*approxEdgeBytes
+}
+
+// buildGraphForPoint builds a road graph around a single point
+func (s *pmtilesRoutingService) buildGraphForPoint(ctx context.Context, coord usecase.Coordinate) *RoadGraph {
+	graph := s.newRoadGraph()
+	merged := make(map[*RoadGraph]struct{})
+
+	for _, t := range s.neighborhoodTiles(coord) {
+		tileGraph, err := s.loadTileGraph(ctx, t)
+		if err != nil {
 			continue
 		}
-		mergeGraphs(graph, tileGraph)
+		mergeTileGraphOnce(graph, tileGraph, merged)
 	}
 
 	return graph
 }
 
-// buildGraphForPoint builds a road graph around a single point
-func (s *pmtilesRoutingService) buildGraphForPoint(ctx context.Context, coord usecase.Coordinate) *RoadGraph {
+// buildGraphForPoints builds one road graph covering the tile neighborhood of every point,
+// loading each distinct tile only once.
+// It returns false when the merged graph exceeds the configured memory budget.
+func (s *pmtilesRoutingService) buildGraphForPoints(ctx context.Context, coords []usecase.Coordinate) (*RoadGraph, bool) {
+	seen := make(map[string]struct{})
+	tiles := make([]maptile.Tile, 0, len(coords)*9)
+	for _, coord := range coords {
+		for _, t := range s.neighborhoodTiles(coord) {
+			key := tileKey(t)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			tiles = append(tiles, t)
+		}
+	}
+
+	graph := s.newRoadGraph()
+	merged := make(map[*RoadGraph]struct{}, len(tiles))
+
+	for _, t := range tiles {
+		tileGraph, err := s.loadTileGraph(ctx, t)
+		if err != nil {
+			continue
+		}
+		mergeTileGraphOnce(graph, tileGraph, merged)
+
+		if s.exceedsGraphMemoryBudget(graph) {
+			s.logger.Warn("Merged snap graph memory budget exceeded, snapping points individually",
+				slog.Int("points", len(coords)),
+				slog.Int("tiles_requested", len(tiles)),
+				slog.Int64("budget_bytes", s.maxGraphMemoryBytes),
+			)
+
+			return nil, false
+		}
+	}
+
+	return graph, true
+}
+
+// neighborhoodTiles returns the tile containing the point and its 8 surrounding tiles
+func (s *pmtilesRoutingService) neighborhoodTiles(coord usecase.Coordinate) []maptile.Tile {
 	tile := maptile.At(orb.Point{coord.Lng, coord.Lat}, maptile.Zoom(s.zoomLevel))
 
 	// Load the center tile and all 8 surrounding tiles (3x3 grid)
 	// This ensures coverage when the point is near a tile corner
-	tiles := []maptile.Tile{
+	return []maptile.Tile{
 		// Center
 		tile,
 		// Cardinal directions (up, down, left, right)
@@ -429,26 +1183,17 @@ func (s *pmtilesRoutingService) buildGraphForPoint(ctx context.Context, coord us
 		{X: tile.X - 1, Y: tile.Y + 1, Z: tile.Z},
 		{X: tile.X + 1, Y: tile.Y + 1, Z: tile.Z},
 	}
-
-	graph := NewRoadGraph()
-
-	for _, t := range tiles {
-		tileGraph, err := s.loadTileGraph(ctx, t)
-		if err != nil {
-			continue
-		}
-		mergeGraphs(graph, tileGraph)
-	}
-
-	return graph
 }
 
 // loadTileGraph loads and parses a single tile into a road graph
 func (s *pmtilesRoutingService) loadTileGraph(ctx context.Context, tile maptile.Tile) (*RoadGraph, error) {
+	s.revalidateTileCache(ctx)
+
 	cacheKey := tileKey(tile)
 
-	// Check cache
+	// Check cache; a miss is loaded from the archive the cache belongs to
 	s.tileCacheMu.RLock()
+	archive := s.archive
 	if graph, ok := s.tileCache[cacheKey]; ok {
 		s.tileCacheMu.RUnlock()
 
@@ -457,7 +1202,12 @@ func (s *pmtilesRoutingService) loadTileGraph(ctx context.Context, tile maptile.
 	s.tileCacheMu.RUnlock()
 
 	// Load tile data
-	data, err := s.fetchTile(ctx, tile)
+	data, err := s.fetchArchiveTile(ctx, archive, tile)
+	if errors.Is(err, errTileNotFound) {
+		if ancestor, ok := s.fallbackTile(ctx, tile); ok {
+			return s.loadFallbackTileGraph(ctx, archive, tile, ancestor)
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -469,53 +1219,299 @@ func (s *pmtilesRoutingService) loadTileGraph(ctx context.Context, tile maptile.
 	}
 
 	// Build graph
-	graph := NewRoadGraph()
+	graph := s.newRoadGraph()
+	graph.minEdgeDistance = s.minEdgeDistance
 	for idx := range segments {
 		graph.AddSegment(&segments[idx])
 	}
 
-	// Cache the graph
+	s.cacheTileGraph(archive, cacheKey, graph)
+
+	return graph, nil
+}
+
+// cacheTileGraph caches a graph built from archive. A graph finished after Reload swapped the archive
+// is returned to its request but not cached, so the new archive's cache holds only its own tiles.
+func (s *pmtilesRoutingService) cacheTileGraph(archive *tileArchive, key string, graph *RoadGraph) {
 	s.tileCacheMu.Lock()
-	s.tileCache[cacheKey] = graph
+	defer s.tileCacheMu.Unlock()
+
+	if s.archive == archive {
+		s.tileCache[key] = graph
+	}
+}
+
+// revalidateTileCache clears the cached tile graphs when the PMTiles source version has changed since they were built,
+// so a data refresh is not answered with stale graphs. It checks at most once per tileCacheMaxAge; when the
+// version cannot be read the cache is kept and the check is retried after another max age.
+func (s *pmtilesRoutingService) revalidateTileCache(ctx context.Context) {
+	if s.tileCacheMaxAge <= 0 || s.sourceVersion == nil {
+		return
+	}
+
+	now := s.now()
+	s.tileCacheMu.RLock()
+	due := s.tileCacheCheckedAt.IsZero() || now.Sub(s.tileCacheCheckedAt) >= s.tileCacheMaxAge
+	s.tileCacheMu.RUnlock()
+	if !due {
+		return
+	}
+
+	version, err := s.sourceVersion(ctx)
+
+	s.tileCacheMu.Lock()
+	defer s.tileCacheMu.Unlock()
+
+	// Another request may have revalidated while the version was being read
+	if !s.tileCacheCheckedAt.IsZero() && now.Sub(s.tileCacheCheckedAt) < s.tileCacheMaxAge {
+		return
+	}
+	s.tileCacheCheckedAt = now
+
+	if err != nil {
+		s.logger.Warn("Failed to check PMTiles source version, keeping cached tile graphs",
+			slog.String("error", err.Error()),
+		)
+
+		return
+	}
+
+	if s.tileCacheVersion != "" && version != s.tileCacheVersion {
+		s.logger.Info("PMTiles source version changed, clearing cached tile graphs",
+			slog.String("previous_version", s.tileCacheVersion),
+			slog.String("version", version),
+			slog.Int("cached_tiles", len(s.tileCache)),
+		)
+		s.tileCache = make(map[string]*RoadGraph)
+		s.maxZoomKnown = false
+	}
+	s.tileCacheVersion = version
+}
+
+// fallbackTile returns the ancestor of a missing tile at the archive's max zoom,
+// when zoom fallback is enabled and the archive was built only to a lower zoom.
+// A tile missing at or below the max zoom simply has no data, so it does not fall back.
+func (s *pmtilesRoutingService) fallbackTile(ctx context.Context, tile maptile.Tile) (maptile.Tile, bool) {
+	if !s.zoomFallback || s.archiveMaxZoom == nil {
+		return tile, false
+	}
+
+	maxZoom, err := s.cachedArchiveMaxZoom(ctx)
+	if err != nil {
+		s.logger.Debug("Failed to read PMTiles max zoom",
+			slog.String("error", err.Error()),
+		)
+
+		return tile, false
+	}
+	if tile.Z <= maxZoom {
+		return tile, false
+	}
+
+	for tile.Z > maxZoom {
+		tile = tile.Parent()
+	}
+
+	return tile, true
+}
+
+// cachedArchiveMaxZoom returns the archive's max zoom, reading the header on first use
+func (s *pmtilesRoutingService) cachedArchiveMaxZoom(ctx context.Context) (maptile.Zoom, error) {
+	s.tileCacheMu.RLock()
+	maxZoom, known, archive := s.maxZoom, s.maxZoomKnown, s.archive
+	s.tileCacheMu.RUnlock()
+	if known {
+		return maxZoom, nil
+	}
+
+	maxZoom, err := s.archiveMaxZoom(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// A reload while the header was read leaves the new archive's max zoom to be read again
+	s.tileCacheMu.Lock()
+	if s.archive == archive {
+		s.maxZoom, s.maxZoomKnown = maxZoom, true
+	}
 	s.tileCacheMu.Unlock()
 
+	return maxZoom, nil
+}
+
+// loadFallbackTileGraph loads the ancestor tile's graph and caches it under the missing tile's key too,
+// so later lookups of the missing tile do not fetch again
+func (s *pmtilesRoutingService) loadFallbackTileGraph(ctx context.Context, archive *tileArchive, tile, ancestor maptile.Tile) (*RoadGraph, error) {
+	graph, err := s.loadTileGraph(ctx, ancestor)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug("Tile missing at requested zoom, using lower-zoom ancestor",
+		slog.String("tile", tileKey(tile)),
+		slog.String("ancestor", tileKey(ancestor)),
+	)
+
+	s.cacheTileGraph(archive, tileKey(tile), graph)
+
 	return graph, nil
 }
 
-// fetchTile fetches tile data from PMTiles using HTTP Range requests
+// readArchiveMaxZoom reads the max zoom from the PMTiles archive header
+func (s *pmtilesRoutingService) readArchiveMaxZoom(ctx context.Context) (maptile.Zoom, error) {
+	header, err := s.readArchiveHeader(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return maptile.Zoom(header.MaxZoom), nil
+}
+
+// fetchCenterTile fetches the tile under the archive's center point at the routing zoom level,
+// or at the archive's max zoom when that is lower
+func (s *pmtilesRoutingService) fetchCenterTile(ctx context.Context) error {
+	header, err := s.readArchiveHeader(ctx)
+	if err != nil {
+		return err
+	}
+
+	center := orb.Point{float64(header.CenterLonE7) / 1e7, float64(header.CenterLatE7) / 1e7}
+	zoom := maptile.Zoom(min(s.zoomLevel, int(header.MaxZoom)))
+	if _, err := s.fetchTile(ctx, maptile.At(center, zoom)); err != nil {
+		return fmt.Errorf("fetch center tile: %w", err)
+	}
+
+	return nil
+}
+
+// readArchiveHeader reads the current archive's header
+func (s *pmtilesRoutingService) readArchiveHeader(ctx context.Context) (pmtiles.HeaderV3, error) {
+	return s.currentArchive().readHeader(ctx)
+}
+
+// readHeader reads the PMTiles archive header directly from its bucket
+func (a *tileArchive) readHeader(ctx context.Context) (pmtiles.HeaderV3, error) {
+	header, _, err := a.readVersionedHeader(ctx)
+
+	return header, err
+}
+
+// readVersionedHeader reads the PMTiles archive header along with the storage ETag of the archive object.
+// Buckets derive the ETag from the object itself (provider ETag, or size and mod time for files), so it
+// changes whenever the archive is replaced, even if the metadata JSON is not.
+func (a *tileArchive) readVersionedHeader(ctx context.Context) (pmtiles.HeaderV3, string, error) {
+	bucket, err := pmtiles.OpenBucket(ctx, a.bucketURL, a.bucketPrefix)
+	if err != nil {
+		return pmtiles.HeaderV3{}, "", fmt.Errorf("open PMTiles bucket: %w", err)
+	}
+	defer bucket.Close()
+
+	reader, etag, _, err := bucket.NewRangeReaderEtag(ctx, a.tilesetName+".pmtiles", 0, pmtiles.HeaderV3LenBytes, "")
+	if err != nil {
+		return pmtiles.HeaderV3{}, "", fmt.Errorf("read PMTiles header: %w", err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return pmtiles.HeaderV3{}, "", fmt.Errorf("read PMTiles header: %w", err)
+	}
+	if len(data) < pmtiles.HeaderV3LenBytes {
+		return pmtiles.HeaderV3{}, "", errors.New("read PMTiles header: archive too short")
+	}
+
+	header, err := pmtiles.DeserializeHeader(data)
+	if err != nil {
+		return pmtiles.HeaderV3{}, "", fmt.Errorf("parse PMTiles header: %w", err)
+	}
+
+	return header, etag, nil
+}
+
+// archiveVersion reports the version of the current archive used to invalidate cached tile graphs.
+// It is the archive object's ETag; storage that reports none falls back to a fingerprint of the header's
+// directory and tile data layout, which moves whenever the tiles are regenerated.
+func (s *pmtilesRoutingService) archiveVersion(ctx context.Context) (string, error) {
+	header, etag, err := s.currentArchive().readVersionedHeader(ctx)
+	if err != nil {
+		return "", err
+	}
+	if etag != "" {
+		return etag, nil
+	}
+
+	return fmt.Sprintf("layout:%d-%d/%d-%d/%d-%d/%d-%d/%d/%d/%d",
+		header.RootOffset, header.RootLength,
+		header.MetadataOffset, header.MetadataLength,
+		header.LeafDirectoryOffset, header.LeafDirectoryLength,
+		header.TileDataOffset, header.TileDataLength,
+		header.AddressedTilesCount, header.TileEntriesCount, header.TileContentsCount,
+	), nil
+}
+
+// fetchTile fetches tile data from the current archive
 func (s *pmtilesRoutingService) fetchTile(ctx context.Context, tile maptile.Tile) ([]byte, error) {
+	return s.fetchArchiveTile(ctx, s.currentArchive(), tile)
+}
+
+// fetchArchiveTile fetches tile data from PMTiles using HTTP Range requests
+func (s *pmtilesRoutingService) fetchArchiveTile(ctx context.Context, archive *tileArchive, tile maptile.Tile) ([]byte, error) {
 	// Build the tile path in the format expected by PMTiles server
 	// Format: /{tileset}/{z}/{x}/{y}.mvt
-	tilePath := fmt.Sprintf("/%s/%d/%d/%d.mvt", s.tilesetName, tile.Z, tile.X, tile.Y)
+	tilePath := fmt.Sprintf("/%s/%d/%d/%d.mvt", archive.tilesetName, tile.Z, tile.X, tile.Y)
 
 	// Get tile data using the PMTiles server
 	// The server handles HTTP Range requests internally for remote files
-	statusCode, _, data := s.server.Get(ctx, tilePath)
+	statusCode, _, data := archive.server.Get(ctx, tilePath)
 
 	if statusCode == http.StatusNotFound {
-		return nil, errors.New("tile not found")
+		return nil, errTileNotFound
 	}
 
 	if statusCode != http.StatusOK {
 		return nil, fmt.Errorf("unexpected status code: %d", statusCode)
 	}
+	s.tileFetched.Store(true)
 
 	return data, nil
 }
 
-// getTilesForBounds returns all tiles that cover the given bounds
-func getTilesForBounds(minLat, maxLat, minLng, maxLng float64, zoom maptile.Zoom) []maptile.Tile {
+// getTilesForBounds returns all tiles that cover the given bounds.
+// It rejects non-finite coordinates and ranges wider than maxSpan tiles on either axis
+// before allocating, so a degenerate bounding box cannot produce an enormous tile list.
+func getTilesForBounds(minLat, maxLat, minLng, maxLng float64, zoom maptile.Zoom, maxSpan int) ([]maptile.Tile, error) {
+	for _, value := range []float64{minLat, maxLat, minLng, maxLng} {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, errInvalidTileBounds
+		}
+	}
 	minTile := maptile.At(orb.Point{minLng, maxLat}, zoom)
 	maxTile := maptile.At(orb.Point{maxLng, minLat}, zoom)
 
-	tiles := make([]maptile.Tile, 0)
+	spanX := int64(maxTile.X) - int64(minTile.X) + 1
+	spanY := int64(maxTile.Y) - int64(minTile.Y) + 1
+	if spanX > int64(maxSpan) || spanY > int64(maxSpan) {
+		return nil, fmt.Errorf("%w: %dx%d tiles exceeds %d per axis", errTileSpanExceeded, max(spanX, 0), max(spanY, 0), maxSpan)
+	}
+
+	tiles := make([]maptile.Tile, 0, max(spanX, 0)*max(spanY, 0))
 	for x := minTile.X; x <= maxTile.X; x++ {
 		for y := minTile.Y; y <= maxTile.Y; y++ {
 			tiles = append(tiles, maptile.Tile{X: x, Y: y, Z: zoom})
 		}
 	}
 
-	return tiles
+	return tiles, nil
+}
+
+// mergeTileGraphOnce merges a tile graph unless it was already merged,
+// as when several missing tiles fall back to the same lower-zoom ancestor
+func mergeTileGraphOnce(graph, tileGraph *RoadGraph, merged map[*RoadGraph]struct{}) {
+	if _, ok := merged[tileGraph]; ok {
+		return
+	}
+	merged[tileGraph] = struct{}{}
+	mergeGraphs(graph, tileGraph)
 }
 
 // mergeGraphs merges source graph into target graph by remapping node IDs
@@ -523,10 +1519,9 @@ func getTilesForBounds(minLat, maxLat, minLng, maxLng float64, zoom maptile.Zoom
 func mergeGraphs(target, source *RoadGraph) {
 	// Build mapping from source node IDs to target node IDs
 	idMapping := make(map[NodeID]NodeID)
-	for sourceID, point := range source.Nodes {
-		targetID := target.getOrCreateNode(point)
-		idMapping[sourceID] = targetID
-	}
+	source.eachNode(func(sourceID NodeID, point orb.Point) {
+		idMapping[sourceID] = target.getOrCreateNode(point)
+	})
 
 	// Add edges with remapped node IDs
 	for sourceFromID, edges := range source.Edges {
@@ -536,17 +1531,18 @@ func mergeGraphs(target, source *RoadGraph) {
 				To:       idMapping[edge.To],
 				Distance: edge.Distance,
 				Duration: edge.Duration,
+				Class:    edge.Class,
 			}
 			target.Edges[targetFromID] = append(target.Edges[targetFromID], remappedEdge)
 		}
 	}
 }
 
-// haversineFallback returns Haversine-based results for all targets
+// haversineFallback returns fallback results for all targets
 func (s *pmtilesRoutingService) haversineFallback(source usecase.Coordinate, targets []usecase.Coordinate, startTime time.Time) (*usecase.OneToManyResult, error) {
 	results := make([]usecase.RouteResult, len(targets))
 	for i, target := range targets {
-		results[i] = s.haversineResult(source, target)
+		results[i] = s.fallbackResult(source, target)
 	}
 
 	return &usecase.OneToManyResult{
@@ -557,46 +1553,81 @@ func (s *pmtilesRoutingService) haversineFallback(source usecase.Coordinate, tar
 	}, nil
 }
 
+// fallbackResult returns the result for a target without a road route: a Haversine estimate,
+// or an unreachable result when the fallback is disabled
+func (s *pmtilesRoutingService) fallbackResult(source, target usecase.Coordinate) usecase.RouteResult {
+	if s.disableHaversineFallback {
+		return usecase.RouteResult{
+			Source:      source,
+			Target:      target,
+			IsReachable: false,
+		}
+	}
+
+	return s.haversineResult(source, target)
+}
+
 // haversineResult calculates a Haversine-based result
 func (s *pmtilesRoutingService) haversineResult(source, target usecase.Coordinate) usecase.RouteResult {
+	return haversineEstimate(source, target, s.estimateSpeedKmh)
+}
+
+// haversineEstimate returns the straight-line distance between two coordinates, timed at speedKmh
+func haversineEstimate(source, target usecase.Coordinate, speedKmh float64) usecase.RouteResult {
 	p1 := orb.Point{source.Lng, source.Lat}
 	p2 := orb.Point{target.Lng, target.Lat}
-	dist := haversineDistance(p1, p2)
+	distKm := haversineDistance(p1, p2) / 1000
 
 	return usecase.RouteResult{
 		Source:      source,
 		Target:      target,
-		DistanceKm:  dist / 1000,
-		DurationMin: (dist / 1000 / 30) * 60, // Assume 30 km/h
+		DistanceKm:  distKm,
+		DurationMin: distKm / speedKmh * 60,
 		IsReachable: true,
+		IsEstimate:  true,
 	}
 }
 
+// validateEstimateSpeed reports an ETA speed that is not a positive number of km/h
+func validateEstimateSpeed(speedKmh float64) error {
+	if !(speedKmh > 0) || math.IsInf(speedKmh, 0) {
+		return fmt.Errorf("routing default speed must be positive, got %v km/h", speedKmh)
+	}
+
+	return nil
+}
+
 // haversineFallbackService is a simple Haversine-only implementation
 type haversineFallbackService struct {
-	logger *slog.Logger
+	logger   *slog.Logger
+	speedKmh float64 // Speed in km/h that turns estimates into ETAs
 }
 
-func newHaversineFallbackService(logger *slog.Logger) *haversineFallbackService {
-	return &haversineFallbackService{logger: logger}
+func newHaversineFallbackService(logger *slog.Logger, speedKmh float64) *haversineFallbackService {
+	return &haversineFallbackService{logger: logger, speedKmh: speedKmh}
 }
 
-func (s *haversineFallbackService) OneToMany(ctx context.Context, source usecase.Coordinate, targets []usecase.Coordinate) (*usecase.OneToManyResult, error) {
+// NewHaversineRoutingService creates a routing service that only estimates straight-line distances,
+// timing them at speedKmh
+func NewHaversineRoutingService(logger *slog.Logger, speedKmh float64) (usecase.RoutingUsecase, error) {
+	if err := validateEstimateSpeed(speedKmh); err != nil {
+		return nil, err
+	}
+
+	return newHaversineFallbackService(logger, speedKmh), nil
+}
+
+// ManyToMany routes each source with OneToMany, since straight-line estimates share no graph
+func (s *haversineFallbackService) ManyToMany(ctx context.Context, profile string, sources, targets []usecase.Coordinate) ([]*usecase.OneToManyResult, error) {
+	return usecase.RouteEachSource(ctx, s, profile, sources, targets)
+}
+
+func (s *haversineFallbackService) OneToMany(ctx context.Context, _ string, source usecase.Coordinate, targets []usecase.Coordinate) (*usecase.OneToManyResult, error) {
 	startTime := time.Now()
 	results := make([]usecase.RouteResult, len(targets))
 
 	for i, target := range targets {
-		p1 := orb.Point{source.Lng, source.Lat}
-		p2 := orb.Point{target.Lng, target.Lat}
-		dist := haversineDistance(p1, p2)
-
-		results[i] = usecase.RouteResult{
-			Source:      source,
-			Target:      target,
-			DistanceKm:  dist / 1000,
-			DurationMin: (dist / 1000 / 30) * 60,
-			IsReachable: true,
-		}
+		results[i] = haversineEstimate(source, target, s.speedKmh)
 	}
 
 	return &usecase.OneToManyResult{
@@ -607,27 +1638,50 @@ func (s *haversineFallbackService) OneToMany(ctx context.Context, source usecase
 	}, nil
 }
 
-func (s *haversineFallbackService) FindNearestNode(ctx context.Context, coord usecase.Coordinate) (*usecase.NodeInfo, bool, error) {
+func (s *haversineFallbackService) FindNearestNode(ctx context.Context, _ string, coord usecase.Coordinate) (*usecase.NodeInfo, bool, error) {
 	return &usecase.NodeInfo{
 		ID:       usecase.NodeID(1),
 		Location: coord,
 	}, true, nil
 }
 
-func (s *haversineFallbackService) CalculateDistance(ctx context.Context, source, target usecase.Coordinate) (*usecase.RouteResult, error) {
-	p1 := orb.Point{source.Lng, source.Lat}
-	p2 := orb.Point{target.Lng, target.Lat}
-	dist := haversineDistance(p1, p2)
+func (s *haversineFallbackService) SnapBatch(ctx context.Context, _ string, coords []usecase.Coordinate) ([]usecase.NodeInfo, []bool, error) {
+	nodes := make([]usecase.NodeInfo, len(coords))
+	found := make([]bool, len(coords))
+	for i, coord := range coords {
+		nodes[i] = usecase.NodeInfo{ID: usecase.NodeID(1), Location: coord}
+		found[i] = true
+	}
 
-	return &usecase.RouteResult{
-		Source:      source,
-		Target:      target,
-		DistanceKm:  dist / 1000,
-		DurationMin: (dist / 1000 / 30) * 60,
-		IsReachable: true,
-	}, nil
+	return nodes, found, nil
+}
+
+func (s *haversineFallbackService) CalculateDistance(ctx context.Context, _ string, source, target usecase.Coordinate) (*usecase.RouteResult, error) {
+	result := haversineEstimate(source, target, s.speedKmh)
+
+	return &result, nil
+}
+
+func (s *haversineFallbackService) CalculateRoute(ctx context.Context, profile string, source, target usecase.Coordinate) (*usecase.RouteResult, error) {
+	result, err := s.CalculateDistance(ctx, profile, source, target)
+	if err != nil {
+		return nil, err
+	}
+	result.Geometry = []usecase.Coordinate{source, target}
+
+	return result, nil
+}
+
+// NearestRoadName finds no road, as the Haversine backend loads no road data
+func (s *haversineFallbackService) NearestRoadName(context.Context, usecase.Coordinate) (string, bool, error) {
+	return "", false, nil
 }
 
 func (s *haversineFallbackService) IsReady() bool {
 	return true
 }
+
+// Metadata reports the Haversine backend, which loads no road data
+func (s *haversineFallbackService) Metadata() usecase.RoutingMetadata {
+	return usecase.RoutingMetadata{Backend: config.RoutingBackendHaversine, Ready: true}
+}
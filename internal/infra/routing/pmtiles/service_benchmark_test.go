@@ -23,7 +23,7 @@ func BenchmarkTileKey(b *testing.B) {
 
 func BenchmarkGetTilesForBounds(b *testing.B) {
 	for b.Loop() {
-		getTilesForBounds(25.00, 25.10, 121.50, 121.60, 14)
+		_, _ = getTilesForBounds(25.00, 25.10, 121.50, 121.60, 14, testMaxTileSpan)
 	}
 }
 
@@ -48,7 +48,7 @@ func BenchmarkMergeGraphs(b *testing.B) {
 
 func BenchmarkHaversineFallback_OneToMany(b *testing.B) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
-	svc := newHaversineFallbackService(logger)
+	svc := newHaversineFallbackService(logger, 30)
 
 	source := usecase.Coordinate{Lat: 25.0330, Lng: 121.5654}
 	targets := make([]usecase.Coordinate, 100)
@@ -62,7 +62,7 @@ func BenchmarkHaversineFallback_OneToMany(b *testing.B) {
 	ctx := context.Background()
 
 	for b.Loop() {
-		_, _ = svc.OneToMany(ctx, source, targets)
+		_, _ = svc.OneToMany(ctx, usecase.DefaultRoutingProfile, source, targets)
 	}
 }
 
@@ -102,7 +102,7 @@ func BenchmarkPMTilesService_OneToMany(b *testing.B) {
 
 	b.ResetTimer()
 	for b.Loop() {
-		_, _ = svc.OneToMany(ctx, source, targets)
+		_, _ = svc.OneToMany(ctx, usecase.DefaultRoutingProfile, source, targets)
 	}
 }
 
@@ -136,7 +136,7 @@ func BenchmarkPMTilesService_CalculateDistance(b *testing.B) {
 
 	b.ResetTimer()
 	for b.Loop() {
-		_, _ = svc.CalculateDistance(ctx, source, target)
+		_, _ = svc.CalculateDistance(ctx, usecase.DefaultRoutingProfile, source, target)
 	}
 }
 
@@ -168,7 +168,7 @@ func BenchmarkPMTilesService_FindNearestNode(b *testing.B) {
 
 	b.ResetTimer()
 	for b.Loop() {
-		_, _, _ = svc.FindNearestNode(ctx, coord)
+		_, _, _ = svc.FindNearestNode(ctx, usecase.DefaultRoutingProfile, coord)
 	}
 }
 
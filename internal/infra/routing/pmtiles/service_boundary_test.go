@@ -91,7 +91,7 @@ func TestPMTilesService_TileBoundary_PointNearEdge(t *testing.T) {
 
 			t.Logf("Source tile: %s, Target tile: %s", tileKey(sourceTile), tileKey(targetTile))
 
-			result, err := svc.CalculateDistance(ctx, source, target)
+			result, err := svc.CalculateDistance(ctx, usecase.DefaultRoutingProfile, source, target)
 			require.NoError(t, err)
 
 			t.Logf("Result: reachable=%v, distance=%.2f km, duration=%.2f min",
@@ -233,7 +233,7 @@ func TestPMTilesService_TileBoundary_DiagonalTiles(t *testing.T) {
 		int(targetTile.X)-int(sourceTile.X),
 		int(targetTile.Y)-int(sourceTile.Y))
 
-	result, err := svc.CalculateDistance(ctx, source, target)
+	result, err := svc.CalculateDistance(ctx, usecase.DefaultRoutingProfile, source, target)
 	require.NoError(t, err)
 
 	t.Logf("  Result: reachable=%v, distance=%.2f km", result.IsReachable, result.DistanceKm)
@@ -298,7 +298,7 @@ func TestPMTilesService_CacheSameTileDifferentPoints(t *testing.T) {
 	t.Logf("Point2 tile: %s", tileKey(tile2))
 
 	// First request
-	_, _, err = svc.FindNearestNode(ctx, point1)
+	_, _, err = svc.FindNearestNode(ctx, usecase.DefaultRoutingProfile, point1)
 	require.NoError(t, err)
 
 	pmSvc.tileCacheMu.RLock()
@@ -307,7 +307,7 @@ func TestPMTilesService_CacheSameTileDifferentPoints(t *testing.T) {
 	t.Logf("Cache size after point1: %d tiles", cacheSize1)
 
 	// Second request to different point
-	_, _, err = svc.FindNearestNode(ctx, point2)
+	_, _, err = svc.FindNearestNode(ctx, usecase.DefaultRoutingProfile, point2)
 	require.NoError(t, err)
 
 	pmSvc.tileCacheMu.RLock()
@@ -357,7 +357,7 @@ func TestPMTilesService_TileCacheEfficiency(t *testing.T) {
 	}
 
 	// First request - should populate cache
-	_, err = svc.OneToMany(ctx, source, targets)
+	_, err = svc.OneToMany(ctx, usecase.DefaultRoutingProfile, source, targets)
 	require.NoError(t, err)
 
 	pmSvc.tileCacheMu.RLock()
@@ -366,7 +366,7 @@ func TestPMTilesService_TileCacheEfficiency(t *testing.T) {
 	t.Logf("Cache size after first request: %d tiles", cacheSize1)
 
 	// Second request to same area - should use cache
-	_, err = svc.OneToMany(ctx, source, targets)
+	_, err = svc.OneToMany(ctx, usecase.DefaultRoutingProfile, source, targets)
 	require.NoError(t, err)
 
 	pmSvc.tileCacheMu.RLock()
@@ -381,7 +381,7 @@ func TestPMTilesService_TileCacheEfficiency(t *testing.T) {
 		{Lat: 25.0400, Lng: 121.5700},
 		{Lat: 25.0250, Lng: 121.5600},
 	}
-	_, err = svc.OneToMany(ctx, source, nearbyTargets)
+	_, err = svc.OneToMany(ctx, usecase.DefaultRoutingProfile, source, nearbyTargets)
 	require.NoError(t, err)
 
 	pmSvc.tileCacheMu.RLock()
@@ -444,7 +444,7 @@ func TestTileCache_Concurrent(t *testing.T) {
 			wg.Add(1)
 			go func(src, tgt usecase.Coordinate) {
 				defer wg.Done()
-				_, err := svc.CalculateDistance(ctx, src, tgt)
+				_, err := svc.CalculateDistance(ctx, usecase.DefaultRoutingProfile, src, tgt)
 				if err != nil {
 					errChan <- err
 				}
@@ -509,7 +509,7 @@ func TestTileCache_ConcurrentSameTile(t *testing.T) {
 
 	for range numGoroutines {
 		wg.Go(func() {
-			_, _, err := svc.FindNearestNode(ctx, coord)
+			_, _, err := svc.FindNearestNode(ctx, usecase.DefaultRoutingProfile, coord)
 			if err != nil {
 				errChan <- err
 			}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"radar/config"
 	"radar/internal/usecase"
@@ -82,7 +83,7 @@ func TestNewPMTilesRoutingService_WithValidFile(t *testing.T) {
 
 	require.NoError(t, err)
 	require.NotNil(t, svc)
-	assert.True(t, svc.IsReady())
+	assert.Eventually(t, svc.IsReady, readinessProbeTimeout, 50*time.Millisecond)
 }
 
 func TestNewPMTilesRoutingService_DefaultValues(t *testing.T) {
@@ -118,8 +119,8 @@ func TestPMTilesRoutingService_IsReady(t *testing.T) {
 	pmSvc, ok := svc.(*pmtilesRoutingService)
 	require.True(t, ok)
 
-	assert.True(t, pmSvc.IsReady())
-	assert.NotNil(t, pmSvc.server)
+	assert.Eventually(t, pmSvc.IsReady, readinessProbeTimeout, 50*time.Millisecond)
+	assert.NotNil(t, pmSvc.currentArchive().server)
 }
 
 func TestPMTilesService_OneToMany_Integration(t *testing.T) {
@@ -133,7 +134,7 @@ func TestPMTilesService_OneToMany_Integration(t *testing.T) {
 		{Lat: 25.0310, Lng: 121.5640},
 	}
 
-	result, err := svc.OneToMany(ctx, source, targets)
+	result, err := svc.OneToMany(ctx, usecase.DefaultRoutingProfile, source, targets)
 
 	require.NoError(t, err)
 	require.NotNil(t, result)
@@ -149,7 +150,7 @@ func TestPMTilesService_OneToMany_EmptyTargets(t *testing.T) {
 	source := usecase.Coordinate{Lat: 25.0330, Lng: 121.5654}
 	targets := []usecase.Coordinate{}
 
-	result, err := svc.OneToMany(ctx, source, targets)
+	result, err := svc.OneToMany(ctx, usecase.DefaultRoutingProfile, source, targets)
 
 	require.NoError(t, err)
 	require.NotNil(t, result)
@@ -163,7 +164,7 @@ func TestPMTilesService_CalculateDistance_Integration(t *testing.T) {
 	source := usecase.Coordinate{Lat: 25.0330, Lng: 121.5654}
 	target := usecase.Coordinate{Lat: 25.0350, Lng: 121.5670}
 
-	result, err := svc.CalculateDistance(ctx, source, target)
+	result, err := svc.CalculateDistance(ctx, usecase.DefaultRoutingProfile, source, target)
 
 	require.NoError(t, err)
 	require.NotNil(t, result)
@@ -180,7 +181,7 @@ func TestPMTilesService_FindNearestNode_Integration(t *testing.T) {
 
 	coord := usecase.Coordinate{Lat: 25.0330, Lng: 121.5654}
 
-	nodeInfo, found, err := svc.FindNearestNode(ctx, coord)
+	nodeInfo, found, err := svc.FindNearestNode(ctx, usecase.DefaultRoutingProfile, coord)
 
 	require.NoError(t, err)
 	// Node may or may not be found depending on coverage
@@ -213,7 +214,7 @@ func TestPMTilesService_LongDistanceRoute(t *testing.T) {
 	)
 	t.Logf("Straight-line distance: %.2f km", straightLine/1000)
 
-	result, err := svc.CalculateDistance(ctx, source, target)
+	result, err := svc.CalculateDistance(ctx, usecase.DefaultRoutingProfile, source, target)
 	require.NoError(t, err)
 
 	pmSvc.tileCacheMu.RLock()
@@ -368,7 +369,8 @@ func TestPMTilesService_MultiTileFetch(t *testing.T) {
 	minLat, maxLat := 25.02, 25.05
 	minLng, maxLng := 121.51, 121.57
 
-	tiles := getTilesForBounds(minLat, maxLat, minLng, maxLng, 15)
+	tiles, err := getTilesForBounds(minLat, maxLat, minLng, maxLng, 15, testMaxTileSpan)
+	require.NoError(t, err)
 
 	t.Logf("Area bounds: [%.4f, %.4f] to [%.4f, %.4f]", minLat, minLng, maxLat, maxLng)
 	t.Logf("Tiles needed: %d", len(tiles))
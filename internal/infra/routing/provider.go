@@ -0,0 +1,189 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"radar/config"
+	"radar/internal/infra/routing/ch"
+	"radar/internal/infra/routing/pmtiles"
+	"radar/internal/usecase"
+
+	"go.uber.org/fx"
+)
+
+// ServiceParams holds dependencies for the routing backend selection
+type ServiceParams struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Config    *config.RoutingConfig `optional:"true"`
+	PMTiles   *config.PMTilesConfig `optional:"true"`
+	Logger    *slog.Logger
+}
+
+// NewRoutingService creates the routing usecase for the backend selected by routing.backend,
+// or a fallback chain over the backends listed in routing.fallbackChain
+func NewRoutingService(params ServiceParams) (usecase.RoutingUsecase, error) {
+	cfg := params.Config.WithDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid routing config: %w", err)
+	}
+
+	backends := &backendBuilder{cfg: cfg, pmtiles: params.PMTiles, lifecycle: params.Lifecycle, logger: params.Logger}
+
+	if len(cfg.FallbackChain) == 0 {
+		params.Logger.Info("Routing backend selected", slog.String("backend", cfg.Backend))
+
+		return backends.build(cfg.Backend)
+	}
+
+	params.Logger.Info("Routing fallback chain selected", slog.Any("backends", cfg.FallbackChain))
+
+	strategies := make([]fallbackStrategy, len(cfg.FallbackChain))
+	for idx, backend := range cfg.FallbackChain {
+		router, err := backends.build(backend)
+		if err != nil {
+			return nil, err
+		}
+		strategies[idx] = fallbackStrategy{name: backend, router: router, timeout: cfg.FallbackTimeouts[backend]}
+	}
+
+	return newFallbackChainService(strategies, params.Logger), nil
+}
+
+// backendBuilder creates routing backends, loading the CH engine at most once so a fallback chain
+// and large graph delegation share it
+type backendBuilder struct {
+	cfg       config.RoutingConfig
+	pmtiles   *config.PMTilesConfig
+	lifecycle fx.Lifecycle
+	logger    *slog.Logger
+
+	ch usecase.RoutingUsecase
+}
+
+func (b *backendBuilder) build(backend string) (usecase.RoutingUsecase, error) {
+	switch backend {
+	case config.RoutingBackendCH:
+		return b.chService()
+	case config.RoutingBackendHaversine:
+		return pmtiles.NewHaversineRoutingService(b.logger, b.cfg.DefaultSpeedKmh)
+	default:
+		largeGraph, err := b.largeGraphDelegate()
+		if err != nil {
+			return nil, err
+		}
+
+		return pmtiles.NewPMTilesRoutingService(pmtiles.PMTilesServiceParams{
+			Config:     b.pmtiles,
+			Routing:    &b.cfg,
+			LargeGraph: largeGraph,
+			Logger:     b.logger,
+		})
+	}
+}
+
+func (b *backendBuilder) chService() (usecase.RoutingUsecase, error) {
+	if b.ch == nil {
+		router, err := newCHRoutingService(b.cfg.CH, b.lifecycle, b.logger)
+		if err != nil {
+			return nil, err
+		}
+		b.ch = router
+	}
+
+	return b.ch, nil
+}
+
+// largeGraphDelegate returns the CH engine that answers large PMTiles queries, or nil when
+// delegation is off or PMTiles routing is disabled
+func (b *backendBuilder) largeGraphDelegate() (*pmtiles.LargeGraphDelegate, error) {
+	pmtilesCfg := b.pmtiles.WithDefaults()
+	if b.cfg.LargeGraphEdgeThreshold <= 0 || !pmtilesCfg.Enabled {
+		return nil, nil
+	}
+
+	profiles, err := largeGraphProfiles(pmtilesCfg.Profiles)
+	if err != nil {
+		return nil, err
+	}
+
+	router, err := b.chService()
+	if err != nil {
+		return nil, err
+	}
+
+	return &pmtiles.LargeGraphDelegate{Router: router, EdgeThreshold: b.cfg.LargeGraphEdgeThreshold, Profiles: profiles}, nil
+}
+
+// largeGraphProfiles maps each PMTiles profile to the CH profile its large queries are delegated to.
+// A profile without largeGraphProfile keeps its own name when CH has that profile, and is left to the CH
+// default otherwise. Naming a profile CH does not have is a config error, since every delegated query
+// on it would fail.
+func largeGraphProfiles(profiles []config.PMTilesProfileConfig) (map[string]string, error) {
+	known := ch.DefaultEngineConfig().Profiles
+	mapped := make(map[string]string, len(profiles))
+	for idx, profile := range profiles {
+		name := strings.TrimSpace(profile.Name)
+		target := strings.TrimSpace(profile.LargeGraphProfile)
+		if target == "" {
+			if _, ok := known[ch.Profile(name)]; ok {
+				mapped[name] = name
+			}
+
+			continue
+		}
+		if _, ok := known[ch.Profile(target)]; !ok {
+			return nil, fmt.Errorf("pmtiles.profiles[%d].largeGraphProfile %q is not a CH profile", idx, target)
+		}
+		mapped[name] = target
+	}
+
+	return mapped, nil
+}
+
+// newCHRoutingService loads the prepared CH data and wraps the engine as a routing usecase.
+// The engine is closed when the application stops, which unmaps a memory-mapped graph.
+func newCHRoutingService(cfg config.CHRoutingConfig, lifecycle fx.Lifecycle, logger *slog.Logger) (usecase.RoutingUsecase, error) {
+	engineConfig := ch.DefaultEngineConfig()
+	engineConfig.MaxSnapDistanceMeters = cfg.MaxSnapDistanceMeters
+	engineConfig.MemoryMap = cfg.MemoryMap
+	engineConfig.TurnRestrictions = cfg.TurnRestrictions
+	if cfg.OneToManyWorkers > 0 {
+		engineConfig.OneToManyWorkers = cfg.OneToManyWorkers
+	}
+	if cfg.WorkersPerCPU > 0 {
+		engineConfig.WorkersPerCPU = cfg.WorkersPerCPU
+	}
+
+	engine := ch.NewEngine(engineConfig, logger)
+	if err := engine.LoadData(cfg.DataDir); err != nil {
+		// A load that fails after mapping the graph still holds the mapping
+		if closeErr := engine.Close(); closeErr != nil {
+			logger.Warn("Failed to close CH routing engine", slog.String("error", closeErr.Error()))
+		}
+
+		return nil, fmt.Errorf("failed to load CH routing data: %w", err)
+	}
+	lifecycle.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			if err := engine.Close(); err != nil {
+				return fmt.Errorf("close CH routing engine: %w", err)
+			}
+
+			return nil
+		},
+	})
+
+	// A failed warmup only leaves pages cold, so the engine still serves
+	if cfg.Warmup {
+		if err := engine.Warmup(context.Background()); err != nil {
+			logger.Warn("CH routing engine warmup failed", slog.String("error", err.Error()))
+		}
+	}
+
+	return ch.NewRoutingService(engine), nil
+}
@@ -0,0 +1,137 @@
+package routing
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"radar/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+)
+
+func writeCHTestData(t *testing.T) string {
+	dataDir := t.TempDir()
+	files := map[string]string{
+		"vertices.csv":  "id,lat,lng,order_pos,importance\n0,25.0330,121.5654,0,1\n1,25.0478,121.5170,1,2\n",
+		"edges.csv":     "from,to,weight\n0,1,2000\n1,0,2000\n",
+		"shortcuts.csv": "from,to,weight,via_node\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dataDir, name), []byte(content), 0o644))
+	}
+
+	return dataDir
+}
+
+func TestNewRoutingService_SelectsBackend(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	chDataDir := writeCHTestData(t)
+
+	tests := []struct {
+		name     string
+		routing  *config.RoutingConfig
+		pmtiles  *config.PMTilesConfig
+		wantType string
+
+		// PMTiles reports ready only after fetching a tile, which these missing archives never serve
+		wantNotReady bool
+	}{
+		{name: "default is pmtiles", routing: nil, pmtiles: &config.PMTilesConfig{Enabled: true, Source: "walking.pmtiles"}, wantType: "*pmtiles.pmtilesRoutingService", wantNotReady: true},
+		{name: "pmtiles", routing: &config.RoutingConfig{Backend: config.RoutingBackendPMTiles}, pmtiles: &config.PMTilesConfig{Enabled: true, Source: "walking.pmtiles"}, wantType: "*pmtiles.pmtilesRoutingService", wantNotReady: true},
+		{name: "pmtiles disabled falls back to haversine", routing: &config.RoutingConfig{Backend: config.RoutingBackendPMTiles}, wantType: "*pmtiles.haversineFallbackService"},
+		{name: "ch", routing: &config.RoutingConfig{Backend: config.RoutingBackendCH, CH: config.CHRoutingConfig{DataDir: chDataDir}}, wantType: "*ch.chRoutingService"},
+		{name: "haversine", routing: &config.RoutingConfig{Backend: config.RoutingBackendHaversine}, wantType: "*pmtiles.haversineFallbackService"},
+		{
+			name:         "pmtiles with large graph delegation",
+			routing:      &config.RoutingConfig{LargeGraphEdgeThreshold: 1000, CH: config.CHRoutingConfig{DataDir: chDataDir}},
+			pmtiles:      &config.PMTilesConfig{Enabled: true, Source: "walking.pmtiles"},
+			wantType:     "*pmtiles.pmtilesRoutingService",
+			wantNotReady: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, err := NewRoutingService(ServiceParams{Lifecycle: fxtest.NewLifecycle(t), Config: tt.routing, PMTiles: tt.pmtiles, Logger: logger})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantType, fmt.Sprintf("%T", svc))
+			assert.Equal(t, !tt.wantNotReady, svc.IsReady())
+		})
+	}
+}
+
+func TestNewRoutingService_InvalidConfig(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name    string
+		routing *config.RoutingConfig
+		pmtiles *config.PMTilesConfig
+		wantErr string
+	}{
+		{name: "unknown backend", routing: &config.RoutingConfig{Backend: "osrm"}, wantErr: "routing.backend must be one of"},
+		{name: "ch without data dir", routing: &config.RoutingConfig{Backend: config.RoutingBackendCH}, wantErr: "routing.ch.dataDir is required"},
+		{name: "ch with missing data", routing: &config.RoutingConfig{Backend: config.RoutingBackendCH, CH: config.CHRoutingConfig{DataDir: filepath.Join(t.TempDir(), "missing")}}, wantErr: "failed to load CH routing data"},
+		{
+			name:    "large graph delegation with missing data",
+			routing: &config.RoutingConfig{LargeGraphEdgeThreshold: 1000, CH: config.CHRoutingConfig{DataDir: filepath.Join(t.TempDir(), "missing")}},
+			pmtiles: &config.PMTilesConfig{Enabled: true, Source: "walking.pmtiles"},
+			wantErr: "failed to load CH routing data",
+		},
+		{
+			name:    "large graph profile unknown to ch",
+			routing: &config.RoutingConfig{LargeGraphEdgeThreshold: 1000, CH: config.CHRoutingConfig{DataDir: writeCHTestData(t)}},
+			pmtiles: &config.PMTilesConfig{Enabled: true, Profiles: []config.PMTilesProfileConfig{
+				{Name: "driving", Source: "driving.pmtiles", LargeGraphProfile: "driving"},
+			}},
+			wantErr: `pmtiles.profiles[0].largeGraphProfile "driving" is not a CH profile`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, err := NewRoutingService(ServiceParams{Lifecycle: fxtest.NewLifecycle(t), Config: tt.routing, PMTiles: tt.pmtiles, Logger: logger})
+
+			require.Error(t, err)
+			assert.Nil(t, svc)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLargeGraphProfiles(t *testing.T) {
+	profiles, err := largeGraphProfiles([]config.PMTilesProfileConfig{
+		{Name: "driving", Source: "driving.pmtiles", LargeGraphProfile: "scooter"},
+		{Name: "walking", Source: "walking.pmtiles"},
+		{Name: "transit", Source: "transit.pmtiles"},
+	})
+
+	require.NoError(t, err)
+	// walking keeps its CH counterpart, and transit, which CH lacks, is left to the CH default
+	assert.Equal(t, map[string]string{"driving": "scooter", "walking": "walking"}, profiles)
+}
+
+func TestNewRoutingService_ClosesCHEngineOnStop(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	lifecycle := fxtest.NewLifecycle(t)
+
+	svc, err := NewRoutingService(ServiceParams{
+		Lifecycle: lifecycle,
+		Config:    &config.RoutingConfig{Backend: config.RoutingBackendCH, CH: config.CHRoutingConfig{DataDir: writeCHTestData(t)}},
+		Logger:    logger,
+	})
+	require.NoError(t, err)
+
+	lifecycle.RequireStart()
+	assert.True(t, svc.IsReady())
+
+	lifecycle.RequireStop()
+	assert.False(t, svc.IsReady())
+}
@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"radar/config"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/fx"
+)
+
+// Params defines the dependencies of the tracer provider
+type Params struct {
+	fx.In
+
+	Lc     fx.Lifecycle
+	Ctx    context.Context
+	Config *config.Config
+	Logger *slog.Logger
+}
+
+// NewTracerProvider creates the tracer provider configured by tracing. When tracing is disabled it returns a
+// no-op provider; otherwise spans are batched to an OTLP/HTTP collector and flushed when the app stops.
+func NewTracerProvider(params Params) (trace.TracerProvider, error) {
+	cfg := params.Config.Tracing
+	if cfg == nil || !cfg.Enabled {
+		return noop.NewTracerProvider(), nil
+	}
+
+	sampleRatio := 1.0
+	if cfg.SampleRatio != nil {
+		sampleRatio = *cfg.SampleRatio
+	}
+	if sampleRatio < 0 || sampleRatio > 1 {
+		return nil, fmt.Errorf("tracing.sampleRatio must be between 0 and 1, got %g", sampleRatio)
+	}
+
+	var opts []otlptracehttp.Option
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(params.Ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", params.Config.Env.ServiceName),
+		attribute.String("deployment.environment", params.Config.Env.Env),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+	params.Logger.Info("Exporting spans over OTLP/HTTP",
+		slog.String("endpoint", cfg.Endpoint),
+		slog.Float64("sample_ratio", sampleRatio),
+	)
+
+	// Hooks stop in reverse order, so the servers using the provider stop before the remaining spans are flushed
+	params.Lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			params.Logger.Info("Flushing and shutting down the tracer provider")
+
+			return provider.Shutdown(ctx)
+		},
+	})
+
+	return provider, nil
+}
@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"radar/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/fx/fxtest"
+)
+
+func newTestParams(t *testing.T, tracing *config.TracingConfig) (Params, *fxtest.Lifecycle) {
+	lifecycle := fxtest.NewLifecycle(t)
+	cfg := &config.Config{Tracing: tracing}
+	cfg.Env.ServiceName = "radar-geoworker"
+
+	return Params{
+		Lc:     lifecycle,
+		Ctx:    context.Background(),
+		Config: cfg,
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}, lifecycle
+}
+
+func TestNewTracerProvider_DisabledIsNoop(t *testing.T) {
+	params, _ := newTestParams(t, &config.TracingConfig{Endpoint: "collector:4318"})
+
+	provider, err := NewTracerProvider(params)
+
+	require.NoError(t, err)
+	assert.IsType(t, noop.TracerProvider{}, provider)
+}
+
+func TestNewTracerProvider_ExportsSpansOnStop(t *testing.T) {
+	var exports atomic.Int32
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/traces" {
+			exports.Add(1)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	params, lifecycle := newTestParams(t, &config.TracingConfig{
+		Enabled:  true,
+		Endpoint: strings.TrimPrefix(collector.URL, "http://"),
+		Insecure: true,
+	})
+	provider, err := NewTracerProvider(params)
+	require.NoError(t, err)
+
+	lifecycle.RequireStart()
+	_, span := provider.Tracer("test").Start(context.Background(), "push")
+	span.End()
+
+	// The batch is still buffered until the provider is shut down with the app
+	assert.Zero(t, exports.Load())
+	lifecycle.RequireStop()
+	assert.Equal(t, int32(1), exports.Load())
+}
+
+func TestNewTracerProvider_RejectsSampleRatioOutOfRange(t *testing.T) {
+	params, _ := newTestParams(t, &config.TracingConfig{Enabled: true, SampleRatio: new(1.5)})
+
+	_, err := NewTracerProvider(params)
+
+	require.ErrorContains(t, err, "tracing.sampleRatio must be between 0 and 1")
+}
@@ -441,6 +441,74 @@ func (_c *MockAddressRepository_FindAddressesByOwner_Call) RunAndReturn(run func
 	return _c
 }
 
+// FindDeletedAddressByID provides a mock function for the type MockAddressRepository
+func (_mock *MockAddressRepository) FindDeletedAddressByID(ctx context.Context, id uuid.UUID) (*entity.Address, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDeletedAddressByID")
+	}
+
+	var r0 *entity.Address
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entity.Address, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entity.Address); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Address)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAddressRepository_FindDeletedAddressByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindDeletedAddressByID'
+type MockAddressRepository_FindDeletedAddressByID_Call struct {
+	*mock.Call
+}
+
+// FindDeletedAddressByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockAddressRepository_Expecter) FindDeletedAddressByID(ctx interface{}, id interface{}) *MockAddressRepository_FindDeletedAddressByID_Call {
+	return &MockAddressRepository_FindDeletedAddressByID_Call{Call: _e.mock.On("FindDeletedAddressByID", ctx, id)}
+}
+
+func (_c *MockAddressRepository_FindDeletedAddressByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockAddressRepository_FindDeletedAddressByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAddressRepository_FindDeletedAddressByID_Call) Return(address *entity.Address, err error) *MockAddressRepository_FindDeletedAddressByID_Call {
+	_c.Call.Return(address, err)
+	return _c
+}
+
+func (_c *MockAddressRepository_FindDeletedAddressByID_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (*entity.Address, error)) *MockAddressRepository_FindDeletedAddressByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FindPrimaryAddressByOwner provides a mock function for the type MockAddressRepository
 func (_mock *MockAddressRepository) FindPrimaryAddressByOwner(ctx context.Context, ownerID uuid.UUID, ownerType entity.OwnerType) (*entity.Address, error) {
 	ret := _mock.Called(ctx, ownerID, ownerType)
@@ -515,6 +583,194 @@ func (_c *MockAddressRepository_FindPrimaryAddressByOwner_Call) RunAndReturn(run
 	return _c
 }
 
+// FindPrimaryAddressesByOwnerType provides a mock function for the type MockAddressRepository
+func (_mock *MockAddressRepository) FindPrimaryAddressesByOwnerType(ctx context.Context, ownerType entity.OwnerType) ([]*entity.Address, error) {
+	ret := _mock.Called(ctx, ownerType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPrimaryAddressesByOwnerType")
+	}
+
+	var r0 []*entity.Address
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entity.OwnerType) ([]*entity.Address, error)); ok {
+		return returnFunc(ctx, ownerType)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, entity.OwnerType) []*entity.Address); ok {
+		r0 = returnFunc(ctx, ownerType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Address)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, entity.OwnerType) error); ok {
+		r1 = returnFunc(ctx, ownerType)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAddressRepository_FindPrimaryAddressesByOwnerType_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindPrimaryAddressesByOwnerType'
+type MockAddressRepository_FindPrimaryAddressesByOwnerType_Call struct {
+	*mock.Call
+}
+
+// FindPrimaryAddressesByOwnerType is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ownerType entity.OwnerType
+func (_e *MockAddressRepository_Expecter) FindPrimaryAddressesByOwnerType(ctx interface{}, ownerType interface{}) *MockAddressRepository_FindPrimaryAddressesByOwnerType_Call {
+	return &MockAddressRepository_FindPrimaryAddressesByOwnerType_Call{Call: _e.mock.On("FindPrimaryAddressesByOwnerType", ctx, ownerType)}
+}
+
+func (_c *MockAddressRepository_FindPrimaryAddressesByOwnerType_Call) Run(run func(ctx context.Context, ownerType entity.OwnerType)) *MockAddressRepository_FindPrimaryAddressesByOwnerType_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 entity.OwnerType
+		if args[1] != nil {
+			arg1 = args[1].(entity.OwnerType)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAddressRepository_FindPrimaryAddressesByOwnerType_Call) Return(addresses []*entity.Address, err error) *MockAddressRepository_FindPrimaryAddressesByOwnerType_Call {
+	_c.Call.Return(addresses, err)
+	return _c
+}
+
+func (_c *MockAddressRepository_FindPrimaryAddressesByOwnerType_Call) RunAndReturn(run func(ctx context.Context, ownerType entity.OwnerType) ([]*entity.Address, error)) *MockAddressRepository_FindPrimaryAddressesByOwnerType_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LockOwner provides a mock function for the type MockAddressRepository
+func (_mock *MockAddressRepository) LockOwner(ctx context.Context, ownerID uuid.UUID, ownerType entity.OwnerType) error {
+	ret := _mock.Called(ctx, ownerID, ownerType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockOwner")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, entity.OwnerType) error); ok {
+		r0 = returnFunc(ctx, ownerID, ownerType)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockAddressRepository_LockOwner_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LockOwner'
+type MockAddressRepository_LockOwner_Call struct {
+	*mock.Call
+}
+
+// LockOwner is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ownerID uuid.UUID
+//   - ownerType entity.OwnerType
+func (_e *MockAddressRepository_Expecter) LockOwner(ctx interface{}, ownerID interface{}, ownerType interface{}) *MockAddressRepository_LockOwner_Call {
+	return &MockAddressRepository_LockOwner_Call{Call: _e.mock.On("LockOwner", ctx, ownerID, ownerType)}
+}
+
+func (_c *MockAddressRepository_LockOwner_Call) Run(run func(ctx context.Context, ownerID uuid.UUID, ownerType entity.OwnerType)) *MockAddressRepository_LockOwner_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 entity.OwnerType
+		if args[2] != nil {
+			arg2 = args[2].(entity.OwnerType)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAddressRepository_LockOwner_Call) Return(err error) *MockAddressRepository_LockOwner_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockAddressRepository_LockOwner_Call) RunAndReturn(run func(ctx context.Context, ownerID uuid.UUID, ownerType entity.OwnerType) error) *MockAddressRepository_LockOwner_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RestoreAddress provides a mock function for the type MockAddressRepository
+func (_mock *MockAddressRepository) RestoreAddress(ctx context.Context, id uuid.UUID) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreAddress")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockAddressRepository_RestoreAddress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreAddress'
+type MockAddressRepository_RestoreAddress_Call struct {
+	*mock.Call
+}
+
+// RestoreAddress is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockAddressRepository_Expecter) RestoreAddress(ctx interface{}, id interface{}) *MockAddressRepository_RestoreAddress_Call {
+	return &MockAddressRepository_RestoreAddress_Call{Call: _e.mock.On("RestoreAddress", ctx, id)}
+}
+
+func (_c *MockAddressRepository_RestoreAddress_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockAddressRepository_RestoreAddress_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAddressRepository_RestoreAddress_Call) Return(err error) *MockAddressRepository_RestoreAddress_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockAddressRepository_RestoreAddress_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) error) *MockAddressRepository_RestoreAddress_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateAddress provides a mock function for the type MockAddressRepository
 func (_mock *MockAddressRepository) UpdateAddress(ctx context.Context, address *entity.Address) error {
 	ret := _mock.Called(ctx, address)
@@ -8,6 +8,7 @@ import (
 	"context"
 	"radar/internal/domain/entity"
 	"radar/internal/domain/repository"
+	"time"
 
 	"github.com/google/uuid"
 	mock "github.com/stretchr/testify/mock"
@@ -154,6 +155,72 @@ func (_c *MockDeviceRepository_DeleteDevice_Call) RunAndReturn(run func(ctx cont
 	return _c
 }
 
+// DeleteDevices provides a mock function for the type MockDeviceRepository
+func (_mock *MockDeviceRepository) DeleteDevices(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	ret := _mock.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDevices")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []uuid.UUID) (int64, error)); ok {
+		return returnFunc(ctx, ids)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []uuid.UUID) int64); ok {
+		r0 = returnFunc(ctx, ids)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockDeviceRepository_DeleteDevices_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteDevices'
+type MockDeviceRepository_DeleteDevices_Call struct {
+	*mock.Call
+}
+
+// DeleteDevices is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []uuid.UUID
+func (_e *MockDeviceRepository_Expecter) DeleteDevices(ctx interface{}, ids interface{}) *MockDeviceRepository_DeleteDevices_Call {
+	return &MockDeviceRepository_DeleteDevices_Call{Call: _e.mock.On("DeleteDevices", ctx, ids)}
+}
+
+func (_c *MockDeviceRepository_DeleteDevices_Call) Run(run func(ctx context.Context, ids []uuid.UUID)) *MockDeviceRepository_DeleteDevices_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].([]uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockDeviceRepository_DeleteDevices_Call) Return(n int64, err error) *MockDeviceRepository_DeleteDevices_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockDeviceRepository_DeleteDevices_Call) RunAndReturn(run func(ctx context.Context, ids []uuid.UUID) (int64, error)) *MockDeviceRepository_DeleteDevices_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FindDeviceByID provides a mock function for the type MockDeviceRepository
 func (_mock *MockDeviceRepository) FindDeviceByID(ctx context.Context, id uuid.UUID) (*entity.UserDevice, error) {
 	ret := _mock.Called(ctx, id)
@@ -512,6 +579,284 @@ func (_c *MockDeviceRepository_FindDevicesByUser_Call) RunAndReturn(run func(ctx
 	return _c
 }
 
+// FindStaleDevices provides a mock function for the type MockDeviceRepository
+func (_mock *MockDeviceRepository) FindStaleDevices(ctx context.Context, olderThan time.Time, afterID uuid.UUID, limit int) ([]*entity.UserDevice, error) {
+	ret := _mock.Called(ctx, olderThan, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindStaleDevices")
+	}
+
+	var r0 []*entity.UserDevice
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, uuid.UUID, int) ([]*entity.UserDevice, error)); ok {
+		return returnFunc(ctx, olderThan, afterID, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, uuid.UUID, int) []*entity.UserDevice); ok {
+		r0 = returnFunc(ctx, olderThan, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.UserDevice)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, uuid.UUID, int) error); ok {
+		r1 = returnFunc(ctx, olderThan, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockDeviceRepository_FindStaleDevices_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindStaleDevices'
+type MockDeviceRepository_FindStaleDevices_Call struct {
+	*mock.Call
+}
+
+// FindStaleDevices is a helper method to define mock.On call
+//   - ctx context.Context
+//   - olderThan time.Time
+//   - afterID uuid.UUID
+//   - limit int
+func (_e *MockDeviceRepository_Expecter) FindStaleDevices(ctx interface{}, olderThan interface{}, afterID interface{}, limit interface{}) *MockDeviceRepository_FindStaleDevices_Call {
+	return &MockDeviceRepository_FindStaleDevices_Call{Call: _e.mock.On("FindStaleDevices", ctx, olderThan, afterID, limit)}
+}
+
+func (_c *MockDeviceRepository_FindStaleDevices_Call) Run(run func(ctx context.Context, olderThan time.Time, afterID uuid.UUID, limit int)) *MockDeviceRepository_FindStaleDevices_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockDeviceRepository_FindStaleDevices_Call) Return(devices []*entity.UserDevice, err error) *MockDeviceRepository_FindStaleDevices_Call {
+	_c.Call.Return(devices, err)
+	return _c
+}
+
+func (_c *MockDeviceRepository_FindStaleDevices_Call) RunAndReturn(run func(ctx context.Context, olderThan time.Time, afterID uuid.UUID, limit int) ([]*entity.UserDevice, error)) *MockDeviceRepository_FindStaleDevices_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordDeliverySuccess provides a mock function for the type MockDeviceRepository
+func (_mock *MockDeviceRepository) RecordDeliverySuccess(ctx context.Context, ids []uuid.UUID, at time.Time) error {
+	ret := _mock.Called(ctx, ids, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordDeliverySuccess")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []uuid.UUID, time.Time) error); ok {
+		r0 = returnFunc(ctx, ids, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockDeviceRepository_RecordDeliverySuccess_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordDeliverySuccess'
+type MockDeviceRepository_RecordDeliverySuccess_Call struct {
+	*mock.Call
+}
+
+// RecordDeliverySuccess is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []uuid.UUID
+//   - at time.Time
+func (_e *MockDeviceRepository_Expecter) RecordDeliverySuccess(ctx interface{}, ids interface{}, at interface{}) *MockDeviceRepository_RecordDeliverySuccess_Call {
+	return &MockDeviceRepository_RecordDeliverySuccess_Call{Call: _e.mock.On("RecordDeliverySuccess", ctx, ids, at)}
+}
+
+func (_c *MockDeviceRepository_RecordDeliverySuccess_Call) Run(run func(ctx context.Context, ids []uuid.UUID, at time.Time)) *MockDeviceRepository_RecordDeliverySuccess_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].([]uuid.UUID)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockDeviceRepository_RecordDeliverySuccess_Call) Return(err error) *MockDeviceRepository_RecordDeliverySuccess_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockDeviceRepository_RecordDeliverySuccess_Call) RunAndReturn(run func(ctx context.Context, ids []uuid.UUID, at time.Time) error) *MockDeviceRepository_RecordDeliverySuccess_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordInvalidTokenStrike provides a mock function for the type MockDeviceRepository
+func (_mock *MockDeviceRepository) RecordInvalidTokenStrike(ctx context.Context, id uuid.UUID, at time.Time, window time.Duration) (int, error) {
+	ret := _mock.Called(ctx, id, at, window)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordInvalidTokenStrike")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, time.Duration) (int, error)); ok {
+		return returnFunc(ctx, id, at, window)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, time.Duration) int); ok {
+		r0 = returnFunc(ctx, id, at, window)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time, time.Duration) error); ok {
+		r1 = returnFunc(ctx, id, at, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockDeviceRepository_RecordInvalidTokenStrike_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordInvalidTokenStrike'
+type MockDeviceRepository_RecordInvalidTokenStrike_Call struct {
+	*mock.Call
+}
+
+// RecordInvalidTokenStrike is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - at time.Time
+//   - window time.Duration
+func (_e *MockDeviceRepository_Expecter) RecordInvalidTokenStrike(ctx interface{}, id interface{}, at interface{}, window interface{}) *MockDeviceRepository_RecordInvalidTokenStrike_Call {
+	return &MockDeviceRepository_RecordInvalidTokenStrike_Call{Call: _e.mock.On("RecordInvalidTokenStrike", ctx, id, at, window)}
+}
+
+func (_c *MockDeviceRepository_RecordInvalidTokenStrike_Call) Run(run func(ctx context.Context, id uuid.UUID, at time.Time, window time.Duration)) *MockDeviceRepository_RecordInvalidTokenStrike_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		var arg3 time.Duration
+		if args[3] != nil {
+			arg3 = args[3].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockDeviceRepository_RecordInvalidTokenStrike_Call) Return(_a0 int, _a1 error) *MockDeviceRepository_RecordInvalidTokenStrike_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockDeviceRepository_RecordInvalidTokenStrike_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, at time.Time, window time.Duration) (int, error)) *MockDeviceRepository_RecordInvalidTokenStrike_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetInvalidTokenStrikes provides a mock function for the type MockDeviceRepository
+func (_mock *MockDeviceRepository) ResetInvalidTokenStrikes(ctx context.Context, ids []uuid.UUID) error {
+	ret := _mock.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetInvalidTokenStrikes")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []uuid.UUID) error); ok {
+		r0 = returnFunc(ctx, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockDeviceRepository_ResetInvalidTokenStrikes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetInvalidTokenStrikes'
+type MockDeviceRepository_ResetInvalidTokenStrikes_Call struct {
+	*mock.Call
+}
+
+// ResetInvalidTokenStrikes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []uuid.UUID
+func (_e *MockDeviceRepository_Expecter) ResetInvalidTokenStrikes(ctx interface{}, ids interface{}) *MockDeviceRepository_ResetInvalidTokenStrikes_Call {
+	return &MockDeviceRepository_ResetInvalidTokenStrikes_Call{Call: _e.mock.On("ResetInvalidTokenStrikes", ctx, ids)}
+}
+
+func (_c *MockDeviceRepository_ResetInvalidTokenStrikes_Call) Run(run func(ctx context.Context, ids []uuid.UUID)) *MockDeviceRepository_ResetInvalidTokenStrikes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].([]uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockDeviceRepository_ResetInvalidTokenStrikes_Call) Return(_a0 error) *MockDeviceRepository_ResetInvalidTokenStrikes_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockDeviceRepository_ResetInvalidTokenStrikes_Call) RunAndReturn(run func(ctx context.Context, ids []uuid.UUID) error) *MockDeviceRepository_ResetInvalidTokenStrikes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // RestoreAndUpdateDevice provides a mock function for the type MockDeviceRepository
 func (_mock *MockDeviceRepository) RestoreAndUpdateDevice(ctx context.Context, userID uuid.UUID, id uuid.UUID, fcmToken string) error {
 	ret := _mock.Called(ctx, userID, id, fcmToken)
@@ -710,6 +1055,138 @@ func (_c *MockDeviceRepository_SoftDeleteStaleDevices_Call) RunAndReturn(run fun
 	return _c
 }
 
+// UpdateDeviceClientInfo provides a mock function for the type MockDeviceRepository
+func (_mock *MockDeviceRepository) UpdateDeviceClientInfo(ctx context.Context, deviceID uuid.UUID, platform string, appVersion string) error {
+	ret := _mock.Called(ctx, deviceID, platform, appVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDeviceClientInfo")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string) error); ok {
+		r0 = returnFunc(ctx, deviceID, platform, appVersion)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockDeviceRepository_UpdateDeviceClientInfo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateDeviceClientInfo'
+type MockDeviceRepository_UpdateDeviceClientInfo_Call struct {
+	*mock.Call
+}
+
+// UpdateDeviceClientInfo is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deviceID uuid.UUID
+//   - platform string
+//   - appVersion string
+func (_e *MockDeviceRepository_Expecter) UpdateDeviceClientInfo(ctx interface{}, deviceID interface{}, platform interface{}, appVersion interface{}) *MockDeviceRepository_UpdateDeviceClientInfo_Call {
+	return &MockDeviceRepository_UpdateDeviceClientInfo_Call{Call: _e.mock.On("UpdateDeviceClientInfo", ctx, deviceID, platform, appVersion)}
+}
+
+func (_c *MockDeviceRepository_UpdateDeviceClientInfo_Call) Run(run func(ctx context.Context, deviceID uuid.UUID, platform string, appVersion string)) *MockDeviceRepository_UpdateDeviceClientInfo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockDeviceRepository_UpdateDeviceClientInfo_Call) Return(err error) *MockDeviceRepository_UpdateDeviceClientInfo_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockDeviceRepository_UpdateDeviceClientInfo_Call) RunAndReturn(run func(ctx context.Context, deviceID uuid.UUID, platform string, appVersion string) error) *MockDeviceRepository_UpdateDeviceClientInfo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateDeviceNotificationSettings provides a mock function for the type MockDeviceRepository
+func (_mock *MockDeviceRepository) UpdateDeviceNotificationSettings(ctx context.Context, deviceID uuid.UUID, settings entity.DeviceNotificationSettings) error {
+	ret := _mock.Called(ctx, deviceID, settings)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDeviceNotificationSettings")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, entity.DeviceNotificationSettings) error); ok {
+		r0 = returnFunc(ctx, deviceID, settings)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockDeviceRepository_UpdateDeviceNotificationSettings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateDeviceNotificationSettings'
+type MockDeviceRepository_UpdateDeviceNotificationSettings_Call struct {
+	*mock.Call
+}
+
+// UpdateDeviceNotificationSettings is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deviceID uuid.UUID
+//   - settings entity.DeviceNotificationSettings
+func (_e *MockDeviceRepository_Expecter) UpdateDeviceNotificationSettings(ctx interface{}, deviceID interface{}, settings interface{}) *MockDeviceRepository_UpdateDeviceNotificationSettings_Call {
+	return &MockDeviceRepository_UpdateDeviceNotificationSettings_Call{Call: _e.mock.On("UpdateDeviceNotificationSettings", ctx, deviceID, settings)}
+}
+
+func (_c *MockDeviceRepository_UpdateDeviceNotificationSettings_Call) Run(run func(ctx context.Context, deviceID uuid.UUID, settings entity.DeviceNotificationSettings)) *MockDeviceRepository_UpdateDeviceNotificationSettings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 entity.DeviceNotificationSettings
+		if args[2] != nil {
+			arg2 = args[2].(entity.DeviceNotificationSettings)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockDeviceRepository_UpdateDeviceNotificationSettings_Call) Return(err error) *MockDeviceRepository_UpdateDeviceNotificationSettings_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockDeviceRepository_UpdateDeviceNotificationSettings_Call) RunAndReturn(run func(ctx context.Context, deviceID uuid.UUID, settings entity.DeviceNotificationSettings) error) *MockDeviceRepository_UpdateDeviceNotificationSettings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateFCMToken provides a mock function for the type MockDeviceRepository
 func (_mock *MockDeviceRepository) UpdateFCMToken(ctx context.Context, deviceID uuid.UUID, fcmToken string) error {
 	ret := _mock.Called(ctx, deviceID, fcmToken)
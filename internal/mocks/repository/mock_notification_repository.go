@@ -7,6 +7,8 @@ package repository
 import (
 	"context"
 	"radar/internal/domain/entity"
+	"radar/internal/domain/repository"
+	"time"
 
 	"github.com/google/uuid"
 	mock "github.com/stretchr/testify/mock"
@@ -39,6 +41,75 @@ func (_m *MockNotificationRepository) EXPECT() *MockNotificationRepository_Expec
 	return &MockNotificationRepository_Expecter{mock: &_m.Mock}
 }
 
+// AddNotificationResults provides a mock function for the type MockNotificationRepository
+func (_mock *MockNotificationRepository) AddNotificationResults(ctx context.Context, id uuid.UUID, sent int, failed int) error {
+	ret := _mock.Called(ctx, id, sent, failed)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddNotificationResults")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) error); ok {
+		r0 = returnFunc(ctx, id, sent, failed)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockNotificationRepository_AddNotificationResults_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddNotificationResults'
+type MockNotificationRepository_AddNotificationResults_Call struct {
+	*mock.Call
+}
+
+// AddNotificationResults is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - sent int
+//   - failed int
+func (_e *MockNotificationRepository_Expecter) AddNotificationResults(ctx interface{}, id interface{}, sent interface{}, failed interface{}) *MockNotificationRepository_AddNotificationResults_Call {
+	return &MockNotificationRepository_AddNotificationResults_Call{Call: _e.mock.On("AddNotificationResults", ctx, id, sent, failed)}
+}
+
+func (_c *MockNotificationRepository_AddNotificationResults_Call) Run(run func(ctx context.Context, id uuid.UUID, sent int, failed int)) *MockNotificationRepository_AddNotificationResults_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockNotificationRepository_AddNotificationResults_Call) Return(err error) *MockNotificationRepository_AddNotificationResults_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockNotificationRepository_AddNotificationResults_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, sent int, failed int) error) *MockNotificationRepository_AddNotificationResults_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // BatchCreateNotificationLogs provides a mock function for the type MockNotificationRepository
 func (_mock *MockNotificationRepository) BatchCreateNotificationLogs(ctx context.Context, logs []*entity.NotificationLog) error {
 	ret := _mock.Called(ctx, logs)
@@ -96,7 +167,6 @@ func (_c *MockNotificationRepository_BatchCreateNotificationLogs_Call) RunAndRet
 	return _c
 }
 
-// CreateNotification provides a mock function for the type MockNotificationRepository
 func (_mock *MockNotificationRepository) CreateNotification(ctx context.Context, notification *entity.MerchantLocationNotification) error {
 	ret := _mock.Called(ctx, notification)
 
@@ -153,6 +223,84 @@ func (_c *MockNotificationRepository_CreateNotification_Call) RunAndReturn(run f
 	return _c
 }
 
+// ClaimScheduledNotification provides a mock function for the type MockNotificationRepository
+func (_mock *MockNotificationRepository) ClaimScheduledNotification(ctx context.Context, notification *entity.MerchantLocationNotification, cooldown time.Duration, claimedBefore time.Time) (repository.ScheduleClaim, error) {
+	ret := _mock.Called(ctx, notification, cooldown, claimedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimScheduledNotification")
+	}
+
+	var r0 repository.ScheduleClaim
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *entity.MerchantLocationNotification, time.Duration, time.Time) (repository.ScheduleClaim, error)); ok {
+		return returnFunc(ctx, notification, cooldown, claimedBefore)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *entity.MerchantLocationNotification, time.Duration, time.Time) repository.ScheduleClaim); ok {
+		r0 = returnFunc(ctx, notification, cooldown, claimedBefore)
+	} else {
+		r0 = ret.Get(0).(repository.ScheduleClaim)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *entity.MerchantLocationNotification, time.Duration, time.Time) error); ok {
+		r1 = returnFunc(ctx, notification, cooldown, claimedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockNotificationRepository_ClaimScheduledNotification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimScheduledNotification'
+type MockNotificationRepository_ClaimScheduledNotification_Call struct {
+	*mock.Call
+}
+
+// ClaimScheduledNotification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - notification *entity.MerchantLocationNotification
+//   - cooldown time.Duration
+//   - claimedBefore time.Time
+func (_e *MockNotificationRepository_Expecter) ClaimScheduledNotification(ctx interface{}, notification interface{}, cooldown interface{}, claimedBefore interface{}) *MockNotificationRepository_ClaimScheduledNotification_Call {
+	return &MockNotificationRepository_ClaimScheduledNotification_Call{Call: _e.mock.On("ClaimScheduledNotification", ctx, notification, cooldown, claimedBefore)}
+}
+
+func (_c *MockNotificationRepository_ClaimScheduledNotification_Call) Run(run func(ctx context.Context, notification *entity.MerchantLocationNotification, cooldown time.Duration, claimedBefore time.Time)) *MockNotificationRepository_ClaimScheduledNotification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *entity.MerchantLocationNotification
+		if args[1] != nil {
+			arg1 = args[1].(*entity.MerchantLocationNotification)
+		}
+		var arg2 time.Duration
+		if args[2] != nil {
+			arg2 = args[2].(time.Duration)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockNotificationRepository_ClaimScheduledNotification_Call) Return(scheduleClaim repository.ScheduleClaim, err error) *MockNotificationRepository_ClaimScheduledNotification_Call {
+	_c.Call.Return(scheduleClaim, err)
+	return _c
+}
+
+func (_c *MockNotificationRepository_ClaimScheduledNotification_Call) RunAndReturn(run func(ctx context.Context, notification *entity.MerchantLocationNotification, cooldown time.Duration, claimedBefore time.Time) (repository.ScheduleClaim, error)) *MockNotificationRepository_ClaimScheduledNotification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateNotificationLog provides a mock function for the type MockNotificationRepository
 func (_mock *MockNotificationRepository) CreateNotificationLog(ctx context.Context, log *entity.NotificationLog) error {
 	ret := _mock.Called(ctx, log)
@@ -210,6 +358,228 @@ func (_c *MockNotificationRepository_CreateNotificationLog_Call) RunAndReturn(ru
 	return _c
 }
 
+// CreateNotificationOutsideCooldown provides a mock function for the type MockNotificationRepository
+func (_mock *MockNotificationRepository) CreateNotificationOutsideCooldown(ctx context.Context, notification *entity.MerchantLocationNotification, cooldown time.Duration) (*time.Time, error) {
+	ret := _mock.Called(ctx, notification, cooldown)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateNotificationOutsideCooldown")
+	}
+
+	var r0 *time.Time
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *entity.MerchantLocationNotification, time.Duration) (*time.Time, error)); ok {
+		return returnFunc(ctx, notification, cooldown)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *entity.MerchantLocationNotification, time.Duration) *time.Time); ok {
+		r0 = returnFunc(ctx, notification, cooldown)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*time.Time)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *entity.MerchantLocationNotification, time.Duration) error); ok {
+		r1 = returnFunc(ctx, notification, cooldown)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockNotificationRepository_CreateNotificationOutsideCooldown_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateNotificationOutsideCooldown'
+type MockNotificationRepository_CreateNotificationOutsideCooldown_Call struct {
+	*mock.Call
+}
+
+// CreateNotificationOutsideCooldown is a helper method to define mock.On call
+//   - ctx context.Context
+//   - notification *entity.MerchantLocationNotification
+//   - cooldown time.Duration
+func (_e *MockNotificationRepository_Expecter) CreateNotificationOutsideCooldown(ctx interface{}, notification interface{}, cooldown interface{}) *MockNotificationRepository_CreateNotificationOutsideCooldown_Call {
+	return &MockNotificationRepository_CreateNotificationOutsideCooldown_Call{Call: _e.mock.On("CreateNotificationOutsideCooldown", ctx, notification, cooldown)}
+}
+
+func (_c *MockNotificationRepository_CreateNotificationOutsideCooldown_Call) Run(run func(ctx context.Context, notification *entity.MerchantLocationNotification, cooldown time.Duration)) *MockNotificationRepository_CreateNotificationOutsideCooldown_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *entity.MerchantLocationNotification
+		if args[1] != nil {
+			arg1 = args[1].(*entity.MerchantLocationNotification)
+		}
+		var arg2 time.Duration
+		if args[2] != nil {
+			arg2 = args[2].(time.Duration)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockNotificationRepository_CreateNotificationOutsideCooldown_Call) Return(_a0 *time.Time, _a1 error) *MockNotificationRepository_CreateNotificationOutsideCooldown_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockNotificationRepository_CreateNotificationOutsideCooldown_Call) RunAndReturn(run func(ctx context.Context, notification *entity.MerchantLocationNotification, cooldown time.Duration) (*time.Time, error)) *MockNotificationRepository_CreateNotificationOutsideCooldown_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindDueScheduledNotifications provides a mock function for the type MockNotificationRepository
+func (_mock *MockNotificationRepository) FindDueScheduledNotifications(ctx context.Context, dueBy time.Time, claimedBefore time.Time, limit int) ([]*entity.MerchantLocationNotification, error) {
+	ret := _mock.Called(ctx, dueBy, claimedBefore, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDueScheduledNotifications")
+	}
+
+	var r0 []*entity.MerchantLocationNotification
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int) ([]*entity.MerchantLocationNotification, error)); ok {
+		return returnFunc(ctx, dueBy, claimedBefore, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Time, int) []*entity.MerchantLocationNotification); ok {
+		r0 = returnFunc(ctx, dueBy, claimedBefore, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.MerchantLocationNotification)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, time.Time, int) error); ok {
+		r1 = returnFunc(ctx, dueBy, claimedBefore, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockNotificationRepository_FindDueScheduledNotifications_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindDueScheduledNotifications'
+type MockNotificationRepository_FindDueScheduledNotifications_Call struct {
+	*mock.Call
+}
+
+// FindDueScheduledNotifications is a helper method to define mock.On call
+//   - ctx context.Context
+//   - dueBy time.Time
+//   - claimedBefore time.Time
+//   - limit int
+func (_e *MockNotificationRepository_Expecter) FindDueScheduledNotifications(ctx interface{}, dueBy interface{}, claimedBefore interface{}, limit interface{}) *MockNotificationRepository_FindDueScheduledNotifications_Call {
+	return &MockNotificationRepository_FindDueScheduledNotifications_Call{Call: _e.mock.On("FindDueScheduledNotifications", ctx, dueBy, claimedBefore, limit)}
+}
+
+func (_c *MockNotificationRepository_FindDueScheduledNotifications_Call) Run(run func(ctx context.Context, dueBy time.Time, claimedBefore time.Time, limit int)) *MockNotificationRepository_FindDueScheduledNotifications_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockNotificationRepository_FindDueScheduledNotifications_Call) Return(_a0 []*entity.MerchantLocationNotification, _a1 error) *MockNotificationRepository_FindDueScheduledNotifications_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockNotificationRepository_FindDueScheduledNotifications_Call) RunAndReturn(run func(ctx context.Context, dueBy time.Time, claimedBefore time.Time, limit int) ([]*entity.MerchantLocationNotification, error)) *MockNotificationRepository_FindDueScheduledNotifications_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindLoggedDeviceIDs provides a mock function for the type MockNotificationRepository
+func (_mock *MockNotificationRepository) FindLoggedDeviceIDs(ctx context.Context, notificationID uuid.UUID) ([]uuid.UUID, error) {
+	ret := _mock.Called(ctx, notificationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindLoggedDeviceIDs")
+	}
+
+	var r0 []uuid.UUID
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]uuid.UUID, error)); ok {
+		return returnFunc(ctx, notificationID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) []uuid.UUID); ok {
+		r0 = returnFunc(ctx, notificationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uuid.UUID)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, notificationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockNotificationRepository_FindLoggedDeviceIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindLoggedDeviceIDs'
+type MockNotificationRepository_FindLoggedDeviceIDs_Call struct {
+	*mock.Call
+}
+
+// FindLoggedDeviceIDs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - notificationID uuid.UUID
+func (_e *MockNotificationRepository_Expecter) FindLoggedDeviceIDs(ctx interface{}, notificationID interface{}) *MockNotificationRepository_FindLoggedDeviceIDs_Call {
+	return &MockNotificationRepository_FindLoggedDeviceIDs_Call{Call: _e.mock.On("FindLoggedDeviceIDs", ctx, notificationID)}
+}
+
+func (_c *MockNotificationRepository_FindLoggedDeviceIDs_Call) Run(run func(ctx context.Context, notificationID uuid.UUID)) *MockNotificationRepository_FindLoggedDeviceIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockNotificationRepository_FindLoggedDeviceIDs_Call) Return(uuids []uuid.UUID, err error) *MockNotificationRepository_FindLoggedDeviceIDs_Call {
+	_c.Call.Return(uuids, err)
+	return _c
+}
+
+func (_c *MockNotificationRepository_FindLoggedDeviceIDs_Call) RunAndReturn(run func(ctx context.Context, notificationID uuid.UUID) ([]uuid.UUID, error)) *MockNotificationRepository_FindLoggedDeviceIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FindNotificationByID provides a mock function for the type MockNotificationRepository
 func (_mock *MockNotificationRepository) FindNotificationByID(ctx context.Context, id uuid.UUID) (*entity.MerchantLocationNotification, error) {
 	ret := _mock.Called(ctx, id)
@@ -278,6 +648,86 @@ func (_c *MockNotificationRepository_FindNotificationByID_Call) RunAndReturn(run
 	return _c
 }
 
+// FindNotificationLogsByUser provides a mock function for the type MockNotificationRepository
+func (_mock *MockNotificationRepository) FindNotificationLogsByUser(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]*entity.ReceivedNotification, error) {
+	ret := _mock.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindNotificationLogsByUser")
+	}
+
+	var r0 []*entity.ReceivedNotification
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) ([]*entity.ReceivedNotification, error)); ok {
+		return returnFunc(ctx, userID, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) []*entity.ReceivedNotification); ok {
+		r0 = returnFunc(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.ReceivedNotification)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, int, int) error); ok {
+		r1 = returnFunc(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockNotificationRepository_FindNotificationLogsByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindNotificationLogsByUser'
+type MockNotificationRepository_FindNotificationLogsByUser_Call struct {
+	*mock.Call
+}
+
+// FindNotificationLogsByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - limit int
+//   - offset int
+func (_e *MockNotificationRepository_Expecter) FindNotificationLogsByUser(ctx interface{}, userID interface{}, limit interface{}, offset interface{}) *MockNotificationRepository_FindNotificationLogsByUser_Call {
+	return &MockNotificationRepository_FindNotificationLogsByUser_Call{Call: _e.mock.On("FindNotificationLogsByUser", ctx, userID, limit, offset)}
+}
+
+func (_c *MockNotificationRepository_FindNotificationLogsByUser_Call) Run(run func(ctx context.Context, userID uuid.UUID, limit int, offset int)) *MockNotificationRepository_FindNotificationLogsByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockNotificationRepository_FindNotificationLogsByUser_Call) Return(receivedNotifications []*entity.ReceivedNotification, err error) *MockNotificationRepository_FindNotificationLogsByUser_Call {
+	_c.Call.Return(receivedNotifications, err)
+	return _c
+}
+
+func (_c *MockNotificationRepository_FindNotificationLogsByUser_Call) RunAndReturn(run func(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]*entity.ReceivedNotification, error)) *MockNotificationRepository_FindNotificationLogsByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FindNotificationsByMerchant provides a mock function for the type MockNotificationRepository
 func (_mock *MockNotificationRepository) FindNotificationsByMerchant(ctx context.Context, merchantID uuid.UUID, limit int, offset int) ([]*entity.MerchantLocationNotification, error) {
 	ret := _mock.Called(ctx, merchantID, limit, offset)
@@ -358,6 +808,230 @@ func (_c *MockNotificationRepository_FindNotificationsByMerchant_Call) RunAndRet
 	return _c
 }
 
+// MarkNotificationOpened provides a mock function for the type MockNotificationRepository
+func (_mock *MockNotificationRepository) MarkNotificationOpened(ctx context.Context, notificationID uuid.UUID, userID uuid.UUID, openedAt time.Time) (bool, error) {
+	ret := _mock.Called(ctx, notificationID, userID, openedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkNotificationOpened")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, time.Time) (bool, error)); ok {
+		return returnFunc(ctx, notificationID, userID, openedAt)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, time.Time) bool); ok {
+		r0 = returnFunc(ctx, notificationID, userID, openedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, time.Time) error); ok {
+		r1 = returnFunc(ctx, notificationID, userID, openedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockNotificationRepository_MarkNotificationOpened_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkNotificationOpened'
+type MockNotificationRepository_MarkNotificationOpened_Call struct {
+	*mock.Call
+}
+
+// MarkNotificationOpened is a helper method to define mock.On call
+//   - ctx context.Context
+//   - notificationID uuid.UUID
+//   - userID uuid.UUID
+//   - openedAt time.Time
+func (_e *MockNotificationRepository_Expecter) MarkNotificationOpened(ctx interface{}, notificationID interface{}, userID interface{}, openedAt interface{}) *MockNotificationRepository_MarkNotificationOpened_Call {
+	return &MockNotificationRepository_MarkNotificationOpened_Call{Call: _e.mock.On("MarkNotificationOpened", ctx, notificationID, userID, openedAt)}
+}
+
+func (_c *MockNotificationRepository_MarkNotificationOpened_Call) Run(run func(ctx context.Context, notificationID uuid.UUID, userID uuid.UUID, openedAt time.Time)) *MockNotificationRepository_MarkNotificationOpened_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockNotificationRepository_MarkNotificationOpened_Call) Return(b bool, err error) *MockNotificationRepository_MarkNotificationOpened_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockNotificationRepository_MarkNotificationOpened_Call) RunAndReturn(run func(ctx context.Context, notificationID uuid.UUID, userID uuid.UUID, openedAt time.Time) (bool, error)) *MockNotificationRepository_MarkNotificationOpened_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PurgeOldNotificationLogs provides a mock function for the type MockNotificationRepository
+func (_mock *MockNotificationRepository) PurgeOldNotificationLogs(ctx context.Context, olderThan time.Time) (int64, error) {
+	ret := _mock.Called(ctx, olderThan)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeOldNotificationLogs")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return returnFunc(ctx, olderThan)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = returnFunc(ctx, olderThan)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = returnFunc(ctx, olderThan)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockNotificationRepository_PurgeOldNotificationLogs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeOldNotificationLogs'
+type MockNotificationRepository_PurgeOldNotificationLogs_Call struct {
+	*mock.Call
+}
+
+// PurgeOldNotificationLogs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - olderThan time.Time
+func (_e *MockNotificationRepository_Expecter) PurgeOldNotificationLogs(ctx interface{}, olderThan interface{}) *MockNotificationRepository_PurgeOldNotificationLogs_Call {
+	return &MockNotificationRepository_PurgeOldNotificationLogs_Call{Call: _e.mock.On("PurgeOldNotificationLogs", ctx, olderThan)}
+}
+
+func (_c *MockNotificationRepository_PurgeOldNotificationLogs_Call) Run(run func(ctx context.Context, olderThan time.Time)) *MockNotificationRepository_PurgeOldNotificationLogs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockNotificationRepository_PurgeOldNotificationLogs_Call) Return(n int64, err error) *MockNotificationRepository_PurgeOldNotificationLogs_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockNotificationRepository_PurgeOldNotificationLogs_Call) RunAndReturn(run func(ctx context.Context, olderThan time.Time) (int64, error)) *MockNotificationRepository_PurgeOldNotificationLogs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SumBroadcastsByDay provides a mock function for the type MockNotificationRepository
+func (_mock *MockNotificationRepository) SumBroadcastsByDay(ctx context.Context, merchantID uuid.UUID, from time.Time, to time.Time) ([]*entity.BroadcastDayStats, error) {
+	ret := _mock.Called(ctx, merchantID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SumBroadcastsByDay")
+	}
+
+	var r0 []*entity.BroadcastDayStats
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, time.Time) ([]*entity.BroadcastDayStats, error)); ok {
+		return returnFunc(ctx, merchantID, from, to)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, time.Time) []*entity.BroadcastDayStats); ok {
+		r0 = returnFunc(ctx, merchantID, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.BroadcastDayStats)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time, time.Time) error); ok {
+		r1 = returnFunc(ctx, merchantID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockNotificationRepository_SumBroadcastsByDay_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SumBroadcastsByDay'
+type MockNotificationRepository_SumBroadcastsByDay_Call struct {
+	*mock.Call
+}
+
+// SumBroadcastsByDay is a helper method to define mock.On call
+//   - ctx context.Context
+//   - merchantID uuid.UUID
+//   - from time.Time
+//   - to time.Time
+func (_e *MockNotificationRepository_Expecter) SumBroadcastsByDay(ctx interface{}, merchantID interface{}, from interface{}, to interface{}) *MockNotificationRepository_SumBroadcastsByDay_Call {
+	return &MockNotificationRepository_SumBroadcastsByDay_Call{Call: _e.mock.On("SumBroadcastsByDay", ctx, merchantID, from, to)}
+}
+
+func (_c *MockNotificationRepository_SumBroadcastsByDay_Call) Run(run func(ctx context.Context, merchantID uuid.UUID, from time.Time, to time.Time)) *MockNotificationRepository_SumBroadcastsByDay_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockNotificationRepository_SumBroadcastsByDay_Call) Return(broadcastDayStatss []*entity.BroadcastDayStats, err error) *MockNotificationRepository_SumBroadcastsByDay_Call {
+	_c.Call.Return(broadcastDayStatss, err)
+	return _c
+}
+
+func (_c *MockNotificationRepository_SumBroadcastsByDay_Call) RunAndReturn(run func(ctx context.Context, merchantID uuid.UUID, from time.Time, to time.Time) ([]*entity.BroadcastDayStats, error)) *MockNotificationRepository_SumBroadcastsByDay_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateNotificationStatus provides a mock function for the type MockNotificationRepository
 func (_mock *MockNotificationRepository) UpdateNotificationStatus(ctx context.Context, id uuid.UUID, totalSent int, totalFailed int) error {
 	ret := _mock.Called(ctx, id, totalSent, totalFailed)
@@ -426,3 +1100,81 @@ func (_c *MockNotificationRepository_UpdateNotificationStatus_Call) RunAndReturn
 	_c.Call.Return(run)
 	return _c
 }
+
+// UpdateScheduleStatus provides a mock function for the type MockNotificationRepository
+func (_mock *MockNotificationRepository) UpdateScheduleStatus(ctx context.Context, id uuid.UUID, from entity.ScheduleStatus, to entity.ScheduleStatus) (bool, error) {
+	ret := _mock.Called(ctx, id, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateScheduleStatus")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, entity.ScheduleStatus, entity.ScheduleStatus) (bool, error)); ok {
+		return returnFunc(ctx, id, from, to)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, entity.ScheduleStatus, entity.ScheduleStatus) bool); ok {
+		r0 = returnFunc(ctx, id, from, to)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, entity.ScheduleStatus, entity.ScheduleStatus) error); ok {
+		r1 = returnFunc(ctx, id, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockNotificationRepository_UpdateScheduleStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateScheduleStatus'
+type MockNotificationRepository_UpdateScheduleStatus_Call struct {
+	*mock.Call
+}
+
+// UpdateScheduleStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - from entity.ScheduleStatus
+//   - to entity.ScheduleStatus
+func (_e *MockNotificationRepository_Expecter) UpdateScheduleStatus(ctx interface{}, id interface{}, from interface{}, to interface{}) *MockNotificationRepository_UpdateScheduleStatus_Call {
+	return &MockNotificationRepository_UpdateScheduleStatus_Call{Call: _e.mock.On("UpdateScheduleStatus", ctx, id, from, to)}
+}
+
+func (_c *MockNotificationRepository_UpdateScheduleStatus_Call) Run(run func(ctx context.Context, id uuid.UUID, from entity.ScheduleStatus, to entity.ScheduleStatus)) *MockNotificationRepository_UpdateScheduleStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 entity.ScheduleStatus
+		if args[2] != nil {
+			arg2 = args[2].(entity.ScheduleStatus)
+		}
+		var arg3 entity.ScheduleStatus
+		if args[3] != nil {
+			arg3 = args[3].(entity.ScheduleStatus)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockNotificationRepository_UpdateScheduleStatus_Call) Return(_a0 bool, _a1 error) *MockNotificationRepository_UpdateScheduleStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockNotificationRepository_UpdateScheduleStatus_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, from entity.ScheduleStatus, to entity.ScheduleStatus) (bool, error)) *MockNotificationRepository_UpdateScheduleStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
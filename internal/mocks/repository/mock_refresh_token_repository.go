@@ -7,6 +7,7 @@ package repository
 import (
 	"context"
 	"radar/internal/domain/entity"
+	"time"
 
 	"github.com/google/uuid"
 	mock "github.com/stretchr/testify/mock"
@@ -163,20 +164,29 @@ func (_c *MockRefreshTokenRepository_CreateRefreshToken_Call) RunAndReturn(run f
 }
 
 // DeleteExpiredRefreshTokens provides a mock function for the type MockRefreshTokenRepository
-func (_mock *MockRefreshTokenRepository) DeleteExpiredRefreshTokens(ctx context.Context, revokedRetentionDays int) error {
-	ret := _mock.Called(ctx, revokedRetentionDays)
+func (_mock *MockRefreshTokenRepository) DeleteExpiredRefreshTokens(ctx context.Context, now time.Time, revokedRetentionDays int) (int64, error) {
+	ret := _mock.Called(ctx, now, revokedRetentionDays)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteExpiredRefreshTokens")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
-		r0 = returnFunc(ctx, revokedRetentionDays)
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) (int64, error)); ok {
+		return returnFunc(ctx, now, revokedRetentionDays)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) int64); ok {
+		r0 = returnFunc(ctx, now, revokedRetentionDays)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int64)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = returnFunc(ctx, now, revokedRetentionDays)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
 // MockRefreshTokenRepository_DeleteExpiredRefreshTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteExpiredRefreshTokens'
@@ -186,35 +196,41 @@ type MockRefreshTokenRepository_DeleteExpiredRefreshTokens_Call struct {
 
 // DeleteExpiredRefreshTokens is a helper method to define mock.On call
 //   - ctx context.Context
+//   - now time.Time
 //   - revokedRetentionDays int
-func (_e *MockRefreshTokenRepository_Expecter) DeleteExpiredRefreshTokens(ctx interface{}, revokedRetentionDays interface{}) *MockRefreshTokenRepository_DeleteExpiredRefreshTokens_Call {
-	return &MockRefreshTokenRepository_DeleteExpiredRefreshTokens_Call{Call: _e.mock.On("DeleteExpiredRefreshTokens", ctx, revokedRetentionDays)}
+func (_e *MockRefreshTokenRepository_Expecter) DeleteExpiredRefreshTokens(ctx interface{}, now interface{}, revokedRetentionDays interface{}) *MockRefreshTokenRepository_DeleteExpiredRefreshTokens_Call {
+	return &MockRefreshTokenRepository_DeleteExpiredRefreshTokens_Call{Call: _e.mock.On("DeleteExpiredRefreshTokens", ctx, now, revokedRetentionDays)}
 }
 
-func (_c *MockRefreshTokenRepository_DeleteExpiredRefreshTokens_Call) Run(run func(ctx context.Context, revokedRetentionDays int)) *MockRefreshTokenRepository_DeleteExpiredRefreshTokens_Call {
+func (_c *MockRefreshTokenRepository_DeleteExpiredRefreshTokens_Call) Run(run func(ctx context.Context, now time.Time, revokedRetentionDays int)) *MockRefreshTokenRepository_DeleteExpiredRefreshTokens_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 int
+		var arg1 time.Time
 		if args[1] != nil {
-			arg1 = args[1].(int)
+			arg1 = args[1].(time.Time)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockRefreshTokenRepository_DeleteExpiredRefreshTokens_Call) Return(err error) *MockRefreshTokenRepository_DeleteExpiredRefreshTokens_Call {
-	_c.Call.Return(err)
+func (_c *MockRefreshTokenRepository_DeleteExpiredRefreshTokens_Call) Return(n int64, err error) *MockRefreshTokenRepository_DeleteExpiredRefreshTokens_Call {
+	_c.Call.Return(n, err)
 	return _c
 }
 
-func (_c *MockRefreshTokenRepository_DeleteExpiredRefreshTokens_Call) RunAndReturn(run func(ctx context.Context, revokedRetentionDays int) error) *MockRefreshTokenRepository_DeleteExpiredRefreshTokens_Call {
+func (_c *MockRefreshTokenRepository_DeleteExpiredRefreshTokens_Call) RunAndReturn(run func(ctx context.Context, now time.Time, revokedRetentionDays int) (int64, error)) *MockRefreshTokenRepository_DeleteExpiredRefreshTokens_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -776,6 +792,69 @@ func (_c *MockRefreshTokenRepository_RevokeTokenFamily_Call) RunAndReturn(run fu
 	return _c
 }
 
+// TouchRefreshToken provides a mock function for the type MockRefreshTokenRepository
+func (_mock *MockRefreshTokenRepository) TouchRefreshToken(ctx context.Context, tokenHash string, usedAt time.Time) error {
+	ret := _mock.Called(ctx, tokenHash, usedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchRefreshToken")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = returnFunc(ctx, tokenHash, usedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRefreshTokenRepository_TouchRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TouchRefreshToken'
+type MockRefreshTokenRepository_TouchRefreshToken_Call struct {
+	*mock.Call
+}
+
+// TouchRefreshToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenHash string
+//   - usedAt time.Time
+func (_e *MockRefreshTokenRepository_Expecter) TouchRefreshToken(ctx interface{}, tokenHash interface{}, usedAt interface{}) *MockRefreshTokenRepository_TouchRefreshToken_Call {
+	return &MockRefreshTokenRepository_TouchRefreshToken_Call{Call: _e.mock.On("TouchRefreshToken", ctx, tokenHash, usedAt)}
+}
+
+func (_c *MockRefreshTokenRepository_TouchRefreshToken_Call) Run(run func(ctx context.Context, tokenHash string, usedAt time.Time)) *MockRefreshTokenRepository_TouchRefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRefreshTokenRepository_TouchRefreshToken_Call) Return(err error) *MockRefreshTokenRepository_TouchRefreshToken_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRefreshTokenRepository_TouchRefreshToken_Call) RunAndReturn(run func(ctx context.Context, tokenHash string, usedAt time.Time) error) *MockRefreshTokenRepository_TouchRefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateRefreshToken provides a mock function for the type MockRefreshTokenRepository
 func (_mock *MockRefreshTokenRepository) UpdateRefreshToken(ctx context.Context, token *entity.RefreshToken) error {
 	ret := _mock.Called(ctx, token)
@@ -7,6 +7,8 @@ package repository
 import (
 	"context"
 	"radar/internal/domain/entity"
+	"radar/internal/domain/repository"
+	"time"
 
 	"github.com/google/uuid"
 	mock "github.com/stretchr/testify/mock"
@@ -154,8 +156,8 @@ func (_c *MockSubscriptionRepository_DeleteSubscription_Call) RunAndReturn(run f
 }
 
 // FindDevicesForUsers provides a mock function for the type MockSubscriptionRepository
-func (_mock *MockSubscriptionRepository) FindDevicesForUsers(ctx context.Context, userIDs []uuid.UUID, healthyWindowDays int) ([]*entity.UserDevice, error) {
-	ret := _mock.Called(ctx, userIDs, healthyWindowDays)
+func (_mock *MockSubscriptionRepository) FindDevicesForUsers(ctx context.Context, userIDs []uuid.UUID, healthyWindowDays int, target repository.DeviceTargetFilter) ([]*entity.UserDevice, error) {
+	ret := _mock.Called(ctx, userIDs, healthyWindowDays, target)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FindDevicesForUsers")
@@ -163,18 +165,18 @@ func (_mock *MockSubscriptionRepository) FindDevicesForUsers(ctx context.Context
 
 	var r0 []*entity.UserDevice
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []uuid.UUID, int) ([]*entity.UserDevice, error)); ok {
-		return returnFunc(ctx, userIDs, healthyWindowDays)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []uuid.UUID, int, repository.DeviceTargetFilter) ([]*entity.UserDevice, error)); ok {
+		return returnFunc(ctx, userIDs, healthyWindowDays, target)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []uuid.UUID, int) []*entity.UserDevice); ok {
-		r0 = returnFunc(ctx, userIDs, healthyWindowDays)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []uuid.UUID, int, repository.DeviceTargetFilter) []*entity.UserDevice); ok {
+		r0 = returnFunc(ctx, userIDs, healthyWindowDays, target)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*entity.UserDevice)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, []uuid.UUID, int) error); ok {
-		r1 = returnFunc(ctx, userIDs, healthyWindowDays)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []uuid.UUID, int, repository.DeviceTargetFilter) error); ok {
+		r1 = returnFunc(ctx, userIDs, healthyWindowDays, target)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -190,11 +192,12 @@ type MockSubscriptionRepository_FindDevicesForUsers_Call struct {
 //   - ctx context.Context
 //   - userIDs []uuid.UUID
 //   - healthyWindowDays int
-func (_e *MockSubscriptionRepository_Expecter) FindDevicesForUsers(ctx interface{}, userIDs interface{}, healthyWindowDays interface{}) *MockSubscriptionRepository_FindDevicesForUsers_Call {
-	return &MockSubscriptionRepository_FindDevicesForUsers_Call{Call: _e.mock.On("FindDevicesForUsers", ctx, userIDs, healthyWindowDays)}
+//   - target repository.DeviceTargetFilter
+func (_e *MockSubscriptionRepository_Expecter) FindDevicesForUsers(ctx interface{}, userIDs interface{}, healthyWindowDays interface{}, target interface{}) *MockSubscriptionRepository_FindDevicesForUsers_Call {
+	return &MockSubscriptionRepository_FindDevicesForUsers_Call{Call: _e.mock.On("FindDevicesForUsers", ctx, userIDs, healthyWindowDays, target)}
 }
 
-func (_c *MockSubscriptionRepository_FindDevicesForUsers_Call) Run(run func(ctx context.Context, userIDs []uuid.UUID, healthyWindowDays int)) *MockSubscriptionRepository_FindDevicesForUsers_Call {
+func (_c *MockSubscriptionRepository_FindDevicesForUsers_Call) Run(run func(ctx context.Context, userIDs []uuid.UUID, healthyWindowDays int, target repository.DeviceTargetFilter)) *MockSubscriptionRepository_FindDevicesForUsers_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -208,10 +211,15 @@ func (_c *MockSubscriptionRepository_FindDevicesForUsers_Call) Run(run func(ctx
 		if args[2] != nil {
 			arg2 = args[2].(int)
 		}
+		var arg3 repository.DeviceTargetFilter
+		if args[3] != nil {
+			arg3 = args[3].(repository.DeviceTargetFilter)
+		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
@@ -222,7 +230,81 @@ func (_c *MockSubscriptionRepository_FindDevicesForUsers_Call) Return(userDevice
 	return _c
 }
 
-func (_c *MockSubscriptionRepository_FindDevicesForUsers_Call) RunAndReturn(run func(ctx context.Context, userIDs []uuid.UUID, healthyWindowDays int) ([]*entity.UserDevice, error)) *MockSubscriptionRepository_FindDevicesForUsers_Call {
+func (_c *MockSubscriptionRepository_FindDevicesForUsers_Call) RunAndReturn(run func(ctx context.Context, userIDs []uuid.UUID, healthyWindowDays int, target repository.DeviceTargetFilter) ([]*entity.UserDevice, error)) *MockSubscriptionRepository_FindDevicesForUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindSubscriberAddressesByTile provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) FindSubscriberAddressesByTile(ctx context.Context, sources []repository.SubscriberSource, zoom int) ([]*entity.SubscriberTileBucket, error) {
+	ret := _mock.Called(ctx, sources, zoom)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindSubscriberAddressesByTile")
+	}
+
+	var r0 []*entity.SubscriberTileBucket
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []repository.SubscriberSource, int) ([]*entity.SubscriberTileBucket, error)); ok {
+		return returnFunc(ctx, sources, zoom)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []repository.SubscriberSource, int) []*entity.SubscriberTileBucket); ok {
+		r0 = returnFunc(ctx, sources, zoom)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.SubscriberTileBucket)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []repository.SubscriberSource, int) error); ok {
+		r1 = returnFunc(ctx, sources, zoom)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionRepository_FindSubscriberAddressesByTile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindSubscriberAddressesByTile'
+type MockSubscriptionRepository_FindSubscriberAddressesByTile_Call struct {
+	*mock.Call
+}
+
+// FindSubscriberAddressesByTile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sources []repository.SubscriberSource
+//   - zoom int
+func (_e *MockSubscriptionRepository_Expecter) FindSubscriberAddressesByTile(ctx interface{}, sources interface{}, zoom interface{}) *MockSubscriptionRepository_FindSubscriberAddressesByTile_Call {
+	return &MockSubscriptionRepository_FindSubscriberAddressesByTile_Call{Call: _e.mock.On("FindSubscriberAddressesByTile", ctx, sources, zoom)}
+}
+
+func (_c *MockSubscriptionRepository_FindSubscriberAddressesByTile_Call) Run(run func(ctx context.Context, sources []repository.SubscriberSource, zoom int)) *MockSubscriptionRepository_FindSubscriberAddressesByTile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []repository.SubscriberSource
+		if args[1] != nil {
+			arg1 = args[1].([]repository.SubscriberSource)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_FindSubscriberAddressesByTile_Call) Return(_a0 []*entity.SubscriberTileBucket, _a1 error) *MockSubscriptionRepository_FindSubscriberAddressesByTile_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_FindSubscriberAddressesByTile_Call) RunAndReturn(run func(ctx context.Context, sources []repository.SubscriberSource, zoom int) ([]*entity.SubscriberTileBucket, error)) *MockSubscriptionRepository_FindSubscriberAddressesByTile_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -739,6 +821,69 @@ func (_c *MockSubscriptionRepository_FindSubscriptionsByUser_Call) RunAndReturn(
 	return _c
 }
 
+// UpdateGlobalSnoozedUntil provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) UpdateGlobalSnoozedUntil(ctx context.Context, userID uuid.UUID, until *time.Time) error {
+	ret := _mock.Called(ctx, userID, until)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateGlobalSnoozedUntil")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *time.Time) error); ok {
+		r0 = returnFunc(ctx, userID, until)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSubscriptionRepository_UpdateGlobalSnoozedUntil_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateGlobalSnoozedUntil'
+type MockSubscriptionRepository_UpdateGlobalSnoozedUntil_Call struct {
+	*mock.Call
+}
+
+// UpdateGlobalSnoozedUntil is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - until *time.Time
+func (_e *MockSubscriptionRepository_Expecter) UpdateGlobalSnoozedUntil(ctx interface{}, userID interface{}, until interface{}) *MockSubscriptionRepository_UpdateGlobalSnoozedUntil_Call {
+	return &MockSubscriptionRepository_UpdateGlobalSnoozedUntil_Call{Call: _e.mock.On("UpdateGlobalSnoozedUntil", ctx, userID, until)}
+}
+
+func (_c *MockSubscriptionRepository_UpdateGlobalSnoozedUntil_Call) Run(run func(ctx context.Context, userID uuid.UUID, until *time.Time)) *MockSubscriptionRepository_UpdateGlobalSnoozedUntil_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 *time.Time
+		if args[2] != nil {
+			arg2 = args[2].(*time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_UpdateGlobalSnoozedUntil_Call) Return(err error) *MockSubscriptionRepository_UpdateGlobalSnoozedUntil_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_UpdateGlobalSnoozedUntil_Call) RunAndReturn(run func(ctx context.Context, userID uuid.UUID, until *time.Time) error) *MockSubscriptionRepository_UpdateGlobalSnoozedUntil_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateNotificationRadius provides a mock function for the type MockSubscriptionRepository
 func (_mock *MockSubscriptionRepository) UpdateNotificationRadius(ctx context.Context, id uuid.UUID, radius float64) error {
 	ret := _mock.Called(ctx, id, radius)
@@ -802,6 +947,141 @@ func (_c *MockSubscriptionRepository_UpdateNotificationRadius_Call) RunAndReturn
 	return _c
 }
 
+// UpdateNotificationRadiusByUser provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) UpdateNotificationRadiusByUser(ctx context.Context, userID uuid.UUID, radius float64) (int64, error) {
+	ret := _mock.Called(ctx, userID, radius)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateNotificationRadiusByUser")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, float64) (int64, error)); ok {
+		return returnFunc(ctx, userID, radius)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, float64) int64); ok {
+		r0 = returnFunc(ctx, userID, radius)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, float64) error); ok {
+		r1 = returnFunc(ctx, userID, radius)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionRepository_UpdateNotificationRadiusByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateNotificationRadiusByUser'
+type MockSubscriptionRepository_UpdateNotificationRadiusByUser_Call struct {
+	*mock.Call
+}
+
+// UpdateNotificationRadiusByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - radius float64
+func (_e *MockSubscriptionRepository_Expecter) UpdateNotificationRadiusByUser(ctx interface{}, userID interface{}, radius interface{}) *MockSubscriptionRepository_UpdateNotificationRadiusByUser_Call {
+	return &MockSubscriptionRepository_UpdateNotificationRadiusByUser_Call{Call: _e.mock.On("UpdateNotificationRadiusByUser", ctx, userID, radius)}
+}
+
+func (_c *MockSubscriptionRepository_UpdateNotificationRadiusByUser_Call) Run(run func(ctx context.Context, userID uuid.UUID, radius float64)) *MockSubscriptionRepository_UpdateNotificationRadiusByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 float64
+		if args[2] != nil {
+			arg2 = args[2].(float64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_UpdateNotificationRadiusByUser_Call) Return(n int64, err error) *MockSubscriptionRepository_UpdateNotificationRadiusByUser_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_UpdateNotificationRadiusByUser_Call) RunAndReturn(run func(ctx context.Context, userID uuid.UUID, radius float64) (int64, error)) *MockSubscriptionRepository_UpdateNotificationRadiusByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateSnoozedUntil provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) UpdateSnoozedUntil(ctx context.Context, id uuid.UUID, until *time.Time) error {
+	ret := _mock.Called(ctx, id, until)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSnoozedUntil")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *time.Time) error); ok {
+		r0 = returnFunc(ctx, id, until)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSubscriptionRepository_UpdateSnoozedUntil_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateSnoozedUntil'
+type MockSubscriptionRepository_UpdateSnoozedUntil_Call struct {
+	*mock.Call
+}
+
+// UpdateSnoozedUntil is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - until *time.Time
+func (_e *MockSubscriptionRepository_Expecter) UpdateSnoozedUntil(ctx interface{}, id interface{}, until interface{}) *MockSubscriptionRepository_UpdateSnoozedUntil_Call {
+	return &MockSubscriptionRepository_UpdateSnoozedUntil_Call{Call: _e.mock.On("UpdateSnoozedUntil", ctx, id, until)}
+}
+
+func (_c *MockSubscriptionRepository_UpdateSnoozedUntil_Call) Run(run func(ctx context.Context, id uuid.UUID, until *time.Time)) *MockSubscriptionRepository_UpdateSnoozedUntil_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 *time.Time
+		if args[2] != nil {
+			arg2 = args[2].(*time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_UpdateSnoozedUntil_Call) Return(err error) *MockSubscriptionRepository_UpdateSnoozedUntil_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_UpdateSnoozedUntil_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, until *time.Time) error) *MockSubscriptionRepository_UpdateSnoozedUntil_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateSubscriptionStatus provides a mock function for the type MockSubscriptionRepository
 func (_mock *MockSubscriptionRepository) UpdateSubscriptionStatus(ctx context.Context, id uuid.UUID, isActive bool) error {
 	ret := _mock.Called(ctx, id, isActive)
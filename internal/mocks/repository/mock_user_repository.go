@@ -289,6 +289,78 @@ func (_c *MockUserRepository_FindByID_Call) RunAndReturn(run func(ctx context.Co
 	return _c
 }
 
+// IsBusinessLicenseTaken provides a mock function for the type MockUserRepository
+func (_mock *MockUserRepository) IsBusinessLicenseTaken(ctx context.Context, businessLicense string, excludeUserID uuid.UUID) (bool, error) {
+	ret := _mock.Called(ctx, businessLicense, excludeUserID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsBusinessLicenseTaken")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) (bool, error)); ok {
+		return returnFunc(ctx, businessLicense, excludeUserID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) bool); ok {
+		r0 = returnFunc(ctx, businessLicense, excludeUserID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, businessLicense, excludeUserID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockUserRepository_IsBusinessLicenseTaken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsBusinessLicenseTaken'
+type MockUserRepository_IsBusinessLicenseTaken_Call struct {
+	*mock.Call
+}
+
+// IsBusinessLicenseTaken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - businessLicense string
+//   - excludeUserID uuid.UUID
+func (_e *MockUserRepository_Expecter) IsBusinessLicenseTaken(ctx interface{}, businessLicense interface{}, excludeUserID interface{}) *MockUserRepository_IsBusinessLicenseTaken_Call {
+	return &MockUserRepository_IsBusinessLicenseTaken_Call{Call: _e.mock.On("IsBusinessLicenseTaken", ctx, businessLicense, excludeUserID)}
+}
+
+func (_c *MockUserRepository_IsBusinessLicenseTaken_Call) Run(run func(ctx context.Context, businessLicense string, excludeUserID uuid.UUID)) *MockUserRepository_IsBusinessLicenseTaken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 uuid.UUID
+		if args[2] != nil {
+			arg2 = args[2].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockUserRepository_IsBusinessLicenseTaken_Call) Return(b bool, err error) *MockUserRepository_IsBusinessLicenseTaken_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockUserRepository_IsBusinessLicenseTaken_Call) RunAndReturn(run func(ctx context.Context, businessLicense string, excludeUserID uuid.UUID) (bool, error)) *MockUserRepository_IsBusinessLicenseTaken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Update provides a mock function for the type MockUserRepository
 func (_mock *MockUserRepository) Update(ctx context.Context, user *entity.User) error {
 	ret := _mock.Called(ctx, user)
@@ -6,6 +6,7 @@ package service
 
 import (
 	"context"
+	"radar/internal/domain/service"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -38,43 +39,31 @@ func (_m *MockNotificationService) EXPECT() *MockNotificationService_Expecter {
 }
 
 // SendBatchNotification provides a mock function for the type MockNotificationService
-func (_mock *MockNotificationService) SendBatchNotification(ctx context.Context, tokens []string, title string, body string, data map[string]string) (int, int, []string, error) {
+func (_mock *MockNotificationService) SendBatchNotification(ctx context.Context, tokens []string, title string, body string, data map[string]string) ([]service.TokenResult, error) {
 	ret := _mock.Called(ctx, tokens, title, body, data)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendBatchNotification")
 	}
 
-	var r0 int
-	var r1 int
-	var r2 []string
-	var r3 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, string, string, map[string]string) (int, int, []string, error)); ok {
+	var r0 []service.TokenResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, string, string, map[string]string) ([]service.TokenResult, error)); ok {
 		return returnFunc(ctx, tokens, title, body, data)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, string, string, map[string]string) int); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, string, string, map[string]string) []service.TokenResult); ok {
 		r0 = returnFunc(ctx, tokens, title, body, data)
 	} else {
-		r0 = ret.Get(0).(int)
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, []string, string, string, map[string]string) int); ok {
-		r1 = returnFunc(ctx, tokens, title, body, data)
-	} else {
-		r1 = ret.Get(1).(int)
-	}
-	if returnFunc, ok := ret.Get(2).(func(context.Context, []string, string, string, map[string]string) []string); ok {
-		r2 = returnFunc(ctx, tokens, title, body, data)
-	} else {
-		if ret.Get(2) != nil {
-			r2 = ret.Get(2).([]string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.TokenResult)
 		}
 	}
-	if returnFunc, ok := ret.Get(3).(func(context.Context, []string, string, string, map[string]string) error); ok {
-		r3 = returnFunc(ctx, tokens, title, body, data)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string, string, string, map[string]string) error); ok {
+		r1 = returnFunc(ctx, tokens, title, body, data)
 	} else {
-		r3 = ret.Error(3)
+		r1 = ret.Error(1)
 	}
-	return r0, r1, r2, r3
+	return r0, r1
 }
 
 // MockNotificationService_SendBatchNotification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendBatchNotification'
@@ -125,12 +114,12 @@ func (_c *MockNotificationService_SendBatchNotification_Call) Run(run func(ctx c
 	return _c
 }
 
-func (_c *MockNotificationService_SendBatchNotification_Call) Return(successCount int, failureCount int, invalidTokens []string, err error) *MockNotificationService_SendBatchNotification_Call {
-	_c.Call.Return(successCount, failureCount, invalidTokens, err)
+func (_c *MockNotificationService_SendBatchNotification_Call) Return(results []service.TokenResult, err error) *MockNotificationService_SendBatchNotification_Call {
+	_c.Call.Return(results, err)
 	return _c
 }
 
-func (_c *MockNotificationService_SendBatchNotification_Call) RunAndReturn(run func(ctx context.Context, tokens []string, title string, body string, data map[string]string) (int, int, []string, error)) *MockNotificationService_SendBatchNotification_Call {
+func (_c *MockNotificationService_SendBatchNotification_Call) RunAndReturn(run func(ctx context.Context, tokens []string, title string, body string, data map[string]string) ([]service.TokenResult, error)) *MockNotificationService_SendBatchNotification_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+
+	"radar/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// CreatedAPIToken is a newly created API token together with its raw value.
+// The raw value is only available here; later reads return the token without it.
+type CreatedAPIToken struct {
+	*entity.APIToken
+
+	Token string `json:"token"` // Raw token to send as "Authorization: Bearer <token>"
+}
+
+// APITokenUsecase defines the interface for merchant API token use cases
+type APITokenUsecase interface {
+	// CreateAPIToken issues a token that lets the merchant's own servers publish notifications.
+	CreateAPIToken(ctx context.Context, merchantID uuid.UUID, name string) (*CreatedAPIToken, error)
+
+	// ListAPITokens returns the merchant's tokens, newest first, including revoked ones.
+	ListAPITokens(ctx context.Context, merchantID uuid.UUID) ([]*entity.APIToken, error)
+
+	// RevokeAPIToken revokes one of the merchant's active tokens.
+	RevokeAPIToken(ctx context.Context, merchantID, tokenID uuid.UUID) error
+
+	// AuthenticateAPIToken resolves a raw token to the active API token it belongs to.
+	// It returns ErrInvalidToken for unknown and revoked tokens.
+	AuthenticateAPIToken(ctx context.Context, rawToken string) (*entity.APIToken, error)
+}
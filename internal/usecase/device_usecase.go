@@ -11,9 +11,10 @@ import (
 
 // DeviceInfo represents device information for registration
 type DeviceInfo struct {
-	FCMToken string `json:"fcm_token"`
-	DeviceID string `json:"device_id"`
-	Platform string `json:"platform"`
+	FCMToken   string `json:"fcm_token"`
+	DeviceID   string `json:"device_id"`
+	Platform   string `json:"platform"`
+	AppVersion string `json:"app_version"`
 }
 
 // DeviceHealthStatus is the client-facing health state of a user device.
@@ -48,6 +49,9 @@ type DeviceUsecase interface {
 	// GetDeviceHealth retrieves computed device health information for a user.
 	GetDeviceHealth(ctx context.Context, userID uuid.UUID) ([]*DeviceHealthInfo, error)
 
+	// UpdateNotificationSettings replaces a device's overrides of the user's notification preferences.
+	UpdateNotificationSettings(ctx context.Context, userID, deviceID uuid.UUID, settings entity.DeviceNotificationSettings) error
+
 	// DeactivateDevice deactivates a device without soft-deleting it.
 	DeactivateDevice(ctx context.Context, userID, deviceID uuid.UUID) error
 }
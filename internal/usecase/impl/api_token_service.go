@@ -0,0 +1,104 @@
+package impl
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
+	"radar/internal/domain/repository"
+	"radar/internal/domain/service"
+	"radar/internal/usecase"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+// apiTokenSecretBytes is the amount of randomness in each raw API token
+const apiTokenSecretBytes = 32
+
+type apiTokenService struct {
+	apiTokenRepo repository.APITokenRepository
+	tokenService service.TokenService
+	clock        service.Clock
+}
+
+// APITokenServiceParams holds dependencies for APITokenService, injected by Fx.
+type APITokenServiceParams struct {
+	fx.In
+
+	APITokenRepo repository.APITokenRepository
+	TokenService service.TokenService
+}
+
+// NewAPITokenService creates a new merchant API token service instance
+func NewAPITokenService(params APITokenServiceParams) usecase.APITokenUsecase {
+	return &apiTokenService{
+		apiTokenRepo: params.APITokenRepo,
+		tokenService: params.TokenService,
+		clock:        service.ClockFunc(time.Now),
+	}
+}
+
+// CreateAPIToken issues a publish-scoped token and stores only its hash
+func (s *apiTokenService) CreateAPIToken(ctx context.Context, merchantID uuid.UUID, name string) (*usecase.CreatedAPIToken, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domainerrors.ErrValidationFailed.WithDetails("API token name is required")
+	}
+
+	secret := make([]byte, apiTokenSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generate api token: %w", err)
+	}
+	rawToken := entity.APITokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	token := &entity.APIToken{
+		MerchantID: merchantID,
+		Name:       name,
+		TokenHash:  s.tokenService.HashToken(rawToken),
+		Scopes:     []entity.APITokenScope{entity.APITokenScopePublishNotifications},
+	}
+	if err := s.apiTokenRepo.CreateAPIToken(ctx, token); err != nil {
+		return nil, err
+	}
+
+	return &usecase.CreatedAPIToken{APIToken: token, Token: rawToken}, nil
+}
+
+// ListAPITokens returns the merchant's tokens without their hashes
+func (s *apiTokenService) ListAPITokens(ctx context.Context, merchantID uuid.UUID) ([]*entity.APIToken, error) {
+	return s.apiTokenRepo.ListAPITokensByMerchant(ctx, merchantID)
+}
+
+// RevokeAPIToken revokes one of the merchant's active tokens
+func (s *apiTokenService) RevokeAPIToken(ctx context.Context, merchantID, tokenID uuid.UUID) error {
+	return s.apiTokenRepo.RevokeAPIToken(ctx, merchantID, tokenID, s.clock.Now())
+}
+
+// AuthenticateAPIToken looks the token up by its hash and rejects unknown and revoked tokens alike
+func (s *apiTokenService) AuthenticateAPIToken(ctx context.Context, rawToken string) (*entity.APIToken, error) {
+	if !entity.IsAPIToken(rawToken) {
+		return nil, domainerrors.ErrInvalidToken
+	}
+
+	token, err := s.apiTokenRepo.FindAPITokenByHash(ctx, s.tokenService.HashToken(rawToken))
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrAPITokenNotFound) {
+			return nil, replaceWithSourceStack(err, domainerrors.ErrInvalidToken)
+		}
+
+		return nil, err
+	}
+
+	if token.IsRevoked() {
+		return nil, domainerrors.ErrInvalidToken
+	}
+
+	return token, nil
+}
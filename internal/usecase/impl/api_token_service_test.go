@@ -0,0 +1,114 @@
+package impl
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
+	mockRepo "radar/internal/mocks/repository"
+	mockSvc "radar/internal/mocks/service"
+	"radar/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// apiTokenServiceFixtures holds all test dependencies for API token service tests.
+type apiTokenServiceFixtures struct {
+	service      usecase.APITokenUsecase
+	apiTokenRepo *mockRepo.MockAPITokenRepository
+	tokenService *mockSvc.MockTokenService
+}
+
+func createTestAPITokenService(t *testing.T) apiTokenServiceFixtures {
+	apiTokenRepo := mockRepo.NewMockAPITokenRepository(t)
+	tokenService := mockSvc.NewMockTokenService(t)
+	service := NewAPITokenService(APITokenServiceParams{
+		APITokenRepo: apiTokenRepo,
+		TokenService: tokenService,
+	})
+
+	return apiTokenServiceFixtures{
+		service:      service,
+		apiTokenRepo: apiTokenRepo,
+		tokenService: tokenService,
+	}
+}
+
+func TestAPITokenService_CreateAPIToken_StoresOnlyTheHash(t *testing.T) {
+	fx := createTestAPITokenService(t)
+	ctx := context.Background()
+	merchantID := uuid.New()
+
+	var rawToken string
+	fx.tokenService.EXPECT().HashToken(mock.Anything).RunAndReturn(func(token string) string {
+		rawToken = token
+
+		return "hashed"
+	})
+	fx.apiTokenRepo.EXPECT().
+		CreateAPIToken(ctx, mock.MatchedBy(func(token *entity.APIToken) bool {
+			return token.MerchantID == merchantID && token.Name == "POS" && token.TokenHash == "hashed" &&
+				token.HasScope(entity.APITokenScopePublishNotifications)
+		})).
+		Return(nil)
+
+	created, err := fx.service.CreateAPIToken(ctx, merchantID, " POS ")
+
+	require.NoError(t, err)
+	assert.Equal(t, rawToken, created.Token)
+	assert.True(t, strings.HasPrefix(created.Token, entity.APITokenPrefix))
+}
+
+func TestAPITokenService_AuthenticateAPIToken(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		token   *entity.APIToken
+		findErr error
+		wantErr error
+	}{
+		{
+			name:  "active token",
+			token: &entity.APIToken{ID: uuid.New(), Scopes: []entity.APITokenScope{entity.APITokenScopePublishNotifications}},
+		},
+		{name: "revoked token", token: &entity.APIToken{ID: uuid.New(), RevokedAt: &revokedAt}, wantErr: domainerrors.ErrInvalidToken},
+		{name: "unknown token", findErr: domainerrors.ErrAPITokenNotFound, wantErr: domainerrors.ErrInvalidToken},
+		{name: "lookup failure", findErr: domainerrors.ErrPersistenceFailed, wantErr: domainerrors.ErrPersistenceFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := createTestAPITokenService(t)
+			ctx := context.Background()
+
+			fx.tokenService.EXPECT().HashToken("nnr_secret").Return("hashed")
+			fx.apiTokenRepo.EXPECT().FindAPITokenByHash(ctx, "hashed").Return(tt.token, tt.findErr)
+
+			token, err := fx.service.AuthenticateAPIToken(ctx, "nnr_secret")
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, token)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.token, token)
+		})
+	}
+}
+
+func TestAPITokenService_AuthenticateAPIToken_RejectsJWTWithoutLookup(t *testing.T) {
+	fx := createTestAPITokenService(t)
+
+	_, err := fx.service.AuthenticateAPIToken(context.Background(), "eyJhbGciOiJIUzI1NiJ9.payload.signature")
+
+	require.ErrorIs(t, err, domainerrors.ErrInvalidToken)
+}
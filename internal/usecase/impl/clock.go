@@ -0,0 +1,16 @@
+package impl
+
+import (
+	"time"
+
+	"radar/internal/domain/service"
+)
+
+// clockOrDefault returns the injected clock, falling back to the real wall clock
+func clockOrDefault(clock service.Clock) service.Clock {
+	if clock == nil {
+		return service.ClockFunc(time.Now)
+	}
+
+	return clock
+}
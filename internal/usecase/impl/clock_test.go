@@ -0,0 +1,51 @@
+package impl
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"radar/internal/domain/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock is a manually advanced clock for deterministic time-dependent tests
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func TestClockOrDefault(t *testing.T) {
+	fixed := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.Equal(t, fixed, clockOrDefault(service.ClockFunc(func() time.Time { return fixed })).Now())
+
+	before := time.Now()
+	assert.False(t, clockOrDefault(nil).Now().Before(before))
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	assert.Equal(t, 90, retryAfterSeconds(now.Add(90*time.Second), now))
+	assert.Equal(t, 2, retryAfterSeconds(now.Add(1500*time.Millisecond), now))
+	assert.Equal(t, 1, retryAfterSeconds(now.Add(-time.Minute), now))
+}
@@ -80,6 +80,23 @@ func (s *deviceService) GetDeviceHealth(ctx context.Context, userID uuid.UUID) (
 	return result, nil
 }
 
+// UpdateNotificationSettings replaces a device's overrides of the user's notification preferences
+func (s *deviceService) UpdateNotificationSettings(
+	ctx context.Context,
+	userID, deviceID uuid.UUID,
+	settings entity.DeviceNotificationSettings,
+) error {
+	if err := s.ensureOwnedDevice(ctx, userID, deviceID); err != nil {
+		return err
+	}
+
+	if err := s.deviceRepo.UpdateDeviceNotificationSettings(ctx, deviceID, settings); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // DeactivateDevice deactivates a device without soft-deleting it.
 func (s *deviceService) DeactivateDevice(ctx context.Context, userID, deviceID uuid.UUID) error {
 	if err := s.ensureOwnedDevice(ctx, userID, deviceID); err != nil {
@@ -116,6 +116,44 @@ func TestDeviceService_RegisterDevice_UpdateExisting(t *testing.T) {
 	assert.Equal(t, "new-fcm-token", device.FCMToken)
 }
 
+func TestDeviceService_RegisterDevice_UpdatesAppVersion(t *testing.T) {
+	fx := createTestDeviceService(t)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	deviceID := uuid.New()
+	existingDevice := &entity.UserDevice{
+		ID:         deviceID,
+		UserID:     userID,
+		FCMToken:   "fcm-token",
+		DeviceID:   "device-123",
+		Platform:   "android",
+		AppVersion: "2.3.0",
+		IsActive:   true,
+	}
+
+	fx.deviceRepo.EXPECT().
+		FindDeviceByUserAndDeviceID(ctx, userID, "device-123").
+		Return(existingDevice, nil)
+	fx.deviceRepo.EXPECT().
+		UpdateFCMToken(ctx, deviceID, "fcm-token").
+		Return(nil)
+	fx.deviceRepo.EXPECT().
+		UpdateDeviceClientInfo(ctx, deviceID, "android", "2.4.0").
+		Return(nil)
+	fx.deviceRepo.EXPECT().
+		FindDeviceByID(ctx, deviceID).
+		Return(existingDevice, nil)
+
+	_, err := fx.service.RegisterDevice(ctx, userID, &usecase.DeviceInfo{
+		FCMToken:   "fcm-token",
+		DeviceID:   "device-123",
+		Platform:   "android",
+		AppVersion: " 2.4.0 ",
+	})
+	require.NoError(t, err)
+}
+
 func TestDeviceService_RegisterDevice_NormalizesDeviceInfo(t *testing.T) {
 	fx := createTestDeviceService(t)
 
@@ -182,7 +220,16 @@ func TestDeviceService_RegisterDevice_InvalidDeviceInfo(t *testing.T) {
 			deviceInfo: &usecase.DeviceInfo{
 				FCMToken: "token-123",
 				DeviceID: "device-123",
-				Platform: "web",
+				Platform: "windows",
+			},
+		},
+		{
+			name: "invalid app version",
+			deviceInfo: &usecase.DeviceInfo{
+				FCMToken:   "token-123",
+				DeviceID:   "device-123",
+				Platform:   "ios",
+				AppVersion: "latest",
 			},
 		},
 	}
@@ -272,3 +319,27 @@ func TestDeviceService_DeactivateDevice_Success(t *testing.T) {
 	err := fx.service.DeactivateDevice(ctx, userID, deviceID)
 	require.NoError(t, err)
 }
+
+func TestDeviceService_UpdateNotificationSettings(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	deviceID := uuid.New()
+	disabled := false
+	settings := entity.DeviceNotificationSettings{NotificationsEnabled: &disabled}
+
+	t.Run("owned device", func(t *testing.T) {
+		fx := createTestDeviceService(t)
+		fx.deviceRepo.EXPECT().FindDeviceByID(ctx, deviceID).Return(&entity.UserDevice{ID: deviceID, UserID: userID}, nil)
+		fx.deviceRepo.EXPECT().UpdateDeviceNotificationSettings(ctx, deviceID, settings).Return(nil)
+
+		require.NoError(t, fx.service.UpdateNotificationSettings(ctx, userID, deviceID, settings))
+	})
+
+	t.Run("another user's device", func(t *testing.T) {
+		fx := createTestDeviceService(t)
+		fx.deviceRepo.EXPECT().FindDeviceByID(ctx, deviceID).Return(&entity.UserDevice{ID: deviceID, UserID: uuid.New()}, nil)
+
+		err := fx.service.UpdateNotificationSettings(ctx, userID, deviceID, settings)
+		require.ErrorIs(t, err, domainerrors.ErrDeviceOwnershipViolation)
+	})
+}
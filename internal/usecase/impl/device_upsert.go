@@ -8,6 +8,7 @@ import (
 
 	"radar/internal/domain/entity"
 	domainerrors "radar/internal/domain/errors"
+	"radar/internal/domain/policy"
 	"radar/internal/domain/repository"
 	"radar/internal/usecase"
 
@@ -17,6 +18,7 @@ import (
 const (
 	devicePlatformIOS     = "ios"
 	devicePlatformAndroid = "android"
+	devicePlatformWeb     = "web"
 )
 
 func upsertUserDevice(
@@ -44,6 +46,7 @@ func upsertUserDevice(
 		FCMToken:         deviceInfo.FCMToken,
 		DeviceID:         deviceInfo.DeviceID,
 		Platform:         deviceInfo.Platform,
+		AppVersion:       deviceInfo.AppVersion,
 		IsActive:         true,
 		TokenRefreshedAt: now,
 		CreatedAt:        now,
@@ -74,6 +77,9 @@ func upsertExistingDevice(
 	if err := deviceRepo.UpdateFCMToken(ctx, device.ID, deviceInfo.FCMToken); err != nil {
 		return nil, err
 	}
+	if err := updateDeviceClientInfo(ctx, deviceRepo, device, deviceInfo); err != nil {
+		return nil, err
+	}
 	if !device.IsActive {
 		if err := deviceRepo.SetDeviceActive(ctx, device.ID, true); err != nil {
 			return nil, err
@@ -100,10 +106,31 @@ func restoreDeletedDevice(
 	if err := deviceRepo.RestoreAndUpdateDevice(ctx, userID, deletedDevice.ID, deviceInfo.FCMToken); err != nil {
 		return nil, err
 	}
+	if err := updateDeviceClientInfo(ctx, deviceRepo, deletedDevice, deviceInfo); err != nil {
+		return nil, err
+	}
 
 	return deviceRepo.FindDeviceByUserAndDeviceID(ctx, userID, deviceInfo.DeviceID)
 }
 
+// updateDeviceClientInfo stores a changed platform or app version; a client that omits its version keeps the stored one
+func updateDeviceClientInfo(
+	ctx context.Context,
+	deviceRepo repository.DeviceRepository,
+	device *entity.UserDevice,
+	deviceInfo *usecase.DeviceInfo,
+) error {
+	appVersion := deviceInfo.AppVersion
+	if appVersion == "" {
+		appVersion = device.AppVersion
+	}
+	if device.Platform == deviceInfo.Platform && device.AppVersion == appVersion {
+		return nil
+	}
+
+	return deviceRepo.UpdateDeviceClientInfo(ctx, device.ID, deviceInfo.Platform, appVersion)
+}
+
 func validateDeviceInfo(deviceInfo *usecase.DeviceInfo) error {
 	if deviceInfo == nil {
 		return domainerrors.ErrValidationFailed.WithDetails("device info is required")
@@ -112,6 +139,7 @@ func validateDeviceInfo(deviceInfo *usecase.DeviceInfo) error {
 	deviceInfo.FCMToken = strings.TrimSpace(deviceInfo.FCMToken)
 	deviceInfo.DeviceID = strings.TrimSpace(deviceInfo.DeviceID)
 	deviceInfo.Platform = strings.ToLower(strings.TrimSpace(deviceInfo.Platform))
+	deviceInfo.AppVersion = strings.TrimSpace(deviceInfo.AppVersion)
 
 	if deviceInfo.FCMToken == "" {
 		return domainerrors.ErrValidationFailed.WithDetails("fcm_token is required")
@@ -119,8 +147,11 @@ func validateDeviceInfo(deviceInfo *usecase.DeviceInfo) error {
 	if deviceInfo.DeviceID == "" {
 		return domainerrors.ErrValidationFailed.WithDetails("device_id is required")
 	}
-	if deviceInfo.Platform != devicePlatformIOS && deviceInfo.Platform != devicePlatformAndroid {
-		return domainerrors.ErrValidationFailed.WithDetails("platform must be ios or android")
+	if deviceInfo.Platform != devicePlatformIOS && deviceInfo.Platform != devicePlatformAndroid && deviceInfo.Platform != devicePlatformWeb {
+		return domainerrors.ErrValidationFailed.WithDetails("platform must be ios, android, or web")
+	}
+	if deviceInfo.AppVersion != "" && !policy.ValidAppVersion(deviceInfo.AppVersion) {
+		return domainerrors.ErrValidationFailed.WithDetails("app_version must be a dotted version such as 2.4.0")
 	}
 
 	return nil
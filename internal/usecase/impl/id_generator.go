@@ -0,0 +1,16 @@
+package impl
+
+import (
+	"radar/internal/domain/service"
+
+	"github.com/google/uuid"
+)
+
+// idGeneratorOrDefault returns the injected generator, falling back to random UUIDs
+func idGeneratorOrDefault(generator service.IDGenerator) service.IDGenerator {
+	if generator == nil {
+		return service.IDGeneratorFunc(uuid.New)
+	}
+
+	return generator
+}
@@ -0,0 +1,47 @@
+package impl
+
+import (
+	"sync"
+	"testing"
+
+	"radar/internal/domain/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sequenceIDGenerator hands out the given IDs in order and fails the test once they run out
+type sequenceIDGenerator struct {
+	t   *testing.T
+	mu  sync.Mutex
+	ids []uuid.UUID
+}
+
+func newSequenceIDGenerator(t *testing.T, ids ...uuid.UUID) *sequenceIDGenerator {
+	t.Helper()
+
+	return &sequenceIDGenerator{t: t, ids: ids}
+}
+
+func (g *sequenceIDGenerator) NewID() uuid.UUID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	require.NotEmpty(g.t, g.ids, "ID generator sequence exhausted")
+	id := g.ids[0]
+	g.ids = g.ids[1:]
+
+	return id
+}
+
+func TestIDGeneratorOrDefault(t *testing.T) {
+	injected := service.IDGeneratorFunc(func() uuid.UUID { return uuid.Nil })
+	assert.Equal(t, uuid.Nil, idGeneratorOrDefault(injected).NewID())
+
+	fallback := idGeneratorOrDefault(nil)
+	first := fallback.NewID()
+	second := fallback.NewID()
+	assert.NotEqual(t, uuid.Nil, first)
+	assert.NotEqual(t, first, second)
+}
@@ -3,15 +3,19 @@ package impl
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
 	"time"
 
 	"radar/config"
 	"radar/internal/domain/entity"
 	domainerrors "radar/internal/domain/errors"
 	"radar/internal/domain/repository"
+	"radar/internal/domain/service"
 	"radar/internal/usecase"
 
 	"github.com/google/uuid"
+	"github.com/paulmach/orb/geojson"
 	"go.uber.org/fx"
 )
 
@@ -26,6 +30,9 @@ var (
 
 type locationService struct {
 	addressRepo repository.AddressRepository
+	txManager   repository.TransactionManager
+	idGenerator service.IDGenerator
+	routingSvc  usecase.RoutingUsecase
 	config      *config.Config
 }
 
@@ -34,6 +41,9 @@ type LocationServiceParams struct {
 	fx.In
 
 	AddressRepo repository.AddressRepository
+	TxManager   repository.TransactionManager
+	IDGenerator service.IDGenerator `optional:"true"`
+	RoutingSvc  usecase.RoutingUsecase
 	Config      *config.Config
 }
 
@@ -46,6 +56,9 @@ func NewLocationService(params LocationServiceParams) usecase.LocationUsecase {
 
 	return &locationService{
 		addressRepo: params.AddressRepo,
+		txManager:   params.TxManager,
+		idGenerator: idGeneratorOrDefault(params.IDGenerator),
+		routingSvc:  params.RoutingSvc,
 		config:      params.Config,
 	}
 }
@@ -74,10 +87,10 @@ func (s *locationService) applyAddressUpdates(address *entity.Address, input *us
 		address.FullAddress = *input.FullAddress
 	}
 	if input.Latitude != nil {
-		address.Latitude = *input.Latitude
+		address.Latitude = s.roundCoordinate(*input.Latitude)
 	}
 	if input.Longitude != nil {
-		address.Longitude = *input.Longitude
+		address.Longitude = s.roundCoordinate(*input.Longitude)
 	}
 	if input.IsPrimary != nil {
 		address.IsPrimary = *input.IsPrimary
@@ -85,6 +98,12 @@ func (s *locationService) applyAddressUpdates(address *entity.Address, input *us
 	if input.IsActive != nil {
 		address.IsActive = *input.IsActive
 	}
+	switch {
+	case input.ClearGeofence:
+		address.GeofencePolygon = nil
+	case len(input.GeofencePolygon) > 0:
+		address.GeofencePolygon = input.GeofencePolygon
+	}
 	address.UpdatedAt = time.Now()
 }
 
@@ -113,6 +132,25 @@ func (s *locationService) DeleteMerchantLocation(ctx context.Context, merchantID
 	return s.deleteLocation(ctx, merchantID, locationID, entity.OwnerTypeMerchantProfile)
 }
 
+// RestoreMerchantLocation restores a deleted location for a merchant, as a non-primary location
+func (s *locationService) RestoreMerchantLocation(ctx context.Context, merchantID, locationID uuid.UUID) (*entity.Address, error) {
+	return s.restoreLocation(ctx, merchantID, locationID, entity.OwnerTypeMerchantProfile, s.config.LocationNotification.MerchantMaxLocations)
+}
+
+// PreviewRoute calculates the route between two points on profile, including its geometry.
+// A profile the routing backend does not serve is a validation error.
+func (s *locationService) PreviewRoute(ctx context.Context, profile string, source, target usecase.Coordinate) (*usecase.RouteResult, error) {
+	result, err := s.routingSvc.CalculateRoute(ctx, profile, source, target)
+	if errors.Is(err, usecase.ErrUnknownRoutingProfile) {
+		return nil, domainerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("unknown routing profile %q", profile))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("routing service failed: %w", err)
+	}
+
+	return result, nil
+}
+
 func (s *locationService) getLocations(ctx context.Context, ownerID uuid.UUID, ownerType entity.OwnerType) ([]*entity.Address, error) {
 	addresses, err := s.addressRepo.FindAddressesByOwner(ctx, ownerID, ownerType)
 	if err != nil {
@@ -132,6 +170,9 @@ func (s *locationService) addLocation(
 	if input == nil {
 		return nil, domainerrors.ErrValidationFailed.WithDetails("location input is required")
 	}
+	if err := checkGeofenceOwner(ownerType, input.GeofencePolygon); err != nil {
+		return nil, err
+	}
 
 	count, err := s.addressRepo.CountAddressesByOwner(ctx, ownerID, ownerType)
 	if err != nil {
@@ -159,6 +200,9 @@ func (s *locationService) updateLocation(
 	if input == nil {
 		return nil, domainerrors.ErrValidationFailed.WithDetails("location update input is required")
 	}
+	if err := checkGeofenceOwner(ownerType, input.GeofencePolygon); err != nil {
+		return nil, err
+	}
 
 	address, err := s.findOwnedAddress(ctx, ownerID, locationID, ownerType)
 	if err != nil {
@@ -189,6 +233,57 @@ func (s *locationService) deleteLocation(
 	return nil
 }
 
+func (s *locationService) restoreLocation(
+	ctx context.Context,
+	ownerID, locationID uuid.UUID,
+	ownerType entity.OwnerType,
+	maxLocations int,
+) (*entity.Address, error) {
+	var restored *entity.Address
+	err := s.txManager.Execute(ctx, func(repoFactory repository.RepositoryFactory) error {
+		addressRepo := repoFactory.AddressRepo()
+
+		address, err := addressRepo.FindDeletedAddressByID(ctx, locationID)
+		if err != nil {
+			return err
+		}
+		if address.OwnerID != ownerID || address.OwnerType != ownerType {
+			return ErrUnauthorized
+		}
+
+		// A restored location counts toward the limit like a new one; holding the owner's row
+		// keeps concurrent restores from both passing the check
+		if err := addressRepo.LockOwner(ctx, ownerID, ownerType); err != nil {
+			return err
+		}
+		count, err := addressRepo.CountAddressesByOwner(ctx, ownerID, ownerType)
+		if err != nil {
+			return err
+		}
+		if count >= int64(maxLocations) {
+			return ErrLocationLimitReached
+		}
+
+		if err := addressRepo.RestoreAddress(ctx, locationID); err != nil {
+			return err
+		}
+
+		// Reload so the caller sees the stored primary flag and update time
+		restored, err = addressRepo.FindAddressByID(ctx, locationID)
+
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, domainerrors.ErrAddressNotFound) {
+			return nil, ErrLocationNotFound
+		}
+
+		return nil, err
+	}
+
+	return restored, nil
+}
+
 func (s *locationService) findOwnedAddress(
 	ctx context.Context,
 	ownerID, locationID uuid.UUID,
@@ -212,16 +307,34 @@ func (s *locationService) findOwnedAddress(
 
 func (s *locationService) newAddress(ownerID uuid.UUID, ownerType entity.OwnerType, input *usecase.AddLocationInput) *entity.Address {
 	return &entity.Address{
-		ID:          uuid.New(),
+		ID:          s.idGenerator.NewID(),
 		OwnerID:     ownerID,
 		OwnerType:   ownerType,
 		Label:       input.Label,
 		FullAddress: input.FullAddress,
-		Latitude:    input.Latitude,
-		Longitude:   input.Longitude,
+		Latitude:    s.roundCoordinate(input.Latitude),
+		Longitude:   s.roundCoordinate(input.Longitude),
 		IsPrimary:   input.IsPrimary,
 		IsActive:    input.IsActive,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
+
+		GeofencePolygon: input.GeofencePolygon,
+	}
+}
+
+// checkGeofenceOwner rejects a geofence on a merchant location, since only subscriber addresses are matched by one
+func checkGeofenceOwner(ownerType entity.OwnerType, polygon geojson.Polygon) error {
+	if len(polygon) > 0 && ownerType != entity.OwnerTypeUserProfile {
+		return domainerrors.ErrInvalidGeofence.WithDetails("geofence polygons are only supported on user locations")
 	}
+
+	return nil
+}
+
+// roundCoordinate rounds a coordinate to the configured number of decimal places
+func (s *locationService) roundCoordinate(value float64) float64 {
+	scale := math.Pow10(*s.config.LocationNotification.CoordinatePrecision)
+
+	return math.Round(value*scale) / scale
 }
@@ -172,6 +172,61 @@ func TestLocationService_DeleteMerchantLocation_Unauthorized(t *testing.T) {
 	assert.Equal(t, ErrUnauthorized, err)
 }
 
+func TestLocationService_RestoreMerchantLocation_NotDeleted(t *testing.T) {
+	fx := createTestLocationService(t, nil)
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationID := uuid.New()
+
+	fx.onExecute(t, ctx, fx.addressRepo)
+	fx.addressRepo.EXPECT().
+		FindDeletedAddressByID(ctx, locationID).
+		Return(nil, domainerrors.ErrAddressNotFound)
+
+	_, err := fx.service.RestoreMerchantLocation(ctx, merchantID, locationID)
+	assert.Equal(t, ErrLocationNotFound, err)
+}
+
+func TestLocationService_RestoreMerchantLocation_Unauthorized(t *testing.T) {
+	fx := createTestLocationService(t, nil)
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationID := uuid.New()
+
+	fx.onExecute(t, ctx, fx.addressRepo)
+	fx.addressRepo.EXPECT().
+		FindDeletedAddressByID(ctx, locationID).
+		Return(&entity.Address{ID: locationID, OwnerID: uuid.New(), OwnerType: entity.OwnerTypeMerchantProfile}, nil)
+
+	_, err := fx.service.RestoreMerchantLocation(ctx, merchantID, locationID)
+	assert.Equal(t, ErrUnauthorized, err)
+}
+
+func TestLocationService_RestoreMerchantLocation_LimitReached(t *testing.T) {
+	fx := createTestLocationService(t, nil)
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationID := uuid.New()
+
+	fx.onExecute(t, ctx, fx.addressRepo)
+	fx.addressRepo.EXPECT().
+		FindDeletedAddressByID(ctx, locationID).
+		Return(&entity.Address{ID: locationID, OwnerID: merchantID, OwnerType: entity.OwnerTypeMerchantProfile}, nil)
+	fx.addressRepo.EXPECT().
+		LockOwner(ctx, merchantID, entity.OwnerTypeMerchantProfile).
+		Return(nil)
+	fx.addressRepo.EXPECT().
+		CountAddressesByOwner(ctx, merchantID, entity.OwnerTypeMerchantProfile).
+		Return(int64(10), nil)
+
+	_, err := fx.service.RestoreMerchantLocation(ctx, merchantID, locationID)
+	assert.Equal(t, ErrLocationLimitReached, err)
+	fx.addressRepo.AssertNotCalled(t, "RestoreAddress", mock.Anything, mock.Anything)
+}
+
 func TestLocationService_DeleteMerchantLocation_WrongOwnerType(t *testing.T) {
 	fx := createTestLocationService(t, nil)
 
@@ -2,14 +2,20 @@ package impl
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"radar/config"
 	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
+	"radar/internal/domain/repository"
 	mockRepo "radar/internal/mocks/repository"
 	"radar/internal/usecase"
 
 	"github.com/google/uuid"
+	"github.com/paulmach/orb/geojson"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -19,10 +25,12 @@ import (
 type locationServiceFixtures struct {
 	service     usecase.LocationUsecase
 	addressRepo *mockRepo.MockAddressRepository
+	txManager   *mockRepo.MockTransactionManager
 }
 
 func createTestLocationService(t *testing.T, cfg *config.Config) locationServiceFixtures {
 	addressRepo := mockRepo.NewMockAddressRepository(t)
+	txManager := mockRepo.NewMockTransactionManager(t)
 	if cfg == nil {
 		cfg = &config.Config{
 			LocationNotification: &config.LocationNotificationConfig{
@@ -33,15 +41,30 @@ func createTestLocationService(t *testing.T, cfg *config.Config) locationService
 	}
 	service := NewLocationService(LocationServiceParams{
 		AddressRepo: addressRepo,
+		TxManager:   txManager,
 		Config:      cfg,
 	})
 
 	return locationServiceFixtures{
 		service:     service,
 		addressRepo: addressRepo,
+		txManager:   txManager,
 	}
 }
 
+// onExecute runs the transaction body against a factory whose address repository is txAddressRepo.
+func (fx locationServiceFixtures) onExecute(t *testing.T, ctx context.Context, txAddressRepo *mockRepo.MockAddressRepository) {
+	fx.txManager.EXPECT().
+		Execute(ctx, mock.AnythingOfType("func(repository.RepositoryFactory) error")).
+		RunAndReturn(func(ctx context.Context, fn func(repository.RepositoryFactory) error) error {
+			factory := mockRepo.NewMockRepositoryFactory(t)
+			factory.EXPECT().AddressRepo().Return(txAddressRepo)
+
+			return fn(factory)
+		}).
+		Once()
+}
+
 func TestLocationService_GetUserLocations(t *testing.T) {
 	fx := createTestLocationService(t, nil)
 
@@ -90,6 +113,36 @@ func TestLocationService_AddUserLocation_Success(t *testing.T) {
 	assert.Equal(t, input.Label, address.Label)
 }
 
+func TestLocationService_AddUserLocation_UsesInjectedIDGenerator(t *testing.T) {
+	addressRepo := mockRepo.NewMockAddressRepository(t)
+	addressID := uuid.New()
+	service := NewLocationService(LocationServiceParams{
+		AddressRepo: addressRepo,
+		IDGenerator: newSequenceIDGenerator(t, addressID),
+		Config: &config.Config{
+			LocationNotification: &config.LocationNotificationConfig{UserMaxLocations: 5, MerchantMaxLocations: 10},
+		},
+	})
+
+	ctx := context.Background()
+	userID := uuid.New()
+	input := &usecase.AddLocationInput{Label: "Home", FullAddress: "123 Main St", Latitude: 25.0, Longitude: 121.0}
+
+	addressRepo.EXPECT().
+		CountAddressesByOwner(ctx, userID, entity.OwnerTypeUserProfile).
+		Return(int64(0), nil)
+	addressRepo.EXPECT().
+		CreateAddress(ctx, mock.MatchedBy(func(address *entity.Address) bool {
+			return address.ID == addressID
+		})).
+		Return(nil)
+
+	address, err := service.AddUserLocation(ctx, userID, input)
+
+	require.NoError(t, err)
+	assert.Equal(t, addressID, address.ID)
+}
+
 func TestLocationService_UpdateUserLocation_Success(t *testing.T) {
 	fx := createTestLocationService(t, nil)
 
@@ -122,6 +175,48 @@ func TestLocationService_UpdateUserLocation_Success(t *testing.T) {
 	assert.Equal(t, newLabel, address.Label)
 }
 
+func TestLocationService_UpdateUserLocation_SetsAndClearsGeofence(t *testing.T) {
+	fx := createTestLocationService(t, nil)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	locationID := uuid.New()
+	polygon := geojson.Polygon{{{121.55, 25.02}, {121.58, 25.02}, {121.58, 25.05}, {121.55, 25.02}}}
+	existingAddress := &entity.Address{ID: locationID, OwnerID: userID, OwnerType: entity.OwnerTypeUserProfile, Label: "Home"}
+
+	fx.addressRepo.EXPECT().FindAddressByID(ctx, locationID).Return(existingAddress, nil).Twice()
+	fx.addressRepo.EXPECT().UpdateAddress(ctx, existingAddress).Return(nil).Twice()
+
+	address, err := fx.service.UpdateUserLocation(ctx, userID, locationID, &usecase.UpdateLocationInput{GeofencePolygon: polygon})
+	require.NoError(t, err)
+	assert.Equal(t, polygon, address.GeofencePolygon)
+
+	// Updating other fields keeps the geofence; clearing removes it
+	label := "Office"
+	address, err = fx.service.UpdateUserLocation(ctx, userID, locationID, &usecase.UpdateLocationInput{Label: &label})
+	require.NoError(t, err)
+	assert.Equal(t, polygon, address.GeofencePolygon)
+
+	fx.addressRepo.EXPECT().FindAddressByID(ctx, locationID).Return(existingAddress, nil).Once()
+	fx.addressRepo.EXPECT().UpdateAddress(ctx, existingAddress).Return(nil).Once()
+	address, err = fx.service.UpdateUserLocation(ctx, userID, locationID, &usecase.UpdateLocationInput{ClearGeofence: true})
+	require.NoError(t, err)
+	assert.False(t, address.HasGeofence())
+}
+
+func TestLocationService_MerchantLocation_RejectsGeofence(t *testing.T) {
+	fx := createTestLocationService(t, nil)
+
+	ctx := context.Background()
+	polygon := geojson.Polygon{{{121.55, 25.02}, {121.58, 25.02}, {121.58, 25.05}, {121.55, 25.02}}}
+
+	_, err := fx.service.AddMerchantLocation(ctx, uuid.New(), &usecase.AddLocationInput{Label: "Store", GeofencePolygon: polygon})
+	require.ErrorIs(t, err, domainerrors.ErrInvalidGeofence)
+
+	_, err = fx.service.UpdateMerchantLocation(ctx, uuid.New(), uuid.New(), &usecase.UpdateLocationInput{GeofencePolygon: polygon})
+	require.ErrorIs(t, err, domainerrors.ErrInvalidGeofence)
+}
+
 func TestLocationService_DeleteUserLocation_Success(t *testing.T) {
 	fx := createTestLocationService(t, nil)
 
@@ -255,6 +350,61 @@ func TestLocationService_DeleteMerchantLocation_Success(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestLocationService_RestoreMerchantLocation_AfterDelete(t *testing.T) {
+	fx := createTestLocationService(t, nil)
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationID := uuid.New()
+	deletedAddress := &entity.Address{
+		ID:        locationID,
+		OwnerID:   merchantID,
+		OwnerType: entity.OwnerTypeMerchantProfile,
+		Label:     "Night Market Stall",
+		IsPrimary: true,
+		IsActive:  true,
+	}
+
+	restoredAt := time.Now()
+	txAddressRepo := mockRepo.NewMockAddressRepository(t)
+	fx.onExecute(t, ctx, txAddressRepo)
+	txAddressRepo.EXPECT().
+		FindDeletedAddressByID(ctx, locationID).
+		Return(deletedAddress, nil).
+		Once()
+	txAddressRepo.EXPECT().
+		LockOwner(ctx, merchantID, entity.OwnerTypeMerchantProfile).
+		Return(nil).
+		Once()
+	txAddressRepo.EXPECT().
+		CountAddressesByOwner(ctx, merchantID, entity.OwnerTypeMerchantProfile).
+		Return(int64(3), nil).
+		Once()
+	txAddressRepo.EXPECT().
+		RestoreAddress(ctx, locationID).
+		Return(nil).
+		Once()
+	txAddressRepo.EXPECT().
+		FindAddressByID(ctx, locationID).
+		Return(&entity.Address{
+			ID:        locationID,
+			OwnerID:   merchantID,
+			OwnerType: entity.OwnerTypeMerchantProfile,
+			Label:     "Night Market Stall",
+			IsActive:  true,
+			UpdatedAt: restoredAt,
+		}, nil).
+		Once()
+
+	restored, err := fx.service.RestoreMerchantLocation(ctx, merchantID, locationID)
+
+	require.NoError(t, err)
+	assert.Equal(t, locationID, restored.ID)
+	assert.Equal(t, "Night Market Stall", restored.Label)
+	assert.False(t, restored.IsPrimary, "a restored location must not reclaim primary")
+	assert.Equal(t, restoredAt, restored.UpdatedAt, "the restored location is reloaded after the restore")
+}
+
 func TestLocationService_UpdateMerchantLocation_AllFields(t *testing.T) {
 	fx := createTestLocationService(t, nil)
 
@@ -324,3 +474,101 @@ func TestLocationService_NewLocationService_NilConfig(t *testing.T) {
 
 	assert.NotNil(t, service)
 }
+
+func TestLocationService_AddUserLocation_RoundsCoordinates(t *testing.T) {
+	tests := []struct {
+		name      string
+		precision *int
+		wantLat   float64
+		wantLng   float64
+	}{
+		{name: "default precision", wantLat: 25.03301, wantLng: 121.56543},
+		{name: "configured precision", precision: new(3), wantLat: 25.033, wantLng: 121.565},
+		{name: "whole degrees", precision: new(0), wantLat: 25, wantLng: 122},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := createTestLocationService(t, &config.Config{
+				LocationNotification: &config.LocationNotificationConfig{
+					UserMaxLocations:    5,
+					CoordinatePrecision: tt.precision,
+				},
+			})
+
+			ctx := context.Background()
+			userID := uuid.New()
+			input := &usecase.AddLocationInput{
+				Label:       "Home",
+				FullAddress: "123 Main St",
+				Latitude:    25.033012345,
+				Longitude:   121.565427891,
+			}
+
+			fx.addressRepo.EXPECT().
+				CountAddressesByOwner(ctx, userID, entity.OwnerTypeUserProfile).
+				Return(int64(0), nil)
+			fx.addressRepo.EXPECT().
+				CreateAddress(ctx, mock.MatchedBy(func(address *entity.Address) bool {
+					return address.Latitude == tt.wantLat && address.Longitude == tt.wantLng
+				})).
+				Return(nil)
+
+			address, err := fx.service.AddUserLocation(ctx, userID, input)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.wantLat, address.Latitude, 1e-12)
+			assert.InDelta(t, tt.wantLng, address.Longitude, 1e-12)
+		})
+	}
+}
+
+func TestLocationService_UpdateMerchantLocation_RoundsCoordinates(t *testing.T) {
+	fx := createTestLocationService(t, nil)
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationID := uuid.New()
+	newLat := 25.047812345
+	newLng := 121.517049999
+
+	fx.addressRepo.EXPECT().
+		FindAddressByID(ctx, locationID).
+		Return(&entity.Address{ID: locationID, OwnerID: merchantID, OwnerType: entity.OwnerTypeMerchantProfile}, nil)
+	fx.addressRepo.EXPECT().
+		UpdateAddress(ctx, mock.AnythingOfType("*entity.Address")).
+		Return(nil)
+
+	address, err := fx.service.UpdateMerchantLocation(ctx, merchantID, locationID, &usecase.UpdateLocationInput{
+		Latitude:  &newLat,
+		Longitude: &newLng,
+	})
+
+	require.NoError(t, err)
+	assert.InDelta(t, 25.04781, address.Latitude, 1e-12)
+	assert.InDelta(t, 121.51705, address.Longitude, 1e-12)
+}
+
+func TestLocationService_PreviewRoute_UnknownProfileIsValidationError(t *testing.T) {
+	service := NewLocationService(LocationServiceParams{
+		AddressRepo: mockRepo.NewMockAddressRepository(t),
+		RoutingSvc:  &failingRoutingService{err: fmt.Errorf("%w: %q", usecase.ErrUnknownRoutingProfile, "driving")},
+	})
+
+	route, err := service.PreviewRoute(context.Background(), "driving", usecase.Coordinate{Lat: 25.03, Lng: 121.56}, usecase.Coordinate{Lat: 25.04, Lng: 121.57})
+
+	require.ErrorIs(t, err, domainerrors.ErrValidationFailed)
+	assert.Nil(t, route)
+}
+
+func TestLocationService_PreviewRoute_WrapsRoutingError(t *testing.T) {
+	routingErr := errors.New("tiles unavailable")
+	service := NewLocationService(LocationServiceParams{
+		AddressRepo: mockRepo.NewMockAddressRepository(t),
+		RoutingSvc:  &failingRoutingService{err: routingErr},
+	})
+
+	route, err := service.PreviewRoute(context.Background(), usecase.DefaultRoutingProfile, usecase.Coordinate{Lat: 25.03, Lng: 121.56}, usecase.Coordinate{Lat: 25.04, Lng: 121.57})
+
+	require.ErrorIs(t, err, routingErr)
+	assert.Nil(t, route)
+}
@@ -87,6 +87,10 @@ func (s *userRepositoryStub) Update(context.Context, *entity.User) error {
 	return nil
 }
 
+func (s *userRepositoryStub) IsBusinessLicenseTaken(context.Context, string, uuid.UUID) (bool, error) {
+	return false, nil
+}
+
 func TestMenuService_CreateMenuItem_Success(t *testing.T) {
 	ctx := context.Background()
 	merchantID := uuid.New()
@@ -0,0 +1,204 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"radar/config"
+	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
+	"radar/internal/domain/repository"
+	"radar/internal/usecase"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+// merchantSettingsCacheTTL bounds how long another instance's update can take to become visible,
+// since invalidation on update only clears the local cache.
+const merchantSettingsCacheTTL = 5 * time.Minute
+
+// merchantSettingsCacheMaxEntries bounds the cache, so reads across many merchants cannot grow it without limit
+const merchantSettingsCacheMaxEntries = 10000
+
+type cachedMerchantFlags struct {
+	flags     usecase.MerchantFlags
+	expiresAt time.Time
+}
+
+type merchantSettingsService struct {
+	settingsRepo repository.MerchantSettingsRepository
+	config       *config.Config
+
+	cacheMu         sync.RWMutex
+	cache           map[uuid.UUID]cachedMerchantFlags
+	cacheMaxEntries int
+}
+
+// MerchantSettingsServiceParams holds dependencies for MerchantSettingsService, injected by Fx.
+type MerchantSettingsServiceParams struct {
+	fx.In
+
+	SettingsRepo repository.MerchantSettingsRepository
+	Config       *config.Config
+}
+
+// NewMerchantSettingsService creates a new merchant settings service instance
+func NewMerchantSettingsService(params MerchantSettingsServiceParams) usecase.MerchantSettingsUsecase {
+	if params.Config == nil {
+		params.Config = &config.Config{}
+	}
+	config.ApplyDefaults(params.Config)
+
+	return &merchantSettingsService{
+		settingsRepo: params.SettingsRepo,
+		config:       params.Config,
+		cache:        make(map[uuid.UUID]cachedMerchantFlags),
+
+		cacheMaxEntries: merchantSettingsCacheMaxEntries,
+	}
+}
+
+// GetMerchantFlags returns the merchant's effective settings, serving repeated reads from a short-lived cache
+func (s *merchantSettingsService) GetMerchantFlags(ctx context.Context, merchantID uuid.UUID) (*usecase.MerchantFlags, error) {
+	if flags, ok := s.cachedFlags(merchantID); ok {
+		return flags, nil
+	}
+
+	settings, err := s.settingsRepo.FindMerchantSettings(ctx, merchantID)
+	if err != nil && !errors.Is(err, domainerrors.ErrMerchantSettingsNotFound) {
+		return nil, err
+	}
+
+	flags := s.resolveFlags(merchantID, settings)
+	s.storeFlags(flags)
+
+	return &flags, nil
+}
+
+// UpdateMerchantSettings validates and saves the merchant's overrides, then drops the cached flags
+func (s *merchantSettingsService) UpdateMerchantSettings(ctx context.Context, settings *entity.MerchantSettings) (*usecase.MerchantFlags, error) {
+	if err := s.validateSettings(settings); err != nil {
+		return nil, err
+	}
+
+	if err := s.settingsRepo.UpsertMerchantSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	s.invalidate(settings.MerchantID)
+
+	flags := s.resolveFlags(settings.MerchantID, settings)
+
+	return &flags, nil
+}
+
+// resolveFlags applies service-wide defaults to any setting the merchant has not overridden
+func (s *merchantSettingsService) resolveFlags(merchantID uuid.UUID, settings *entity.MerchantSettings) usecase.MerchantFlags {
+	flags := usecase.MerchantFlags{
+		MerchantID:            merchantID,
+		MaxNotificationRadius: s.config.LocationNotification.MaxRadius,
+		DistanceUnit:          usecase.DistanceUnitKilometers,
+	}
+	if settings == nil {
+		return flags
+	}
+
+	if settings.MaxNotificationRadius != nil {
+		flags.MaxNotificationRadius = *settings.MaxNotificationRadius
+	}
+	if settings.BroadcastsPerHour != nil {
+		flags.BroadcastsPerHour = *settings.BroadcastsPerHour
+	}
+	if settings.DistanceUnit != nil {
+		if unit, ok := usecase.ParseDistanceUnit(*settings.DistanceUnit); ok {
+			flags.DistanceUnit = unit
+		}
+	}
+	if settings.RotateNotificationTemplates != nil {
+		flags.RotateNotificationTemplates = *settings.RotateNotificationTemplates
+	}
+
+	return flags
+}
+
+func (s *merchantSettingsService) validateSettings(settings *entity.MerchantSettings) error {
+	if settings == nil || settings.MerchantID == uuid.Nil {
+		return domainerrors.ErrInvalidInput.WithDetails("merchant id is required")
+	}
+
+	if radius := settings.MaxNotificationRadius; radius != nil {
+		if *radius <= 0 || *radius > s.config.LocationNotification.MaxRadius {
+			return domainerrors.ErrInvalidNotificationRadius.WithDetails("max notification radius must be within the service limit")
+		}
+	}
+	if limit := settings.BroadcastsPerHour; limit != nil && *limit < 0 {
+		return domainerrors.ErrInvalidInput.WithDetails("broadcasts per hour must not be negative")
+	}
+	if settings.DistanceUnit != nil {
+		unit, ok := usecase.ParseDistanceUnit(*settings.DistanceUnit)
+		if !ok {
+			return domainerrors.ErrInvalidInput.WithDetails("unsupported distance unit")
+		}
+		normalized := string(unit)
+		settings.DistanceUnit = &normalized
+	}
+
+	return nil
+}
+
+func (s *merchantSettingsService) cachedFlags(merchantID uuid.UUID) (*usecase.MerchantFlags, bool) {
+	s.cacheMu.RLock()
+	defer s.cacheMu.RUnlock()
+
+	entry, ok := s.cache[merchantID]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+
+	flags := entry.flags
+
+	return &flags, true
+}
+
+func (s *merchantSettingsService) storeFlags(flags usecase.MerchantFlags) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	now := time.Now()
+	if _, ok := s.cache[flags.MerchantID]; !ok && len(s.cache) >= s.cacheMaxEntries {
+		s.evictLocked(now)
+	}
+	s.cache[flags.MerchantID] = cachedMerchantFlags{flags: flags, expiresAt: now.Add(merchantSettingsCacheTTL)}
+}
+
+// evictLocked makes room for one entry in a full cache: it drops the expired entries, or the entry closest
+// to expiring when none has expired. All entries share one TTL, so that is the one stored longest ago.
+// The caller must hold the write lock.
+func (s *merchantSettingsService) evictLocked(now time.Time) {
+	var oldestID uuid.UUID
+	var oldestExpiry time.Time
+	for merchantID, entry := range s.cache {
+		if now.After(entry.expiresAt) {
+			delete(s.cache, merchantID)
+
+			continue
+		}
+		if oldestExpiry.IsZero() || entry.expiresAt.Before(oldestExpiry) {
+			oldestID, oldestExpiry = merchantID, entry.expiresAt
+		}
+	}
+
+	if len(s.cache) >= s.cacheMaxEntries {
+		delete(s.cache, oldestID)
+	}
+}
+
+func (s *merchantSettingsService) invalidate(merchantID uuid.UUID) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	delete(s.cache, merchantID)
+}
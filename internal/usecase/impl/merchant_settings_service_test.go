@@ -0,0 +1,172 @@
+package impl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"radar/config"
+	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
+	mockRepo "radar/internal/mocks/repository"
+	"radar/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// merchantSettingsServiceFixtures holds all test dependencies for merchant settings service tests.
+type merchantSettingsServiceFixtures struct {
+	service      usecase.MerchantSettingsUsecase
+	settingsRepo *mockRepo.MockMerchantSettingsRepository
+}
+
+func createTestMerchantSettingsService(t *testing.T) merchantSettingsServiceFixtures {
+	settingsRepo := mockRepo.NewMockMerchantSettingsRepository(t)
+	service := NewMerchantSettingsService(MerchantSettingsServiceParams{
+		SettingsRepo: settingsRepo,
+		Config: &config.Config{
+			LocationNotification: &config.LocationNotificationConfig{MaxRadius: 5000},
+		},
+	})
+
+	return merchantSettingsServiceFixtures{
+		service:      service,
+		settingsRepo: settingsRepo,
+	}
+}
+
+func TestMerchantSettingsService_GetMerchantFlags_DefaultsWhenUnset(t *testing.T) {
+	fx := createTestMerchantSettingsService(t)
+	ctx := context.Background()
+	merchantID := uuid.New()
+
+	fx.settingsRepo.EXPECT().FindMerchantSettings(ctx, merchantID).Return(nil, domainerrors.ErrMerchantSettingsNotFound)
+
+	flags, err := fx.service.GetMerchantFlags(ctx, merchantID)
+
+	require.NoError(t, err)
+	assert.Equal(t, &usecase.MerchantFlags{
+		MerchantID:            merchantID,
+		MaxNotificationRadius: 5000,
+		DistanceUnit:          usecase.DistanceUnitKilometers,
+	}, flags)
+}
+
+func TestMerchantSettingsService_GetMerchantFlags_ReadsOverrides(t *testing.T) {
+	fx := createTestMerchantSettingsService(t)
+	ctx := context.Background()
+	merchantID := uuid.New()
+	radius := 2000.0
+	rotate := true
+
+	// Only radius and rotation are overridden; the rest keep their defaults
+	fx.settingsRepo.EXPECT().FindMerchantSettings(ctx, merchantID).Return(&entity.MerchantSettings{
+		MerchantID:                  merchantID,
+		MaxNotificationRadius:       &radius,
+		RotateNotificationTemplates: &rotate,
+	}, nil).Once()
+
+	flags, err := fx.service.GetMerchantFlags(ctx, merchantID)
+	require.NoError(t, err)
+	assert.InDelta(t, 2000.0, flags.MaxNotificationRadius, 1e-9)
+	assert.True(t, flags.RotateNotificationTemplates)
+	assert.Zero(t, flags.BroadcastsPerHour)
+	assert.Equal(t, usecase.DistanceUnitKilometers, flags.DistanceUnit)
+
+	// A second read is served from the cache (the repository expectation is Once)
+	cached, err := fx.service.GetMerchantFlags(ctx, merchantID)
+	require.NoError(t, err)
+	assert.Equal(t, flags, cached)
+}
+
+func TestMerchantSettingsService_UpdateMerchantSettings_InvalidatesCache(t *testing.T) {
+	fx := createTestMerchantSettingsService(t)
+	ctx := context.Background()
+	merchantID := uuid.New()
+	limit := 3
+	unit := "MI"
+
+	fx.settingsRepo.EXPECT().FindMerchantSettings(ctx, merchantID).Return(nil, domainerrors.ErrMerchantSettingsNotFound).Once()
+	before, err := fx.service.GetMerchantFlags(ctx, merchantID)
+	require.NoError(t, err)
+	assert.Zero(t, before.BroadcastsPerHour)
+
+	update := &entity.MerchantSettings{MerchantID: merchantID, BroadcastsPerHour: &limit, DistanceUnit: &unit}
+	fx.settingsRepo.EXPECT().UpsertMerchantSettings(ctx, update).Return(nil).Once()
+
+	updated, err := fx.service.UpdateMerchantSettings(ctx, update)
+	require.NoError(t, err)
+	assert.Equal(t, 3, updated.BroadcastsPerHour)
+	assert.Equal(t, usecase.DistanceUnitMiles, updated.DistanceUnit)
+	assert.Equal(t, "mi", *update.DistanceUnit, "the unit is stored in canonical form")
+
+	// The next read misses the cache and observes the saved overrides
+	fx.settingsRepo.EXPECT().FindMerchantSettings(ctx, merchantID).Return(update, nil).Once()
+	after, err := fx.service.GetMerchantFlags(ctx, merchantID)
+	require.NoError(t, err)
+	assert.Equal(t, updated, after)
+}
+
+func TestMerchantSettingsService_GetMerchantFlags_EvictsWhenCacheIsFull(t *testing.T) {
+	fx := createTestMerchantSettingsService(t)
+	ctx := context.Background()
+	service := fx.service.(*merchantSettingsService)
+	service.cacheMaxEntries = 2
+
+	now := time.Now()
+	older, newer, expired := uuid.New(), uuid.New(), uuid.New()
+	service.cache[older] = cachedMerchantFlags{flags: usecase.MerchantFlags{MerchantID: older}, expiresAt: now.Add(time.Minute)}
+	service.cache[newer] = cachedMerchantFlags{flags: usecase.MerchantFlags{MerchantID: newer}, expiresAt: now.Add(2 * time.Minute)}
+
+	// A full cache drops the entry closest to expiring to store a new one
+	merchantID := uuid.New()
+	fx.settingsRepo.EXPECT().FindMerchantSettings(ctx, merchantID).Return(nil, domainerrors.ErrMerchantSettingsNotFound).Once()
+	_, err := fx.service.GetMerchantFlags(ctx, merchantID)
+	require.NoError(t, err)
+	assert.Len(t, service.cache, 2)
+	assert.NotContains(t, service.cache, older)
+	assert.Contains(t, service.cache, newer)
+	assert.Contains(t, service.cache, merchantID)
+
+	// Expired entries are dropped first, keeping every live one
+	delete(service.cache, newer)
+	service.cache[expired] = cachedMerchantFlags{flags: usecase.MerchantFlags{MerchantID: expired}, expiresAt: now.Add(-time.Minute)}
+	another := uuid.New()
+	fx.settingsRepo.EXPECT().FindMerchantSettings(ctx, another).Return(nil, domainerrors.ErrMerchantSettingsNotFound).Once()
+	_, err = fx.service.GetMerchantFlags(ctx, another)
+	require.NoError(t, err)
+	assert.Len(t, service.cache, 2)
+	assert.NotContains(t, service.cache, expired)
+	assert.Contains(t, service.cache, merchantID)
+	assert.Contains(t, service.cache, another)
+}
+
+func TestMerchantSettingsService_UpdateMerchantSettings_RejectsInvalidValues(t *testing.T) {
+	fx := createTestMerchantSettingsService(t)
+	ctx := context.Background()
+	merchantID := uuid.New()
+	tooFar := 8000.0
+	negative := -1
+	unit := "furlong"
+
+	tests := []struct {
+		name     string
+		settings *entity.MerchantSettings
+		err      error
+	}{
+		{name: "radius beyond service limit", settings: &entity.MerchantSettings{MerchantID: merchantID, MaxNotificationRadius: &tooFar}, err: domainerrors.ErrInvalidNotificationRadius},
+		{name: "negative rate limit", settings: &entity.MerchantSettings{MerchantID: merchantID, BroadcastsPerHour: &negative}, err: domainerrors.ErrInvalidInput},
+		{name: "unsupported unit", settings: &entity.MerchantSettings{MerchantID: merchantID, DistanceUnit: &unit}, err: domainerrors.ErrInvalidInput},
+		{name: "missing merchant", settings: &entity.MerchantSettings{}, err: domainerrors.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := fx.service.UpdateMerchantSettings(ctx, tt.settings)
+
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
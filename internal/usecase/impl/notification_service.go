@@ -5,9 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"slices"
+	"sync"
 	"time"
 
+	"radar/config"
 	"radar/internal/domain/entity"
 	domainerrors "radar/internal/domain/errors"
 	"radar/internal/domain/policy"
@@ -23,6 +24,15 @@ import (
 const (
 	// Firebase batch size limit
 	firebaseBatchSize = 500
+
+	// Maximum number of locations accepted by a single multi-location publish
+	maxMultiLocationPublish = 10
+
+	// Furthest ahead a broadcast can be scheduled
+	maxScheduleLead = 7 * 24 * time.Hour
+
+	// Longest range of days a broadcast stats query may cover
+	maxBroadcastStatsDays = 366
 )
 
 type notificationService struct {
@@ -31,9 +41,37 @@ type notificationService struct {
 	subscriptionRepo repository.SubscriptionRepository
 	deviceRepo       repository.DeviceRepository
 	addressRepo      repository.AddressRepository
+	preferenceRepo   repository.NotificationPreferenceRepository
 	notificationSvc  service.NotificationService
 	routingSvc       usecase.RoutingUsecase
+	routeCache       usecase.RouteCacheUsecase
 	eventPublisher   service.EventPublisher
+	idGenerator      service.IDGenerator
+	clock            service.Clock
+	metrics          service.NotificationMetrics
+	deepLinkPolicy   policy.DeepLinkPolicy
+	broadcastTTL     time.Duration
+	maxConcurrency   int
+	radiusPolicy     usecase.RadiusPolicy
+	deviceTarget     repository.DeviceTargetFilter
+
+	// Minimum time between immediate broadcasts from the same location; 0 disables the cooldown
+	broadcastCooldown time.Duration
+
+	// When set, subscribers that also have a merchant account are not notified
+	excludeMerchantSubscribers bool
+
+	// Limits broadcasts to a canary cohort of subscribers when enabled
+	canaryPolicy policy.CanaryPolicy
+
+	// When devices with invalid tokens are deleted
+	invalidTokenPolicy policy.InvalidTokenPolicy
+
+	// When set, the async path filters by road reachability before publishing
+	prefilterReachability bool
+
+	// Bounds how many reachable subscribers one broadcast is delivered to
+	recipientCap policy.RecipientCapPolicy
 }
 
 // NotificationServiceParams holds dependencies for NotificationService, injected by Fx.
@@ -45,24 +83,66 @@ type NotificationServiceParams struct {
 	SubscriptionRepo repository.SubscriptionRepository
 	DeviceRepo       repository.DeviceRepository
 	AddressRepo      repository.AddressRepository
+	PreferenceRepo   repository.NotificationPreferenceRepository `optional:"true"`
 	NotificationSvc  service.NotificationService
 	RoutingSvc       usecase.RoutingUsecase
+	RouteCache       usecase.RouteCacheUsecase `optional:"true"`
 	EventPublisher   service.EventPublisher
+	IDGenerator      service.IDGenerator         `optional:"true"`
+	Clock            service.Clock               `optional:"true"`
+	Metrics          service.NotificationMetrics `optional:"true"`
+	Policies         policy.NotificationPolicies
+	Config           *config.Config
 }
 
 // NewNotificationService creates a new notification service instance
 func NewNotificationService(params NotificationServiceParams) usecase.NotificationUsecase {
 	params.Logger.Info("Notification service configured for async Pub/Sub delivery")
 
+	var broadcastTTL time.Duration
+	var prefilterReachability bool
+	var deviceTarget repository.DeviceTargetFilter
+	var excludeMerchantSubscribers bool
+	var broadcastCooldown time.Duration
+	maxConcurrency := 1
+	if params.Config != nil && params.Config.Notification != nil {
+		broadcastTTL = params.Config.Notification.BroadcastTTL
+		maxConcurrency = max(params.Config.Notification.MaxConcurrentBatches, 1)
+		prefilterReachability = params.Config.Notification.PrefilterReachability
+		deviceTarget = repository.DeviceTargetFilter{
+			Platforms:     params.Config.Notification.TargetPlatforms,
+			MinAppVersion: params.Config.Notification.MinAppVersion,
+		}
+		excludeMerchantSubscribers = params.Config.Notification.ExcludeMerchantSubscribers
+		broadcastCooldown = params.Config.Notification.BroadcastCooldown
+	}
+
 	return &notificationService{
 		logger:           params.Logger,
 		notificationRepo: params.NotificationRepo,
 		subscriptionRepo: params.SubscriptionRepo,
 		deviceRepo:       params.DeviceRepo,
 		addressRepo:      params.AddressRepo,
+		preferenceRepo:   params.PreferenceRepo,
 		notificationSvc:  params.NotificationSvc,
 		routingSvc:       params.RoutingSvc,
+		routeCache:       params.RouteCache,
 		eventPublisher:   params.EventPublisher,
+		idGenerator:      idGeneratorOrDefault(params.IDGenerator),
+		clock:            clockOrDefault(params.Clock),
+		metrics:          service.NotificationMetricsOrNoop(params.Metrics),
+		deepLinkPolicy:   params.Policies.DeepLink,
+		broadcastTTL:     broadcastTTL,
+		maxConcurrency:   maxConcurrency,
+		radiusPolicy:     usecase.NewRadiusPolicy(params.Config),
+		deviceTarget:     deviceTarget,
+
+		broadcastCooldown:          broadcastCooldown,
+		excludeMerchantSubscribers: excludeMerchantSubscribers,
+		canaryPolicy:               params.Policies.Canary,
+		invalidTokenPolicy:         params.Policies.InvalidToken,
+		prefilterReachability:      prefilterReachability,
+		recipientCap:               params.Policies.RecipientCap,
 	}
 }
 
@@ -79,6 +159,93 @@ func (s *notificationService) PublishLocationNotification(
 	locationData *usecase.LocationData,
 	hintMessage string,
 ) (*entity.MerchantLocationNotification, error) {
+	input := usecase.PublishLocationInput{AddressID: addressID, LocationData: locationData, HintMessage: hintMessage}
+
+	return s.publishLocation(ctx, merchantID, input, nil)
+}
+
+// ScheduleLocationNotification stores a location notification to be sent at input.ScheduledAt
+func (s *notificationService) ScheduleLocationNotification(
+	ctx context.Context,
+	merchantID uuid.UUID,
+	input usecase.PublishLocationInput,
+) (*entity.MerchantLocationNotification, error) {
+	if input.ScheduledAt == nil {
+		return nil, domainerrors.ErrInvalidNotificationData.WithDetails("scheduled_at is required")
+	}
+
+	return s.publishLocation(ctx, merchantID, input, nil)
+}
+
+// CancelScheduledNotification cancels one of the merchant's scheduled notifications that has not been sent yet
+func (s *notificationService) CancelScheduledNotification(ctx context.Context, merchantID, notificationID uuid.UUID) error {
+	notification, err := s.notificationRepo.FindNotificationByID(ctx, notificationID)
+	if err != nil {
+		return err
+	}
+
+	if notification.MerchantID != merchantID {
+		return domainerrors.ErrNotificationOwnershipViolation
+	}
+
+	if notification.ScheduleStatus != entity.ScheduleStatusPending {
+		return domainerrors.ErrNotificationNotCancelable
+	}
+
+	// The dispatcher may claim the notification between the read and this update
+	canceled, err := s.notificationRepo.UpdateScheduleStatus(ctx, notificationID, entity.ScheduleStatusPending, entity.ScheduleStatusCanceled)
+	if err != nil {
+		return err
+	}
+	if !canceled {
+		return domainerrors.ErrNotificationNotCancelable
+	}
+
+	return nil
+}
+
+// PublishMultiLocation publishes a notification for each location, deduplicating subscribers across the batch
+func (s *notificationService) PublishMultiLocation(
+	ctx context.Context,
+	merchantID uuid.UUID,
+	inputs []usecase.PublishLocationInput,
+) ([]*usecase.PublishLocationResult, error) {
+	if len(inputs) == 0 {
+		return nil, domainerrors.ErrInvalidNotificationData.WithDetails("at least one location is required")
+	}
+	if len(inputs) > maxMultiLocationPublish {
+		return nil, domainerrors.ErrInvalidNotificationData.WithDetails(
+			fmt.Sprintf("at most %d locations can be published at once", maxMultiLocationPublish),
+		)
+	}
+
+	claims := make(subscriberClaims)
+	results := make([]*usecase.PublishLocationResult, 0, len(inputs))
+	for _, input := range inputs {
+		notification, err := s.publishLocation(ctx, merchantID, input, claims)
+		if err != nil {
+			s.log(ctx).Warn("Failed to publish location in multi-location batch",
+				slog.String("merchant_id", merchantID.String()),
+				slog.String("error", err.Error()),
+			)
+		}
+		results = append(results, &usecase.PublishLocationResult{Notification: notification, Err: err})
+	}
+
+	return results, nil
+}
+
+// publishLocation validates and publishes one location, or stores it for later when it is scheduled.
+// Subscribers already in claims are skipped and the subscribers this location targets are added to it;
+// claims is nil outside multi-location publishes. Scheduled locations pick their subscribers when they are due.
+func (s *notificationService) publishLocation(
+	ctx context.Context,
+	merchantID uuid.UUID,
+	input usecase.PublishLocationInput,
+	claims subscriberClaims,
+) (*entity.MerchantLocationNotification, error) {
+	addressID, locationData := input.AddressID, input.LocationData
+
 	// Validate input
 	if addressID == nil && locationData == nil {
 		return nil, domainerrors.ErrInvalidNotificationData
@@ -87,15 +254,35 @@ func (s *notificationService) PublishLocationNotification(
 		return nil, fmt.Errorf("address_id and location_data are mutually exclusive: %w", domainerrors.ErrInvalidNotificationData)
 	}
 
+	hintMessage, err := policy.DefaultHintMessagePolicy().Normalize(input.HintMessage)
+	if err != nil {
+		return nil, domainerrors.ErrInvalidNotificationData.WithDetails(err.Error())
+	}
+
+	now := s.clock.Now()
+	if err := validateScheduledAt(input.ScheduledAt, now); err != nil {
+		return nil, err
+	}
+
 	// Get location information
 	locationName, fullAddress, latitude, longitude, err := s.getLocationInfo(ctx, merchantID, addressID, locationData)
 	if err != nil {
 		return nil, err
 	}
 
+	// Subscribers are selected before the notification is recorded, so a strict recipient cap rejects the
+	// broadcast without leaving a notification behind. Scheduled locations select theirs when they are due.
+	var audience *broadcastAudience
+	if input.ScheduledAt == nil {
+		audience, err = s.selectAudience(ctx, merchantID, latitude, longitude, claims)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Create notification record
 	notification := &entity.MerchantLocationNotification{
-		ID:           uuid.New(),
+		ID:           s.idGenerator.NewID(),
 		MerchantID:   merchantID,
 		AddressID:    addressID,
 		LocationName: locationName,
@@ -105,39 +292,161 @@ func (s *notificationService) PublishLocationNotification(
 		HintMessage:  hintMessage,
 		TotalSent:    0,
 		TotalFailed:  0,
-		PublishedAt:  time.Now(),
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		PublishedAt:  now,
+		CreatedAt:    now,
+		UpdatedAt:    now,
 	}
 
-	if err := s.notificationRepo.CreateNotification(ctx, notification); err != nil {
+	if input.ScheduledAt != nil {
+		notification.PublishedAt = *input.ScheduledAt
+		notification.ScheduledAt = input.ScheduledAt
+		notification.ScheduleStatus = entity.ScheduleStatusPending
+	}
+
+	if err := s.createNotification(ctx, notification); err != nil {
 		return nil, err
 	}
+	s.metrics.NotificationCreated()
+
+	if notification.ScheduledAt != nil {
+		s.log(ctx).Info("Notification scheduled",
+			slog.String("notification_id", notification.ID.String()),
+			slog.Time("scheduled_at", *notification.ScheduledAt),
+		)
+
+		return notification, nil
+	}
 
-	return s.publishAsync(ctx, notification, merchantID, latitude, longitude, locationName, fullAddress, hintMessage)
+	return s.publishAsync(ctx, notification, audience, merchantID, latitude, longitude, locationName, fullAddress, hintMessage, claims)
 }
 
-// publishAsync publishes the notification event to Pub/Sub for async processing
-func (s *notificationService) publishAsync(
+// createNotification persists the notification. An immediate broadcast is rejected when the merchant already sent
+// one from the same location within the cooldown; the repository checks and inserts atomically, so concurrent
+// publishes cannot both get through. Scheduled notifications are checked again when they are dispatched.
+func (s *notificationService) createNotification(ctx context.Context, notification *entity.MerchantLocationNotification) error {
+	if notification.ScheduledAt != nil || s.broadcastCooldown <= 0 {
+		return s.notificationRepo.CreateNotification(ctx, notification)
+	}
+
+	latest, err := s.notificationRepo.CreateNotificationOutsideCooldown(ctx, notification, s.broadcastCooldown)
+	if err != nil {
+		return err
+	}
+	if latest != nil {
+		wait := s.broadcastCooldown - notification.PublishedAt.Sub(*latest)
+
+		return domainerrors.ErrRateLimited.WithDetails(
+			fmt.Sprintf("retry after %s", wait.Round(time.Second)),
+		)
+	}
+
+	return nil
+}
+
+// validateScheduledAt accepts no schedule, or a time after now and within maxScheduleLead of it
+func validateScheduledAt(scheduledAt *time.Time, now time.Time) error {
+	if scheduledAt == nil {
+		return nil
+	}
+
+	if !scheduledAt.After(now) {
+		return domainerrors.ErrInvalidNotificationData.WithDetails("scheduled_at must be in the future")
+	}
+	if scheduledAt.After(now.Add(maxScheduleLead)) {
+		return domainerrors.ErrInvalidNotificationData.WithDetails(
+			fmt.Sprintf("scheduled_at must be within %s", maxScheduleLead),
+		)
+	}
+
+	return nil
+}
+
+// broadcastAudience is the subscribers an immediate broadcast is published to
+type broadcastAudience struct {
+	addresses            []*entity.SubscriberAddress
+	reachabilityFiltered bool
+	canarySuppressed     int
+	recipientsCapped     int
+
+	// Set when the subscriber lookup failed, so the broadcast is delivered synchronously instead
+	sync bool
+}
+
+// selectAudience picks the subscribers a broadcast from the location is published to and applies the recipient cap.
+// A strict cap is checked even when the reachability prefilter fails: the straight-line candidates include every
+// reachable subscriber, so a broadcast within the cap on them is within it on the reachable ones too.
+func (s *notificationService) selectAudience(
 	ctx context.Context,
-	notification *entity.MerchantLocationNotification,
 	merchantID uuid.UUID,
 	latitude, longitude float64,
-	locationName, fullAddress, hintMessage string,
-) (*entity.MerchantLocationNotification, error) {
+	claims subscriberClaims,
+) (*broadcastAudience, error) {
 	// Pre-filter subscribers using PostGIS (straight-line distance)
 	candidateAddresses, err := s.subscriptionRepo.FindSubscriberAddressesWithinRadius(ctx, merchantID, latitude, longitude)
 	if err != nil {
+		// A strict cap cannot be checked without the subscribers, and the sync path would check it only
+		// after the notification is recorded
+		if s.recipientCap.Strict && s.recipientCap.MaxRecipients > 0 {
+			return nil, fmt.Errorf("failed to find subscriber addresses: %w", err)
+		}
+
 		// Startup is strict about configuration, but runtime pre-filter failures are
 		// treated as a degraded-mode event so delivery can still proceed via direct Firebase.
 		s.log(ctx).Warn("Failed to pre-filter subscribers, falling back to sync",
 			slog.String("error", err.Error()),
 		)
 
-		return s.publishSync(ctx, notification, merchantID, latitude, longitude, locationName, fullAddress, hintMessage)
+		return &broadcastAudience{sync: true}, nil
+	}
+
+	candidateAddresses, canarySuppressed := s.eligibleSubscribers(candidateAddresses)
+	if allowed, err := s.applyNotificationPreferences(ctx, merchantID, candidateAddresses); err != nil {
+		// The worker applies preferences again at delivery time, so publishing without them loses nothing
+		s.log(ctx).Warn("Failed to apply notification preferences, leaving the check to the worker",
+			slog.String("error", err.Error()),
+		)
+	} else {
+		candidateAddresses = allowed
+	}
+	// Multi-location batches always prefilter so a subscriber is only claimed by a location that reaches them,
+	// and a recipient cap needs the reachable subscribers to be known before it can pick the nearest ones
+	forcePrefilter := claims != nil || s.recipientCap.MaxRecipients > 0
+	candidateAddresses, reachabilityFiltered := s.prefilterReachableAddresses(ctx, merchantID, latitude, longitude, candidateAddresses, forcePrefilter)
+	candidateAddresses = claims.unclaimed(candidateAddresses)
+	// Without a prefilter the worker picks the nearest subscribers after its own reachability check
+	var recipientsCapped int
+	if reachabilityFiltered || s.recipientCap.Strict {
+		candidateAddresses, recipientsCapped, err = usecase.CapRecipients(usecase.Coordinate{Lat: latitude, Lng: longitude}, candidateAddresses, s.recipientCap)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &broadcastAudience{
+		addresses:            candidateAddresses,
+		reachabilityFiltered: reachabilityFiltered,
+		canarySuppressed:     canarySuppressed,
+		recipientsCapped:     recipientsCapped,
+	}, nil
+}
+
+// publishAsync publishes the notification event for the selected audience to Pub/Sub for async processing
+func (s *notificationService) publishAsync(
+	ctx context.Context,
+	notification *entity.MerchantLocationNotification,
+	audience *broadcastAudience,
+	merchantID uuid.UUID,
+	latitude, longitude float64,
+	locationName, fullAddress, hintMessage string,
+	claims subscriberClaims,
+) (*entity.MerchantLocationNotification, error) {
+	if audience.sync {
+		return s.publishSync(ctx, notification, merchantID, latitude, longitude, locationName, fullAddress, hintMessage, claims)
 	}
 
+	candidateAddresses := audience.addresses
 	if len(candidateAddresses) == 0 {
+		s.recordCanarySuppressed(ctx, audience.canarySuppressed)
 		s.log(ctx).Info("No subscribers within radius",
 			slog.String("notification_id", notification.ID.String()),
 		)
@@ -146,12 +455,9 @@ func (s *notificationService) publishAsync(
 	}
 
 	// Extract unique user IDs
-	userIDSet := make(map[uuid.UUID]bool)
-	for _, addr := range candidateAddresses {
-		userIDSet[addr.OwnerID] = true
-	}
-	subscriberIDs := make([]string, 0, len(userIDSet))
-	for userID := range userIDSet {
+	userIDs := usecase.SubscriberIDs(candidateAddresses)
+	subscriberIDs := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
 		subscriberIDs = append(subscriberIDs, userID.String())
 	}
 
@@ -166,6 +472,11 @@ func (s *notificationService) publishAsync(
 		FullAddress:    fullAddress,
 		HintMessage:    hintMessage,
 		SubscriberIDs:  subscriberIDs,
+
+		ReachabilityFiltered: audience.reachabilityFiltered,
+	}
+	if s.broadcastTTL > 0 {
+		event.ExpiresAt = s.clock.Now().Add(s.broadcastTTL)
 	}
 
 	if err := s.eventPublisher.PublishNotificationEvent(ctx, event); err != nil {
@@ -175,9 +486,14 @@ func (s *notificationService) publishAsync(
 			slog.String("error", err.Error()),
 		)
 
-		return s.publishSync(ctx, notification, merchantID, latitude, longitude, locationName, fullAddress, hintMessage)
+		return s.publishSync(ctx, notification, merchantID, latitude, longitude, locationName, fullAddress, hintMessage, claims)
 	}
 
+	claims.claim(userIDs)
+	// Recorded only once the event is published, since the sync fallback selects subscribers again
+	s.recordCanarySuppressed(ctx, audience.canarySuppressed)
+	s.recordRecipientsCapped(ctx, audience.recipientsCapped)
+
 	s.log(ctx).Info("Notification event published for async processing",
 		slog.String("notification_id", notification.ID.String()),
 		slog.Int("subscriber_count", len(subscriberIDs)),
@@ -187,6 +503,123 @@ func (s *notificationService) publishAsync(
 	return notification, nil
 }
 
+// eligibleSubscribers drops snoozed subscribers and, when configured, subscribers with a merchant account
+// and subscribers outside the canary cohort. It also returns how many subscribers the canary suppressed.
+func (s *notificationService) eligibleSubscribers(addresses []*entity.SubscriberAddress) ([]*entity.SubscriberAddress, int) {
+	addresses = entity.WithoutSnoozedSubscribers(addresses, s.clock.Now())
+	if s.excludeMerchantSubscribers {
+		addresses = entity.WithoutMerchantSubscribers(addresses)
+	}
+
+	return usecase.CanaryCohort(addresses, s.canaryPolicy)
+}
+
+// applyNotificationPreferences drops subscribers who are in their quiet hours or opted out of the merchant's category
+func (s *notificationService) applyNotificationPreferences(
+	ctx context.Context,
+	merchantID uuid.UUID,
+	addresses []*entity.SubscriberAddress,
+) ([]*entity.SubscriberAddress, error) {
+	if s.preferenceRepo == nil {
+		return addresses, nil
+	}
+
+	allowed, suppressed, err := usecase.ApplyNotificationPreferences(ctx, s.preferenceRepo, merchantID, addresses, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if suppressed > 0 {
+		s.log(ctx).Info("Notification preferences suppressed subscribers",
+			slog.Int("suppressed_count", suppressed),
+		)
+	}
+
+	return allowed, nil
+}
+
+// applyDevicePreferences drops devices whose own notification settings are disabled or in their quiet hours
+func (s *notificationService) applyDevicePreferences(ctx context.Context, devices []*entity.UserDevice) ([]*entity.UserDevice, error) {
+	allowed, suppressed, err := usecase.ApplyDevicePreferences(ctx, s.preferenceRepo, devices, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	if suppressed > 0 {
+		s.log(ctx).Info("Device notification settings suppressed devices",
+			slog.Int("suppressed_count", suppressed),
+		)
+	}
+
+	return allowed, nil
+}
+
+// recordCanarySuppressed records the subscribers a broadcast skipped because they are outside the canary cohort
+func (s *notificationService) recordCanarySuppressed(ctx context.Context, suppressed int) {
+	if suppressed == 0 {
+		return
+	}
+
+	s.metrics.CanarySuppressed(suppressed)
+	s.log(ctx).Info("Canary mode suppressed subscribers outside the cohort",
+		slog.Int("suppressed_count", suppressed),
+	)
+}
+
+// recordRecipientsCapped records the subscribers a broadcast dropped because it was over the recipient cap
+func (s *notificationService) recordRecipientsCapped(ctx context.Context, dropped int) {
+	if dropped == 0 {
+		return
+	}
+
+	s.metrics.RecipientsCapped(dropped)
+	s.log(ctx).Warn("Broadcast capped to the nearest subscribers",
+		slog.Int("max_recipients", s.recipientCap.MaxRecipients),
+		slog.Int("dropped_count", dropped),
+	)
+}
+
+// prefilterReachableAddresses applies the shared road reachability filter before publishing when enabled or forced.
+// On routing failure the unfiltered candidates are returned so the worker still performs the check.
+func (s *notificationService) prefilterReachableAddresses(
+	ctx context.Context,
+	merchantID uuid.UUID,
+	latitude, longitude float64,
+	addresses []*entity.SubscriberAddress,
+	force bool,
+) ([]*entity.SubscriberAddress, bool) {
+	if (!s.prefilterReachability && !force) || len(addresses) == 0 {
+		return addresses, false
+	}
+
+	source := usecase.Coordinate{Lat: latitude, Lng: longitude}
+	reachable, err := s.filterReachableAddresses(ctx, merchantID, source, addresses)
+	if err != nil {
+		s.log(ctx).Warn("Failed to pre-filter reachability, leaving the check to the worker",
+			slog.String("error", err.Error()),
+		)
+
+		return addresses, false
+	}
+
+	return reachable, true
+}
+
+// filterReachableAddresses applies the road reachability filter, reusing warmed routes when a route cache is wired
+func (s *notificationService) filterReachableAddresses(
+	ctx context.Context,
+	merchantID uuid.UUID,
+	source usecase.Coordinate,
+	addresses []*entity.SubscriberAddress,
+) ([]*entity.SubscriberAddress, error) {
+	startedAt := s.clock.Now()
+	defer func() { s.metrics.ObserveRoutingLatency(s.clock.Now().Sub(startedAt)) }()
+
+	if s.routeCache != nil {
+		return s.routeCache.FilterReachableAddresses(ctx, merchantID, source, addresses)
+	}
+
+	return usecase.FilterReachableAddresses(ctx, s.routingSvc, s.radiusPolicy, source, addresses)
+}
+
 // publishSync processes notifications synchronously (original behavior)
 func (s *notificationService) publishSync(
 	ctx context.Context,
@@ -194,9 +627,10 @@ func (s *notificationService) publishSync(
 	merchantID uuid.UUID,
 	latitude, longitude float64,
 	locationName, fullAddress, hintMessage string,
+	claims subscriberClaims,
 ) (*entity.MerchantLocationNotification, error) {
 	// Get devices for subscribers
-	tokens, deviceMap, err := s.getSubscriberDevices(ctx, merchantID, latitude, longitude)
+	tokens, deviceMap, err := s.getSubscriberDevices(ctx, merchantID, latitude, longitude, claims)
 	if err != nil {
 		return nil, err
 	}
@@ -228,6 +662,173 @@ func (s *notificationService) GetMerchantNotificationHistory(
 	return notifications, nil
 }
 
+// GetMerchantBroadcastStats summarizes a merchant's broadcasts per day, filling days without broadcasts with zeros
+func (s *notificationService) GetMerchantBroadcastStats(
+	ctx context.Context,
+	merchantID uuid.UUID,
+	from, to time.Time,
+) (*usecase.MerchantBroadcastStats, error) {
+	firstDay, lastDay := utcDay(from), utcDay(to)
+	if lastDay.Before(firstDay) {
+		return nil, domainerrors.ErrValidationFailed.WithDetails("to must not be before from")
+	}
+	dayCount := int(lastDay.Sub(firstDay)/(24*time.Hour)) + 1
+	if dayCount > maxBroadcastStatsDays {
+		return nil, domainerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("range must not exceed %d days", maxBroadcastStatsDays))
+	}
+
+	buckets, err := s.notificationRepo.SumBroadcastsByDay(ctx, merchantID, firstDay, lastDay.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	byDay := make(map[time.Time]*entity.BroadcastDayStats, len(buckets))
+	for _, bucket := range buckets {
+		byDay[utcDay(bucket.Day)] = bucket
+	}
+
+	stats := &usecase.MerchantBroadcastStats{
+		From: firstDay,
+		To:   lastDay,
+		Days: make([]*entity.BroadcastDayStats, 0, dayCount),
+	}
+	for day := firstDay; !day.After(lastDay); day = day.AddDate(0, 0, 1) {
+		bucket := &entity.BroadcastDayStats{Day: day}
+		if found, ok := byDay[day]; ok {
+			bucket.Broadcasts, bucket.Recipients = found.Broadcasts, found.Recipients
+		}
+		stats.Days = append(stats.Days, bucket)
+		stats.TotalBroadcasts += bucket.Broadcasts
+		stats.TotalRecipients += bucket.Recipients
+	}
+	if stats.TotalBroadcasts > 0 {
+		stats.AverageRecipients = float64(stats.TotalRecipients) / float64(stats.TotalBroadcasts)
+	}
+
+	return stats, nil
+}
+
+// utcDay returns midnight UTC at the start of t's UTC day
+func utcDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// GetReceivedNotifications retrieves the notifications delivered to a user with pagination
+func (s *notificationService) GetReceivedNotifications(
+	ctx context.Context,
+	userID uuid.UUID,
+	limit, offset int,
+) ([]*entity.ReceivedNotification, error) {
+	received, err := s.notificationRepo.FindNotificationLogsByUser(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return received, nil
+}
+
+// RecordNotificationOpened marks the user's delivery of the notification as opened
+func (s *notificationService) RecordNotificationOpened(ctx context.Context, userID, notificationID uuid.UUID) error {
+	opened, err := s.notificationRepo.MarkNotificationOpened(ctx, notificationID, userID, s.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	if !opened {
+		s.log(ctx).Debug("Notification open already recorded",
+			slog.String("notification_id", notificationID.String()),
+		)
+	}
+
+	return nil
+}
+
+// GetSubscriberSnapshots returns snap nodes and road distances for subscribers around a merchant address
+func (s *notificationService) GetSubscriberSnapshots(
+	ctx context.Context,
+	merchantID, addressID uuid.UUID,
+) ([]*usecase.SubscriberSnapshot, error) {
+	_, _, latitude, longitude, err := s.getLocationInfo(ctx, merchantID, &addressID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses, err := s.subscriptionRepo.FindSubscriberAddressesWithinRadius(ctx, merchantID, latitude, longitude)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find subscriber addresses: %w", err)
+	}
+
+	return s.buildSubscriberSnapshots(ctx, usecase.Coordinate{Lat: latitude, Lng: longitude}, addresses)
+}
+
+// GetSubscriptionReachability returns routing diagnostics for the subscriber's own addresses from a merchant location
+func (s *notificationService) GetSubscriptionReachability(
+	ctx context.Context,
+	userID, merchantID uuid.UUID,
+	from usecase.Coordinate,
+) ([]*usecase.SubscriberSnapshot, error) {
+	subscription, err := s.subscriptionRepo.FindSubscriptionByUserAndMerchant(ctx, userID, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	if !subscription.IsActive {
+		return nil, domainerrors.ErrSubscriptionNotFound
+	}
+
+	addresses, err := s.subscriptionRepo.FindSubscriberAddressesByUserIDs(ctx, merchantID, []uuid.UUID{userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find subscriber addresses: %w", err)
+	}
+
+	return s.buildSubscriberSnapshots(ctx, from, addresses)
+}
+
+// buildSubscriberSnapshots snaps each address and routes to it from source, applying the fan-out's radius decision
+func (s *notificationService) buildSubscriberSnapshots(
+	ctx context.Context,
+	source usecase.Coordinate,
+	addresses []*entity.SubscriberAddress,
+) ([]*usecase.SubscriberSnapshot, error) {
+	snapshots := make([]*usecase.SubscriberSnapshot, 0, len(addresses))
+	if len(addresses) == 0 {
+		return snapshots, nil
+	}
+
+	targets := s.buildTargetCoordinates(addresses)
+
+	nodes, snapped, err := s.routingSvc.SnapBatch(ctx, s.radiusPolicy.Profile, targets)
+	if err != nil {
+		return nil, fmt.Errorf("routing service failed: %w", err)
+	}
+
+	routeResults, err := s.routingSvc.OneToMany(ctx, s.radiusPolicy.Profile, source, targets)
+	if err != nil {
+		return nil, fmt.Errorf("routing service failed: %w", err)
+	}
+
+	for idx, addr := range addresses {
+		snapshot := &usecase.SubscriberSnapshot{
+			AddressID:          addr.ID,
+			NotificationRadius: addr.NotificationRadius,
+		}
+		if idx < len(snapped) && snapped[idx] {
+			snapshot.SnapNode = &nodes[idx]
+		}
+		if idx < len(routeResults.Results) {
+			result := routeResults.Results[idx]
+			snapshot.DistanceKm = result.DistanceKm
+			snapshot.DurationMin = result.DurationMin
+			snapshot.IsReachable = result.IsReachable
+			snapshot.UnreachableReason = result.UnreachableReason
+			snapshot.WithinRadius = usecase.IsWithinSubscriberArea(source, addr, result, s.radiusPolicy)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
+
 // getLocationInfo retrieves location information from either addressID or locationData
 func (s *notificationService) getLocationInfo(
 	ctx context.Context,
@@ -254,14 +855,37 @@ func (s *notificationService) getLocationInfo(
 		return address.Label, address.FullAddress, address.Latitude, address.Longitude, nil
 	}
 
-	// Use provided location data
-	return locationData.LocationName, locationData.FullAddress, locationData.Latitude, locationData.Longitude, nil
+	// Use provided location data; a publish with only coordinates is labeled with the road it is on
+	fullAddress = locationData.FullAddress
+	if fullAddress == "" {
+		fullAddress = s.nearestRoadName(ctx, locationData.Latitude, locationData.Longitude)
+	}
+
+	return locationData.LocationName, fullAddress, locationData.Latitude, locationData.Longitude, nil
+}
+
+// nearestRoadName returns the name of the road closest to the coordinates, or an empty string when
+// none is found. A lookup failure only leaves the address empty, so it does not fail the publish.
+func (s *notificationService) nearestRoadName(ctx context.Context, latitude, longitude float64) string {
+	name, found, err := s.routingSvc.NearestRoadName(ctx, usecase.Coordinate{Lat: latitude, Lng: longitude})
+	if err != nil {
+		s.logger.Warn("Nearest road name lookup failed, publishing without an address",
+			slog.String("error", err.Error()),
+		)
+
+		return ""
+	}
+	if !found {
+		return ""
+	}
+
+	return name
 }
 
 // prepareNotificationContent prepares the notification title and body
 func (s *notificationService) prepareNotificationContent(locationName, fullAddress, hintMessage string) (title, body string) {
 	title = "商戶位置通知"
-	body = fmt.Sprintf("%s 已在 %s 開始營業", locationName, fullAddress)
+	body = policy.LocationNotificationBody(locationName, fullAddress)
 	if hintMessage != "" {
 		body = fmt.Sprintf("%s - %s", body, hintMessage)
 	}
@@ -269,7 +893,16 @@ func (s *notificationService) prepareNotificationContent(locationName, fullAddre
 	return title, body
 }
 
-// sendNotificationBatches sends notifications in batches and returns statistics
+// batchSendResult holds the outcome of sending a single provider batch
+type batchSendResult struct {
+	sent          int
+	failed        int
+	logs          []*entity.NotificationLog
+	invalidTokens []string
+}
+
+// sendNotificationBatches sends notifications in batches, up to maxConcurrency at a time, and returns statistics.
+// Results are aggregated in batch order regardless of completion order.
 func (s *notificationService) sendNotificationBatches(
 	ctx context.Context,
 	tokens []string,
@@ -278,67 +911,92 @@ func (s *notificationService) sendNotificationBatches(
 	notificationData map[string]string,
 	notificationID uuid.UUID,
 ) (totalSent, totalFailed int, notificationLogs []*entity.NotificationLog, invalidTokens []string) {
-	for i := 0; i < len(tokens); i += firebaseBatchSize {
-		end := min(i+firebaseBatchSize, len(tokens))
-		batch := tokens[i:end]
-
-		// Send batch notification
-		successCount, failureCount, batchInvalidTokens, err := s.notificationSvc.SendBatchNotification(
-			ctx,
-			batch,
-			title,
-			body,
-			notificationData,
-		)
-
-		if err != nil {
-			// Log error but continue with other batches
-			totalFailed += len(batch)
+	batchCount := (len(tokens) + firebaseBatchSize - 1) / firebaseBatchSize
+	results := make([]batchSendResult, batchCount)
+	semaphore := make(chan struct{}, s.maxConcurrency)
+
+	var waitGroup sync.WaitGroup
+	for idx := range batchCount {
+		start := idx * firebaseBatchSize
+		batch := tokens[start:min(start+firebaseBatchSize, len(tokens))]
+
+		semaphore <- struct{}{}
+		waitGroup.Go(func() {
+			defer func() { <-semaphore }()
+			results[idx] = s.sendNotificationBatch(ctx, batch, deviceMap, title, body, notificationData, notificationID)
+		})
+	}
+	waitGroup.Wait()
 
-			continue
-		}
+	for _, result := range results {
+		totalSent += result.sent
+		totalFailed += result.failed
+		notificationLogs = append(notificationLogs, result.logs...)
+		invalidTokens = append(invalidTokens, result.invalidTokens...)
+	}
 
-		totalSent += successCount
-		totalFailed += failureCount
-		invalidTokens = append(invalidTokens, batchInvalidTokens...)
+	return totalSent, totalFailed, notificationLogs, invalidTokens
+}
 
-		// Create notification logs for this batch
-		batchLogs := s.createNotificationLogs(batch, deviceMap, batchInvalidTokens, notificationID)
-		notificationLogs = append(notificationLogs, batchLogs...)
+// sendNotificationBatch sends one provider batch and builds its notification logs
+func (s *notificationService) sendNotificationBatch(
+	ctx context.Context,
+	batch []string,
+	deviceMap map[string]*entity.UserDevice,
+	title, body string,
+	notificationData map[string]string,
+	notificationID uuid.UUID,
+) batchSendResult {
+	results, err := s.notificationSvc.SendBatchNotification(
+		ctx,
+		batch,
+		title,
+		body,
+		notificationData,
+	)
+	// Results still describe each token when the batch reports an error; other batches continue independently
+	if err != nil {
+		s.log(ctx).Warn("Notification batch left tokens undelivered",
+			slog.Int("batch_size", len(batch)),
+			slog.String("error", err.Error()),
+		)
 	}
 
-	return totalSent, totalFailed, notificationLogs, invalidTokens
+	sent, failed, invalidTokens := service.SummarizeTokenResults(results)
+
+	return batchSendResult{
+		sent:          sent,
+		failed:        failed + max(len(batch)-len(results), 0),
+		logs:          s.createNotificationLogs(results, deviceMap, notificationID),
+		invalidTokens: invalidTokens,
+	}
 }
 
-// createNotificationLogs creates notification logs for a batch of tokens
+// createNotificationLogs creates a notification log for each token result of a batch
 func (s *notificationService) createNotificationLogs(
-	tokens []string,
+	results []service.TokenResult,
 	deviceMap map[string]*entity.UserDevice,
-	invalidTokens []string,
 	notificationID uuid.UUID,
 ) []*entity.NotificationLog {
-	logs := make([]*entity.NotificationLog, 0, len(tokens))
+	logs := make([]*entity.NotificationLog, 0, len(results))
 
-	for _, token := range tokens {
-		device := deviceMap[token]
-		status := "sent"
-		errorMsg := ""
-
-		// Check if token is invalid
-		if slices.Contains(invalidTokens, token) {
-			status = "failed"
-			errorMsg = "unregistered token"
+	for _, result := range results {
+		device, ok := deviceMap[result.Token]
+		if !ok || device == nil {
+			continue
 		}
 
+		status, errorMsg := policy.NotificationLogOutcome(result)
+
 		log := &entity.NotificationLog{
-			ID:             uuid.New(),
+			ID:             s.idGenerator.NewID(),
 			NotificationID: notificationID,
 			UserID:         device.UserID,
 			DeviceID:       device.ID,
 			Status:         status,
 			FCMMessageID:   "", // Firebase doesn't provide individual message IDs in batch mode
 			ErrorMessage:   errorMsg,
-			SentAt:         time.Now(),
+			SentAt:         s.clock.Now(),
 		}
 		logs = append(logs, log)
 	}
@@ -346,52 +1004,111 @@ func (s *notificationService) createNotificationLogs(
 	return logs
 }
 
-// handleInvalidTokens soft deletes devices with tokens confirmed unregistered by FCM.
+// handleInvalidTokens soft deletes devices with tokens confirmed unregistered by FCM, by the same rules as the worker
 func (s *notificationService) handleInvalidTokens(ctx context.Context, invalidTokens []string, deviceMap map[string]*entity.UserDevice) {
-	for _, token := range invalidTokens {
-		if device, ok := deviceMap[token]; ok {
-			if err := s.deviceRepo.DeleteDevice(ctx, device.ID); err != nil {
-				// Log error but continue
-				s.log(ctx).Warn("failed to delete unregistered device", slog.String("device_id", device.ID.String()), slog.String("error", err.Error()))
-			}
+	cleaned, err := s.invalidTokenPolicy.CleanupInvalidTokens(ctx, s.deviceRepo, invalidTokens, deviceMap, s.clock.Now())
+	if err != nil {
+		// Log error but continue
+		s.log(ctx).Warn("failed to clean up invalid tokens", slog.String("error", err.Error()))
+	}
+	s.metrics.InvalidTokensCleaned(cleaned)
+}
+
+// resetInvalidTokenStrikes clears the invalid-token strikes of devices the notification was sent to
+func (s *notificationService) resetInvalidTokenStrikes(ctx context.Context, logs []*entity.NotificationLog) {
+	if err := s.invalidTokenPolicy.ResetStrikes(ctx, s.deviceRepo, logs); err != nil {
+		s.log(ctx).Warn("failed to reset invalid token strikes", slog.String("error", err.Error()))
+	}
+}
+
+// recordDeliverySuccess stamps the devices the notification was sent to so stale-device cleanup keeps them
+func (s *notificationService) recordDeliverySuccess(ctx context.Context, logs []*entity.NotificationLog) {
+	if err := policy.RecordDeliverySuccess(ctx, s.deviceRepo, logs, s.clock.Now()); err != nil {
+		s.log(ctx).Warn("failed to record device delivery success", slog.String("error", err.Error()))
+	}
+}
+
+// subscriberClaims records the subscribers already targeted by earlier locations of a multi-location publish.
+// A nil claims set claims nothing and filters nothing.
+type subscriberClaims map[uuid.UUID]struct{}
+
+// unclaimed returns the addresses whose owners have not been claimed yet
+func (c subscriberClaims) unclaimed(addresses []*entity.SubscriberAddress) []*entity.SubscriberAddress {
+	if len(c) == 0 {
+		return addresses
+	}
+
+	filtered := make([]*entity.SubscriberAddress, 0, len(addresses))
+	for _, addr := range addresses {
+		if _, claimed := c[addr.OwnerID]; !claimed {
+			filtered = append(filtered, addr)
 		}
 	}
+
+	return filtered
 }
 
-// getSubscriberDevices retrieves devices for subscribers within radius using road network distance
+// claim marks the given subscribers as targeted
+func (c subscriberClaims) claim(userIDs []uuid.UUID) {
+	if c == nil {
+		return
+	}
+
+	for _, userID := range userIDs {
+		c[userID] = struct{}{}
+	}
+}
+
+// getSubscriberDevices retrieves devices for unclaimed subscribers within radius using road network distance
 func (s *notificationService) getSubscriberDevices(
 	ctx context.Context,
 	merchantID uuid.UUID,
 	latitude, longitude float64,
+	claims subscriberClaims,
 ) (tokens []string, deviceMap map[string]*entity.UserDevice, err error) {
 	candidateAddresses, err := s.subscriptionRepo.FindSubscriberAddressesWithinRadius(ctx, merchantID, latitude, longitude)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to find subscriber addresses: %w", err)
 	}
 
+	candidateAddresses, canarySuppressed := s.eligibleSubscribers(candidateAddresses)
+	s.recordCanarySuppressed(ctx, canarySuppressed)
+	candidateAddresses, err = s.applyNotificationPreferences(ctx, merchantID, candidateAddresses)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to apply notification preferences: %w", err)
+	}
 	if len(candidateAddresses) == 0 {
 		return s.emptyDeviceResponse()
 	}
 
-	targets := s.buildTargetCoordinates(candidateAddresses)
-
 	source := usecase.Coordinate{Lat: latitude, Lng: longitude}
-	routeResults, err := s.routingSvc.OneToMany(ctx, source, targets)
+	validAddresses, err := s.filterReachableAddresses(ctx, merchantID, source, candidateAddresses)
 	if err != nil {
 		return nil, nil, fmt.Errorf("routing service failed: %w", err)
 	}
 
-	validAddresses := filterReachableAddresses(candidateAddresses, routeResults.Results)
+	validAddresses = claims.unclaimed(validAddresses)
+	validAddresses, recipientsCapped, err := usecase.CapRecipients(source, validAddresses, s.recipientCap)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.recordRecipientsCapped(ctx, recipientsCapped)
 	if len(validAddresses) == 0 {
 		return s.emptyDeviceResponse()
 	}
 
-	userIDs := collectAddressUserIDs(validAddresses)
+	userIDs := usecase.SubscriberIDs(validAddresses)
 
-	devices, err := s.subscriptionRepo.FindDevicesForUsers(ctx, userIDs, policy.DefaultDevicePolicy().HealthyWindowDays)
+	devices, err := s.subscriptionRepo.FindDevicesForUsers(ctx, userIDs, policy.DefaultDevicePolicy().HealthyWindowDays, s.deviceTarget)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to fetch devices: %w", err)
 	}
+	claims.claim(userIDs)
+
+	devices, err = s.applyDevicePreferences(ctx, devices)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to apply device preferences: %w", err)
+	}
 
 	if len(devices) == 0 {
 		return s.emptyDeviceResponse()
@@ -415,27 +1132,6 @@ func (s *notificationService) buildTargetCoordinates(addresses []*entity.Subscri
 	return targets
 }
 
-func filterReachableAddresses(addresses []*entity.SubscriberAddress, results []usecase.RouteResult) []*entity.SubscriberAddress {
-	validAddresses := make([]*entity.SubscriberAddress, 0, len(addresses))
-	for idx, result := range results {
-		distanceMeters := result.DistanceKm * 1000.0
-		if result.IsReachable && distanceMeters <= addresses[idx].NotificationRadius {
-			validAddresses = append(validAddresses, addresses[idx])
-		}
-	}
-
-	return validAddresses
-}
-
-func collectAddressUserIDs(addresses []*entity.SubscriberAddress) []uuid.UUID {
-	userIDs := make([]uuid.UUID, 0, len(addresses))
-	for _, addr := range addresses {
-		userIDs = append(userIDs, addr.OwnerID)
-	}
-
-	return userIDs
-}
-
 func buildDeviceCollections(devices []*entity.UserDevice) ([]string, map[string]*entity.UserDevice) {
 	tokens := make([]string, 0, len(devices))
 	deviceMap := make(map[string]*entity.UserDevice, len(devices))
@@ -468,6 +1164,9 @@ func (s *notificationService) sendAndProcessNotifications(
 		"location_name":   locationName,
 		"full_address":    fullAddress,
 	}
+	if err := s.deepLinkPolicy.ApplyMerchantAction(notificationData, merchantID.String()); err != nil {
+		s.log(ctx).Warn("Skipping rejected notification deep link", slog.String("error", err.Error()))
+	}
 
 	// Send notifications in batches
 	totalSent, totalFailed, notificationLogs, invalidTokens := s.sendNotificationBatches(
@@ -479,6 +1178,8 @@ func (s *notificationService) sendAndProcessNotifications(
 		notificationData,
 		notification.ID,
 	)
+	s.metrics.TokensSent(totalSent)
+	s.metrics.DeliveryFailed(totalFailed)
 
 	// Batch create notification logs
 	if len(notificationLogs) > 0 {
@@ -492,6 +1193,8 @@ func (s *notificationService) sendAndProcessNotifications(
 	if len(invalidTokens) > 0 {
 		s.handleInvalidTokens(ctx, invalidTokens, deviceMap)
 	}
+	s.resetInvalidTokenStrikes(ctx, notificationLogs)
+	s.recordDeliverySuccess(ctx, notificationLogs)
 
 	// Update notification statistics
 	if err := s.notificationRepo.UpdateNotificationStatus(ctx, notification.ID, totalSent, totalFailed); err != nil {
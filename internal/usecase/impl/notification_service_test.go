@@ -3,14 +3,20 @@ package impl
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"radar/config"
+	"radar/internal/domain/constants"
 	"radar/internal/domain/entity"
 	domainerrors "radar/internal/domain/errors"
 	"radar/internal/domain/policy"
+	"radar/internal/domain/repository"
 	"radar/internal/domain/service"
 	"radar/internal/infra/routing/pmtiles"
 	mockRepo "radar/internal/mocks/repository"
@@ -41,22 +47,42 @@ type failingRoutingService struct {
 	err error
 }
 
-func (s *failingRoutingService) OneToMany(context.Context, usecase.Coordinate, []usecase.Coordinate) (*usecase.OneToManyResult, error) {
+func (s *failingRoutingService) ManyToMany(context.Context, string, []usecase.Coordinate, []usecase.Coordinate) ([]*usecase.OneToManyResult, error) {
 	return nil, s.err
 }
 
-func (s *failingRoutingService) FindNearestNode(context.Context, usecase.Coordinate) (*usecase.NodeInfo, bool, error) {
+func (s *failingRoutingService) OneToMany(context.Context, string, usecase.Coordinate, []usecase.Coordinate) (*usecase.OneToManyResult, error) {
+	return nil, s.err
+}
+
+func (s *failingRoutingService) FindNearestNode(context.Context, string, usecase.Coordinate) (*usecase.NodeInfo, bool, error) {
 	return nil, false, s.err
 }
 
-func (s *failingRoutingService) CalculateDistance(context.Context, usecase.Coordinate, usecase.Coordinate) (*usecase.RouteResult, error) {
+func (s *failingRoutingService) SnapBatch(context.Context, string, []usecase.Coordinate) ([]usecase.NodeInfo, []bool, error) {
+	return nil, nil, s.err
+}
+
+func (s *failingRoutingService) CalculateDistance(context.Context, string, usecase.Coordinate, usecase.Coordinate) (*usecase.RouteResult, error) {
+	return nil, s.err
+}
+
+func (s *failingRoutingService) CalculateRoute(context.Context, string, usecase.Coordinate, usecase.Coordinate) (*usecase.RouteResult, error) {
 	return nil, s.err
 }
 
+func (s *failingRoutingService) NearestRoadName(context.Context, usecase.Coordinate) (string, bool, error) {
+	return "", false, s.err
+}
+
 func (s *failingRoutingService) IsReady() bool {
 	return false
 }
 
+func (s *failingRoutingService) Metadata() usecase.RoutingMetadata {
+	return usecase.RoutingMetadata{}
+}
+
 func (p *fallbackEventPublisher) PublishNotificationEvent(ctx context.Context, event *service.NotificationEvent) error {
 	return p.err
 }
@@ -83,6 +109,8 @@ func createTestNotificationServiceWithRouting(t *testing.T, routingSvc usecase.R
 	notificationRepo := mockRepo.NewMockNotificationRepository(t)
 	subscriptionRepo := mockRepo.NewMockSubscriptionRepository(t)
 	deviceRepo := mockRepo.NewMockDeviceRepository(t)
+	// Every successful send stamps its devices; tests that check the stamp assert the call
+	deviceRepo.EXPECT().RecordDeliverySuccess(mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
 	addressRepo := mockRepo.NewMockAddressRepository(t)
 	notificationSvc := mockSvc.NewMockNotificationService(t)
 	eventPublisher := &fallbackEventPublisher{err: errors.New("pubsub unavailable")}
@@ -109,6 +137,16 @@ func createTestNotificationServiceWithRouting(t *testing.T, routingSvc usecase.R
 	}
 }
 
+// sentTokenResults reports every token as delivered
+func sentTokenResults(tokens ...string) []service.TokenResult {
+	results := make([]service.TokenResult, len(tokens))
+	for idx, token := range tokens {
+		results[idx] = service.TokenResult{Token: token, Status: service.TokenStatusSent}
+	}
+
+	return results
+}
+
 func TestNotificationService_PublishLocationNotification_Success(t *testing.T) {
 	fx := createTestNotificationService(t)
 
@@ -132,12 +170,12 @@ func TestNotificationService_PublishLocationNotification_Success(t *testing.T) {
 
 	userDevice := &entity.UserDevice{ID: uuid.New(), UserID: subscriberOwnerID, FCMToken: "test-fcm-token"}
 	fx.subscriptionRepo.EXPECT().
-		FindDevicesForUsers(ctx, []uuid.UUID{subscriberOwnerID}, policy.DefaultDevicePolicy().HealthyWindowDays).
+		FindDevicesForUsers(ctx, []uuid.UUID{subscriberOwnerID}, policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
 		Return([]*entity.UserDevice{userDevice}, nil)
 
 	fx.notificationSvc.EXPECT().
 		SendBatchNotification(ctx, []string{"test-fcm-token"}, "商戶位置通知", mock.Anything, mock.Anything).
-		Return(1, 0, nil, nil)
+		Return(sentTokenResults("test-fcm-token"), nil)
 
 	fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
 	fx.notificationRepo.EXPECT().UpdateNotificationStatus(ctx, mock.Anything, 1, 0).Return(nil)
@@ -147,6 +185,125 @@ func TestNotificationService_PublishLocationNotification_Success(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, notification)
 	assert.Equal(t, 1, notification.TotalSent)
+	// The inline send keeps the device out of stale-device cleanup, as the worker does
+	fx.deviceRepo.AssertCalled(t, "RecordDeliverySuccess", ctx, []uuid.UUID{userDevice.ID}, mock.Anything)
+}
+
+func TestNotificationService_PublishLocationNotification_SkipsDisabledDevice(t *testing.T) {
+	fx := createTestNotificationService(t)
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationData := &usecase.LocationData{LocationName: "Test Store", FullAddress: "123 Test St", Latitude: 25.0, Longitude: 121.0}
+	subscriberOwnerID := uuid.New()
+	enabled, disabled := true, false
+	phone := &entity.UserDevice{ID: uuid.New(), UserID: subscriberOwnerID, FCMToken: "token-phone"}
+	phone.NotificationsEnabled = &enabled
+	workPhone := &entity.UserDevice{ID: uuid.New(), UserID: subscriberOwnerID, FCMToken: "token-work"}
+	workPhone.NotificationsEnabled = &disabled
+
+	fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+		Return([]*entity.SubscriberAddress{
+			{Address: entity.Address{OwnerID: subscriberOwnerID, Latitude: 25.001, Longitude: 121.001}, NotificationRadius: 1000.0},
+		}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindDevicesForUsers(ctx, []uuid.UUID{subscriberOwnerID}, policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
+		Return([]*entity.UserDevice{phone, workPhone}, nil)
+
+	// Only the enabled device is in the token list
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(ctx, []string{"token-phone"}, "商戶位置通知", mock.Anything, mock.Anything).
+		Return(sentTokenResults("token-phone"), nil)
+	fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
+	fx.notificationRepo.EXPECT().UpdateNotificationStatus(ctx, mock.Anything, 1, 0).Return(nil)
+
+	notification, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "hint")
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, notification.TotalSent)
+}
+
+func TestNotificationService_PublishLocationNotification_IncludesMerchantDeepLink(t *testing.T) {
+	fx := createTestNotificationService(t)
+	svc, ok := fx.service.(*notificationService)
+	require.True(t, ok)
+	svc.deepLinkPolicy = policy.DeepLinkPolicy{
+		Template:       "nomnom://merchants/{merchant_id}",
+		ClickAction:    "VIEW_STORE",
+		AllowedSchemes: []string{"nomnom"},
+	}
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0}
+	subscriberOwnerID := uuid.New()
+
+	fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+		Return([]*entity.SubscriberAddress{
+			{Address: entity.Address{OwnerID: subscriberOwnerID, Latitude: 25.001, Longitude: 121.001}, NotificationRadius: 1000.0},
+		}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindDevicesForUsers(ctx, []uuid.UUID{subscriberOwnerID}, policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
+		Return([]*entity.UserDevice{{ID: uuid.New(), UserID: subscriberOwnerID, FCMToken: "token-a"}}, nil)
+
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(ctx, []string{"token-a"}, mock.Anything, mock.Anything, mock.MatchedBy(func(data map[string]string) bool {
+			return data[constants.NotificationDataDeepLink] == "nomnom://merchants/"+merchantID.String() &&
+				data[constants.NotificationDataClickAction] == "VIEW_STORE"
+		})).
+		Return(sentTokenResults("token-a"), nil)
+	fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
+	fx.notificationRepo.EXPECT().UpdateNotificationStatus(ctx, mock.Anything, 1, 0).Return(nil)
+
+	_, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+	require.NoError(t, err)
+}
+
+func TestNotificationService_PublishLocationNotification_UsesInjectedIDGenerator(t *testing.T) {
+	fx := createTestNotificationService(t)
+	notificationID := uuid.New()
+	logID := uuid.New()
+	svc, ok := fx.service.(*notificationService)
+	require.True(t, ok)
+	svc.idGenerator = newSequenceIDGenerator(t, notificationID, logID)
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0}
+	subscriberOwnerID := uuid.New()
+
+	fx.notificationRepo.EXPECT().
+		CreateNotification(ctx, mock.MatchedBy(func(notification *entity.MerchantLocationNotification) bool {
+			return notification.ID == notificationID
+		})).
+		Return(nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+		Return([]*entity.SubscriberAddress{
+			{Address: entity.Address{OwnerID: subscriberOwnerID, Latitude: 25.001, Longitude: 121.001}, NotificationRadius: 1000.0},
+		}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindDevicesForUsers(ctx, []uuid.UUID{subscriberOwnerID}, policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
+		Return([]*entity.UserDevice{{ID: uuid.New(), UserID: subscriberOwnerID, FCMToken: "token-a"}}, nil)
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(ctx, []string{"token-a"}, mock.Anything, mock.Anything, mock.Anything).
+		Return(sentTokenResults("token-a"), nil)
+	fx.notificationRepo.EXPECT().
+		BatchCreateNotificationLogs(ctx, mock.MatchedBy(func(logs []*entity.NotificationLog) bool {
+			return len(logs) == 1 && logs[0].ID == logID && logs[0].NotificationID == notificationID
+		})).
+		Return(nil)
+	fx.notificationRepo.EXPECT().UpdateNotificationStatus(ctx, notificationID, 1, 0).Return(nil)
+
+	notification, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, notificationID, notification.ID)
 }
 
 func TestNotificationService_PublishLocationNotification_NoSubscribers(t *testing.T) {
@@ -210,13 +367,13 @@ func TestNotificationService_PublishLocationNotification_PartialDeliveryFailure(
 
 	deviceID := uuid.New()
 	fx.subscriptionRepo.EXPECT().
-		FindDevicesForUsers(ctx, []uuid.UUID{subscriberOwnerID}, policy.DefaultDevicePolicy().HealthyWindowDays).
+		FindDevicesForUsers(ctx, []uuid.UUID{subscriberOwnerID}, policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
 		Return([]*entity.UserDevice{{ID: deviceID, UserID: subscriberOwnerID, FCMToken: "bad-token"}}, nil)
 
 	// Simulate: 0 success, 1 failure with an invalid token that should be cleaned up
 	fx.notificationSvc.EXPECT().
 		SendBatchNotification(ctx, []string{"bad-token"}, "商戶位置通知", mock.Anything, mock.Anything).
-		Return(0, 1, []string{"bad-token"}, nil)
+		Return([]service.TokenResult{{Token: "bad-token", Status: service.TokenStatusInvalid, ErrorCode: "UNREGISTERED"}}, nil)
 
 	fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
 	fx.deviceRepo.EXPECT().DeleteDevice(ctx, deviceID).Return(nil)
@@ -229,6 +386,55 @@ func TestNotificationService_PublishLocationNotification_PartialDeliveryFailure(
 	assert.Equal(t, 1, notification.TotalFailed)
 }
 
+func TestNotificationService_PublishLocationNotification_RecordsDeliveryMetrics(t *testing.T) {
+	fx := createTestNotificationService(t)
+	metrics := mockSvc.NewMockNotificationMetrics(t)
+	svc, ok := fx.service.(*notificationService)
+	require.True(t, ok)
+	svc.metrics = metrics
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0}
+	goodOwnerID := uuid.New()
+	badOwnerID := uuid.New()
+
+	fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+		Return([]*entity.SubscriberAddress{
+			{Address: entity.Address{OwnerID: goodOwnerID, Latitude: 25.001, Longitude: 121.001}, NotificationRadius: 1000.0},
+			{Address: entity.Address{OwnerID: badOwnerID, Latitude: 25.002, Longitude: 121.002}, NotificationRadius: 1000.0},
+		}, nil)
+
+	badDeviceID := uuid.New()
+	fx.subscriptionRepo.EXPECT().
+		FindDevicesForUsers(ctx, []uuid.UUID{goodOwnerID, badOwnerID}, policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
+		Return([]*entity.UserDevice{
+			{ID: uuid.New(), UserID: goodOwnerID, FCMToken: "good-token"},
+			{ID: badDeviceID, UserID: badOwnerID, FCMToken: "bad-token"},
+		}, nil)
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(ctx, []string{"good-token", "bad-token"}, "商戶位置通知", mock.Anything, mock.Anything).
+		Return([]service.TokenResult{
+			{Token: "good-token", Status: service.TokenStatusSent},
+			{Token: "bad-token", Status: service.TokenStatusInvalid, ErrorCode: "UNREGISTERED"},
+		}, nil)
+	fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
+	fx.deviceRepo.EXPECT().DeleteDevice(ctx, badDeviceID).Return(nil)
+	fx.notificationRepo.EXPECT().UpdateNotificationStatus(ctx, mock.Anything, 1, 1).Return(nil)
+
+	metrics.EXPECT().NotificationCreated().Return().Once()
+	metrics.EXPECT().ObserveRoutingLatency(mock.AnythingOfType("time.Duration")).Return().Once()
+	metrics.EXPECT().TokensSent(1).Return().Once()
+	metrics.EXPECT().DeliveryFailed(1).Return().Once()
+	metrics.EXPECT().InvalidTokensCleaned(1).Return().Once()
+
+	_, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+	require.NoError(t, err)
+}
+
 func TestNotificationService_PublishLocationNotification_InvalidInput(t *testing.T) {
 	fx := createTestNotificationService(t)
 
@@ -346,6 +552,109 @@ func TestNotificationService_GetMerchantNotificationHistory(t *testing.T) {
 	assert.Equal(t, expected, got)
 }
 
+func TestNotificationService_GetReceivedNotifications(t *testing.T) {
+	fx := createTestNotificationService(t)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	expected := []*entity.ReceivedNotification{
+		{LogID: uuid.New(), NotificationID: uuid.New(), Status: "sent", StoreName: "Night Market Bao"},
+		{LogID: uuid.New(), NotificationID: uuid.New(), Status: "failed", StoreName: "Night Market Bao"},
+	}
+
+	fx.notificationRepo.EXPECT().
+		FindNotificationLogsByUser(ctx, userID, 20, 40).
+		Return(expected, nil)
+
+	got, err := fx.service.GetReceivedNotifications(ctx, userID, 20, 40)
+
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
+}
+
+func TestNotificationService_GetReceivedNotifications_Error(t *testing.T) {
+	fx := createTestNotificationService(t)
+
+	ctx := context.Background()
+	userID := uuid.New()
+
+	fx.notificationRepo.EXPECT().
+		FindNotificationLogsByUser(ctx, userID, 20, 0).
+		Return(nil, domainerrors.ErrPersistenceFailed)
+
+	got, err := fx.service.GetReceivedNotifications(ctx, userID, 20, 0)
+
+	assert.ErrorIs(t, err, domainerrors.ErrPersistenceFailed)
+	assert.Nil(t, got)
+}
+
+func TestNotificationService_GetMerchantBroadcastStats(t *testing.T) {
+	fx := createTestNotificationService(t)
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	oct1 := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	buckets := []*entity.BroadcastDayStats{
+		{Day: oct1, Broadcasts: 2, Recipients: 30},
+		{Day: oct1.AddDate(0, 0, 2), Broadcasts: 1, Recipients: 5},
+		{Day: oct1.AddDate(0, 0, 4), Broadcasts: 3, Recipients: 25},
+	}
+
+	// The range's days are taken in UTC and the repository receives the exclusive end of the last day
+	fx.notificationRepo.EXPECT().
+		SumBroadcastsByDay(ctx, merchantID, oct1, oct1.AddDate(0, 0, 5)).
+		Return(buckets, nil)
+
+	taipei := time.FixedZone("Asia/Taipei", 8*60*60)
+	got, err := fx.service.GetMerchantBroadcastStats(ctx, merchantID,
+		time.Date(2026, 10, 1, 12, 0, 0, 0, taipei), time.Date(2026, 10, 5, 23, 30, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	assert.Equal(t, oct1, got.From)
+	assert.Equal(t, oct1.AddDate(0, 0, 4), got.To)
+	require.Len(t, got.Days, 5)
+	for idx, want := range []struct{ broadcasts, recipients int }{{2, 30}, {0, 0}, {1, 5}, {0, 0}, {3, 25}} {
+		assert.Equal(t, oct1.AddDate(0, 0, idx), got.Days[idx].Day)
+		assert.Equal(t, want.broadcasts, got.Days[idx].Broadcasts, "day %d", idx)
+		assert.Equal(t, want.recipients, got.Days[idx].Recipients, "day %d", idx)
+	}
+	assert.Equal(t, 6, got.TotalBroadcasts)
+	assert.Equal(t, 60, got.TotalRecipients)
+	assert.InDelta(t, 10.0, got.AverageRecipients, 1e-9)
+}
+
+func TestNotificationService_GetMerchantBroadcastStats_EmptyRange(t *testing.T) {
+	fx := createTestNotificationService(t)
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	day := time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC)
+
+	fx.notificationRepo.EXPECT().
+		SumBroadcastsByDay(ctx, merchantID, day, day.AddDate(0, 0, 1)).
+		Return([]*entity.BroadcastDayStats{}, nil)
+
+	got, err := fx.service.GetMerchantBroadcastStats(ctx, merchantID, day, day)
+
+	require.NoError(t, err)
+	assert.Equal(t, []*entity.BroadcastDayStats{{Day: day}}, got.Days)
+	assert.Zero(t, got.TotalBroadcasts)
+	assert.Zero(t, got.AverageRecipients)
+}
+
+func TestNotificationService_GetMerchantBroadcastStats_InvalidRange(t *testing.T) {
+	fx := createTestNotificationService(t)
+
+	ctx := context.Background()
+	day := time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC)
+
+	_, err := fx.service.GetMerchantBroadcastStats(ctx, uuid.New(), day, day.AddDate(0, 0, -1))
+	require.ErrorIs(t, err, domainerrors.ErrValidationFailed)
+
+	_, err = fx.service.GetMerchantBroadcastStats(ctx, uuid.New(), day, day.AddDate(0, 0, maxBroadcastStatsDays))
+	require.ErrorIs(t, err, domainerrors.ErrValidationFailed)
+}
+
 func TestNotificationService_PublishLocationNotification_WithAddressID(t *testing.T) {
 	fx := createTestNotificationService(t)
 
@@ -378,12 +687,12 @@ func TestNotificationService_PublishLocationNotification_WithAddressID(t *testin
 
 	userDevice := &entity.UserDevice{ID: uuid.New(), UserID: subscriberOwnerID, FCMToken: "token-123"}
 	fx.subscriptionRepo.EXPECT().
-		FindDevicesForUsers(ctx, []uuid.UUID{subscriberOwnerID}, policy.DefaultDevicePolicy().HealthyWindowDays).
+		FindDevicesForUsers(ctx, []uuid.UUID{subscriberOwnerID}, policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
 		Return([]*entity.UserDevice{userDevice}, nil)
 
 	fx.notificationSvc.EXPECT().
 		SendBatchNotification(ctx, []string{"token-123"}, "商戶位置通知", mock.Anything, mock.Anything).
-		Return(1, 0, nil, nil)
+		Return(sentTokenResults("token-123"), nil)
 
 	fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
 	fx.notificationRepo.EXPECT().UpdateNotificationStatus(ctx, mock.Anything, 1, 0).Return(nil)
@@ -424,6 +733,9 @@ func TestNotificationService_PublishLocationNotification_CreateNotificationError
 	locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0}
 
 	expectedErr := errors.New("db connection failed")
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+		Return([]*entity.SubscriberAddress{}, nil)
 	fx.notificationRepo.EXPECT().
 		CreateNotification(ctx, mock.Anything).
 		Return(expectedErr)
@@ -452,7 +764,7 @@ func TestNotificationService_PublishLocationNotification_FindDevicesError(t *tes
 		}, nil)
 
 	fx.subscriptionRepo.EXPECT().
-		FindDevicesForUsers(ctx, []uuid.UUID{subscriberOwnerID}, policy.DefaultDevicePolicy().HealthyWindowDays).
+		FindDevicesForUsers(ctx, []uuid.UUID{subscriberOwnerID}, policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
 		Return(nil, errors.New("device query failed"))
 
 	notification, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
@@ -480,13 +792,13 @@ func TestNotificationService_PublishLocationNotification_SendBatchError(t *testi
 
 	userDevice := &entity.UserDevice{ID: uuid.New(), UserID: subscriberOwnerID, FCMToken: "token-xyz"}
 	fx.subscriptionRepo.EXPECT().
-		FindDevicesForUsers(ctx, []uuid.UUID{subscriberOwnerID}, policy.DefaultDevicePolicy().HealthyWindowDays).
+		FindDevicesForUsers(ctx, []uuid.UUID{subscriberOwnerID}, policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
 		Return([]*entity.UserDevice{userDevice}, nil)
 
 	// SendBatchNotification returns an error (e.g., Firebase service unavailable)
 	fx.notificationSvc.EXPECT().
 		SendBatchNotification(ctx, []string{"token-xyz"}, "商戶位置通知", mock.Anything, mock.Anything).
-		Return(0, 0, nil, errors.New("firebase unavailable"))
+		Return(nil, errors.New("firebase unavailable"))
 
 	// Even with error, the flow continues and updates status with all failures
 	fx.notificationRepo.EXPECT().UpdateNotificationStatus(ctx, mock.Anything, 0, 1).Return(nil)
@@ -517,12 +829,12 @@ func TestNotificationService_PublishLocationNotification_UpdateStatusError(t *te
 
 	userDevice := &entity.UserDevice{ID: uuid.New(), UserID: subscriberOwnerID, FCMToken: "token-abc"}
 	fx.subscriptionRepo.EXPECT().
-		FindDevicesForUsers(ctx, []uuid.UUID{subscriberOwnerID}, policy.DefaultDevicePolicy().HealthyWindowDays).
+		FindDevicesForUsers(ctx, []uuid.UUID{subscriberOwnerID}, policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
 		Return([]*entity.UserDevice{userDevice}, nil)
 
 	fx.notificationSvc.EXPECT().
 		SendBatchNotification(ctx, []string{"token-abc"}, "商戶位置通知", mock.Anything, mock.Anything).
-		Return(1, 0, nil, nil)
+		Return(sentTokenResults("token-abc"), nil)
 
 	fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
 	fx.notificationRepo.EXPECT().
@@ -562,7 +874,7 @@ func TestNotificationService_PublishLocationNotification_MultipleSubscribers(t *
 	fx.subscriptionRepo.EXPECT().
 		FindDevicesForUsers(ctx, mock.MatchedBy(func(ids []uuid.UUID) bool {
 			return assert.ElementsMatch(t, []uuid.UUID{user1ID, user2ID}, ids)
-		}), policy.DefaultDevicePolicy().HealthyWindowDays).
+		}), policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
 		Return([]*entity.UserDevice{
 			{ID: uuid.New(), UserID: user1ID, FCMToken: "token-1"},
 			{ID: uuid.New(), UserID: user2ID, FCMToken: "token-2"},
@@ -572,7 +884,7 @@ func TestNotificationService_PublishLocationNotification_MultipleSubscribers(t *
 		SendBatchNotification(ctx, mock.MatchedBy(func(tokens []string) bool {
 			return assert.ElementsMatch(t, []string{"token-1", "token-2"}, tokens)
 		}), "商戶位置通知", mock.Anything, mock.Anything).
-		Return(2, 0, nil, nil)
+		Return(sentTokenResults("token-1", "token-2"), nil)
 
 	fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
 	fx.notificationRepo.EXPECT().UpdateNotificationStatus(ctx, mock.Anything, 2, 0).Return(nil)
@@ -602,7 +914,7 @@ func TestNotificationService_PublishLocationNotification_NoDevicesForSubscribers
 
 	// Subscriber exists but has no registered devices
 	fx.subscriptionRepo.EXPECT().
-		FindDevicesForUsers(ctx, []uuid.UUID{subscriberOwnerID}, policy.DefaultDevicePolicy().HealthyWindowDays).
+		FindDevicesForUsers(ctx, []uuid.UUID{subscriberOwnerID}, policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
 		Return([]*entity.UserDevice{}, nil)
 
 	notification, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
@@ -669,12 +981,12 @@ func TestNotificationService_HaversineDistanceFiltering(t *testing.T) {
 	// Only the nearby subscriber should have their device queried
 	nearbyDevice := &entity.UserDevice{ID: uuid.New(), UserID: nearbyOwner, FCMToken: "nearby-token"}
 	fx.subscriptionRepo.EXPECT().
-		FindDevicesForUsers(ctx, []uuid.UUID{nearbyOwner}, policy.DefaultDevicePolicy().HealthyWindowDays).
+		FindDevicesForUsers(ctx, []uuid.UUID{nearbyOwner}, policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
 		Return([]*entity.UserDevice{nearbyDevice}, nil)
 
 	fx.notificationSvc.EXPECT().
 		SendBatchNotification(ctx, []string{"nearby-token"}, "商戶位置通知", mock.Anything, mock.Anything).
-		Return(1, 0, nil, nil)
+		Return(sentTokenResults("nearby-token"), nil)
 
 	fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
 	fx.notificationRepo.EXPECT().UpdateNotificationStatus(ctx, mock.Anything, 1, 0).Return(nil)
@@ -687,3 +999,1306 @@ func TestNotificationService_HaversineDistanceFiltering(t *testing.T) {
 	assert.Equal(t, 1, notification.TotalSent)
 	assert.Equal(t, 0, notification.TotalFailed)
 }
+
+func TestNotificationService_HandleInvalidTokens_DeduplicatesDevices(t *testing.T) {
+	fx := createTestNotificationService(t)
+	svc := fx.service.(*notificationService)
+
+	ctx := context.Background()
+	deviceID := uuid.New()
+	otherDeviceID := uuid.New()
+	deviceMap := map[string]*entity.UserDevice{
+		"bad-token":   {ID: deviceID, FCMToken: "bad-token"},
+		"other-token": {ID: otherDeviceID, FCMToken: "other-token"},
+	}
+
+	fx.deviceRepo.EXPECT().DeleteDevice(ctx, deviceID).Return(nil).Once()
+	fx.deviceRepo.EXPECT().DeleteDevice(ctx, otherDeviceID).Return(nil).Once()
+
+	svc.handleInvalidTokens(ctx, []string{"bad-token", "other-token", "bad-token", "unknown-token"}, deviceMap)
+}
+
+func TestNotificationService_HandleInvalidTokens_ConcurrentCleanupsSucceed(t *testing.T) {
+	fx := createTestNotificationService(t)
+	svc := fx.service.(*notificationService)
+
+	ctx := context.Background()
+	deviceID := uuid.New()
+	deviceMap := map[string]*entity.UserDevice{
+		"bad-token": {ID: deviceID, FCMToken: "bad-token"},
+	}
+
+	// The repository treats a second delete of the same device as a no-op success,
+	// so each concurrent cleanup deletes the device once without surfacing an error.
+	fx.deviceRepo.EXPECT().DeleteDevice(ctx, deviceID).Return(nil).Times(2)
+
+	var waitGroup sync.WaitGroup
+	for range 2 {
+		waitGroup.Go(func() {
+			svc.handleInvalidTokens(ctx, []string{"bad-token", "bad-token"}, deviceMap)
+		})
+	}
+	waitGroup.Wait()
+}
+
+func TestNotificationService_HandleInvalidTokens_StrikePolicy(t *testing.T) {
+	fx := createTestNotificationService(t)
+	svc := fx.service.(*notificationService)
+	now := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	svc.clock = newFakeClock(now)
+	svc.invalidTokenPolicy = policy.InvalidTokenPolicy{Strikes: 3, Window: 72 * time.Hour}
+
+	ctx := context.Background()
+	deviceID := uuid.New()
+	deviceMap := map[string]*entity.UserDevice{
+		"flaky-token": {ID: deviceID, FCMToken: "flaky-token"},
+	}
+
+	// The first two invalid responses only add strikes; the third reaches the count and deletes the device
+	fx.deviceRepo.EXPECT().RecordInvalidTokenStrike(ctx, deviceID, now, 72*time.Hour).Return(1, nil).Once()
+	fx.deviceRepo.EXPECT().RecordInvalidTokenStrike(ctx, deviceID, now, 72*time.Hour).Return(2, nil).Once()
+	fx.deviceRepo.EXPECT().RecordInvalidTokenStrike(ctx, deviceID, now, 72*time.Hour).Return(3, nil).Once()
+	fx.deviceRepo.EXPECT().DeleteDevice(ctx, deviceID).Return(nil).Once()
+
+	svc.handleInvalidTokens(ctx, []string{"flaky-token"}, deviceMap)
+	fx.deviceRepo.AssertNotCalled(t, "DeleteDevice", ctx, deviceID)
+	svc.handleInvalidTokens(ctx, []string{"flaky-token"}, deviceMap)
+	fx.deviceRepo.AssertNotCalled(t, "DeleteDevice", ctx, deviceID)
+	svc.handleInvalidTokens(ctx, []string{"flaky-token"}, deviceMap)
+}
+
+func TestNotificationService_ResetInvalidTokenStrikes(t *testing.T) {
+	fx := createTestNotificationService(t)
+	svc := fx.service.(*notificationService)
+
+	ctx := context.Background()
+	sentDeviceID := uuid.New()
+	logs := []*entity.NotificationLog{
+		{DeviceID: sentDeviceID, Status: "sent"},
+		{DeviceID: uuid.New(), Status: "failed", ErrorMessage: "unregistered token"},
+	}
+
+	// Without strike tracking there is nothing to reset
+	svc.resetInvalidTokenStrikes(ctx, logs)
+
+	svc.invalidTokenPolicy = policy.InvalidTokenPolicy{Strikes: 3, Window: 72 * time.Hour}
+	fx.deviceRepo.EXPECT().ResetInvalidTokenStrikes(ctx, []uuid.UUID{sentDeviceID}).Return(nil).Once()
+
+	svc.resetInvalidTokenStrikes(ctx, logs)
+}
+
+// scriptedRoutingService returns preset snap and route results index-aligned with the targets
+type scriptedRoutingService struct {
+	failingRoutingService
+	nodes   []usecase.NodeInfo
+	snapped []bool
+	results []usecase.RouteResult
+}
+
+func (s *scriptedRoutingService) SnapBatch(context.Context, string, []usecase.Coordinate) ([]usecase.NodeInfo, []bool, error) {
+	return s.nodes, s.snapped, nil
+}
+
+func (s *scriptedRoutingService) OneToMany(_ context.Context, _ string, source usecase.Coordinate, targets []usecase.Coordinate) (*usecase.OneToManyResult, error) {
+	return &usecase.OneToManyResult{Source: source, Targets: targets, Results: s.results}, nil
+}
+
+func TestNotificationService_GetSubscriberSnapshots(t *testing.T) {
+	routingSvc := &scriptedRoutingService{
+		nodes: []usecase.NodeInfo{
+			{ID: 11, Location: usecase.Coordinate{Lat: 25.0011, Lng: 121.0011}},
+			{ID: 12, Location: usecase.Coordinate{Lat: 25.0101, Lng: 121.0101}},
+			{},
+		},
+		snapped: []bool{true, true, false},
+		results: []usecase.RouteResult{
+			{DistanceKm: 0.4, DurationMin: 5, IsReachable: true},
+			{DistanceKm: 2.5, DurationMin: 30, IsReachable: true},
+			{IsReachable: false, UnreachableReason: usecase.UnreachableReasonOffNetwork},
+		},
+	}
+	fx := createTestNotificationServiceWithRouting(t, routingSvc)
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	addressID := uuid.New()
+	address := &entity.Address{
+		ID:        addressID,
+		OwnerID:   merchantID,
+		OwnerType: entity.OwnerTypeMerchantProfile,
+		Latitude:  25.0,
+		Longitude: 121.0,
+	}
+	subscriberAddresses := []*entity.SubscriberAddress{
+		{Address: entity.Address{ID: uuid.New(), OwnerID: uuid.New(), Latitude: 25.001, Longitude: 121.001}, NotificationRadius: 1000},
+		{Address: entity.Address{ID: uuid.New(), OwnerID: uuid.New(), Latitude: 25.010, Longitude: 121.010}, NotificationRadius: 2000},
+		{Address: entity.Address{ID: uuid.New(), OwnerID: uuid.New(), Latitude: 25.020, Longitude: 121.020}, NotificationRadius: 3000},
+	}
+
+	fx.addressRepo.EXPECT().FindAddressByID(ctx, addressID).Return(address, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, address.Latitude, address.Longitude).
+		Return(subscriberAddresses, nil)
+
+	snapshots, err := fx.service.GetSubscriberSnapshots(ctx, merchantID, addressID)
+
+	require.NoError(t, err)
+	require.Len(t, snapshots, 3)
+
+	assert.Equal(t, subscriberAddresses[0].ID, snapshots[0].AddressID)
+	require.NotNil(t, snapshots[0].SnapNode)
+	assert.Equal(t, usecase.NodeID(11), snapshots[0].SnapNode.ID)
+	assert.InDelta(t, 0.4, snapshots[0].DistanceKm, 1e-9)
+	assert.InDelta(t, 5.0, snapshots[0].DurationMin, 1e-9)
+	assert.True(t, snapshots[0].IsReachable)
+	assert.True(t, snapshots[0].WithinRadius)
+
+	assert.Equal(t, subscriberAddresses[1].ID, snapshots[1].AddressID)
+	require.NotNil(t, snapshots[1].SnapNode)
+	assert.True(t, snapshots[1].IsReachable)
+	assert.False(t, snapshots[1].WithinRadius, "2.5 km road distance exceeds the 2 km radius")
+
+	assert.Equal(t, subscriberAddresses[2].ID, snapshots[2].AddressID)
+	assert.Nil(t, snapshots[2].SnapNode)
+	assert.False(t, snapshots[2].IsReachable)
+	assert.Equal(t, usecase.UnreachableReasonOffNetwork, snapshots[2].UnreachableReason)
+	assert.False(t, snapshots[2].WithinRadius)
+	assert.InDelta(t, 3000.0, snapshots[2].NotificationRadius, 1e-9)
+}
+
+func TestNotificationService_GetSubscriberSnapshots_AddressOwnershipViolation(t *testing.T) {
+	fx := createTestNotificationService(t)
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	addressID := uuid.New()
+
+	fx.addressRepo.EXPECT().
+		FindAddressByID(ctx, addressID).
+		Return(&entity.Address{ID: addressID, OwnerID: uuid.New(), OwnerType: entity.OwnerTypeMerchantProfile}, nil)
+
+	snapshots, err := fx.service.GetSubscriberSnapshots(ctx, merchantID, addressID)
+
+	assert.Nil(t, snapshots)
+	assert.ErrorIs(t, err, domainerrors.ErrAddressOwnershipViolation)
+}
+
+func TestNotificationService_GetSubscriptionReachability(t *testing.T) {
+	routingSvc := &scriptedRoutingService{
+		nodes:   []usecase.NodeInfo{{ID: 21, Location: usecase.Coordinate{Lat: 25.0011, Lng: 121.0011}}},
+		snapped: []bool{true},
+		results: []usecase.RouteResult{{DistanceKm: 0.6, DurationMin: 8, IsReachable: true}},
+	}
+	fx := createTestNotificationServiceWithRouting(t, routingSvc)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	merchantID := uuid.New()
+	from := usecase.Coordinate{Lat: 25.0, Lng: 121.0}
+	subscriberAddresses := []*entity.SubscriberAddress{
+		{Address: entity.Address{ID: uuid.New(), OwnerID: userID, Latitude: 25.001, Longitude: 121.001}, NotificationRadius: 1000},
+	}
+
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriptionByUserAndMerchant(ctx, userID, merchantID).
+		Return(&entity.UserMerchantSubscription{UserID: userID, MerchantID: merchantID, IsActive: true}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByUserIDs(ctx, merchantID, []uuid.UUID{userID}).
+		Return(subscriberAddresses, nil)
+
+	snapshots, err := fx.service.GetSubscriptionReachability(ctx, userID, merchantID, from)
+
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, subscriberAddresses[0].ID, snapshots[0].AddressID)
+	require.NotNil(t, snapshots[0].SnapNode)
+	assert.True(t, snapshots[0].IsReachable)
+	assert.True(t, snapshots[0].WithinRadius)
+}
+
+func TestNotificationService_GetSubscriptionReachability_RequiresActiveSubscription(t *testing.T) {
+	tests := []struct {
+		name         string
+		subscription *entity.UserMerchantSubscription
+		findErr      error
+	}{
+		{name: "not subscribed", findErr: domainerrors.ErrSubscriptionNotFound},
+		{name: "inactive subscription", subscription: &entity.UserMerchantSubscription{IsActive: false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := createTestNotificationService(t)
+
+			ctx := context.Background()
+			userID := uuid.New()
+			merchantID := uuid.New()
+
+			fx.subscriptionRepo.EXPECT().
+				FindSubscriptionByUserAndMerchant(ctx, userID, merchantID).
+				Return(tt.subscription, tt.findErr)
+
+			snapshots, err := fx.service.GetSubscriptionReachability(ctx, userID, merchantID, usecase.Coordinate{Lat: 25.0, Lng: 121.0})
+
+			assert.Nil(t, snapshots)
+			assert.ErrorIs(t, err, domainerrors.ErrSubscriptionNotFound)
+		})
+	}
+}
+
+func TestNotificationService_RecordNotificationOpened(t *testing.T) {
+	tests := []struct {
+		name    string
+		opened  bool
+		markErr error
+		wantErr error
+	}{
+		{name: "first open", opened: true},
+		{name: "repeated open is idempotent", opened: false},
+		{name: "never received", markErr: domainerrors.ErrNotificationNotFound, wantErr: domainerrors.ErrNotificationNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := createTestNotificationService(t)
+			svc, ok := fx.service.(*notificationService)
+			require.True(t, ok)
+			now := time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC)
+			svc.clock = newFakeClock(now)
+
+			ctx := context.Background()
+			userID := uuid.New()
+			notificationID := uuid.New()
+
+			fx.notificationRepo.EXPECT().
+				MarkNotificationOpened(ctx, notificationID, userID, now).
+				Return(tt.opened, tt.markErr)
+
+			err := fx.service.RecordNotificationOpened(ctx, userID, notificationID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNotificationService_PublishLocationNotification_InvalidHintRejected(t *testing.T) {
+	tests := []struct {
+		name string
+		hint string
+		err  error
+	}{
+		{name: "over-long hint", hint: strings.Repeat("a", policy.DefaultHintMessagePolicy().MaxLength+1), err: policy.ErrHintMessageTooLong},
+		{name: "multi-line hint", hint: "first spot\nby the corner", err: policy.ErrHintMessageMultiline},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := createTestNotificationService(t)
+
+			locationData := &usecase.LocationData{LocationName: "Test Store", FullAddress: "123 Test St", Latitude: 25.0, Longitude: 121.0}
+
+			notification, err := fx.service.PublishLocationNotification(context.Background(), uuid.New(), nil, locationData, tt.hint)
+
+			assert.Nil(t, notification)
+			assert.ErrorIs(t, err, domainerrors.ErrInvalidNotificationData)
+			appErr, ok := errors.AsType[domainerrors.AppError](err)
+			require.True(t, ok)
+			assert.Contains(t, appErr.Details(), tt.err.Error())
+		})
+	}
+}
+
+func TestNotificationService_PublishLocationNotification_StripsHintControlCharacters(t *testing.T) {
+	fx := createTestNotificationService(t)
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationData := &usecase.LocationData{LocationName: "Test Store", FullAddress: "123 Test St", Latitude: 25.0, Longitude: 121.0}
+
+	fx.notificationRepo.EXPECT().
+		CreateNotification(ctx, mock.MatchedBy(func(notification *entity.MerchantLocationNotification) bool {
+			return notification.HintMessage == "By the gate"
+		})).
+		Return(nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+		Return([]*entity.SubscriberAddress{}, nil)
+
+	notification, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "By the\x00 gate\x07")
+
+	require.NoError(t, err)
+	assert.Equal(t, "By the gate", notification.HintMessage)
+}
+
+func TestNotificationService_PublishLocationNotification_SendsBatchesConcurrently(t *testing.T) {
+	const concurrency = 3
+
+	fx := createTestNotificationService(t)
+	svc, ok := fx.service.(*notificationService)
+	require.True(t, ok)
+	svc.maxConcurrency = concurrency
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0}
+	subscriberOwnerID := uuid.New()
+
+	// 1201 devices split into three provider batches of 500, 500 and 201 tokens
+	devices := make([]*entity.UserDevice, 2*firebaseBatchSize+201)
+	for i := range devices {
+		devices[i] = &entity.UserDevice{ID: uuid.New(), UserID: subscriberOwnerID, FCMToken: fmt.Sprintf("token-%04d", i)}
+	}
+
+	fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+		Return([]*entity.SubscriberAddress{
+			{Address: entity.Address{OwnerID: subscriberOwnerID, Latitude: 25.001, Longitude: 121.001}, NotificationRadius: 1000.0},
+		}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindDevicesForUsers(ctx, []uuid.UUID{subscriberOwnerID}, policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
+		Return(devices, nil)
+
+	var started sync.WaitGroup
+	started.Add(concurrency)
+	allStarted := make(chan struct{})
+	go func() {
+		started.Wait()
+		close(allStarted)
+	}()
+
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, tokens []string, _, _ string, _ map[string]string) ([]service.TokenResult, error) {
+			// Every batch waits until all of them are in flight, which only happens with concurrent dispatch
+			started.Done()
+			select {
+			case <-allStarted:
+			case <-time.After(2 * time.Second):
+				return nil, errors.New("batches were not dispatched concurrently")
+			}
+
+			switch tokens[0] {
+			case "token-0000":
+				results := sentTokenResults(tokens...)
+				results[0].Status, results[0].ErrorCode = service.TokenStatusFailed, "INVALID_ARGUMENT"
+
+				return results, nil
+			case fmt.Sprintf("token-%04d", firebaseBatchSize):
+				return sentTokenResults(tokens...), nil
+			default:
+				return nil, errors.New("provider error")
+			}
+		}).
+		Times(concurrency)
+
+	fx.notificationRepo.EXPECT().
+		BatchCreateNotificationLogs(ctx, mock.MatchedBy(func(logs []*entity.NotificationLog) bool {
+			return len(logs) == 2*firebaseBatchSize
+		})).
+		Return(nil)
+	fx.notificationRepo.EXPECT().UpdateNotificationStatus(ctx, mock.Anything, 999, 202).Return(nil)
+
+	notification, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, 999, notification.TotalSent)
+	assert.Equal(t, 202, notification.TotalFailed)
+}
+
+func TestNotificationService_PublishLocationNotification_SkipsSnoozedSubscribers(t *testing.T) {
+	fx := createTestNotificationService(t)
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0}
+	activeSnooze := time.Now().Add(2 * time.Hour)
+	expiredSnooze := time.Now().Add(-time.Minute)
+
+	fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil)
+
+	snoozedUserID := uuid.New()
+	expiredUserID := uuid.New()
+	plainUserID := uuid.New()
+
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+		Return([]*entity.SubscriberAddress{
+			{Address: entity.Address{OwnerID: snoozedUserID, Latitude: 25.001, Longitude: 121.001}, NotificationRadius: 1000.0, SnoozedUntil: &activeSnooze},
+			{Address: entity.Address{OwnerID: expiredUserID, Latitude: 25.002, Longitude: 121.002}, NotificationRadius: 1000.0, SnoozedUntil: &expiredSnooze},
+			{Address: entity.Address{OwnerID: plainUserID, Latitude: 25.001, Longitude: 121.002}, NotificationRadius: 1000.0},
+		}, nil)
+
+	// The actively snoozed subscriber is suppressed; the expired snooze no longer applies
+	fx.subscriptionRepo.EXPECT().
+		FindDevicesForUsers(ctx, mock.MatchedBy(func(ids []uuid.UUID) bool {
+			return assert.ElementsMatch(t, []uuid.UUID{expiredUserID, plainUserID}, ids)
+		}), policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
+		Return([]*entity.UserDevice{
+			{ID: uuid.New(), UserID: expiredUserID, FCMToken: "token-1"},
+			{ID: uuid.New(), UserID: plainUserID, FCMToken: "token-2"},
+		}, nil)
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(ctx, mock.Anything, "商戶位置通知", mock.Anything, mock.Anything).
+		Return(sentTokenResults("token-1", "token-2"), nil)
+	fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
+	fx.notificationRepo.EXPECT().UpdateNotificationStatus(ctx, mock.Anything, 2, 0).Return(nil)
+
+	notification, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, notification.TotalSent)
+}
+
+func TestNotificationService_PublishLocationNotification_AllSubscribersSnoozed(t *testing.T) {
+	fx := createTestNotificationService(t)
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0}
+	activeSnooze := time.Now().Add(2 * time.Hour)
+
+	fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+		Return([]*entity.SubscriberAddress{
+			{Address: entity.Address{OwnerID: uuid.New(), Latitude: 25.001, Longitude: 121.001}, NotificationRadius: 1000.0, SnoozedUntil: &activeSnooze},
+		}, nil)
+
+	// No device lookup or send happens when every candidate is snoozed
+	notification, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, notification.TotalSent)
+}
+
+func TestNotificationService_PublishLocationNotification_EmptyLocationFallbackBody(t *testing.T) {
+	fx := createTestNotificationService(t)
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	userID := uuid.New()
+	locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0, FullAddress: "123 Test St"}
+
+	fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+		Return([]*entity.SubscriberAddress{
+			{Address: entity.Address{OwnerID: userID, Latitude: 25.001, Longitude: 121.001}, NotificationRadius: 1000.0},
+		}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindDevicesForUsers(ctx, []uuid.UUID{userID}, policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
+		Return([]*entity.UserDevice{{ID: uuid.New(), UserID: userID, FCMToken: "token-1"}}, nil)
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(ctx, []string{"token-1"}, "商戶位置通知", "您追蹤的商家 已在 123 Test St 開始營業", mock.Anything).
+		Return(sentTokenResults("token-1"), nil)
+	fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
+	fx.notificationRepo.EXPECT().UpdateNotificationStatus(ctx, mock.Anything, 1, 0).Return(nil)
+
+	_, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+	require.NoError(t, err)
+}
+
+// recordingEventPublisher captures published events and always succeeds
+type recordingEventPublisher struct {
+	events []*service.NotificationEvent
+}
+
+func (p *recordingEventPublisher) PublishNotificationEvent(_ context.Context, event *service.NotificationEvent) error {
+	p.events = append(p.events, event)
+
+	return nil
+}
+
+func (p *recordingEventPublisher) Close() error {
+	return nil
+}
+
+// newReachabilityFixture returns subscriber addresses where only the first and third fit their radius by road
+func newReachabilityFixture() (*scriptedRoutingService, []*entity.SubscriberAddress) {
+	routingSvc := &scriptedRoutingService{
+		results: []usecase.RouteResult{
+			{DistanceKm: 0.4, IsReachable: true},
+			{DistanceKm: 2.5, IsReachable: true},
+			{DistanceKm: 0.9, IsReachable: true},
+			{IsReachable: false},
+		},
+	}
+	addresses := []*entity.SubscriberAddress{
+		{Address: entity.Address{OwnerID: uuid.New(), Latitude: 25.001, Longitude: 121.001}, NotificationRadius: 1000},
+		{Address: entity.Address{OwnerID: uuid.New(), Latitude: 25.010, Longitude: 121.010}, NotificationRadius: 2000},
+		{Address: entity.Address{OwnerID: uuid.New(), Latitude: 25.005, Longitude: 121.005}, NotificationRadius: 1000},
+		{Address: entity.Address{OwnerID: uuid.New(), Latitude: 25.020, Longitude: 121.020}, NotificationRadius: 3000},
+	}
+
+	return routingSvc, addresses
+}
+
+func TestNotificationService_PublishLocationNotification_InlineMatchesSharedReachability(t *testing.T) {
+	routingSvc, addresses := newReachabilityFixture()
+	fx := createTestNotificationServiceWithRouting(t, routingSvc)
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0}
+
+	expected, err := usecase.FilterReachableAddresses(ctx, routingSvc, usecase.RadiusPolicy{}, usecase.Coordinate{Lat: 25.0, Lng: 121.0}, addresses)
+	require.NoError(t, err)
+	expectedIDs := usecase.SubscriberIDs(expected)
+	require.Len(t, expectedIDs, 2)
+
+	fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+		Return(addresses, nil)
+	// The inline path must select exactly the subscribers the shared filter includes
+	fx.subscriptionRepo.EXPECT().
+		FindDevicesForUsers(ctx, expectedIDs, policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
+		Return([]*entity.UserDevice{}, nil)
+
+	_, err = fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+	require.NoError(t, err)
+}
+
+func TestNotificationService_PublishLocationNotification_PrefilterReachability(t *testing.T) {
+	routingSvc, addresses := newReachabilityFixture()
+	fx := createTestNotificationServiceWithRouting(t, routingSvc)
+	publisher := &recordingEventPublisher{}
+	svc, ok := fx.service.(*notificationService)
+	require.True(t, ok)
+	svc.eventPublisher = publisher
+	svc.prefilterReachability = true
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0}
+
+	fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+		Return(addresses, nil)
+
+	_, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+	require.NoError(t, err)
+	require.Len(t, publisher.events, 1)
+	assert.True(t, publisher.events[0].ReachabilityFiltered)
+	assert.Equal(t, []string{addresses[0].OwnerID.String(), addresses[2].OwnerID.String()}, publisher.events[0].SubscriberIDs)
+}
+
+func TestNotificationService_PublishLocationNotification_RoutingDisabledScalesRadius(t *testing.T) {
+	// With routing disabled every distance is a straight-line estimate
+	routingSvc := &scriptedRoutingService{
+		results: []usecase.RouteResult{
+			{DistanceKm: 0.6, IsReachable: true, IsEstimate: true},
+			{DistanceKm: 0.9, IsReachable: true, IsEstimate: true},
+		},
+	}
+	addresses := []*entity.SubscriberAddress{
+		{Address: entity.Address{OwnerID: uuid.New(), Latitude: 25.001, Longitude: 121.001}, NotificationRadius: 1000},
+		{Address: entity.Address{OwnerID: uuid.New(), Latitude: 25.005, Longitude: 121.005}, NotificationRadius: 1000},
+	}
+
+	tests := []struct {
+		name    string
+		factor  float64
+		wantIDs []string
+	}{
+		{name: "radius treated as straight-line", factor: 0, wantIDs: []string{addresses[0].OwnerID.String(), addresses[1].OwnerID.String()}},
+		{name: "radius narrowed for road detours", factor: 0.7, wantIDs: []string{addresses[0].OwnerID.String()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := createTestNotificationServiceWithRouting(t, routingSvc)
+			publisher := &recordingEventPublisher{}
+			svc, ok := fx.service.(*notificationService)
+			require.True(t, ok)
+			svc.eventPublisher = publisher
+			svc.prefilterReachability = true
+			svc.radiusPolicy = usecase.NewRadiusPolicy(&config.Config{Routing: &config.RoutingConfig{
+				Backend:                  config.RoutingBackendHaversine,
+				StraightLineRadiusFactor: tt.factor,
+			}})
+
+			ctx := context.Background()
+			merchantID := uuid.New()
+			locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0}
+
+			fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil)
+			fx.subscriptionRepo.EXPECT().
+				FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+				Return(addresses, nil)
+
+			_, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+			require.NoError(t, err)
+			require.Len(t, publisher.events, 1)
+			assert.Equal(t, tt.wantIDs, publisher.events[0].SubscriberIDs)
+		})
+	}
+}
+
+func TestNotificationService_PublishLocationNotification_PrefilterFailureLeavesCheckToWorker(t *testing.T) {
+	_, addresses := newReachabilityFixture()
+	fx := createTestNotificationServiceWithRouting(t, &failingRoutingService{err: errors.New("routing unavailable")})
+	publisher := &recordingEventPublisher{}
+	svc, ok := fx.service.(*notificationService)
+	require.True(t, ok)
+	svc.eventPublisher = publisher
+	svc.prefilterReachability = true
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0}
+
+	fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+		Return(addresses, nil)
+
+	_, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+	require.NoError(t, err)
+	require.Len(t, publisher.events, 1)
+	assert.False(t, publisher.events[0].ReachabilityFiltered)
+	assert.Len(t, publisher.events[0].SubscriberIDs, len(addresses))
+}
+
+// newMultiLocationFixture builds a notification service that records published events and returns
+// two location inputs about 1.5 km apart
+func newMultiLocationFixture(t *testing.T) (notificationServiceFixtures, *recordingEventPublisher, []usecase.PublishLocationInput) {
+	t.Helper()
+
+	fx := createTestNotificationService(t)
+	publisher := &recordingEventPublisher{}
+	svc, ok := fx.service.(*notificationService)
+	require.True(t, ok)
+	svc.eventPublisher = publisher
+
+	inputs := []usecase.PublishLocationInput{
+		{LocationData: &usecase.LocationData{LocationName: "Stall A", FullAddress: "1 Market St", Latitude: 25.0, Longitude: 121.0}},
+		{LocationData: &usecase.LocationData{LocationName: "Stall B", FullAddress: "9 Market St", Latitude: 25.01, Longitude: 121.01}},
+	}
+
+	return fx, publisher, inputs
+}
+
+func newNearbySubscriber(lat, lng float64) *entity.SubscriberAddress {
+	return &entity.SubscriberAddress{
+		Address:            entity.Address{ID: uuid.New(), OwnerID: uuid.New(), Latitude: lat, Longitude: lng},
+		NotificationRadius: 2000,
+	}
+}
+
+func TestNotificationService_PublishMultiLocation_DistinctLocations(t *testing.T) {
+	fx, publisher, inputs := newMultiLocationFixture(t)
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	nearA := newNearbySubscriber(25.001, 121.001)
+	nearB := newNearbySubscriber(25.011, 121.011)
+
+	fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil).Twice()
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, 25.0, 121.0).
+		Return([]*entity.SubscriberAddress{nearA}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, 25.01, 121.01).
+		Return([]*entity.SubscriberAddress{nearB}, nil)
+
+	results, err := fx.service.PublishMultiLocation(ctx, merchantID, inputs)
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		require.NoError(t, result.Err)
+		require.NotNil(t, result.Notification)
+	}
+	assert.Equal(t, "Stall A", results[0].Notification.LocationName)
+	assert.Equal(t, "Stall B", results[1].Notification.LocationName)
+
+	require.Len(t, publisher.events, 2)
+	assert.Equal(t, []string{nearA.OwnerID.String()}, publisher.events[0].SubscriberIDs)
+	assert.Equal(t, []string{nearB.OwnerID.String()}, publisher.events[1].SubscriberIDs)
+	assert.True(t, publisher.events[0].ReachabilityFiltered)
+	assert.True(t, publisher.events[1].ReachabilityFiltered)
+}
+
+func TestNotificationService_PublishMultiLocation_DedupesOverlappingSubscribers(t *testing.T) {
+	fx, publisher, inputs := newMultiLocationFixture(t)
+	inputs = append(inputs, usecase.PublishLocationInput{
+		LocationData: &usecase.LocationData{LocationName: "Stall C", FullAddress: "5 Market St", Latitude: 25.005, Longitude: 121.005},
+	})
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	nearA := newNearbySubscriber(25.001, 121.001)
+	between := newNearbySubscriber(25.005, 121.005)
+	nearB := newNearbySubscriber(25.011, 121.011)
+
+	fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil).Times(3)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, 25.0, 121.0).
+		Return([]*entity.SubscriberAddress{nearA, between}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, 25.01, 121.01).
+		Return([]*entity.SubscriberAddress{between, nearB}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, 25.005, 121.005).
+		Return([]*entity.SubscriberAddress{between}, nil)
+
+	results, err := fx.service.PublishMultiLocation(ctx, merchantID, inputs)
+
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	for _, result := range results {
+		require.NoError(t, result.Err)
+		require.NotNil(t, result.Notification)
+	}
+
+	// The third location only reaches an already-notified subscriber, so nothing is published for it
+	require.Len(t, publisher.events, 2)
+	assert.Equal(t, []string{nearA.OwnerID.String(), between.OwnerID.String()}, publisher.events[0].SubscriberIDs)
+	assert.Equal(t, []string{nearB.OwnerID.String()}, publisher.events[1].SubscriberIDs)
+}
+
+func TestNotificationService_PublishMultiLocation_PartialFailure(t *testing.T) {
+	fx, publisher, inputs := newMultiLocationFixture(t)
+	foreignAddressID := uuid.New()
+	inputs[0] = usecase.PublishLocationInput{AddressID: &foreignAddressID}
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	nearB := newNearbySubscriber(25.011, 121.011)
+
+	fx.addressRepo.EXPECT().
+		FindAddressByID(ctx, foreignAddressID).
+		Return(&entity.Address{ID: foreignAddressID, OwnerID: uuid.New(), OwnerType: entity.OwnerTypeMerchantProfile}, nil)
+	fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil).Once()
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, 25.01, 121.01).
+		Return([]*entity.SubscriberAddress{nearB}, nil)
+
+	results, err := fx.service.PublishMultiLocation(ctx, merchantID, inputs)
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Nil(t, results[0].Notification)
+	assert.ErrorIs(t, results[0].Err, domainerrors.ErrAddressOwnershipViolation)
+	require.NoError(t, results[1].Err)
+	require.NotNil(t, results[1].Notification)
+
+	require.Len(t, publisher.events, 1)
+	assert.Equal(t, []string{nearB.OwnerID.String()}, publisher.events[0].SubscriberIDs)
+}
+
+func TestNotificationService_PublishMultiLocation_RejectsInvalidBatchSize(t *testing.T) {
+	fx := createTestNotificationService(t)
+	tooMany := make([]usecase.PublishLocationInput, maxMultiLocationPublish+1)
+
+	for _, inputs := range [][]usecase.PublishLocationInput{nil, tooMany} {
+		results, err := fx.service.PublishMultiLocation(context.Background(), uuid.New(), inputs)
+
+		assert.Nil(t, results)
+		assert.ErrorIs(t, err, domainerrors.ErrInvalidNotificationData)
+	}
+}
+
+func TestNotificationService_ScheduleLocationNotification_StoresPendingWithoutPublishing(t *testing.T) {
+	fx := createTestNotificationService(t)
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	scheduledAt := time.Now().Add(2 * time.Hour)
+	input := usecase.PublishLocationInput{
+		LocationData: &usecase.LocationData{LocationName: "Test Store", FullAddress: "123 Test St", Latitude: 25.0, Longitude: 121.0},
+		ScheduledAt:  &scheduledAt,
+	}
+
+	// No subscriber lookup or delivery expectations: a scheduled notification is only stored
+	fx.notificationRepo.EXPECT().
+		CreateNotification(ctx, mock.MatchedBy(func(n *entity.MerchantLocationNotification) bool {
+			return n.ScheduleStatus == entity.ScheduleStatusPending && n.ScheduledAt.Equal(scheduledAt) && n.PublishedAt.Equal(scheduledAt)
+		})).
+		Return(nil)
+
+	notification, err := fx.service.ScheduleLocationNotification(ctx, merchantID, input)
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.ScheduleStatusPending, notification.ScheduleStatus)
+}
+
+// namedRoadRoutingService names every coordinate with a fixed road
+type namedRoadRoutingService struct {
+	failingRoutingService
+	roadName string
+}
+
+func (s *namedRoadRoutingService) NearestRoadName(context.Context, usecase.Coordinate) (string, bool, error) {
+	return s.roadName, true, nil
+}
+
+func TestNotificationService_ScheduleLocationNotification_LabelsCoordinatesWithRoadName(t *testing.T) {
+	fx := createTestNotificationServiceWithRouting(t, &namedRoadRoutingService{roadName: "Songren Road"})
+
+	ctx := context.Background()
+	scheduledAt := time.Now().Add(2 * time.Hour)
+	input := usecase.PublishLocationInput{
+		LocationData: &usecase.LocationData{LocationName: "Test Store", Latitude: 25.0, Longitude: 121.0},
+		ScheduledAt:  &scheduledAt,
+	}
+
+	fx.notificationRepo.EXPECT().
+		CreateNotification(ctx, mock.MatchedBy(func(n *entity.MerchantLocationNotification) bool {
+			return n.FullAddress == "Songren Road"
+		})).
+		Return(nil)
+
+	notification, err := fx.service.ScheduleLocationNotification(ctx, uuid.New(), input)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Songren Road", notification.FullAddress)
+}
+
+func TestNotificationService_ScheduleLocationNotification_InvalidTimeRejected(t *testing.T) {
+	fx := createTestNotificationService(t)
+	locationData := &usecase.LocationData{LocationName: "Test Store", FullAddress: "123 Test St", Latitude: 25.0, Longitude: 121.0}
+	past := time.Now().Add(-time.Minute)
+	tooFar := time.Now().Add(maxScheduleLead + time.Hour)
+
+	for _, scheduledAt := range []*time.Time{nil, &past, &tooFar} {
+		input := usecase.PublishLocationInput{LocationData: locationData, ScheduledAt: scheduledAt}
+
+		_, err := fx.service.ScheduleLocationNotification(context.Background(), uuid.New(), input)
+
+		require.ErrorIs(t, err, domainerrors.ErrInvalidNotificationData)
+	}
+}
+
+func TestNotificationService_CancelScheduledNotification(t *testing.T) {
+	merchantID := uuid.New()
+	notificationID := uuid.New()
+
+	tests := []struct {
+		name         string
+		notification *entity.MerchantLocationNotification
+		findErr      error
+		attempted    bool // whether the conditional cancel update runs
+		canceled     bool
+		wantErr      error
+	}{
+		{
+			name:         "pending notification is canceled",
+			notification: &entity.MerchantLocationNotification{ID: notificationID, MerchantID: merchantID, ScheduleStatus: entity.ScheduleStatusPending},
+			attempted:    true,
+			canceled:     true,
+		},
+		{
+			name:    "missing notification",
+			findErr: domainerrors.ErrNotificationNotFound,
+			wantErr: domainerrors.ErrNotificationNotFound,
+		},
+		{
+			name:         "other merchant's notification",
+			notification: &entity.MerchantLocationNotification{ID: notificationID, MerchantID: uuid.New(), ScheduleStatus: entity.ScheduleStatusPending},
+			wantErr:      domainerrors.ErrNotificationOwnershipViolation,
+		},
+		{
+			name:         "notification sent immediately",
+			notification: &entity.MerchantLocationNotification{ID: notificationID, MerchantID: merchantID},
+			wantErr:      domainerrors.ErrNotificationNotCancelable,
+		},
+		{
+			name:         "dispatched while canceling",
+			notification: &entity.MerchantLocationNotification{ID: notificationID, MerchantID: merchantID, ScheduleStatus: entity.ScheduleStatusPending},
+			attempted:    true,
+			wantErr:      domainerrors.ErrNotificationNotCancelable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := createTestNotificationService(t)
+			ctx := context.Background()
+
+			fx.notificationRepo.EXPECT().FindNotificationByID(ctx, notificationID).Return(tt.notification, tt.findErr)
+			if tt.attempted {
+				fx.notificationRepo.EXPECT().
+					UpdateScheduleStatus(ctx, notificationID, entity.ScheduleStatusPending, entity.ScheduleStatusCanceled).
+					Return(tt.canceled, nil)
+			}
+
+			err := fx.service.CancelScheduledNotification(ctx, merchantID, notificationID)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNotificationService_PublishLocationNotification_MerchantSubscriberPolicy(t *testing.T) {
+	consumerID := uuid.New()
+	merchantSubscriberID := uuid.New()
+
+	tests := []struct {
+		name             string
+		excludeMerchants bool
+		wantRecipients   []uuid.UUID
+	}{
+		{name: "merchant subscribers receive broadcasts by default", wantRecipients: []uuid.UUID{consumerID, merchantSubscriberID}},
+		{name: "merchant subscribers excluded", excludeMerchants: true, wantRecipients: []uuid.UUID{consumerID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := createTestNotificationService(t)
+			svc, ok := fx.service.(*notificationService)
+			require.True(t, ok)
+			svc.excludeMerchantSubscribers = tt.excludeMerchants
+
+			ctx := context.Background()
+			merchantID := uuid.New()
+			locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0}
+
+			fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil)
+			fx.subscriptionRepo.EXPECT().
+				FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+				Return([]*entity.SubscriberAddress{
+					{Address: entity.Address{OwnerID: consumerID, Latitude: 25.001, Longitude: 121.001}, NotificationRadius: 1000.0},
+					{Address: entity.Address{OwnerID: merchantSubscriberID, Latitude: 25.001, Longitude: 121.002}, NotificationRadius: 1000.0, IsMerchant: true},
+				}, nil)
+
+			devices := make([]*entity.UserDevice, 0, len(tt.wantRecipients))
+			for _, userID := range tt.wantRecipients {
+				devices = append(devices, &entity.UserDevice{ID: uuid.New(), UserID: userID, FCMToken: "token-" + userID.String()})
+			}
+			fx.subscriptionRepo.EXPECT().
+				FindDevicesForUsers(ctx, mock.MatchedBy(func(ids []uuid.UUID) bool {
+					return assert.ElementsMatch(t, tt.wantRecipients, ids)
+				}), policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
+				Return(devices, nil)
+			fx.notificationSvc.EXPECT().
+				SendBatchNotification(ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				RunAndReturn(func(_ context.Context, tokens []string, _, _ string, _ map[string]string) ([]service.TokenResult, error) {
+					return sentTokenResults(tokens...), nil
+				})
+			fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
+			fx.notificationRepo.EXPECT().UpdateNotificationStatus(ctx, mock.Anything, len(devices), 0).Return(nil)
+
+			notification, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.wantRecipients), notification.TotalSent)
+		})
+	}
+}
+
+func TestNotificationService_PublishLocationNotification_CanaryCohort(t *testing.T) {
+	fx := createTestNotificationService(t)
+	metrics := mockSvc.NewMockNotificationMetrics(t)
+	svc, ok := fx.service.(*notificationService)
+	require.True(t, ok)
+	svc.metrics = metrics
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0}
+
+	// An empty fraction leaves only the allowlisted subscriber in the cohort
+	canaryID := uuid.New()
+	suppressedIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	svc.canaryPolicy = policy.CanaryPolicy{Enabled: true, AllowedUserIDs: []string{canaryID.String()}}
+
+	addresses := []*entity.SubscriberAddress{
+		{Address: entity.Address{OwnerID: canaryID, Latitude: 25.001, Longitude: 121.001}, NotificationRadius: 1000.0},
+	}
+	for _, userID := range suppressedIDs {
+		addresses = append(addresses,
+			&entity.SubscriberAddress{Address: entity.Address{OwnerID: userID, Latitude: 25.001, Longitude: 121.002}, NotificationRadius: 1000.0},
+		)
+	}
+	device := &entity.UserDevice{ID: uuid.New(), UserID: canaryID, FCMToken: "canary-token"}
+
+	fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+		Return(addresses, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindDevicesForUsers(ctx, []uuid.UUID{canaryID}, policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
+		Return([]*entity.UserDevice{device}, nil)
+	fx.notificationSvc.EXPECT().
+		SendBatchNotification(ctx, []string{"canary-token"}, mock.Anything, mock.Anything, mock.Anything).
+		Return(sentTokenResults("canary-token"), nil)
+	fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
+	fx.notificationRepo.EXPECT().UpdateNotificationStatus(ctx, mock.Anything, 1, 0).Return(nil)
+	metrics.EXPECT().NotificationCreated().Once()
+	metrics.EXPECT().CanarySuppressed(len(suppressedIDs)).Once()
+	metrics.EXPECT().ObserveRoutingLatency(mock.Anything).Maybe()
+	metrics.EXPECT().TokensSent(1).Once()
+	metrics.EXPECT().DeliveryFailed(0).Once()
+
+	notification, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, notification.TotalSent)
+}
+
+func TestNotificationService_PublishLocationNotification_NotificationPreferences(t *testing.T) {
+	// 23:30 in Taipei
+	now := time.Date(2026, 10, 14, 15, 30, 0, 0, time.UTC)
+	quietStart, quietEnd := 22*60, 7*60
+
+	tests := []struct {
+		name      string
+		timezone  string
+		wantSends bool
+	}{
+		{name: "inside quiet hours", timezone: "Asia/Taipei"},
+		{name: "outside quiet hours", timezone: "Europe/London", wantSends: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := createTestNotificationService(t)
+			svc, ok := fx.service.(*notificationService)
+			require.True(t, ok)
+			svc.clock = newFakeClock(now)
+			preferenceRepo := mockRepo.NewMockNotificationPreferenceRepository(t)
+			svc.preferenceRepo = preferenceRepo
+
+			ctx := context.Background()
+			merchantID := uuid.New()
+			userID := uuid.New()
+			locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0}
+
+			fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil)
+			fx.subscriptionRepo.EXPECT().
+				FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+				Return([]*entity.SubscriberAddress{
+					{Address: entity.Address{OwnerID: userID, Latitude: 25.001, Longitude: 121.001}, NotificationRadius: 1000.0},
+				}, nil)
+			preferenceRepo.EXPECT().
+				FindNotificationPreferences(ctx, []uuid.UUID{userID}).
+				Return([]*entity.NotificationPreference{
+					{UserID: userID, Timezone: tt.timezone, QuietHoursStart: &quietStart, QuietHoursEnd: &quietEnd},
+				}, nil)
+			preferenceRepo.EXPECT().FindMerchantCategoryID(ctx, merchantID).Return(nil, nil)
+			if tt.wantSends {
+				fx.subscriptionRepo.EXPECT().
+					FindDevicesForUsers(ctx, []uuid.UUID{userID}, policy.DefaultDevicePolicy().HealthyWindowDays, repository.DeviceTargetFilter{}).
+					Return([]*entity.UserDevice{{ID: uuid.New(), UserID: userID, FCMToken: "token"}}, nil)
+				fx.notificationSvc.EXPECT().
+					SendBatchNotification(ctx, []string{"token"}, mock.Anything, mock.Anything, mock.Anything).
+					Return(sentTokenResults("token"), nil)
+				fx.notificationRepo.EXPECT().BatchCreateNotificationLogs(ctx, mock.Anything).Return(nil)
+				fx.notificationRepo.EXPECT().UpdateNotificationStatus(ctx, mock.Anything, 1, 0).Return(nil)
+			}
+
+			notification, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+			require.NoError(t, err)
+			if tt.wantSends {
+				assert.Equal(t, 1, notification.TotalSent)
+			} else {
+				assert.Zero(t, notification.TotalSent)
+			}
+		})
+	}
+}
+
+func TestNotificationService_PublishLocationNotification_BroadcastCooldown(t *testing.T) {
+	now := time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC)
+	withinCooldown := now.Add(-10 * time.Minute)
+
+	tests := []struct {
+		name      string
+		cooldown  time.Duration
+		blockedBy *time.Time
+		wantErr   error
+	}{
+		{name: "within cooldown", cooldown: 15 * time.Minute, blockedBy: &withinCooldown, wantErr: domainerrors.ErrRateLimited},
+		{name: "outside cooldown", cooldown: 15 * time.Minute},
+		{name: "cooldown disabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fx := createTestNotificationService(t)
+			svc, ok := fx.service.(*notificationService)
+			require.True(t, ok)
+			svc.clock = newFakeClock(now)
+			svc.broadcastCooldown = tt.cooldown
+
+			ctx := context.Background()
+			merchantID := uuid.New()
+			locationData := &usecase.LocationData{LocationName: "Test Store", Latitude: 25.0, Longitude: 121.0}
+
+			// The cooldown check and the insert are one repository call, so concurrent publishes cannot both pass
+			if tt.cooldown > 0 {
+				fx.notificationRepo.EXPECT().
+					CreateNotificationOutsideCooldown(ctx, mock.Anything, tt.cooldown).
+					Return(tt.blockedBy, nil)
+			} else {
+				fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil)
+			}
+			fx.subscriptionRepo.EXPECT().
+				FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+				Return([]*entity.SubscriberAddress{}, nil)
+
+			notification, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+			if tt.wantErr != nil {
+				assert.Nil(t, notification)
+				assert.ErrorIs(t, err, tt.wantErr)
+				appErr, ok := errors.AsType[domainerrors.AppError](err)
+				require.True(t, ok)
+				assert.Equal(t, "retry after 5m0s", appErr.Details())
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, now, notification.PublishedAt)
+		})
+	}
+}
+
+func TestNotificationService_PublishLocationNotification_RecipientCapKeepsNearest(t *testing.T) {
+	routingSvc, addresses := newReachabilityFixture()
+	fx := createTestNotificationServiceWithRouting(t, routingSvc)
+	publisher := &recordingEventPublisher{}
+	metrics := mockSvc.NewMockNotificationMetrics(t)
+	svc, ok := fx.service.(*notificationService)
+	require.True(t, ok)
+	svc.eventPublisher = publisher
+	svc.metrics = metrics
+	svc.recipientCap = policy.RecipientCapPolicy{MaxRecipients: 1}
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0}
+
+	fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+		Return(addresses, nil)
+	metrics.EXPECT().NotificationCreated().Once()
+	metrics.EXPECT().ObserveRoutingLatency(mock.Anything).Once()
+	metrics.EXPECT().RecipientsCapped(1).Once()
+
+	_, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+	// The cap needs the reachable subscribers, so the publisher filters even though prefiltering is off
+	require.NoError(t, err)
+	require.Len(t, publisher.events, 1)
+	assert.True(t, publisher.events[0].ReachabilityFiltered)
+	assert.Equal(t, []string{addresses[0].OwnerID.String()}, publisher.events[0].SubscriberIDs)
+}
+
+func TestNotificationService_PublishLocationNotification_StrictRecipientCapRejects(t *testing.T) {
+	routingSvc, addresses := newReachabilityFixture()
+	fx := createTestNotificationServiceWithRouting(t, routingSvc)
+	publisher := &recordingEventPublisher{}
+	svc, ok := fx.service.(*notificationService)
+	require.True(t, ok)
+	svc.eventPublisher = publisher
+	svc.recipientCap = policy.RecipientCapPolicy{MaxRecipients: 1, Strict: true}
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0}
+
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+		Return(addresses, nil)
+
+	_, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+	// Rejected before the notification is recorded, so no CreateNotification call is expected
+	require.ErrorIs(t, err, domainerrors.ErrRecipientCapExceeded)
+	assert.Empty(t, publisher.events)
+}
+
+func TestNotificationService_PublishLocationNotification_StrictRecipientCapWithoutPrefilter(t *testing.T) {
+	fx := createTestNotificationServiceWithRouting(t, &failingRoutingService{err: errors.New("tiles unavailable")})
+	publisher := &recordingEventPublisher{}
+	svc, ok := fx.service.(*notificationService)
+	require.True(t, ok)
+	svc.eventPublisher = publisher
+	svc.recipientCap = policy.RecipientCapPolicy{MaxRecipients: 1, Strict: true}
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0}
+	addresses := []*entity.SubscriberAddress{
+		{Address: entity.Address{OwnerID: uuid.New(), Latitude: 25.001, Longitude: 121.0}, NotificationRadius: 1000},
+		{Address: entity.Address{OwnerID: uuid.New(), Latitude: 25.002, Longitude: 121.0}, NotificationRadius: 1000},
+	}
+
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+		Return(addresses, nil)
+
+	_, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+	// Routing is down, but the straight-line candidates already exceed the cap
+	require.ErrorIs(t, err, domainerrors.ErrRecipientCapExceeded)
+	assert.Empty(t, publisher.events)
+}
+
+func TestNotificationService_PublishLocationNotification_StrictRecipientCapLookupFailure(t *testing.T) {
+	fx := createTestNotificationService(t)
+	svc, ok := fx.service.(*notificationService)
+	require.True(t, ok)
+	svc.recipientCap = policy.RecipientCapPolicy{MaxRecipients: 1, Strict: true}
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0}
+
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+		Return(nil, domainerrors.ErrPersistenceFailed)
+
+	_, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+	// Without the subscribers the cap cannot be checked, so nothing is recorded or sent
+	require.ErrorIs(t, err, domainerrors.ErrPersistenceFailed)
+}
+
+func TestNotificationService_PublishLocationNotification_UnderRecipientCap(t *testing.T) {
+	routingSvc, addresses := newReachabilityFixture()
+	fx := createTestNotificationServiceWithRouting(t, routingSvc)
+	publisher := &recordingEventPublisher{}
+	svc, ok := fx.service.(*notificationService)
+	require.True(t, ok)
+	svc.eventPublisher = publisher
+	// Four candidates are within the straight-line radius, but only two are reachable by road
+	svc.recipientCap = policy.RecipientCapPolicy{MaxRecipients: 2, Strict: true}
+
+	ctx := context.Background()
+	merchantID := uuid.New()
+	locationData := &usecase.LocationData{Latitude: 25.0, Longitude: 121.0}
+
+	fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, locationData.Latitude, locationData.Longitude).
+		Return(addresses, nil)
+
+	_, err := fx.service.PublishLocationNotification(ctx, merchantID, nil, locationData, "")
+
+	require.NoError(t, err)
+	require.Len(t, publisher.events, 1)
+	assert.Equal(t, []string{addresses[0].OwnerID.String(), addresses[2].OwnerID.String()}, publisher.events[0].SubscriberIDs)
+}
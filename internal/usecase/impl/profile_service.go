@@ -364,6 +364,14 @@ func (srv *profileService) SubmitMerchantVerification(ctx context.Context, userI
 			return domainerrors.ErrConflict.WithDetails("merchant business license has already been verified")
 		}
 
+		taken, err := userRepo.IsBusinessLicenseTaken(ctx, businessLicense, userID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return domainerrors.ErrBusinessLicenseExists
+		}
+
 		now := time.Now()
 		user.MerchantProfile.BusinessLicense = businessLicense
 		user.MerchantProfile.VerificationStatus = entity.MerchantVerificationStatusVerified
@@ -212,6 +212,7 @@ func TestProfileService_SubmitMerchantVerification_Success(t *testing.T) {
 			mockUserRepo := mockRepo.NewMockUserRepository(t)
 			mockFactory.EXPECT().UserRepo().Return(mockUserRepo)
 			mockUserRepo.EXPECT().FindByID(ctx, userID).Return(existingUser, nil)
+			mockUserRepo.EXPECT().IsBusinessLicenseTaken(ctx, "BL-456", userID).Return(false, nil)
 			mockUserRepo.EXPECT().
 				Update(ctx, mock.AnythingOfType("*entity.User")).
 				Run(func(_ context.Context, user *entity.User) {
@@ -229,6 +230,75 @@ func TestProfileService_SubmitMerchantVerification_Success(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestProfileService_SubmitMerchantVerification_DuplicateLicenseRejectedByPreCheck(t *testing.T) {
+	fx := createTestProfileService(t)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	input := &usecase.SubmitMerchantVerificationInput{BusinessLicense: "BL-456"}
+	existingUser := &entity.User{
+		ID: userID,
+		MerchantProfile: &entity.MerchantProfile{
+			UserID:             userID,
+			StoreName:          "Store",
+			VerificationStatus: entity.MerchantVerificationStatusUnverified,
+		},
+	}
+
+	fx.txManager.EXPECT().
+		Execute(ctx, mock.AnythingOfType("func(repository.RepositoryFactory) error")).
+		RunAndReturn(func(ctx context.Context, fn func(repository.RepositoryFactory) error) error {
+			mockFactory := mockRepo.NewMockRepositoryFactory(t)
+			mockUserRepo := mockRepo.NewMockUserRepository(t)
+			mockFactory.EXPECT().UserRepo().Return(mockUserRepo)
+			mockUserRepo.EXPECT().FindByID(ctx, userID).Return(existingUser, nil)
+			mockUserRepo.EXPECT().IsBusinessLicenseTaken(ctx, "BL-456", userID).Return(true, nil)
+
+			return fn(mockFactory)
+		})
+
+	err := fx.service.SubmitMerchantVerification(ctx, userID, input)
+
+	require.ErrorIs(t, err, domainerrors.ErrBusinessLicenseExists)
+	assert.Equal(t, entity.MerchantVerificationStatusUnverified, existingUser.MerchantProfile.VerificationStatus)
+}
+
+func TestProfileService_SubmitMerchantVerification_DuplicateLicenseRejectedOnUniqueViolation(t *testing.T) {
+	fx := createTestProfileService(t)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	input := &usecase.SubmitMerchantVerificationInput{BusinessLicense: "BL-456"}
+	existingUser := &entity.User{
+		ID: userID,
+		MerchantProfile: &entity.MerchantProfile{
+			UserID:             userID,
+			StoreName:          "Store",
+			VerificationStatus: entity.MerchantVerificationStatusUnverified,
+		},
+	}
+
+	fx.txManager.EXPECT().
+		Execute(ctx, mock.AnythingOfType("func(repository.RepositoryFactory) error")).
+		RunAndReturn(func(ctx context.Context, fn func(repository.RepositoryFactory) error) error {
+			mockFactory := mockRepo.NewMockRepositoryFactory(t)
+			mockUserRepo := mockRepo.NewMockUserRepository(t)
+			mockFactory.EXPECT().UserRepo().Return(mockUserRepo)
+			mockUserRepo.EXPECT().FindByID(ctx, userID).Return(existingUser, nil)
+			// Another merchant claims the license between the pre-check and the write.
+			mockUserRepo.EXPECT().IsBusinessLicenseTaken(ctx, "BL-456", userID).Return(false, nil)
+			mockUserRepo.EXPECT().
+				Update(ctx, mock.AnythingOfType("*entity.User")).
+				Return(domainerrors.ErrBusinessLicenseExists)
+
+			return fn(mockFactory)
+		})
+
+	err := fx.service.SubmitMerchantVerification(ctx, userID, input)
+
+	require.ErrorIs(t, err, domainerrors.ErrBusinessLicenseExists)
+}
+
 func TestProfileService_SubmitMerchantVerification_VerifiedSameLicenseIsIdempotent(t *testing.T) {
 	fx := createTestProfileService(t)
 
@@ -0,0 +1,321 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"radar/config"
+	"radar/internal/domain/entity"
+	"radar/internal/domain/repository"
+	"radar/internal/platform/observability"
+	"radar/internal/usecase"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+// cachedRoute is a routing result remembered for the coordinate it was computed against
+type cachedRoute struct {
+	target usecase.Coordinate
+	result usecase.RouteResult
+}
+
+// merchantRoutes holds routes from one merchant's default location, keyed by subscriber address ID
+type merchantRoutes struct {
+	source usecase.Coordinate
+	routes map[uuid.UUID]cachedRoute
+}
+
+type routeCacheService struct {
+	logger           *slog.Logger
+	addressRepo      repository.AddressRepository
+	subscriptionRepo repository.SubscriptionRepository
+	routingSvc       usecase.RoutingUsecase
+	radiusPolicy     usecase.RadiusPolicy
+
+	mu        sync.RWMutex
+	merchants map[uuid.UUID]*merchantRoutes
+}
+
+// RouteCacheServiceParams holds dependencies for RouteCacheService, injected by Fx.
+type RouteCacheServiceParams struct {
+	fx.In
+
+	Lifecycle        fx.Lifecycle
+	Logger           *slog.Logger
+	AddressRepo      repository.AddressRepository
+	SubscriptionRepo repository.SubscriptionRepository
+	RoutingSvc       usecase.RoutingUsecase
+	Config           *config.Config
+}
+
+// NewRouteCacheService creates the route cache and, when an interval is configured, starts its background warmer
+func NewRouteCacheService(params RouteCacheServiceParams) usecase.RouteCacheUsecase {
+	svc := &routeCacheService{
+		logger:           params.Logger,
+		addressRepo:      params.AddressRepo,
+		subscriptionRepo: params.SubscriptionRepo,
+		routingSvc:       params.RoutingSvc,
+		radiusPolicy:     usecase.NewRadiusPolicy(params.Config),
+		merchants:        make(map[uuid.UUID]*merchantRoutes),
+	}
+
+	var interval time.Duration
+	if params.Config != nil && params.Config.Notification != nil {
+		interval = params.Config.Notification.RouteCacheWarmInterval
+	}
+	if interval <= 0 || params.Lifecycle == nil {
+		return svc
+	}
+
+	var stopWarmer chan struct{}
+	params.Lifecycle.Append(fx.Hook{
+		OnStart: func(startCtx context.Context) error {
+			stopWarmer = make(chan struct{})
+			go svc.runWarmer(context.WithoutCancel(startCtx), stopWarmer, interval)
+
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			if stopWarmer != nil {
+				close(stopWarmer)
+			}
+
+			return nil
+		},
+	})
+
+	return svc
+}
+
+// log returns a request-scoped logger if available, otherwise falls back to the service's logger.
+func (s *routeCacheService) log(ctx context.Context) *slog.Logger {
+	return observability.LoggerFromContextOrDefault(ctx, s.logger)
+}
+
+// runWarmer warms the cache immediately and then on every tick until stopped
+func (s *routeCacheService) runWarmer(ctx context.Context, stop <-chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := s.WarmAll(ctx)
+		if err != nil {
+			s.log(ctx).Warn("Failed to warm route cache", slog.String("error", err.Error()))
+		} else {
+			s.log(ctx).Debug("Route cache warmed",
+				slog.Int("merchants", result.Merchants),
+				slog.Int("routed", result.Routed),
+				slog.Int("cached", result.Cached),
+				slog.Int("failed", result.Failed),
+			)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// WarmAll refreshes the cached routes of every merchant with a primary location
+func (s *routeCacheService) WarmAll(ctx context.Context) (*usecase.RouteCacheWarmResult, error) {
+	locations, err := s.addressRepo.FindPrimaryAddressesByOwnerType(ctx, entity.OwnerTypeMerchantProfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merchant default locations: %w", err)
+	}
+
+	result := &usecase.RouteCacheWarmResult{}
+	warmed := make(map[uuid.UUID]struct{}, len(locations))
+	for _, location := range locations {
+		source := usecase.Coordinate{Lat: location.Latitude, Lng: location.Longitude}
+		routed, cached, warmErr := s.warmMerchant(ctx, location.OwnerID, source)
+		if warmErr != nil {
+			s.log(ctx).Warn("Failed to warm merchant routes",
+				slog.String("merchant_id", location.OwnerID.String()),
+				slog.String("error", warmErr.Error()),
+			)
+			result.Failed++
+
+			continue
+		}
+
+		warmed[location.OwnerID] = struct{}{}
+		result.Merchants++
+		result.Routed += routed
+		result.Cached += cached
+	}
+
+	s.pruneMerchants(warmed, len(locations) == result.Merchants)
+
+	return result, nil
+}
+
+// warmMerchant routes the merchant's current subscribers that are missing from the cache and drops departed ones
+func (s *routeCacheService) warmMerchant(
+	ctx context.Context,
+	merchantID uuid.UUID,
+	source usecase.Coordinate,
+) (routed, cached int, err error) {
+	addresses, err := s.subscriptionRepo.FindSubscriberAddressesWithinRadius(ctx, merchantID, source.Lat, source.Lng)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to find subscriber addresses: %w", err)
+	}
+
+	results, misses := s.lookup(merchantID, source, addresses)
+	if err := s.routeMisses(ctx, source, addresses, results, misses); err != nil {
+		return 0, 0, err
+	}
+
+	routes := make(map[uuid.UUID]cachedRoute, len(addresses))
+	for idx, addr := range addresses {
+		if cacheable(results[idx]) {
+			routes[addr.ID] = cachedRoute{target: addressCoordinate(addr), result: results[idx]}
+		}
+	}
+
+	s.mu.Lock()
+	s.merchants[merchantID] = &merchantRoutes{source: source, routes: routes}
+	s.mu.Unlock()
+
+	return len(misses), len(addresses) - len(misses), nil
+}
+
+// pruneMerchants forgets merchants that no longer have a default location.
+// It only runs after a pass with no failures so a transient error does not discard a warm entry.
+func (s *routeCacheService) pruneMerchants(warmed map[uuid.UUID]struct{}, complete bool) {
+	if !complete {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for merchantID := range s.merchants {
+		if _, ok := warmed[merchantID]; !ok {
+			delete(s.merchants, merchantID)
+		}
+	}
+}
+
+// FilterReachableAddresses applies the notification radius filter, routing only addresses the cache cannot answer
+func (s *routeCacheService) FilterReachableAddresses(
+	ctx context.Context,
+	merchantID uuid.UUID,
+	source usecase.Coordinate,
+	addresses []*entity.SubscriberAddress,
+) ([]*entity.SubscriberAddress, error) {
+	if len(addresses) == 0 {
+		return []*entity.SubscriberAddress{}, nil
+	}
+
+	results, misses := s.lookup(merchantID, source, addresses)
+	if err := s.routeMisses(ctx, source, addresses, results, misses); err != nil {
+		return nil, fmt.Errorf("filter reachable addresses: %w", err)
+	}
+
+	s.remember(merchantID, source, addresses, results, misses)
+
+	return usecase.AddressesWithinRadius(source, addresses, results, s.radiusPolicy), nil
+}
+
+// lookup returns index-aligned cached results and the indexes of addresses that still need routing.
+// A cached route only counts when it was computed from the same source to the address's current coordinate.
+func (s *routeCacheService) lookup(
+	merchantID uuid.UUID,
+	source usecase.Coordinate,
+	addresses []*entity.SubscriberAddress,
+) ([]usecase.RouteResult, []int) {
+	results := make([]usecase.RouteResult, len(addresses))
+	misses := make([]int, 0, len(addresses))
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry := s.merchants[merchantID]
+	for idx, addr := range addresses {
+		if entry != nil && entry.source == source {
+			if route, ok := entry.routes[addr.ID]; ok && route.target == addressCoordinate(addr) {
+				results[idx] = route.result
+				continue
+			}
+		}
+		misses = append(misses, idx)
+	}
+
+	return results, misses
+}
+
+// routeMisses fills the missing results with one batched routing query
+func (s *routeCacheService) routeMisses(
+	ctx context.Context,
+	source usecase.Coordinate,
+	addresses []*entity.SubscriberAddress,
+	results []usecase.RouteResult,
+	misses []int,
+) error {
+	if len(misses) == 0 {
+		return nil
+	}
+
+	targets := make([]usecase.Coordinate, len(misses))
+	for i, idx := range misses {
+		targets[i] = addressCoordinate(addresses[idx])
+	}
+
+	routeResults, err := s.routingSvc.OneToMany(ctx, s.radiusPolicy.Profile, source, targets)
+	if err != nil {
+		return fmt.Errorf("routing service failed: %w", err)
+	}
+
+	for i, result := range routeResults.Results {
+		if i < len(misses) {
+			results[misses[i]] = result
+		}
+	}
+
+	return nil
+}
+
+// remember stores freshly routed results when they were computed from the merchant's warmed default location
+func (s *routeCacheService) remember(
+	merchantID uuid.UUID,
+	source usecase.Coordinate,
+	addresses []*entity.SubscriberAddress,
+	results []usecase.RouteResult,
+	misses []int,
+) {
+	if len(misses) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.merchants[merchantID]
+	if entry == nil || entry.source != source {
+		return
+	}
+
+	for _, idx := range misses {
+		if !cacheable(results[idx]) {
+			continue
+		}
+		addr := addresses[idx]
+		entry.routes[addr.ID] = cachedRoute{target: addressCoordinate(addr), result: results[idx]}
+	}
+}
+
+// cacheable reports whether a route result may be served from the cache.
+// Straight-line estimates stand in for a failed or unavailable road route, so they are re-routed on the next lookup.
+func cacheable(result usecase.RouteResult) bool {
+	return !result.IsEstimate
+}
+
+func addressCoordinate(addr *entity.SubscriberAddress) usecase.Coordinate {
+	return usecase.Coordinate{Lat: addr.Latitude, Lng: addr.Longitude}
+}
@@ -0,0 +1,258 @@
+package impl
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"radar/internal/domain/entity"
+	"radar/internal/domain/repository"
+	mockRepo "radar/internal/mocks/repository"
+	"radar/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingRoutingService answers routes per target and records every batch it was asked to route
+type recordingRoutingService struct {
+	failingRoutingService
+	results map[usecase.Coordinate]usecase.RouteResult
+	batches [][]usecase.Coordinate
+}
+
+func (s *recordingRoutingService) OneToMany(_ context.Context, _ string, source usecase.Coordinate, targets []usecase.Coordinate) (*usecase.OneToManyResult, error) {
+	s.batches = append(s.batches, targets)
+
+	results := make([]usecase.RouteResult, len(targets))
+	for idx, target := range targets {
+		results[idx] = s.results[target]
+	}
+
+	return &usecase.OneToManyResult{Source: source, Targets: targets, Results: results}, nil
+}
+
+type routeCacheFixtures struct {
+	service          *routeCacheService
+	addressRepo      *mockRepo.MockAddressRepository
+	subscriptionRepo *mockRepo.MockSubscriptionRepository
+	routingSvc       *recordingRoutingService
+}
+
+func createTestRouteCacheService(t *testing.T) routeCacheFixtures {
+	addressRepo := mockRepo.NewMockAddressRepository(t)
+	subscriptionRepo := mockRepo.NewMockSubscriptionRepository(t)
+	routingSvc := &recordingRoutingService{results: make(map[usecase.Coordinate]usecase.RouteResult)}
+
+	svc := NewRouteCacheService(RouteCacheServiceParams{
+		Logger:           slog.New(slog.NewTextHandler(io.Discard, nil)),
+		AddressRepo:      addressRepo,
+		SubscriptionRepo: subscriptionRepo,
+		RoutingSvc:       routingSvc,
+	})
+
+	return routeCacheFixtures{
+		service:          svc.(*routeCacheService),
+		addressRepo:      addressRepo,
+		subscriptionRepo: subscriptionRepo,
+		routingSvc:       routingSvc,
+	}
+}
+
+func newSubscriberAddress(lat, lng, radius float64) *entity.SubscriberAddress {
+	return &entity.SubscriberAddress{
+		Address:            entity.Address{ID: uuid.New(), OwnerID: uuid.New(), Latitude: lat, Longitude: lng},
+		NotificationRadius: radius,
+	}
+}
+
+func TestRouteCacheService_WarmAll_InitialWarm(t *testing.T) {
+	fx := createTestRouteCacheService(t)
+	ctx := context.Background()
+	merchantID := uuid.New()
+	location := &entity.Address{OwnerID: merchantID, OwnerType: entity.OwnerTypeMerchantProfile, Latitude: 25.0, Longitude: 121.0}
+	near := newSubscriberAddress(25.001, 121.001, 1000)
+	far := newSubscriberAddress(25.010, 121.010, 1000)
+	fx.routingSvc.results[addressCoordinate(near)] = usecase.RouteResult{DistanceKm: 0.4, IsReachable: true}
+	fx.routingSvc.results[addressCoordinate(far)] = usecase.RouteResult{DistanceKm: 2.5, IsReachable: true}
+
+	fx.addressRepo.EXPECT().
+		FindPrimaryAddressesByOwnerType(ctx, entity.OwnerTypeMerchantProfile).
+		Return([]*entity.Address{location}, nil).
+		Twice()
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, 25.0, 121.0).
+		Return([]*entity.SubscriberAddress{near, far}, nil).
+		Twice()
+
+	first, err := fx.service.WarmAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &usecase.RouteCacheWarmResult{Merchants: 1, Routed: 2}, first)
+	require.Len(t, fx.routingSvc.batches, 1)
+	assert.Len(t, fx.routingSvc.batches[0], 2)
+
+	// An unchanged subscriber set is answered entirely from the cache
+	second, err := fx.service.WarmAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &usecase.RouteCacheWarmResult{Merchants: 1, Cached: 2}, second)
+	assert.Len(t, fx.routingSvc.batches, 1)
+}
+
+func TestRouteCacheService_WarmAll_RoutesOnlyChangedSubscribers(t *testing.T) {
+	fx := createTestRouteCacheService(t)
+	ctx := context.Background()
+	merchantID := uuid.New()
+	location := &entity.Address{OwnerID: merchantID, OwnerType: entity.OwnerTypeMerchantProfile, Latitude: 25.0, Longitude: 121.0}
+	stable := newSubscriberAddress(25.001, 121.001, 1000)
+	moving := newSubscriberAddress(25.010, 121.010, 1000)
+	departing := newSubscriberAddress(25.020, 121.020, 1000)
+
+	fx.addressRepo.EXPECT().
+		FindPrimaryAddressesByOwnerType(ctx, entity.OwnerTypeMerchantProfile).
+		Return([]*entity.Address{location}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, 25.0, 121.0).
+		Return([]*entity.SubscriberAddress{stable, moving, departing}, nil).
+		Once()
+
+	_, err := fx.service.WarmAll(ctx)
+	require.NoError(t, err)
+
+	moved := *moving
+	moved.Latitude, moved.Longitude = 25.003, 121.003
+	joining := newSubscriberAddress(25.004, 121.004, 1000)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, 25.0, 121.0).
+		Return([]*entity.SubscriberAddress{stable, &moved, joining}, nil).
+		Once()
+
+	result, err := fx.service.WarmAll(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, &usecase.RouteCacheWarmResult{Merchants: 1, Routed: 2, Cached: 1}, result)
+	require.Len(t, fx.routingSvc.batches, 2)
+	assert.Equal(t, []usecase.Coordinate{addressCoordinate(&moved), addressCoordinate(joining)}, fx.routingSvc.batches[1])
+
+	routes := fx.service.merchants[merchantID].routes
+	assert.Len(t, routes, 3)
+	assert.NotContains(t, routes, departing.ID)
+	assert.Equal(t, addressCoordinate(&moved), routes[moving.ID].target)
+}
+
+func TestRouteCacheService_FilterReachableAddresses_IgnoresCacheForOtherSource(t *testing.T) {
+	fx := createTestRouteCacheService(t)
+	ctx := context.Background()
+	merchantID := uuid.New()
+	location := &entity.Address{OwnerID: merchantID, OwnerType: entity.OwnerTypeMerchantProfile, Latitude: 25.0, Longitude: 121.0}
+	subscriber := newSubscriberAddress(25.001, 121.001, 1000)
+	fx.routingSvc.results[addressCoordinate(subscriber)] = usecase.RouteResult{DistanceKm: 0.4, IsReachable: true}
+
+	fx.addressRepo.EXPECT().
+		FindPrimaryAddressesByOwnerType(ctx, entity.OwnerTypeMerchantProfile).
+		Return([]*entity.Address{location}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, 25.0, 121.0).
+		Return([]*entity.SubscriberAddress{subscriber}, nil)
+
+	_, err := fx.service.WarmAll(ctx)
+	require.NoError(t, err)
+
+	reachable, err := fx.service.FilterReachableAddresses(ctx, merchantID, usecase.Coordinate{Lat: 25.002, Lng: 121.002}, []*entity.SubscriberAddress{subscriber})
+
+	require.NoError(t, err)
+	assert.Equal(t, []*entity.SubscriberAddress{subscriber}, reachable)
+	assert.Len(t, fx.routingSvc.batches, 2)
+}
+
+func TestNotificationService_PublishLocationNotification_UsesWarmedRouteCache(t *testing.T) {
+	cache := createTestRouteCacheService(t)
+	ctx := context.Background()
+	merchantID := uuid.New()
+	location := &entity.Address{
+		ID:        uuid.New(),
+		OwnerID:   merchantID,
+		OwnerType: entity.OwnerTypeMerchantProfile,
+		Label:     "Main Stall",
+		Latitude:  25.0,
+		Longitude: 121.0,
+	}
+	warmed := newSubscriberAddress(25.001, 121.001, 1000)
+	unreachable := newSubscriberAddress(25.010, 121.010, 1000)
+	joining := newSubscriberAddress(25.004, 121.004, 1000)
+	cache.routingSvc.results[addressCoordinate(warmed)] = usecase.RouteResult{DistanceKm: 0.4, IsReachable: true}
+	cache.routingSvc.results[addressCoordinate(joining)] = usecase.RouteResult{DistanceKm: 0.6, IsReachable: true}
+
+	cache.addressRepo.EXPECT().
+		FindPrimaryAddressesByOwnerType(ctx, entity.OwnerTypeMerchantProfile).
+		Return([]*entity.Address{location}, nil)
+	cache.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, 25.0, 121.0).
+		Return([]*entity.SubscriberAddress{warmed, unreachable}, nil).
+		Once()
+
+	_, err := cache.service.WarmAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, cache.routingSvc.batches, 1)
+
+	fx := createTestNotificationServiceWithRouting(t, &failingRoutingService{})
+	svc, ok := fx.service.(*notificationService)
+	require.True(t, ok)
+	svc.routeCache = cache.service
+
+	fx.addressRepo.EXPECT().FindAddressByID(ctx, location.ID).Return(location, nil)
+	fx.notificationRepo.EXPECT().CreateNotification(ctx, mock.Anything).Return(nil)
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, 25.0, 121.0).
+		Return([]*entity.SubscriberAddress{warmed, unreachable, joining}, nil)
+	fx.subscriptionRepo.EXPECT().
+		FindDevicesForUsers(ctx, []uuid.UUID{warmed.OwnerID, joining.OwnerID}, mock.Anything, repository.DeviceTargetFilter{}).
+		Return([]*entity.UserDevice{}, nil)
+
+	notification, err := fx.service.PublishLocationNotification(ctx, merchantID, &location.ID, nil, "")
+
+	require.NoError(t, err)
+	require.NotNil(t, notification)
+	require.Len(t, cache.routingSvc.batches, 2)
+	assert.Equal(t, []usecase.Coordinate{addressCoordinate(joining)}, cache.routingSvc.batches[1])
+	assert.Contains(t, cache.service.merchants[merchantID].routes, joining.ID)
+}
+
+func TestRouteCacheService_DoesNotCacheEstimates(t *testing.T) {
+	fx := createTestRouteCacheService(t)
+	ctx := context.Background()
+	merchantID := uuid.New()
+	location := &entity.Address{OwnerID: merchantID, OwnerType: entity.OwnerTypeMerchantProfile, Latitude: 25.0, Longitude: 121.0}
+	routed := newSubscriberAddress(25.001, 121.001, 1000)
+	estimated := newSubscriberAddress(25.002, 121.002, 1000)
+	fx.routingSvc.results[addressCoordinate(routed)] = usecase.RouteResult{DistanceKm: 0.4, IsReachable: true}
+	fx.routingSvc.results[addressCoordinate(estimated)] = usecase.RouteResult{DistanceKm: 0.3, IsReachable: true, IsEstimate: true}
+
+	fx.addressRepo.EXPECT().
+		FindPrimaryAddressesByOwnerType(ctx, entity.OwnerTypeMerchantProfile).
+		Return([]*entity.Address{location}, nil).
+		Twice()
+	fx.subscriptionRepo.EXPECT().
+		FindSubscriberAddressesWithinRadius(ctx, merchantID, 25.0, 121.0).
+		Return([]*entity.SubscriberAddress{routed, estimated}, nil).
+		Twice()
+
+	_, err := fx.service.WarmAll(ctx)
+	require.NoError(t, err)
+	assert.NotContains(t, fx.service.merchants[merchantID].routes, estimated.ID)
+
+	second, err := fx.service.WarmAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &usecase.RouteCacheWarmResult{Merchants: 1, Routed: 1, Cached: 1}, second)
+	require.Len(t, fx.routingSvc.batches, 2)
+	assert.Equal(t, []usecase.Coordinate{addressCoordinate(estimated)}, fx.routingSvc.batches[1])
+
+	// A live lookup re-routes the estimate too
+	_, err = fx.service.FilterReachableAddresses(ctx, merchantID, usecase.Coordinate{Lat: 25.0, Lng: 121.0}, []*entity.SubscriberAddress{routed, estimated})
+	require.NoError(t, err)
+	require.Len(t, fx.routingSvc.batches, 3)
+	assert.Equal(t, []usecase.Coordinate{addressCoordinate(estimated)}, fx.routingSvc.batches[2])
+	assert.NotContains(t, fx.service.merchants[merchantID].routes, estimated.ID)
+}
@@ -11,6 +11,7 @@ import (
 	domainerrors "radar/internal/domain/errors"
 	"radar/internal/domain/policy"
 	"radar/internal/domain/repository"
+	"radar/internal/domain/service"
 	"radar/internal/platform/observability"
 	"radar/internal/usecase"
 
@@ -22,6 +23,7 @@ type sessionService struct {
 	txManager          repository.TransactionManager
 	logger             *slog.Logger
 	refreshTokenPolicy policy.RefreshTokenPolicy
+	clock              service.Clock
 }
 
 // NewSessionService is the constructor for sessionService.
@@ -33,6 +35,7 @@ func NewSessionService(
 		txManager:          txManager,
 		logger:             logger,
 		refreshTokenPolicy: policy.DefaultRefreshTokenPolicy(),
+		clock:              service.ClockFunc(time.Now),
 	}
 }
 
@@ -68,7 +71,7 @@ func (srv *sessionService) GetActiveSessions(ctx context.Context, userID uuid.UU
 		}
 
 		// 3. Convert to session info
-		now := time.Now()
+		now := srv.clock.Now()
 		for _, token := range tokens {
 			sessions = append(sessions, &entity.SessionInfo{
 				ID:        token.ID,
@@ -76,7 +79,7 @@ func (srv *sessionService) GetActiveSessions(ctx context.Context, userID uuid.UU
 				CreatedAt: token.CreatedAt,
 				ExpiresAt: token.ExpiresAt,
 				IsActive:  token.ExpiresAt.After(now),
-				LastUsed:  &token.CreatedAt, // In a real implementation, you'd track last usage
+				LastUsed:  token.LastUsedAt,
 			})
 		}
 
@@ -262,14 +265,14 @@ func (srv *sessionService) GetSessionInfo(ctx context.Context, userID, sessionID
 		}
 
 		// 4. Create session info
-		now := time.Now()
+		now := srv.clock.Now()
 		sessionInfo = &entity.SessionInfo{
 			ID:        token.ID,
 			UserID:    token.UserID,
 			CreatedAt: token.CreatedAt,
 			ExpiresAt: token.ExpiresAt,
 			IsActive:  token.ExpiresAt.After(now),
-			LastUsed:  &token.CreatedAt,
+			LastUsed:  token.LastUsedAt,
 		}
 
 		return nil
@@ -295,13 +298,11 @@ func (srv *sessionService) CleanupExpiredSessions(ctx context.Context) (int, err
 		refreshRepo := repoFactory.RefreshTokenRepo()
 
 		// Delete expired sessions
-		if err := refreshRepo.DeleteExpiredRefreshTokens(ctx, srv.refreshTokenPolicy.RevokedRetentionDays); err != nil {
+		deleted, err := refreshRepo.DeleteExpiredRefreshTokens(ctx, srv.clock.Now(), srv.refreshTokenPolicy.RevokedRetentionDays)
+		if err != nil {
 			return fmt.Errorf("failed to delete expired sessions: %w", err)
 		}
-
-		// Note: In a real implementation, you might want to return the count
-		// from the repository method
-		deletedCount = 0 // Placeholder
+		deletedCount = int(deleted)
 
 		return nil
 	})
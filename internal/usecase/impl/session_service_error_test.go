@@ -13,6 +13,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestSessionService_CleanupExpiredSessions_Error(t *testing.T) {
@@ -24,12 +25,14 @@ func TestSessionService_CleanupExpiredSessions_Error(t *testing.T) {
 	fx.onExecute(ctx, fmt.Errorf("failed to delete expired sessions: %w", dbError), func(factory *mockRepo.MockRepositoryFactory) {
 		mockRefreshRepo := mockRepo.NewMockRefreshTokenRepository(t)
 		factory.EXPECT().RefreshTokenRepo().Return(mockRefreshRepo)
-		mockRefreshRepo.EXPECT().DeleteExpiredRefreshTokens(ctx, policy.DefaultRefreshTokenPolicy().RevokedRetentionDays).Return(dbError)
+		mockRefreshRepo.EXPECT().DeleteExpiredRefreshTokens(ctx, mock.Anything, policy.DefaultRefreshTokenPolicy().RevokedRetentionDays).Return(int64(0), dbError)
 	})
 
-	_, err := fx.service.CleanupExpiredSessions(ctx)
+	count, err := fx.service.CleanupExpiredSessions(ctx)
 
 	assert.Error(t, err)
+	assert.ErrorIs(t, err, dbError)
+	assert.Zero(t, count)
 	assert.Contains(t, err.Error(), "failed to cleanup expired sessions")
 }
 
@@ -55,7 +55,9 @@ func TestSessionService_GetActiveSessions_Success(t *testing.T) {
 	ctx := context.Background()
 	userID := uuid.New()
 	user := &entity.User{ID: userID}
+	lastUsedAt := time.Now().Add(-10 * time.Minute)
 	tokens := []*entity.RefreshToken{
+		{ID: uuid.New(), UserID: userID, CreatedAt: time.Now().Add(-time.Hour), ExpiresAt: time.Now().Add(time.Hour), LastUsedAt: &lastUsedAt},
 		{ID: uuid.New(), UserID: userID, CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)},
 	}
 
@@ -73,8 +75,49 @@ func TestSessionService_GetActiveSessions_Success(t *testing.T) {
 	sessions, err := fx.service.GetActiveSessions(ctx, userID)
 
 	require.NoError(t, err)
-	assert.Len(t, sessions, 1)
+	require.Len(t, sessions, 2)
 	assert.Equal(t, tokens[0].ID, sessions[0].ID)
+	require.NotNil(t, sessions[0].LastUsed)
+	assert.Equal(t, lastUsedAt, *sessions[0].LastUsed)
+	assert.Nil(t, sessions[1].LastUsed, "a session never refreshed has no last use")
+}
+
+func TestSessionService_GetActiveSessions_InactiveAfterThirtyDayExpiry(t *testing.T) {
+	fx := createTestSessionService(t)
+	createdAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	clock := newFakeClock(createdAt)
+	fx.service.clock = clock
+
+	ctx := context.Background()
+	userID := uuid.New()
+	tokens := []*entity.RefreshToken{
+		{ID: uuid.New(), UserID: userID, CreatedAt: createdAt, ExpiresAt: createdAt.AddDate(0, 0, 30)},
+	}
+
+	for range 2 {
+		fx.onExecute(ctx, nil, func(factory *mockRepo.MockRepositoryFactory) {
+			mockUserRepo := mockRepo.NewMockUserRepository(t)
+			mockRefreshRepo := mockRepo.NewMockRefreshTokenRepository(t)
+
+			factory.EXPECT().UserRepo().Return(mockUserRepo)
+			factory.EXPECT().RefreshTokenRepo().Return(mockRefreshRepo)
+
+			mockUserRepo.EXPECT().FindByID(ctx, userID).Return(&entity.User{ID: userID}, nil)
+			mockRefreshRepo.EXPECT().FindRefreshTokensByUserID(ctx, userID).Return(tokens, nil)
+		})
+	}
+
+	clock.Advance(29 * 24 * time.Hour)
+	sessions, err := fx.service.GetActiveSessions(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.True(t, sessions[0].IsActive)
+
+	clock.Advance(2 * 24 * time.Hour)
+	sessions, err = fx.service.GetActiveSessions(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.False(t, sessions[0].IsActive)
 }
 
 func TestSessionService_RevokeSession_Success(t *testing.T) {
@@ -196,15 +239,18 @@ func TestSessionService_CleanupExpiredSessions_Success(t *testing.T) {
 	fx := createTestSessionService(t)
 
 	ctx := context.Background()
+	now := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	fx.service.clock = newFakeClock(now)
 
+	// Expiry is judged at the service's clock
 	fx.onExecute(ctx, nil, func(factory *mockRepo.MockRepositoryFactory) {
 		mockRefreshRepo := mockRepo.NewMockRefreshTokenRepository(t)
 		factory.EXPECT().RefreshTokenRepo().Return(mockRefreshRepo)
-		mockRefreshRepo.EXPECT().DeleteExpiredRefreshTokens(ctx, policy.DefaultRefreshTokenPolicy().RevokedRetentionDays).Return(nil)
+		mockRefreshRepo.EXPECT().DeleteExpiredRefreshTokens(ctx, now, policy.DefaultRefreshTokenPolicy().RevokedRetentionDays).Return(int64(3), nil)
 	})
 
 	count, err := fx.service.CleanupExpiredSessions(ctx)
 
 	require.NoError(t, err)
-	assert.Equal(t, 0, count)
+	assert.Equal(t, 3, count)
 }
@@ -0,0 +1,175 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+
+	"radar/config"
+	"radar/internal/domain/entity"
+	"radar/internal/domain/repository"
+	"radar/internal/usecase"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+type subscriberMatrixService struct {
+	addressRepo      repository.AddressRepository
+	subscriptionRepo repository.SubscriptionRepository
+	routingSvc       usecase.RoutingUsecase
+	radiusPolicy     usecase.RadiusPolicy
+	tileZoom         int
+}
+
+// SubscriberMatrixServiceParams holds dependencies for SubscriberMatrixService, injected by Fx.
+type SubscriberMatrixServiceParams struct {
+	fx.In
+
+	AddressRepo      repository.AddressRepository
+	SubscriptionRepo repository.SubscriptionRepository
+	RoutingSvc       usecase.RoutingUsecase
+	Config           *config.Config
+}
+
+// NewSubscriberMatrixService creates a service that routes many merchant locations to their subscribers
+func NewSubscriberMatrixService(params SubscriberMatrixServiceParams) usecase.SubscriberMatrixUsecase {
+	svc := &subscriberMatrixService{
+		addressRepo:      params.AddressRepo,
+		subscriptionRepo: params.SubscriptionRepo,
+		routingSvc:       params.RoutingSvc,
+		radiusPolicy:     usecase.NewRadiusPolicy(params.Config),
+	}
+	if params.Config != nil && params.Config.Notification != nil {
+		svc.tileZoom = params.Config.Notification.SubscriberTileZoom
+	}
+
+	return svc
+}
+
+// RouteSubscribers looks up every source's subscribers grouped by tile and routes each tile with one query
+func (s *subscriberMatrixService) RouteSubscribers(
+	ctx context.Context,
+	sources []usecase.SubscriberMatrixSource,
+) ([][]usecase.SubscriberRoute, error) {
+	routes := make([][]usecase.SubscriberRoute, len(sources))
+	if len(sources) == 0 {
+		return routes, nil
+	}
+
+	repoSources := make([]repository.SubscriberSource, len(sources))
+	for idx, source := range sources {
+		repoSources[idx] = repository.SubscriberSource{
+			MerchantID: source.MerchantID,
+			Latitude:   source.Location.Lat,
+			Longitude:  source.Location.Lng,
+		}
+	}
+
+	buckets, err := s.subscriptionRepo.FindSubscriberAddressesByTile(ctx, repoSources, s.tileZoom)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find subscriber addresses: %w", err)
+	}
+
+	for _, bucket := range buckets {
+		if err := s.routeBucket(ctx, sources, bucket, routes); err != nil {
+			return nil, err
+		}
+	}
+
+	return routes, nil
+}
+
+// GetMerchantLocationReach counts the reachable subscribers of each active merchant location
+func (s *subscriberMatrixService) GetMerchantLocationReach(
+	ctx context.Context,
+	merchantID uuid.UUID,
+) ([]*usecase.LocationReach, error) {
+	addresses, err := s.addressRepo.FindActiveAddressesByOwner(ctx, merchantID, entity.OwnerTypeMerchantProfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merchant locations: %w", err)
+	}
+
+	sources := make([]usecase.SubscriberMatrixSource, len(addresses))
+	for idx, addr := range addresses {
+		sources[idx] = usecase.SubscriberMatrixSource{
+			MerchantID: merchantID,
+			Location:   usecase.Coordinate{Lat: addr.Latitude, Lng: addr.Longitude},
+		}
+	}
+
+	routes, err := s.RouteSubscribers(ctx, sources)
+	if err != nil {
+		return nil, err
+	}
+
+	reach := make([]*usecase.LocationReach, len(addresses))
+	for idx, addr := range addresses {
+		reachable := 0
+		for _, route := range routes[idx] {
+			if usecase.IsWithinSubscriberArea(sources[idx].Location, route.Address, route.Route, s.radiusPolicy) {
+				reachable++
+			}
+		}
+		reach[idx] = &usecase.LocationReach{
+			AddressID:  addr.ID,
+			Label:      addr.Label,
+			Candidates: len(routes[idx]),
+			Reachable:  reachable,
+		}
+	}
+
+	return reach, nil
+}
+
+// routeBucket routes every source with candidates in the bucket to the bucket's addresses in one ManyToMany query
+// and appends each candidate's route to its source's routes.
+func (s *subscriberMatrixService) routeBucket(
+	ctx context.Context,
+	sources []usecase.SubscriberMatrixSource,
+	bucket *entity.SubscriberTileBucket,
+	routes [][]usecase.SubscriberRoute,
+) error {
+	sourcePositions := make(map[int]int)
+	targetPositions := make(map[uuid.UUID]int)
+	var bucketSources, targets []usecase.Coordinate
+
+	candidates := make([]entity.SubscriberCandidate, 0, len(bucket.Candidates))
+	for _, candidate := range bucket.Candidates {
+		if candidate.Address == nil || candidate.SourceIndex < 0 || candidate.SourceIndex >= len(sources) {
+			continue
+		}
+		candidates = append(candidates, candidate)
+
+		if _, ok := sourcePositions[candidate.SourceIndex]; !ok {
+			sourcePositions[candidate.SourceIndex] = len(bucketSources)
+			bucketSources = append(bucketSources, sources[candidate.SourceIndex].Location)
+		}
+		if _, ok := targetPositions[candidate.Address.ID]; !ok {
+			targetPositions[candidate.Address.ID] = len(targets)
+			targets = append(targets, addressCoordinate(candidate.Address))
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	results, err := s.routingSvc.ManyToMany(ctx, s.radiusPolicy.Profile, bucketSources, targets)
+	if err != nil {
+		return fmt.Errorf("routing service failed: %w", err)
+	}
+
+	for _, candidate := range candidates {
+		sourcePos := sourcePositions[candidate.SourceIndex]
+		targetPos := targetPositions[candidate.Address.ID]
+		if sourcePos >= len(results) || results[sourcePos] == nil || targetPos >= len(results[sourcePos].Results) {
+			continue
+		}
+
+		routes[candidate.SourceIndex] = append(routes[candidate.SourceIndex], usecase.SubscriberRoute{
+			Address: candidate.Address,
+			Route:   results[sourcePos].Results[targetPos],
+		})
+	}
+
+	return nil
+}
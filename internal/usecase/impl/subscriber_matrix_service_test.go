@@ -0,0 +1,199 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"radar/config"
+	"radar/internal/domain/entity"
+	"radar/internal/domain/repository"
+	mockRepo "radar/internal/mocks/repository"
+	"radar/internal/usecase"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// tileLoadingRoutingService models the PMTiles backend without a tile cache: every query builds its graph by loading
+// each tile its points fall in, and the loads are counted per tile. Single-source queries load tiles too, so a bucket
+// routed one source at a time, or split over several queries, shows up as a tile loaded more than once.
+type tileLoadingRoutingService struct {
+	failingRoutingService
+	zoom      int
+	loads     map[entity.MapTile]int
+	distances map[usecase.Coordinate]float64
+}
+
+func newTileLoadingRoutingService(zoom int) *tileLoadingRoutingService {
+	return &tileLoadingRoutingService{
+		zoom:      zoom,
+		loads:     make(map[entity.MapTile]int),
+		distances: make(map[usecase.Coordinate]float64),
+	}
+}
+
+// loadGraph counts one load of every distinct tile covering the points
+func (s *tileLoadingRoutingService) loadGraph(points ...usecase.Coordinate) {
+	tiles := make(map[entity.MapTile]struct{}, len(points))
+	for _, point := range points {
+		tiles[entity.TileAt(point.Lat, point.Lng, s.zoom)] = struct{}{}
+	}
+	for tile := range tiles {
+		s.loads[tile]++
+	}
+}
+
+func (s *tileLoadingRoutingService) route(source usecase.Coordinate, targets []usecase.Coordinate) *usecase.OneToManyResult {
+	routes := make([]usecase.RouteResult, len(targets))
+	for idx, target := range targets {
+		routes[idx] = usecase.RouteResult{Source: source, Target: target, DistanceKm: s.distances[target], IsReachable: true}
+	}
+
+	return &usecase.OneToManyResult{Source: source, Targets: targets, Results: routes}
+}
+
+func (s *tileLoadingRoutingService) OneToMany(_ context.Context, _ string, source usecase.Coordinate, targets []usecase.Coordinate) (*usecase.OneToManyResult, error) {
+	s.loadGraph(append([]usecase.Coordinate{source}, targets...)...)
+
+	return s.route(source, targets), nil
+}
+
+func (s *tileLoadingRoutingService) ManyToMany(_ context.Context, _ string, sources, targets []usecase.Coordinate) ([]*usecase.OneToManyResult, error) {
+	s.loadGraph(append(slices.Clone(sources), targets...)...)
+
+	results := make([]*usecase.OneToManyResult, len(sources))
+	for idx, source := range sources {
+		results[idx] = s.route(source, targets)
+	}
+
+	return results, nil
+}
+
+func TestSubscriberMatrixService_RouteSubscribers_LoadsEachTileGraphOnce(t *testing.T) {
+	subscriptionRepo := mockRepo.NewMockSubscriptionRepository(t)
+	routingSvc := newTileLoadingRoutingService(14)
+	svc := NewSubscriberMatrixService(SubscriberMatrixServiceParams{
+		SubscriptionRepo: subscriptionRepo,
+		RoutingSvc:       routingSvc,
+		Config:           &config.Config{Notification: &config.NotificationConfig{SubscriberTileZoom: 14}},
+	})
+	ctx := context.Background()
+
+	sources := []usecase.SubscriberMatrixSource{
+		{MerchantID: uuid.New(), Location: usecase.Coordinate{Lat: 25.0330, Lng: 121.5654}},
+		{MerchantID: uuid.New(), Location: usecase.Coordinate{Lat: 25.0340, Lng: 121.5640}},
+		{MerchantID: uuid.New(), Location: usecase.Coordinate{Lat: 23.5711, Lng: 119.5793}},
+	}
+	shared := newSubscriberAddress(25.0335, 121.5660, 1000)
+	neighbor := newSubscriberAddress(25.0332, 121.5651, 1000)
+	island := newSubscriberAddress(23.5715, 119.5790, 1000)
+	xinyi := entity.MapTile{Zoom: 14, X: 13724, Y: 7014}
+	penghu := entity.MapTile{Zoom: 14, X: 13634, Y: 7087}
+
+	subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByTile(ctx, mock.MatchedBy(func(got []repository.SubscriberSource) bool {
+			return len(got) == 3 && got[1].MerchantID == sources[1].MerchantID && got[1].Latitude == 25.0340
+		}), 14).
+		Return([]*entity.SubscriberTileBucket{
+			{
+				Tile: xinyi,
+				Candidates: []entity.SubscriberCandidate{
+					{SourceIndex: 0, Address: shared},
+					{SourceIndex: 1, Address: shared},
+					{SourceIndex: 1, Address: neighbor},
+				},
+			},
+			{
+				Tile:       penghu,
+				Candidates: []entity.SubscriberCandidate{{SourceIndex: 2, Address: island}},
+			},
+		}, nil).Once()
+
+	routes, err := svc.RouteSubscribers(ctx, sources)
+	require.NoError(t, err)
+
+	// Both Xinyi sources are routed on one graph, so their shared tile loads once; one query per source would load it twice
+	assert.Equal(t, map[entity.MapTile]int{xinyi: 1, penghu: 1}, routingSvc.loads)
+
+	require.Len(t, routes, 3)
+	require.Len(t, routes[0], 1)
+	assert.Equal(t, shared, routes[0][0].Address)
+	assert.Equal(t, sources[0].Location, routes[0][0].Route.Source)
+
+	require.Len(t, routes[1], 2)
+	assert.Equal(t, addressCoordinate(shared), routes[1][0].Route.Target)
+	assert.Equal(t, sources[1].Location, routes[1][1].Route.Source)
+	assert.Equal(t, addressCoordinate(neighbor), routes[1][1].Route.Target)
+
+	require.Len(t, routes[2], 1)
+	assert.Equal(t, island, routes[2][0].Address)
+}
+
+func TestSubscriberMatrixService_GetMerchantLocationReach(t *testing.T) {
+	addressRepo := mockRepo.NewMockAddressRepository(t)
+	subscriptionRepo := mockRepo.NewMockSubscriptionRepository(t)
+	routingSvc := newTileLoadingRoutingService(14)
+	svc := NewSubscriberMatrixService(SubscriberMatrixServiceParams{
+		AddressRepo:      addressRepo,
+		SubscriptionRepo: subscriptionRepo,
+		RoutingSvc:       routingSvc,
+		Config:           &config.Config{Notification: &config.NotificationConfig{SubscriberTileZoom: 14}},
+	})
+	ctx := context.Background()
+	merchantID := uuid.New()
+
+	market := &entity.Address{ID: uuid.New(), Label: "Market", Latitude: 25.0330, Longitude: 121.5654}
+	station := &entity.Address{ID: uuid.New(), Label: "Station", Latitude: 25.0340, Longitude: 121.5640}
+	near := newSubscriberAddress(25.0335, 121.5660, 1000)
+	detour := newSubscriberAddress(25.0332, 121.5651, 1000)
+	routingSvc.distances[addressCoordinate(near)] = 0.4
+	routingSvc.distances[addressCoordinate(detour)] = 1.6
+
+	addressRepo.EXPECT().
+		FindActiveAddressesByOwner(ctx, merchantID, entity.OwnerTypeMerchantProfile).
+		Return([]*entity.Address{market, station}, nil)
+	subscriptionRepo.EXPECT().
+		FindSubscriberAddressesByTile(ctx, mock.MatchedBy(func(got []repository.SubscriberSource) bool {
+			return len(got) == 2 && got[0].MerchantID == merchantID && got[1].Latitude == station.Latitude
+		}), 14).
+		Return([]*entity.SubscriberTileBucket{{
+			Tile: entity.MapTile{Zoom: 14, X: 13724, Y: 7014},
+			Candidates: []entity.SubscriberCandidate{
+				{SourceIndex: 0, Address: near},
+				{SourceIndex: 0, Address: detour},
+				{SourceIndex: 1, Address: detour},
+			},
+		}}, nil)
+
+	reach, err := svc.GetMerchantLocationReach(ctx, merchantID)
+
+	require.NoError(t, err)
+	// The detour is within the straight-line radius of both locations but its road route is too long
+	assert.Equal(t, []*usecase.LocationReach{
+		{AddressID: market.ID, Label: "Market", Candidates: 2, Reachable: 1},
+		{AddressID: station.ID, Label: "Station", Candidates: 1, Reachable: 0},
+	}, reach)
+}
+
+func TestSubscriberMatrixService_RouteSubscribers_RepositoryError(t *testing.T) {
+	subscriptionRepo := mockRepo.NewMockSubscriptionRepository(t)
+	routingSvc := newTileLoadingRoutingService(14)
+	svc := NewSubscriberMatrixService(SubscriberMatrixServiceParams{
+		SubscriptionRepo: subscriptionRepo,
+		RoutingSvc:       routingSvc,
+	})
+	ctx := context.Background()
+
+	subscriptionRepo.EXPECT().FindSubscriberAddressesByTile(ctx, mock.Anything, mock.Anything).
+		Return(nil, errors.New("db down")).Once()
+
+	routes, err := svc.RouteSubscribers(ctx, []usecase.SubscriberMatrixSource{{MerchantID: uuid.New()}})
+
+	require.Error(t, err)
+	assert.Nil(t, routes)
+	assert.Empty(t, routingSvc.loads)
+}
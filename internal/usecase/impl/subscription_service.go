@@ -139,6 +139,60 @@ func (s *subscriptionService) UnsubscribeFromMerchant(ctx context.Context, userI
 	return nil
 }
 
+// SnoozeSubscription suppresses broadcasts from one merchant until the given time; a zero time clears the snooze
+func (s *subscriptionService) SnoozeSubscription(ctx context.Context, userID, merchantID uuid.UUID, until time.Time) (*entity.UserMerchantSubscription, error) {
+	snoozedUntil, err := snoozeEnd(until)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := s.subscriptionRepo.FindSubscriptionByUserAndMerchant(ctx, userID, merchantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.subscriptionRepo.UpdateSnoozedUntil(ctx, subscription.ID, snoozedUntil); err != nil {
+		return nil, err
+	}
+
+	subscription.SnoozedUntil = snoozedUntil
+
+	return subscription, nil
+}
+
+// SnoozeAllSubscriptions suppresses broadcasts from every subscribed merchant until the given time; a zero time clears the snooze
+func (s *subscriptionService) SnoozeAllSubscriptions(ctx context.Context, userID uuid.UUID, until time.Time) error {
+	snoozedUntil, err := snoozeEnd(until)
+	if err != nil {
+		return err
+	}
+
+	return s.subscriptionRepo.UpdateGlobalSnoozedUntil(ctx, userID, snoozedUntil)
+}
+
+// UpdateAllSubscriptionRadii sets the notification radius on every active subscription of a user in a single update
+func (s *subscriptionService) UpdateAllSubscriptionRadii(ctx context.Context, userID uuid.UUID, radiusKm float64) (int64, error) {
+	radius := radiusKm * 1000
+	if radius <= 0 || radius > s.config.LocationNotification.MaxRadius {
+		return 0, domainerrors.ErrInvalidNotificationRadius.WithDetails("notification radius must be positive and within the service limit")
+	}
+
+	return s.subscriptionRepo.UpdateNotificationRadiusByUser(ctx, userID, radius)
+}
+
+// snoozeEnd validates a requested snooze end, mapping the zero time to nil (snooze cleared)
+func snoozeEnd(until time.Time) (*time.Time, error) {
+	if until.IsZero() {
+		return nil, nil
+	}
+
+	if !until.After(time.Now()) {
+		return nil, domainerrors.ErrInvalidInput.WithDetails("snooze end time must be in the future")
+	}
+
+	return &until, nil
+}
+
 // GetUserSubscriptions retrieves all subscriptions for a user
 func (s *subscriptionService) GetUserSubscriptions(ctx context.Context, userID uuid.UUID) ([]*entity.UserMerchantSubscription, error) {
 	subscriptions, err := s.subscriptionRepo.FindSubscriptionsByUser(ctx, userID)
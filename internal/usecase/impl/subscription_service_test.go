@@ -3,6 +3,7 @@ package impl
 import (
 	"context"
 	"testing"
+	"time"
 
 	"radar/config"
 	"radar/internal/domain/entity"
@@ -32,6 +33,7 @@ func createTestSubscriptionService(t *testing.T) subscriptionServiceFixtures {
 	cfg := &config.Config{
 		LocationNotification: &config.LocationNotificationConfig{
 			DefaultRadius: 1000.0,
+			MaxRadius:     5000.0,
 		},
 	}
 	service := NewSubscriptionService(SubscriptionServiceParams{
@@ -285,6 +287,7 @@ func TestSubscriptionService_SubscribeToMerchant_ExistingDeviceUpdate(t *testing
 		UserID:   userID,
 		DeviceID: "device-123",
 		FCMToken: "old-token",
+		Platform: "ios",
 	}
 	deviceInfo := &usecase.DeviceInfo{
 		FCMToken: "new-token",
@@ -372,3 +375,84 @@ func TestSubscriptionService_ProcessQRSubscription_WithDevice(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, reloadedSub, subscription)
 }
+
+func TestSubscriptionService_SnoozeSubscription(t *testing.T) {
+	fx := createTestSubscriptionService(t)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	merchantID := uuid.New()
+	existingSub := buildReloadedSubscription(userID, merchantID, true, 1000.0)
+	until := time.Now().Add(2 * time.Hour)
+
+	fx.subRepo.EXPECT().FindSubscriptionByUserAndMerchant(ctx, userID, merchantID).Return(existingSub, nil)
+	fx.subRepo.EXPECT().UpdateSnoozedUntil(ctx, existingSub.ID, &until).Return(nil)
+
+	subscription, err := fx.service.SnoozeSubscription(ctx, userID, merchantID, until)
+
+	require.NoError(t, err)
+	require.NotNil(t, subscription.SnoozedUntil)
+	assert.Equal(t, until, *subscription.SnoozedUntil)
+}
+
+func TestSubscriptionService_SnoozeSubscription_ZeroTimeClears(t *testing.T) {
+	fx := createTestSubscriptionService(t)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	merchantID := uuid.New()
+	existingSub := buildReloadedSubscription(userID, merchantID, true, 1000.0)
+
+	fx.subRepo.EXPECT().FindSubscriptionByUserAndMerchant(ctx, userID, merchantID).Return(existingSub, nil)
+	fx.subRepo.EXPECT().UpdateSnoozedUntil(ctx, existingSub.ID, (*time.Time)(nil)).Return(nil)
+
+	subscription, err := fx.service.SnoozeSubscription(ctx, userID, merchantID, time.Time{})
+
+	require.NoError(t, err)
+	assert.Nil(t, subscription.SnoozedUntil)
+}
+
+func TestSubscriptionService_SnoozeSubscription_PastTimeRejected(t *testing.T) {
+	fx := createTestSubscriptionService(t)
+
+	_, err := fx.service.SnoozeSubscription(context.Background(), uuid.New(), uuid.New(), time.Now().Add(-time.Minute))
+
+	require.ErrorIs(t, err, domainerrors.ErrInvalidInput)
+}
+
+func TestSubscriptionService_SnoozeAllSubscriptions(t *testing.T) {
+	fx := createTestSubscriptionService(t)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	until := time.Now().Add(2 * time.Hour)
+
+	fx.subRepo.EXPECT().UpdateGlobalSnoozedUntil(ctx, userID, &until).Return(nil)
+
+	require.NoError(t, fx.service.SnoozeAllSubscriptions(ctx, userID, until))
+}
+
+func TestSubscriptionService_UpdateAllSubscriptionRadii(t *testing.T) {
+	fx := createTestSubscriptionService(t)
+
+	ctx := context.Background()
+	userID := uuid.New()
+
+	// Only the caller's subscriptions are updated
+	fx.subRepo.EXPECT().UpdateNotificationRadiusByUser(ctx, userID, 2500.0).Return(int64(3), nil).Once()
+
+	updated, err := fx.service.UpdateAllSubscriptionRadii(ctx, userID, 2.5)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), updated)
+}
+
+func TestSubscriptionService_UpdateAllSubscriptionRadii_OutOfBoundsRejected(t *testing.T) {
+	for _, radiusKm := range []float64{0, -1, 5.001} {
+		fx := createTestSubscriptionService(t)
+
+		_, err := fx.service.UpdateAllSubscriptionRadii(context.Background(), uuid.New(), radiusKm)
+
+		require.ErrorIs(t, err, domainerrors.ErrInvalidNotificationRadius, "radius %v km", radiusKm)
+	}
+}
@@ -0,0 +1,31 @@
+package impl
+
+import (
+	"context"
+
+	"radar/internal/domain/service"
+)
+
+// noopUserEventPublisher discards every user event
+type noopUserEventPublisher struct{}
+
+func (noopUserEventPublisher) PublishUserEvent(context.Context, service.UserEvent) {}
+
+// userEventPublisherOrDefault returns the injected publisher, falling back to one that publishes nothing
+func userEventPublisherOrDefault(publisher service.UserEventPublisher) service.UserEventPublisher {
+	if publisher == nil {
+		return noopUserEventPublisher{}
+	}
+
+	return publisher
+}
+
+// publishUserEvent publishes event, if any. Callers invoke it only after the transaction that made the
+// change has committed, so subscribers never observe a change that was rolled back.
+func (srv *userService) publishUserEvent(ctx context.Context, event service.UserEvent) {
+	if event == nil {
+		return
+	}
+
+	srv.userEvents.PublishUserEvent(ctx, event)
+}
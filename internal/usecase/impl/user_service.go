@@ -34,10 +34,14 @@ type userService struct {
 	tokenService        service.TokenService
 	googleAuthService   service.OAuthAuthService
 	notificationSvc     service.NotificationService
+	idGenerator         service.IDGenerator
+	clock               service.Clock
+	userEvents          service.UserEventPublisher
 	maxActiveSessions   int
 	loginThrottleCfg    config.LoginThrottleConfig
 	loginThrottlePolicy policy.LoginThrottlePolicy
 	notificationTimeout time.Duration
+	adminUserIDs        map[uuid.UUID]struct{}
 	logger              *slog.Logger
 }
 
@@ -66,6 +70,9 @@ type UserServiceParams struct {
 	TokenService      service.TokenService
 	GoogleAuthService service.OAuthAuthService
 	NotificationSvc   service.NotificationService
+	IDGenerator       service.IDGenerator        `optional:"true"`
+	Clock             service.Clock              `optional:"true"`
+	UserEvents        service.UserEventPublisher `optional:"true"`
 	Config            *config.Config
 	Logger            *slog.Logger
 }
@@ -96,14 +103,36 @@ func NewUserService(params UserServiceParams) usecase.UserUsecase {
 		tokenService:        params.TokenService,
 		googleAuthService:   params.GoogleAuthService,
 		notificationSvc:     params.NotificationSvc,
+		idGenerator:         idGeneratorOrDefault(params.IDGenerator),
+		clock:               clockOrDefault(params.Clock),
+		userEvents:          userEventPublisherOrDefault(params.UserEvents),
 		maxActiveSessions:   maxActiveSessions,
 		loginThrottleCfg:    loginThrottleCfg,
 		loginThrottlePolicy: policy.DefaultLoginThrottlePolicy(),
 		notificationTimeout: notificationTimeout,
+		adminUserIDs:        parseAdminUserIDs(cfg.Auth.AdminUserIDs, params.Logger),
 		logger:              params.Logger,
 	}
 }
 
+// parseAdminUserIDs builds the admin allowlist, skipping entries that are not valid UUIDs.
+func parseAdminUserIDs(values []string, logger *slog.Logger) map[uuid.UUID]struct{} {
+	adminUserIDs := make(map[uuid.UUID]struct{}, len(values))
+	for _, value := range values {
+		id, err := uuid.Parse(strings.TrimSpace(value))
+		if err != nil {
+			if logger != nil {
+				logger.Warn("Ignoring invalid admin user ID in config", slog.String("value", value))
+			}
+
+			continue
+		}
+		adminUserIDs[id] = struct{}{}
+	}
+
+	return adminUserIDs
+}
+
 // log returns a request-scoped logger if available, otherwise falls back to the service's logger.
 func (srv *userService) log(ctx context.Context) *slog.Logger {
 	return observability.LoggerFromContextOrDefault(ctx, srv.logger)
@@ -269,7 +298,7 @@ func (srv *userService) Login(ctx context.Context, input *usecase.LoginInput) (*
 		return nil, err
 	}
 
-	output, err := srv.authenticate(ctx, &authRequest{
+	output, event, err := srv.resolveAuthentication(ctx, &authRequest{
 		Method:   authMethodEmailPassword,
 		Intent:   authIntentLogin,
 		Provider: entity.ProviderTypeEmail,
@@ -289,12 +318,13 @@ func (srv *userService) Login(ctx context.Context, input *usecase.LoginInput) (*
 	if err := srv.recordLoginSuccess(ctx, input.Email); err != nil {
 		return nil, err
 	}
+	srv.publishUserEvent(ctx, event)
 
 	return output, nil
 }
 
 func (srv *userService) persistLoginRefreshToken(ctx context.Context, userID uuid.UUID, refreshTokenString string) error {
-	familyID := uuid.New()
+	familyID := srv.idGenerator.NewID()
 
 	if srv.maxActiveSessions > 0 {
 		// When session limit is enabled, keep lock/count/insert in one short transaction.
@@ -343,7 +373,7 @@ func (srv *userService) RefreshToken(ctx context.Context, input *usecase.Refresh
 	if result.ReuseDetected {
 		srv.sendTokenReuseNotification(ctx, claims.UserID)
 
-		return nil, domainerrors.ErrRefreshTokenInvalid
+		return nil, domainerrors.ErrTokenReuseDetected
 	}
 
 	return &usecase.RefreshTokenOutput{
@@ -433,7 +463,7 @@ func (srv *userService) executeRefreshTokenRotationTx(
 	if storedToken.IsRevoked {
 		return handleRevokedRefreshToken(ctx, refreshRepo, storedToken, result)
 	}
-	if storedToken.ExpiresAt.Before(time.Now()) {
+	if storedToken.ExpiresAt.Before(srv.clock.Now()) {
 		return domainerrors.ErrRefreshTokenExpired
 	}
 
@@ -467,6 +497,12 @@ func (srv *userService) rotateActiveRefreshToken(
 		return err
 	}
 
+	usedAt := srv.clock.Now()
+	if err := refreshRepo.TouchRefreshToken(ctx, storedToken.TokenHash, usedAt); err != nil {
+		return err
+	}
+	storedToken.LastUsedAt = &usedAt
+
 	accessToken, refreshToken, err := srv.issueAndStoreRotatedTokenPair(ctx, refreshRepo, user, storedToken)
 	if err != nil {
 		return err
@@ -492,11 +528,13 @@ func (srv *userService) issueAndStoreRotatedTokenPair(
 		return "", "", fmt.Errorf("failed to rotate refresh token pair: %w", err)
 	}
 
+	// The replacement inherits the session's last use so listings reflect activity across rotations
 	newRefreshToken := &entity.RefreshToken{
-		UserID:    user.ID,
-		TokenHash: refreshTokenHash,
-		FamilyID:  storedToken.FamilyID,
-		ExpiresAt: time.Now().Add(srv.tokenService.GetRefreshTokenDuration()),
+		UserID:     user.ID,
+		TokenHash:  refreshTokenHash,
+		FamilyID:   storedToken.FamilyID,
+		ExpiresAt:  srv.clock.Now().Add(srv.tokenService.GetRefreshTokenDuration()),
+		LastUsedAt: storedToken.LastUsedAt,
 	}
 	if err := refreshRepo.CreateRefreshToken(ctx, newRefreshToken); err != nil {
 		return "", "", err
@@ -564,6 +602,7 @@ func (srv *userService) Logout(ctx context.Context, input *usecase.LogoutInput)
 
 	tokenHash := srv.tokenService.HashToken(input.RefreshToken)
 
+	var revokedUserID uuid.UUID
 	if err := srv.txManager.Execute(ctx, func(repoFactory repository.RepositoryFactory) error {
 		refreshRepo := repoFactory.RefreshTokenRepo()
 		userRepo := repoFactory.UserRepo()
@@ -581,13 +620,21 @@ func (srv *userService) Logout(ctx context.Context, input *usecase.LogoutInput)
 			return err
 		}
 
-		return refreshRepo.RevokeTokenFamily(ctx, token.FamilyID)
+		if err := refreshRepo.RevokeTokenFamily(ctx, token.FamilyID); err != nil {
+			return err
+		}
+		revokedUserID = token.UserID
+
+		return nil
 	}); err != nil {
 		srv.log(ctx).Error("Failed to revoke refresh token family during logout", slog.String("error", err.Error()))
 
 		return replaceWithSourceStack(err, domainerrors.ErrInternalError)
 	}
 	srv.log(ctx).Info("Successfully logged out")
+	if revokedUserID != uuid.Nil {
+		srv.publishUserEvent(ctx, service.UserLoggedOut{UserID: revokedUserID, OccurredAt: srv.clock.Now()})
+	}
 
 	return nil
 }
@@ -644,11 +691,20 @@ func (srv *userService) LinkProvider(ctx context.Context, input usecase.LinkProv
 	if err != nil {
 		return nil, err
 	}
+	if provider == entity.ProviderTypeGoogle {
+		srv.publishUserEvent(ctx, service.GoogleLinked{UserID: resolution.user.ID, OccurredAt: srv.clock.Now()})
+	}
 	if output != nil {
 		return output, nil
 	}
 
-	return srv.buildAuthenticatedResult(ctx, resolution.user)
+	result, err := srv.buildAuthenticatedResult(ctx, resolution.user)
+	if err != nil {
+		return nil, err
+	}
+	srv.publishUserEvent(ctx, service.UserLoggedIn{UserID: resolution.user.ID, Provider: provider, OccurredAt: srv.clock.Now()})
+
+	return result, nil
 }
 
 type linkProviderResolution struct {
@@ -905,6 +961,9 @@ func (srv *userService) extractUserRoles(user *entity.User) entity.Roles {
 	if user.MerchantProfile != nil {
 		roles = append(roles, entity.RoleMerchant)
 	}
+	if _, ok := srv.adminUserIDs[user.ID]; ok {
+		roles = append(roles, entity.RoleAdmin)
+	}
 
 	return roles
 }
@@ -962,7 +1021,7 @@ func (srv *userService) storeRefreshTokenWithRepo(
 		UserID:    userID,
 		TokenHash: refreshTokenHash,
 		FamilyID:  familyID,
-		ExpiresAt: time.Now().Add(srv.tokenService.GetRefreshTokenDuration()),
+		ExpiresAt: srv.clock.Now().Add(srv.tokenService.GetRefreshTokenDuration()),
 	}
 
 	if err := refreshRepo.CreateRefreshToken(ctx, newRefreshToken); err != nil {
@@ -1061,6 +1120,7 @@ func (srv *userService) LinkGoogleAccount(ctx context.Context, userID uuid.UUID,
 		return fmt.Errorf("failed to link Google account: %w", err)
 	}
 	srv.log(ctx).Info("Successfully linked Google account", slog.String("user_id", userID.String()))
+	srv.publishUserEvent(ctx, service.GoogleLinked{UserID: userID, OccurredAt: srv.clock.Now()})
 
 	return nil
 }
@@ -1186,6 +1246,7 @@ func (srv *userService) UnlinkGoogleAccount(ctx context.Context, userID uuid.UUI
 		return err
 	}
 	srv.log(ctx).Info("Successfully unlinked Google account", slog.String("user_id", userID.String()))
+	srv.publishUserEvent(ctx, service.GoogleUnlinked{UserID: userID, OccurredAt: srv.clock.Now()})
 
 	return nil
 }
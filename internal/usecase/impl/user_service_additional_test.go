@@ -190,6 +190,10 @@ func TestUserService_RegisterMerchant_ExistingOAuthUserReturnsConflict(t *testin
 
 func TestUserService_RefreshToken_Success(t *testing.T) {
 	fx := createTestUserService(t)
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	svc, ok := fx.service.(*userService)
+	require.True(t, ok)
+	svc.clock = newFakeClock(now)
 
 	ctx := context.Background()
 	userID := uuid.New()
@@ -233,8 +237,9 @@ func TestUserService_RefreshToken_Success(t *testing.T) {
 				Return(&entity.RefreshToken{
 					ID:        oldTokenID,
 					UserID:    userID,
+					TokenHash: "refresh-token-hash",
 					FamilyID:  familyID,
-					ExpiresAt: time.Now().Add(time.Hour),
+					ExpiresAt: now.Add(time.Hour),
 				}, nil)
 			mockUserRepo.EXPECT().
 				FindByID(ctx, userID).
@@ -243,11 +248,16 @@ func TestUserService_RefreshToken_Success(t *testing.T) {
 					UserProfile:     &entity.UserProfile{UserID: userID},
 					MerchantProfile: &entity.MerchantProfile{UserID: userID},
 				}, nil)
+			mockRefreshRepo.EXPECT().
+				TouchRefreshToken(ctx, "refresh-token-hash", now).
+				Return(nil).
+				Once()
 			mockRefreshRepo.EXPECT().
 				CreateRefreshToken(ctx, mock.MatchedBy(func(token *entity.RefreshToken) bool {
 					return token.UserID == userID &&
 						token.FamilyID == familyID &&
-						token.TokenHash == "new-refresh-token-hash"
+						token.TokenHash == "new-refresh-token-hash" &&
+						token.LastUsedAt != nil && token.LastUsedAt.Equal(now)
 				})).
 				Run(func(_ context.Context, token *entity.RefreshToken) {
 					token.ID = newTokenID
@@ -337,7 +347,7 @@ func TestUserService_RefreshToken_ExpiredRevokedTokenTriggersReuseDetection(t *t
 
 	require.Error(t, err)
 	assert.Nil(t, output)
-	assert.True(t, errors.Is(err, domainerrors.ErrRefreshTokenInvalid))
+	assert.True(t, errors.Is(err, domainerrors.ErrTokenReuseDetected))
 	fx.tokenService.AssertNotCalled(t, "RotateTokens", mock.Anything, mock.Anything)
 	select {
 	case <-notificationLoaded:
@@ -461,6 +471,81 @@ func TestUserService_RefreshToken_RefreshTokenNotFound(t *testing.T) {
 	fx.tokenService.AssertNotCalled(t, "RotateTokens", mock.Anything, mock.Anything)
 }
 
+func TestUserService_RefreshToken_ExpiresOnceClockPassesExpiry(t *testing.T) {
+	fx := createTestUserService(t)
+	issuedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	svc, ok := fx.service.(*userService)
+	require.True(t, ok)
+	svc.clock = newFakeClock(issuedAt.AddDate(0, 0, 30).Add(time.Second))
+
+	ctx := context.Background()
+	userID := uuid.New()
+	input := &usecase.RefreshTokenInput{RefreshToken: "month-old-refresh-token"}
+
+	fx.tokenService.EXPECT().
+		ValidateToken(input.RefreshToken).
+		Return(&service.Claims{UserID: userID, Type: service.TokenTypeRefresh}, nil).
+		Once()
+	fx.tokenService.EXPECT().
+		HashToken(input.RefreshToken).
+		Return("month-old-refresh-token-hash").
+		Once()
+
+	fx.txManager.EXPECT().
+		Execute(ctx, mock.AnythingOfType("func(repository.RepositoryFactory) error")).
+		RunAndReturn(func(ctx context.Context, fn func(repository.RepositoryFactory) error) error {
+			mockFactory := mockRepo.NewMockRepositoryFactory(t)
+			mockUserRepo := mockRepo.NewMockUserRepository(t)
+			mockRefreshRepo := mockRepo.NewMockRefreshTokenRepository(t)
+
+			mockFactory.EXPECT().UserRepo().Return(mockUserRepo)
+			mockFactory.EXPECT().RefreshTokenRepo().Return(mockRefreshRepo)
+			mockUserRepo.EXPECT().
+				AcquireSessionMutex(ctx, userID).
+				Return(nil)
+			mockRefreshRepo.EXPECT().
+				FindRefreshTokenByHashIncludingRevoked(ctx, "month-old-refresh-token-hash").
+				Return(&entity.RefreshToken{
+					ID:        uuid.New(),
+					UserID:    userID,
+					FamilyID:  uuid.New(),
+					CreatedAt: issuedAt,
+					ExpiresAt: issuedAt.AddDate(0, 0, 30),
+				}, nil)
+
+			return fn(mockFactory)
+		}).
+		Once()
+
+	output, err := fx.service.RefreshToken(ctx, input)
+
+	require.Error(t, err)
+	assert.Nil(t, output)
+	assert.ErrorIs(t, err, domainerrors.ErrRefreshTokenExpired)
+}
+
+func TestUserService_PersistLoginRefreshToken_ExpiryFollowsClock(t *testing.T) {
+	fx := createTestUserService(t)
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	svc, ok := fx.service.(*userService)
+	require.True(t, ok)
+	svc.clock = newFakeClock(now)
+
+	ctx := context.Background()
+	userID := uuid.New()
+
+	fx.tokenService.EXPECT().HashToken("refresh-token").Return("refresh-token-hash").Once()
+	fx.tokenService.EXPECT().GetRefreshTokenDuration().Return(30 * 24 * time.Hour).Once()
+	fx.refreshTokenRepo.EXPECT().
+		CreateRefreshToken(ctx, mock.MatchedBy(func(token *entity.RefreshToken) bool {
+			return token.ExpiresAt.Equal(now.Add(30 * 24 * time.Hour))
+		})).
+		Return(nil).
+		Once()
+
+	require.NoError(t, svc.persistLoginRefreshToken(ctx, userID, "refresh-token"))
+}
+
 func TestUserService_RefreshToken_RefreshTokenExpired(t *testing.T) {
 	fx := createTestUserService(t)
 
@@ -618,6 +703,7 @@ func TestUserService_Logout_InvalidTokenStillDeletesRefreshToken(t *testing.T) {
 	ctx := context.Background()
 	input := &usecase.LogoutInput{RefreshToken: "stale-refresh-token"}
 	familyID := uuid.New()
+	userID := uuid.New()
 
 	fx.tokenService.EXPECT().
 		ValidateToken(input.RefreshToken).
@@ -638,7 +724,7 @@ func TestUserService_Logout_InvalidTokenStillDeletesRefreshToken(t *testing.T) {
 			mockFactory.EXPECT().RefreshTokenRepo().Return(mockRefreshRepo)
 			mockFactory.EXPECT().UserRepo().Return(mockUserRepo)
 
-			storedToken := &entity.RefreshToken{ID: uuid.New(), UserID: uuid.New(), FamilyID: familyID}
+			storedToken := &entity.RefreshToken{ID: uuid.New(), UserID: userID, FamilyID: familyID}
 			mockRefreshRepo.EXPECT().
 				FindRefreshTokenByHashIncludingRevoked(ctx, "stale-refresh-token-hash").
 				Return(storedToken, nil)
@@ -655,6 +741,10 @@ func TestUserService_Logout_InvalidTokenStillDeletesRefreshToken(t *testing.T) {
 		Once()
 
 	require.NoError(t, fx.service.Logout(ctx, input))
+	require.Len(t, fx.userEvents.events, 1)
+	loggedOut, ok := fx.userEvents.events[0].(service.UserLoggedOut)
+	require.True(t, ok, "logout should publish UserLoggedOut, got %T", fx.userEvents.events[0])
+	assert.Equal(t, userID, loggedOut.UserID)
 }
 
 func TestUserService_Logout_RefreshTokenNotFoundIsIdempotent(t *testing.T) {
@@ -690,6 +780,8 @@ func TestUserService_Logout_RefreshTokenNotFoundIsIdempotent(t *testing.T) {
 		Once()
 
 	require.NoError(t, fx.service.Logout(ctx, input))
+	// Nothing was revoked, so there is no session to report as logged out
+	assert.Empty(t, fx.userEvents.events)
 }
 
 func TestUserService_Logout_DeleteRefreshTokenErrorReturnsInternalError(t *testing.T) {
@@ -742,6 +834,7 @@ func TestUserService_Logout_DeleteRefreshTokenErrorReturnsInternalError(t *testi
 	assert.ErrorIs(t, err, databaseErr)
 	_, ok := errors.AsType[stack.Provider](err)
 	assert.True(t, ok, "logout error should retain the repository source stack")
+	assert.Empty(t, fx.userEvents.events)
 }
 
 func TestUserService_LogoutAllDevices_Success(t *testing.T) {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	"radar/internal/domain/entity"
 	domainerrors "radar/internal/domain/errors"
@@ -23,6 +24,14 @@ const (
 	authMethodOAuth         authMethod = "oauth"
 )
 
+// registrationRetryWindow bounds how long after an account was created repeating its email registration
+// is treated as a client retry that signs in, rather than a conflict
+const registrationRetryWindow = 10 * time.Minute
+
+// errEmailRegistered rejects a registration whose email belongs to an account the registration did not just
+// create; resolving it again cannot change the outcome.
+var errEmailRegistered = fmt.Errorf("email already registered: %w", domainerrors.ErrUserAlreadyExists)
+
 type authIntent string
 
 const (
@@ -64,43 +73,128 @@ type authResolution struct {
 	// StoredPasswordHash is set when an existing email auth record is found,
 	// so that password hash check can happen outside the transaction.
 	StoredPasswordHash string
+	// RegistrationRetry is set when an email registration repeats one that just created the account
+	RegistrationRetry bool
+	// Created is set when the resolution created the account
+	Created bool
 }
 
+// authenticate resolves req and publishes the resulting user event once the account changes have committed.
 func (srv *userService) authenticate(ctx context.Context, req *authRequest) (*usecase.AuthResult, error) {
-	verifiedIdentity, err := srv.verifyIdentity(ctx, req)
+	result, event, err := srv.resolveAuthentication(ctx, req)
 	if err != nil {
 		return nil, err
 	}
+	srv.publishUserEvent(ctx, event)
 
-	var resolution *authResolution
+	return result, nil
+}
 
-	err = srv.txManager.Execute(ctx, func(repoFactory repository.RepositoryFactory) error {
-		var resolveErr error
-		resolution, resolveErr = srv.resolveAuthRequest(ctx, repoFactory, req, verifiedIdentity)
+// resolveAuthentication resolves req to an auth result along with the user event it produced, if any:
+// UserRegistered when it created the account, UserLoggedIn when it issued a session to an existing one.
+// The event is returned rather than published so callers with follow-up steps publish only once those succeed.
+func (srv *userService) resolveAuthentication(ctx context.Context, req *authRequest) (*usecase.AuthResult, domainservice.UserEvent, error) {
+	verifiedIdentity, err := srv.verifyIdentity(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		return resolveErr
-	})
+	resolution, err := srv.resolveInTransaction(ctx, req, verifiedIdentity)
+	if err != nil && isConcurrentRegistration(req, err) {
+		// A client retrying a timed-out registration can race its own first attempt, which commits the
+		// account between our lookup and insert. Resolve again so the retry sees the committed account.
+		resolution, err = srv.resolveInTransaction(ctx, req, verifiedIdentity)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute authentication transaction: %w", err)
+		return nil, nil, fmt.Errorf("failed to execute authentication transaction: %w", err)
 	}
 
 	// Password hash check is CPU-bound; run it outside the transaction to avoid holding
 	// the DB connection while hashing.
-	if resolution.StoredPasswordHash != "" {
+	switch {
+	case resolution.RegistrationRetry:
+		if err := srv.checkRegistrationRetry(ctx, req, resolution.StoredPasswordHash); err != nil {
+			return nil, nil, err
+		}
+	case resolution.StoredPasswordHash != "":
 		if !srv.hasher.Check(req.Password, resolution.StoredPasswordHash) {
-			return nil, fmt.Errorf("invalid credentials: %w", domainerrors.ErrInvalidCredentials)
+			return nil, nil, fmt.Errorf("invalid credentials: %w", domainerrors.ErrInvalidCredentials)
+		}
+	}
+
+	var event domainservice.UserEvent
+	if resolution.Created {
+		event = domainservice.UserRegistered{
+			UserID:     resolution.User.ID,
+			Role:       req.RequestedRole,
+			Provider:   verifiedIdentity.Provider,
+			OccurredAt: srv.clock.Now(),
+		}
+	}
+
+	var result *usecase.AuthResult
+	switch {
+	case resolution.LinkingRequired:
+		result, err = srv.buildLinkingRequiredResult(resolution.User, req, resolution.LinkingProvider, resolution.LinkingProviderUserID)
+	case resolution.OnboardingRequired:
+		result, err = srv.buildOnboardingRequiredResult(resolution.User, req.RequestedRole)
+	default:
+		result, err = srv.buildAuthenticatedResult(ctx, resolution.User)
+		if err == nil && !resolution.Created {
+			event = domainservice.UserLoggedIn{
+				UserID:     resolution.User.ID,
+				Provider:   verifiedIdentity.Provider,
+				OccurredAt: srv.clock.Now(),
+			}
 		}
 	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result, event, nil
+}
+
+func (srv *userService) resolveInTransaction(ctx context.Context, req *authRequest, identity *verifiedIdentity) (*authResolution, error) {
+	var resolution *authResolution
+
+	err := srv.txManager.Execute(ctx, func(repoFactory repository.RepositoryFactory) error {
+		var resolveErr error
+		resolution, resolveErr = srv.resolveAuthRequest(ctx, repoFactory, req, identity)
+
+		return resolveErr
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	if resolution.LinkingRequired {
-		return srv.buildLinkingRequiredResult(resolution.User, req, resolution.LinkingProvider, resolution.LinkingProviderUserID)
+	return resolution, nil
+}
+
+// checkRegistrationRetry verifies the password of a registration retry under the same throttle as Login,
+// so repeating a registration cannot be used to guess the password around the account lockout.
+// A different password means the email belongs to someone else's account and is a conflict.
+func (srv *userService) checkRegistrationRetry(ctx context.Context, req *authRequest, storedPasswordHash string) error {
+	attempt, err := srv.checkLoginThrottle(ctx, req.Email)
+	if err != nil {
+		return err
 	}
 
-	if resolution.OnboardingRequired {
-		return srv.buildOnboardingRequiredResult(resolution.User, req.RequestedRole)
+	if !srv.hasher.Check(req.Password, storedPasswordHash) {
+		if err := srv.recordLoginFailure(ctx, req.Email, attempt.UserID); err != nil {
+			return err
+		}
+
+		return fmt.Errorf("email already registered with different credentials: %w", domainerrors.ErrUserAlreadyExists)
 	}
 
-	return srv.buildAuthenticatedResult(ctx, resolution.User)
+	return srv.recordLoginSuccess(ctx, req.Email)
+}
+
+// isConcurrentRegistration reports whether an email registration failed because the account was created concurrently
+func isConcurrentRegistration(req *authRequest, err error) bool {
+	return req.Method == authMethodEmailPassword && req.Intent == authIntentRegister && !errors.Is(err, errEmailRegistered) &&
+		(errors.Is(err, domainerrors.ErrUserAlreadyExists) || errors.Is(err, domainerrors.ErrAuthAlreadyExists))
 }
 
 func (srv *userService) verifyIdentity(ctx context.Context, req *authRequest) (*verifiedIdentity, error) {
@@ -185,7 +279,7 @@ func (srv *userService) resolveAuthRequest(
 	}
 
 	if err == nil {
-		return srv.resolveExistingLinkedUser(ctx, userRepo, req, identity, authRecord)
+		return srv.resolveExistingLinkedUser(ctx, userRepo, authRepo, req, identity, authRecord)
 	}
 
 	if req.Method == authMethodEmailPassword && req.Intent == authIntentLogin {
@@ -198,6 +292,7 @@ func (srv *userService) resolveAuthRequest(
 func (srv *userService) resolveExistingLinkedUser(
 	ctx context.Context,
 	userRepo repository.UserRepository,
+	authRepo repository.AuthRepository,
 	req *authRequest,
 	identity *verifiedIdentity,
 	authRecord *entity.Authentication,
@@ -207,6 +302,10 @@ func (srv *userService) resolveExistingLinkedUser(
 		return nil, err
 	}
 
+	if req.Method == authMethodEmailPassword && req.Intent == authIntentRegister {
+		return srv.resolveRegistrationRetry(ctx, authRepo, req, user, authRecord)
+	}
+
 	onboardingRequired, err := srv.ensureRequestedRole(ctx, userRepo, user, req, identity)
 	if err != nil {
 		return nil, err
@@ -225,6 +324,64 @@ func (srv *userService) resolveExistingLinkedUser(
 	return resolution, nil
 }
 
+// resolveRegistrationRetry resolves an email registration whose email already has a login. Only a repeat of
+// the registration that just created the account is a retry, whose password is then checked like a login;
+// any other account is a conflict without a password check, so registration cannot probe passwords.
+func (srv *userService) resolveRegistrationRetry(
+	ctx context.Context,
+	authRepo repository.AuthRepository,
+	req *authRequest,
+	user *entity.User,
+	authRecord *entity.Authentication,
+) (*authResolution, error) {
+	retry, err := srv.isRegistrationRetry(ctx, authRepo, req, user)
+	if err != nil {
+		return nil, err
+	}
+	if !retry {
+		return nil, errEmailRegistered
+	}
+
+	return &authResolution{
+		User:               user,
+		OnboardingRequired: req.RequestedRole == entity.RoleMerchant && !userHasMerchantProfile(user),
+		StoredPasswordHash: authRecord.PasswordHash,
+		RegistrationRetry:  true,
+	}, nil
+}
+
+// isRegistrationRetry reports whether user is the skeleton a registration like req created moments ago:
+// created within registrationRetryWindow, with no login but its email one, and holding the requested role
+// or, for a merchant, still awaiting onboarding.
+func (srv *userService) isRegistrationRetry(
+	ctx context.Context,
+	authRepo repository.AuthRepository,
+	req *authRequest,
+	user *entity.User,
+) (bool, error) {
+	if srv.clock.Now().Sub(user.CreatedAt) > registrationRetryWindow {
+		return false, nil
+	}
+
+	switch req.RequestedRole {
+	case entity.RoleMerchant:
+		if user.UserProfile != nil && !userHasMerchantProfile(user) {
+			return false, nil
+		}
+	default:
+		if !userHasUserProfile(user) || userHasMerchantProfile(user) {
+			return false, nil
+		}
+	}
+
+	auths, err := authRepo.ListAuthenticationsByUserID(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+
+	return len(auths) == 1 && auths[0].Provider == entity.ProviderTypeEmail, nil
+}
+
 func (srv *userService) resolveUnlinkedIdentity(
 	ctx context.Context,
 	repoFactory repository.RepositoryFactory,
@@ -308,6 +465,7 @@ func (srv *userService) createNewUserForIdentity(
 	return &authResolution{
 		User:               user,
 		OnboardingRequired: onboardingRequired,
+		Created:            true,
 	}, nil
 }
 
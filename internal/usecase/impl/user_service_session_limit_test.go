@@ -171,6 +171,10 @@ func (r *sessionLimitTestUserRepo) Update(_ context.Context, _ *entity.User) err
 	panic("not implemented")
 }
 
+func (r *sessionLimitTestUserRepo) IsBusinessLicenseTaken(_ context.Context, _ string, _ uuid.UUID) (bool, error) {
+	panic("not implemented")
+}
+
 type sessionLimitTestAuthRepo struct {
 	authRecord *entity.Authentication
 }
@@ -257,11 +261,15 @@ func (r *sessionLimitTestRefreshRepo) DeleteRefreshTokenByHash(_ context.Context
 	panic("not implemented")
 }
 
+func (r *sessionLimitTestRefreshRepo) TouchRefreshToken(_ context.Context, _ string, _ time.Time) error {
+	panic("not implemented")
+}
+
 func (r *sessionLimitTestRefreshRepo) DeleteRefreshTokensByUserID(_ context.Context, _ uuid.UUID) error {
 	panic("not implemented")
 }
 
-func (r *sessionLimitTestRefreshRepo) DeleteExpiredRefreshTokens(_ context.Context, _ int) error {
+func (r *sessionLimitTestRefreshRepo) DeleteExpiredRefreshTokens(_ context.Context, _ time.Time, _ int) (int64, error) {
 	panic("not implemented")
 }
 
@@ -400,6 +408,14 @@ func (r *sessionLimitTestDeviceRepo) UpdateFCMToken(_ context.Context, _ uuid.UU
 	panic("not implemented")
 }
 
+func (r *sessionLimitTestDeviceRepo) UpdateDeviceClientInfo(_ context.Context, _ uuid.UUID, _, _ string) error {
+	panic("not implemented")
+}
+
+func (r *sessionLimitTestDeviceRepo) UpdateDeviceNotificationSettings(_ context.Context, _ uuid.UUID, _ entity.DeviceNotificationSettings) error {
+	panic("not implemented")
+}
+
 func (r *sessionLimitTestDeviceRepo) DeleteDevice(_ context.Context, _ uuid.UUID) error {
 	panic("not implemented")
 }
@@ -420,10 +436,30 @@ func (r *sessionLimitTestDeviceRepo) SoftDeleteStaleDevices(_ context.Context, _
 	panic("not implemented")
 }
 
+func (r *sessionLimitTestDeviceRepo) RecordInvalidTokenStrike(_ context.Context, _ uuid.UUID, _ time.Time, _ time.Duration) (int, error) {
+	panic("not implemented")
+}
+
+func (r *sessionLimitTestDeviceRepo) ResetInvalidTokenStrikes(_ context.Context, _ []uuid.UUID) error {
+	panic("not implemented")
+}
+
+func (r *sessionLimitTestDeviceRepo) RecordDeliverySuccess(_ context.Context, _ []uuid.UUID, _ time.Time) error {
+	panic("not implemented")
+}
+
+func (r *sessionLimitTestDeviceRepo) FindStaleDevices(_ context.Context, _ time.Time, _ uuid.UUID, _ int) ([]*entity.UserDevice, error) {
+	panic("not implemented")
+}
+
+func (r *sessionLimitTestDeviceRepo) DeleteDevices(_ context.Context, _ []uuid.UUID) (int64, error) {
+	panic("not implemented")
+}
+
 type sessionLimitTestNotificationService struct{}
 
-func (s *sessionLimitTestNotificationService) SendBatchNotification(_ context.Context, _ []string, _, _ string, _ map[string]string) (int, int, []string, error) {
-	return 0, 0, nil, nil
+func (s *sessionLimitTestNotificationService) SendBatchNotification(_ context.Context, _ []string, _, _ string, _ map[string]string) ([]service.TokenResult, error) {
+	return nil, nil
 }
 
 func (s *sessionLimitTestNotificationService) SendSingleNotification(_ context.Context, _, _, _ string, _ map[string]string) error {
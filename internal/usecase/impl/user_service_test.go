@@ -10,6 +10,7 @@ import (
 	"radar/internal/domain/entity"
 	domainerrors "radar/internal/domain/errors"
 	"radar/internal/domain/repository"
+	"radar/internal/domain/service"
 	mockRepo "radar/internal/mocks/repository"
 	mockSvc "radar/internal/mocks/service"
 	"radar/internal/usecase"
@@ -34,6 +35,16 @@ type userServiceFixtures struct {
 	tokenService      *mockSvc.MockTokenService
 	googleAuthService *mockSvc.MockOAuthAuthService
 	notificationSvc   *mockSvc.MockNotificationService
+	userEvents        *recordingUserEventPublisher
+}
+
+// recordingUserEventPublisher records the user events the service publishes.
+type recordingUserEventPublisher struct {
+	events []service.UserEvent
+}
+
+func (p *recordingUserEventPublisher) PublishUserEvent(_ context.Context, event service.UserEvent) {
+	p.events = append(p.events, event)
 }
 
 func createTestUserService(t *testing.T) *userServiceFixtures {
@@ -48,6 +59,7 @@ func createTestUserService(t *testing.T) *userServiceFixtures {
 	googleAuthService := mockSvc.NewMockOAuthAuthService(t)
 	notificationSvc := mockSvc.NewMockNotificationService(t)
 	googleAuthService.EXPECT().GetProvider().Return(entity.ProviderTypeGoogle).Maybe()
+	userEvents := &recordingUserEventPublisher{}
 	logger := newDiscardLogger()
 
 	service := NewUserService(UserServiceParams{
@@ -61,6 +73,7 @@ func createTestUserService(t *testing.T) *userServiceFixtures {
 		TokenService:      tokenService,
 		GoogleAuthService: googleAuthService,
 		NotificationSvc:   notificationSvc,
+		UserEvents:        userEvents,
 		Config:            newTestConfig(0),
 		Logger:            logger,
 	})
@@ -78,6 +91,7 @@ func createTestUserService(t *testing.T) *userServiceFixtures {
 		tokenService:      tokenService,
 		googleAuthService: googleAuthService,
 		notificationSvc:   notificationSvc,
+		userEvents:        userEvents,
 	}
 }
 
@@ -108,6 +122,24 @@ func TestNewUserService_UsesLoginThrottleDefaultsWhenConfigIsMissing(t *testing.
 	}
 }
 
+func TestUserService_ExtractUserRoles_GrantsConfiguredAdmin(t *testing.T) {
+	adminID := uuid.New()
+	cfg := newTestConfig(0)
+	cfg.Auth.AdminUserIDs = []string{adminID.String(), "not-a-uuid"}
+
+	service, ok := NewUserService(UserServiceParams{
+		Config: cfg,
+		Logger: newDiscardLogger(),
+	}).(*userService)
+	require.True(t, ok)
+
+	adminRoles := service.extractUserRoles(&entity.User{ID: adminID, UserProfile: &entity.UserProfile{}})
+	assert.Equal(t, entity.Roles{entity.RoleUser, entity.RoleAdmin}, adminRoles)
+
+	userRoles := service.extractUserRoles(&entity.User{ID: uuid.New(), UserProfile: &entity.UserProfile{}})
+	assert.Equal(t, entity.Roles{entity.RoleUser}, userRoles)
+}
+
 // onExecute is a helper method to reduce boilerplate for mocking txManager.Execute.
 func (fx *userServiceFixtures) onExecute(ctx context.Context, returnErr error, setupMocks func(factory *mockRepo.MockRepositoryFactory)) {
 	fx.txManager.EXPECT().
@@ -186,55 +218,361 @@ func TestUserService_RegisterUser_Success(t *testing.T) {
 	assert.NotNil(t, output)
 	assert.Equal(t, usecase.AuthStatusAuthenticated, output.Status)
 	assert.Equal(t, input.Email, output.User.Email)
+
+	require.Len(t, fx.userEvents.events, 1)
+	registered, ok := fx.userEvents.events[0].(service.UserRegistered)
+	require.True(t, ok, "registration should publish UserRegistered, got %T", fx.userEvents.events[0])
+	assert.Equal(t, output.User.ID, registered.UserID)
+	assert.Equal(t, entity.RoleUser, registered.Role)
+	assert.Equal(t, entity.ProviderTypeEmail, registered.Provider)
+	assert.False(t, registered.OccurredAt.IsZero())
+}
+
+// newRegistrationRetryAccount returns an account that an email registration of email created a minute ago
+func newRegistrationRetryAccount(email string) (*entity.User, *entity.Authentication) {
+	userID := uuid.New()
+	user := &entity.User{
+		ID:          userID,
+		Email:       email,
+		UserProfile: &entity.UserProfile{UserID: userID},
+		CreatedAt:   time.Now().Add(-time.Minute),
+	}
+	authRecord := &entity.Authentication{
+		UserID:         userID,
+		Provider:       entity.ProviderTypeEmail,
+		ProviderUserID: email,
+		PasswordHash:   "stored_hash",
+	}
+
+	return user, authRecord
+}
+
+// expectExistingAccountResolution sets up the auth transaction that finds user as the owner of its email login
+func (fx *userServiceFixtures) expectExistingAccountResolution(ctx context.Context, user *entity.User, authRecord *entity.Authentication) {
+	fx.txManager.EXPECT().
+		Execute(ctx, mock.AnythingOfType("func(repository.RepositoryFactory) error")).
+		RunAndReturn(func(ctx context.Context, fn func(repository.RepositoryFactory) error) error {
+			mockFactory := mockRepo.NewMockRepositoryFactory(fx.t)
+			mockUserRepo := mockRepo.NewMockUserRepository(fx.t)
+			mockAuthRepo := mockRepo.NewMockAuthRepository(fx.t)
+
+			mockFactory.EXPECT().UserRepo().Return(mockUserRepo)
+			mockFactory.EXPECT().AuthRepo().Return(mockAuthRepo)
+			mockAuthRepo.EXPECT().
+				FindAuthentication(ctx, entity.ProviderTypeEmail, user.Email).
+				Return(authRecord, nil)
+			mockUserRepo.EXPECT().
+				FindByID(ctx, user.ID).
+				Return(user, nil)
+			mockAuthRepo.EXPECT().
+				ListAuthenticationsByUserID(ctx, user.ID).
+				Return([]*entity.Authentication{authRecord}, nil).
+				Maybe()
+
+			return fn(mockFactory)
+		}).
+		Once()
+}
+
+// expectLoginThrottleCheck sets up the throttle lookup that precedes a password check of authRecord's email
+func (fx *userServiceFixtures) expectLoginThrottleCheck(ctx context.Context, authRecord *entity.Authentication) {
+	attemptKey := entity.NormalizeEmail(authRecord.ProviderUserID)
+
+	fx.loginAttemptRepo.EXPECT().
+		DecayLockoutCounts(ctx, 7).
+		Return(nil).
+		Once()
+	fx.authRepo.EXPECT().
+		FindAuthentication(ctx, entity.ProviderTypeEmail, attemptKey).
+		Return(authRecord, nil).
+		Once()
+	fx.loginAttemptRepo.EXPECT().
+		FindOrCreateByAttemptKey(ctx, attemptKey, &authRecord.UserID).
+		Return(&entity.LoginAttempt{AttemptKey: attemptKey, UserID: &authRecord.UserID}, nil).
+		Once()
 }
 
-func TestUserService_RegisterUser_InvalidCredentials(t *testing.T) {
+func TestUserService_RegisterUser_DifferentPasswordReturnsConflict(t *testing.T) {
 	fx := createTestUserService(t)
 
 	ctx := context.Background()
-	userID := uuid.New()
 	input := &usecase.RegisterUserInput{
 		Name:     "Test User",
 		Email:    "test@example.com",
 		Password: "wrong",
 	}
+	user, authRecord := newRegistrationRetryAccount(input.Email)
+	attemptKey := entity.NormalizeEmail(input.Email)
 
 	fx.hasher.EXPECT().ValidatePasswordStrength(input.Password).Return(nil).Once()
 	fx.hasher.EXPECT().Hash(input.Password).Return("hashed_password", nil).Once()
+	fx.expectExistingAccountResolution(ctx, user, authRecord)
+	fx.expectLoginThrottleCheck(ctx, authRecord)
+	fx.hasher.EXPECT().Check(input.Password, authRecord.PasswordHash).Return(false).Once()
 
-	authRecord := &entity.Authentication{
-		UserID:       userID,
-		PasswordHash: "hashed",
-		Provider:     entity.ProviderTypeEmail,
+	// The wrong password counts as a failed login
+	fx.txManager.EXPECT().
+		Execute(ctx, mock.AnythingOfType("func(repository.RepositoryFactory) error")).
+		RunAndReturn(func(ctx context.Context, fn func(repository.RepositoryFactory) error) error {
+			mockFactory := mockRepo.NewMockRepositoryFactory(t)
+			mockFactory.EXPECT().LoginAttemptRepo().Return(fx.loginAttemptRepo)
+			fx.loginAttemptRepo.EXPECT().
+				FindOrCreateByAttemptKeyForUpdate(ctx, attemptKey, &user.ID).
+				Return(&entity.LoginAttempt{AttemptKey: attemptKey, UserID: &user.ID}, nil)
+			fx.loginAttemptRepo.EXPECT().
+				Save(ctx, mock.MatchedBy(func(attempt *entity.LoginAttempt) bool {
+					return attempt.FailedCount == 1 && attempt.LockedUntil == nil
+				})).
+				Return(nil)
+
+			return fn(mockFactory)
+		}).
+		Once()
+
+	output, err := fx.service.RegisterUser(ctx, input)
+
+	assert.Error(t, err)
+	assert.Nil(t, output)
+	assert.True(t, errors.Is(err, domainerrors.ErrUserAlreadyExists))
+	assert.False(t, errors.Is(err, domainerrors.ErrInvalidCredentials))
+	assert.Empty(t, fx.userEvents.events)
+	fx.tokenService.AssertNotCalled(t, "GenerateTokens", mock.Anything, mock.Anything)
+}
+
+func TestUserService_RegisterUser_EstablishedAccountReturnsConflictWithoutPasswordCheck(t *testing.T) {
+	fx := createTestUserService(t)
+
+	ctx := context.Background()
+	input := &usecase.RegisterUserInput{
+		Name:     "Test User",
+		Email:    "test@example.com",
+		Password: "Password123!",
 	}
+	user, authRecord := newRegistrationRetryAccount(input.Email)
+	user.CreatedAt = time.Now().Add(-2 * registrationRetryWindow)
 
-	fx.onExecute(ctx, nil, func(factory *mockRepo.MockRepositoryFactory) {
-		mockUserRepo := mockRepo.NewMockUserRepository(t)
-		mockAuthRepo := mockRepo.NewMockAuthRepository(t)
+	fx.hasher.EXPECT().ValidatePasswordStrength(input.Password).Return(nil).Once()
+	fx.hasher.EXPECT().Hash(input.Password).Return("hashed_password", nil).Once()
+	fx.expectExistingAccountResolution(ctx, user, authRecord)
 
-		factory.EXPECT().UserRepo().Return(mockUserRepo)
-		factory.EXPECT().AuthRepo().Return(mockAuthRepo)
+	output, err := fx.service.RegisterUser(ctx, input)
 
-		mockAuthRepo.EXPECT().
-			FindAuthentication(ctx, entity.ProviderTypeEmail, input.Email).
-			Return(authRecord, nil)
-		mockUserRepo.EXPECT().
-			FindByID(ctx, userID).
-			Return(&entity.User{
-				ID:          userID,
-				Email:       input.Email,
-				UserProfile: &entity.UserProfile{UserID: userID},
-			}, nil)
-	})
+	require.ErrorIs(t, err, domainerrors.ErrUserAlreadyExists)
+	assert.Nil(t, output)
+	assert.Empty(t, fx.userEvents.events)
+	// Registration answers the same whatever the password, so it cannot be used to test one
+	fx.hasher.AssertNotCalled(t, "Check", mock.Anything, mock.Anything)
+	fx.loginAttemptRepo.AssertNotCalled(t, "FindOrCreateByAttemptKey", mock.Anything, mock.Anything, mock.Anything)
+}
 
-	// Password check happens outside the transaction.
-	fx.hasher.EXPECT().Check(input.Password, authRecord.PasswordHash).Return(false).Once()
+func TestUserService_RegisterUser_AccountWithOtherLoginReturnsConflict(t *testing.T) {
+	fx := createTestUserService(t)
+
+	ctx := context.Background()
+	input := &usecase.RegisterUserInput{
+		Name:     "Test User",
+		Email:    "test@example.com",
+		Password: "Password123!",
+	}
+	user, authRecord := newRegistrationRetryAccount(input.Email)
+
+	fx.hasher.EXPECT().ValidatePasswordStrength(input.Password).Return(nil).Once()
+	fx.hasher.EXPECT().Hash(input.Password).Return("hashed_password", nil).Once()
+	fx.txManager.EXPECT().
+		Execute(ctx, mock.AnythingOfType("func(repository.RepositoryFactory) error")).
+		RunAndReturn(func(ctx context.Context, fn func(repository.RepositoryFactory) error) error {
+			mockFactory := mockRepo.NewMockRepositoryFactory(t)
+			mockUserRepo := mockRepo.NewMockUserRepository(t)
+			mockAuthRepo := mockRepo.NewMockAuthRepository(t)
+
+			mockFactory.EXPECT().UserRepo().Return(mockUserRepo)
+			mockFactory.EXPECT().AuthRepo().Return(mockAuthRepo)
+			mockAuthRepo.EXPECT().
+				FindAuthentication(ctx, entity.ProviderTypeEmail, input.Email).
+				Return(authRecord, nil)
+			mockUserRepo.EXPECT().
+				FindByID(ctx, user.ID).
+				Return(user, nil)
+			// A linked Google login means the account is in use, not a fresh registration
+			mockAuthRepo.EXPECT().
+				ListAuthenticationsByUserID(ctx, user.ID).
+				Return([]*entity.Authentication{authRecord, {UserID: user.ID, Provider: entity.ProviderTypeGoogle}}, nil)
+
+			return fn(mockFactory)
+		}).
+		Once()
 
 	output, err := fx.service.RegisterUser(ctx, input)
 
-	assert.Error(t, err)
+	require.ErrorIs(t, err, domainerrors.ErrUserAlreadyExists)
 	assert.Nil(t, output)
-	assert.True(t, errors.Is(err, domainerrors.ErrInvalidCredentials))
+	fx.hasher.AssertNotCalled(t, "Check", mock.Anything, mock.Anything)
+}
+
+func TestUserService_RegisterUser_RepeatedWrongPasswordsLockOut(t *testing.T) {
+	fx := createTestUserService(t)
+
+	ctx := context.Background()
+	input := &usecase.RegisterUserInput{
+		Name:     "Test User",
+		Email:    "test@example.com",
+		Password: "Guess123!",
+	}
+	user, authRecord := newRegistrationRetryAccount(input.Email)
+	attemptKey := entity.NormalizeEmail(input.Email)
+	attempt := &entity.LoginAttempt{AttemptKey: attemptKey, UserID: &user.ID}
+
+	// Every registration finds the fresh account and is throttled on the same attempt record
+	fx.hasher.EXPECT().ValidatePasswordStrength(input.Password).Return(nil)
+	fx.hasher.EXPECT().Hash(input.Password).Return("hashed_password", nil)
+	fx.hasher.EXPECT().Check(input.Password, authRecord.PasswordHash).Return(false)
+	fx.loginAttemptRepo.EXPECT().DecayLockoutCounts(ctx, 7).Return(nil)
+	fx.authRepo.EXPECT().FindAuthentication(ctx, entity.ProviderTypeEmail, attemptKey).Return(authRecord, nil)
+	fx.loginAttemptRepo.EXPECT().FindOrCreateByAttemptKey(ctx, attemptKey, &user.ID).Return(attempt, nil)
+	fx.loginAttemptRepo.EXPECT().FindOrCreateByAttemptKeyForUpdate(ctx, attemptKey, &user.ID).Return(attempt, nil)
+	fx.loginAttemptRepo.EXPECT().Save(ctx, attempt).Return(nil)
+	fx.txManager.EXPECT().
+		Execute(ctx, mock.AnythingOfType("func(repository.RepositoryFactory) error")).
+		RunAndReturn(func(ctx context.Context, fn func(repository.RepositoryFactory) error) error {
+			mockFactory := mockRepo.NewMockRepositoryFactory(t)
+			mockUserRepo := mockRepo.NewMockUserRepository(t)
+			mockAuthRepo := mockRepo.NewMockAuthRepository(t)
+
+			mockFactory.EXPECT().UserRepo().Return(mockUserRepo).Maybe()
+			mockFactory.EXPECT().AuthRepo().Return(mockAuthRepo).Maybe()
+			mockFactory.EXPECT().LoginAttemptRepo().Return(fx.loginAttemptRepo).Maybe()
+			mockAuthRepo.EXPECT().FindAuthentication(ctx, entity.ProviderTypeEmail, input.Email).Return(authRecord, nil).Maybe()
+			mockAuthRepo.EXPECT().ListAuthenticationsByUserID(ctx, user.ID).Return([]*entity.Authentication{authRecord}, nil).Maybe()
+			mockUserRepo.EXPECT().FindByID(ctx, user.ID).Return(user, nil).Maybe()
+
+			return fn(mockFactory)
+		})
+	notified := make(chan struct{})
+	fx.deviceRepo.EXPECT().
+		FindDevicesByUser(mock.Anything, user.ID, mock.Anything).
+		RunAndReturn(func(context.Context, uuid.UUID, repository.DeviceListFilter) ([]*entity.UserDevice, error) {
+			close(notified)
+
+			return nil, nil
+		}).
+		Once()
+
+	for range 4 {
+		_, err := fx.service.RegisterUser(ctx, input)
+		require.ErrorIs(t, err, domainerrors.ErrUserAlreadyExists)
+	}
+
+	// The fifth wrong password locks the account, as it would through Login
+	_, err := fx.service.RegisterUser(ctx, input)
+	var lockoutErr *usecase.LockoutError
+	require.ErrorAs(t, err, &lockoutErr)
+	assert.Positive(t, lockoutErr.RetryAfterSeconds)
+	<-notified
+
+	// A locked account rejects further registrations before checking the password
+	_, err = fx.service.RegisterUser(ctx, input)
+	require.ErrorAs(t, err, &lockoutErr)
+	fx.hasher.AssertNumberOfCalls(t, "Check", 5)
+	assert.Empty(t, fx.userEvents.events)
+}
+
+// expectIssuedTokens sets up the token and refresh token calls of a successful sign-in
+func (fx *userServiceFixtures) expectIssuedTokens(ctx context.Context, userID uuid.UUID) {
+	fx.tokenService.EXPECT().
+		GenerateTokens(userID, []string{"user"}).
+		Return("access-token", "refresh-token", nil).
+		Once()
+	fx.tokenService.EXPECT().
+		HashToken("refresh-token").
+		Return("refresh-token-hash").
+		Once()
+	fx.tokenService.EXPECT().
+		GetRefreshTokenDuration().
+		Return(time.Hour).
+		Once()
+	fx.refreshTokenRepo.EXPECT().
+		CreateRefreshToken(ctx, mock.AnythingOfType("*entity.RefreshToken")).
+		Return(nil).
+		Once()
+}
+
+func TestUserService_RegisterUser_RetrySameCredentialsSignsIn(t *testing.T) {
+	fx := createTestUserService(t)
+
+	ctx := context.Background()
+	input := &usecase.RegisterUserInput{
+		Name:     "Test User",
+		Email:    "test@example.com",
+		Password: "Password123!",
+	}
+	// The first attempt already committed the account before the client gave up on it
+	user, authRecord := newRegistrationRetryAccount(input.Email)
+
+	fx.hasher.EXPECT().ValidatePasswordStrength(input.Password).Return(nil).Once()
+	fx.hasher.EXPECT().Hash(input.Password).Return("hashed_password", nil).Once()
+	fx.expectExistingAccountResolution(ctx, user, authRecord)
+	fx.expectLoginThrottleCheck(ctx, authRecord)
+	fx.hasher.EXPECT().Check(input.Password, authRecord.PasswordHash).Return(true).Once()
+	fx.loginAttemptRepo.EXPECT().ResetOnSuccess(ctx, entity.NormalizeEmail(input.Email)).Return(nil).Once()
+	fx.expectIssuedTokens(ctx, user.ID)
+
+	output, err := fx.service.RegisterUser(ctx, input)
+
+	require.NoError(t, err)
+	assert.Equal(t, usecase.AuthStatusAuthenticated, output.Status)
+	assert.Equal(t, "access-token", output.AccessToken)
+	assert.Equal(t, user.ID, output.User.ID)
+}
+
+func TestUserService_RegisterUser_RetryRacingFirstAttemptSignsIn(t *testing.T) {
+	fx := createTestUserService(t)
+
+	ctx := context.Background()
+	input := &usecase.RegisterUserInput{
+		Name:     "Test User",
+		Email:    "test@example.com",
+		Password: "Password123!",
+	}
+	user, authRecord := newRegistrationRetryAccount(input.Email)
+
+	fx.hasher.EXPECT().ValidatePasswordStrength(input.Password).Return(nil).Once()
+	fx.hasher.EXPECT().Hash(input.Password).Return("hashed_password", nil).Once()
+
+	// The first attempt commits between the retry's lookup and insert, so the insert hits the unique email
+	fx.txManager.EXPECT().
+		Execute(ctx, mock.AnythingOfType("func(repository.RepositoryFactory) error")).
+		RunAndReturn(func(ctx context.Context, fn func(repository.RepositoryFactory) error) error {
+			mockFactory := mockRepo.NewMockRepositoryFactory(t)
+			mockUserRepo := mockRepo.NewMockUserRepository(t)
+			mockAuthRepo := mockRepo.NewMockAuthRepository(t)
+
+			mockFactory.EXPECT().UserRepo().Return(mockUserRepo)
+			mockFactory.EXPECT().AuthRepo().Return(mockAuthRepo)
+			mockFactory.EXPECT().LoginAttemptRepo().Return(fx.loginAttemptRepo)
+			mockAuthRepo.EXPECT().
+				FindAuthentication(ctx, entity.ProviderTypeEmail, input.Email).
+				Return(nil, domainerrors.ErrAuthNotFound)
+			mockUserRepo.EXPECT().
+				FindByEmail(ctx, input.Email).
+				Return(nil, domainerrors.ErrUserNotFound)
+			mockUserRepo.EXPECT().
+				Create(ctx, mock.AnythingOfType("*entity.User")).
+				Return(domainerrors.ErrUserAlreadyExists)
+
+			return fn(mockFactory)
+		}).
+		Once()
+	fx.expectExistingAccountResolution(ctx, user, authRecord)
+	fx.expectLoginThrottleCheck(ctx, authRecord)
+	fx.hasher.EXPECT().Check(input.Password, authRecord.PasswordHash).Return(true).Once()
+	fx.loginAttemptRepo.EXPECT().ResetOnSuccess(ctx, entity.NormalizeEmail(input.Email)).Return(nil).Once()
+	fx.expectIssuedTokens(ctx, user.ID)
+
+	output, err := fx.service.RegisterUser(ctx, input)
+
+	require.NoError(t, err)
+	assert.Equal(t, usecase.AuthStatusAuthenticated, output.Status)
+	assert.Equal(t, user.ID, output.User.ID)
 }
 
 func TestUserService_Login_InvalidCredentials_DoesNotLoadUser(t *testing.T) {
@@ -325,6 +663,7 @@ func TestUserService_Login_InvalidCredentials_DoesNotLoadUser(t *testing.T) {
 	require.Error(t, err)
 	assert.Nil(t, output)
 	assert.True(t, errors.Is(err, domainerrors.ErrInvalidCredentials))
+	assert.Empty(t, fx.userEvents.events)
 	fx.txManager.AssertNumberOfCalls(t, "Execute", 2)
 	fx.tokenService.AssertNotCalled(t, "GenerateTokens", mock.Anything, mock.Anything)
 	fx.refreshTokenRepo.AssertNotCalled(t, "CreateRefreshToken", mock.Anything, mock.Anything)
@@ -399,6 +738,7 @@ func TestUserService_Login_LockoutTriggerReturnsLockoutError(t *testing.T) {
 	require.Error(t, err)
 	assert.Nil(t, output)
 	assert.True(t, errors.Is(err, domainerrors.ErrInvalidCredentials))
+	assert.Empty(t, fx.userEvents.events)
 
 	var lockoutErr *usecase.LockoutError
 	require.ErrorAs(t, err, &lockoutErr)
@@ -503,6 +843,36 @@ func TestUserService_Login_Success_LoadsUserAfterPasswordCheck(t *testing.T) {
 	assert.Equal(t, "refresh-token", output.RefreshToken)
 	assert.Equal(t, userID, output.User.ID)
 	fx.txManager.AssertNumberOfCalls(t, "Execute", 1)
+
+	require.Len(t, fx.userEvents.events, 1)
+	loggedIn, ok := fx.userEvents.events[0].(service.UserLoggedIn)
+	require.True(t, ok, "login should publish UserLoggedIn, got %T", fx.userEvents.events[0])
+	assert.Equal(t, userID, loggedIn.UserID)
+	assert.Equal(t, entity.ProviderTypeEmail, loggedIn.Provider)
+}
+
+func TestUserService_PersistLoginRefreshToken_UsesInjectedIDGenerator(t *testing.T) {
+	fx := createTestUserService(t)
+	familyID := uuid.New()
+	svc, ok := fx.service.(*userService)
+	require.True(t, ok)
+	svc.idGenerator = newSequenceIDGenerator(t, familyID)
+
+	ctx := context.Background()
+	userID := uuid.New()
+
+	fx.tokenService.EXPECT().HashToken("refresh-token").Return("refresh-token-hash").Once()
+	fx.tokenService.EXPECT().GetRefreshTokenDuration().Return(time.Hour).Once()
+	fx.refreshTokenRepo.EXPECT().
+		CreateRefreshToken(ctx, mock.MatchedBy(func(token *entity.RefreshToken) bool {
+			return token.UserID == userID && token.FamilyID == familyID
+		})).
+		Return(nil).
+		Once()
+
+	err := svc.persistLoginRefreshToken(ctx, userID, "refresh-token")
+
+	require.NoError(t, err)
 }
 
 func TestUserService_Login_LockedOutReturnsLockoutErrorWithoutAuthTransaction(t *testing.T) {
@@ -544,6 +914,7 @@ func TestUserService_Login_LockedOutReturnsLockoutErrorWithoutAuthTransaction(t
 	require.Error(t, err)
 	assert.Nil(t, output)
 	assert.True(t, errors.Is(err, domainerrors.ErrInvalidCredentials))
+	assert.Empty(t, fx.userEvents.events)
 
 	var lockoutErr *usecase.LockoutError
 	require.ErrorAs(t, err, &lockoutErr)
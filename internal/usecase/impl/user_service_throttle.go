@@ -44,12 +44,13 @@ func (srv *userService) checkLoginThrottle(ctx context.Context, email string) (*
 		attempt.UserID = userID
 	}
 
-	if attempt.LockedUntil == nil || !attempt.LockedUntil.After(time.Now()) {
+	now := srv.clock.Now()
+	if attempt.LockedUntil == nil || !attempt.LockedUntil.After(now) {
 		return attempt, nil
 	}
 
 	return attempt, &usecase.LockoutError{
-		RetryAfterSeconds: retryAfterSeconds(*attempt.LockedUntil),
+		RetryAfterSeconds: retryAfterSeconds(*attempt.LockedUntil, now),
 		Err:               domainerrors.ErrInvalidCredentials,
 	}
 }
@@ -68,7 +69,7 @@ func (srv *userService) recordLoginFailure(ctx context.Context, email string, us
 			return err
 		}
 
-		now := time.Now()
+		now := srv.clock.Now()
 		if isLoginAttemptLocked(attempt, now) {
 			lockedBefore = true
 
@@ -83,7 +84,8 @@ func (srv *userService) recordLoginFailure(ctx context.Context, email string, us
 		return err
 	}
 
-	if attempt.LockedUntil == nil || !attempt.LockedUntil.After(time.Now()) || attempt.FailedCount != 0 {
+	now := srv.clock.Now()
+	if attempt.LockedUntil == nil || !attempt.LockedUntil.After(now) || attempt.FailedCount != 0 {
 		return nil
 	}
 
@@ -92,7 +94,7 @@ func (srv *userService) recordLoginFailure(ctx context.Context, email string, us
 	}
 
 	return &usecase.LockoutError{
-		RetryAfterSeconds: retryAfterSeconds(*attempt.LockedUntil),
+		RetryAfterSeconds: retryAfterSeconds(*attempt.LockedUntil, now),
 		Err:               domainerrors.ErrInvalidCredentials,
 	}
 }
@@ -165,7 +167,7 @@ func (srv *userService) sendLockoutNotification(ctx context.Context, userID uuid
 			"locked_until": lockedUntil.UTC().Format(time.RFC3339),
 		}
 
-		if _, _, _, err := srv.notificationSvc.SendBatchNotification(
+		if _, err := srv.notificationSvc.SendBatchNotification(
 			notifyCtx,
 			tokens,
 			lockoutNotificationTitle,
@@ -208,7 +210,7 @@ func (srv *userService) sendTokenReuseNotification(ctx context.Context, userID u
 			"event": "refresh_token_reuse_detected",
 		}
 
-		if _, _, _, err := srv.notificationSvc.SendBatchNotification(
+		if _, err := srv.notificationSvc.SendBatchNotification(
 			notifyCtx,
 			tokens,
 			securityAlertTitle,
@@ -232,8 +234,8 @@ func (srv *userService) resolveLoginAttemptUserID(ctx context.Context, authRepo
 	return nil, err
 }
 
-func retryAfterSeconds(lockedUntil time.Time) int {
-	seconds := int(math.Ceil(time.Until(lockedUntil).Seconds()))
+func retryAfterSeconds(lockedUntil, now time.Time) int {
+	seconds := int(math.Ceil(lockedUntil.Sub(now).Seconds()))
 	if seconds < 1 {
 		return 1
 	}
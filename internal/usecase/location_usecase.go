@@ -6,6 +6,7 @@ import (
 	"radar/internal/domain/entity"
 
 	"github.com/google/uuid"
+	"github.com/paulmach/orb/geojson"
 )
 
 // AddLocationInput represents the input for adding a new location
@@ -16,6 +17,9 @@ type AddLocationInput struct {
 	Longitude   float64 `json:"longitude"`
 	IsPrimary   bool    `json:"is_primary"`
 	IsActive    bool    `json:"is_active"`
+
+	// Neighborhood the subscriber wants broadcasts from instead of the radius; user locations only
+	GeofencePolygon geojson.Polygon `json:"geofence_polygon,omitempty"`
 }
 
 // UpdateLocationInput represents the input for updating an existing location
@@ -26,6 +30,10 @@ type UpdateLocationInput struct {
 	Longitude   *float64 `json:"longitude,omitempty"`
 	IsPrimary   *bool    `json:"is_primary,omitempty"`
 	IsActive    *bool    `json:"is_active,omitempty"`
+
+	// GeofencePolygon replaces the location's geofence and ClearGeofence removes it; user locations only
+	GeofencePolygon geojson.Polygon `json:"geofence_polygon,omitempty"`
+	ClearGeofence   bool            `json:"clear_geofence,omitempty"`
 }
 
 // LocationUsecase defines the interface for location management use cases
@@ -41,4 +49,8 @@ type LocationUsecase interface {
 	AddMerchantLocation(ctx context.Context, merchantID uuid.UUID, input *AddLocationInput) (*entity.Address, error)
 	UpdateMerchantLocation(ctx context.Context, merchantID, locationID uuid.UUID, input *UpdateLocationInput) (*entity.Address, error)
 	DeleteMerchantLocation(ctx context.Context, merchantID, locationID uuid.UUID) error
+	RestoreMerchantLocation(ctx context.Context, merchantID, locationID uuid.UUID) (*entity.Address, error)
+
+	// Route preview between two points on the named routing profile, including the route geometry
+	PreviewRoute(ctx context.Context, profile string, source, target Coordinate) (*RouteResult, error)
 }
@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"context"
+
+	"radar/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// MerchantFlags is the typed, defaults-applied view of a merchant's settings.
+// Services read merchant-specific behavior through it instead of the raw overrides.
+type MerchantFlags struct {
+	MerchantID                  uuid.UUID    `json:"merchant_id"`
+	MaxNotificationRadius       float64      `json:"max_notification_radius"`       // Upper bound (in meters) for subscriber notification radii
+	BroadcastsPerHour           int          `json:"broadcasts_per_hour"`           // Maximum location broadcasts per hour; 0 means unlimited
+	DistanceUnit                DistanceUnit `json:"distance_unit"`                 // Unit used when presenting distances
+	RotateNotificationTemplates bool         `json:"rotate_notification_templates"` // Whether broadcasts rotate through notification templates
+}
+
+// MerchantSettingsUsecase defines the interface for per-merchant settings use cases
+type MerchantSettingsUsecase interface {
+	// GetMerchantFlags returns the merchant's effective settings, using defaults for values the merchant has not set.
+	GetMerchantFlags(ctx context.Context, merchantID uuid.UUID) (*MerchantFlags, error)
+
+	// UpdateMerchantSettings saves the merchant's overrides and returns the resulting effective settings.
+	UpdateMerchantSettings(ctx context.Context, settings *entity.MerchantSettings) (*MerchantFlags, error)
+}
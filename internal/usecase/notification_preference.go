@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"radar/internal/domain/entity"
+	"radar/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+// ApplyNotificationPreferences keeps the addresses whose subscribers' preferences allow a broadcast from the
+// merchant at now, dropping subscribers in their quiet hours or opted out of the merchant's category.
+// It also returns how many distinct subscribers were suppressed. Both the inline publish path and the worker
+// use it so their subscriber selection cannot diverge.
+func ApplyNotificationPreferences(
+	ctx context.Context,
+	preferenceRepo repository.NotificationPreferenceRepository,
+	merchantID uuid.UUID,
+	addresses []*entity.SubscriberAddress,
+	now time.Time,
+) ([]*entity.SubscriberAddress, int, error) {
+	if len(addresses) == 0 {
+		return addresses, 0, nil
+	}
+
+	userIDs := SubscriberIDs(addresses)
+	preferences, err := preferenceRepo.FindNotificationPreferences(ctx, userIDs)
+	if err != nil {
+		return nil, 0, fmt.Errorf("find notification preferences: %w", err)
+	}
+	if len(preferences) == 0 {
+		return addresses, 0, nil
+	}
+
+	categoryID, err := preferenceRepo.FindMerchantCategoryID(ctx, merchantID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("find merchant category: %w", err)
+	}
+
+	allowed := entity.WithoutPreferenceSuppressed(addresses, preferences, now, categoryID)
+
+	return allowed, len(userIDs) - len(SubscriberIDs(allowed)), nil
+}
+
+// ApplyDevicePreferences keeps the devices whose own notification settings allow a broadcast at now, and returns
+// how many devices were suppressed. The owners' preferences are only loaded for their time zones when a device
+// sets quiet hours of its own; without a preference repository device quiet hours are evaluated in UTC.
+func ApplyDevicePreferences(
+	ctx context.Context,
+	preferenceRepo repository.NotificationPreferenceRepository,
+	devices []*entity.UserDevice,
+	now time.Time,
+) ([]*entity.UserDevice, int, error) {
+	quietOwners := make(map[uuid.UUID]struct{})
+	for _, device := range devices {
+		if device.HasQuietHours() {
+			quietOwners[device.UserID] = struct{}{}
+		}
+	}
+
+	var preferences []*entity.NotificationPreference
+	if len(quietOwners) > 0 && preferenceRepo != nil {
+		var err error
+		preferences, err = preferenceRepo.FindNotificationPreferences(ctx, slices.Collect(maps.Keys(quietOwners)))
+		if err != nil {
+			return nil, 0, fmt.Errorf("find notification preferences: %w", err)
+		}
+	}
+
+	allowed := entity.WithoutDeviceSuppressed(devices, preferences, now)
+
+	return allowed, len(devices) - len(allowed), nil
+}
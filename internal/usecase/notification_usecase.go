@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"radar/internal/domain/entity"
 
@@ -16,12 +17,83 @@ type LocationData struct {
 	Longitude    float64 `json:"longitude"`
 }
 
+// PublishLocationInput describes one location of a multi-location or scheduled publish.
+// Like a single publish, exactly one of AddressID or LocationData must be provided.
+type PublishLocationInput struct {
+	AddressID    *uuid.UUID
+	LocationData *LocationData
+	HintMessage  string
+	ScheduledAt  *time.Time // When set, the notification is stored and sent by the scheduled dispatch job at this time
+}
+
+// PublishLocationResult reports the outcome of one location of a multi-location publish.
+// Err is set when that location failed; other locations are unaffected.
+type PublishLocationResult struct {
+	Notification *entity.MerchantLocationNotification
+	Err          error
+}
+
+// SubscriberSnapshot describes how a subscriber address relates to a merchant location on the road network.
+// Raw address coordinates are not exposed; only the snapped road node is returned.
+type SubscriberSnapshot struct {
+	AddressID          uuid.UUID         `json:"address_id"`
+	SnapNode           *NodeInfo         `json:"snap_node,omitempty"` // Nil when the address is beyond the maximum snap distance
+	DistanceKm         float64           `json:"distance_km"`         // Road network distance from the merchant location
+	DurationMin        float64           `json:"duration_min"`        // Estimated travel time from the merchant location
+	IsReachable        bool              `json:"is_reachable"`
+	UnreachableReason  UnreachableReason `json:"unreachable_reason,omitempty"` // Set when the routing backend reports why
+	NotificationRadius float64           `json:"notification_radius"`          // Subscriber's radius in meters
+	WithinRadius       bool              `json:"within_radius"`                // Whether the fan-out would notify this address
+}
+
+// MerchantBroadcastStats summarizes a merchant's broadcast reach over a range of UTC days
+type MerchantBroadcastStats struct {
+	From              time.Time                   `json:"from"` // First day of the range, midnight UTC
+	To                time.Time                   `json:"to"`   // Last day of the range, inclusive
+	Days              []*entity.BroadcastDayStats `json:"days"` // One bucket per day, oldest first; days without broadcasts are zero
+	TotalBroadcasts   int                         `json:"total_broadcasts"`
+	TotalRecipients   int                         `json:"total_recipients"`
+	AverageRecipients float64                     `json:"average_recipients"` // Recipients per broadcast; 0 when nothing was sent
+}
+
 // NotificationUsecase defines the interface for notification management use cases
 type NotificationUsecase interface {
 	// PublishLocationNotification publishes a location notification to nearby subscribers
 	// Either addressID or locationData must be provided
 	PublishLocationNotification(ctx context.Context, merchantID uuid.UUID, addressID *uuid.UUID, locationData *LocationData, hintMessage string) (*entity.MerchantLocationNotification, error)
 
+	// ScheduleLocationNotification stores a location notification to be sent at input.ScheduledAt, which is required
+	ScheduleLocationNotification(ctx context.Context, merchantID uuid.UUID, input PublishLocationInput) (*entity.MerchantLocationNotification, error)
+
+	// CancelScheduledNotification cancels a pending scheduled notification owned by the merchant.
+	// Notifications that were sent immediately, already dispatched, or already canceled cannot be canceled.
+	CancelScheduledNotification(ctx context.Context, merchantID, notificationID uuid.UUID) error
+
+	// PublishMultiLocation publishes one notification per location, in order, and returns a result per location.
+	// A subscriber reached by several locations is only notified by the first of them; scheduled locations
+	// are stored instead and take no part in that deduplication.
+	PublishMultiLocation(ctx context.Context, merchantID uuid.UUID, inputs []PublishLocationInput) ([]*PublishLocationResult, error)
+
 	// GetMerchantNotificationHistory retrieves notification history for a merchant with pagination
 	GetMerchantNotificationHistory(ctx context.Context, merchantID uuid.UUID, limit, offset int) ([]*entity.MerchantLocationNotification, error)
+
+	// GetMerchantBroadcastStats summarizes the merchant's sent notifications per UTC day from the day of from
+	// through the day of to, inclusive
+	GetMerchantBroadcastStats(ctx context.Context, merchantID uuid.UUID, from, to time.Time) (*MerchantBroadcastStats, error)
+
+	// GetReceivedNotifications retrieves the notifications delivered to the user's devices, newest first, with pagination.
+	// Each delivery carries its status, so failed deliveries are listed too.
+	GetReceivedNotifications(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*entity.ReceivedNotification, error)
+
+	// RecordNotificationOpened records that the user opened a notification sent to them.
+	// Repeated opens are accepted and counted once; a notification the user never received is not found.
+	RecordNotificationOpened(ctx context.Context, userID, notificationID uuid.UUID) error
+
+	// GetSubscriberSnapshots returns the fan-out's routing diagnostics for subscribers around one of the merchant's
+	// addresses without sending any notification
+	GetSubscriberSnapshots(ctx context.Context, merchantID, addressID uuid.UUID) ([]*SubscriberSnapshot, error)
+
+	// GetSubscriptionReachability returns the same diagnostics for the subscriber's own addresses as if the merchant
+	// broadcast from the given location. The subscriber must hold an active subscription to the merchant.
+	GetSubscriptionReachability(ctx context.Context, userID, merchantID uuid.UUID, from Coordinate) ([]*SubscriberSnapshot, error)
 }
@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"radar/config"
+	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
+	"radar/internal/domain/policy"
+
+	"github.com/google/uuid"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+)
+
+// RadiusPolicy decides how route results are compared against a subscriber's notification radius.
+type RadiusPolicy struct {
+	// StraightLineFactor scales the radius for straight-line estimates, which are used when routing is
+	// disabled or a target has no road route. Below 1 narrows the radius to allow for road detours,
+	// above 1 widens it; 0 compares estimates against the radius unchanged.
+	StraightLineFactor float64
+
+	// Profile names the road network reachability is routed on; DefaultRoutingProfile uses the backend's default
+	Profile string
+}
+
+// NewRadiusPolicy builds the notification radius policy from the routing config. The API and the worker share it,
+// so a subscriber's radius is compared the same way on the publish and delivery paths.
+func NewRadiusPolicy(cfg *config.Config) RadiusPolicy {
+	var routingCfg *config.RoutingConfig
+	if cfg != nil {
+		routingCfg = cfg.Routing
+	}
+	routing := routingCfg.WithDefaults()
+
+	return RadiusPolicy{
+		StraightLineFactor: routing.StraightLineRadiusFactor,
+		Profile:            routing.NotificationProfile,
+	}
+}
+
+// radiusMeters returns the radius a result is compared against
+func (p RadiusPolicy) radiusMeters(result RouteResult, radiusMeters float64) float64 {
+	if result.IsEstimate && p.StraightLineFactor > 0 {
+		return radiusMeters * p.StraightLineFactor
+	}
+
+	return radiusMeters
+}
+
+// IsWithinNotificationRadius reports whether a route reaches the subscriber within their chosen radius.
+func IsWithinNotificationRadius(result RouteResult, radiusMeters float64, policy RadiusPolicy) bool {
+	return result.IsReachable && result.DistanceKm*1000.0 <= policy.radiusMeters(result, radiusMeters)
+}
+
+// IsWithinSubscriberArea reports whether a broadcast from source reaches the subscriber address. An address with
+// a geofence polygon is reached only from inside it, whatever the route; otherwise the route must fit the radius.
+func IsWithinSubscriberArea(source Coordinate, addr *entity.SubscriberAddress, result RouteResult, policy RadiusPolicy) bool {
+	if addr.HasGeofence() {
+		return addr.InGeofence(source.Lat, source.Lng)
+	}
+
+	return IsWithinNotificationRadius(result, addr.NotificationRadius, policy)
+}
+
+// FilterReachableAddresses keeps the addresses whose road route from source fits their notification radius,
+// or whose geofence polygon contains source.
+// Both the inline publish path and the worker use it so their subscriber selection cannot diverge.
+func FilterReachableAddresses(
+	ctx context.Context,
+	routingSvc RoutingUsecase,
+	policy RadiusPolicy,
+	source Coordinate,
+	addresses []*entity.SubscriberAddress,
+) ([]*entity.SubscriberAddress, error) {
+	if len(addresses) == 0 {
+		return []*entity.SubscriberAddress{}, nil
+	}
+
+	targets := make([]Coordinate, len(addresses))
+	for idx, addr := range addresses {
+		targets[idx] = Coordinate{Lat: addr.Latitude, Lng: addr.Longitude}
+	}
+
+	routeResults, err := routingSvc.OneToMany(ctx, policy.Profile, source, targets)
+	if err != nil {
+		return nil, fmt.Errorf("filter reachable addresses: %w", err)
+	}
+
+	return AddressesWithinRadius(source, addresses, routeResults.Results, policy), nil
+}
+
+// AddressesWithinRadius keeps the addresses whose index-aligned route result fits their notification radius,
+// or whose geofence polygon contains source.
+func AddressesWithinRadius(
+	source Coordinate,
+	addresses []*entity.SubscriberAddress,
+	results []RouteResult,
+	policy RadiusPolicy,
+) []*entity.SubscriberAddress {
+	reachable := make([]*entity.SubscriberAddress, 0, len(addresses))
+	for idx, result := range results {
+		if idx < len(addresses) && IsWithinSubscriberArea(source, addresses[idx], result, policy) {
+			reachable = append(reachable, addresses[idx])
+		}
+	}
+
+	return reachable
+}
+
+// SubscriberIDs returns the distinct owners of the addresses in first-seen order.
+func SubscriberIDs(addresses []*entity.SubscriberAddress) []uuid.UUID {
+	seen := make(map[uuid.UUID]struct{}, len(addresses))
+	userIDs := make([]uuid.UUID, 0, len(addresses))
+	for _, addr := range addresses {
+		if _, ok := seen[addr.OwnerID]; ok {
+			continue
+		}
+		seen[addr.OwnerID] = struct{}{}
+		userIDs = append(userIDs, addr.OwnerID)
+	}
+
+	return userIDs
+}
+
+// CanaryCohort keeps the addresses whose subscribers are in the canary cohort.
+// It also returns how many distinct subscribers were suppressed because they are outside it.
+func CanaryCohort(addresses []*entity.SubscriberAddress, canary policy.CanaryPolicy) ([]*entity.SubscriberAddress, int) {
+	if !canary.Enabled {
+		return addresses, 0
+	}
+
+	cohort := make([]*entity.SubscriberAddress, 0, len(addresses))
+	suppressed := make(map[uuid.UUID]struct{})
+	for _, addr := range addresses {
+		if canary.Includes(addr.OwnerID) {
+			cohort = append(cohort, addr)
+		} else {
+			suppressed[addr.OwnerID] = struct{}{}
+		}
+	}
+
+	return cohort, len(suppressed)
+}
+
+// CapRecipients applies the recipient cap to the addresses a broadcast from source reaches. Over the cap a strict
+// policy returns ErrRecipientCapExceeded; otherwise only the addresses of the MaxRecipients subscribers nearest to
+// source, by straight-line distance to their closest address, are kept. It also returns how many distinct
+// subscribers were dropped.
+func CapRecipients(
+	source Coordinate,
+	addresses []*entity.SubscriberAddress,
+	recipientCap policy.RecipientCapPolicy,
+) ([]*entity.SubscriberAddress, int, error) {
+	nearest := make(map[uuid.UUID]float64)
+	origin := orb.Point{source.Lng, source.Lat}
+	for _, addr := range addresses {
+		distance := geo.Distance(origin, orb.Point{addr.Longitude, addr.Latitude})
+		if known, seen := nearest[addr.OwnerID]; !seen || distance < known {
+			nearest[addr.OwnerID] = distance
+		}
+	}
+	if !recipientCap.Exceeded(len(nearest)) {
+		return addresses, 0, nil
+	}
+	if recipientCap.Strict {
+		return nil, 0, domainerrors.ErrRecipientCapExceeded.WithDetails(
+			fmt.Sprintf("%d subscribers exceed the cap of %d", len(nearest), recipientCap.MaxRecipients),
+		)
+	}
+
+	// Ties are broken by user ID so the same subscribers are kept on every delivery attempt
+	userIDs := make([]uuid.UUID, 0, len(nearest))
+	for userID := range nearest {
+		userIDs = append(userIDs, userID)
+	}
+	slices.SortFunc(userIDs, func(a, b uuid.UUID) int {
+		return cmp.Or(cmp.Compare(nearest[a], nearest[b]), cmp.Compare(a.String(), b.String()))
+	})
+	kept := make(map[uuid.UUID]struct{}, recipientCap.MaxRecipients)
+	for _, userID := range userIDs[:recipientCap.MaxRecipients] {
+		kept[userID] = struct{}{}
+	}
+
+	capped := make([]*entity.SubscriberAddress, 0, len(addresses))
+	for _, addr := range addresses {
+		if _, ok := kept[addr.OwnerID]; ok {
+			capped = append(capped, addr)
+		}
+	}
+
+	return capped, len(userIDs) - len(kept), nil
+}
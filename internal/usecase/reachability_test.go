@@ -0,0 +1,286 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"radar/internal/domain/entity"
+	domainerrors "radar/internal/domain/errors"
+	"radar/internal/domain/policy"
+
+	"github.com/google/uuid"
+	"github.com/paulmach/orb/geojson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubRoutingService returns preset route results index-aligned with the targets
+type stubRoutingService struct {
+	results []RouteResult
+	err     error
+	profile string // Profile of the last OneToMany call
+}
+
+func (s *stubRoutingService) ManyToMany(ctx context.Context, profile string, sources, targets []Coordinate) ([]*OneToManyResult, error) {
+	return RouteEachSource(ctx, s, profile, sources, targets)
+}
+
+func (s *stubRoutingService) OneToMany(_ context.Context, profile string, source Coordinate, targets []Coordinate) (*OneToManyResult, error) {
+	s.profile = profile
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &OneToManyResult{Source: source, Targets: targets, Results: s.results}, nil
+}
+
+func (s *stubRoutingService) FindNearestNode(context.Context, string, Coordinate) (*NodeInfo, bool, error) {
+	return nil, false, s.err
+}
+
+func (s *stubRoutingService) SnapBatch(context.Context, string, []Coordinate) ([]NodeInfo, []bool, error) {
+	return nil, nil, s.err
+}
+
+func (s *stubRoutingService) CalculateDistance(context.Context, string, Coordinate, Coordinate) (*RouteResult, error) {
+	return nil, s.err
+}
+
+func (s *stubRoutingService) CalculateRoute(context.Context, string, Coordinate, Coordinate) (*RouteResult, error) {
+	return nil, s.err
+}
+
+func (s *stubRoutingService) NearestRoadName(context.Context, Coordinate) (string, bool, error) {
+	return "", false, s.err
+}
+
+func (s *stubRoutingService) IsReady() bool {
+	return true
+}
+
+func (s *stubRoutingService) Metadata() RoutingMetadata {
+	return RoutingMetadata{}
+}
+
+func TestFilterReachableAddresses(t *testing.T) {
+	t.Parallel()
+
+	ownerA, ownerB := uuid.New(), uuid.New()
+	addresses := []*entity.SubscriberAddress{
+		{Address: entity.Address{OwnerID: ownerA}, NotificationRadius: 1000},
+		{Address: entity.Address{OwnerID: ownerB}, NotificationRadius: 1000},
+		{Address: entity.Address{OwnerID: ownerB}, NotificationRadius: 500},
+		{Address: entity.Address{OwnerID: uuid.New()}, NotificationRadius: 5000},
+	}
+	routingSvc := &stubRoutingService{results: []RouteResult{
+		{DistanceKm: 0.8, IsReachable: true},
+		{DistanceKm: 1.2, IsReachable: true}, // Beyond the radius by road
+		{DistanceKm: 0.5, IsReachable: true}, // Exactly on the radius
+		{IsReachable: false},
+	}}
+
+	reachable, err := FilterReachableAddresses(context.Background(), routingSvc, RadiusPolicy{}, Coordinate{}, addresses)
+
+	require.NoError(t, err)
+	assert.Equal(t, []*entity.SubscriberAddress{addresses[0], addresses[2]}, reachable)
+	assert.Equal(t, []uuid.UUID{ownerA, ownerB}, SubscriberIDs(reachable))
+}
+
+func TestFilterReachableAddresses_RoutingError(t *testing.T) {
+	t.Parallel()
+
+	routingErr := errors.New("routing unavailable")
+	addresses := []*entity.SubscriberAddress{{NotificationRadius: 1000}}
+
+	_, err := FilterReachableAddresses(context.Background(), &stubRoutingService{err: routingErr}, RadiusPolicy{}, Coordinate{}, addresses)
+
+	require.ErrorIs(t, err, routingErr)
+}
+
+func TestFilterReachableAddresses_RoutesOnPolicyProfile(t *testing.T) {
+	t.Parallel()
+
+	addresses := []*entity.SubscriberAddress{{NotificationRadius: 1000}}
+	routingSvc := &stubRoutingService{results: []RouteResult{{DistanceKm: 0.5, IsReachable: true}}}
+
+	_, err := FilterReachableAddresses(context.Background(), routingSvc, RadiusPolicy{Profile: "walking"}, Coordinate{}, addresses)
+
+	require.NoError(t, err)
+	assert.Equal(t, "walking", routingSvc.profile)
+}
+
+func TestIsWithinNotificationRadius_StraightLinePolicy(t *testing.T) {
+	t.Parallel()
+
+	estimate := RouteResult{DistanceKm: 0.9, IsReachable: true, IsEstimate: true}
+	road := RouteResult{DistanceKm: 0.9, IsReachable: true}
+
+	tests := []struct {
+		name   string
+		result RouteResult
+		policy RadiusPolicy
+		want   bool
+	}{
+		{name: "estimate against unchanged radius", result: estimate, policy: RadiusPolicy{}, want: true},
+		{name: "estimate with factor of one", result: estimate, policy: RadiusPolicy{StraightLineFactor: 1}, want: true},
+		{name: "estimate outside narrowed radius", result: estimate, policy: RadiusPolicy{StraightLineFactor: 0.8}, want: false},
+		{name: "estimate inside widened radius", result: RouteResult{DistanceKm: 1.2, IsReachable: true, IsEstimate: true}, policy: RadiusPolicy{StraightLineFactor: 1.5}, want: true},
+		{name: "road route ignores factor", result: road, policy: RadiusPolicy{StraightLineFactor: 0.8}, want: true},
+		{name: "unreachable estimate", result: RouteResult{IsEstimate: true}, policy: RadiusPolicy{StraightLineFactor: 2}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, IsWithinNotificationRadius(tt.result, 1000, tt.policy))
+		})
+	}
+}
+
+func TestIsWithinSubscriberArea_PrefersGeofence(t *testing.T) {
+	t.Parallel()
+
+	// A neighborhood around Taipei 101 with a hole cut out over the Xinyi shopping district
+	neighborhood := geojson.Polygon{
+		{{121.55, 25.02}, {121.58, 25.02}, {121.58, 25.05}, {121.55, 25.05}, {121.55, 25.02}},
+		{{121.565, 25.035}, {121.57, 25.035}, {121.57, 25.04}, {121.565, 25.04}, {121.565, 25.035}},
+	}
+	fenced := &entity.SubscriberAddress{Address: entity.Address{GeofencePolygon: neighborhood}, NotificationRadius: 1000}
+	unfenced := &entity.SubscriberAddress{NotificationRadius: 1000}
+	nearby := RouteResult{DistanceKm: 0.5, IsReachable: true}
+	faraway := RouteResult{DistanceKm: 8, IsReachable: true}
+
+	tests := []struct {
+		name    string
+		source  Coordinate
+		address *entity.SubscriberAddress
+		result  RouteResult
+		want    bool
+	}{
+		{name: "inside polygon beyond the radius", source: Coordinate{Lat: 25.025, Lng: 121.555}, address: fenced, result: faraway, want: true},
+		{name: "inside polygon without a road route", source: Coordinate{Lat: 25.025, Lng: 121.555}, address: fenced, result: RouteResult{}, want: true},
+		{name: "outside polygon within the radius", source: Coordinate{Lat: 25.06, Lng: 121.56}, address: fenced, result: nearby, want: false},
+		{name: "inside the polygon hole", source: Coordinate{Lat: 25.0375, Lng: 121.5675}, address: fenced, result: nearby, want: false},
+		{name: "no polygon within the radius", source: Coordinate{Lat: 25.06, Lng: 121.56}, address: unfenced, result: nearby, want: true},
+		{name: "no polygon beyond the radius", source: Coordinate{Lat: 25.025, Lng: 121.555}, address: unfenced, result: faraway, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, IsWithinSubscriberArea(tt.source, tt.address, tt.result, RadiusPolicy{}))
+		})
+	}
+}
+
+func TestFilterReachableAddresses_RoutingDisabledNarrowsRadius(t *testing.T) {
+	t.Parallel()
+
+	addresses := []*entity.SubscriberAddress{
+		{Address: entity.Address{OwnerID: uuid.New()}, NotificationRadius: 1000},
+		{Address: entity.Address{OwnerID: uuid.New()}, NotificationRadius: 1000},
+	}
+	// The Haversine fallback answers every target with a straight-line estimate
+	routingSvc := &stubRoutingService{results: []RouteResult{
+		{DistanceKm: 0.6, IsReachable: true, IsEstimate: true},
+		{DistanceKm: 0.8, IsReachable: true, IsEstimate: true},
+	}}
+
+	reachable, err := FilterReachableAddresses(context.Background(), routingSvc, RadiusPolicy{StraightLineFactor: 0.7}, Coordinate{}, addresses)
+
+	require.NoError(t, err)
+	assert.Equal(t, []*entity.SubscriberAddress{addresses[0]}, reachable)
+}
+
+func TestFilterReachableAddresses_ExcludesSourceOutsideGeofence(t *testing.T) {
+	t.Parallel()
+
+	// Both subscribers are a short drive away, but only the first fenced in the source's block
+	source := Coordinate{Lat: 25.033, Lng: 121.565}
+	around := geojson.Polygon{{{121.56, 25.03}, {121.57, 25.03}, {121.57, 25.04}, {121.56, 25.04}, {121.56, 25.03}}}
+	elsewhere := geojson.Polygon{{{121.50, 25.06}, {121.52, 25.06}, {121.52, 25.08}, {121.50, 25.08}, {121.50, 25.06}}}
+	addresses := []*entity.SubscriberAddress{
+		{Address: entity.Address{OwnerID: uuid.New(), GeofencePolygon: around}, NotificationRadius: 1000},
+		{Address: entity.Address{OwnerID: uuid.New(), GeofencePolygon: elsewhere}, NotificationRadius: 1000},
+	}
+	routingSvc := &stubRoutingService{results: []RouteResult{
+		{DistanceKm: 0.4, IsReachable: true},
+		{DistanceKm: 0.4, IsReachable: true},
+	}}
+
+	reachable, err := FilterReachableAddresses(context.Background(), routingSvc, RadiusPolicy{}, source, addresses)
+
+	require.NoError(t, err)
+	assert.Equal(t, []*entity.SubscriberAddress{addresses[0]}, reachable)
+}
+
+func TestSubscriberIDs_DeduplicatesInOrder(t *testing.T) {
+	t.Parallel()
+
+	ownerA, ownerB := uuid.New(), uuid.New()
+	addresses := []*entity.SubscriberAddress{
+		{Address: entity.Address{OwnerID: ownerB}},
+		{Address: entity.Address{OwnerID: ownerA}},
+		{Address: entity.Address{OwnerID: ownerB}},
+	}
+
+	assert.Equal(t, []uuid.UUID{ownerB, ownerA}, SubscriberIDs(addresses))
+	assert.Empty(t, SubscriberIDs(nil))
+}
+
+// cappedAddresses places subscribers north of the origin: near has two addresses, 1km and 5km away;
+// middle is 2km away and far 3km away
+func cappedAddresses() (near, middle, far uuid.UUID, addresses []*entity.SubscriberAddress) {
+	near, middle, far = uuid.New(), uuid.New(), uuid.New()
+	addresses = []*entity.SubscriberAddress{
+		{Address: entity.Address{OwnerID: far, Latitude: 0.027}},
+		{Address: entity.Address{OwnerID: near, Latitude: 0.045}},
+		{Address: entity.Address{OwnerID: middle, Latitude: 0.018}},
+		{Address: entity.Address{OwnerID: near, Latitude: 0.009}},
+	}
+
+	return near, middle, far, addresses
+}
+
+func TestCapRecipients_UnderCap(t *testing.T) {
+	t.Parallel()
+
+	_, _, _, addresses := cappedAddresses()
+
+	for _, recipientCap := range []policy.RecipientCapPolicy{{}, {MaxRecipients: 3, Strict: true}, {MaxRecipients: 10}} {
+		capped, dropped, err := CapRecipients(Coordinate{}, addresses, recipientCap)
+
+		require.NoError(t, err)
+		assert.Equal(t, addresses, capped, "%+v", recipientCap)
+		assert.Zero(t, dropped)
+	}
+}
+
+func TestCapRecipients_StrictRejectsOverCap(t *testing.T) {
+	t.Parallel()
+
+	_, _, _, addresses := cappedAddresses()
+
+	capped, dropped, err := CapRecipients(Coordinate{}, addresses, policy.RecipientCapPolicy{MaxRecipients: 2, Strict: true})
+
+	require.ErrorIs(t, err, domainerrors.ErrRecipientCapExceeded)
+	assert.Nil(t, capped)
+	assert.Zero(t, dropped)
+}
+
+func TestCapRecipients_KeepsNearestSubscribers(t *testing.T) {
+	t.Parallel()
+
+	near, middle, _, addresses := cappedAddresses()
+
+	capped, dropped, err := CapRecipients(Coordinate{}, addresses, policy.RecipientCapPolicy{MaxRecipients: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, dropped)
+	// A subscriber is ranked by their closest address and keeps all of their addresses
+	assert.Equal(t, []*entity.SubscriberAddress{addresses[1], addresses[2], addresses[3]}, capped)
+	assert.Equal(t, []uuid.UUID{near, middle}, SubscriberIDs(capped))
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+
+	"radar/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// RouteCacheWarmResult summarizes one pass of the route cache warmer
+type RouteCacheWarmResult struct {
+	Merchants int `json:"merchants"` // Merchants whose default location was warmed
+	Routed    int `json:"routed"`    // Subscriber addresses routed because they were new or had moved
+	Cached    int `json:"cached"`    // Subscriber addresses answered from the existing cache
+	Failed    int `json:"failed"`    // Merchants skipped because routing or lookup failed
+}
+
+// RouteCacheUsecase keeps road routes from each merchant's default location to its subscribers precomputed
+type RouteCacheUsecase interface {
+	// WarmAll refreshes the cached routes of every merchant with a primary location.
+	// Only subscriber addresses that are new or have moved since the last pass are routed.
+	WarmAll(ctx context.Context) (*RouteCacheWarmResult, error)
+
+	// FilterReachableAddresses behaves like the package-level FilterReachableAddresses for a merchant broadcast,
+	// answering warmed addresses from the cache and routing only the rest.
+	FilterReachableAddresses(
+		ctx context.Context,
+		merchantID uuid.UUID,
+		source Coordinate,
+		addresses []*entity.SubscriberAddress,
+	) ([]*entity.SubscriberAddress, error)
+}
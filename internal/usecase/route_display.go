@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// DistanceUnit represents the unit used when presenting distances to clients.
+// Routing computation always stays metric; the unit only affects display values.
+type DistanceUnit string
+
+const (
+	DistanceUnitKilometers DistanceUnit = "km"
+	DistanceUnitMiles      DistanceUnit = "mi"
+)
+
+const kilometersPerMile = 1.609344
+
+// RouteDisplay bundles raw metric route values with unit-aware formatted strings.
+type RouteDisplay struct {
+	DistanceKm   float64      `json:"distance_km"`   // Raw road network distance in kilometers
+	DurationMin  float64      `json:"duration_min"`  // Raw estimated travel time in minutes
+	Unit         DistanceUnit `json:"unit"`          // Unit used for DistanceText
+	DistanceText string       `json:"distance_text"` // Distance formatted in Unit, e.g. "1.2 mi"
+	DurationText string       `json:"duration_text"` // ETA formatted for display, e.g. "1 h 5 min"
+}
+
+// KilometersToMiles converts kilometers to statute miles.
+func KilometersToMiles(km float64) float64 {
+	return km / kilometersPerMile
+}
+
+// MilesToKilometers converts statute miles to kilometers.
+func MilesToKilometers(miles float64) float64 {
+	return miles * kilometersPerMile
+}
+
+// ParseDistanceUnit parses a client-supplied unit value such as "km" or "mi".
+// It returns false when the value is empty or unsupported.
+func ParseDistanceUnit(value string) (DistanceUnit, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "km", "kilometer", "kilometers", "metric":
+		return DistanceUnitKilometers, true
+	case "mi", "mile", "miles", "imperial":
+		return DistanceUnitMiles, true
+	default:
+		return "", false
+	}
+}
+
+// DistanceUnitFromAcceptLanguage picks a display unit from the highest-priority
+// language tag in an Accept-Language header, defaulting to kilometers.
+func DistanceUnitFromAcceptLanguage(header string) DistanceUnit {
+	primary, _, _ := strings.Cut(header, ",")
+	tag, _, _ := strings.Cut(strings.TrimSpace(primary), ";")
+
+	parts := strings.FieldsFunc(tag, func(r rune) bool { return r == '-' || r == '_' })
+	for _, part := range parts[min(1, len(parts)):] {
+		if isImperialRegion(part) {
+			return DistanceUnitMiles
+		}
+	}
+
+	return DistanceUnitKilometers
+}
+
+// isImperialRegion reports whether a language region uses miles on road signage.
+func isImperialRegion(region string) bool {
+	switch strings.ToUpper(region) {
+	case "US", "GB", "LR", "MM":
+		return true
+	default:
+		return false
+	}
+}
+
+// FormatDistance formats a metric distance in the requested unit.
+// Values under ten are shown with one decimal place.
+func FormatDistance(distanceKm float64, unit DistanceUnit) string {
+	value := distanceKm
+	if unit == DistanceUnitMiles {
+		value = KilometersToMiles(distanceKm)
+	} else {
+		unit = DistanceUnitKilometers
+	}
+
+	if value < 10 {
+		return fmt.Sprintf("%.1f %s", value, unit)
+	}
+
+	return fmt.Sprintf("%.0f %s", value, unit)
+}
+
+// FormatETA formats a duration in minutes, e.g. "< 1 min", "12 min", "1 h 5 min".
+func FormatETA(durationMin float64) string {
+	minutes := int(math.Round(durationMin))
+	if minutes < 1 {
+		return "< 1 min"
+	}
+
+	if minutes < 60 {
+		return fmt.Sprintf("%d min", minutes)
+	}
+
+	hours := minutes / 60
+	remaining := minutes % 60
+	if remaining == 0 {
+		return fmt.Sprintf("%d h", hours)
+	}
+
+	return fmt.Sprintf("%d h %d min", hours, remaining)
+}
+
+// NewRouteDisplay builds a display-ready view of a route result in the requested unit.
+func NewRouteDisplay(result *RouteResult, unit DistanceUnit) RouteDisplay {
+	if unit != DistanceUnitMiles {
+		unit = DistanceUnitKilometers
+	}
+
+	return RouteDisplay{
+		DistanceKm:   result.DistanceKm,
+		DurationMin:  result.DurationMin,
+		Unit:         unit,
+		DistanceText: FormatDistance(result.DistanceKm, unit),
+		DurationText: FormatETA(result.DurationMin),
+	}
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKilometersMilesConversion(t *testing.T) {
+	t.Parallel()
+
+	assert.InDelta(t, 1.0, KilometersToMiles(1.609344), 1e-9)
+	assert.InDelta(t, 3.10686, KilometersToMiles(5), 1e-5)
+	assert.InDelta(t, 16.09344, MilesToKilometers(10), 1e-9)
+	assert.InDelta(t, 42.195, MilesToKilometers(KilometersToMiles(42.195)), 1e-9)
+}
+
+func TestParseDistanceUnit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value    string
+		expected DistanceUnit
+		ok       bool
+	}{
+		{value: "km", expected: DistanceUnitKilometers, ok: true},
+		{value: " MI ", expected: DistanceUnitMiles, ok: true},
+		{value: "imperial", expected: DistanceUnitMiles, ok: true},
+		{value: "", ok: false},
+		{value: "furlong", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			unit, ok := ParseDistanceUnit(tt.value)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, unit)
+		})
+	}
+}
+
+func TestDistanceUnitFromAcceptLanguage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		header   string
+		expected DistanceUnit
+	}{
+		{header: "", expected: DistanceUnitKilometers},
+		{header: "zh-TW,zh;q=0.9,en-US;q=0.8", expected: DistanceUnitKilometers},
+		{header: "en-US,en;q=0.9", expected: DistanceUnitMiles},
+		{header: "en_GB", expected: DistanceUnitMiles},
+		{header: "en", expected: DistanceUnitKilometers},
+		{header: "us", expected: DistanceUnitKilometers},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, DistanceUnitFromAcceptLanguage(tt.header))
+		})
+	}
+}
+
+func TestFormatDistance(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "1.5 km", FormatDistance(1.5, DistanceUnitKilometers))
+	assert.Equal(t, "12 km", FormatDistance(12.4, DistanceUnitKilometers))
+	assert.Equal(t, "1.0 mi", FormatDistance(1.609344, DistanceUnitMiles))
+	assert.Equal(t, "19 mi", FormatDistance(30, DistanceUnitMiles))
+	assert.Equal(t, "2.0 km", FormatDistance(2, ""))
+}
+
+func TestFormatETA(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		minutes  float64
+		expected string
+	}{
+		{minutes: 0, expected: "< 1 min"},
+		{minutes: 0.4, expected: "< 1 min"},
+		{minutes: 0.6, expected: "1 min"},
+		{minutes: 12.2, expected: "12 min"},
+		{minutes: 60, expected: "1 h"},
+		{minutes: 65, expected: "1 h 5 min"},
+		{minutes: 134.6, expected: "2 h 15 min"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, FormatETA(tt.minutes))
+		})
+	}
+}
+
+func TestNewRouteDisplay(t *testing.T) {
+	t.Parallel()
+
+	result := &RouteResult{DistanceKm: 8.04672, DurationMin: 16, IsReachable: true}
+
+	display := NewRouteDisplay(result, DistanceUnitMiles)
+
+	assert.Equal(t, 8.04672, display.DistanceKm)
+	assert.Equal(t, 16.0, display.DurationMin)
+	assert.Equal(t, DistanceUnitMiles, display.Unit)
+	assert.Equal(t, "5.0 mi", display.DistanceText)
+	assert.Equal(t, "16 min", display.DurationText)
+
+	metric := NewRouteDisplay(result, "")
+	assert.Equal(t, DistanceUnitKilometers, metric.Unit)
+	assert.Equal(t, "8.0 km", metric.DistanceText)
+}
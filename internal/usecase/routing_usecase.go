@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
@@ -18,8 +19,27 @@ type RouteResult struct {
 	DistanceKm  float64    `json:"distance_km"`  // Road network distance in kilometers
 	DurationMin float64    `json:"duration_min"` // Estimated travel time in minutes
 	IsReachable bool       `json:"is_reachable"` // Whether target is reachable via road network
+	IsEstimate  bool       `json:"is_estimate"`  // Whether the distance is a straight-line estimate rather than a road route
+
+	// Why the target is unreachable; empty when reachable or when the backend does not report a reason
+	UnreachableReason UnreachableReason `json:"unreachable_reason,omitempty"`
+
+	// Ordered coordinates along the route from source to target; only populated by CalculateRoute
+	Geometry []Coordinate `json:"geometry,omitempty"`
 }
 
+// UnreachableReason explains why a routing backend found no route to a target
+type UnreachableReason string
+
+const (
+	// UnreachableReasonOffNetwork means the source or target is too far from any road to be snapped
+	UnreachableReasonOffNetwork UnreachableReason = "off_network"
+	// UnreachableReasonDisconnected means both ends snapped but no road path connects them
+	UnreachableReasonDisconnected UnreachableReason = "disconnected"
+	// UnreachableReasonOutOfRange means the target lies beyond the backend's query radius and was not routed
+	UnreachableReasonOutOfRange UnreachableReason = "out_of_range"
+)
+
 // OneToManyResult represents the result of a one-to-many routing query
 type OneToManyResult struct {
 	Source   Coordinate    `json:"source"`
@@ -35,22 +55,125 @@ type NodeID int
 type NodeInfo struct {
 	ID       NodeID     `json:"id"`
 	Location Coordinate `json:"location"`
+	Edge     *EdgeSnap  `json:"edge,omitempty"` // Nearest edge projection; only set when edge snapping is enabled
+}
+
+// EdgeSnap represents the projection of a coordinate onto its nearest road network edge
+type EdgeSnap struct {
+	FromID    NodeID     `json:"from_id"`
+	ToID      NodeID     `json:"to_id"`
+	Projected Coordinate `json:"projected"`  // Closest point on the edge
+	Offset    float64    `json:"offset"`     // Fraction of the edge from FromID to Projected, in [0, 1]
+	DistanceM float64    `json:"distance_m"` // Distance in meters from the coordinate to Projected
+}
+
+// RoutingMetadata describes the road data a routing backend has loaded.
+// Fields the backend cannot report are left empty.
+type RoutingMetadata struct {
+	Backend string `json:"backend"` // Routing backend: ch, pmtiles, or haversine
+	Ready   bool   `json:"ready"`
+
+	// Dataset provenance recorded by the CH preprocessing run
+	DataVersion    string    `json:"data_version,omitempty"`
+	Region         string    `json:"region,omitempty"`
+	OSMTimestamp   time.Time `json:"osm_timestamp,omitzero"`
+	GeneratedAt    time.Time `json:"generated_at,omitzero"`
+	Profile        string    `json:"profile,omitempty"`
+	VerticesCount  int64     `json:"vertices_count,omitempty"`
+	EdgesCount     int64     `json:"edges_count,omitempty"`
+	ShortcutsCount int64     `json:"shortcuts_count,omitempty"`
+	Query          string    `json:"query,omitempty"` // CH search algorithm: ch, dijkstra, or dijkstra_restrictions
+
+	// PMTiles archive the road graph is built from; Source omits any query string
+	Source    string `json:"source,omitempty"`
+	Tileset   string `json:"tileset,omitempty"`
+	RoadLayer string `json:"road_layer,omitempty"`
+	ZoomLevel int    `json:"zoom_level,omitempty"`
+}
+
+// DefaultRoutingProfile selects the backend's default road network profile
+const DefaultRoutingProfile = ""
+
+// ErrUnknownRoutingProfile is wrapped by backends when a call names a profile they do not serve
+var ErrUnknownRoutingProfile = errors.New("unknown routing profile")
+
+// RoutingOptions overrides backend settings for the RoutingUsecase calls made with a context.
+// Zero fields keep the backend's configured values, and backends ignore settings they do not have.
+type RoutingOptions struct {
+	// Maximum distance in meters a coordinate may be from the road network to be snapped onto it
+	MaxSnapDistanceMeters float64
 }
 
-// RoutingUsecase defines the interface for routing engine use cases
+type routingOptionsKey struct{}
+
+// WithRoutingOptions returns a context whose RoutingUsecase calls use opts. The options travel on the
+// context so wrappers such as fallback chains and tracing pass them to the backend unchanged.
+func WithRoutingOptions(ctx context.Context, opts RoutingOptions) context.Context {
+	return context.WithValue(ctx, routingOptionsKey{}, opts)
+}
+
+// RoutingOptionsFromContext returns the routing options set on ctx, or zero options when none are set
+func RoutingOptionsFromContext(ctx context.Context) RoutingOptions {
+	opts, _ := ctx.Value(routingOptionsKey{}).(RoutingOptions)
+
+	return opts
+}
+
+// RoutingUsecase defines the interface for routing engine use cases.
+// The profile argument picks the road network to route on, such as driving, walking, or cycling;
+// DefaultRoutingProfile uses the backend's default, and backends that load a single network ignore it.
 type RoutingUsecase interface {
 	// OneToMany calculates routes from one source coordinate to multiple target coordinates
 	// Returns results for all targets, with unreachable targets marked accordingly
-	OneToMany(ctx context.Context, source Coordinate, targets []Coordinate) (*OneToManyResult, error)
+	OneToMany(ctx context.Context, profile string, source Coordinate, targets []Coordinate) (*OneToManyResult, error)
+
+	// ManyToMany calculates routes from every source to every target; results are index-aligned with sources
+	// Backends that build a road graph per query build a single graph shared by all sources
+	ManyToMany(ctx context.Context, profile string, sources, targets []Coordinate) ([]*OneToManyResult, error)
 
 	// FindNearestNode finds the nearest road network node to a given GPS coordinate
 	// Returns the node information and whether it was within the maximum snap distance
-	FindNearestNode(ctx context.Context, coord Coordinate) (*NodeInfo, bool, error)
+	FindNearestNode(ctx context.Context, profile string, coord Coordinate) (*NodeInfo, bool, error)
+
+	// SnapBatch finds the nearest road network node for each coordinate in a single pass
+	// Results are index-aligned with coords; found[i] is false when coords[i] is beyond the maximum snap distance
+	SnapBatch(ctx context.Context, profile string, coords []Coordinate) ([]NodeInfo, []bool, error)
 
 	// CalculateDistance calculates the road network distance between two coordinates
 	// Returns RouteResult with distance, duration, and reachability information
-	CalculateDistance(ctx context.Context, source, target Coordinate) (*RouteResult, error)
+	CalculateDistance(ctx context.Context, profile string, source, target Coordinate) (*RouteResult, error)
+
+	// CalculateRoute calculates the road network route between two coordinates, including its geometry
+	// Returns the same result as CalculateDistance plus Geometry, which is empty when the backend cannot reconstruct the path
+	CalculateRoute(ctx context.Context, profile string, source, target Coordinate) (*RouteResult, error)
+
+	// NearestRoadName returns the name of the closest named road within the maximum snap distance of coord
+	// found is false when no named road is that close or the backend has no road names
+	NearestRoadName(ctx context.Context, coord Coordinate) (name string, found bool, err error)
 
 	// IsReady returns whether the routing engine is loaded and ready for queries
 	IsReady() bool
+
+	// Metadata describes the loaded road data on a best-effort basis
+	Metadata() RoutingMetadata
+}
+
+// oneToManyRouter is the part of RoutingUsecase that RouteEachSource needs
+type oneToManyRouter interface {
+	OneToMany(ctx context.Context, profile string, source Coordinate, targets []Coordinate) (*OneToManyResult, error)
+}
+
+// RouteEachSource answers a ManyToMany query with one OneToMany query per source,
+// for backends that have no per-query graph to share between sources
+func RouteEachSource(ctx context.Context, router oneToManyRouter, profile string, sources, targets []Coordinate) ([]*OneToManyResult, error) {
+	results := make([]*OneToManyResult, len(sources))
+	for idx, source := range sources {
+		result, err := router.OneToMany(ctx, profile, source, targets)
+		if err != nil {
+			return nil, err
+		}
+		results[idx] = result
+	}
+
+	return results, nil
 }
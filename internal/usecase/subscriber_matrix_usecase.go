@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+
+	"radar/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// SubscriberMatrixSource is one merchant location whose subscribers are routed
+type SubscriberMatrixSource struct {
+	MerchantID uuid.UUID  `json:"merchant_id"`
+	Location   Coordinate `json:"location"`
+}
+
+// SubscriberRoute is the road route from a source location to one of its subscriber addresses
+type SubscriberRoute struct {
+	Address *entity.SubscriberAddress `json:"address"`
+	Route   RouteResult               `json:"route"`
+}
+
+// LocationReach counts the subscribers a broadcast from one of the merchant's locations would reach
+type LocationReach struct {
+	AddressID  uuid.UUID `json:"address_id"`
+	Label      string    `json:"label"`
+	Candidates int       `json:"candidates"` // Subscribers whose radius or geofence covers the location in a straight line
+	Reachable  int       `json:"reachable"`  // Candidates the notification fan-out would reach by road
+}
+
+// SubscriberMatrixUsecase routes many merchant locations to their subscribers for matrix and analytics exports
+type SubscriberMatrixUsecase interface {
+	// RouteSubscribers returns the routes from each source to the subscriber addresses within its notification radius,
+	// index-aligned with sources. Subscribers are looked up in batches and grouped by map tile, and every source with
+	// subscribers in a tile is routed in one ManyToMany query so the routing backend builds that tile's graph once.
+	RouteSubscribers(ctx context.Context, sources []SubscriberMatrixSource) ([][]SubscriberRoute, error)
+
+	// GetMerchantLocationReach routes every active location of the merchant to its subscribers at once and counts
+	// the subscribers each would reach, in the order of the merchant's addresses. Only counts are returned, so no
+	// subscriber address leaves the service.
+	GetMerchantLocationReach(ctx context.Context, merchantID uuid.UUID) ([]*LocationReach, error)
+}
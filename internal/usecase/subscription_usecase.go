@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"radar/internal/domain/entity"
 
@@ -16,6 +17,15 @@ type SubscriptionUsecase interface {
 	// UnsubscribeFromMerchant deactivates a subscription (soft delete)
 	UnsubscribeFromMerchant(ctx context.Context, userID, merchantID uuid.UUID) error
 
+	// SnoozeSubscription suppresses broadcasts from one merchant until the given time; a zero time clears the snooze
+	SnoozeSubscription(ctx context.Context, userID, merchantID uuid.UUID, until time.Time) (*entity.UserMerchantSubscription, error)
+
+	// SnoozeAllSubscriptions suppresses broadcasts from every subscribed merchant until the given time; a zero time clears the snooze
+	SnoozeAllSubscriptions(ctx context.Context, userID uuid.UUID, until time.Time) error
+
+	// UpdateAllSubscriptionRadii sets the notification radius on every active subscription of a user and returns the count updated
+	UpdateAllSubscriptionRadii(ctx context.Context, userID uuid.UUID, radiusKm float64) (int64, error)
+
 	// GetUserSubscriptions retrieves all subscriptions for a user
 	GetUserSubscriptions(ctx context.Context, userID uuid.UUID) ([]*entity.UserMerchantSubscription, error)
 